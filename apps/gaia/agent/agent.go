@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// frame is an opaque, already-serialized gRPC message passed through the agent.
+type frame struct {
+	payload []byte
+}
+
+// rawCodec passes message bytes through untouched, so the agent can proxy any
+// RPC without knowing its protobuf types. It reports itself as "proto" so the
+// content-type seen by both ends is unchanged.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	f, ok := v.(*frame)
+	if !ok {
+		return nil, fmt.Errorf("agent: unexpected message type %T", v)
+	}
+	return f.payload, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	f, ok := v.(*frame)
+	if !ok {
+		return fmt.Errorf("agent: unexpected message type %T", v)
+	}
+	f.payload = append(f.payload[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string { return "proto" }
+
+// Agent holds a single authenticated connection to the daemon and proxies
+// RPCs received on a local socket over it, so that many short-lived CLI
+// invocations share one TLS handshake.
+type Agent struct {
+	upstream *grpc.ClientConn
+	server   *grpc.Server
+}
+
+// New creates an agent that forwards every call to the given upstream connection.
+func New(upstream *grpc.ClientConn) *Agent {
+	a := &Agent{upstream: upstream}
+	a.server = grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(a.proxy),
+	)
+	return a
+}
+
+// Listen opens the unix socket at path, replacing a stale socket left over at
+// it. The socket is created accessible only to the current user. Anything at
+// path that is not a socket is left alone and an error is returned.
+func Listen(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode().Type() != os.ModeSocket {
+			return nil, fmt.Errorf("refusing to replace '%s': not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale agent socket: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to inspect agent socket path: %w", err)
+	}
+	lis, err := listenUnix(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on agent socket: %w", err)
+	}
+	return lis, nil
+}
+
+// Serve accepts proxied calls on lis until Stop is called.
+func (a *Agent) Serve(lis net.Listener) error {
+	return a.server.Serve(lis)
+}
+
+// Stop gracefully stops the local server. The upstream connection is owned by
+// the caller and is left open.
+func (a *Agent) Stop() {
+	a.server.GracefulStop()
+}
+
+// proxy forwards a single call, unary or streaming, to the upstream connection.
+func (a *Agent) proxy(_ any, serverStream grpc.ServerStream) error {
+	method, ok := grpc.MethodFromServerStream(serverStream)
+	if !ok {
+		return status.Error(codes.Internal, "agent: could not determine method")
+	}
+
+	ctx := serverStream.Context()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = metadata.NewOutgoingContext(ctx, md.Copy())
+	}
+
+	desc := &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+	clientStream, err := a.upstream.NewStream(ctx, desc, method, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return err
+	}
+
+	// Pump requests from the local caller to the daemon. A failure on this side
+	// cancels the call context, which surfaces below through RecvMsg.
+	go func() {
+		for {
+			f := &frame{}
+			if err := serverStream.RecvMsg(f); err != nil {
+				if errors.Is(err, io.EOF) {
+					_ = clientStream.CloseSend()
+				}
+				return
+			}
+			if err := clientStream.SendMsg(f); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Pump responses from the daemon back to the local caller.
+	headerSent := false
+	for {
+		f := &frame{}
+		if err := clientStream.RecvMsg(f); err != nil {
+			serverStream.SetTrailer(clientStream.Trailer())
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if !headerSent {
+			if md, err := clientStream.Header(); err == nil {
+				if err := serverStream.SendHeader(md); err != nil {
+					return err
+				}
+			}
+			headerSent = true
+		}
+		if err := serverStream.SendMsg(f); err != nil {
+			return err
+		}
+	}
+}
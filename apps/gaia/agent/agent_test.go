@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// countingListener records how many connections the upstream server accepted.
+type countingListener struct {
+	net.Listener
+	accepted atomic.Int32
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.accepted.Add(1)
+	}
+	return conn, err
+}
+
+type statusServer struct {
+	pb.UnimplementedGaiaAdminServer
+	calls atomic.Int32
+}
+
+func (s *statusServer) GetStatus(_ context.Context, _ *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	s.calls.Add(1)
+	return &pb.GetStatusResponse{Status: "running"}, nil
+}
+
+func TestAgentProxiesOverSingleUpstreamConnection(t *testing.T) {
+	tcpLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	upstreamLis := &countingListener{Listener: tcpLis}
+	srv := &statusServer{}
+	upstreamServer := grpc.NewServer()
+	pb.RegisterGaiaAdminServer(upstreamServer, srv)
+	go upstreamServer.Serve(upstreamLis)
+	defer upstreamServer.Stop()
+
+	upstream, err := grpc.NewClient(tcpLis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create upstream client: %v", err)
+	}
+	defer upstream.Close()
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	lis, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	a := New(upstream)
+	go a.Serve(lis)
+	defer a.Stop()
+
+	// Each call uses its own local connection, like separate CLI invocations.
+	const calls = 3
+	for i := 0; i < calls; i++ {
+		conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("failed to connect to agent: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		res, err := pb.NewGaiaAdminClient(conn).GetStatus(ctx, &pb.GetStatusRequest{})
+		cancel()
+		conn.Close()
+		if err != nil {
+			t.Fatalf("GetStatus() via agent error = %v", err)
+		}
+		if res.Status != "running" {
+			t.Errorf("GetStatus() = %q, want %q", res.Status, "running")
+		}
+	}
+
+	if got := srv.calls.Load(); got != calls {
+		t.Errorf("upstream served %d status calls, want %d", got, calls)
+	}
+	if got := upstreamLis.accepted.Load(); got != 1 {
+		t.Errorf("upstream accepted %d connections, want 1", got)
+	}
+}
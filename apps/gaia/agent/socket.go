@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// socketName is the file name of the agent socket in its default directory.
+const socketName = "gaia-agent.sock"
+
+// DefaultSocketPath returns the socket path used when none is configured: in
+// $XDG_RUNTIME_DIR when it is set, otherwise in a per-user directory that only
+// the current user can access, which is created if needed.
+func DefaultSocketPath() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, socketName), nil
+	}
+	dir, err := userSocketDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create agent socket directory: %w", err)
+	}
+	// The directory may have been created by someone else before us.
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect agent socket directory: %w", err)
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("agent socket directory '%s' is not a directory", dir)
+	}
+	if err := checkOwner(dir, fi); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, socketName), nil
+}
+
+// CheckSocket returns an error unless path is a socket owned by the current
+// user with no group or other permissions, so calls and their credentials are
+// never sent to a socket planted by another user.
+func CheckSocket(path string) error {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if fi.Mode().Type() != os.ModeSocket {
+		return fmt.Errorf("'%s' is not a socket", path)
+	}
+	return checkOwner(path, fi)
+}
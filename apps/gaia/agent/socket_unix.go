@@ -0,0 +1,43 @@
+//go:build unix
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// listenUnix binds the socket at path under a umask that leaves it accessible
+// only to the current user, so there is no window in which others can connect.
+func listenUnix(path string) (net.Listener, error) {
+	old := unix.Umask(0o177)
+	defer unix.Umask(old)
+	return net.Listen("unix", path)
+}
+
+// checkOwner returns an error unless fi, the file info of path, belongs to the
+// current user and grants no access to group or others.
+func checkOwner(path string, fi os.FileInfo) error {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("cannot determine the owner of '%s'", path)
+	}
+	if int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("'%s' is owned by uid %d, not by the current user", path, st.Uid)
+	}
+	if perm := fi.Mode().Perm(); perm&0o077 != 0 {
+		return fmt.Errorf("'%s' is accessible to other users (mode %s)", path, perm)
+	}
+	return nil
+}
+
+// userSocketDir returns the per-user directory of the socket when
+// XDG_RUNTIME_DIR is not set.
+func userSocketDir() (string, error) {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gaia-%d", os.Getuid())), nil
+}
@@ -0,0 +1,106 @@
+//go:build unix
+
+package agent
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListen_RestrictsSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	lis, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer lis.Close()
+
+	fi, err := os.Lstat(path)
+	if err != nil {
+		t.Fatalf("Lstat() error = %v", err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o600 {
+		t.Errorf("socket mode = %s, want -rw-------", perm)
+	}
+	if err := CheckSocket(path); err != nil {
+		t.Errorf("CheckSocket() of the agent socket error = %v", err)
+	}
+}
+
+func TestListen_RefusesNonSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if lis, err := Listen(path); err == nil {
+		lis.Close()
+		t.Fatal("Listen() replaced a regular file")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("regular file was removed: %v", err)
+	}
+}
+
+func TestCheckSocket_Rejects(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	open := filepath.Join(dir, "open.sock")
+	lis, err := net.Listen("unix", open)
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer lis.Close()
+	if err := os.Chmod(open, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.sock")
+	if err := os.Symlink(open, link); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, path := range map[string]string{
+		"missing":          filepath.Join(dir, "missing.sock"),
+		"regular file":     file,
+		"world accessible": open,
+		"symlink":          link,
+	} {
+		if err := CheckSocket(path); err == nil {
+			t.Errorf("CheckSocket() of a %s succeeded", name)
+		}
+	}
+}
+
+func TestDefaultSocketPath(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	if got, err := DefaultSocketPath(); err != nil || got != filepath.Join(runtimeDir, socketName) {
+		t.Errorf("DefaultSocketPath() = %q, %v; want the socket in XDG_RUNTIME_DIR", got, err)
+	}
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("TMPDIR", t.TempDir())
+	got, err := DefaultSocketPath()
+	if err != nil {
+		t.Fatalf("DefaultSocketPath() without XDG_RUNTIME_DIR error = %v", err)
+	}
+	fi, err := os.Stat(filepath.Dir(got))
+	if err != nil {
+		t.Fatalf("Stat() of the socket directory error = %v", err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o700 {
+		t.Errorf("socket directory mode = %s, want drwx------", perm)
+	}
+
+	// A directory others can access is refused.
+	if err := os.Chmod(filepath.Dir(got), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DefaultSocketPath(); err == nil {
+		t.Error("DefaultSocketPath() accepted a directory accessible to other users")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -12,88 +13,158 @@ import (
 
 // GenerateCA creates a new self-signed Certificate Authority and saves the certificate and private key.
 func GenerateCA(cfg *config.Config, commonName string) error {
+	certPath, keyPath, err := writeCA(cfg, commonName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Generated Root CA: %s and %s\n", certPath, keyPath)
+	return nil
+}
+
+// writeCA does the work of GenerateCA and returns the written paths.
+func writeCA(cfg *config.Config, commonName string) (certPath, keyPath string, err error) {
 	if err := os.MkdirAll(cfg.CertsDirectory, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+		return "", "", fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	caKey, caCert, err := generateCA(commonName, cfg.CertExpiryDays)
 	if err != nil {
-		return fmt.Errorf("failed to generate CA: %w", err)
+		return "", "", fmt.Errorf("failed to generate CA: %w", err)
 	}
 
-	certPath := filepath.Join(cfg.CertsDirectory, cfg.CACertFile)
+	certPath = filepath.Join(cfg.CertsDirectory, cfg.CACertFile)
 	if err := saveCert(certPath, caCert); err != nil {
-		return fmt.Errorf("failed to save CA certificate: %w", err)
+		return "", "", fmt.Errorf("failed to save CA certificate: %w", err)
 	}
 
-	keyPath := filepath.Join(cfg.CertsDirectory, "ca.key") // Assuming ca.key is the standard name
+	keyPath = filepath.Join(cfg.CertsDirectory, "ca.key") // Assuming ca.key is the standard name
 	if err := saveKey(keyPath, caKey); err != nil {
-		return fmt.Errorf("failed to save CA key: %w", err)
+		return "", "", fmt.Errorf("failed to save CA key: %w", err)
 	}
-
-	fmt.Printf("Generated Root CA: %s and %s\n", certPath, keyPath)
-	return nil
+	return certPath, keyPath, nil
 }
 
 // GenerateServerCertificate creates a server certificate signed by the CA.
 func GenerateServerCertificate(cfg *config.Config, serverName string) error {
+	certPath, keyPath, err := writeServerCertificate(cfg, serverName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Generated server certificate: %s and %s\n", certPath, keyPath)
+	return nil
+}
+
+// writeServerCertificate does the work of GenerateServerCertificate and returns
+// the written paths.
+func writeServerCertificate(cfg *config.Config, serverName string) (certPath, keyPath string, err error) {
 	caCertPath := filepath.Join(cfg.CertsDirectory, cfg.CACertFile)
 	caKeyPath := filepath.Join(cfg.CertsDirectory, "ca.key")
 
 	caCert, caKey, err := loadCA(caCertPath, caKeyPath)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
 	serverKey, serverCert, err := generateCert(serverName, caKey, caCert, true, cfg.CertExpiryDays)
 	if err != nil {
-		return fmt.Errorf("failed to generate server certificate: %w", err)
+		return "", "", fmt.Errorf("failed to generate server certificate: %w", err)
 	}
 
-	certPath := filepath.Join(cfg.CertsDirectory, cfg.ServerCertFile)
+	certPath = filepath.Join(cfg.CertsDirectory, cfg.ServerCertFile)
 	if err := saveCert(certPath, serverCert); err != nil {
-		return fmt.Errorf("failed to save server certificate: %w", err)
+		return "", "", fmt.Errorf("failed to save server certificate: %w", err)
 	}
 
-	keyPath := filepath.Join(cfg.CertsDirectory, cfg.ServerKeyFile)
+	keyPath = filepath.Join(cfg.CertsDirectory, cfg.ServerKeyFile)
 	if err := saveKey(keyPath, serverKey); err != nil {
-		return fmt.Errorf("failed to save server key: %w", err)
+		return "", "", fmt.Errorf("failed to save server key: %w", err)
 	}
-
-	fmt.Printf("Generated server certificate: %s and %s\n", certPath, keyPath)
-	return nil
+	return certPath, keyPath, nil
 }
 
 // GenerateClientCertificate creates a client certificate signed by the CA.
 func GenerateClientCertificate(cfg *config.Config, clientName string) error {
+	certPath, keyPath, err := writeClientCertificate(cfg, clientName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Generated client certificate: %s and %s\n", certPath, keyPath)
+	return nil
+}
+
+// writeClientCertificate does the work of GenerateClientCertificate and returns
+// the written paths.
+func writeClientCertificate(cfg *config.Config, clientName string) (certPath, keyPath string, err error) {
 	caCertPath := filepath.Join(cfg.CertsDirectory, cfg.CACertFile)
 	caKeyPath := filepath.Join(cfg.CertsDirectory, "ca.key")
 
 	caCert, caKey, err := loadCA(caCertPath, caKeyPath)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
 	clientKey, clientCert, err := generateCert(clientName, caKey, caCert, false, cfg.CertExpiryDays)
 	if err != nil {
-		return fmt.Errorf("failed to generate client certificate: %w", err)
+		return "", "", fmt.Errorf("failed to generate client certificate: %w", err)
 	}
 
-	certPath := filepath.Join(cfg.CertsDirectory, clientName+".crt")
+	certPath = filepath.Join(cfg.CertsDirectory, clientName+".crt")
 	if err := saveCert(certPath, clientCert); err != nil {
-		return fmt.Errorf("failed to save client certificate: %w", err)
+		return "", "", fmt.Errorf("failed to save client certificate: %w", err)
 	}
 
-	keyPath := filepath.Join(cfg.CertsDirectory, clientName+".key")
+	keyPath = filepath.Join(cfg.CertsDirectory, clientName+".key")
 	if err := saveKey(keyPath, clientKey); err != nil {
-		return fmt.Errorf("failed to save client key: %w", err)
+		return "", "", fmt.Errorf("failed to save client key: %w", err)
 	}
+	return certPath, keyPath, nil
+}
 
-	fmt.Printf("Generated client certificate: %s and %s\n", certPath, keyPath)
+// CertStep describes one completed step of GenerateTLSCertificates.
+type CertStep struct {
+	// Name is what was generated, e.g. "Root CA".
+	Name     string
+	CertPath string
+	KeyPath  string
+}
+
+// GenerateTLSCertificates creates a new CA, a server certificate for serverName
+// and a client certificate for clientName in cfg.CertsDirectory, valid for
+// cfg.CertExpiryDays. Unlike the single-step functions it prints nothing;
+// progress, when not nil, is called after each completed step instead. The
+// first failing step ends the run.
+func GenerateTLSCertificates(cfg *config.Config, caName, serverName, clientName string, progress func(CertStep)) error {
+	steps := []struct {
+		name  string
+		write func() (string, string, error)
+	}{
+		{"Root CA", func() (string, string, error) { return writeCA(cfg, caName) }},
+		{"server certificate", func() (string, string, error) { return writeServerCertificate(cfg, serverName) }},
+		{"client certificate", func() (string, string, error) { return writeClientCertificate(cfg, clientName) }},
+	}
+	for _, step := range steps {
+		certPath, keyPath, err := step.write()
+		if err != nil {
+			return fmt.Errorf("generating %s failed: %w", step.name, err)
+		}
+		if progress != nil {
+			progress(CertStep{Name: step.name, CertPath: certPath, KeyPath: keyPath})
+		}
+	}
 	return nil
 }
 
+// ClientCertOptions adds optional subject fields and subject alternative names
+// to a client certificate, e.g. for mapping certificates to roles. The zero value
+// issues a certificate that carries only the client name as its Common Name.
+type ClientCertOptions struct {
+	Organization       string
+	OrganizationalUnit string
+	// URIs are added as URI SANs, e.g. spiffe://example.org/gaia/billing.
+	URIs []*url.URL
+}
+
 // GenerateClientCertificateData generates client certificate data in memory.
-func GenerateClientCertificateData(clientName string, caCert *x509.Certificate, caKey *rsa.PrivateKey, validityDays int) (certPEM, keyPEM []byte, err error) {
-	return generateClientCertData(clientName, caCert, caKey, validityDays)
+func GenerateClientCertificateData(clientName string, caCert *x509.Certificate, caKey *rsa.PrivateKey, validityDays int, opts ClientCertOptions) (certPEM, keyPEM []byte, err error) {
+	return generateClientCertData(clientName, caCert, caKey, validityDays, opts)
 }
@@ -0,0 +1,70 @@
+package certs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+func TestGenerateTLSCertificates(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.CertsDirectory = filepath.Join(t.TempDir(), "certs")
+	cfg.CertExpiryDays = 30
+
+	var steps []CertStep
+	if err := GenerateTLSCertificates(cfg, "Test CA", "gaia.example.com", "gaia-cli", func(s CertStep) {
+		steps = append(steps, s)
+	}); err != nil {
+		t.Fatalf("GenerateTLSCertificates() error = %v", err)
+	}
+
+	want := []CertStep{
+		{"Root CA", filepath.Join(cfg.CertsDirectory, cfg.CACertFile), filepath.Join(cfg.CertsDirectory, "ca.key")},
+		{"server certificate", filepath.Join(cfg.CertsDirectory, cfg.ServerCertFile), filepath.Join(cfg.CertsDirectory, cfg.ServerKeyFile)},
+		{"client certificate", filepath.Join(cfg.CertsDirectory, "gaia-cli.crt"), filepath.Join(cfg.CertsDirectory, "gaia-cli.key")},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Fatalf("progress steps = %+v, want %+v", steps, want)
+	}
+
+	// The server certificate chains to the new CA and covers the server name.
+	caPath := want[0].CertPath
+	if _, err := VerifyCertificate(caPath, want[1].CertPath, "gaia.example.com"); err != nil {
+		t.Errorf("VerifyCertificate(server) error = %v", err)
+	}
+	res, err := VerifyCertificate(caPath, want[2].CertPath, "gaia.example.com")
+	if err != nil || res.Subject != "gaia-cli" {
+		t.Errorf("VerifyCertificate(client) = %+v, %v, want a client certificate for gaia-cli", res, err)
+	}
+
+	// The validity comes from the configuration.
+	cert, err := loadCert(want[2].CertPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validity := cert.NotAfter.Sub(cert.NotBefore); validity > 31*24*time.Hour {
+		t.Errorf("client certificate is valid for %v, want about %d days", validity, cfg.CertExpiryDays)
+	}
+}
+
+func TestGenerateTLSCertificates_StopsAtFailedStep(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.CertsDirectory = filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(cfg.CertsDirectory, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	err := GenerateTLSCertificates(cfg, "Test CA", "localhost", "gaia-cli", func(CertStep) { called = true })
+	if err == nil || !strings.Contains(err.Error(), "Root CA") {
+		t.Errorf("GenerateTLSCertificates() error = %v, want a failed Root CA step", err)
+	}
+	if called {
+		t.Error("progress was reported for a failed step")
+	}
+}
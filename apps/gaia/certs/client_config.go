@@ -0,0 +1,73 @@
+package certs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+// ClientConnConfig is the connection file written next to a newly registered
+// client's certificate. It is read by LoadConfigFile of the Go client library.
+type ClientConnConfig struct {
+	Address    string `json:"address"`
+	ServerName string `json:"server_name"`
+	CACert     string `json:"ca_cert_file"`
+	ClientCert string `json:"client_cert_file"`
+	ClientKey  string `json:"client_key_file"`
+}
+
+// SaveClientCertificate writes the certificate and key issued to clientName to
+// <dir>/<clientName>.crt and .key, creating dir if needed, and returns their
+// paths together with the CA certificate of cfg.
+func SaveClientCertificate(cfg *config.Config, dir, clientName string, certPEM, keyPEM []byte) (ClientPaths, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ClientPaths{}, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	p := ClientPaths{
+		CACert: filepath.Join(cfg.CertsDirectory, cfg.CACertFile),
+		Cert:   filepath.Join(dir, clientName+".crt"),
+		Key:    filepath.Join(dir, clientName+".key"),
+	}
+	if err := os.WriteFile(p.Cert, certPEM, 0644); err != nil {
+		return ClientPaths{}, fmt.Errorf("failed to write certificate file: %w", err)
+	}
+	if err := os.WriteFile(p.Key, keyPEM, 0600); err != nil {
+		return ClientPaths{}, fmt.Errorf("failed to write private key file: %w", err)
+	}
+	return p, nil
+}
+
+// WriteClientConnConfig writes the connection file for the client certificate
+// at p to <dir of the certificate>/<clientName>.json and returns its path. The
+// address and server name are those of the daemon configured by cfg, and the
+// certificate paths are made absolute.
+func WriteClientConnConfig(cfg *config.Config, p ClientPaths, clientName string) (string, error) {
+	c := ClientConnConfig{
+		Address:    net.JoinHostPort(ServerName(cfg), cfg.GRPCPort),
+		ServerName: ServerName(cfg),
+	}
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{{&c.CACert, p.CACert}, {&c.ClientCert, p.Cert}, {&c.ClientKey, p.Key}} {
+		abs, err := filepath.Abs(f.src)
+		if err != nil {
+			return "", err
+		}
+		*f.dst = abs
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(filepath.Dir(p.Cert), clientName+".json")
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write client config file: %w", err)
+	}
+	return path, nil
+}
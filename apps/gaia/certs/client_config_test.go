@@ -0,0 +1,56 @@
+package certs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteClientConnConfig(t *testing.T) {
+	cfg := newTestCertsDir(t, "billing")
+	cfg.GRPCServerName = "gaia.internal"
+	cfg.GRPCPort = "6000"
+	certPEM, err := os.ReadFile(filepath.Join(cfg.CertsDirectory, "billing.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(cfg.CertsDirectory, "billing.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+	p, err := SaveClientCertificate(cfg, out, "billing", certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("SaveClientCertificate() error = %v", err)
+	}
+	path, err := WriteClientConnConfig(cfg, p, "billing")
+	if err != nil {
+		t.Fatalf("WriteClientConnConfig() error = %v", err)
+	}
+	if path != filepath.Join(out, "billing.json") {
+		t.Errorf("WriteClientConnConfig() path = %s, want billing.json next to the certificate", path)
+	}
+
+	// Decode the file the way the Go client's LoadConfigFile does.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got ClientConnConfig
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("client config is not valid JSON: %v", err)
+	}
+	if got.Address != "gaia.internal:6000" || got.ServerName != "gaia.internal" {
+		t.Errorf("client config address = %s, server name = %s; want gaia.internal:6000 and gaia.internal", got.Address, got.ServerName)
+	}
+	for _, f := range []string{got.CACert, got.ClientCert, got.ClientKey} {
+		if !filepath.IsAbs(f) {
+			t.Errorf("client config path %q is not absolute", f)
+		}
+	}
+	if _, err := ClientTLSConfig(ClientPaths{CACert: got.CACert, Cert: got.ClientCert, Key: got.ClientKey}, got.ServerName); err != nil {
+		t.Errorf("ClientTLSConfig() from the client config error = %v", err)
+	}
+}
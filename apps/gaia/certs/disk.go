@@ -8,18 +8,21 @@ import (
 	"os"
 )
 
-// saveCert writes a certificate to a file.
-func saveCert(filename string, cert *x509.Certificate) error {
+// saveCert writes certificates to a file, in order. A leaf is followed by the
+// intermediates it is sent with.
+func saveCert(filename string, certs ...*x509.Certificate) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	return pem.Encode(file, &pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: cert.Raw,
-	})
+	for _, cert := range certs {
+		if err := pem.Encode(file, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // saveKey writes a private key to a file.
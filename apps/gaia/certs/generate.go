@@ -13,15 +13,37 @@ import (
 	"time"
 )
 
+// serialNumberBits is the size of certificate serial numbers. Random 128-bit
+// serials cannot collide in practice, which revocation relies on to tell
+// certificates apart.
+const serialNumberBits = 128
+
+// newSerialNumber returns a random, positive certificate serial number.
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	// A serial number must be positive, so zero is replaced by one.
+	if serial.Sign() == 0 {
+		serial.SetInt64(1)
+	}
+	return serial, nil
+}
+
 // generateCA creates a self-signed Root Certificate Authority.
 func generateCA(commonName string, validityDays int) (*rsa.PrivateKey, *x509.Certificate, error) {
 	key, err := rsa.GenerateKey(rand.Reader, 4096)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate CA key: %w", err)
 	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(1),
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			CommonName:   commonName,
 			Organization: []string{"Gaia Root CA"},
@@ -47,15 +69,50 @@ func generateCA(commonName string, validityDays int) (*rsa.PrivateKey, *x509.Cer
 	return key, cert, nil
 }
 
+// crossSignCA issues a copy of caCert, with the same subject and key, signed by
+// another CA. Clients that only trust the other CA accept certificates of caCert
+// when the copy is sent along as an intermediate.
+func crossSignCA(caCert, signerCert *x509.Certificate, signerKey *rsa.PrivateKey) (*x509.Certificate, error) {
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	// The copy is only needed while the signing CA is still trusted.
+	notAfter := caCert.NotAfter
+	if signerCert.NotAfter.Before(notAfter) {
+		notAfter = signerCert.NotAfter
+	}
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               caCert.Subject,
+		SubjectKeyId:          caCert.SubjectKeyId,
+		NotBefore:             caCert.NotBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		ExtKeyUsage:           caCert.ExtKeyUsage,
+		KeyUsage:              caCert.KeyUsage,
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, &template, signerCert, caCert.PublicKey, signerKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cross-sign CA certificate: %w", err)
+	}
+	return x509.ParseCertificate(certBytes)
+}
+
 // generateCert creates a certificate signed by the given CA.
 func generateCert(commonName string, caKey *rsa.PrivateKey, caCert *x509.Certificate, isServer bool, validityDays int) (*rsa.PrivateKey, *x509.Certificate, error) {
 	key, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate key: %w", err)
 	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			CommonName: commonName,
 		},
@@ -65,8 +122,7 @@ func generateCert(commonName string, caKey *rsa.PrivateKey, caCert *x509.Certifi
 	}
 	if isServer {
 		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
-		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
-		template.DNSNames = []string{"localhost"}
+		template.DNSNames, template.IPAddresses = serverSANs(commonName)
 	} else {
 		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
 	}
@@ -84,15 +140,35 @@ func generateCert(commonName string, caKey *rsa.PrivateKey, caCert *x509.Certifi
 	return key, cert, nil
 }
 
+// serverSANs returns the subject alternative names of a server certificate for
+// serverName. localhost and 127.0.0.1 are always included so local connections
+// keep working when the daemon is reachable under another name.
+func serverSANs(serverName string) ([]string, []net.IP) {
+	dnsNames := []string{DefaultServerName}
+	ips := []net.IP{net.ParseIP("127.0.0.1")}
+	if ip := net.ParseIP(serverName); ip != nil {
+		if !ip.Equal(ips[0]) {
+			ips = append(ips, ip)
+		}
+	} else if serverName != "" && serverName != DefaultServerName {
+		dnsNames = append(dnsNames, serverName)
+	}
+	return dnsNames, ips
+}
+
 // generateClientCertData creates a new client certificate and returns the PEM-encoded data.
-func generateClientCertData(clientName string, caCert *x509.Certificate, caKey *rsa.PrivateKey, validityDays int) (certPEM, keyPEM []byte, err error) {
+func generateClientCertData(clientName string, caCert *x509.Certificate, caKey *rsa.PrivateKey, validityDays int, opts ClientCertOptions) (certPEM, keyPEM []byte, err error) {
 	clientKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate client key: %w", err)
 	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	template := &x509.Certificate{
-		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			CommonName: clientName,
 		},
@@ -100,6 +176,13 @@ func generateClientCertData(clientName string, caCert *x509.Certificate, caKey *
 		NotAfter:    time.Now().AddDate(0, 0, validityDays),
 		KeyUsage:    x509.KeyUsageDigitalSignature,
 		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		URIs:        opts.URIs,
+	}
+	if opts.Organization != "" {
+		template.Subject.Organization = []string{opts.Organization}
+	}
+	if opts.OrganizationalUnit != "" {
+		template.Subject.OrganizationalUnit = []string{opts.OrganizationalUnit}
 	}
 
 	certBytes, err := x509.CreateCertificate(rand.Reader, template, caCert, &clientKey.PublicKey, caKey)
@@ -0,0 +1,54 @@
+package certs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+func TestNewSerialNumber_Unique(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 10000 {
+		serial, err := newSerialNumber()
+		if err != nil {
+			t.Fatalf("newSerialNumber() error = %v", err)
+		}
+		if serial.Sign() <= 0 || serial.BitLen() > serialNumberBits {
+			t.Fatalf("newSerialNumber() = %v, want a positive %d-bit number", serial, serialNumberBits)
+		}
+		if seen[serial.String()] {
+			t.Fatalf("newSerialNumber() returned %v twice", serial)
+		}
+		seen[serial.String()] = true
+	}
+}
+
+func TestGenerate_DistinctSerials(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.CertsDirectory = t.TempDir()
+	if err := GenerateCA(cfg, "Test CA"); err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	if err := GenerateServerCertificate(cfg, "localhost"); err != nil {
+		t.Fatalf("GenerateServerCertificate() error = %v", err)
+	}
+	for _, name := range []string{"app-a", "app-b"} {
+		if err := GenerateClientCertificate(cfg, name); err != nil {
+			t.Fatalf("GenerateClientCertificate(%s) error = %v", name, err)
+		}
+	}
+
+	seen := make(map[string]string)
+	for _, file := range []string{cfg.CACertFile, cfg.ServerCertFile, "app-a.crt", "app-b.crt"} {
+		cert, err := loadCert(filepath.Join(cfg.CertsDirectory, file))
+		if err != nil {
+			t.Fatalf("failed to load %s: %v", file, err)
+		}
+		serial := cert.SerialNumber.String()
+		if other, ok := seen[serial]; ok {
+			t.Errorf("%s and %s share serial number %s", file, other, serial)
+		}
+		seen[serial] = file
+	}
+}
@@ -0,0 +1,103 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+// DefaultAdminClientName is the Common Name of the CLI certificate created by
+// 'gaia certs generate' when no other name is given.
+const DefaultAdminClientName = "gaia-cli"
+
+// ClientPaths holds the files a client needs for an mTLS connection to the daemon.
+type ClientPaths struct {
+	CACert string
+	Cert   string
+	Key    string
+}
+
+// missing returns an error naming the first file of p that does not exist.
+func (p ClientPaths) missing() error {
+	for _, path := range []string{p.Cert, p.Key, p.CACert} {
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveClientPaths locates <clientName>.crt, <clientName>.key and ca.crt in
+// certsDir, the layout written by GenerateClientCertificate and GenerateCA.
+func ResolveClientPaths(certsDir, clientName string) (ClientPaths, error) {
+	p := ClientPaths{
+		CACert: filepath.Join(certsDir, "ca.crt"),
+		Cert:   filepath.Join(certsDir, clientName+".crt"),
+		Key:    filepath.Join(certsDir, clientName+".key"),
+	}
+	if err := p.missing(); err != nil {
+		return ClientPaths{}, fmt.Errorf("certificate for client '%s' not found: %w", clientName, err)
+	}
+	return p, nil
+}
+
+// AdminClientPaths returns the certificate files the CLI, the TUI and status
+// checks use to reach the daemon. The configured client certificate and key are
+// looked up in CertsDirectory; when they do not exist, the certificate of
+// DefaultAdminClientName is used instead.
+func AdminClientPaths(cfg *config.Config) (ClientPaths, error) {
+	p := ClientPaths{
+		CACert: filepath.Join(cfg.CertsDirectory, cfg.CACertFile),
+		Cert:   filepath.Join(cfg.CertsDirectory, cfg.GaiaClientCertFile),
+		Key:    filepath.Join(cfg.CertsDirectory, cfg.GaiaClientKeyFile),
+	}
+	err := p.missing()
+	if err == nil {
+		return p, nil
+	}
+	if fallback, fallbackErr := ResolveClientPaths(cfg.CertsDirectory, DefaultAdminClientName); fallbackErr == nil {
+		return fallback, nil
+	}
+	return ClientPaths{}, fmt.Errorf("admin client certificate not found: %w", err)
+}
+
+// DefaultServerName is the name clients verify the daemon certificate against
+// when no gRPC server name is configured.
+const DefaultServerName = "localhost"
+
+// ServerName returns the name clients use to verify the daemon certificate: the
+// configured gRPC server name, or DefaultServerName when it is empty.
+func ServerName(cfg *config.Config) string {
+	if cfg.GRPCServerName == "" {
+		return DefaultServerName
+	}
+	return cfg.GRPCServerName
+}
+
+// ClientTLSConfig loads the key pair and CA of p into a TLS configuration for
+// connecting to serverName.
+func ClientTLSConfig(p ClientPaths, serverName string) (*tls.Config, error) {
+	clientCert, err := tls.LoadX509KeyPair(p.Cert, p.Key)
+	if err != nil {
+		return nil, fmt.Errorf("could not load client key pair: %w", err)
+	}
+
+	caCert, err := os.ReadFile(p.CACert)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA certificate: %w", err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("could not append CA certificate to pool")
+	}
+
+	return &tls.Config{
+		ServerName:   serverName,
+		Certificates: []tls.Certificate{clientCert},
+		RootCAs:      certPool,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package certs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+// newTestCertsDir generates a CA and a certificate for each client in a temporary directory.
+func newTestCertsDir(t *testing.T, clients ...string) *config.Config {
+	t.Helper()
+	cfg := config.NewDefaultConfig()
+	cfg.CertsDirectory = t.TempDir()
+	if err := GenerateCA(cfg, "Test CA"); err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	for _, name := range clients {
+		if err := GenerateClientCertificate(cfg, name); err != nil {
+			t.Fatalf("GenerateClientCertificate(%s) error = %v", name, err)
+		}
+	}
+	return cfg
+}
+
+func TestResolveClientPaths(t *testing.T) {
+	cfg := newTestCertsDir(t, "billing")
+
+	p, err := ResolveClientPaths(cfg.CertsDirectory, "billing")
+	if err != nil {
+		t.Fatalf("ResolveClientPaths() error = %v", err)
+	}
+	want := ClientPaths{
+		CACert: filepath.Join(cfg.CertsDirectory, "ca.crt"),
+		Cert:   filepath.Join(cfg.CertsDirectory, "billing.crt"),
+		Key:    filepath.Join(cfg.CertsDirectory, "billing.key"),
+	}
+	if p != want {
+		t.Errorf("ResolveClientPaths() = %+v, want %+v", p, want)
+	}
+	if _, err := ClientTLSConfig(p, "localhost"); err != nil {
+		t.Errorf("ClientTLSConfig() error = %v", err)
+	}
+
+	if _, err := ResolveClientPaths(cfg.CertsDirectory, "orders"); err == nil {
+		t.Error("ResolveClientPaths() for a client without certificates succeeded, want an error")
+	}
+}
+
+func TestAdminClientPaths(t *testing.T) {
+	t.Run("configured files in the certs directory", func(t *testing.T) {
+		cfg := newTestCertsDir(t, "admin")
+		cfg.GaiaClientCertFile = "admin.crt"
+		cfg.GaiaClientKeyFile = "admin.key"
+
+		// Run from another directory: the files must not be resolved relative to
+		// the working directory.
+		t.Chdir(t.TempDir())
+		p, err := AdminClientPaths(cfg)
+		if err != nil {
+			t.Fatalf("AdminClientPaths() error = %v", err)
+		}
+		if p.Cert != filepath.Join(cfg.CertsDirectory, "admin.crt") || p.Key != filepath.Join(cfg.CertsDirectory, "admin.key") {
+			t.Errorf("AdminClientPaths() = %+v, want the configured files in %s", p, cfg.CertsDirectory)
+		}
+	})
+
+	t.Run("falls back to the generated CLI certificate", func(t *testing.T) {
+		cfg := newTestCertsDir(t, DefaultAdminClientName)
+		p, err := AdminClientPaths(cfg)
+		if err != nil {
+			t.Fatalf("AdminClientPaths() error = %v", err)
+		}
+		if p.Cert != filepath.Join(cfg.CertsDirectory, DefaultAdminClientName+".crt") {
+			t.Errorf("AdminClientPaths() cert = %s, want the %s certificate", p.Cert, DefaultAdminClientName)
+		}
+	})
+
+	t.Run("no certificate", func(t *testing.T) {
+		cfg := newTestCertsDir(t)
+		if _, err := AdminClientPaths(cfg); err == nil {
+			t.Error("AdminClientPaths() without client certificates succeeded, want an error")
+		}
+	})
+}
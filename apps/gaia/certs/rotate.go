@@ -0,0 +1,117 @@
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+// RotateCAResult lists the files written by RotateCA.
+type RotateCAResult struct {
+	CACert     string
+	ServerCert string
+	// AdminCert is empty when there was no admin client certificate to reissue.
+	AdminCert string
+	// PreviousCACert is empty when the previous CA was not kept.
+	PreviousCACert string
+}
+
+// RotateCA replaces the CA in cfg.CertsDirectory with a new one named
+// commonName and re-signs the server and admin client certificates with it,
+// keeping their names. The old CA key is overwritten.
+//
+// With keepPrevious, the old CA certificate is kept as cfg.PreviousCACertFile,
+// which the daemon trusts for client certificates until the file is removed,
+// and the server certificate is sent along with a copy of the new CA signed by
+// the old one, so clients that only trust the old CA still accept the daemon.
+// Without it, a previous CA left by an earlier rotation is removed as well.
+func RotateCA(cfg *config.Config, commonName string, keepPrevious bool) (*RotateCAResult, error) {
+	dir := cfg.CertsDirectory
+	caCertPath := filepath.Join(dir, cfg.CACertFile)
+	caKeyPath := filepath.Join(dir, "ca.key")
+	serverCertPath := filepath.Join(dir, cfg.ServerCertFile)
+	previousPath := filepath.Join(dir, cfg.PreviousCACertFile)
+
+	if keepPrevious && cfg.PreviousCACertFile == "" {
+		return nil, errors.New("previous_ca_cert_file is not set, so the previous CA cannot be kept")
+	}
+
+	oldCert, oldKey, err := loadCA(caCertPath, caKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	oldServer, err := loadCert(serverCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	// The admin certificate is reissued when it lives in the same directory.
+	var oldAdmin *x509.Certificate
+	admin, err := AdminClientPaths(cfg)
+	if err == nil {
+		if oldAdmin, err = loadCert(admin.Cert); err != nil {
+			return nil, fmt.Errorf("failed to load admin client certificate: %w", err)
+		}
+	}
+
+	caKey, caCert, err := generateCA(commonName, cfg.CertExpiryDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA: %w", err)
+	}
+	serverKey, serverCert, err := generateCert(oldServer.Subject.CommonName, caKey, caCert, true, cfg.CertExpiryDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate server certificate: %w", err)
+	}
+	serverChain := []*x509.Certificate{serverCert}
+	if keepPrevious {
+		cross, err := crossSignCA(caCert, oldCert, oldKey)
+		if err != nil {
+			return nil, err
+		}
+		serverChain = append(serverChain, cross)
+	}
+
+	res := &RotateCAResult{CACert: caCertPath, ServerCert: serverCertPath}
+	if keepPrevious {
+		if err := saveCert(previousPath, oldCert); err != nil {
+			return nil, fmt.Errorf("failed to save previous CA certificate: %w", err)
+		}
+		res.PreviousCACert = previousPath
+	} else if cfg.PreviousCACertFile != "" {
+		if err := os.Remove(previousPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove previous CA certificate: %w", err)
+		}
+	}
+
+	if err := saveCert(caCertPath, caCert); err != nil {
+		return nil, fmt.Errorf("failed to save CA certificate: %w", err)
+	}
+	if err := saveKey(caKeyPath, caKey); err != nil {
+		return nil, fmt.Errorf("failed to save CA key: %w", err)
+	}
+	if err := saveCert(serverCertPath, serverChain...); err != nil {
+		return nil, fmt.Errorf("failed to save server certificate: %w", err)
+	}
+	if err := saveKey(filepath.Join(dir, cfg.ServerKeyFile), serverKey); err != nil {
+		return nil, fmt.Errorf("failed to save server key: %w", err)
+	}
+
+	if oldAdmin != nil {
+		adminKey, adminCert, err := generateCert(oldAdmin.Subject.CommonName, caKey, caCert, false, cfg.CertExpiryDays)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate admin client certificate: %w", err)
+		}
+		if err := saveCert(admin.Cert, adminCert); err != nil {
+			return nil, fmt.Errorf("failed to save admin client certificate: %w", err)
+		}
+		if err := saveKey(admin.Key, adminKey); err != nil {
+			return nil, fmt.Errorf("failed to save admin client key: %w", err)
+		}
+		res.AdminCert = admin.Cert
+	}
+	return res, nil
+}
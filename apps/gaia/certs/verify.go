@@ -0,0 +1,78 @@
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// VerifyResult describes a certificate that chained successfully to the CA.
+type VerifyResult struct {
+	Subject  string
+	IsServer bool
+	NotAfter time.Time
+}
+
+// VerifyCertificate checks that the certificate at certPath chains to the CA at
+// caCertPath. Whether the leaf is a server or client certificate is detected
+// from its extended key usage. For server certificates, serverName (if not
+// empty) must be covered by the certificate's SANs.
+func VerifyCertificate(caCertPath, certPath, serverName string) (*VerifyResult, error) {
+	caCert, err := loadCert(caCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA certificate: %w", err)
+	}
+	cert, err := loadCert(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate: %w", err)
+	}
+	return verifyCert(caCert, cert, serverName, time.Now())
+}
+
+// verifyCert runs the chain verification against a single trusted root.
+func verifyCert(caCert, cert *x509.Certificate, serverName string, now time.Time) (*VerifyResult, error) {
+	isServer := slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
+	usage := x509.ExtKeyUsageClientAuth
+	if isServer {
+		usage = x509.ExtKeyUsageServerAuth
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	opts := x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{usage},
+	}
+	if _, err := cert.Verify(opts); err != nil {
+		return nil, fmt.Errorf("certificate %q failed verification: %w", cert.Subject.CommonName, err)
+	}
+
+	if isServer && serverName != "" {
+		if err := cert.VerifyHostname(serverName); err != nil {
+			return nil, fmt.Errorf("server certificate does not cover %q: %w", serverName, err)
+		}
+	}
+
+	return &VerifyResult{
+		Subject:  cert.Subject.CommonName,
+		IsServer: isServer,
+		NotAfter: cert.NotAfter,
+	}, nil
+}
+
+// loadCert reads a single PEM-encoded certificate from disk.
+func loadCert(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM certificate found in %s", path)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,117 @@
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+func TestVerifyCertificate_ValidChain(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.CertsDirectory = t.TempDir()
+	if err := GenerateCA(cfg, "Test CA"); err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	if err := GenerateServerCertificate(cfg, "localhost"); err != nil {
+		t.Fatalf("GenerateServerCertificate() error = %v", err)
+	}
+	if err := GenerateClientCertificate(cfg, "app-a"); err != nil {
+		t.Fatalf("GenerateClientCertificate() error = %v", err)
+	}
+	caPath := filepath.Join(cfg.CertsDirectory, cfg.CACertFile)
+
+	res, err := VerifyCertificate(caPath, filepath.Join(cfg.CertsDirectory, cfg.ServerCertFile), "localhost")
+	if err != nil {
+		t.Fatalf("VerifyCertificate(server) error = %v", err)
+	}
+	if !res.IsServer {
+		t.Errorf("server certificate detected as client certificate")
+	}
+
+	res, err = VerifyCertificate(caPath, filepath.Join(cfg.CertsDirectory, "app-a.crt"), "localhost")
+	if err != nil {
+		t.Fatalf("VerifyCertificate(client) error = %v", err)
+	}
+	if res.IsServer || res.Subject != "app-a" {
+		t.Errorf("VerifyCertificate(client) = %+v, want client certificate for app-a", res)
+	}
+
+	if _, err := VerifyCertificate(caPath, filepath.Join(cfg.CertsDirectory, cfg.ServerCertFile), "gaia.example.com"); err == nil {
+		t.Errorf("VerifyCertificate() accepted a server name not covered by the SANs")
+	}
+}
+
+func TestVerifyCertificate_ExpiredLeaf(t *testing.T) {
+	caKey, caCert, err := generateCA("Test CA", 365)
+	if err != nil {
+		t.Fatalf("generateCA() error = %v", err)
+	}
+	_, leaf, err := generateCert("app-a", caKey, caCert, false, 1)
+	if err != nil {
+		t.Fatalf("generateCert() error = %v", err)
+	}
+
+	_, err = verifyCert(caCert, leaf, "", time.Now().AddDate(0, 0, 2))
+	if err == nil {
+		t.Fatalf("verifyCert() accepted an expired certificate")
+	}
+	if !strings.Contains(err.Error(), "expired") {
+		t.Errorf("verifyCert() error = %v, want an expiry error", err)
+	}
+}
+
+func TestVerifyCertificate_WrongCA(t *testing.T) {
+	caKey, caCert, err := generateCA("Test CA", 365)
+	if err != nil {
+		t.Fatalf("generateCA() error = %v", err)
+	}
+	_, otherCA, err := generateCA("Other CA", 365)
+	if err != nil {
+		t.Fatalf("generateCA() error = %v", err)
+	}
+	_, leaf, err := generateCert("app-a", caKey, caCert, false, 30)
+	if err != nil {
+		t.Fatalf("generateCert() error = %v", err)
+	}
+
+	if _, err := verifyCert(otherCA, leaf, "", time.Now()); err == nil {
+		t.Fatalf("verifyCert() accepted a certificate signed by a different CA")
+	}
+}
+
+func TestVerifyCertificate_WrongUsage(t *testing.T) {
+	caKey, caCert, err := generateCA("Test CA", 365)
+	if err != nil {
+		t.Fatalf("generateCA() error = %v", err)
+	}
+	key, _, err := generateCert("unused", caKey, caCert, false, 30)
+	if err != nil {
+		t.Fatalf("generateCert() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "signing-only"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(0, 0, 30),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+
+	if _, err := verifyCert(caCert, leaf, "", time.Now()); err == nil {
+		t.Fatalf("verifyCert() accepted a certificate without client or server usage")
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/stain-win/gaia/apps/gaia/agent"
+)
+
+var agentSocket string
+
+// agentCmd represents the `agent` command.
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Run a local agent that shares one daemon connection across CLI calls",
+	Long: `Starts a long-running agent that holds a single authenticated mTLS connection
+to the Gaia daemon and exposes it on a local unix socket.
+
+Other gaia commands route through the agent when GAIA_AGENT_SOCKET (or
+agent_socket in the config file) points at its socket, so scripts issuing many
+calls in a row pay for the TLS handshake only once. Without that setting, every
+command connects to the daemon directly as usual.
+
+The socket defaults to gaia-agent.sock in $XDG_RUNTIME_DIR, or in a directory
+only the current user can access when that is not set. It is created accessible
+only to the current user, and commands refuse to use a socket that is not.
+
+Example:
+  gaia agent --socket "$XDG_RUNTIME_DIR/gaia-agent.sock" &
+  export GAIA_AGENT_SOCKET="$XDG_RUNTIME_DIR/gaia-agent.sock"
+  gaia status`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := gaiaDaemon.GetConfig()
+
+		socketPath := agentSocket
+		if socketPath == "" {
+			socketPath = cfg.AgentSocket
+		}
+		if socketPath == "" {
+			var err error
+			if socketPath, err = agent.DefaultSocketPath(); err != nil {
+				return err
+			}
+		}
+
+		// The agent itself must always talk to the daemon directly.
+		upstream, err := dialDaemon(context.Background(), cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer upstream.Close()
+
+		lis, err := agent.Listen(socketPath)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(socketPath)
+
+		a := agent.New(upstream)
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-sigs
+			a.Stop()
+		}()
+
+		fmt.Printf("Gaia agent listening on %s\n", socketPath)
+		fmt.Printf("Set GAIA_AGENT_SOCKET=%s to route commands through it.\n", socketPath)
+		return a.Serve(lis)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+	agentCmd.Flags().StringVar(&agentSocket, "socket", "", "Path of the unix socket to listen on")
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+)
+
+// auditLogFile is the audit log written by every gaia command.
+const auditLogFile = "gaia_audit.log"
+
+// auditCmd represents the base command for the audit log.
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Read the audit log",
+	Long:  `Provides subcommands to read the audit log written by the daemon.`,
+}
+
+// decryptAuditCmd represents the `audit decrypt` subcommand.
+var decryptAuditCmd = &cobra.Command{
+	Use:   "decrypt [file]",
+	Short: "Print the audit log with encrypted lines decrypted",
+	Long: `Prints an audit log written with 'encrypt_audit_log: true', decrypting every
+encrypted line with the master passphrase. Lines written while the daemon was
+locked are printed as they are. Rotated logs compressed with gzip (.gz) are
+read directly.
+
+The file defaults to ` + auditLogFile + ` in the current directory. The daemon does not
+need to be running.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := auditLogFile
+		if len(args) == 1 {
+			path = args[0]
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open audit log: %w", err)
+		}
+		defer f.Close()
+
+		var r io.Reader = f
+		if strings.HasSuffix(path, ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return fmt.Errorf("failed to read compressed audit log: %w", err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+
+		passphrase, err := masterPassphrase(context.Background(), gaiaDaemon.GetConfig(), "Enter master passphrase: ")
+		if err != nil {
+			return err
+		}
+		return gaialog.DecryptLog(r, cmd.OutOrStdout(), []byte(passphrase))
+	},
+}
+
+func init() {
+	auditCmd.AddCommand(decryptAuditCmd)
+}
@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stain-win/gaia/apps/gaia/certs"
 	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
 )
 
 var (
@@ -15,6 +19,16 @@ var (
 	clientName string
 )
 
+// resolveOutputDir returns the directory certificates are written to: flag when
+// --output-dir was given, and the configured certs directory otherwise, so the
+// daemon finds what the CLI writes.
+func resolveOutputDir(flag string, cfg *config.Config) string {
+	if flag != "" {
+		return flag
+	}
+	return cfg.CertsDirectory
+}
+
 // certsCmd represents the base command for certificate management
 var certsCmd = &cobra.Command{
 	Use:   "certs",
@@ -30,13 +44,13 @@ var createCaCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Println("Generating new Certificate Authority...")
 		cfg := config.NewDefaultConfig()
-		cfg.CertsDirectory = outputDir
+		cfg.CertsDirectory = resolveOutputDir(outputDir, gaiaDaemon.GetConfig())
 
 		if err := certs.GenerateCA(cfg, caName); err != nil {
 			return fmt.Errorf("failed to generate CA: %w", err)
 		}
 
-		fmt.Printf("\n✔ Certificate Authority created successfully in %s/\n", outputDir)
+		fmt.Printf("\n✔ Certificate Authority created successfully in %s/\n", cfg.CertsDirectory)
 		fmt.Println("  - ca.crt (public certificate)")
 		fmt.Println("  - ca.key (private key - KEEP SAFE!)")
 		return nil
@@ -53,13 +67,13 @@ var createServerCmd = &cobra.Command{
 		serverName = args[0]
 		fmt.Printf("Generating new server certificate for %s...\n", serverName)
 		cfg := config.NewDefaultConfig()
-		cfg.CertsDirectory = outputDir
+		cfg.CertsDirectory = resolveOutputDir(outputDir, gaiaDaemon.GetConfig())
 
 		if err := certs.GenerateServerCertificate(cfg, serverName); err != nil {
 			return fmt.Errorf("failed to generate server certificate: %w", err)
 		}
 
-		fmt.Printf("\n✔ Server certificate created successfully in %s/\n", outputDir)
+		fmt.Printf("\n✔ Server certificate created successfully in %s/\n", cfg.CertsDirectory)
 		fmt.Println("  - server.crt (public certificate)")
 		fmt.Println("  - server.key (private key)")
 		return nil
@@ -76,19 +90,131 @@ var createClientCmd = &cobra.Command{
 		clientName := args[0]
 		fmt.Printf("Generating new client certificate for %s...\n", clientName)
 		cfg := config.NewDefaultConfig()
-		cfg.CertsDirectory = outputDir
+		cfg.CertsDirectory = resolveOutputDir(outputDir, gaiaDaemon.GetConfig())
 
 		if err := certs.GenerateClientCertificate(cfg, clientName); err != nil {
 			return fmt.Errorf("failed to generate client certificate: %w", err)
 		}
 
-		fmt.Printf("\n✔ Client certificate created successfully in %s/\n", outputDir)
+		fmt.Printf("\n✔ Client certificate created successfully in %s/\n", cfg.CertsDirectory)
 		fmt.Printf("  - %s.crt (public certificate)\n", clientName)
 		fmt.Printf("  - %s.key (private key)\n", clientName)
 		return nil
 	},
 }
 
+// verifyCertCmd represents the `certs verify` subcommand.
+var verifyCertCmd = &cobra.Command{
+	Use:   "verify [cert-file]",
+	Short: "Verify a certificate against the CA without starting the daemon",
+	Long:  `Verifies that a server or client certificate chains to the CA in the output directory.\n\nThe certificate type is detected from its extended key usage. Server certificates\nmust also cover the configured gRPC server name. If no certificate file is given,\nthe server certificate is verified.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := gaiaDaemon.GetConfig()
+		dir := resolveOutputDir(outputDir, cfg)
+		certPath := filepath.Join(dir, cfg.ServerCertFile)
+		if len(args) == 1 {
+			certPath = args[0]
+		}
+
+		res, err := certs.VerifyCertificate(filepath.Join(dir, cfg.CACertFile), certPath, cfg.GRPCServerName)
+		if err != nil {
+			return err
+		}
+
+		kind := "client"
+		if res.IsServer {
+			kind = "server"
+		}
+		fmt.Printf("✔ %s certificate %q is valid until %s\n", kind, res.Subject, res.NotAfter.Format("2006-01-02"))
+		if res.IsServer {
+			fmt.Printf("  - covers server name %q\n", cfg.GRPCServerName)
+		}
+		return nil
+	},
+}
+
+var rotateNoTransition bool
+
+// rotateCACmd represents the `certs rotate-ca` subcommand.
+var rotateCACmd = &cobra.Command{
+	Use:   "rotate-ca",
+	Short: "Replace the CA and re-sign the server and admin certificates",
+	Long: `Generates a new Certificate Authority in the output directory and re-signs the
+server certificate and the admin client certificate with it. The old CA key is
+overwritten.
+
+By default the old CA certificate is kept as ca.previous.crt for a transition
+window: the daemon keeps accepting client certificates issued by it, and the
+server certificate carries a copy of the new CA signed by the old one, so
+clients that only trust the old CA still connect. Reissue every client
+certificate with 'gaia clients register --reissue', hand out the new ca.crt,
+then remove ca.previous.crt, re-sign the server certificate with
+'gaia certs create-server' and restart the daemon to end the transition.
+
+Use --no-transition when the old CA key was compromised: certificates of the old
+CA are rejected as soon as the daemon restarts.
+
+The clients to reissue are listed from the running daemon before the rotation.
+Restart the daemon afterwards to load the new certificates.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := *gaiaDaemon.GetConfig()
+		clients, listErr := registeredClients(&cfg)
+
+		cfg.CertsDirectory = resolveOutputDir(outputDir, &cfg)
+		res, err := certs.RotateCA(&cfg, caName, !rotateNoTransition)
+		if err != nil {
+			return fmt.Errorf("failed to rotate CA: %w", err)
+		}
+
+		fmt.Printf("✔ New Certificate Authority written to %s\n", res.CACert)
+		fmt.Printf("  - server certificate re-signed: %s\n", res.ServerCert)
+		if res.AdminCert != "" {
+			fmt.Printf("  - admin client certificate re-signed: %s\n", res.AdminCert)
+		}
+		if res.PreviousCACert != "" {
+			fmt.Printf("  - previous CA kept for the transition: %s\n", res.PreviousCACert)
+		} else {
+			fmt.Println("  - certificates of the previous CA are no longer accepted")
+		}
+		fmt.Println("\nRestart the daemon to use the new CA, then reissue every client certificate:")
+		if listErr != nil {
+			fmt.Printf("  (could not list clients from the daemon: %v; see 'gaia clients manifest')\n", listErr)
+		}
+		for _, name := range clients {
+			fmt.Printf("  gaia clients register --reissue %s\n", name)
+		}
+		return nil
+	},
+}
+
+// registeredClients lists the clients with certificates of their own, leaving
+// out the common area.
+func registeredClients(cfg *config.Config) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := getClientConn(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	res, err := pb.NewGaiaAdminClient(conn).ListClients(ctx, &pb.ListClientsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, c := range res.Clients {
+		if cfg.EnableCommonNamespace && c.Name == cfg.CommonName {
+			continue
+		}
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
 // generateCmd represents the `certs generate` subcommand
 var generateCmd = &cobra.Command{
 	Use:   "generate",
@@ -97,7 +223,7 @@ var generateCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Generating new TLS certificates...")
 		cfg := config.NewDefaultConfig()
-		cfg.CertsDirectory = outputDir
+		cfg.CertsDirectory = resolveOutputDir(outputDir, gaiaDaemon.GetConfig())
 
 		fmt.Println("Step 1: Generating Root CA...")
 		if err := certs.GenerateCA(cfg, caName); err != nil {
@@ -130,11 +256,16 @@ func init() {
 	certsCmd.AddCommand(createCaCmd)
 	certsCmd.AddCommand(createServerCmd)
 	certsCmd.AddCommand(createClientCmd)
+	certsCmd.AddCommand(verifyCertCmd)
+	certsCmd.AddCommand(rotateCACmd)
 
-	certsCmd.PersistentFlags().StringVarP(&outputDir, "output-dir", "o", "./certs", "The output directory for the certificates")
+	certsCmd.PersistentFlags().StringVarP(&outputDir, "output-dir", "o", "", "The output directory for the certificates (default: certs_directory of the configuration)")
 
 	createCaCmd.Flags().StringVar(&caName, "ca-name", "Gaia Root CA", "The Common Name for the Root CA")
 
+	rotateCACmd.Flags().StringVar(&caName, "ca-name", "Gaia Root CA", "The Common Name for the new Root CA")
+	rotateCACmd.Flags().BoolVar(&rotateNoTransition, "no-transition", false, "Do not keep trusting the previous CA, e.g. after its key was compromised")
+
 	generateCmd.Flags().StringVar(&caName, "ca-name", "Gaia Root CA", "The Common Name for the Root CA")
 	generateCmd.Flags().StringVar(&serverName, "server-name", "localhost", "The Common Name for the server certificate")
 	generateCmd.Flags().StringVar(&clientName, "client-name", "gaia-cli", "The Common Name for the CLI client certificate")
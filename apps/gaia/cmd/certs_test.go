@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+func TestResolveOutputDir(t *testing.T) {
+	cfg := &config.Config{CertsDirectory: "/etc/gaia/certs"}
+
+	if got := resolveOutputDir("", cfg); got != "/etc/gaia/certs" {
+		t.Errorf("resolveOutputDir() without --output-dir = %q, want the configured certs directory", got)
+	}
+	if got := resolveOutputDir("./certs", cfg); got != "./certs" {
+		t.Errorf("resolveOutputDir(./certs) = %q, want the flag value", got)
+	}
+}
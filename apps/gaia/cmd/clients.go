@@ -1,14 +1,22 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/stain-win/gaia/apps/gaia/certs"
+	"github.com/stain-win/gaia/apps/gaia/config"
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // clientsCmd represents the base command for client management.
@@ -20,7 +28,7 @@ var clientsCmd = &cobra.Command{
 
 // registerClientCmd represents the `clients register` subcommand.
 var registerClientCmd = &cobra.Command{
-	Use:   "register [name]",
+	Use:   "register [name] | --name name... | --from-file file",
 	Short: "Register a new client and generate its certificate",
 	Long: `Registers a new client with the Gaia daemon.
 
@@ -30,12 +38,172 @@ This command communicates with the daemon to:
 
 The generated client certificate and private key will be saved to the specified
 output directory. This certificate is required for the client to authenticate
-with the Gaia daemon.`,
-	Args: cobra.ExactArgs(1), // Enforce that the client name is provided as an argument.
+with the Gaia daemon.
+
+Registering a name that is already taken fails. Use --reissue to issue a new
+certificate for an existing client; its registration is kept unchanged. The
+previous certificate stays valid until it expires.
+
+Use --organization and --ou to set the certificate's Organization and
+Organizational Unit, and --uri-san to add URI subject alternative names such as
+a SPIFFE ID, for environments that map client certificates to roles.
+
+Use --emit-config to also write <name>.json next to the certificate, holding the
+daemon address, server name and certificate paths. The Go client library loads
+it with client.LoadConfigFile.
+
+To onboard many clients at once, repeat --name or list one name per line in a
+file given with --from-file. Each client is registered and saved in turn; a
+failure is reported for its client without stopping the others, and the command
+exits with an error when any client failed.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		clientName = args[0]
+		names := append(append([]string{}, args...), registerNames...)
+		if registerFromFile != "" {
+			fromFile, err := readClientNames(registerFromFile)
+			if err != nil {
+				return err
+			}
+			names = append(names, fromFile...)
+		}
+		if len(names) == 0 {
+			return errors.New("no client name given; pass a name, --name or --from-file")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		c := pb.NewGaiaAdminClient(conn)
+		if len(names) == 1 {
+			if err := registerClient(ctx, c, cfg, names[0]); err != nil {
+				return err
+			}
+			if reissueCert {
+				fmt.Println("\nClient certificate reissued successfully.")
+			} else {
+				fmt.Println("\nClient registered successfully.")
+			}
+			return nil
+		}
+		return registerClients(c, cfg, names)
+	},
+}
+
+// registerClient registers clientName, or reissues its certificate with
+// --reissue, and saves the certificate, the key and, with --emit-config, the
+// connection file.
+func registerClient(ctx context.Context, c pb.GaiaAdminClient, cfg *config.Config, clientName string) error {
+	if reissueCert {
+		fmt.Printf("Reissuing certificate for client: %s\n", clientName)
+	} else {
 		fmt.Printf("Registering new client: %s\n", clientName)
+	}
 
+	res, err := c.RegisterClient(ctx, &pb.RegisterClientRequest{
+		ClientName:         clientName,
+		Reissue:            reissueCert,
+		Organization:       certOrganization,
+		OrganizationalUnit: certOU,
+		UriSans:            certURISANs,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.AlreadyExists:
+			return fmt.Errorf("client '%s' is already registered; use --reissue to issue a new certificate", clientName)
+		case codes.NotFound:
+			return fmt.Errorf("client '%s' is not registered; run without --reissue to register it", clientName)
+		}
+		return fmt.Errorf("gRPC RegisterClient failed: %w", err)
+	}
+
+	paths, err := certs.SaveClientCertificate(cfg, resolveOutputDir(outputDir, cfg), clientName, []byte(res.Certificate), []byte(res.PrivateKey))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  ✓ Certificate saved to: %s\n", paths.Cert)
+	fmt.Printf("  ✓ Private key saved to: %s\n", paths.Key)
+	if emitConfig {
+		configPath, err := certs.WriteClientConnConfig(cfg, paths, clientName)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  ✓ Client config saved to: %s\n", configPath)
+	}
+	return nil
+}
+
+// registerClients registers every name in turn. A failure is reported for its
+// client and does not stop the others; the returned error counts the failures.
+func registerClients(c pb.GaiaAdminClient, cfg *config.Config, names []string) error {
+	failed := 0
+	for _, name := range names {
+		err := func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			return registerClient(ctx, c, cfg, name)
+		}()
+		if err != nil {
+			fmt.Printf("  ✗ %v\n", err)
+			failed++
+		}
+	}
+
+	fmt.Printf("\n%d of %d clients done.\n", len(names)-failed, len(names))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d clients failed", failed, len(names))
+	}
+	return nil
+}
+
+// readClientNames reads client names from path, one per line. Blank lines and
+// lines starting with '#' are skipped.
+func readClientNames(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client names: %w", err)
+	}
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names, nil
+}
+
+var (
+	revokeDryRun     bool
+	reissueCert      bool
+	emitConfig       bool
+	registerNames    []string
+	registerFromFile string
+	certOrganization string
+	certOU           string
+	certURISANs      []string
+)
+
+// revokeClientCmd represents the `clients revoke` subcommand.
+var revokeClientCmd = &cobra.Command{
+	Use:   "revoke [name]",
+	Short: "Revoke a client and delete all of its secrets",
+	Long: `Removes a client's registration and deletes every secret stored for it.
+
+Use --dry-run to print the namespaces and number of secrets that would be
+removed without changing anything.
+
+When the daemon requires re-authentication for destructive operations, you will
+be prompted for the master passphrase.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
@@ -47,35 +215,336 @@ with the Gaia daemon.`,
 		defer conn.Close()
 
 		c := pb.NewGaiaAdminClient(conn)
+		req := &pb.RevokeClientRequest{ClientName: args[0], DryRun: revokeDryRun}
+		res, err := c.RevokeClient(ctx, req)
+		if status.Code(err) == codes.Unauthenticated && !revokeDryRun {
+			// The daemon requires the passphrase to confirm the revocation.
+			if req.Passphrase, err = readPassphrase("Enter master passphrase to confirm: "); err != nil {
+				return err
+			}
+			retryCtx, retryCancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer retryCancel()
+			res, err = c.RevokeClient(retryCtx, req)
+		}
+		if err != nil {
+			return fmt.Errorf("gRPC RevokeClient failed: %w", err)
+		}
+
+		if revokeDryRun {
+			fmt.Printf("Revoking client '%s' would delete %d secrets", args[0], res.SecretCount)
+		} else {
+			fmt.Printf("✔ Client '%s' revoked, %d secrets deleted", args[0], res.SecretCount)
+		}
+		if len(res.Namespaces) == 0 {
+			fmt.Println(".")
+			return nil
+		}
+		fmt.Println(" from:")
+		for _, ns := range res.Namespaces {
+			fmt.Printf("  - %s\n", ns)
+		}
+		return nil
+	},
+}
+
+// grantCommonCmd represents the `clients grant-common` subcommand.
+var grantCommonCmd = &cobra.Command{
+	Use:   "grant-common [name] [namespace...]",
+	Short: "Restrict which common namespaces a client may read",
+	Long: `Limits a client to the listed namespaces of the common area. Clients without
+grants can read every common namespace.
+
+Running the command with only a client name removes the restriction again.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
 
-		res, err := c.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: clientName})
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
 		if err != nil {
-			return fmt.Errorf("gRPC RegisterClient failed: %w", err)
+			return fmt.Errorf("could not connect to daemon: %w", err)
 		}
+		defer conn.Close()
 
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+		c := pb.NewGaiaAdminClient(conn)
+		_, err = c.SetCommonGrants(ctx, &pb.SetCommonGrantsRequest{ClientName: args[0], Namespaces: args[1:]})
+		if err != nil {
+			return fmt.Errorf("gRPC SetCommonGrants failed: %w", err)
 		}
 
-		certPath := filepath.Join(outputDir, clientName+".crt")
-		if err := os.WriteFile(certPath, []byte(res.Certificate), 0644); err != nil {
-			return fmt.Errorf("failed to write certificate file: %w", err)
+		if len(args) == 1 {
+			fmt.Printf("✔ Client '%s' may read every common namespace\n", args[0])
+			return nil
 		}
-		fmt.Printf("  ✓ Certificate saved to: %s\n", certPath)
+		fmt.Printf("✔ Client '%s' may read common namespaces: %s\n", args[0], strings.Join(args[1:], ", "))
+		return nil
+	},
+}
 
-		keyPath := filepath.Join(outputDir, clientName+".key")
-		if err := os.WriteFile(keyPath, []byte(res.PrivateKey), 0600); err != nil {
-			return fmt.Errorf("failed to write private key file: %w", err)
+// allowNamespacesCmd represents the `clients allow-namespaces` subcommand.
+var allowNamespacesCmd = &cobra.Command{
+	Use:   "allow-namespaces [name] [pattern...]",
+	Short: "Restrict which namespaces a client's secrets may be written to",
+	Long: `Rejects secret writes and imports for a client unless the namespace matches
+one of the listed patterns. Patterns are regular expressions matched against the
+whole namespace, so a plain name such as "production" allows only that namespace
+and "prod|staging-.*" allows "prod" and every namespace starting with "staging-".
+
+Running the command with only a client name removes the restriction again.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
 		}
-		fmt.Printf("  ✓ Private key saved to: %s\n", keyPath)
-		fmt.Println("\nClient registered successfully.")
+		defer conn.Close()
 
+		c := pb.NewGaiaAdminClient(conn)
+		_, err = c.SetNamespacePatterns(ctx, &pb.SetNamespacePatternsRequest{ClientName: args[0], Patterns: args[1:]})
+		if err != nil {
+			return fmt.Errorf("gRPC SetNamespacePatterns failed: %w", err)
+		}
+
+		if len(args) == 1 {
+			fmt.Printf("✔ Client '%s' accepts secrets in every namespace\n", args[0])
+			return nil
+		}
+		fmt.Printf("✔ Client '%s' accepts secrets in namespaces matching: %s\n", args[0], strings.Join(args[1:], ", "))
+		return nil
+	},
+}
+
+var (
+	manifestFormat string
+	manifestOutput string
+)
+
+// manifestCmd represents the `clients manifest` subcommand.
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Export registered clients and their certificate metadata",
+	Long: `Prints every registered client with its registration time and the serial,
+SHA-256 fingerprint, issue time and expiry of the last certificate issued to
+it, for access reviews.
+
+Clients registered before certificates were recorded have empty certificate
+fields; reissue their certificate to fill them in. Use --format csv for a
+spreadsheet-friendly export and --output to write to a file.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if manifestFormat != "json" && manifestFormat != "csv" {
+			return fmt.Errorf("unsupported format '%s', use json or csv", manifestFormat)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		c := pb.NewGaiaAdminClient(conn)
+		res, err := c.ExportClientManifest(ctx, &pb.ExportClientManifestRequest{})
+		if err != nil {
+			return fmt.Errorf("gRPC ExportClientManifest failed: %w", err)
+		}
+
+		data, err := encodeManifest(res.Clients, manifestFormat)
+		if err != nil {
+			return err
+		}
+		if manifestOutput == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(manifestOutput, data, 0644); err != nil {
+			return fmt.Errorf("failed to write manifest file: %w", err)
+		}
+		fmt.Printf("✔ Manifest of %d clients written to %s\n", len(res.Clients), manifestOutput)
+		return nil
+	},
+}
+
+// manifestEntry is the JSON form of a single client in the manifest.
+type manifestEntry struct {
+	Name        string `json:"name"`
+	TimeCreated string `json:"time_created"`
+	Serial      string `json:"serial"`
+	Fingerprint string `json:"fingerprint"`
+	IssuedAt    string `json:"issued_at"`
+	NotAfter    string `json:"not_after"`
+}
+
+// encodeManifest serializes the manifest as indented JSON or as CSV with a header row.
+func encodeManifest(clients []*pb.ClientManifestEntry, format string) ([]byte, error) {
+	if format == "csv" {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write([]string{"name", "time_created", "serial", "fingerprint", "issued_at", "not_after"}); err != nil {
+			return nil, err
+		}
+		for _, c := range clients {
+			if err := w.Write([]string{c.Name, c.TimeCreated, c.Serial, c.Fingerprint, c.IssuedAt, c.NotAfter}); err != nil {
+				return nil, err
+			}
+		}
+		w.Flush()
+		return buf.Bytes(), w.Error()
+	}
+
+	entries := make([]manifestEntry, len(clients))
+	for i, c := range clients {
+		entries[i] = manifestEntry{
+			Name:        c.Name,
+			TimeCreated: c.TimeCreated,
+			Serial:      c.Serial,
+			Fingerprint: c.Fingerprint,
+			IssuedAt:    c.IssuedAt,
+			NotAfter:    c.NotAfter,
+		}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+var (
+	clientsExportOutput    string
+	clientsImportOverwrite bool
+)
+
+// registryEntry is the JSON form of a single client registration in an export.
+type registryEntry struct {
+	Name        string `json:"name"`
+	TimeCreated string `json:"time_created"`
+}
+
+// exportClientsCmd represents the `clients export` subcommand.
+var exportClientsCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the client registry as JSON",
+	Long: `Prints every registered client with its registration time as JSON, so the
+registry can be backed up or moved separately from secrets and restored with
+'gaia clients import'. Certificates are not included; reissue them after a
+restore with 'gaia clients register --reissue'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		c := pb.NewGaiaAdminClient(conn)
+		res, err := c.ExportClients(ctx, &pb.ExportClientsRequest{})
+		if err != nil {
+			return fmt.Errorf("gRPC ExportClients failed: %w", err)
+		}
+
+		entries := make([]registryEntry, len(res.Clients))
+		for i, c := range res.Clients {
+			entries[i] = registryEntry{Name: c.Name, TimeCreated: c.TimeCreated}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode clients: %w", err)
+		}
+		data = append(data, '\n')
+		if clientsExportOutput == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(clientsExportOutput, data, 0644); err != nil {
+			return fmt.Errorf("failed to write clients file: %w", err)
+		}
+		fmt.Printf("✔ %d clients exported to %s\n", len(entries), clientsExportOutput)
+		return nil
+	},
+}
+
+// importClientsCmd represents the `clients import` subcommand.
+var importClientsCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Restore client registrations from a 'gaia clients export' file",
+	Long: `Registers every client listed in a file written by 'gaia clients export',
+keeping its original registration time. The import is applied in one
+transaction.
+
+Clients that are already registered are left untouched unless --overwrite is
+given, in which case their registration time is replaced with the one from the
+file.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read clients file: %w", err)
+		}
+		var entries []registryEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("failed to parse clients file: %w", err)
+		}
+		clients := make([]*pb.Client, len(entries))
+		for i, e := range entries {
+			clients[i] = &pb.Client{Name: e.Name, TimeCreated: e.TimeCreated}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		c := pb.NewGaiaAdminClient(conn)
+		res, err := c.ImportClients(ctx, &pb.ImportClientsRequest{Clients: clients, Overwrite: clientsImportOverwrite})
+		if err != nil {
+			return fmt.Errorf("gRPC ImportClients failed: %w", err)
+		}
+		fmt.Printf("✔ %d clients imported, %d already registered clients left untouched\n", res.ClientsImported, res.ClientsSkipped)
 		return nil
 	},
 }
 
 func init() {
 	clientsCmd.AddCommand(registerClientCmd)
+	clientsCmd.AddCommand(revokeClientCmd)
+	clientsCmd.AddCommand(grantCommonCmd)
+	clientsCmd.AddCommand(allowNamespacesCmd)
+	clientsCmd.AddCommand(manifestCmd)
+	clientsCmd.AddCommand(exportClientsCmd)
+	clientsCmd.AddCommand(importClientsCmd)
+
+	registerClientCmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Output directory for the new client certificate and key (default: certs_directory of the configuration)")
+	registerClientCmd.Flags().StringArrayVar(&registerNames, "name", nil, "Client to register (repeatable)")
+	registerClientCmd.Flags().StringVar(&registerFromFile, "from-file", "", "File with one client name per line to register")
+	registerClientCmd.Flags().BoolVar(&emitConfig, "emit-config", false, "Also write <client>.json with the address, server name and certificate paths for the Go client's LoadConfigFile")
+	registerClientCmd.Flags().BoolVar(&reissueCert, "reissue", false, "Issue a new certificate for an already registered client")
+	registerClientCmd.Flags().StringVar(&certOrganization, "organization", "", "Organization (O) of the client certificate")
+	registerClientCmd.Flags().StringVar(&certOU, "ou", "", "Organizational Unit (OU) of the client certificate")
+	registerClientCmd.Flags().StringArrayVar(&certURISANs, "uri-san", nil, "URI subject alternative name to add, e.g. spiffe://example.org/gaia/billing (repeatable)")
+
+	manifestCmd.Flags().StringVar(&manifestFormat, "format", "json", "Output format: json or csv")
+	manifestCmd.Flags().StringVarP(&manifestOutput, "output", "o", "", "Write the manifest to this file instead of standard output")
+
+	exportClientsCmd.Flags().StringVarP(&clientsExportOutput, "output", "o", "", "Write the clients to this file instead of standard output")
+	importClientsCmd.Flags().BoolVar(&clientsImportOverwrite, "overwrite", false, "Replace the registration time of clients that are already registered")
 
-	registerClientCmd.Flags().StringVarP(&outputDir, "output-dir", "o", "./certs", "Output directory for the new client certificate and key")
+	revokeClientCmd.Flags().BoolVar(&revokeDryRun, "dry-run", false, "Report what would be deleted without revoking the client")
 }
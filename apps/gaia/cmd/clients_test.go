@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeRegisterClient answers RegisterClient with a dummy certificate, failing
+// for names that are already registered.
+type fakeRegisterClient struct {
+	pb.GaiaAdminClient
+	registered map[string]bool
+}
+
+func (f *fakeRegisterClient) RegisterClient(_ context.Context, req *pb.RegisterClientRequest, _ ...grpc.CallOption) (*pb.RegisterClientResponse, error) {
+	if f.registered[req.ClientName] {
+		return nil, status.Error(codes.AlreadyExists, "client already registered")
+	}
+	f.registered[req.ClientName] = true
+	return &pb.RegisterClientResponse{Certificate: "cert of " + req.ClientName, PrivateKey: "key of " + req.ClientName}, nil
+}
+
+func TestRegisterClients_FromFile(t *testing.T) {
+	dir := t.TempDir()
+	namesFile := filepath.Join(dir, "names.txt")
+	if err := os.WriteFile(namesFile, []byte("# onboarding batch\nbilling\n\n  reports  \ntaken\nsearch\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	names, err := readClientNames(namesFile)
+	if err != nil {
+		t.Fatalf("readClientNames() error = %v", err)
+	}
+	if strings.Join(names, ",") != "billing,reports,taken,search" {
+		t.Fatalf("readClientNames() = %v, want billing, reports, taken and search", names)
+	}
+
+	cfg := &config.Config{CertsDirectory: filepath.Join(dir, "certs"), CACertFile: "ca.crt"}
+	c := &fakeRegisterClient{registered: map[string]bool{"taken": true}}
+	err = registerClients(c, cfg, names)
+	if err == nil || !strings.Contains(err.Error(), "1 of 4") {
+		t.Errorf("registerClients() error = %v, want one failure reported", err)
+	}
+
+	for _, name := range []string{"billing", "reports", "search"} {
+		for _, ext := range []string{".crt", ".key"} {
+			data, err := os.ReadFile(filepath.Join(cfg.CertsDirectory, name+ext))
+			if err != nil {
+				t.Errorf("%s%s was not written: %v", name, ext, err)
+				continue
+			}
+			if !strings.HasSuffix(string(data), name) {
+				t.Errorf("%s%s = %q, want the file issued to %s", name, ext, data, name)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(cfg.CertsDirectory, "taken.crt")); err == nil {
+		t.Error("certificate written for a client that failed to register")
+	}
+}
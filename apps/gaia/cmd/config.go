@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/stain-win/gaia/apps/gaia/config"
+	"gopkg.in/yaml.v3"
+)
+
+var showSources bool
+
+// configCmd represents the base command for inspecting the configuration.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the Gaia configuration",
+	Long:  `Provides subcommands to inspect the configuration Gaia is running with.`,
+}
+
+// configShowCmd represents the `config show` subcommand.
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Long: `Prints the configuration after defaults, the configuration file and
+environment variables have been applied, as YAML.
+
+Use --sources to list every setting with the layer it came from (default, file
+or env), e.g. to find out why Gaia is using an unexpected database path.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.ResolvePath(cfgFile)
+		if err != nil {
+			return err
+		}
+		cfg, prov, err := config.LoadWithProvenance(path)
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		if !showSources {
+			data, err := yaml.Marshal(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to encode configuration: %w", err)
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+
+		fmt.Printf("Configuration file: %s\n\n", path)
+		values := config.FieldValues(cfg)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+		for _, name := range slices.Sorted(maps.Keys(prov)) {
+			fmt.Fprintf(w, "%s\t%v\t%s\n", name, values[name], prov[name])
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+
+	configShowCmd.Flags().StringVar(&cfgFile, "config", "", "Path to the configuration file (YAML)")
+	configShowCmd.Flags().BoolVar(&showSources, "sources", false, "Show which layer set each setting")
+}
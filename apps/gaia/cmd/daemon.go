@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stain-win/gaia/apps/gaia/daemon"
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 )
 
@@ -37,6 +39,11 @@ with the 'gaia unlock' command before it will serve secrets. This ensures that
 even after a system reboot, secrets are not exposed until an operator
 intervenes.
 
+With 'require_unlock_to_serve: true' in the config file, start prompts for the
+master passphrase on the terminal and unlocks the database before the gRPC
+listener is opened. Nothing is reachable over the network until then. If the
+daemon is locked again later, every RPC except Unlock and GetStatus is refused.
+
 Configuration values can be overridden from the config file using flags.
 For example:
   gaia start --db-file /var/lib/gaia/data.db
@@ -69,6 +76,18 @@ For example:
 			cfg.ServerKeyFile = "/server.key"
 		}
 
+		if cfg.RequireUnlockToServe {
+			fmt.Print("Enter master passphrase: ")
+			passphrase, err := term.ReadPassword(int(syscall.Stdin))
+			if err != nil {
+				log.Fatalf("failed to read passphrase: %v", err)
+			}
+			fmt.Println()
+			if err := gaiaDaemon.UnlockDB(string(passphrase)); err != nil {
+				log.Fatalf("Daemon failed to unlock: %v", err)
+			}
+		}
+
 		err := gaiaDaemon.Start(cfg)
 		if err != nil {
 			log.Fatalf("Daemon failed to start: %v", err)
@@ -143,7 +162,8 @@ var restartCmd = &cobra.Command{
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show the status of the Gaia daemon",
-	Long:  `The status command returns the current operational status of the Gaia daemon.`,
+	Long: `The status command returns the current operational status of the Gaia daemon,
+how long it has been running and the Go version and platform it was built for.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := gaiaDaemon.GetConfig()
 		ctx, cancel := context.WithTimeout(context.Background(), cfg.GRPCClientTimeout)
@@ -163,7 +183,20 @@ var statusCmd = &cobra.Command{
 			return
 		}
 
+		if res.Banner != "" {
+			fmt.Println(res.Banner)
+		}
 		fmt.Printf("Gaia daemon status: %s\n", res.Status)
+		if started, err := time.Parse(time.RFC3339, res.StartedAt); err == nil {
+			fmt.Printf("Uptime: %s\n", daemon.DescribeUptime(started, time.Duration(res.UptimeSeconds)*time.Second))
+		}
+		if res.GoVersion != "" {
+			fmt.Printf("Build: %s %s\n", res.GoVersion, res.Os)
+		}
+		if res.Locked {
+			since, _ := time.Parse(time.RFC3339, res.LockChanged)
+			fmt.Printf("Database: %s\n", daemon.DescribeLock(daemon.LockReason(res.LockReason), since))
+		}
 	},
 }
 
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dbCmd represents the base command for database maintenance.
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Check the encrypted database",
+	Long:  `Provides subcommands to inspect the health of the daemon's encrypted database.`,
+}
+
+// verifyDBCmd represents the `db verify` subcommand.
+var verifyDBCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Decrypt every secret to detect corruption",
+	Long: `Asks the unlocked daemon to decrypt every stored secret and lists the ones
+that fail by client, namespace and id. Values are never printed.
+
+A secret that fails to decrypt points to corruption of the database file, such
+as bit rot or a partial write; restore the database from a backup. The command
+exits with an error when any secret fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		res, err := client.VerifyIntegrity(ctx, &pb.VerifyIntegrityRequest{})
+		if err != nil {
+			return fmt.Errorf("gRPC VerifyIntegrity failed: %w", err)
+		}
+
+		if len(res.Failures) == 0 {
+			fmt.Printf("✔ All %d secrets decrypted successfully\n", res.Checked)
+			return nil
+		}
+		if err := printIntegrityFailures(res.Failures); err != nil {
+			return err
+		}
+		return fmt.Errorf("%d of %d secrets failed to decrypt; restore the database from a backup", len(res.Failures), res.Checked)
+	},
+}
+
+// rekeyCheckCmd represents the `db rekey-check` subcommand.
+var rekeyCheckCmd = &cobra.Command{
+	Use:   "rekey-check",
+	Short: "Check that the database can be rekeyed",
+	Long: `Asks the unlocked daemon to validate the current master passphrase and to
+decrypt every stored secret without writing anything, then reports how many
+secrets a rekey would re-encrypt and lists the ones that fail to decrypt.
+
+Rekeying re-encrypts every secret and fails on the first one that does not
+decrypt, so fix or restore failing secrets before rekeying. The command exits
+with an error when any secret fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		passphrase, err := masterPassphrase(ctx, cfg, "Enter current master passphrase: ")
+		if err != nil {
+			return err
+		}
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		res, err := client.RekeyDryRun(ctx, &pb.RekeyDryRunRequest{OldPassphrase: passphrase})
+		if err != nil {
+			return fmt.Errorf("gRPC RekeyDryRun failed: %w", err)
+		}
+
+		if len(res.Failures) == 0 {
+			fmt.Printf("✔ Passphrase accepted; all %d secrets can be re-encrypted\n", res.Secrets)
+			return nil
+		}
+		if err := printIntegrityFailures(res.Failures); err != nil {
+			return err
+		}
+		return fmt.Errorf("%d of %d secrets failed to decrypt; fix them before rekeying", len(res.Failures), res.Secrets)
+	},
+}
+
+// rekeyCmd represents the `db rekey` subcommand.
+var rekeyCmd = &cobra.Command{
+	Use:   "rekey",
+	Short: "Change the master passphrase",
+	Long: `Changes the master passphrase of the unlocked daemon. Every secret is
+re-encrypted with a key derived from the new passphrase in a single
+transaction, so the database never holds a mix of both keys.
+
+Every secret is decrypted before any is rewritten, and the rekey fails with
+the list of secrets that do not decrypt, as 'gaia db rekey-check' shows them.
+With --check=false the rekey skips that pass and fails on the first secret that
+does not decrypt. When passphrase_command is configured, update the stored
+passphrase once the rekey succeeds.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		oldPassphrase, err := masterPassphrase(ctx, cfg, "Enter current master passphrase: ")
+		if err != nil {
+			return err
+		}
+		newPassphrase, err := readPassphrase("Enter new master passphrase: ")
+		if err != nil {
+			return err
+		}
+		if _, err := encrypt.ValidatePassword(newPassphrase); err != nil {
+			return err
+		}
+		confirm, err := readPassphrase("Confirm new master passphrase: ")
+		if err != nil {
+			return err
+		}
+		if confirm != newPassphrase {
+			return errors.New("passphrases do not match")
+		}
+
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		res, err := client.Rekey(ctx, &pb.RekeyRequest{OldPassphrase: oldPassphrase, NewPassphrase: newPassphrase, VerifyFirst: rekeyVerifyFirst})
+		if err != nil {
+			return fmt.Errorf("gRPC Rekey failed: %w", err)
+		}
+		fmt.Printf("✔ Master passphrase changed; %d secrets re-encrypted\n", res.Secrets)
+		return nil
+	},
+}
+
+// pruneDBCmd represents the `db prune` subcommand.
+var pruneDBCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete secrets of clients that are no longer registered",
+	Long: `Finds secrets stored for clients without a registration, as left behind when
+a registration was removed out of band, and deletes them. The common area is
+never pruned.
+
+With --empty-clients, registrations that hold no secrets are removed as well,
+as 'gaia clients revoke' would. Use --dry-run to list what would be removed
+without changing anything.
+
+When the daemon requires re-authentication for destructive operations, you will
+be prompted for the master passphrase.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		req := &pb.PruneOrphansRequest{DryRun: pruneDryRun, IncludeEmptyClients: pruneEmptyClients}
+		res, err := client.PruneOrphans(ctx, req)
+		if status.Code(err) == codes.Unauthenticated && !pruneDryRun {
+			// The daemon requires the passphrase to confirm the prune.
+			if req.Passphrase, err = readPassphrase("Enter master passphrase to confirm: "); err != nil {
+				return err
+			}
+			res, err = client.PruneOrphans(ctx, req)
+		}
+		if err != nil {
+			return fmt.Errorf("gRPC PruneOrphans failed: %w", err)
+		}
+
+		if len(res.Orphaned) == 0 && len(res.EmptyClients) == 0 {
+			fmt.Println("✔ Nothing to prune")
+			return nil
+		}
+		verb := "Deleted"
+		if pruneDryRun {
+			verb = "Would delete"
+		}
+		for _, o := range res.Orphaned {
+			fmt.Printf("%s %d secrets of unregistered client '%s'\n", verb, o.Secrets, o.ClientName)
+		}
+		for _, name := range res.EmptyClients {
+			fmt.Printf("%s registration of client '%s' without secrets\n", verb, name)
+		}
+		return nil
+	},
+}
+
+var (
+	rekeyVerifyFirst  bool
+	pruneDryRun       bool
+	pruneEmptyClients bool
+)
+
+// printIntegrityFailures prints the secrets that failed to decrypt as a table.
+func printIntegrityFailures(failures []*pb.IntegrityFailure) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLIENT\tNAMESPACE\tID\tERROR")
+	for _, f := range failures {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.ClientName, f.Namespace, f.Id, f.Reason)
+	}
+	return w.Flush()
+}
+
+func init() {
+	dbCmd.AddCommand(verifyDBCmd)
+	dbCmd.AddCommand(rekeyCheckCmd)
+	dbCmd.AddCommand(rekeyCmd)
+	dbCmd.AddCommand(pruneDBCmd)
+
+	rekeyCmd.Flags().BoolVar(&rekeyVerifyFirst, "check", true, "Decrypt every secret before rewriting any and report all that fail")
+
+	pruneDBCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "List what would be removed without deleting anything")
+	pruneDBCmd.Flags().BoolVar(&pruneEmptyClients, "empty-clients", false, "Also remove client registrations that hold no secrets")
+}
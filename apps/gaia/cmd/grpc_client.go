@@ -3,44 +3,184 @@ package cmd
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"net"
+	"sync"
 
+	"github.com/stain-win/gaia/apps/gaia/agent"
+	"github.com/stain-win/gaia/apps/gaia/certs"
 	"github.com/stain-win/gaia/apps/gaia/config"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	// Registers gzip, so responses the daemon compresses are accepted.
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
 )
 
-// getClientConn establishes a secure gRPC connection to the daemon.
-func getClientConn(ctx context.Context, cfg *config.Config) (*grpc.ClientConn, error) {
-	daemonAddress := fmt.Sprintf("%s:%s", cfg.GRPCServerName, cfg.GRPCPort)
-	caCertFile := filepath.Join(cfg.CertsDirectory, cfg.CACertFile)
-	clientCertFile := filepath.Join(cfg.CertsDirectory, cfg.GaiaClientCertFile)
-	clientKeyFile := filepath.Join(cfg.CertsDirectory, cfg.GaianClientKeyFile)
+// connErrorKind is the cause of a failure to reach the daemon.
+type connErrorKind int
+
+const (
+	// connErrCertificate means the local CA, certificate or key could not be loaded.
+	connErrCertificate connErrorKind = iota + 1
+	// connErrNetwork means the daemon could not be reached at all.
+	connErrNetwork
+	// connErrAuth means the TLS handshake failed: the daemon's certificate is
+	// not trusted, or the daemon rejected the client certificate.
+	connErrAuth
+)
+
+// connError is a failure to connect to the daemon, with its cause. It wraps the
+// original error, so gRPC status codes are still available through it.
+type connError struct {
+	kind connErrorKind
+	err  error
+}
+
+func (e *connError) Error() string { return e.err.Error() }
+
+func (e *connError) Unwrap() error { return e.err }
+
+// hint tells the user what to check for the kind of failure.
+func (e *connError) hint() string {
+	switch e.kind {
+	case connErrCertificate:
+		return "Check certs_directory: it must hold the CA certificate and the admin client certificate and key ('gaia certs create-client')."
+	case connErrNetwork:
+		return "Is the daemon running? Start it with 'gaia start' and check grpc_server_name and grpc_port."
+	case connErrAuth:
+		return "The TLS handshake failed: the daemon and this client must use certificates issued by the same CA, and the daemon certificate must cover grpc_server_name."
+	default:
+		return ""
+	}
+}
+
+// connProbe records the last dial or handshake failure of a connection.
+// Connections are dialed lazily, so the failure only surfaces as an Unavailable
+// RPC error; the probe lets that error be traced back to its cause.
+type connProbe struct {
+	mu  sync.Mutex
+	err *connError
+}
 
-	clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+func (p *connProbe) record(kind connErrorKind, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = &connError{kind: kind, err: err}
+}
+
+// classify returns err as a connError of the last recorded failure when it is
+// an Unavailable RPC error, and err unchanged otherwise.
+func (p *connProbe) classify(err error) error {
+	if status.Code(err) != codes.Unavailable {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		return err
+	}
+	return &connError{kind: p.err.kind, err: err}
+}
+
+func (p *connProbe) dial(ctx context.Context, addr string) (net.Conn, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
 	if err != nil {
-		return nil, fmt.Errorf("could not load client key pair: %w", err)
+		p.record(connErrNetwork, err)
 	}
+	return conn, err
+}
+
+func (p *connProbe) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return p.classify(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+func (p *connProbe) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	return stream, p.classify(err)
+}
 
-	caCert, err := os.ReadFile(caCertFile)
+// probeCreds records failed TLS handshakes, including a rejection of the client
+// certificate, which TLS 1.3 only reports on the first read.
+type probeCreds struct {
+	credentials.TransportCredentials
+	probe *connProbe
+}
+
+func (c probeCreds) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, info, err := c.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
 	if err != nil {
-		return nil, fmt.Errorf("could not read CA certificate: %w", err)
+		c.probe.record(connErrAuth, err)
+		return nil, nil, err
 	}
-	certPool := x509.NewCertPool()
-	if !certPool.AppendCertsFromPEM(caCert) {
-		return nil, fmt.Errorf("could not append CA certificate to pool")
+	return &probeConn{Conn: conn, probe: c.probe}, info, nil
+}
+
+func (c probeCreds) Clone() credentials.TransportCredentials {
+	return probeCreds{TransportCredentials: c.TransportCredentials.Clone(), probe: c.probe}
+}
+
+type probeConn struct {
+	net.Conn
+	probe *connProbe
+}
+
+func (c *probeConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	var alert tls.AlertError
+	if errors.As(err, &alert) {
+		c.probe.record(connErrAuth, err)
 	}
+	return n, err
+}
 
-	creds := credentials.NewTLS(&tls.Config{
-		ServerName:   "localhost",
-		Certificates: []tls.Certificate{clientCert},
-		RootCAs:      certPool,
-	})
+// getClientConn returns a connection to the daemon. When an agent socket is
+// configured, calls are routed through the running `gaia agent` so they reuse
+// its authenticated connection; otherwise a direct mTLS connection is made.
+func getClientConn(ctx context.Context, cfg *config.Config) (*grpc.ClientConn, error) {
+	if cfg.AgentSocket != "" {
+		return dialAgent(cfg.AgentSocket)
+	}
+	return dialDaemon(ctx, cfg)
+}
+
+// dialAgent connects to a local `gaia agent` over its unix socket. No transport
+// security is layered on top, so the socket is refused unless it is owned by and
+// only accessible to the current user.
+func dialAgent(socketPath string) (*grpc.ClientConn, error) {
+	if err := agent.CheckSocket(socketPath); err != nil {
+		return nil, &connError{kind: connErrNetwork, err: fmt.Errorf("agent socket not available at '%s': %w", socketPath, err)}
+	}
+	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent client: %w", err)
+	}
+	return conn, nil
+}
+
+// dialDaemon establishes a secure gRPC connection directly to the daemon. Errors
+// of the connection and of RPCs failing to reach the daemon are connErrors.
+func dialDaemon(_ context.Context, cfg *config.Config) (*grpc.ClientConn, error) {
+	daemonAddress := net.JoinHostPort(cfg.GRPCServerName, cfg.GRPCPort)
+	paths, err := certs.AdminClientPaths(cfg)
+	if err != nil {
+		return nil, &connError{kind: connErrCertificate, err: err}
+	}
+	tlsConfig, err := certs.ClientTLSConfig(paths, certs.ServerName(cfg))
+	if err != nil {
+		return nil, &connError{kind: connErrCertificate, err: err}
+	}
 
-	conn, err := grpc.NewClient(daemonAddress, grpc.WithTransportCredentials(creds))
+	probe := &connProbe{}
+	conn, err := grpc.NewClient(daemonAddress,
+		grpc.WithTransportCredentials(probeCreds{TransportCredentials: credentials.NewTLS(tlsConfig), probe: probe}),
+		grpc.WithContextDialer(probe.dial),
+		grpc.WithChainUnaryInterceptor(probe.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(probe.streamInterceptor),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
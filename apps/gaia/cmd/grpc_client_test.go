@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/certs"
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// newConnTestConfig returns a configuration whose certs directory holds a new CA
+// with a server certificate for 127.0.0.1 and an admin client certificate.
+func newConnTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg := config.NewDefaultConfig()
+	cfg.CertsDirectory = t.TempDir()
+	cfg.GRPCServerName = "127.0.0.1"
+	if err := certs.GenerateCA(cfg, "Gaia Test CA"); err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	if err := certs.GenerateServerCertificate(cfg, "127.0.0.1"); err != nil {
+		t.Fatalf("GenerateServerCertificate() error = %v", err)
+	}
+	if err := certs.GenerateClientCertificate(cfg, certs.DefaultAdminClientName); err != nil {
+		t.Fatalf("GenerateClientCertificate() error = %v", err)
+	}
+	return cfg
+}
+
+// callDaemon makes a GetStatus call through getClientConn.
+func callDaemon(cfg *config.Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := getClientConn(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = pb.NewGaiaAdminClient(conn).GetStatus(ctx, &pb.GetStatusRequest{})
+	return err
+}
+
+func assertConnErrorKind(t *testing.T, err error, want connErrorKind) {
+	t.Helper()
+	var connErr *connError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("error = %v, want a connError", err)
+	}
+	if connErr.kind != want {
+		t.Errorf("connError kind = %d, want %d (error: %v)", connErr.kind, want, err)
+	}
+	if connErr.hint() == "" {
+		t.Error("connError has no hint")
+	}
+}
+
+func TestConnError_MissingCertificate(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.CertsDirectory = t.TempDir()
+	assertConnErrorKind(t, callDaemon(cfg), connErrCertificate)
+}
+
+func TestConnError_ConnectionRefused(t *testing.T) {
+	cfg := newConnTestConfig(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	_, cfg.GRPCPort, _ = net.SplitHostPort(lis.Addr().String())
+	lis.Close()
+
+	assertConnErrorKind(t, callDaemon(cfg), connErrNetwork)
+}
+
+func TestConnError_UntrustedServerCertificate(t *testing.T) {
+	cfg := newConnTestConfig(t)
+	other := newConnTestConfig(t)
+
+	// The daemon presents a certificate of another CA.
+	serverCert, err := tls.LoadX509KeyPair(filepath.Join(other.CertsDirectory, other.ServerCertFile), filepath.Join(other.CertsDirectory, other.ServerKeyFile))
+	if err != nil {
+		t.Fatalf("failed to load server key pair: %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{serverCert}})))
+	go s.Serve(lis)
+	defer s.Stop()
+	_, cfg.GRPCPort, _ = net.SplitHostPort(lis.Addr().String())
+
+	assertConnErrorKind(t, callDaemon(cfg), connErrAuth)
+}
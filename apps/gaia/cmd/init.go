@@ -7,8 +7,10 @@ import (
 
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
+	"github.com/stain-win/gaia/apps/gaia/config"
 	"github.com/stain-win/gaia/apps/gaia/daemon"
 	"github.com/stain-win/gaia/apps/gaia/encrypt"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
 )
 
 // initCmd is the Cobra command for `gaia init`.
@@ -18,6 +20,14 @@ var initCmd = &cobra.Command{
 	Long: `The init command guides you through the process of setting up Gaia's encrypted database and master passphrase.
 
 This is a one-time operation. Once the database is initialized, this command will not run again unless the database file is deleted.
+
+With --shares and --threshold, no passphrase is set. A random master key is split
+into key shares instead, and the daemon is unlocked with 'gaia unlock --share'
+once enough share holders have submitted theirs.
+
+With --import, the secrets of a JSON file in the 'gaia secrets import' format
+are stored right after the database is created, using the passphrase just
+entered, so a new deployment needs no separate start, unlock and import.
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := gaiaDaemon.GetConfig()
@@ -31,6 +41,26 @@ This is a one-time operation. Once the database is initialized, this command wil
 			os.Exit(1)
 		}
 
+		var seed map[string]map[string]map[string]string
+		if initImportFile != "" {
+			if initShares > 0 || initThreshold > 0 {
+				fmt.Println("--import cannot be combined with --shares.")
+				os.Exit(1)
+			}
+			// The file is read before the passphrase prompt, so a bad file
+			// fails before anything is created.
+			var err error
+			if seed, err = readSecretsFile(initImportFile, false, ""); err != nil {
+				fmt.Printf("Failed to read import file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if initShares > 0 || initThreshold > 0 {
+			initWithShares(cfg)
+			return
+		}
+
 		var passphrase string
 		var confirm bool
 
@@ -44,7 +74,9 @@ This is a one-time operation. Once the database is initialized, this command wil
 			huh.NewGroup(
 				huh.NewInput().
 					Title("Choose a master passphrase").
-					Description("This will be used to encrypt your data. Do not forget it!").
+					DescriptionFunc(func() string {
+						return "This will be used to encrypt your data. Do not forget it!\n" + strengthMeter(passphrase, showStrength)
+					}, &passphrase).
 					Value(&passphrase).
 					Password(true).
 					Validate(func(s string) error {
@@ -85,6 +117,11 @@ This is a one-time operation. Once the database is initialized, this command wil
 			os.Exit(0)
 		}
 
+		if showStrength {
+			entropy := encrypt.EstimateEntropy(passphrase)
+			fmt.Printf("Passphrase entropy: %.1f bits (%s)\n", entropy, encrypt.Strength(entropy))
+		}
+
 		// Initialize the database.
 		gaiaDaemon := daemon.NewDaemon(cfg)
 		err = gaiaDaemon.InitializeDB(passphrase)
@@ -95,10 +132,87 @@ This is a one-time operation. Once the database is initialized, this command wil
 
 		fmt.Println("\nGaia encrypted database initialized successfully!")
 		fmt.Printf("Your database file is located at: %s\n", cfg.DBFile)
+
+		if seed != nil {
+			imported, err := importAtInit(gaiaDaemon, passphrase, seed)
+			if err != nil {
+				fmt.Printf("Failed to import secrets: %v\n", err)
+				fmt.Println("The database was created; import the file with 'gaia secrets import' once the daemon is running.")
+				os.Exit(1)
+			}
+			fmt.Printf("✔ Imported %d secrets from %s\n", imported, initImportFile)
+		}
 	},
 }
 
+var (
+	showStrength   bool
+	initShares     int
+	initThreshold  int
+	initImportFile string
+)
+
+// importAtInit unlocks the freshly initialized database of d with passphrase,
+// imports secretsData and locks it again. The import fails on any conflict, as
+// the database is new.
+func importAtInit(d *daemon.Daemon, passphrase string, secretsData map[string]map[string]map[string]string) (int, error) {
+	if err := d.UnlockDB(passphrase); err != nil {
+		return 0, err
+	}
+	defer d.LockDB()
+
+	var items []*pb.ImportSecretItem
+	for clientName, namespaces := range secretsData {
+		for namespace, secrets := range namespaces {
+			for id, value := range secrets {
+				items = append(items, &pb.ImportSecretItem{ClientName: clientName, Namespace: namespace, Id: id, Value: value})
+			}
+		}
+	}
+	result, err := d.ImportSecrets(items, daemon.ImportFailOnConflict, "")
+	if err != nil {
+		return 0, err
+	}
+	return result.Imported, nil
+}
+
+// initWithShares initializes the database under a random master key split into
+// initShares key shares and prints them.
+func initWithShares(cfg *config.Config) {
+	gaiaDaemon := daemon.NewDaemon(cfg)
+	shares, err := gaiaDaemon.InitializeDBWithShares(initShares, initThreshold)
+	if err != nil {
+		fmt.Printf("\nFailed to initialize database: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Gaia encrypted database initialized successfully!")
+	fmt.Printf("Your database file is located at: %s\n", cfg.DBFile)
+	fmt.Printf("\nThe master key was split into %d shares, any %d of which unlock the daemon.\n", initShares, initThreshold)
+	fmt.Println("Hand each share to a different operator. They are shown only once:")
+	for i, share := range shares {
+		fmt.Printf("  Share %d: %s\n", i+1, share)
+	}
+}
+
+// strengthMeter renders a one-line strength indicator for the passphrase being typed.
+func strengthMeter(passphrase string, showBits bool) string {
+	if passphrase == "" {
+		return "Strength: -"
+	}
+	entropy := encrypt.EstimateEntropy(passphrase)
+	meter := "Strength: " + encrypt.Strength(entropy)
+	if showBits {
+		meter += fmt.Sprintf(" (%.1f bits, %.0f required)", entropy, encrypt.MinEntropy)
+	}
+	return meter
+}
+
 func init() {
 	rootCmd.AddCommand(initCmd)
 	initCmd.Flags().StringVarP(&dbFile, "db-file", "d", "", "The path to the BoltDB file")
+	initCmd.Flags().BoolVar(&showStrength, "show-strength", false, "Show the estimated passphrase entropy in bits")
+	initCmd.Flags().IntVar(&initShares, "shares", 0, "Split a random master key into this many key shares instead of setting a passphrase")
+	initCmd.Flags().IntVar(&initThreshold, "threshold", 0, "The number of key shares needed to unlock, with --shares")
+	initCmd.Flags().StringVar(&initImportFile, "import", "", "A JSON file of secrets to import right after initialization")
 }
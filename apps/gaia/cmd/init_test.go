@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/daemon"
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+)
+
+func TestImportAtInit(t *testing.T) {
+	gaialog.Init(gaialog.LevelError, "", false)
+	const passphrase = "correct-horse-battery-staple-42"
+	dir := t.TempDir()
+	seedFile := filepath.Join(dir, "seed.json")
+	seed := `{"billing": {"billing": {"api_key": "s3cret", "db_url": "postgres://db"}}}`
+	if err := os.WriteFile(seedFile, []byte(seed), 0600); err != nil {
+		t.Fatal(err)
+	}
+	secretsData, err := readSecretsFile(seedFile, false, "")
+	if err != nil {
+		t.Fatalf("readSecretsFile() error = %v", err)
+	}
+
+	// Unlocking loads the CA, which 'gaia certs generate' creates before init.
+	cfg := newConnTestConfig(t)
+	cfg.DBFile = filepath.Join(dir, "gaia.db")
+	d := daemon.NewDaemon(cfg)
+	if err := d.InitializeDB(passphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	imported, err := importAtInit(d, passphrase, secretsData)
+	if err != nil {
+		t.Fatalf("importAtInit() error = %v", err)
+	}
+	if imported != 2 {
+		t.Errorf("importAtInit() imported %d secrets, want 2", imported)
+	}
+	if locked, _, _ := d.LockState(); !locked {
+		t.Error("daemon is still unlocked after importAtInit()")
+	}
+
+	// The seeded secrets decrypt once the database is reopened.
+	reopened := daemon.NewDaemon(cfg)
+	if err := reopened.UnlockDB(passphrase); err != nil {
+		t.Fatalf("UnlockDB() error = %v", err)
+	}
+	defer reopened.LockDB()
+	for id, want := range map[string]string{"api_key": "s3cret", "db_url": "postgres://db"} {
+		if got, err := reopened.GetSecret("billing", "billing", id); err != nil || got != want {
+			t.Errorf("GetSecret(%q) = %q, %v, want %q", id, got, err, want)
+		}
+	}
+}
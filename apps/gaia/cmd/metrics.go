@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+)
+
+// metricsCmd represents the `metrics` command.
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Print the daemon's operational counters",
+	Long: `Fetches the daemon's counters over the admin gRPC channel and prints them in
+the Prometheus text format, one metric per line.
+
+No separate metrics port is needed: run the command from cron and write its
+output to a node_exporter textfile collector, or scrape it with any tool that
+can run a command. The counters are served while the daemon is locked.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := gaiaDaemon.GetConfig()
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.GRPCClientTimeout)
+		defer cancel()
+
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		res, err := pb.NewGaiaAdminClient(conn).GetMetrics(ctx, &pb.GetMetricsRequest{})
+		if err != nil {
+			return fmt.Errorf("gRPC GetMetrics failed: %w", err)
+		}
+		return writeMetrics(os.Stdout, res)
+	},
+}
+
+// writeMetrics writes m in the Prometheus text format.
+func writeMetrics(w io.Writer, m *pb.GetMetricsResponse) error {
+	locked := 0
+	if m.Locked {
+		locked = 1
+	}
+	for _, metric := range []struct {
+		name, kind, help string
+		value            uint64
+	}{
+		{"gaia_secrets_served_total", "counter", "Successful GetSecret calls.", m.SecretsServed},
+		{"gaia_access_denied_total", "counter", "GetSecret calls rejected by namespace authorization.", m.AccessDenied},
+		{"gaia_secrets_written_total", "counter", "Secrets stored by AddSecret, ImportSecrets and RotateSecrets.", m.SecretsWritten},
+		{"gaia_secrets_deleted_total", "counter", "Successful DeleteSecret calls.", m.SecretsDeleted},
+		{"gaia_unlocks_total", "counter", "Successful unlocks.", m.Unlocks},
+		{"gaia_failed_unlocks_total", "counter", "Unlock attempts that returned an error.", m.FailedUnlocks},
+		{"gaia_locked", "gauge", "Whether the daemon is locked.", uint64(locked)},
+	} {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", metric.name, metric.help, metric.name, metric.kind, metric.name, metric.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var moveOverwrite bool
+
+// namespacesCmd represents the base command for namespace management.
+var namespacesCmd = &cobra.Command{
+	Use:   "namespaces",
+	Short: "Manage namespaces across clients",
+	Long:  `Provides subcommands to reorganize the namespaces owned by Gaia clients to set their default TTL and to allow their rotation.`,
+}
+
+// moveNamespaceCmd represents the `namespaces move` subcommand.
+var moveNamespaceCmd = &cobra.Command{
+	Use:   "move [src-client] [dst-client] [namespace]",
+	Short: "Move a namespace and all of its secrets to another client",
+	Long: `Moves every secret of a namespace from one client to another in a single
+transaction. The namespace keeps its name.
+
+The move fails without changing anything if a secret with the same id already
+exists in the destination namespace. Use --overwrite to replace such secrets.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		c := pb.NewGaiaAdminClient(conn)
+		res, err := c.MoveNamespace(ctx, &pb.MoveNamespaceRequest{
+			SrcClient: args[0],
+			DstClient: args[1],
+			Namespace: args[2],
+			Overwrite: moveOverwrite,
+		})
+		if err != nil {
+			if status.Code(err) == codes.AlreadyExists {
+				return fmt.Errorf("%s; use --overwrite to replace existing secrets", status.Convert(err).Message())
+			}
+			return fmt.Errorf("gRPC MoveNamespace failed: %w", err)
+		}
+
+		fmt.Printf("✔ Moved %d secrets of namespace '%s' from '%s' to '%s'\n", res.MovedCount, args[2], args[0], args[1])
+		return nil
+	},
+}
+
+// namespaceTTLCmd represents the `namespaces ttl` subcommand.
+var namespaceTTLCmd = &cobra.Command{
+	Use:   "ttl",
+	Short: "Manage the default TTL of namespaces",
+	Long: `Provides subcommands to set and clear the default TTL of a namespace. Secrets
+added to a namespace with a default TTL, without a --ttl of their own, expire
+that long after they were written.`,
+}
+
+// setNamespaceTTLCmd represents the `namespaces ttl set` subcommand.
+var setNamespaceTTLCmd = &cobra.Command{
+	Use:   "set [client-name] [namespace] [ttl]",
+	Short: "Set the default TTL of a namespace",
+	Long: `Makes secrets added to a client's namespace without a TTL of their own expire
+after ttl, such as 1h or 30m. Secrets already in the namespace keep their
+expiry until they are written again.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ttl, err := time.ParseDuration(args[2])
+		if err != nil {
+			return fmt.Errorf("invalid TTL '%s': %w", args[2], err)
+		}
+		if ttl < time.Second {
+			return fmt.Errorf("invalid TTL '%s': must be at least 1s", args[2])
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		c := pb.NewGaiaAdminClient(conn)
+		_, err = c.SetNamespaceTTL(ctx, &pb.SetNamespaceTTLRequest{
+			ClientName: args[0],
+			Namespace:  args[1],
+			TtlSeconds: int64(ttl / time.Second),
+		})
+		if err != nil {
+			return fmt.Errorf("gRPC SetNamespaceTTL failed: %w", err)
+		}
+
+		fmt.Printf("✔ Secrets added to '%s/%s' expire after %s\n", args[0], args[1], ttl)
+		return nil
+	},
+}
+
+// clearNamespaceTTLCmd represents the `namespaces ttl clear` subcommand.
+var clearNamespaceTTLCmd = &cobra.Command{
+	Use:   "clear [client-name] [namespace]",
+	Short: "Remove the default TTL of a namespace",
+	Long: `Removes the default TTL of a client's namespace, so secrets added to it without
+a TTL of their own no longer expire. Secrets already in the namespace keep their
+expiry.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		c := pb.NewGaiaAdminClient(conn)
+		_, err = c.ClearNamespaceTTL(ctx, &pb.ClearNamespaceTTLRequest{ClientName: args[0], Namespace: args[1]})
+		if err != nil {
+			return fmt.Errorf("gRPC ClearNamespaceTTL failed: %w", err)
+		}
+
+		fmt.Printf("✔ Secrets added to '%s/%s' no longer expire by default\n", args[0], args[1])
+		return nil
+	},
+}
+
+// namespaceRotationCmd represents the `namespaces rotation` subcommand.
+var namespaceRotationCmd = &cobra.Command{
+	Use:   "rotation",
+	Short: "Manage which namespaces can be rotated",
+	Long: `Provides subcommands to enable and disable 'gaia secrets rotate' for a
+namespace. Rotation is disabled for every namespace until it is enabled.`,
+}
+
+// enableNamespaceRotationCmd represents the `namespaces rotation enable` subcommand.
+var enableNamespaceRotationCmd = &cobra.Command{
+	Use:   "enable [client-name] [namespace]",
+	Short: "Allow the secrets of a namespace to be rotated",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setNamespaceRotation(args[0], args[1], true); err != nil {
+			return err
+		}
+		fmt.Printf("✔ Secrets of '%s/%s' can be rotated\n", args[0], args[1])
+		return nil
+	},
+}
+
+// disableNamespaceRotationCmd represents the `namespaces rotation disable` subcommand.
+var disableNamespaceRotationCmd = &cobra.Command{
+	Use:   "disable [client-name] [namespace]",
+	Short: "Stop the secrets of a namespace from being rotated",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setNamespaceRotation(args[0], args[1], false); err != nil {
+			return err
+		}
+		fmt.Printf("✔ Secrets of '%s/%s' can no longer be rotated\n", args[0], args[1])
+		return nil
+	},
+}
+
+// setNamespaceRotation enables or disables rotation of a client's namespace.
+func setNamespaceRotation(clientName, namespace string, enabled bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cfg := gaiaDaemon.GetConfig()
+	conn, err := getClientConn(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("could not connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	c := pb.NewGaiaAdminClient(conn)
+	_, err = c.SetNamespaceRotation(ctx, &pb.SetNamespaceRotationRequest{ClientName: clientName, Namespace: namespace, Enabled: enabled})
+	if err != nil {
+		return fmt.Errorf("gRPC SetNamespaceRotation failed: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	namespacesCmd.AddCommand(moveNamespaceCmd)
+	namespacesCmd.AddCommand(namespaceTTLCmd)
+	namespaceTTLCmd.AddCommand(setNamespaceTTLCmd)
+	namespaceTTLCmd.AddCommand(clearNamespaceTTLCmd)
+	namespacesCmd.AddCommand(namespaceRotationCmd)
+	namespaceRotationCmd.AddCommand(enableNamespaceRotationCmd)
+	namespaceRotationCmd.AddCommand(disableNamespaceRotationCmd)
+
+	moveNamespaceCmd.Flags().BoolVar(&moveOverwrite, "overwrite", false, "Replace secrets that already exist at the destination")
+}
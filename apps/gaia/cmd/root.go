@@ -1,15 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/stain-win/gaia/apps/gaia/config"
 	"github.com/stain-win/gaia/apps/gaia/daemon"
 	"github.com/stain-win/gaia/apps/gaia/gaialog"
-	"github.com/stain-win/gaia/apps/gaia/tui"
 )
 
 // gaiaDaemon is the single, global daemon instance.
@@ -31,7 +30,7 @@ credentials to web applications running on the same server.`,
 		}
 
 		// Initialize the logger
-		gaialog.Init(gaialog.LevelInfo, "gaia_audit.log", true)
+		gaialog.Init(gaialog.LevelInfo, auditLogFile, true)
 		gaiaDaemon = daemon.NewDaemon(cfg)
 
 		return nil
@@ -40,14 +39,10 @@ credentials to web applications running on the same server.`,
 		// Default behavior: launch the interactive TUI if no command is given.
 		if len(args) == 0 {
 			cfg := gaiaDaemon.GetConfig()
-			err := tui.Run(cfg)
-			if err != nil {
-				if strings.Contains(err.Error(), "open /dev/tty") {
-					fmt.Println("Error: Could not open a new TTY. Please run Gaia in a real terminal (not in an IDE or redirected environment).\nDetails:", err)
-				} else {
-					fmt.Println("TUI exited with error:", err)
-				}
-			}
+			// The TUI still runs when the config location cannot be determined; it
+			// just does not pick up changes.
+			configPath, _ := config.ResolvePath(cfgFile)
+			runTUI(cfg, configPath)
 		} else {
 			err := cmd.Help()
 			if err != nil {
@@ -61,6 +56,10 @@ credentials to web applications running on the same server.`,
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
+		var connErr *connError
+		if errors.As(err, &connErr) {
+			fmt.Println(connErr.hint())
+		}
 		os.Exit(1)
 	}
 }
@@ -71,12 +70,18 @@ func init() {
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(restartCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(metricsCmd)
 	rootCmd.AddCommand(certsCmd)
 	rootCmd.AddCommand(clientsCmd)
 	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(namespacesCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(lockCmd)
 	rootCmd.AddCommand(unlockCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(tuiCmd)
 
 	// Cobra automatically adds the -v / --version flag to the rootCmd
 	// if we set the Version field. This provides a convenient shortcut.
@@ -1,18 +1,65 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
 	"time"
+	"unicode"
 
 	"github.com/spf13/cobra"
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+	"github.com/stain-win/gaia/apps/gaia/formats"
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
 )
 
 var (
-	overwrite bool
+	overwrite        bool
+	merge            bool
+	importBatchID    string
+	importSealed     bool
+	importKeyFile    string
+	exportSealed     bool
+	exportFormat     string
+	exportNamespace  string
+	k8sSecretName    string
+	k8sNamespace     string
+	exportClient     string
+	recipientKeyFile string
+	rotateLength     int
+	rotateCharset    string
+	rotateOutput     string
+	searchClient     string
+	searchNamespace  string
+	searchID         string
+	searchField      string
+	searchValues     bool
+	treeAll          bool
+	treeDepth        int
+	renameOverwrite  bool
+	addFormat        string
+	addValueFile     string
+	addTags          []string
+	addEncryptFields []string
+	addTTL           time.Duration
+	deleteTag        string
+	deleteYes        bool
+	templateFile     string
+	templateOut      string
 )
 
 // secretsCmd represents the base command for secret management.
@@ -48,20 +95,23 @@ Example JSON structure:
 
 The import is additive. By default, it will fail if any secret in the file
 already exists in the database. Use the --overwrite flag to update existing
-secrets with the values from the file.`,
+secrets with the values from the file, or --merge to only add the secrets that
+are missing and leave existing ones untouched, e.g. when seeding defaults.
+
+Pass --batch-id to make the import safe to retry: the daemon remembers batch IDs
+for seven days and does not apply a batch it has already completed again.
+
+Bundles produced by 'gaia secrets export --sealed' are imported with
+--sealed --key <private-key.pem>; the bundle is decrypted locally before
+any secret is sent to the daemon.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		filePath := args[0]
-
-		file, err := os.Open(filePath)
-		if err != nil {
-			return fmt.Errorf("failed to open file: %w", err)
+		if overwrite && merge {
+			return fmt.Errorf("--overwrite and --merge cannot be combined")
 		}
-		defer file.Close()
-
-		var secretsData map[string]map[string]map[string]string
-		if err := json.NewDecoder(file).Decode(&secretsData); err != nil {
-			return fmt.Errorf("failed to parse JSON file: %w", err)
+		secretsData, err := readSecretsFile(args[0], importSealed, importKeyFile)
+		if err != nil {
+			return err
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second) // Longer timeout for potentially large files
@@ -75,58 +125,972 @@ secrets with the values from the file.`,
 
 		client := pb.NewGaiaAdminClient(conn)
 
-		stream, err := client.ImportSecrets(ctx)
+		stream, err := client.ImportSecretsWithProgress(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to open import stream: %w", err)
 		}
 
-		configReq := &pb.ImportSecretsRequest{
-			Payload: &pb.ImportSecretsRequest_Config{
-				Config: &pb.ImportSecretsConfig{
-					Overwrite: overwrite,
-				},
-			},
-		}
-		if err := stream.Send(configReq); err != nil {
-			return fmt.Errorf("failed to send import config: %w", err)
+		total := 0
+		for _, namespaces := range secretsData {
+			for _, secrets := range namespaces {
+				total += len(secrets)
+			}
 		}
+		fmt.Printf("Starting import of %d secrets...\n", total)
 
-		fmt.Println("Starting secret import...")
-
-		for clientName, namespaces := range secretsData {
-			for namespace, secrets := range namespaces {
-				for id, value := range secrets {
-					itemReq := &pb.ImportSecretsRequest{
-						Payload: &pb.ImportSecretsRequest_Item{
-							Item: &pb.ImportSecretItem{
-								ClientName: clientName,
-								Namespace:  namespace,
-								Id:         id,
-								Value:      value,
+		// Items are sent while progress is received, so the counter moves as the
+		// daemon takes them in. A failed send ends the stream; its error is
+		// reported by Recv.
+		go func() {
+			defer stream.CloseSend()
+			configReq := &pb.ImportSecretsRequest{
+				Payload: &pb.ImportSecretsRequest_Config{
+					Config: &pb.ImportSecretsConfig{
+						Overwrite: overwrite,
+						Merge:     merge,
+						BatchId:   importBatchID,
+					},
+				},
+			}
+			if err := stream.Send(configReq); err != nil {
+				return
+			}
+			for clientName, namespaces := range secretsData {
+				for namespace, secrets := range namespaces {
+					for id, value := range secrets {
+						itemReq := &pb.ImportSecretsRequest{
+							Payload: &pb.ImportSecretsRequest_Item{
+								Item: &pb.ImportSecretItem{
+									ClientName: clientName,
+									Namespace:  namespace,
+									Id:         id,
+									Value:      value,
+								},
 							},
-						},
-					}
-					if err := stream.Send(itemReq); err != nil {
-						return fmt.Errorf("failed to send secret on stream: %w", err)
+						}
+						if err := stream.Send(itemReq); err != nil {
+							return
+						}
 					}
 				}
 			}
-		}
-		reply, err := stream.CloseAndRecv()
-		if err != nil {
-			return fmt.Errorf("import failed: %w", err)
+		}()
+
+		var reply *pb.ImportSecretsResponse
+		progressShown := false
+		for reply == nil {
+			msg, err := stream.Recv()
+			if err != nil {
+				if progressShown {
+					fmt.Println()
+				}
+				return fmt.Errorf("import failed: %w", err)
+			}
+			switch p := msg.Payload.(type) {
+			case *pb.ImportSecretsProgress_ItemsReceived:
+				fmt.Printf("\r  Received %d of %d secrets", p.ItemsReceived, total)
+				progressShown = true
+			case *pb.ImportSecretsProgress_Result:
+				reply = p.Result
+			}
 		}
 
-		fmt.Printf("\n✔ Import successful.\n")
+		if reply.Replayed {
+			fmt.Printf("\n✔ Batch '%s' was already imported, nothing changed.\n", importBatchID)
+		} else {
+			fmt.Printf("\n✔ Import successful.\n")
+		}
 		fmt.Printf("  Secrets Imported: %d\n", reply.SecretsImported)
+		if merge {
+			fmt.Printf("  Secrets Skipped: %d\n", reply.SecretsSkipped)
+		}
 		fmt.Printf("  Message: %s\n", reply.Message)
+		for _, v := range reply.Violations {
+			fmt.Printf("  Warning: %s/%s/%s matches value rule '%s'\n", v.ClientName, v.Namespace, v.Id, v.Rule)
+		}
+
+		return nil
+	},
+}
+
+// exportCmd represents the `secrets export` subcommand.
+var exportCmd = &cobra.Command{
+	Use:   "export [output-file]",
+	Short: "Export secrets to a JSON, YAML, dotenv or Kubernetes Secret file",
+	Long: `Exports decrypted secrets from Gaia in the same nested JSON structure that
+'gaia secrets import' accepts. If no output file is given, the export is
+written to standard output.
+
+Use --format yaml for the same nested structure as YAML, or --format env for
+GAIA_NAMESPACE_KEY=value lines, e.g. for a .env file. The env format needs a
+single client, so combine it with --client when more than one is registered.
+
+Use --format k8s --client <client> --namespace <ns> --name <secret> to render
+one namespace as a Kubernetes v1 Secret manifest, with each secret id as a
+base64-encoded data entry. Set the Kubernetes namespace with --k8s-namespace.
+The manifest is printed or written to the output file; apply it with
+'kubectl apply -f -' so values never appear on a command line.
+
+Use --sealed --recipient-key <public-key.pem> to encrypt the whole bundle to an
+RSA public key (RSA-OAEP with an AES-256-GCM payload), producing a file that is
+safe to commit for GitOps workflows. Sealing only protects the bundle in
+transit; secrets stored by the daemon remain encrypted with the master key.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportSealed && recipientKeyFile == "" {
+			return fmt.Errorf("--sealed requires --recipient-key")
+		}
+		if exportSealed && exportFormat != "json" {
+			return fmt.Errorf("--sealed only supports the json format")
+		}
+		if exportFormat == "k8s" && (exportClient == "" || exportNamespace == "" || k8sSecretName == "") {
+			return fmt.Errorf("--format k8s requires --client, --namespace and --name")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		res, err := client.ExportSecrets(ctx, &pb.ExportSecretsRequest{ClientName: exportClient})
+		if err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
+
+		var data []byte
+		if exportFormat == "k8s" {
+			secrets, ok := itemsToNested(res.Items)[exportClient][exportNamespace]
+			if !ok {
+				return fmt.Errorf("client '%s' has no secrets in namespace '%s'", exportClient, exportNamespace)
+			}
+			data, err = encodeK8sSecret(secrets, k8sSecretName, k8sNamespace)
+		} else {
+			data, err = encodeExport(itemsToNested(res.Items), exportFormat, exportSealed, recipientKeyFile)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := writePrivateFile(args[0], data); err != nil {
+			return fmt.Errorf("failed to write export file: %w", err)
+		}
+		fmt.Printf("✔ Exported %d secrets to %s\n", len(res.Items), args[0])
+		return nil
+	},
+}
+
+// rotateCmd represents the `secrets rotate` subcommand.
+var rotateCmd = &cobra.Command{
+	Use:   "rotate [client-name] [namespace]",
+	Short: "Regenerate every secret in a namespace",
+	Long: `Replaces the value of every secret in the given namespace with a new random
+value and prints the old and new values as JSON, so downstream systems can be
+updated. All secrets in the namespace are rotated in a single transaction.
+
+Rotation always targets exactly one namespace; namespaces that are not named
+on the command line are never touched. It must first be enabled for the
+namespace with 'gaia namespaces rotation enable', and fails if a new value does
+not pass the format constraint of its secret or the value rules. Use --output to write the mapping to a
+file (created with 0600 permissions) instead of standard output.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		res, err := client.RotateSecrets(ctx, &pb.RotateSecretsRequest{
+			ClientName: args[0],
+			Namespace:  args[1],
+			Length:     int32(rotateLength),
+			Charset:    rotateCharset,
+		})
+		if err != nil {
+			return fmt.Errorf("rotation failed: %w", err)
+		}
+
+		data, err := encodeRotation(res.Secrets)
+		if err != nil {
+			return err
+		}
+
+		if rotateOutput == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := writePrivateFile(rotateOutput, data); err != nil {
+			return fmt.Errorf("failed to write rotation file: %w", err)
+		}
+		fmt.Printf("✔ Rotated %d secrets in '%s/%s', mapping written to %s\n", len(res.Secrets), args[0], args[1], rotateOutput)
+		return nil
+	},
+}
+
+// usageCmd represents the `secrets usage` subcommand.
+var usageCmd = &cobra.Command{
+	Use:   "usage [client-name]",
+	Short: "Show how often and how recently each secret of a client was read",
+	Long: `Lists every secret of a client with its read count and last read time,
+least recently read first, so unused secrets can be found and pruned.
+
+Reads are only recorded while 'track_secret_access' is enabled in the daemon
+configuration.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		res, err := client.GetSecretUsage(ctx, &pb.GetSecretUsageRequest{ClientName: args[0]})
+		if err != nil {
+			return fmt.Errorf("gRPC GetSecretUsage failed: %w", err)
+		}
+
+		if !res.TrackingEnabled {
+			fmt.Println("Note: secret access tracking is disabled; counts may be stale.")
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAMESPACE\tID\tREADS\tLAST READ")
+		for _, u := range res.Secrets {
+			last := u.LastAccessed
+			if last == "" {
+				last = "never"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", u.Namespace, u.Id, u.AccessCount, last)
+		}
+		return w.Flush()
+	},
+}
+
+// addCmd represents the `secrets add` subcommand.
+var addCmd = &cobra.Command{
+	Use:   "add [client-name] [namespace] [id]",
+	Short: "Add or update a single secret",
+	Long: `Stores a secret, replacing its value if it already exists. The value is
+prompted for without echo, or read from --value-file ("-" reads standard input).
+
+--format sets a format constraint on the secret: the value must parse as the
+format, and so must every later value written to the secret, by this command,
+the TUI or an import. Built-in formats are url, json, pem and base64; pass
+--format none to remove the constraint.
+
+--tag key=value tags the secret and can be repeated. The tags replace those the
+secret had; without --tag they are kept. Tagged secrets can be deleted together
+with 'gaia secrets delete --tag'.
+
+--encrypt-field path stores a JSON object value with only the field at path
+encrypted, e.g. --encrypt-field database.password, and can be repeated. The
+other fields stay searchable with 'gaia secrets search --field'. Later writes
+keep the same fields encrypted.
+
+--ttl makes the secret expire that long after this write, e.g. --ttl 1h.
+Without it the secret gets the default TTL of the namespace, if one is set with
+'gaia namespaces ttl set', and never expires otherwise.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, err := parseTags(addTags)
+		if err != nil {
+			return err
+		}
+		if addTTL != 0 && addTTL < time.Second {
+			return fmt.Errorf("invalid --ttl %s: must be at least 1s", addTTL)
+		}
+		value, err := readSecretValue(addValueFile)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		res, err := client.AddSecret(ctx, &pb.AddSecretRequest{
+			ClientName:    args[0],
+			Namespace:     args[1],
+			Id:            args[2],
+			Value:         value,
+			Format:        addFormat,
+			Tags:          tags,
+			EncryptFields: addEncryptFields,
+			TtlSeconds:    int64(addTTL / time.Second),
+		})
+		if err != nil {
+			return fmt.Errorf("gRPC AddSecret failed: %w", err)
+		}
+		if !res.Success {
+			return fmt.Errorf("failed to add secret: %s", res.Message)
+		}
+
+		fmt.Printf("✔ Stored '%s' in '%s/%s'\n", args[2], args[0], args[1])
+		for _, w := range res.Warnings {
+			fmt.Printf("  Warning: %s\n", w)
+		}
+		return nil
+	},
+}
+
+// parseTags parses key=value tags. Values may be empty, keys may not.
+func parseTags(tags []string) (map[string]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		k, v, ok := strings.Cut(tag, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid tag '%s', want key=value", tag)
+		}
+		parsed[k] = v
+	}
+	return parsed, nil
+}
+
+// confirm asks prompt on out and reports whether the answer read from in is
+// yes. Anything else, including an empty answer, declines.
+func confirm(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// deleteCmd represents the `secrets delete` subcommand.
+var deleteCmd = &cobra.Command{
+	Use:   "delete [client-name]",
+	Short: "Delete every secret of a client carrying a tag",
+	Long: `Deletes every secret of the client tagged with --tag key=value, in all of its
+namespaces, in a single transaction. Tags are set with 'gaia secrets add --tag'.
+
+You are asked to confirm the deletion unless --yes is given. When the daemon
+requires re-authentication for destructive operations, you will be prompted
+for the master passphrase.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagKey, tagValue, ok := strings.Cut(deleteTag, "=")
+		if !ok || tagKey == "" {
+			return fmt.Errorf("invalid tag '%s', want key=value", deleteTag)
+		}
+
+		if !deleteYes {
+			ok, err := confirm(os.Stdin, os.Stdout, fmt.Sprintf("Delete every secret of '%s' tagged %s?", args[0], deleteTag))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Deletion not confirmed. Aborting.")
+				return nil
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		req := &pb.DeleteSecretsByTagRequest{ClientName: args[0], TagKey: tagKey, TagValue: tagValue}
+		res, err := client.DeleteSecretsByTag(ctx, req)
+		if status.Code(err) == codes.Unauthenticated {
+			// The daemon requires the passphrase to confirm the deletion.
+			if req.Passphrase, err = readPassphrase("Enter master passphrase to confirm: "); err != nil {
+				return err
+			}
+			res, err = client.DeleteSecretsByTag(ctx, req)
+		}
+		if err != nil {
+			return fmt.Errorf("gRPC DeleteSecretsByTag failed: %w", err)
+		}
 
+		fmt.Printf("✔ Deleted %d secrets of '%s' tagged %s\n", res.Deleted, args[0], deleteTag)
 		return nil
 	},
 }
 
+// readSecretValue reads a secret value from path, or from standard input when
+// path is "-". Without a path, the value is prompted for without echo.
+func readSecretValue(path string) (string, error) {
+	switch path {
+	case "":
+		return readPassphrase("Enter secret value: ")
+	case "-":
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read value file: %w", err)
+	}
+	return string(data), nil
+}
+
+// templateCmd represents the `secrets template` subcommand.
+var templateCmd = &cobra.Command{
+	Use:   "template [client-name] [namespace]",
+	Short: "Render a config file from secrets with a Go template",
+	Long: `Renders the Go text/template in --file with the secrets of a client as its
+data and writes the result to --out with 0600 permissions, or to standard
+output without --out.
+
+With a namespace, the template sees the secrets of that namespace by id, e.g.
+{{ .db_password }}; ids that are not valid identifiers are read with
+{{ index . "db-password" }}. Without one, it sees every namespace of the
+client, e.g. {{ .database.password }}. A template that references a secret
+that does not exist fails, and no file is written.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tmpl, err := os.ReadFile(templateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		res, err := client.ExportSecrets(ctx, &pb.ExportSecretsRequest{ClientName: args[0]})
+		if err != nil {
+			return fmt.Errorf("gRPC ExportSecrets failed: %w", err)
+		}
+
+		var data any = itemsToNested(res.Items)[args[0]]
+		if len(args) == 2 {
+			secrets, ok := itemsToNested(res.Items)[args[0]][args[1]]
+			if !ok {
+				return fmt.Errorf("client '%s' has no secrets in namespace '%s'", args[0], args[1])
+			}
+			data = secrets
+		}
+		out, err := renderSecretsTemplate(templateFile, string(tmpl), data)
+		if err != nil {
+			return err
+		}
+
+		if templateOut == "" {
+			_, err = os.Stdout.Write(out)
+			return err
+		}
+		if err := writePrivateFile(templateOut, out); err != nil {
+			return fmt.Errorf("failed to write rendered file: %w", err)
+		}
+		fmt.Printf("✔ Rendered %s to %s\n", templateFile, templateOut)
+		return nil
+	},
+}
+
+// renderSecretsTemplate executes the template text with secrets as its data.
+// Referencing a missing secret is an error rather than rendering "<no value>".
+func renderSecretsTemplate(name, text string, secrets any) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, secrets); err != nil {
+		return nil, fmt.Errorf("failed to render template, is a secret missing? %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writePrivateFile writes data to path with 0600 permissions, also when the file
+// already exists with wider ones.
+func writePrivateFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0600)
+}
+
+// renameCmd represents the `secrets rename` subcommand.
+var renameCmd = &cobra.Command{
+	Use:   "rename [client-name] [namespace] [old-id] [new-id]",
+	Short: "Change the id of a single secret",
+	Long: `Renames a secret within its namespace, for instance to fix a typo in its id.
+The value and the recorded reads move with it in a single transaction, so
+nothing is lost as it would be with a delete and add.
+
+The rename fails if a secret with the new id already exists. Use --overwrite
+to replace it.`,
+	Args: cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		_, err = client.RenameSecret(ctx, &pb.RenameSecretRequest{
+			ClientName: args[0],
+			Namespace:  args[1],
+			OldId:      args[2],
+			NewId:      args[3],
+			Overwrite:  renameOverwrite,
+		})
+		if err != nil {
+			if status.Code(err) == codes.AlreadyExists {
+				return fmt.Errorf("%s; use --overwrite to replace it", status.Convert(err).Message())
+			}
+			return fmt.Errorf("gRPC RenameSecret failed: %w", err)
+		}
+
+		fmt.Printf("✔ Renamed '%s' to '%s' in '%s/%s'\n", args[2], args[3], args[0], args[1])
+		return nil
+	},
+}
+
+// searchCmd represents the `secrets search` subcommand.
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Find secrets across all clients",
+	Long: `Searches the secrets of every client and prints the client, namespace and id
+of each match. Filter by client and namespace with --client and --namespace,
+and by a part of the secret id with --id, e.g. 'gaia secrets search --id api_key'.
+--field path=value finds structured secrets, stored with 'gaia secrets add
+--encrypt-field', whose unencrypted field at path has that value.
+
+Values are masked unless --show-values is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var field, fieldValue string
+		if searchField != "" {
+			var ok bool
+			if field, fieldValue, ok = strings.Cut(searchField, "="); !ok || field == "" {
+				return fmt.Errorf("invalid field '%s', want path=value", searchField)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		req := &pb.SearchSecretsRequest{
+			ClientName:    searchClient,
+			Namespace:     searchNamespace,
+			IdContains:    searchID,
+			Field:         field,
+			FieldValue:    fieldValue,
+			IncludeValues: searchValues,
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CLIENT\tNAMESPACE\tID\tVALUE")
+		found := 0
+		for {
+			res, err := client.SearchSecrets(ctx, req)
+			if err != nil {
+				return fmt.Errorf("gRPC SearchSecrets failed: %w", err)
+			}
+			for _, m := range res.Matches {
+				value := "********"
+				if searchValues {
+					value = m.Value
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.ClientName, m.Namespace, m.Id, value)
+			}
+			found += len(res.Matches)
+			if res.NextPageToken == "" {
+				break
+			}
+			req.PageToken = res.NextPageToken
+		}
+		if found == 0 {
+			fmt.Println("No matching secrets found.")
+			return nil
+		}
+		return w.Flush()
+	},
+}
+
+// Levels of the tree printed by `secrets tree`, selected with --depth.
+const (
+	treeDepthClients    = 1
+	treeDepthNamespaces = 2
+	treeDepthSecrets    = 3
+)
+
+// treeCmd represents the `secrets tree` subcommand.
+var treeCmd = &cobra.Command{
+	Use:   "tree [client-name]",
+	Short: "Print clients, namespaces and secret ids as a tree",
+	Long: `Prints the namespaces and secret ids of a client, or of every registered client
+with --all, as a tree with the number of namespaces and secrets at each level.
+Values are always masked. This is the scriptable counterpart of the TUI inspector.
+
+Use --depth 1 to list only clients and --depth 2 to stop at namespaces.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if treeAll == (len(args) == 1) {
+			return fmt.Errorf("specify either a client name or --all")
+		}
+		if treeDepth < treeDepthClients || treeDepth > treeDepthSecrets {
+			return fmt.Errorf("--depth must be between %d and %d", treeDepthClients, treeDepthSecrets)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		cfg := gaiaDaemon.GetConfig()
+		conn, err := getClientConn(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("could not connect to daemon: %w", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewGaiaAdminClient(conn)
+		names := args
+		if treeAll {
+			res, err := client.ListClients(ctx, &pb.ListClientsRequest{})
+			if err != nil {
+				return fmt.Errorf("gRPC ListClients failed: %w", err)
+			}
+			names = nil
+			for _, c := range res.Clients {
+				names = append(names, c.Name)
+			}
+		}
+
+		var clients []clientSecrets
+		for _, name := range names {
+			res, err := client.ListSecrets(ctx, &pb.ListSecretsRequest{ClientName: name})
+			if err != nil {
+				return fmt.Errorf("gRPC ListSecrets for client '%s' failed: %w", name, err)
+			}
+			clients = append(clients, clientSecrets{Name: name, Namespaces: res.Namespaces})
+		}
+		return renderSecretTree(os.Stdout, clients, treeDepth)
+	},
+}
+
+// clientSecrets holds the namespaces returned by ListSecrets for one client.
+type clientSecrets struct {
+	Name       string
+	Namespaces []*pb.Namespace
+}
+
+// renderSecretTree writes clients as a tree of client, namespace and secret id
+// down to depth, followed by the totals. Namespaces and ids are sorted and
+// values are never printed.
+func renderSecretTree(w io.Writer, clients []clientSecrets, depth int) error {
+	clients = slices.Clone(clients)
+	slices.SortFunc(clients, func(a, b clientSecrets) int { return strings.Compare(a.Name, b.Name) })
+
+	var b strings.Builder
+	totalNamespaces, totalSecrets := 0, 0
+	for _, c := range clients {
+		namespaces := slices.Clone(c.Namespaces)
+		slices.SortFunc(namespaces, func(a, b *pb.Namespace) int { return strings.Compare(a.Name, b.Name) })
+		secretCount := 0
+		for _, ns := range namespaces {
+			secretCount += len(ns.Secrets)
+		}
+		totalNamespaces += len(namespaces)
+		totalSecrets += secretCount
+
+		fmt.Fprintf(&b, "%s (%s, %s)\n", c.Name, plural(len(namespaces), "namespace"), plural(secretCount, "secret"))
+		if depth < treeDepthNamespaces {
+			continue
+		}
+		for i, ns := range namespaces {
+			branch, indent := "├── ", "│   "
+			if i == len(namespaces)-1 {
+				branch, indent = "└── ", "    "
+			}
+			fmt.Fprintf(&b, "%s%s (%s)\n", branch, ns.Name, plural(len(ns.Secrets), "secret"))
+			if depth < treeDepthSecrets {
+				continue
+			}
+			ids := make([]string, 0, len(ns.Secrets))
+			for _, s := range ns.Secrets {
+				ids = append(ids, s.Id)
+			}
+			slices.Sort(ids)
+			for j, id := range ids {
+				leaf := "├── "
+				if j == len(ids)-1 {
+					leaf = "└── "
+				}
+				fmt.Fprintf(&b, "%s%s%s = ********\n", indent, leaf, id)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\n%s, %s, %s\n", plural(len(clients), "client"), plural(totalNamespaces, "namespace"), plural(totalSecrets, "secret"))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// plural formats n with noun, adding an "s" unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// rotatedValue is the JSON form of a single rotated secret.
+type rotatedValue struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// encodeRotation serializes rotated secrets as a JSON object keyed by secret id.
+func encodeRotation(secrets []*pb.RotatedSecret) ([]byte, error) {
+	mapping := make(map[string]rotatedValue, len(secrets))
+	for _, s := range secrets {
+		mapping[s.Id] = rotatedValue{Old: s.OldValue, New: s.NewValue}
+	}
+	data, err := json.MarshalIndent(mapping, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rotation: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// itemsToNested groups flat secret items into the client/namespace/id structure
+// used by the import and export file format.
+func itemsToNested(items []*pb.ImportSecretItem) map[string]map[string]map[string]string {
+	nested := make(map[string]map[string]map[string]string)
+	for _, item := range items {
+		if _, ok := nested[item.ClientName]; !ok {
+			nested[item.ClientName] = make(map[string]map[string]string)
+		}
+		if _, ok := nested[item.ClientName][item.Namespace]; !ok {
+			nested[item.ClientName][item.Namespace] = make(map[string]string)
+		}
+		nested[item.ClientName][item.Namespace][item.Id] = item.Value
+	}
+	return nested
+}
+
+// encodeExport serializes exported secrets in format (json, yaml or env),
+// optionally sealing the result to the RSA public key stored at recipientKeyPath.
+// Map keys are emitted in sorted order, so the same data always produces the same
+// plaintext document.
+func encodeExport(data map[string]map[string]map[string]string, format string, sealed bool, recipientKeyPath string) ([]byte, error) {
+	var plaintext []byte
+	var err error
+	switch format {
+	case "json":
+		plaintext, err = json.MarshalIndent(data, "", "  ")
+		plaintext = append(plaintext, '\n')
+	case "yaml":
+		plaintext, err = yaml.Marshal(data)
+	case "env":
+		plaintext, err = encodeEnv(data)
+	default:
+		return nil, fmt.Errorf("unsupported export format '%s', use json, yaml or env", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export: %w", err)
+	}
+	if !sealed {
+		return plaintext, nil
+	}
+
+	pub, err := encrypt.LoadRSAPublicKey(recipientKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := encrypt.Seal(pub, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal export: %w", err)
+	}
+	return append(bundle, '\n'), nil
+}
+
+// encodeEnv renders the secrets of a single client as GAIA_NAMESPACE_KEY=value
+// lines, named like the variables set by the client library's LoadEnv. Values that
+// are not plain words are double-quoted with Go escaping.
+func encodeEnv(data map[string]map[string]map[string]string) ([]byte, error) {
+	if len(data) > 1 {
+		return nil, fmt.Errorf("the env format needs a single client, got %d; use --client", len(data))
+	}
+
+	var buf bytes.Buffer
+	for _, namespaces := range data {
+		for _, ns := range slices.Sorted(maps.Keys(namespaces)) {
+			for _, id := range slices.Sorted(maps.Keys(namespaces[ns])) {
+				name := strings.ToUpper(fmt.Sprintf("GAIA_%s_%s", ns, id))
+				name = strings.ReplaceAll(name, "-", "_")
+				value := namespaces[ns][id]
+				if value == "" || strings.ContainsFunc(value, func(r rune) bool {
+					return !(r == '_' || r == '-' || r == '.' || r == '/' || r == ':' ||
+						unicode.IsLetter(r) || unicode.IsDigit(r))
+				}) {
+					value = strconv.Quote(value)
+				}
+				fmt.Fprintf(&buf, "%s=%s\n", name, value)
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// k8sSecret is the subset of a Kubernetes v1 Secret written by encodeK8sSecret.
+type k8sSecret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   k8sMetadata       `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	Data       map[string]string `yaml:"data"`
+}
+
+type k8sMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// encodeK8sSecret renders the secrets of one namespace as an Opaque Kubernetes
+// Secret manifest, keyed by secret id with base64-encoded values.
+func encodeK8sSecret(secrets map[string]string, name, namespace string) ([]byte, error) {
+	manifest := k8sSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   k8sMetadata{Name: name, Namespace: namespace},
+		Type:       "Opaque",
+		Data:       make(map[string]string, len(secrets)),
+	}
+	for id, value := range secrets {
+		manifest.Data[id] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Kubernetes Secret: %w", err)
+	}
+	return data, nil
+}
+
+// readSecretsFile reads an import file, unsealing it with the RSA private key at
+// keyPath first when sealed is set.
+func readSecretsFile(path string, sealed bool, keyPath string) (map[string]map[string]map[string]string, error) {
+	if sealed && keyPath == "" {
+		return nil, fmt.Errorf("--sealed requires --key")
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	if sealed {
+		priv, err := encrypt.LoadRSAPrivateKey(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		raw, err = encrypt.Unseal(priv, raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unseal import file: %w", err)
+		}
+	}
+
+	var secretsData map[string]map[string]map[string]string
+	if err := json.Unmarshal(raw, &secretsData); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON file: %w", err)
+	}
+	return secretsData, nil
+}
+
 func init() {
 	secretsCmd.AddCommand(importCmd)
+	secretsCmd.AddCommand(exportCmd)
+	secretsCmd.AddCommand(rotateCmd)
+	secretsCmd.AddCommand(usageCmd)
+	secretsCmd.AddCommand(searchCmd)
+	secretsCmd.AddCommand(treeCmd)
+	secretsCmd.AddCommand(renameCmd)
+	secretsCmd.AddCommand(addCmd)
+	secretsCmd.AddCommand(deleteCmd)
+	secretsCmd.AddCommand(templateCmd)
 
 	importCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Overwrite existing secrets with values from the file")
+	importCmd.Flags().BoolVar(&merge, "merge", false, "Only add secrets that do not exist yet, leaving existing ones untouched")
+	importCmd.Flags().StringVar(&importBatchID, "batch-id", "", "Identifier that makes retrying the same import a no-op")
+	importCmd.Flags().BoolVar(&importSealed, "sealed", false, "Treat the file as a sealed bundle produced by 'secrets export --sealed'")
+	importCmd.Flags().StringVar(&importKeyFile, "key", "", "RSA private key (PEM) used to unseal a sealed bundle")
+
+	exportCmd.Flags().StringVar(&exportClient, "client", "", "Only export secrets belonging to this client")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "json", "Output format: json, yaml, env or k8s")
+	exportCmd.Flags().StringVar(&exportNamespace, "namespace", "", "Namespace to render as a Kubernetes Secret (k8s format)")
+	exportCmd.Flags().StringVar(&k8sSecretName, "name", "", "Name of the Kubernetes Secret (k8s format)")
+	exportCmd.Flags().StringVar(&k8sNamespace, "k8s-namespace", "", "Kubernetes namespace of the Secret (k8s format)")
+	exportCmd.Flags().BoolVar(&exportSealed, "sealed", false, "Encrypt the exported bundle to a recipient public key")
+	exportCmd.Flags().StringVar(&recipientKeyFile, "recipient-key", "", "RSA public key or certificate (PEM) to seal the bundle to")
+
+	searchCmd.Flags().StringVar(&searchClient, "client", "", "Only search the secrets of this client")
+	searchCmd.Flags().StringVar(&searchNamespace, "namespace", "", "Only search this namespace")
+	searchCmd.Flags().StringVar(&searchID, "id", "", "Only show secrets whose id contains this text")
+	searchCmd.Flags().StringVar(&searchField, "field", "", "Only show structured secrets whose unencrypted field path=value")
+	searchCmd.Flags().BoolVar(&searchValues, "show-values", false, "Print decrypted values instead of masking them")
+
+	treeCmd.Flags().BoolVar(&treeAll, "all", false, "Print the secrets of every registered client")
+	treeCmd.Flags().IntVar(&treeDepth, "depth", treeDepthSecrets, "Levels to print: 1 clients, 2 namespaces, 3 secret ids")
+
+	renameCmd.Flags().BoolVar(&renameOverwrite, "overwrite", false, "Replace an existing secret with the new id")
+
+	addCmd.Flags().StringVar(&addFormat, "format", "", "Format the value must match: "+strings.Join(formats.Names(), ", ")+", or none to remove it")
+	addCmd.Flags().StringVar(&addValueFile, "value-file", "", "Read the value from this file, or - for standard input")
+	addCmd.Flags().StringArrayVar(&addTags, "tag", nil, "Tag the secret with key=value; can be repeated")
+	addCmd.Flags().StringArrayVar(&addEncryptFields, "encrypt-field", nil, "Encrypt only this field of a JSON object value, e.g. database.password; can be repeated")
+	addCmd.Flags().DurationVar(&addTTL, "ttl", 0, "Expire the secret this long after the write, e.g. 1h; defaults to the namespace TTL")
+
+	deleteCmd.Flags().StringVar(&deleteTag, "tag", "", "Delete the secrets tagged key=value")
+	deleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Delete without asking for confirmation")
+	deleteCmd.MarkFlagRequired("tag")
+
+	templateCmd.Flags().StringVar(&templateFile, "file", "", "Go text/template to render")
+	templateCmd.Flags().StringVar(&templateOut, "out", "", "Write the rendered file here (0600) instead of standard output")
+	templateCmd.MarkFlagRequired("file")
+
+	rotateCmd.Flags().IntVar(&rotateLength, "length", 32, "Length of each generated value")
+	rotateCmd.Flags().StringVar(&rotateCharset, "charset", encrypt.DefaultCharset, "Characters to draw generated values from")
+	rotateCmd.Flags().StringVarP(&rotateOutput, "output", "o", "", "Write the old/new mapping to this file instead of standard output")
 }
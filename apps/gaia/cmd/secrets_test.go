@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"gopkg.in/yaml.v3"
+)
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestSealedExportImportRoundtrip(t *testing.T) {
+	dir := t.TempDir()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	pubPath := filepath.Join(dir, "pub.pem")
+	privPath := filepath.Join(dir, "priv.pem")
+	writePEM(t, pubPath, "PUBLIC KEY", pubDER)
+	writePEM(t, privPath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(priv))
+
+	exported := itemsToNested([]*pb.ImportSecretItem{
+		{ClientName: "app-a", Namespace: "app-a", Id: "db_url", Value: "postgres://a"},
+		{ClientName: "app-a", Namespace: "app-a", Id: "api_key", Value: "k1"},
+		{ClientName: "common", Namespace: "shared", Id: "region", Value: "eu-west-1"},
+	})
+
+	data, err := encodeExport(exported, "json", true, pubPath)
+	if err != nil {
+		t.Fatalf("encodeExport() error = %v", err)
+	}
+	bundlePath := filepath.Join(dir, "bundle.sealed.json")
+	if err := os.WriteFile(bundlePath, data, 0600); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+
+	if _, err := readSecretsFile(bundlePath, false, ""); err == nil {
+		t.Error("readSecretsFile() without --sealed should not parse a sealed bundle")
+	}
+
+	imported, err := readSecretsFile(bundlePath, true, privPath)
+	if err != nil {
+		t.Fatalf("readSecretsFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(imported, exported) {
+		t.Errorf("readSecretsFile() = %v, want %v", imported, exported)
+	}
+}
+
+func TestEncodeExportIsDeterministic(t *testing.T) {
+	data := map[string]map[string]map[string]string{
+		"b": {"ns": {"z": "1", "a": "2"}},
+		"a": {"ns": {"k": "v"}},
+	}
+	first, err := encodeExport(data, "json", false, "")
+	if err != nil {
+		t.Fatalf("encodeExport() error = %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		again, _ := encodeExport(data, "json", false, "")
+		if string(again) != string(first) {
+			t.Fatalf("encodeExport() output differs between runs:\n%s\n%s", first, again)
+		}
+	}
+}
+
+func TestEncodeExportFormats(t *testing.T) {
+	data := itemsToNested([]*pb.ImportSecretItem{
+		{ClientName: "app-a", Namespace: "app-a", Id: "db_url", Value: "postgres://a"},
+		{ClientName: "app-a", Namespace: "app-a", Id: "api-key", Value: "k1"},
+		{ClientName: "app-a", Namespace: "shared", Id: "motd", Value: "hello \"world\"\n"},
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		out, err := encodeExport(data, "yaml", false, "")
+		if err != nil {
+			t.Fatalf("encodeExport() error = %v", err)
+		}
+		var decoded map[string]map[string]map[string]string
+		if err := yaml.Unmarshal(out, &decoded); err != nil {
+			t.Fatalf("failed to parse YAML export: %v\n%s", err, out)
+		}
+		if !reflect.DeepEqual(decoded, data) {
+			t.Errorf("YAML export = %v, want %v", decoded, data)
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		out, err := encodeExport(data, "env", false, "")
+		if err != nil {
+			t.Fatalf("encodeExport() error = %v", err)
+		}
+		want := "GAIA_APP_A_API_KEY=k1\n" +
+			"GAIA_APP_A_DB_URL=postgres://a\n" +
+			"GAIA_SHARED_MOTD=\"hello \\\"world\\\"\\n\"\n"
+		if string(out) != want {
+			t.Errorf("env export =\n%s\nwant\n%s", out, want)
+		}
+	})
+
+	t.Run("env needs a single client", func(t *testing.T) {
+		multi := map[string]map[string]map[string]string{
+			"app-a": {"app-a": {"k": "v"}},
+			"app-b": {"app-b": {"k": "v"}},
+		}
+		if _, err := encodeExport(multi, "env", false, ""); err == nil {
+			t.Error("encodeExport(env) with two clients succeeded, want an error")
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if _, err := encodeExport(data, "toml", false, ""); err == nil {
+			t.Error("encodeExport(toml) succeeded, want an error")
+		}
+	})
+}
+
+func TestEncodeK8sSecret(t *testing.T) {
+	secrets := map[string]string{
+		"db_url":  "postgres://user:pa$$@db/app",
+		"tls_key": "-----BEGIN KEY-----\nabc\n-----END KEY-----\n",
+	}
+	out, err := encodeK8sSecret(secrets, "app-secrets", "payments")
+	if err != nil {
+		t.Fatalf("encodeK8sSecret() error = %v", err)
+	}
+
+	var manifest k8sSecret
+	if err := yaml.Unmarshal(out, &manifest); err != nil {
+		t.Fatalf("failed to parse manifest: %v\n%s", err, out)
+	}
+	if manifest.APIVersion != "v1" || manifest.Kind != "Secret" || manifest.Type != "Opaque" {
+		t.Errorf("manifest header = %s/%s/%s, want v1/Secret/Opaque", manifest.APIVersion, manifest.Kind, manifest.Type)
+	}
+	if manifest.Metadata.Name != "app-secrets" || manifest.Metadata.Namespace != "payments" {
+		t.Errorf("metadata = %+v", manifest.Metadata)
+	}
+	if len(manifest.Data) != len(secrets) {
+		t.Fatalf("manifest has %d data entries, want %d", len(manifest.Data), len(secrets))
+	}
+	for id, want := range secrets {
+		got, err := base64.StdEncoding.DecodeString(manifest.Data[id])
+		if err != nil {
+			t.Fatalf("data[%s] is not base64: %v", id, err)
+		}
+		if string(got) != want {
+			t.Errorf("data[%s] decodes to %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestRenderSecretTree(t *testing.T) {
+	clients := []clientSecrets{
+		{Name: "billing", Namespaces: []*pb.Namespace{
+			{Name: "billing", Secrets: []*pb.Secret{{Id: "stripe_key", Value: "sk_live"}, {Id: "db_url", Value: "postgres://b"}}},
+			{Name: "common", Secrets: []*pb.Secret{{Id: "region", Value: "eu-west-1"}}},
+		}},
+		{Name: "api", Namespaces: []*pb.Namespace{
+			{Name: "api", Secrets: []*pb.Secret{{Id: "jwt_secret", Value: "hunter2"}}},
+		}},
+		{Name: "idle"},
+	}
+
+	var out strings.Builder
+	if err := renderSecretTree(&out, clients, treeDepthSecrets); err != nil {
+		t.Fatalf("renderSecretTree() error = %v", err)
+	}
+	want := `api (1 namespace, 1 secret)
+└── api (1 secret)
+    └── jwt_secret = ********
+billing (2 namespaces, 3 secrets)
+├── billing (2 secrets)
+│   ├── db_url = ********
+│   └── stripe_key = ********
+└── common (1 secret)
+    └── region = ********
+idle (0 namespaces, 0 secrets)
+
+3 clients, 3 namespaces, 4 secrets
+`
+	if out.String() != want {
+		t.Errorf("renderSecretTree() =\n%s\nwant\n%s", out.String(), want)
+	}
+	for _, value := range []string{"sk_live", "postgres://b", "eu-west-1", "hunter2"} {
+		if strings.Contains(out.String(), value) {
+			t.Errorf("tree contains the secret value %q", value)
+		}
+	}
+
+	out.Reset()
+	if err := renderSecretTree(&out, clients, treeDepthNamespaces); err != nil {
+		t.Fatalf("renderSecretTree() error = %v", err)
+	}
+	want = `api (1 namespace, 1 secret)
+└── api (1 secret)
+billing (2 namespaces, 3 secrets)
+├── billing (2 secrets)
+└── common (1 secret)
+idle (0 namespaces, 0 secrets)
+
+3 clients, 3 namespaces, 4 secrets
+`
+	if out.String() != want {
+		t.Errorf("renderSecretTree(depth 2) =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRenderSecretsTemplate(t *testing.T) {
+	secrets := map[string]string{"db_user": "app", "db-password": "s3cret", "unused": "x"}
+	tmpl := "user = {{ .db_user }}\npassword = {{ index . \"db-password\" }}\n"
+
+	out, err := renderSecretsTemplate("app.conf.tpl", tmpl, secrets)
+	if err != nil {
+		t.Fatalf("renderSecretsTemplate() error = %v", err)
+	}
+	if want := "user = app\npassword = s3cret\n"; string(out) != want {
+		t.Errorf("renderSecretsTemplate() = %q, want %q", out, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.conf")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePrivateFile(path, out); err != nil {
+		t.Fatalf("writePrivateFile() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat rendered file: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("rendered file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	_, err = renderSecretsTemplate("app.conf.tpl", "{{ .db_host }}", secrets)
+	if err == nil || !strings.Contains(err.Error(), "db_host") {
+		t.Errorf("renderSecretsTemplate() with a missing secret error = %v, want one naming db_host", err)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tags, err := parseTags([]string{"env=prod", "team=", "url=a=b"})
+	if err != nil {
+		t.Fatalf("parseTags() error = %v", err)
+	}
+	if want := map[string]string{"env": "prod", "team": "", "url": "a=b"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("parseTags() = %v, want %v", tags, want)
+	}
+	for _, bad := range []string{"env", "=prod"} {
+		if _, err := parseTags([]string{bad}); err == nil {
+			t.Errorf("parseTags(%q) error = nil, want one", bad)
+		}
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	for answer, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false} {
+		var out strings.Builder
+		got, err := confirm(strings.NewReader(answer), &out, "Delete?")
+		if err != nil || got != want {
+			t.Errorf("confirm(%q) = %v, %v; want %v", answer, got, err, want)
+		}
+		if out.String() != "Delete? [y/N]: " {
+			t.Errorf("confirm() prompt = %q", out.String())
+		}
+	}
+}
@@ -1,16 +1,75 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/stain-win/gaia/apps/gaia/config"
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
 	"golang.org/x/term"
 )
 
+// passphraseCommandTimeout bounds how long a passphrase command may run, which
+// includes waiting for the user at a prompt such as a GPG pinentry.
+const passphraseCommandTimeout = time.Minute
+
+// readPassphrase prompts for a passphrase on the terminal without echoing it.
+func readPassphrase(prompt string) (string, error) {
+	fmt.Print(prompt)
+	passphrase, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println() // Newline after password input
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
+
+// runPassphraseCommand runs command through the system shell and returns its
+// standard output, without the trailing newline, as the passphrase. Standard
+// error is passed through so the command can prompt the user. The output is
+// never included in errors.
+func runPassphraseCommand(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, passphraseCommandTimeout)
+	defer cancel()
+
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	}
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("passphrase command failed: %w", err)
+	}
+
+	passphrase := bytes.TrimSuffix(stdout.Bytes(), []byte("\n"))
+	passphrase = bytes.TrimSuffix(passphrase, []byte("\r"))
+	if len(passphrase) == 0 {
+		return "", errors.New("passphrase command printed nothing")
+	}
+	return string(passphrase), nil
+}
+
+// masterPassphrase returns the master passphrase from cfg.PassphraseCommand when
+// it is set, and prompts for it with prompt otherwise.
+func masterPassphrase(ctx context.Context, cfg *config.Config, prompt string) (string, error) {
+	if cfg.PassphraseCommand != "" {
+		return runPassphraseCommand(ctx, cfg.PassphraseCommand)
+	}
+	return readPassphrase(prompt)
+}
+
 // lockCmd represents the `lock` command.
 var lockCmd = &cobra.Command{
 	Use:   "lock",
@@ -49,19 +108,25 @@ var unlockCmd = &cobra.Command{
 	Long: `Sends the master passphrase to the running Gaia daemon to unlock its storage.
 
 The daemon must be unlocked before it can serve secrets to clients. You will be
-prompted to enter the master passphrase securely.`,
+prompted to enter the master passphrase securely, unless 'passphrase_command' is
+set in the configuration, in which case its output is used instead.
+
+For a database initialized with key shares, each share holder runs
+'gaia unlock --share' and enters their share. The daemon keeps the submitted
+shares in memory and unlocks once enough of them are collected.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Print("Enter master passphrase: ")
-		passphrase, err := term.ReadPassword(int(syscall.Stdin))
+		cfg := gaiaDaemon.GetConfig()
+		if unlockShare {
+			return submitShare(cfg)
+		}
+		passphrase, err := masterPassphrase(context.Background(), cfg, "Enter master passphrase: ")
 		if err != nil {
-			return fmt.Errorf("failed to read passphrase: %w", err)
+			return err
 		}
-		fmt.Println() // Newline after password input
 
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		cfg := gaiaDaemon.GetConfig()
 		conn, err := getClientConn(ctx, cfg)
 		if err != nil {
 			return fmt.Errorf("could not connect to daemon: %w", err)
@@ -69,12 +134,54 @@ prompted to enter the master passphrase securely.`,
 		defer conn.Close()
 
 		client := pb.NewGaiaAdminClient(conn)
-		_, err = client.Unlock(ctx, &pb.UnlockRequest{Passphrase: string(passphrase)})
+		res, err := client.Unlock(ctx, &pb.UnlockRequest{Passphrase: passphrase})
 		if err != nil {
 			return fmt.Errorf("gRPC Unlock failed: %w", err)
 		}
+		if res.AlreadyUnlocked {
+			fmt.Println("Daemon is already unlocked.")
+			return nil
+		}
 
 		fmt.Println("Daemon unlocked successfully.")
 		return nil
 	},
 }
+
+var unlockShare bool
+
+// submitShare prompts for a key share and submits it to the daemon.
+func submitShare(cfg *config.Config) error {
+	share, err := readPassphrase("Enter key share: ")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := getClientConn(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("could not connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	client := pb.NewGaiaAdminClient(conn)
+	res, err := client.Unlock(ctx, &pb.UnlockRequest{Share: share})
+	if err != nil {
+		return fmt.Errorf("gRPC Unlock failed: %w", err)
+	}
+	switch {
+	case res.AlreadyUnlocked:
+		fmt.Println("Daemon is already unlocked.")
+	case res.Success:
+		fmt.Println("Daemon unlocked successfully.")
+	default:
+		fmt.Printf("Share accepted, %d of %d shares collected.\n", res.SharesCollected, res.SharesRequired)
+	}
+	return nil
+}
+
+func init() {
+	unlockCmd.Flags().BoolVar(&unlockShare, "share", false, "Submit a key share instead of the master passphrase")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/certs"
+	"github.com/stain-win/gaia/apps/gaia/config"
+	"github.com/stain-win/gaia/apps/gaia/daemon"
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+)
+
+func TestRunPassphraseCommand_UnlocksDaemon(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub command is a shell script")
+	}
+	gaialog.Init(gaialog.LevelError, "", false)
+	const passphrase = "correct horse battery staple"
+	dir := t.TempDir()
+
+	cfg := config.NewDefaultConfig()
+	cfg.DBFile = filepath.Join(dir, "gaia.db")
+	cfg.CertsDirectory = filepath.Join(dir, "certs")
+	if err := certs.GenerateCA(cfg, "Gaia Test CA"); err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	d := daemon.NewDaemon(cfg)
+	if err := d.InitializeDB(passphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+
+	script := filepath.Join(dir, "get-passphrase.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho 'Fetching passphrase' >&2\nprintf '%s\\n' '"+passphrase+"'\n"), 0700); err != nil {
+		t.Fatalf("failed to write stub command: %v", err)
+	}
+	cfg.PassphraseCommand = script
+
+	got, err := masterPassphrase(context.Background(), cfg, "")
+	if err != nil {
+		t.Fatalf("masterPassphrase() error = %v", err)
+	}
+	if err := d.UnlockDB(got); err != nil {
+		t.Fatalf("UnlockDB() with the command's output error = %v", err)
+	}
+	d.LockDB()
+
+	if _, err := runPassphraseCommand(context.Background(), "exit 3"); err == nil {
+		t.Error("runPassphraseCommand() with a failing command succeeded")
+	}
+	if _, err := runPassphraseCommand(context.Background(), "true"); err == nil {
+		t.Error("runPassphraseCommand() with no output succeeded")
+	}
+}
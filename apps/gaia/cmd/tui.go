@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stain-win/gaia/apps/gaia/config"
+	"github.com/stain-win/gaia/apps/gaia/tui"
+)
+
+var (
+	tuiServer   string
+	tuiCertsDir string
+)
+
+// tuiCmd represents the `tui` command.
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Open the interactive TUI, optionally against a remote daemon",
+	Long: `Opens the interactive terminal UI, as running 'gaia' without a command does.
+
+--server and --certs override the daemon address and certificates directory of
+the configuration for this session, so a remote daemon can be inspected ad hoc.
+The daemon certificate must be valid for the host given in --server, and the
+certificates directory must hold the CA and admin client certificate for that
+daemon. Changes to the configuration file are not picked up while either flag
+is given, so the override stays in effect.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := tuiTarget(gaiaDaemon.GetConfig(), tuiServer, tuiCertsDir)
+		if err != nil {
+			return err
+		}
+		configPath := ""
+		if tuiServer == "" && tuiCertsDir == "" {
+			configPath, _ = config.ResolvePath(cfgFile)
+		}
+		runTUI(cfg, configPath)
+		return nil
+	},
+}
+
+// tuiTarget returns a copy of cfg that connects to server, given as host:port,
+// with the certificates in certsDir. Empty arguments keep the configured values.
+func tuiTarget(cfg *config.Config, server, certsDir string) (*config.Config, error) {
+	target := *cfg
+	if server != "" {
+		host, port, err := net.SplitHostPort(server)
+		if err != nil || host == "" || port == "" {
+			return nil, fmt.Errorf("invalid --server %q, want host:port", server)
+		}
+		target.GRPCServerName, target.GRPCPort = host, port
+	}
+	if certsDir != "" {
+		target.CertsDirectory = certsDir
+	}
+	return &target, nil
+}
+
+// runTUI runs the TUI and reports why it exited with an error. configPath is
+// watched for changes when it is not empty.
+func runTUI(cfg *config.Config, configPath string) {
+	err := tui.Run(cfg, configPath)
+	if err != nil {
+		if strings.Contains(err.Error(), "open /dev/tty") {
+			fmt.Println("Error: Could not open a new TTY. Please run Gaia in a real terminal (not in an IDE or redirected environment).\nDetails:", err)
+		} else {
+			fmt.Println("TUI exited with error:", err)
+		}
+	}
+}
+
+func init() {
+	tuiCmd.Flags().StringVar(&tuiServer, "server", "", "Daemon address as host:port, overriding grpc_server_name and grpc_port")
+	tuiCmd.Flags().StringVar(&tuiCertsDir, "certs", "", "Directory with the CA and admin client certificate, overriding certs_directory")
+}
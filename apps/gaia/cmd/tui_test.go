@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+func TestTUITarget(t *testing.T) {
+	cfg := &config.Config{GRPCServerName: "localhost", GRPCPort: "50051", CertsDirectory: "/etc/gaia/certs"}
+
+	tests := []struct {
+		name, server, certs string
+		wantHost, wantPort  string
+		wantCerts           string
+		wantErr             bool
+	}{
+		{name: "no flags", wantHost: "localhost", wantPort: "50051", wantCerts: "/etc/gaia/certs"},
+		{name: "server", server: "gaia.internal:6000", wantHost: "gaia.internal", wantPort: "6000", wantCerts: "/etc/gaia/certs"},
+		{name: "ipv6 server", server: "[::1]:6000", wantHost: "::1", wantPort: "6000", wantCerts: "/etc/gaia/certs"},
+		{name: "certs", certs: "/tmp/remote", wantHost: "localhost", wantPort: "50051", wantCerts: "/tmp/remote"},
+		{name: "missing port", server: "gaia.internal", wantErr: true},
+		{name: "missing host", server: ":6000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tuiTarget(cfg, tt.server, tt.certs)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("tuiTarget(%q) succeeded, want an error", tt.server)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tuiTarget() error = %v", err)
+			}
+			if got.GRPCServerName != tt.wantHost || got.GRPCPort != tt.wantPort || got.CertsDirectory != tt.wantCerts {
+				t.Errorf("tuiTarget() = %s:%s certs %s, want %s:%s certs %s",
+					got.GRPCServerName, got.GRPCPort, got.CertsDirectory, tt.wantHost, tt.wantPort, tt.wantCerts)
+			}
+		})
+	}
+
+	if cfg.GRPCServerName != "localhost" || cfg.GRPCPort != "50051" || cfg.CertsDirectory != "/etc/gaia/certs" {
+		t.Errorf("tuiTarget() modified the configuration: %+v", cfg)
+	}
+}
@@ -12,61 +12,213 @@ import (
 
 // Config holds all configurable settings for Gaia.
 type Config struct {
-	GRPCServerName      string        `yaml:"grpc_server_name"`
-	GRPCPort            string        `yaml:"grpc_port"`
-	DBFile              string        `yaml:"db_file"`
-	CertsDirectory      string        `yaml:"certs_directory"`
-	CACertFile          string        `yaml:"ca_cert_file"`
+	GRPCServerName string `yaml:"grpc_server_name"`
+	GRPCPort       string `yaml:"grpc_port"`
+	DBFile         string `yaml:"db_file"`
+	CertsDirectory string `yaml:"certs_directory"`
+	CACertFile     string `yaml:"ca_cert_file"`
+	// PreviousCACertFile is the CA replaced by 'gaia certs rotate-ca'. While it
+	// exists, client certificates issued by it are still accepted.
+	PreviousCACertFile  string        `yaml:"previous_ca_cert_file"`
 	ServerCertFile      string        `yaml:"server_cert_file"`
 	ServerKeyFile       string        `yaml:"server_key_file"`
 	GaiaClientCertFile  string        `yaml:"gaia_client_cert_file"`
-	GaianClientKeyFile  string        `yaml:"gaia_client_key_file"`
+	GaiaClientKeyFile   string        `yaml:"gaia_client_key_file"`
 	GRPCClientTimeout   time.Duration `yaml:"grpc_client_timeout"`
 	GaiaTuiTickInterval time.Duration `yaml:"gaia_tui_tick_interval"`
 	CertExpiryDays      int           `yaml:"cert_expiry_days"`
+	AgentSocket         string        `yaml:"agent_socket"`
+	// EnableCommonNamespace controls the shared "common" area. When false, no
+	// "common" client is registered at init and "common" is treated like any
+	// other namespace during authorization.
+	EnableCommonNamespace bool `yaml:"enable_common_namespace"`
+	// CommonName is the name of the shared area, used both as its namespace and
+	// as the reserved client its secrets are stored under. No real client may be
+	// registered with it. Set it before 'gaia init'; secrets stored under a
+	// previous name are no longer served after a change.
+	CommonName string `yaml:"common_name"`
+	// EnforceClientNamespace rejects admin writes that the owning client could never
+	// read. When false, such writes succeed but are reported with a warning.
+	EnforceClientNamespace bool `yaml:"enforce_client_namespace"`
+	// HideUnauthorizedAsNotFound answers client reads of a namespace the client
+	// is not authorized for as if the secret did not exist, so clients cannot
+	// probe which namespaces exist. Admin RPCs are not affected.
+	HideUnauthorizedAsNotFound bool `yaml:"hide_unauthorized_as_not_found"`
+	// IdentitySource selects the client certificate field a client is identified
+	// by: "cn" (the default), "uri-san", "email-san" or "ou". See
+	// daemon.ClientIdentity for how each field maps to a client name.
+	IdentitySource string `yaml:"identity_source"`
+	// IdentityTrustDomain is the scheme and host of the URI SANs that identify
+	// clients with the "uri-san" identity source, such as spiffe://example.org.
+	// It is required for that source.
+	IdentityTrustDomain string `yaml:"identity_trust_domain"`
+	// NamespaceGrants lists, per client, additional namespaces of its own that the
+	// client may read besides the one named after it.
+	NamespaceGrants map[string][]string `yaml:"namespace_grants"`
+	// OTLPEndpoint is the host:port of an OTLP/gRPC collector. When set, every
+	// secret-access RPC is exported as a trace span. Tracing is off when empty.
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	// OTLPInsecure disables TLS for the connection to the OTLP collector.
+	OTLPInsecure bool `yaml:"otlp_insecure"`
+	// RequireUnlockToServe makes 'gaia start' prompt for the passphrase and unlock
+	// the database before the listener is opened. Once serving, only Unlock and
+	// GetStatus are answered while the daemon is locked.
+	RequireUnlockToServe bool `yaml:"require_unlock_to_serve"`
+	// EnableReflection registers the gRPC server reflection service so tools such
+	// as grpcurl can list and call the daemon's RPCs. Keep it off in production.
+	EnableReflection bool `yaml:"enable_reflection"`
+	// ShutdownGracePeriod bounds how long a stop waits for in-flight RPCs and open
+	// streams before the remaining connections are closed forcibly. Zero waits
+	// indefinitely.
+	ShutdownGracePeriod time.Duration `yaml:"shutdown_grace_period"`
+	// Compression set to "gzip" compresses the responses of bulk RPCs, such as
+	// listing and exporting secrets, for clients that accept gzip. Empty or
+	// "none" sends every response uncompressed.
+	Compression string `yaml:"compression"`
+	// ClientCertClockSkew accepts client certificates that expired or become
+	// valid at most this long ago or ahead, for hosts with slightly unsynced
+	// clocks. Zero applies the validity window strictly.
+	ClientCertClockSkew time.Duration `yaml:"client_cert_clock_skew"`
+	// ExpirySweepInterval is how often an unlocked daemon deletes the secrets
+	// that have expired. Expired secrets are neither served nor listed, but stay
+	// in the database file until swept. Zero disables the sweeper.
+	ExpirySweepInterval time.Duration `yaml:"expiry_sweep_interval"`
+	// KeyCheckInterval is how often an unlocked daemon re-reads the key hash from
+	// the database and locks itself if it no longer matches the loaded key, as
+	// when the database file was replaced. Zero disables the check.
+	KeyCheckInterval time.Duration `yaml:"key_check_interval"`
+	// BackupDirectory and BackupInterval enable scheduled backups: every interval
+	// an unlocked daemon writes a consistent snapshot of the database to a
+	// timestamped file in the directory. Backups are off unless both are set.
+	BackupDirectory string        `yaml:"backup_directory"`
+	BackupInterval  time.Duration `yaml:"backup_interval"`
+	// BackupRetention is the number of scheduled backups kept; older ones are
+	// removed after each backup. Zero keeps all of them.
+	BackupRetention int `yaml:"backup_retention"`
+	// TrackSecretAccess records a read counter and last-read time per secret.
+	// Reads are buffered in memory and written to the database periodically.
+	TrackSecretAccess bool `yaml:"track_secret_access"`
+	// AccessLog writes one log entry per RPC with the method, client Common Name,
+	// status code and duration.
+	AccessLog bool `yaml:"access_log"`
+	// AccessLogSampleRate logs only 1 in N read RPCs. Mutating and failed RPCs
+	// are always logged. Values of 0 and 1 log every RPC.
+	AccessLogSampleRate int `yaml:"access_log_sample_rate"`
+	// AccessLogWritesOnly skips successful read RPCs entirely.
+	AccessLogWritesOnly bool `yaml:"access_log_writes_only"`
+	// EncryptAuditLog encrypts every audit log line with the master key while the
+	// daemon is unlocked. Lines written while it is locked stay in plaintext.
+	// 'gaia audit decrypt' reads the log back with the master passphrase.
+	EncryptAuditLog bool `yaml:"encrypt_audit_log"`
+	// LockKeyMemory keeps the derived master key in locked memory so it is never
+	// swapped to disk. Locking needs privileges (CAP_IPC_LOCK or a sufficient
+	// RLIMIT_MEMLOCK); when it is not permitted the key is kept in ordinary memory.
+	LockKeyMemory bool `yaml:"lock_key_memory"`
+	// RequireReauthForDestructive makes RevokeClient and DeleteSecret require the
+	// master passphrase again, even while the daemon is unlocked.
+	RequireReauthForDestructive bool `yaml:"require_reauth_for_destructive"`
+	// PassphraseCheckCacheTTL is how long a successful passphrase verification is
+	// remembered, so repeated checks of the same passphrase skip the key
+	// derivation. Only a keyed hash of the passphrase is kept, and it is dropped
+	// on lock. Zero disables the cache.
+	PassphraseCheckCacheTTL time.Duration `yaml:"passphrase_check_cache_ttl"`
+	// RepairOnUnlock recreates missing database buckets after a successful unlock.
+	// Keep it off in normal operation so missing buckets are noticed.
+	RepairOnUnlock bool `yaml:"repair_on_unlock"`
+	// DBOpenAttempts is how often unlocking tries to open the database file while
+	// another process holds its lock, waiting a second each time with growing
+	// pauses in between. Values below 1 try once.
+	DBOpenAttempts int `yaml:"db_open_attempts"`
+	// DBNoSync skips the fsync after every write transaction, which makes bulk
+	// imports much faster. A crash or power loss can then lose recent writes or
+	// corrupt the database; only use it for loads that can be repeated from a
+	// backup.
+	DBNoSync bool `yaml:"db_no_sync"`
+	// DBNoFreelistSync does not write the freelist to disk on every commit. Writes
+	// get faster, but opening the database after an unclean shutdown rebuilds
+	// the freelist by scanning the whole file.
+	DBNoFreelistSync bool `yaml:"db_no_freelist_sync"`
+	// DBFreelistType is "array", the default, or "map", which is faster for large
+	// databases with a fragmented freelist.
+	DBFreelistType string `yaml:"db_freelist_type"`
+	// DBInitialMmapSize is the initial size in bytes of the database memory map.
+	// A size above the expected file size avoids remapping, which blocks readers,
+	// while the database grows. Zero maps the file as it is.
+	DBInitialMmapSize int `yaml:"db_initial_mmap_size"`
+	// MaxNamespacesPerClient caps the namespaces a client can have secrets in,
+	// so a buggy or malicious client cannot bloat the database. Zero is unlimited.
+	MaxNamespacesPerClient int `yaml:"max_namespaces_per_client"`
+	// MaxSecretsPerNamespace caps the secrets of one namespace of a client. Zero is
+	// unlimited. Updates of existing secrets are allowed at either limit.
+	MaxSecretsPerNamespace int `yaml:"max_secrets_per_namespace"`
+	// ValueRules are checked against every secret value written by AddSecret,
+	// ImportSecrets and SetSecrets, to catch values that were obviously pasted
+	// into the wrong place. No rules are checked by default.
+	ValueRules []ValueRule `yaml:"value_rules"`
+	// CiphertextEncoding is how newly written secrets are stored: "base64" text or
+	// "raw" binary, which is a third smaller. Secrets in either encoding are read,
+	// so the setting can be changed at any time; existing values are converted
+	// when they are next written.
+	CiphertextEncoding string `yaml:"ciphertext_encoding"`
+	// PassphraseCommand is run by 'gaia unlock' instead of prompting; its standard
+	// output is used as the master passphrase.
+	PassphraseCommand string `yaml:"passphrase_command"`
+	// Banner is returned by GetStatus and shown by 'gaia status' and the TUI, to
+	// tell daemons apart or announce notices such as a maintenance window.
+	Banner string `yaml:"banner"`
+}
+
+// ValueRule flags secret values that match Pattern, a regular expression that
+// matches anywhere in the value unless it is anchored.
+type ValueRule struct {
+	// Name identifies the rule in errors and warnings; it defaults to Pattern.
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+	// Action is "reject" to fail the write or "warn" to write the value and
+	// report it.
+	Action string `yaml:"action"`
 }
 
 // NewDefaultConfig returns a Config with default values.
 func NewDefaultConfig() *Config {
 	return &Config{
-		GRPCServerName:      "localhost",
-		GRPCPort:            "50051",
-		DBFile:              "gaia.db",
-		CertsDirectory:      "./certs",
-		CACertFile:          "ca.crt",
-		ServerCertFile:      "server.crt",
-		ServerKeyFile:       "server.key",
-		GaiaClientCertFile:  "gaia_client.crt",
-		GaianClientKeyFile:  "gaia_client.key",
-		GRPCClientTimeout:   5 * time.Second,
-		GaiaTuiTickInterval: 2 * time.Second,
-		CertExpiryDays:      365, // Default to 365 days
+		GRPCServerName:          "localhost",
+		GRPCPort:                "50051",
+		DBFile:                  defaultDBFile(),
+		CertsDirectory:          "./certs",
+		CACertFile:              "ca.crt",
+		PreviousCACertFile:      "ca.previous.crt",
+		ServerCertFile:          "server.crt",
+		ServerKeyFile:           "server.key",
+		GaiaClientCertFile:      "gaia_client.crt",
+		GaiaClientKeyFile:       "gaia_client.key",
+		GRPCClientTimeout:       5 * time.Second,
+		GaiaTuiTickInterval:     2 * time.Second,
+		CertExpiryDays:          365, // Default to 365 days
+		ShutdownGracePeriod:     10 * time.Second,
+		DBOpenAttempts:          3,
+		BackupRetention:         7,
+		PassphraseCheckCacheTTL: 30 * time.Second,
+		ExpirySweepInterval:     time.Minute,
+		EnableCommonNamespace:   true,
+		CommonName:              "common",
+		CiphertextEncoding:      "base64",
 	}
 }
 
 // Load reads the configuration from the specified path or the default path if empty.
 func Load(path string) (*Config, error) {
-	cfg := NewDefaultConfig()
-
-	if path == "" {
-		var err error
-		path, err = getDefaultConfigPath()
-		if err != nil {
-			return nil, err // This would be an error like "home directory not found"
-		}
-	}
+	cfg, _, err := LoadWithProvenance(path)
+	return cfg, err
+}
 
-	if _, err := os.Stat(path); err == nil {
-		if err := loadConfigFromFile(path, cfg); err != nil {
-			return nil, err
-		}
-	} else if !os.IsNotExist(err) {
-		return nil, err
+// ResolvePath returns path, or the OS-specific default config file path when
+// path is empty.
+func ResolvePath(path string) (string, error) {
+	if path != "" {
+		return path, nil
 	}
-
-	loadConfigFromEnv(cfg)
-
-	return cfg, nil
+	return getDefaultConfigPath()
 }
 
 // getDefaultConfigPath returns the OS-specific path for the config file.
@@ -86,13 +238,29 @@ func getDefaultConfigPath() (string, error) {
 		}
 		path = filepath.Join(homeDir, "Library", "Application Support", "Gaia", "gaia-config.yaml")
 	case "linux":
-		path = filepath.Join("/etc", "gaia", "gaia-config.yaml")
+		// Honour XDG_CONFIG_HOME so unprivileged users can run Gaia without /etc access.
+		if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdgConfig) {
+			path = filepath.Join(xdgConfig, "gaia", "gaia-config.yaml")
+		} else {
+			path = filepath.Join("/etc", "gaia", "gaia-config.yaml")
+		}
 	default:
 		return "", fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
 	}
 	return path, nil
 }
 
+// defaultDBFile returns the default database location. On Linux it is placed under
+// XDG_DATA_HOME when that is set; otherwise it is relative to the working directory.
+func defaultDBFile() string {
+	if runtime.GOOS == "linux" {
+		if xdgData := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(xdgData) {
+			return filepath.Join(xdgData, "gaia", "gaia.db")
+		}
+	}
+	return "gaia.db"
+}
+
 // loadConfigFromFile reads the configuration from the specified file path and unmarshal it into the Config struct.
 func loadConfigFromFile(path string, cfg *Config) error {
 	data, err := os.ReadFile(path)
@@ -113,6 +281,12 @@ func loadConfigFromEnv(cfg *Config) {
 	if grpcPort := os.Getenv("GAIA_GRPC_PORT"); grpcPort != "" {
 		cfg.GRPCPort = grpcPort
 	}
+	if agentSocket := os.Getenv("GAIA_AGENT_SOCKET"); agentSocket != "" {
+		cfg.AgentSocket = agentSocket
+	}
+	if otlpEndpoint := os.Getenv("GAIA_OTLP_ENDPOINT"); otlpEndpoint != "" {
+		cfg.OTLPEndpoint = otlpEndpoint
+	}
 }
 
 // WriteConfigToFile writes the given config to the specified path.
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDefaultPaths_XDG(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG base directories only apply on Linux")
+	}
+
+	t.Run("Set", func(t *testing.T) {
+		configHome := t.TempDir()
+		dataHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+		t.Setenv("XDG_DATA_HOME", dataHome)
+
+		path, err := getDefaultConfigPath()
+		if err != nil {
+			t.Fatalf("getDefaultConfigPath() error = %v", err)
+		}
+		if want := filepath.Join(configHome, "gaia", "gaia-config.yaml"); path != want {
+			t.Errorf("getDefaultConfigPath() = %q, want %q", path, want)
+		}
+		if got, want := NewDefaultConfig().DBFile, filepath.Join(dataHome, "gaia", "gaia.db"); got != want {
+			t.Errorf("default DBFile = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Unset", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+		t.Setenv("XDG_DATA_HOME", "")
+
+		path, err := getDefaultConfigPath()
+		if err != nil {
+			t.Fatalf("getDefaultConfigPath() error = %v", err)
+		}
+		if want := "/etc/gaia/gaia-config.yaml"; path != want {
+			t.Errorf("getDefaultConfigPath() = %q, want %q", path, want)
+		}
+		if got := NewDefaultConfig().DBFile; got != "gaia.db" {
+			t.Errorf("default DBFile = %q, want %q", got, "gaia.db")
+		}
+	})
+
+	t.Run("Relative", func(t *testing.T) {
+		// The XDG spec requires absolute paths; relative values are ignored.
+		t.Setenv("XDG_CONFIG_HOME", "relative/config")
+		t.Setenv("XDG_DATA_HOME", "relative/data")
+
+		path, err := getDefaultConfigPath()
+		if err != nil {
+			t.Fatalf("getDefaultConfigPath() error = %v", err)
+		}
+		if want := "/etc/gaia/gaia-config.yaml"; path != want {
+			t.Errorf("getDefaultConfigPath() = %q, want %q", path, want)
+		}
+		if got := NewDefaultConfig().DBFile; got != "gaia.db" {
+			t.Errorf("default DBFile = %q, want %q", got, "gaia.db")
+		}
+	})
+}
+
+func TestLoadWithProvenance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gaia-config.yaml")
+	content := "db_file: /var/lib/gaia/from-file.db\ngrpc_port: \"6000\"\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("GAIA_DB_FILE", "/tmp/from-env.db")
+
+	cfg, prov, err := LoadWithProvenance(path)
+	if err != nil {
+		t.Fatalf("LoadWithProvenance() error = %v", err)
+	}
+	if cfg.DBFile != "/tmp/from-env.db" {
+		t.Errorf("DBFile = %q, want the env value", cfg.DBFile)
+	}
+
+	want := map[string]Source{
+		"db_file":         SourceEnv,
+		"grpc_port":       SourceFile,
+		"certs_directory": SourceDefault,
+	}
+	for field, source := range want {
+		if prov[field] != source {
+			t.Errorf("provenance of %s = %q, want %q", field, prov[field], source)
+		}
+	}
+	if len(prov) != len(FieldValues(cfg)) {
+		t.Errorf("provenance covers %d fields, want every field (%d)", len(prov), len(FieldValues(cfg)))
+	}
+}
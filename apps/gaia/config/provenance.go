@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Source names the configuration layer that set a field.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+)
+
+// Provenance maps the YAML name of every Config field to the layer that set it.
+type Provenance map[string]Source
+
+// LoadWithProvenance loads the configuration like Load and reports which layer
+// set each field. Every layer is applied to its own copy of the configuration;
+// a field is attributed to the last layer that changed its value, so a file
+// value equal to the default is reported as a default.
+func LoadWithProvenance(path string) (*Config, Provenance, error) {
+	defaults := NewDefaultConfig()
+
+	path, err := ResolvePath(path)
+	if err != nil {
+		return nil, nil, err // This would be an error like "home directory not found"
+	}
+
+	fromFile := *defaults
+	if _, err := os.Stat(path); err == nil {
+		if err := loadConfigFromFile(path, &fromFile); err != nil {
+			return nil, nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	fromEnv := fromFile
+	loadConfigFromEnv(&fromEnv)
+
+	prov := make(Provenance)
+	layers := []struct {
+		source Source
+		cfg    *Config
+	}{{SourceDefault, defaults}, {SourceFile, &fromFile}, {SourceEnv, &fromEnv}}
+	t := reflect.TypeOf(Config{})
+	for i := range t.NumField() {
+		name := yamlName(t.Field(i))
+		prov[name] = SourceDefault
+		for j := 1; j < len(layers); j++ {
+			prev := reflect.ValueOf(layers[j-1].cfg).Elem().Field(i).Interface()
+			cur := reflect.ValueOf(layers[j].cfg).Elem().Field(i).Interface()
+			if !reflect.DeepEqual(prev, cur) {
+				prov[name] = layers[j].source
+			}
+		}
+	}
+	return &fromEnv, prov, nil
+}
+
+// FieldValues returns the value of every field of cfg keyed by its YAML name.
+func FieldValues(cfg *Config) map[string]any {
+	values := make(map[string]any)
+	v := reflect.ValueOf(cfg).Elem()
+	for i := range v.NumField() {
+		values[yamlName(v.Type().Field(i))] = v.Field(i).Interface()
+	}
+	return values
+}
+
+// yamlName returns the YAML key of a Config field.
+func yamlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
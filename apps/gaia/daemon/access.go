@@ -0,0 +1,201 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// secretAccessBucket holds an accessRecord per secret, keyed like the secrets bucket.
+	secretAccessBucket = "secret_access"
+	// accessFlushInterval is how often buffered reads are written to the database.
+	accessFlushInterval = 30 * time.Second
+)
+
+// accessRecord counts the reads of a single secret.
+type accessRecord struct {
+	Count        uint64    `json:"count"`
+	LastAccessed time.Time `json:"last_accessed"`
+}
+
+// SecretUsage reports how often a secret was read and when it was last read. A
+// zero LastAccessed means the secret was never read while tracking was enabled.
+type SecretUsage struct {
+	Namespace    string
+	ID           string
+	AccessCount  uint64
+	LastAccessed time.Time
+}
+
+// accessTracker buffers secret reads in memory so GetSecret never writes to the
+// database. Buffered reads are merged into secretAccessBucket by flushAccessLocked.
+type accessTracker struct {
+	mu      sync.Mutex
+	pending map[string]accessRecord
+}
+
+func (a *accessTracker) record(key []byte, at time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending == nil {
+		a.pending = make(map[string]accessRecord)
+	}
+	rec := a.pending[string(key)]
+	rec.Count++
+	rec.LastAccessed = at
+	a.pending[string(key)] = rec
+}
+
+func (a *accessTracker) drain() map[string]accessRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	pending := a.pending
+	a.pending = nil
+	return pending
+}
+
+// recordAccess buffers a read of the secret stored under key when tracking is enabled.
+func (d *Daemon) recordAccess(key []byte) {
+	if d.config.TrackSecretAccess {
+		d.access.record(key, time.Now().UTC())
+	}
+}
+
+// flushAccessLocked merges buffered reads into the database. Reads of secrets that
+// have since been deleted are dropped. The caller must hold dbLock for writing.
+func (d *Daemon) flushAccessLocked() error {
+	pending := d.access.drain()
+	if len(pending) == 0 || d.db == nil {
+		return nil
+	}
+	return d.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(secretsBucket)) == nil {
+			return nil
+		}
+		b, err := tx.CreateBucketIfNotExists([]byte(secretAccessBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create or get access bucket: %w", err)
+		}
+		for k, p := range pending {
+			key := []byte(k)
+			client, namespace, id, ok := parseSecretKey(key)
+			if !ok || getSecretValue(tx, client, namespace, id) == nil {
+				continue
+			}
+			var rec accessRecord
+			if v := b.Get(key); v != nil {
+				if err := json.Unmarshal(v, &rec); err != nil {
+					return fmt.Errorf("corrupt access record: %w", err)
+				}
+			}
+			rec.Count += p.Count
+			if p.LastAccessed.After(rec.LastAccessed) {
+				rec.LastAccessed = p.LastAccessed
+			}
+			data, err := json.Marshal(rec)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(key, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FlushAccess writes buffered secret reads to the database.
+func (d *Daemon) FlushAccess() error {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return nil
+	}
+	return d.flushAccessLocked()
+}
+
+// runAccessFlusher periodically flushes buffered reads until stopped is closed.
+func (d *Daemon) runAccessFlusher(stopped <-chan struct{}) {
+	ticker := time.NewTicker(accessFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.FlushAccess(); err != nil {
+				log.Printf("failed to flush secret access records: %v", err)
+			}
+		case <-stopped:
+			return
+		}
+	}
+}
+
+// deleteAccessRecords removes the access records of every key starting with prefix.
+func deleteAccessRecords(tx *bbolt.Tx, prefix []byte) error {
+	b := tx.Bucket([]byte(secretAccessBucket))
+	if b == nil {
+		return nil
+	}
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, bytes.Clone(k))
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SecretUsage returns the read statistics of every secret of clientName, least
+// recently read first. Never-read secrets come before all others.
+func (d *Daemon) SecretUsage(clientName string) ([]SecretUsage, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return nil, errors.New("daemon is in a locked state, cannot report secret usage")
+	}
+	if err := d.flushAccessLocked(); err != nil {
+		return nil, fmt.Errorf("failed to flush secret access records: %w", err)
+	}
+
+	var usage []SecretUsage
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		accessB := tx.Bucket([]byte(secretAccessBucket))
+		return forEachSecret(tx, clientName, "", func(_, ns, id string, _ []byte) error {
+			u := SecretUsage{Namespace: ns, ID: id}
+			if accessB != nil {
+				if v := accessB.Get(constructDBKey(clientName, ns, id)); v != nil {
+					var rec accessRecord
+					if err := json.Unmarshal(v, &rec); err != nil {
+						return fmt.Errorf("corrupt access record for '%s/%s': %w", u.Namespace, u.ID, err)
+					}
+					u.AccessCount = rec.Count
+					u.LastAccessed = rec.LastAccessed
+				}
+			}
+			usage = append(usage, u)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(usage, func(a, b SecretUsage) int {
+		return a.LastAccessed.Compare(b.LastAccessed)
+	})
+	return usage, nil
+}
@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// mutatingMethods are the RPCs that change daemon state. They are always logged,
+// regardless of sampling.
+var mutatingMethods = map[string]bool{
+	pb.GaiaAdmin_AddSecret_FullMethodName:                 true,
+	pb.GaiaAdmin_DeleteSecret_FullMethodName:              true,
+	pb.GaiaAdmin_Stop_FullMethodName:                      true,
+	pb.GaiaAdmin_Unlock_FullMethodName:                    true,
+	pb.GaiaAdmin_Lock_FullMethodName:                      true,
+	pb.GaiaAdmin_RegisterClient_FullMethodName:            true,
+	pb.GaiaAdmin_RevokeClient_FullMethodName:              true,
+	pb.GaiaAdmin_ImportClients_FullMethodName:             true,
+	pb.GaiaAdmin_ImportSecrets_FullMethodName:             true,
+	pb.GaiaAdmin_ImportSecretsWithProgress_FullMethodName: true,
+	pb.GaiaAdmin_SetSecrets_FullMethodName:                true,
+	pb.GaiaAdmin_RotateSecrets_FullMethodName:             true,
+	pb.GaiaAdmin_SetCommonGrants_FullMethodName:           true,
+	pb.GaiaAdmin_MoveNamespace_FullMethodName:             true,
+	pb.GaiaAdmin_RenameSecret_FullMethodName:              true,
+	pb.GaiaAdmin_SetNamespacePatterns_FullMethodName:      true,
+	pb.GaiaAdmin_SetNamespaceTTL_FullMethodName:           true,
+	pb.GaiaAdmin_ClearNamespaceTTL_FullMethodName:         true,
+	pb.GaiaAdmin_Rekey_FullMethodName:                     true,
+	pb.GaiaAdmin_PruneOrphans_FullMethodName:              true,
+	pb.GaiaAdmin_DeleteSecretsByTag_FullMethodName:        true,
+	pb.GaiaClient_CreateSecretIfAbsent_FullMethodName:     true,
+}
+
+// accessLogger logs one entry per RPC. Mutating and failed RPCs, which include
+// authorization failures, are always logged; other RPCs are sampled.
+type accessLogger struct {
+	logger     *slog.Logger
+	sampleRate uint64
+	writesOnly bool
+	// identitySource and trustDomain select the client certificate field
+	// logged as client_cn.
+	identitySource string
+	trustDomain    string
+	reads          atomic.Uint64
+}
+
+func newAccessLogger(cfg *config.Config, logger *slog.Logger) *accessLogger {
+	rate := uint64(1)
+	if cfg.AccessLogSampleRate > 1 {
+		rate = uint64(cfg.AccessLogSampleRate)
+	}
+	return &accessLogger{
+		logger:         logger,
+		sampleRate:     rate,
+		writesOnly:     cfg.AccessLogWritesOnly,
+		identitySource: cfg.IdentitySource,
+		trustDomain:    cfg.IdentityTrustDomain,
+	}
+}
+
+// serverOptions returns the interceptors that write the access log.
+func (a *accessLogger) serverOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(a.unary),
+		grpc.ChainStreamInterceptor(a.stream),
+	}
+}
+
+// shouldLog reports whether the call to method that ended with err is logged.
+func (a *accessLogger) shouldLog(method string, err error) bool {
+	if err != nil || mutatingMethods[method] {
+		return true
+	}
+	if a.writesOnly {
+		return false
+	}
+	return (a.reads.Add(1)-1)%a.sampleRate == 0
+}
+
+func (a *accessLogger) log(ctx context.Context, method string, start time.Time, err error) {
+	if !a.shouldLog(method, err) {
+		return
+	}
+	clientName, idErr := getClientIdentity(ctx, a.identitySource, a.trustDomain)
+	if idErr != nil {
+		clientName = "unknown"
+	}
+	a.logger.Info("rpc",
+		slog.String("method", method),
+		slog.String("client_cn", clientName),
+		slog.String("code", status.Code(err).String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+func (a *accessLogger) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	a.log(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+func (a *accessLogger) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	a.log(ss.Context(), info.FullMethod, start, err)
+	return err
+}
@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc"
+)
+
+// runAccessLog sends reads read RPCs, writes write RPCs and one failed read through
+// an access logger and returns the number of logged reads, writes and failures.
+func runAccessLog(t *testing.T, cfg *config.Config, reads, writes int) (loggedReads, loggedWrites, loggedFailures int) {
+	t.Helper()
+	var buf bytes.Buffer
+	a := newAccessLogger(cfg, slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	ok := func(context.Context, any) (any, error) { return nil, nil }
+	failed := func(context.Context, any) (any, error) { return nil, errors.New("permission denied") }
+	call := func(method string, handler grpc.UnaryHandler) {
+		_, _ = a.unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+
+	for i := 0; i < reads; i++ {
+		call(pb.GaiaClient_GetSecret_FullMethodName, ok)
+	}
+	for i := 0; i < writes; i++ {
+		call(pb.GaiaAdmin_AddSecret_FullMethodName, ok)
+	}
+	call(pb.GaiaClient_GetSecret_FullMethodName, failed)
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		switch {
+		case line == "":
+		case strings.Contains(line, `"code":"Unknown"`):
+			loggedFailures++
+		case strings.Contains(line, pb.GaiaAdmin_AddSecret_FullMethodName):
+			loggedWrites++
+		case strings.Contains(line, pb.GaiaClient_GetSecret_FullMethodName):
+			loggedReads++
+		}
+	}
+	return loggedReads, loggedWrites, loggedFailures
+}
+
+func TestAccessLog_Sampling(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        func(*config.Config)
+		wantReads  int
+		wantWrites int
+	}{
+		{name: "every call with N=1", cfg: func(c *config.Config) { c.AccessLogSampleRate = 1 }, wantReads: 100, wantWrites: 10},
+		{name: "1 in 50 reads", cfg: func(c *config.Config) { c.AccessLogSampleRate = 50 }, wantReads: 2, wantWrites: 10},
+		{name: "writes only", cfg: func(c *config.Config) { c.AccessLogWritesOnly = true }, wantReads: 0, wantWrites: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			tt.cfg(cfg)
+			reads, writes, failures := runAccessLog(t, cfg, 100, 10)
+			if reads != tt.wantReads {
+				t.Errorf("logged %d reads, want %d", reads, tt.wantReads)
+			}
+			if writes != tt.wantWrites {
+				t.Errorf("logged %d writes, want %d", writes, tt.wantWrites)
+			}
+			if failures != 1 {
+				t.Errorf("logged %d failures, want 1", failures)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+func TestSecretUsage_TracksReads(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.TrackSecretAccess = true })
+	for _, id := range []string{"used", "unused"} {
+		if _, err := d.AddSecret("app", "app", id, "value", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret(%s) error = %v", id, err)
+		}
+	}
+
+	before := time.Now().UTC().Add(-time.Second)
+	for i := 0; i < 3; i++ {
+		if _, err := d.GetSecret("app", "app", "used"); err != nil {
+			t.Fatalf("GetSecret() error = %v", err)
+		}
+	}
+
+	usage, err := d.SecretUsage("app")
+	if err != nil {
+		t.Fatalf("SecretUsage() error = %v", err)
+	}
+	if len(usage) != 2 {
+		t.Fatalf("SecretUsage() returned %d secrets, want 2", len(usage))
+	}
+	// Never-read secrets sort first.
+	if usage[0].ID != "unused" || usage[0].AccessCount != 0 || !usage[0].LastAccessed.IsZero() {
+		t.Errorf("usage[0] = %+v, want the unread secret with no accesses", usage[0])
+	}
+	used := usage[1]
+	if used.ID != "used" || used.AccessCount != 3 {
+		t.Errorf("usage[1] = %+v, want 3 reads of 'used'", used)
+	}
+	if used.LastAccessed.Before(before) {
+		t.Errorf("LastAccessed = %v, want after %v", used.LastAccessed, before)
+	}
+
+	// Reads buffered after a flush are merged into the stored record.
+	if _, err := d.GetSecret("app", "app", "used"); err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	usage, err = d.SecretUsage("app")
+	if err != nil {
+		t.Fatalf("SecretUsage() error = %v", err)
+	}
+	if got := usage[1].AccessCount; got != 4 {
+		t.Errorf("AccessCount after another read = %d, want 4", got)
+	}
+
+	if err := d.DeleteSecret("app", "app", "used"); err != nil {
+		t.Fatalf("DeleteSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "used", "value", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	usage, err = d.SecretUsage("app")
+	if err != nil {
+		t.Fatalf("SecretUsage() error = %v", err)
+	}
+	for _, u := range usage {
+		if u.AccessCount != 0 {
+			t.Errorf("secret %q kept %d reads after being deleted and re-added", u.ID, u.AccessCount)
+		}
+	}
+}
+
+func TestSecretUsage_TrackingDisabled(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "key", "value", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.GetSecret("app", "app", "key"); err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	usage, err := d.SecretUsage("app")
+	if err != nil {
+		t.Fatalf("SecretUsage() error = %v", err)
+	}
+	if len(usage) != 1 || usage[0].AccessCount != 0 {
+		t.Errorf("SecretUsage() = %+v, want one secret with no recorded reads", usage)
+	}
+}
@@ -0,0 +1,107 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.etcd.io/bbolt"
+)
+
+// ErrUnreachableNamespace is returned for admin writes that the owning client could
+// never read back, when Config.EnforceClientNamespace is set.
+var ErrUnreachableNamespace = errors.New("namespace is not readable by its client")
+
+// commonName returns the configured name of the common area.
+func (d *Daemon) commonName() string {
+	if d.config.CommonName == "" {
+		return commonNamespace
+	}
+	return d.config.CommonName
+}
+
+// checkClientName returns ErrReservedClientName when clientName is the name of
+// the common area, which no real client may use.
+func (d *Daemon) checkClientName(clientName string) error {
+	if d.config.EnableCommonNamespace && clientName == d.commonName() {
+		return fmt.Errorf("%w: '%s' is the name of the common area", ErrReservedClientName, clientName)
+	}
+	return nil
+}
+
+// canReadNamespace reports whether GetSecret lets clientName read from namespace.
+// A client may read the namespace named after itself, the common namespace when
+// it is enabled, and any namespace granted to it in Config.NamespaceGrants.
+func (d *Daemon) canReadNamespace(clientName, namespace string) bool {
+	if namespace == clientName {
+		return true
+	}
+	if d.config.EnableCommonNamespace && namespace == d.commonName() {
+		return true
+	}
+	return slices.Contains(d.config.NamespaceGrants[clientName], namespace)
+}
+
+// checkWriteNamespace validates an admin write of a secret for clientName under
+// namespace against the read model above.
+// An unreachable write is an error when EnforceClientNamespace is set; otherwise
+// a warning describing the problem is returned and the write may proceed.
+func (d *Daemon) checkWriteNamespace(clientName, namespace string) (string, error) {
+	reachable := d.canReadNamespace(clientName, namespace)
+	if d.config.EnableCommonNamespace {
+		// Every namespace of the common client is served by GetCommonSecrets, while
+		// other clients' secrets in the common namespace are shadowed by it.
+		if clientName == d.commonName() {
+			reachable = true
+		} else if namespace == d.commonName() {
+			reachable = false
+		}
+	}
+	if reachable {
+		return "", nil
+	}
+
+	if d.config.EnforceClientNamespace {
+		return "", fmt.Errorf("%w: client '%s' cannot read namespace '%s'", ErrUnreachableNamespace, clientName, namespace)
+	}
+	return fmt.Sprintf("client '%s' cannot read namespace '%s'; the secret will not be retrievable by that client", clientName, namespace), nil
+}
+
+// commonGrants returns the common namespaces clientName is restricted to, or nil
+// when no grants exist and the client may read the whole common area. Grants are
+// stored in the common grants bucket under keyPrefix(client) followed by the namespace.
+func commonGrants(tx *bbolt.Tx, clientName string) []string {
+	b := tx.Bucket([]byte(commonGrantsBucket))
+	if b == nil {
+		return nil
+	}
+
+	var grants []string
+	prefix := keyPrefix(clientName)
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		grants = append(grants, unescapeKeyPart(k[len(prefix):]))
+	}
+	return grants
+}
+
+// canReadCommon reports whether grants returned by commonGrants allow reading
+// the given common namespace.
+func canReadCommon(grants []string, namespace string) bool {
+	return grants == nil || slices.Contains(grants, namespace)
+}
+
+// deleteCommonGrants removes every common grant held by clientName.
+func deleteCommonGrants(tx *bbolt.Tx, clientName string) error {
+	b := tx.Bucket([]byte(commonGrantsBucket))
+	if b == nil {
+		return nil
+	}
+	for _, ns := range commonGrants(tx, clientName) {
+		if err := b.Delete(append(keyPrefix(clientName), escapeKeyPart(ns)...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func addSecretViaAdmin(t *testing.T, d *Daemon, client, namespace, id, value string) *pb.AddSecretResponse {
+	t.Helper()
+	res, err := NewAdminServer(d).AddSecret(context.Background(), &pb.AddSecretRequest{
+		ClientName: client,
+		Namespace:  namespace,
+		Id:         id,
+		Value:      value,
+	})
+	if err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	return res
+}
+
+func TestAdminWrite_OwnNamespaceIsRetrievable(t *testing.T) {
+	d := newTestDaemon(t)
+	res := addSecretViaAdmin(t, d, "app-a", "app-a", "api_key", "s3cret")
+	if !res.Success || len(res.Warnings) > 0 {
+		t.Fatalf("AddSecret() = %+v, want success without warning", res)
+	}
+
+	got, err := d.GetSecret("app-a", "app-a", "api_key")
+	if err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("GetSecret() = %q, want %q", got, "s3cret")
+	}
+}
+
+func TestAdminWrite_UnreachableNamespaceWarns(t *testing.T) {
+	d := newTestDaemon(t)
+	res := addSecretViaAdmin(t, d, "app-a", "production", "api_key", "s3cret")
+	if !res.Success {
+		t.Fatalf("AddSecret() failed: %s", res.Message)
+	}
+	if len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "cannot read namespace 'production'") {
+		t.Errorf("AddSecret() warnings = %q, want a warning about the unreachable namespace", res.Warnings)
+	}
+	if _, err := d.GetSecret("app-a", "production", "api_key"); err == nil {
+		t.Errorf("GetSecret() succeeded for a namespace the client was not granted")
+	}
+}
+
+func TestAdminWrite_EnforcedRejectsUnreachableNamespace(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.EnforceClientNamespace = true })
+
+	res := addSecretViaAdmin(t, d, "app-a", "production", "api_key", "s3cret")
+	if res.Success {
+		t.Fatalf("AddSecret() succeeded for an unreachable namespace with enforcement on")
+	}
+	if _, err := d.AddSecret("app-a", "production", "api_key", "s3cret", AddSecretOptions{}); !errors.Is(err, ErrUnreachableNamespace) {
+		t.Errorf("Daemon.AddSecret() error = %v, want ErrUnreachableNamespace", err)
+	}
+
+	_, err := d.ImportSecrets([]*pb.ImportSecretItem{
+		{ClientName: "app-a", Namespace: "app-a", Id: "ok", Value: "v"},
+		{ClientName: "app-b", Namespace: "common", Id: "shadow", Value: "v"},
+	}, ImportFailOnConflict, "")
+	if !errors.Is(err, ErrUnreachableNamespace) {
+		t.Fatalf("ImportSecrets() error = %v, want ErrUnreachableNamespace", err)
+	}
+	if _, err := d.GetSecret("app-a", "app-a", "ok"); err == nil {
+		t.Errorf("ImportSecrets() partially applied a rejected import")
+	}
+}
+
+func TestAdminWrite_GrantedNamespaceIsRetrievable(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) {
+		c.EnforceClientNamespace = true
+		c.NamespaceGrants = map[string][]string{"app-a": {"production"}}
+	})
+
+	res := addSecretViaAdmin(t, d, "app-a", "production", "api_key", "s3cret")
+	if !res.Success || len(res.Warnings) > 0 {
+		t.Fatalf("AddSecret() = %+v, want success without warning", res)
+	}
+
+	got, err := d.GetSecret("app-a", "production", "api_key")
+	if err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("GetSecret() = %q, want %q", got, "s3cret")
+	}
+	if _, err := d.GetSecret("app-b", "production", "api_key"); err == nil {
+		t.Errorf("GetSecret() let an ungranted client read a granted namespace")
+	}
+}
+
+func TestAdminWrite_CommonNamespace(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.EnforceClientNamespace = true })
+
+	res := addSecretViaAdmin(t, d, "common", "common", "shared", "value")
+	if !res.Success {
+		t.Fatalf("AddSecret() to the common client failed: %s", res.Message)
+	}
+	got, err := d.GetSecret("app-a", "common", "shared")
+	if err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if got != "value" {
+		t.Errorf("GetSecret() = %q, want %q", got, "value")
+	}
+
+	if res := addSecretViaAdmin(t, d, "app-a", "common", "shared", "value"); res.Success {
+		t.Errorf("AddSecret() accepted a common-namespace write for a non-common client")
+	}
+}
+
+func newCommonAreaDaemon(t *testing.T) *Daemon {
+	t.Helper()
+	d := newTestDaemon(t)
+	for _, s := range []struct{ namespace, id string }{
+		{"shared", "smtp_host"}, {"billing", "stripe_key"}, {"common", "region"},
+	} {
+		if _, err := d.AddSecret(commonNamespace, s.namespace, s.id, s.namespace+"-value", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	return d
+}
+
+func TestGetCommonSecrets_Unrestricted(t *testing.T) {
+	d := newCommonAreaDaemon(t)
+
+	got, err := d.GetCommonSecrets("app-b", "")
+	if err != nil {
+		t.Fatalf("GetCommonSecrets() error = %v", err)
+	}
+	for _, ns := range []string{"shared", "billing", "common"} {
+		if _, ok := got[ns]; !ok {
+			t.Errorf("GetCommonSecrets() is missing namespace %q: %v", ns, got)
+		}
+	}
+
+	got, err = d.GetCommonSecrets("app-b", "billing")
+	if err != nil {
+		t.Fatalf("GetCommonSecrets(billing) error = %v", err)
+	}
+	if len(got) != 1 || got["billing"]["stripe_key"] != "billing-value" {
+		t.Errorf("GetCommonSecrets(billing) = %v, want only the billing namespace", got)
+	}
+}
+
+func TestGetCommonSecrets_Restricted(t *testing.T) {
+	d := newCommonAreaDaemon(t)
+	if err := d.SetCommonGrants("app-a", []string{"shared"}); err != nil {
+		t.Fatalf("SetCommonGrants() error = %v", err)
+	}
+
+	got, err := d.GetCommonSecrets("app-a", "")
+	if err != nil {
+		t.Fatalf("GetCommonSecrets() error = %v", err)
+	}
+	if len(got) != 1 || got["shared"]["smtp_host"] != "shared-value" {
+		t.Errorf("GetCommonSecrets() = %v, want only the shared namespace", got)
+	}
+	if _, err := d.GetCommonSecrets("app-a", "billing"); err == nil {
+		t.Errorf("GetCommonSecrets(billing) succeeded for a client not granted that namespace")
+	}
+	if _, err := d.GetSecret("app-a", "common", "region"); err == nil {
+		t.Errorf("GetSecret(common) succeeded for a client not granted the common namespace")
+	}
+
+	// Other clients are unaffected by app-a's grants.
+	if got, err := d.GetCommonSecrets("app-b", ""); err != nil || len(got) != 3 {
+		t.Errorf("GetCommonSecrets() for an unrestricted client = %v, %v; want all 3 namespaces", got, err)
+	}
+
+	// Clearing the grants restores access to the whole common area.
+	if err := d.SetCommonGrants("app-a", nil); err != nil {
+		t.Fatalf("SetCommonGrants(nil) error = %v", err)
+	}
+	if got, err := d.GetCommonSecrets("app-a", ""); err != nil || len(got) != 3 {
+		t.Errorf("GetCommonSecrets() after clearing grants = %v, %v; want all 3 namespaces", got, err)
+	}
+}
+
+func TestGetCommonSecrets_RevokeClearsGrants(t *testing.T) {
+	d := newCommonAreaDaemon(t)
+	if err := d.SetCommonGrants("app-a", []string{"shared"}); err != nil {
+		t.Fatalf("SetCommonGrants() error = %v", err)
+	}
+	if _, err := d.RevokeClient("app-a"); err != nil {
+		t.Fatalf("RevokeClient() error = %v", err)
+	}
+
+	// A client re-registered under the same name starts without restrictions.
+	if got, err := d.GetCommonSecrets("app-a", ""); err != nil || len(got) != 3 {
+		t.Errorf("GetCommonSecrets() after revoke = %v, %v; want all 3 namespaces", got, err)
+	}
+}
+
+func TestCommonGrants_CacheInvalidatedOnChange(t *testing.T) {
+	d := newCommonAreaDaemon(t)
+
+	// Warm the cache with app-a's unrestricted access.
+	if _, err := d.GetSecret("app-a", "common", "region"); err != nil {
+		t.Fatalf("GetSecret(common) before any grant error = %v", err)
+	}
+	if _, ok := d.grants.grants["app-a"]; !ok {
+		t.Fatal("grants of app-a were not cached by GetSecret")
+	}
+
+	if err := d.SetCommonGrants("app-a", []string{"shared"}); err != nil {
+		t.Fatalf("SetCommonGrants() error = %v", err)
+	}
+	if _, err := d.GetSecret("app-a", "common", "region"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("GetSecret(common) after restricting grants error = %v, want ErrPermissionDenied", err)
+	}
+	if got, err := d.GetCommonSecrets("app-a", ""); err != nil || len(got) != 1 {
+		t.Errorf("GetCommonSecrets() after restricting grants = %v, %v; want only the shared namespace", got, err)
+	}
+
+	if _, err := d.RevokeClient("app-a"); err != nil {
+		t.Fatalf("RevokeClient() error = %v", err)
+	}
+	if _, err := d.GetSecret("app-a", "common", "region"); err != nil {
+		t.Errorf("GetSecret(common) after revoke error = %v, want the grants dropped", err)
+	}
+
+	d.LockDB()
+	if d.grants.grants != nil {
+		t.Errorf("grants cache = %v after lock, want it cleared", d.grants.grants)
+	}
+}
+
+func BenchmarkGetSecret_CommonGrants(b *testing.B) {
+	d := newTestDaemon(b)
+	for i := range 50 {
+		if _, err := d.AddSecret(commonNamespace, fmt.Sprintf("ns-%02d", i), "key", "v", AddSecretOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if _, err := d.AddSecret(commonNamespace, "common", "region", "eu", AddSecretOptions{}); err != nil {
+		b.Fatal(err)
+	}
+	grants := []string{"common"}
+	for i := range 50 {
+		grants = append(grants, fmt.Sprintf("ns-%02d", i))
+	}
+	if err := d.SetCommonGrants("app-a", grants); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := d.GetSecret("app-a", "common", "region"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestGetCommonSecrets_Disabled(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.EnableCommonNamespace = false })
+	if _, err := d.GetCommonSecrets("app-a", ""); err == nil {
+		t.Errorf("GetCommonSecrets() succeeded with the common namespace disabled")
+	}
+}
+
+func TestClientGetSecret_HideUnauthorizedAsNotFound(t *testing.T) {
+	for _, hide := range []bool{false, true} {
+		d := newTestDaemon(t, func(cfg *config.Config) { cfg.HideUnauthorizedAsNotFound = hide })
+		if _, err := d.AddSecret("app-b", "app-b", "api_key", "s3cret", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+		srv := NewClientServer(d)
+
+		_, denied := srv.GetSecret(clientContext("app-a"), &pb.GetSecretRequest{Namespace: "app-b", Id: "api_key"})
+		_, missing := srv.GetSecret(clientContext("app-a"), &pb.GetSecretRequest{Namespace: "app-a", Id: "api_key"})
+		if status.Code(missing) != codes.NotFound {
+			t.Errorf("hide=%v: GetSecret() of a missing secret error = %v, want NotFound", hide, missing)
+		}
+		if hide {
+			if status.Code(denied) != codes.NotFound || denied.Error() != missing.Error() {
+				t.Errorf("hide=%v: denied error %q differs from missing error %q", hide, denied, missing)
+			}
+		} else if status.Code(denied) != codes.PermissionDenied {
+			t.Errorf("hide=%v: GetSecret() of another client's namespace error = %v, want PermissionDenied", hide, denied)
+		}
+
+		list, err := srv.ListOwnSecrets(clientContext("app-a"), &pb.ListOwnSecretsRequest{Namespace: "app-b"})
+		if hide && (err != nil || len(list.Secrets) != 0) {
+			t.Errorf("hide=%v: ListOwnSecrets() of another client's namespace = %v, %v; want an empty list", hide, list, err)
+		}
+		if !hide && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("hide=%v: ListOwnSecrets() of another client's namespace error = %v, want PermissionDenied", hide, err)
+		}
+
+		// The daemon still reports the real error, for the audit log and admin tooling.
+		if _, err := d.GetSecret("app-a", "app-b", "api_key"); !errors.Is(err, ErrPermissionDenied) {
+			t.Errorf("hide=%v: daemon GetSecret() error = %v, want ErrPermissionDenied", hide, err)
+		}
+	}
+}
+
+func TestClientListOwnSecretIds(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, s := range [][3]string{
+		{"app-a", "app-a", "db_password"},
+		{"app-a", "app-a", "api_key"},
+		{"app-b", "app-b", "other_key"},
+	} {
+		if _, err := d.AddSecret(s[0], s[1], s[2], "v", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	srv := NewClientServer(d)
+
+	res, err := srv.ListOwnSecretIds(clientContext("app-a"), &pb.ListOwnSecretIdsRequest{Namespace: "app-a"})
+	if err != nil {
+		t.Fatalf("ListOwnSecretIds() error = %v", err)
+	}
+	if !slices.Equal(res.Ids, []string{"api_key", "db_password"}) || res.NextPageToken != "" {
+		t.Errorf("ListOwnSecretIds() = %v, want [api_key db_password] on one page", res)
+	}
+
+	_, err = srv.ListOwnSecretIds(clientContext("app-a"), &pb.ListOwnSecretIdsRequest{Namespace: "app-b"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListOwnSecretIds() of another client's namespace error = %v, want PermissionDenied", err)
+	}
+}
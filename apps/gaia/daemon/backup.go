@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+// Scheduled backups are named backupPrefix, the UTC time of the backup and
+// backupSuffix, so they sort by age.
+const (
+	backupPrefix     = "gaia-"
+	backupSuffix     = ".db"
+	backupTimeLayout = "20060102T150405.000Z"
+)
+
+// Backup writes a consistent snapshot of the database to path. The snapshot is
+// taken in a read transaction, so writes continue while it is copied; it is
+// written to a temporary file first and only renamed to path once complete.
+// Secrets stay encrypted, so the backup is unlocked with the same passphrase.
+func (d *Daemon) Backup(path string) (int64, error) {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked || d.db == nil {
+		return 0, errors.New("daemon is in a locked state, cannot back up the database")
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create backup file: %w", err)
+	}
+	var size int64
+	err = d.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		size, err = tx.WriteTo(f)
+		return err
+	})
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("failed to write backup: %w", err)
+	}
+	return size, nil
+}
+
+// scheduledBackup writes a timestamped backup to the configured backup
+// directory and prunes the backups beyond the retention count.
+func (d *Daemon) scheduledBackup(now time.Time) error {
+	dir := d.config.BackupDirectory
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	path := filepath.Join(dir, backupPrefix+now.UTC().Format(backupTimeLayout)+backupSuffix)
+	size, err := d.Backup(path)
+	if err != nil {
+		return err
+	}
+	gaialog.Get().Info("database backed up", slog.String("path", path), slog.Int64("bytes", size))
+
+	removed, err := pruneBackups(dir, d.config.BackupRetention)
+	for _, name := range removed {
+		gaialog.Get().Info("old backup removed", slog.String("path", filepath.Join(dir, name)))
+	}
+	return err
+}
+
+// pruneBackups removes the oldest scheduled backups in dir until at most keep
+// remain, and returns the names of the removed files. Other files are left
+// alone. A keep below 1 removes nothing.
+func pruneBackups(dir string, keep int) ([]string, error) {
+	if keep < 1 {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []string
+	for _, e := range entries {
+		if name := e.Name(); e.Type().IsRegular() && strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, backupSuffix) {
+			backups = append(backups, name)
+		}
+	}
+	if len(backups) <= keep {
+		return nil, nil
+	}
+	slices.Sort(backups)
+
+	var removed []string
+	for _, name := range backups[:len(backups)-keep] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return removed, err
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+// runScheduledBackups backs up the database every interval until stopped is
+// closed. A locked daemon is skipped, as its database is closed.
+func (d *Daemon) runScheduledBackups(interval time.Duration, stopped <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if locked, _, _ := d.LockState(); locked {
+				gaialog.Get().Debug("daemon is locked, skipping scheduled backup")
+				continue
+			}
+			if err := d.scheduledBackup(now); err != nil {
+				gaialog.Get().Error("scheduled backup failed", slog.String("error", err.Error()))
+			}
+		case <-stopped:
+			return
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+// listBackups returns the names of the scheduled backups in dir.
+func listBackups(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("failed to read backup directory: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), backupPrefix) && strings.HasSuffix(e.Name(), backupSuffix) {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+func TestScheduledBackups_WritesAndRotates(t *testing.T) {
+	backupDir := filepath.Join(t.TempDir(), "backups")
+	d := newTestDaemon(t, func(c *config.Config) {
+		c.BackupDirectory = backupDir
+		c.BackupRetention = 2
+	})
+	if _, err := d.AddSecret("app", "app", "api_key", "s3cret", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	unrelated := filepath.Join(backupDir, "notes.txt")
+	if err := os.WriteFile(unrelated, []byte("keep me"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	stopped, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		d.runScheduledBackups(20*time.Millisecond, stopped)
+		close(done)
+	}()
+	stop := func() {
+		close(stopped)
+		<-done
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(listBackups(t, backupDir)) < 2 {
+		if time.Now().After(deadline) {
+			stop()
+			t.Fatal("no scheduled backups were written")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Let a few more backups run, so rotation has to prune.
+	time.Sleep(100 * time.Millisecond)
+	stop()
+
+	backups := listBackups(t, backupDir)
+	if len(backups) != 2 {
+		t.Errorf("backup directory holds %d backups, want 2 after rotation: %v", len(backups), backups)
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("rotation removed an unrelated file: %v", err)
+	}
+
+	// A backup is a complete database that unlocks with the same passphrase.
+	restored := NewDaemon(newTestConfig(t))
+	restored.config.DBFile = filepath.Join(backupDir, backups[len(backups)-1])
+	if err := restored.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() on the backup error = %v", err)
+	}
+	defer restored.LockDB()
+	if v, err := restored.GetSecret("app", "app", "api_key"); err != nil || v != "s3cret" {
+		t.Errorf("GetSecret() from the backup = %q, %v", v, err)
+	}
+}
+
+func TestScheduledBackups_SkipsLockedDaemon(t *testing.T) {
+	backupDir := filepath.Join(t.TempDir(), "backups")
+	d := newTestDaemon(t, func(c *config.Config) { c.BackupDirectory = backupDir })
+	d.LockDB()
+
+	stopped := make(chan struct{})
+	go d.runScheduledBackups(10*time.Millisecond, stopped)
+	time.Sleep(60 * time.Millisecond)
+	close(stopped)
+
+	if backups := listBackups(t, backupDir); len(backups) != 0 {
+		t.Errorf("locked daemon wrote backups: %v", backups)
+	}
+}
@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+)
+
+// clientCertExpiryWarning is how long before its expiry a client certificate
+// is logged as expiring soon when it connects.
+const clientCertExpiryWarning = 14 * 24 * time.Hour
+
+// verifyClientCertificate returns a tls.Config.VerifyPeerCertificate callback
+// that verifies the client chain against roots. A certificate that expired or
+// becomes valid within skew of now is still accepted, so hosts with slightly
+// unsynced clocks can connect; anything further outside its validity window is
+// rejected. Certificates close to expiry are logged.
+func verifyClientCertificate(roots *x509.CertPool, skew time.Duration, now func() time.Time) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no client certificate presented")
+		}
+		chain := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse client certificate: %w", err)
+			}
+			chain[i] = cert
+		}
+		leaf := chain[0]
+		intermediates := x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		at := now()
+		switch {
+		case at.After(leaf.NotAfter) && at.Sub(leaf.NotAfter) <= skew:
+			gaialog.Get().Warn("accepting expired client certificate within the clock skew allowance",
+				slog.String("client_cn", leaf.Subject.CommonName),
+				slog.Time("not_after", leaf.NotAfter),
+			)
+			at = leaf.NotAfter
+		case at.Before(leaf.NotBefore) && leaf.NotBefore.Sub(at) <= skew:
+			at = leaf.NotBefore
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   at,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		if err != nil {
+			return err
+		}
+
+		if left := leaf.NotAfter.Sub(now()); left > 0 && left < clientCertExpiryWarning {
+			gaialog.Get().Warn("client certificate expires soon",
+				slog.String("client_cn", leaf.Subject.CommonName),
+				slog.Time("not_after", leaf.NotAfter),
+			)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package daemon
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/certs"
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+// issueClientCert returns a CA pool and a client certificate of that CA, valid
+// for one day.
+func issueClientCert(t *testing.T) (*x509.CertPool, *x509.Certificate) {
+	t.Helper()
+	cfg := newTestConfig(t, func(c *config.Config) {
+		c.CertsDirectory = t.TempDir()
+		c.CertExpiryDays = 1
+	})
+	if err := certs.GenerateCA(cfg, "Gaia Test CA"); err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	if err := certs.GenerateClientCertificate(cfg, "app"); err != nil {
+		t.Fatalf("GenerateClientCertificate() error = %v", err)
+	}
+	load := func(name string) *x509.Certificate {
+		data, err := os.ReadFile(filepath.Join(cfg.CertsDirectory, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, _ := pem.Decode(data)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(load(cfg.CACertFile))
+	return roots, load("app.crt")
+}
+
+func TestVerifyClientCertificate_ClockSkew(t *testing.T) {
+	roots, cert := issueClientCert(t)
+	const skew = 5 * time.Minute
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		skew    time.Duration
+		wantErr bool
+	}{
+		{"valid", cert.NotBefore.Add(time.Hour), skew, false},
+		{"just expired, within skew", cert.NotAfter.Add(time.Minute), skew, false},
+		{"well expired", cert.NotAfter.Add(time.Hour), skew, true},
+		{"just expired, no skew", cert.NotAfter.Add(time.Minute), 0, true},
+		{"not yet valid, within skew", cert.NotBefore.Add(-time.Minute), skew, false},
+		{"not yet valid, beyond skew", cert.NotBefore.Add(-time.Hour), skew, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verify := verifyClientCertificate(roots, tt.skew, func() time.Time { return tt.now })
+			err := verify([][]byte{cert.Raw}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	otherRoots, _ := issueClientCert(t)
+	verify := verifyClientCertificate(otherRoots, skew, time.Now)
+	if err := verify([][]byte{cert.Raw}, nil); err == nil {
+		t.Error("verify() accepted a certificate of another CA")
+	}
+}
+
+func TestServerTLSConfig_ClockSkew(t *testing.T) {
+	cfg := newTestConfig(t, func(c *config.Config) { c.CertsDirectory = t.TempDir() })
+	if err := certs.GenerateCA(cfg, "Gaia Test CA"); err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	if err := certs.GenerateServerCertificate(cfg, "localhost"); err != nil {
+		t.Fatalf("GenerateServerCertificate() error = %v", err)
+	}
+
+	tlsConfig, err := NewDaemon(cfg).serverTLSConfig()
+	if err != nil {
+		t.Fatalf("serverTLSConfig() error = %v", err)
+	}
+	if tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("ClientAuth without skew = %v, want RequireAndVerifyClientCert", tlsConfig.ClientAuth)
+	}
+
+	cfg.ClientCertClockSkew = time.Minute
+	tlsConfig, err = NewDaemon(cfg).serverTLSConfig()
+	if err != nil {
+		t.Fatalf("serverTLSConfig() error = %v", err)
+	}
+	// With a skew, the chain is verified only by the callback, which must be set.
+	if tlsConfig.ClientAuth != tls.RequireAnyClientCert || tlsConfig.VerifyPeerCertificate == nil {
+		t.Errorf("ClientAuth with skew = %v, want RequireAnyClientCert and a verify callback", tlsConfig.ClientAuth)
+	}
+}
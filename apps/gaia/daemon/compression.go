@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"context"
+	"slices"
+
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// CompressionGzip is the value of Config.Compression that gzips the responses
+// of bulk RPCs.
+const CompressionGzip = gzip.Name
+
+// compressedMethods are the RPCs whose responses carry many secrets or clients
+// and are worth compressing. Other responses are small and sent as they are.
+var compressedMethods = map[string]bool{
+	pb.GaiaAdmin_ListSecrets_FullMethodName:          true,
+	pb.GaiaAdmin_StreamSecrets_FullMethodName:        true,
+	pb.GaiaAdmin_ExportSecrets_FullMethodName:        true,
+	pb.GaiaAdmin_ExportClients_FullMethodName:        true,
+	pb.GaiaAdmin_ExportClientManifest_FullMethodName: true,
+	pb.GaiaClient_GetCommonSecrets_FullMethodName:    true,
+	pb.GaiaClient_ListOwnSecrets_FullMethodName:      true,
+}
+
+// validCompression reports whether compression is a known Config.Compression.
+func validCompression(compression string) bool {
+	return compression == "" || compression == "none" || compression == CompressionGzip
+}
+
+// compressionOptions returns the interceptors that compress the responses of
+// compressedMethods, or nothing when compression is off.
+func (d *Daemon) compressionOptions() []grpc.ServerOption {
+	if d.config.Compression != CompressionGzip {
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(compressUnary),
+		grpc.ChainStreamInterceptor(compressStream),
+	}
+}
+
+// setResponseCompressor gzips the responses of method when the client
+// announced that it accepts gzip. Clients that do not are answered
+// uncompressed, so compression never breaks a call.
+func setResponseCompressor(ctx context.Context, method string) {
+	if !compressedMethods[method] {
+		return
+	}
+	accepted, err := grpc.ClientSupportedCompressors(ctx)
+	if err != nil || !slices.Contains(accepted, gzip.Name) {
+		return
+	}
+	_ = grpc.SetSendCompressor(ctx, gzip.Name)
+}
+
+func compressUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	setResponseCompressor(ctx, info.FullMethod)
+	return handler(ctx, req)
+}
+
+func compressStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	setResponseCompressor(ss.Context(), info.FullMethod)
+	return handler(srv, ss)
+}
@@ -6,43 +6,94 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/subtle"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"log/slog"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/stain-win/gaia/apps/gaia/certs"
 	"github.com/stain-win/gaia/apps/gaia/config"
 	"github.com/stain-win/gaia/apps/gaia/encrypt"
 	"github.com/stain-win/gaia/apps/gaia/gaialog"
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
 	"go.etcd.io/bbolt"
+	berrors "go.etcd.io/bbolt/errors"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 )
 
-// nullByte is the delimiter used for constructing composite keys in the database.
-var nullByte = []byte{0x00}
+var (
+	// ErrClientExists is returned when registering a client name that is already taken.
+	ErrClientExists = errors.New("client already exists")
+	// ErrReservedClientName is returned when registering a client under the name
+	// of the common area.
+	ErrReservedClientName = errors.New("client name is reserved")
+	// ErrClientNotFound is returned when a client is not registered.
+	ErrClientNotFound = errors.New("client not found")
+	// ErrSecretExists is returned when a write would replace an existing secret
+	// without overwrite being requested.
+	ErrSecretExists = errors.New("secret already exists")
+	// ErrDatabaseCorrupt is returned when the stored salt or key hash is missing or
+	// malformed, as opposed to a wrong passphrase.
+	ErrDatabaseCorrupt = errors.New("database corrupt")
+	// ErrInvalidPassphrase is returned when a passphrase does not match the stored key hash.
+	ErrInvalidPassphrase = errors.New("invalid passphrase")
+	// ErrSecretNotFound is returned when a requested secret does not exist.
+	ErrSecretNotFound = errors.New("secret not found")
+	// ErrPermissionDenied is returned when a client accesses a namespace it is
+	// not authorized for.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrDatabaseInUse is returned when the database file stays locked by
+	// another process, such as a second daemon.
+	ErrDatabaseInUse = errors.New("database file is locked by another process")
+	// ErrAlreadyUnlocked is returned by UnlockDB when the daemon is already
+	// unlocked. The database and key are left untouched.
+	ErrAlreadyUnlocked = errors.New("daemon is already unlocked")
+	// ErrKeySharesRequired is returned by UnlockDB for a database initialized
+	// with key shares, which is unlocked with UnlockWithShare instead.
+	ErrKeySharesRequired = errors.New("database is unlocked with key shares, not a passphrase")
+	// ErrStructuredSecret is returned by RotateSecrets for a namespace holding
+	// structured secrets, whose generated value would not be a JSON object.
+	ErrStructuredSecret = errors.New("structured secrets cannot be rotated")
+)
 
 const (
-	metaPrefix      = "gaia:internal:cmfk1rbd000000m74bic9evy3"
-	saltKey         = metaPrefix + "__salt__"
-	keyHashKey      = metaPrefix + "__key_hash__"
-	secretsBucket   = "secrets"
-	clientsBucket   = "clients"
-	StatusRunning   = "running"
-	StatusStopped   = "stopped"
-	StatusStarting  = "starting"
+	// metaBucket holds the daemon's own metadata, apart from the secrets.
+	metaBucket    = "meta"
+	saltKey       = "salt"
+	keyHashKey    = "key_hash"
+	secretsBucket = "secrets"
+	clientsBucket = "clients"
+	// commonGrantsBucket restricts which common namespaces a client may read.
+	commonGrantsBucket = "common_grants"
+	StatusRunning      = "running"
+	StatusStopped      = "stopped"
+	StatusStarting     = "starting"
+	// commonNamespace is the name of the common area when Config.CommonName is
+	// not set.
 	commonNamespace = "common"
+	// saltLen is the length of the KDF salt stored at init.
+	saltLen = 16
+	// dbOpenTimeout bounds how long one attempt waits for the database file lock.
+	dbOpenTimeout = time.Second
+	// dbOpenBackoff is the pause after the first failed attempt; it doubles after
+	// every further one.
+	dbOpenBackoff = 250 * time.Millisecond
 )
 
 // Client represents a registered client in the Gaia system.
@@ -55,25 +106,40 @@ type Client struct {
 type Daemon struct {
 	config      *config.Config
 	server      *grpc.Server
+	conns       *connCounter
 	db          *bbolt.DB
 	key         []byte
+	keyLocked   bool // key lives in locked memory from allocLocked
 	caCert      *x509.Certificate
 	caKey       *rsa.PrivateKey
 	dbLock      sync.RWMutex
 	status      string
 	isLocked    bool
-	stopChannel chan struct{}
+	lockReason  LockReason    // why the daemon was last locked; empty while unlocked
+	lockChanged time.Time     // when the daemon was last locked or unlocked
+	lifecycleMu sync.Mutex    // serializes Start's server setup with Stop
+	stopped     chan struct{} // closed once Stop has shut the daemon down
 	createdAt   time.Time
+	counters    counters
+	access      accessTracker
+	grants      grantsCache
+	passCheck   passphraseCheckCache
+	shares      [][]byte // key shares submitted towards an unlock, while locked
+	tracer      trace.Tracer
+	tracerStop  func(context.Context) error
 }
 
 // NewDaemon creates a new Daemon instance with default configuration.
 func NewDaemon(cfg *config.Config) *Daemon {
+	now := time.Now().UTC()
 	return &Daemon{
 		config:      cfg,
 		status:      StatusStopped,
 		isLocked:    true,
-		stopChannel: make(chan struct{}),
-		createdAt:   time.Now().UTC(),
+		lockReason:  LockReasonStartup,
+		lockChanged: now,
+		stopped:     make(chan struct{}),
+		createdAt:   now,
 	}
 }
 
@@ -100,22 +166,67 @@ func (d *Daemon) Start(cfg *config.Config) error {
 	if _, err := os.Stat(d.config.DBFile); os.IsNotExist(err) {
 		return fmt.Errorf("initial setup not complete, run 'gaia init' first")
 	}
+	if _, err := compileValueRules(d.config.ValueRules); err != nil {
+		return err
+	}
+	if e := d.config.CiphertextEncoding; e != "" && e != encrypt.EncodingBase64 && e != encrypt.EncodingRaw {
+		return fmt.Errorf("invalid ciphertext_encoding '%s', want %s or %s", e, encrypt.EncodingBase64, encrypt.EncodingRaw)
+	}
+	if t := d.config.DBFreelistType; t != "" && t != string(bbolt.FreelistArrayType) && t != string(bbolt.FreelistMapType) {
+		return fmt.Errorf("invalid db_freelist_type '%s', want %s or %s", t, bbolt.FreelistArrayType, bbolt.FreelistMapType)
+	}
+	if !validCompression(d.config.Compression) {
+		return fmt.Errorf("invalid compression '%s', want none or %s", d.config.Compression, CompressionGzip)
+	}
+	if !validIdentitySource(d.config.IdentitySource) {
+		return fmt.Errorf("invalid identity_source '%s', want %s, %s, %s or %s", d.config.IdentitySource, IdentityCN, IdentityURISAN, IdentityEmailSAN, IdentityOU)
+	}
+	if d.config.IdentitySource == IdentityURISAN {
+		if d.config.IdentityTrustDomain == "" {
+			return fmt.Errorf("identity_source %s requires identity_trust_domain, such as spiffe://example.org", IdentityURISAN)
+		}
+		if _, err := parseTrustDomain(d.config.IdentityTrustDomain); err != nil {
+			return err
+		}
+	}
+	if d.config.DBNoSync {
+		gaialog.Get().Warn("db_no_sync is set, a crash or power loss can lose recent writes or corrupt the database")
+	}
+
+	// With RequireUnlockToServe the listener is only opened once the database has
+	// been unlocked locally, so nothing is reachable over the network before then.
+	preUnlocked := !d.IsLocked()
+	if d.config.RequireUnlockToServe && !preUnlocked {
+		return errors.New("require_unlock_to_serve is set, the database must be unlocked before the daemon starts serving")
+	}
 
 	d.status = StatusStarting
 
+	if d.config.OTLPEndpoint != "" {
+		tp, err := newOTLPTracerProvider(context.Background(), d.config.OTLPEndpoint, d.config.OTLPInsecure)
+		if err != nil {
+			d.status = StatusStopped
+			return err
+		}
+		d.SetTracerProvider(tp)
+		d.tracerStop = tp.Shutdown
+	}
+
 	creds, err := d.loadTLSCredentials()
 	if err != nil {
 		d.status = StatusStopped
 		return fmt.Errorf("failed to load TLS credentials: %w", err)
 	}
 
-	d.dbLock.Lock()
-	if err := d.openDB(); err != nil {
+	if !preUnlocked {
+		d.dbLock.Lock()
+		if err := d.openDB(); err != nil {
+			d.dbLock.Unlock()
+			d.status = StatusStopped
+			return fmt.Errorf("failed to open database: %w", err)
+		}
 		d.dbLock.Unlock()
-		d.status = StatusStopped
-		return fmt.Errorf("failed to open database: %w", err)
 	}
-	d.dbLock.Unlock()
 
 	serverOpts := []grpc.ServerOption{
 		grpc.Creds(creds),
@@ -127,59 +238,135 @@ func (d *Daemon) Start(cfg *config.Config) error {
 			PermitWithoutStream: true,
 		}),
 	}
-
-	d.server = grpc.NewServer(serverOpts...)
-	pb.RegisterGaiaAdminServer(d.server, &gaiaAdminServer{d: d})
-	pb.RegisterGaiaClientServer(d.server, &gaiaClientServer{daemon: d})
+	serverOpts = append(serverOpts, d.unlockGateOptions()...)
+	serverOpts = append(serverOpts, d.compressionOptions()...)
+	if d.config.AccessLog {
+		serverOpts = append(serverOpts, newAccessLogger(d.config, gaialog.Get()).serverOptions()...)
+	}
+	conns := &connCounter{}
+	serverOpts = append(serverOpts, grpc.StatsHandler(conns))
 
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", d.config.GRPCPort))
 	if err != nil {
-		d.db.Close()
+		d.closeDB(LockReasonShutdown)
 		d.status = StatusStopped
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
+	d.lifecycleMu.Lock()
+	server := grpc.NewServer(serverOpts...)
+	d.registerServices(server)
+	d.server = server
+	d.conns = conns
+	stopped := make(chan struct{})
+	d.stopped = stopped
 	d.status = StatusRunning
-	d.isLocked = true
+	d.lifecycleMu.Unlock()
 
 	log.Println("Gaia daemon started successfully and is running in the foreground.")
+	if d.config.TrackSecretAccess {
+		go d.runAccessFlusher(stopped)
+	}
+	if d.config.ExpirySweepInterval > 0 {
+		go d.runExpirySweeper(d.config.ExpirySweepInterval, stopped)
+	}
+	if d.config.KeyCheckInterval > 0 {
+		go d.runKeyChecker(d.config.KeyCheckInterval, stopped)
+	}
+	if d.config.BackupDirectory != "" && d.config.BackupInterval > 0 {
+		go d.runScheduledBackups(d.config.BackupInterval, stopped)
+	}
 	errChan := make(chan error, 1)
 	go func() {
-		if err := d.server.Serve(listener); err != nil {
+		if err := server.Serve(listener); err != nil {
 			errChan <- fmt.Errorf("gRPC server stopped with error: %w", err)
 		}
 	}()
 
-	// Block until a stop signal is received via the channel
+	// Block until Stop has shut the daemon down.
 	select {
-	case <-d.stopChannel:
-		d.server.GracefulStop()
+	case <-stopped:
+		return nil
 	case err := <-errChan:
+		d.Close()
 		return err
 	}
-	d.status = StatusStopped
-	d.db.Close()
-	return nil
 }
 
-// stopDaemon gracefully stops the gRPC server and closes the database.
-func (d *Daemon) stopDaemon(_ context.Context) error {
-	if d.status != StatusRunning {
-		return errors.New("daemon not running")
+// Stop gracefully stops the gRPC server, shuts down tracing and closes the
+// database, unblocking Start. If ctx is done or the configured shutdown grace
+// period elapses before in-flight RPCs finish, the remaining connections are
+// closed forcibly. Stop is safe to call more than once.
+func (d *Daemon) Stop(ctx context.Context) error {
+	d.lifecycleMu.Lock()
+	defer d.lifecycleMu.Unlock()
+
+	if grace := d.GetConfig().ShutdownGracePeriod; grace > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, grace)
+		defer cancel()
+	}
+
+	if d.server != nil {
+		d.stopServer(ctx)
+		d.server = nil
+		d.conns = nil
+		log.Println("Gaia daemon stopped")
 	}
-	d.server.GracefulStop()
-	d.db.Close()
+	d.closeDB(LockReasonShutdown)
+	d.shutdownTracing()
 	d.status = StatusStopped
-	d.isLocked = true
-	log.Println("Gaia daemon stopped")
+
+	select {
+	case <-d.stopped:
+	default:
+		close(d.stopped)
+	}
 	return nil
 }
 
+// Close stops the daemon, waiting for in-flight RPCs to finish.
+func (d *Daemon) Close() error {
+	return d.Stop(context.Background())
+}
+
+// stopServer gracefully stops the gRPC server, falling back to a forced stop when
+// ctx is done.
+func (d *Daemon) stopServer(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		d.server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		var open int64
+		if d.conns != nil {
+			open = d.conns.open.Load()
+		}
+		log.Printf("Shutdown grace period elapsed, forcibly closing %d remaining connections", open)
+		d.server.Stop()
+		<-done
+	}
+}
+
+// registerServices registers the Gaia services on s, plus server reflection when
+// EnableReflection is set.
+func (d *Daemon) registerServices(s *grpc.Server) {
+	pb.RegisterGaiaAdminServer(s, &gaiaAdminServer{d: d})
+	pb.RegisterGaiaClientServer(s, &gaiaClientServer{daemon: d})
+	if d.config.EnableReflection {
+		reflection.Register(s)
+		log.Println("gRPC server reflection is enabled.")
+	}
+}
+
 // Restart stops and then starts the daemon.
 func (d *Daemon) Restart(ctx context.Context) error {
 	log.Println("Restarting daemon...")
 	if d.status == StatusRunning {
-		err := d.stopDaemon(ctx)
+		err := d.Stop(ctx)
 		if err != nil {
 			log.Printf("Failed to stop daemon for restart: %v", err)
 		}
@@ -195,6 +382,16 @@ func (d *Daemon) Status() string {
 	return d.status
 }
 
+// StartedAt returns when the daemon was started.
+func (d *Daemon) StartedAt() time.Time {
+	return d.createdAt
+}
+
+// Uptime returns how long the daemon has been running.
+func (d *Daemon) Uptime() time.Duration {
+	return time.Since(d.createdAt)
+}
+
 func (d *Daemon) GetConfig() *config.Config {
 	if d.config == nil {
 		return config.NewDefaultConfig()
@@ -205,7 +402,8 @@ func (d *Daemon) GetConfig() *config.Config {
 // Stop is the gRPC method for stopping the daemon.
 func (s *gaiaAdminServer) Stop(_ context.Context, _ *pb.StopRequest) (*pb.StopResponse, error) {
 	log.Println("Received stop request via gRPC. Shutting down...")
-	close(s.d.stopChannel)
+	// Stop waits for in-flight RPCs, including this one, so it must not block the handler.
+	go s.d.Stop(context.Background())
 	return &pb.StopResponse{Success: true}, nil
 }
 
@@ -214,7 +412,7 @@ func (d *Daemon) InitializeDB(passphrase string) error {
 	if _, err := os.Stat(d.config.DBFile); err == nil {
 		return errors.New("database already exists")
 	}
-	salt := make([]byte, 16)
+	salt := make([]byte, saltLen)
 	if _, err := rand.Read(salt); err != nil {
 		return err
 	}
@@ -224,30 +422,54 @@ func (d *Daemon) InitializeDB(passphrase string) error {
 	if err != nil {
 		return err
 	}
+	defer wipe(key)
+	return d.createDB(key, salt, nil)
+}
 
+// createDB creates the database file for key, storing salt, a hash of the key
+// for validation and the extra meta entries.
+func (d *Daemon) createDB(key, salt []byte, meta map[string][]byte) error {
 	// Create a hash of the key for future validation.
 	keyHash := sha256.Sum256(key)
 
+	// The default path is in a per-user data directory that may not exist yet.
+	if err := os.MkdirAll(filepath.Dir(d.config.DBFile), 0700); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
 	db, err := bbolt.Open(d.config.DBFile, 0600, nil)
 	if err != nil {
 		return err
 	}
 	err = db.Update(func(tx *bbolt.Tx) error {
-		secretsB, err := tx.CreateBucketIfNotExists([]byte(secretsBucket))
+		metaB, err := tx.CreateBucketIfNotExists([]byte(metaBucket))
 		if err != nil {
-			return fmt.Errorf("failed to create secrets bucket: %w", err)
+			return fmt.Errorf("failed to create meta bucket: %w", err)
 		}
-		if err := secretsB.Put([]byte(saltKey), salt); err != nil {
+		if err := metaB.Put([]byte(saltKey), salt); err != nil {
 			return err
 		}
-		if err := secretsB.Put([]byte(keyHashKey), keyHash[:]); err != nil {
+		if err := metaB.Put([]byte(keyHashKey), keyHash[:]); err != nil {
 			return fmt.Errorf("failed to store key hash: %w", err)
 		}
+		if err := metaB.Put([]byte(schemaVersionKey), []byte(strconv.Itoa(schemaVersion))); err != nil {
+			return fmt.Errorf("failed to store schema version: %w", err)
+		}
+		for k, v := range meta {
+			if err := metaB.Put([]byte(k), v); err != nil {
+				return fmt.Errorf("failed to store %s: %w", k, err)
+			}
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(secretsBucket)); err != nil {
+			return fmt.Errorf("failed to create secrets bucket: %w", err)
+		}
 		clientsB, err := tx.CreateBucketIfNotExists([]byte(clientsBucket))
 		if err != nil {
 			return fmt.Errorf("failed to create clients bucket: %w", err)
 		}
-		if err := clientsB.Put([]byte(commonNamespace), []byte(time.Now().UTC().Format(time.RFC3339))); err != nil {
+		if !d.config.EnableCommonNamespace {
+			return nil
+		}
+		if err := clientsB.Put([]byte(d.commonName()), []byte(time.Now().UTC().Format(time.RFC3339))); err != nil {
 			return fmt.Errorf("failed to register common client: %w", err)
 		}
 
@@ -262,27 +484,66 @@ func (d *Daemon) InitializeDB(passphrase string) error {
 
 // LockDB closes the DB and wipes the in-memory key, returning to a locked state.
 func (d *Daemon) LockDB() {
+	d.closeDB(LockReasonManual)
+	gaialog.Get().Info("Daemon is now in a locked state.")
+}
+
+// closeDB closes the database if it is open, wipes the in-memory key and
+// records reason as the cause of the lock.
+func (d *Daemon) closeDB(reason LockReason) {
 	d.dbLock.Lock()
 	defer d.dbLock.Unlock()
 
 	if d.db != nil {
+		if !d.isLocked {
+			if err := d.flushAccessLocked(); err != nil {
+				log.Printf("failed to flush secret access records: %v", err)
+			}
+		}
+		if d.config.DBNoSync {
+			// Writes were not synced as they happened, so a normal close still
+			// leaves every committed write on disk.
+			if err := d.db.Sync(); err != nil {
+				log.Printf("failed to sync database: %v", err)
+			}
+		}
 		d.db.Close()
 		d.db = nil
 	}
-	// Wipe the key from memory
-	for i := range d.key {
-		d.key[i] = 0
+	d.grants.reset()
+	d.passCheck.reset()
+	d.clearSharesLocked()
+	// Wipe the key from memory, including the copy used for the audit log
+	d.wipeKey()
+	gaialog.ClearEncryptionKey()
+	if !d.isLocked {
+		d.setLockStateLocked(true, reason)
 	}
-	d.key = nil
-	d.isLocked = true
-	gaialog.Get().Info("Daemon is now in a locked state.")
 }
 
 // UnlockDB validates the passphrase, loads the decryption key, and loads the CA credentials.
+// On a daemon that is already unlocked it returns ErrAlreadyUnlocked without
+// reopening the database.
 func (d *Daemon) UnlockDB(passphrase string) error {
+	err := d.unlockDB(passphrase)
+	if errors.Is(err, ErrAlreadyUnlocked) {
+		return err
+	}
+	if err != nil {
+		d.counters.failedUnlocks.Add(1)
+		return err
+	}
+	d.counters.unlocks.Add(1)
+	return nil
+}
+
+func (d *Daemon) unlockDB(passphrase string) error {
 	d.dbLock.Lock()
 	defer d.dbLock.Unlock()
 
+	if !d.isLocked && d.db != nil {
+		return ErrAlreadyUnlocked
+	}
 	if d.db != nil {
 		d.db.Close()
 	}
@@ -293,25 +554,23 @@ func (d *Daemon) UnlockDB(passphrase string) error {
 	}
 
 	var salt, storedHash []byte
+	var threshold int
 	err = d.db.View(func(tx *bbolt.Tx) error {
-		b := tx.Bucket([]byte(secretsBucket))
-		if b == nil {
-			return errors.New("bucket not found")
-		}
-		salt = b.Get([]byte(saltKey))
-		if salt == nil {
-			return errors.New("salt not found")
-		}
-		storedHash = b.Get([]byte(keyHashKey))
-		if storedHash == nil {
-			return errors.New("key hash not found for validation")
+		var err error
+		if salt, storedHash, err = readKeyMaterial(tx); err != nil {
+			return err
 		}
-		return nil
+		threshold, err = readUnlockThreshold(tx)
+		return err
 	})
 	if err != nil {
 		d.db.Close()
 		return err
 	}
+	if threshold > 0 {
+		d.db.Close()
+		return ErrKeySharesRequired
+	}
 
 	// Derive a key from the provided passphrase.
 	derivedKey, err := encrypt.DeriveKey([]byte(passphrase), salt)
@@ -319,27 +578,67 @@ func (d *Daemon) UnlockDB(passphrase string) error {
 		d.db.Close()
 		return err
 	}
+	if len(derivedKey) != encrypt.KeyLen {
+		d.db.Close()
+		return fmt.Errorf("derived key has length %d, want %d", len(derivedKey), encrypt.KeyLen)
+	}
 
 	// **VALIDATION STEP**
 	// Hash the derived key and compare it to the stored hash.
 	derivedKeyHash := sha256.Sum256(derivedKey)
 	if !bytes.Equal(derivedKeyHash[:], storedHash) {
+		wipe(derivedKey)
 		d.db.Close()
-		return errors.New("invalid passphrase")
+		return ErrInvalidPassphrase
 	}
 
 	// If validation passes, store the key and proceed.
-	d.key = derivedKey
+	return d.completeUnlockLocked(derivedKey, salt)
+}
+
+// completeUnlockLocked finishes an unlock with a validated key: it migrates and
+// optionally repairs the database, loads the CA credentials and marks the
+// daemon unlocked. The caller must hold dbLock for writing and have opened the
+// database.
+func (d *Daemon) completeUnlockLocked(key, salt []byte) error {
+	d.setKey(key)
+
+	var migrated bool
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		migrated, err = migrateSchema(tx)
+		return err
+	})
+	if err != nil {
+		d.db.Close()
+		d.db = nil
+		d.wipeKey()
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if migrated {
+		gaialog.Get().Info("database migrated", slog.Int("schema_version", schemaVersion))
+	}
+
+	if d.config.RepairOnUnlock {
+		if _, err := d.verifyAndRepairLocked(); err != nil {
+			d.db.Close()
+			d.db = nil
+			d.wipeKey()
+			return fmt.Errorf("failed to repair database: %w", err)
+		}
+	}
 
 	if err := d.loadCACredentials(); err != nil {
 		d.db.Close()
 		d.db = nil
-		d.key = nil
+		d.wipeKey()
 		return fmt.Errorf("failed to load CA credentials: %w", err)
 	}
 
-	d.isLocked = false
-	d.status = StatusRunning
+	if d.config.EncryptAuditLog {
+		gaialog.SetEncryptionKey(d.key, salt)
+	}
+	d.setLockStateLocked(false, "")
 	gaialog.Get().Info("Daemon is now unlocked.")
 	return nil
 }
@@ -352,12 +651,19 @@ func (d *Daemon) RegisterClient(clientName string) error {
 	if d.isLocked || d.db == nil {
 		return errors.New("daemon is in a locked state, cannot register clients")
 	}
+	if err := d.checkClientName(clientName); err != nil {
+		return err
+	}
 
 	err := d.db.Update(func(tx *bbolt.Tx) error {
 		b, err := tx.CreateBucketIfNotExists([]byte(clientsBucket))
 		if err != nil {
 			return fmt.Errorf("failed to create or get clients bucket: %w", err)
 		}
+		// Never overwrite an existing registration and its creation time.
+		if b.Get([]byte(clientName)) != nil {
+			return fmt.Errorf("%w: '%s'", ErrClientExists, clientName)
+		}
 		return b.Put([]byte(clientName), []byte(time.Now().UTC().Format(time.RFC3339)))
 	})
 
@@ -367,6 +673,34 @@ func (d *Daemon) RegisterClient(clientName string) error {
 	return err
 }
 
+// GetClient returns a registered client, or ErrClientNotFound.
+func (d *Daemon) GetClient(clientName string) (*Client, error) {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked || d.db == nil {
+		return nil, errors.New("daemon is in a locked state, cannot read clients")
+	}
+
+	var client *Client
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(clientsBucket))
+		if b == nil {
+			return fmt.Errorf("%w: '%s'", ErrClientNotFound, clientName)
+		}
+		v := b.Get([]byte(clientName))
+		if v == nil {
+			return fmt.Errorf("%w: '%s'", ErrClientNotFound, clientName)
+		}
+		client = &Client{Name: clientName, TimeCreated: string(v)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
 // ListClients returns a list of all registered clients.
 func (d *Daemon) ListClients() ([]Client, error) {
 	d.dbLock.RLock()
@@ -401,202 +735,627 @@ func (d *Daemon) ListClients() ([]Client, error) {
 	return clients, nil
 }
 
-// RevokeClient removes a client's registration and all of its associated secrets.
-func (d *Daemon) RevokeClient(clientName string) error {
+// ImportClients restores client registrations with their creation times, as
+// listed by ListClients, in one transaction. Clients that are already
+// registered are left untouched and counted as skipped unless overwrite is set.
+func (d *Daemon) ImportClients(clients []Client, overwrite bool) (imported, skipped int, err error) {
 	d.dbLock.Lock()
 	defer d.dbLock.Unlock()
 
 	if d.isLocked || d.db == nil {
-		return errors.New("daemon is in a locked state, cannot revoke clients")
+		return 0, 0, errors.New("daemon is in a locked state, cannot register clients")
 	}
 
-	return d.db.Update(func(tx *bbolt.Tx) error {
-		clientsB := tx.Bucket([]byte(clientsBucket))
-		if clientsB != nil {
-			if err := clientsB.Delete([]byte(clientName)); err != nil {
-				return fmt.Errorf("failed to delete client from registry: %w", err)
-			}
-		}
-		secretsB := tx.Bucket([]byte(secretsBucket))
-		if secretsB == nil {
-			return nil // No secrets bucket, so nothing to delete.
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(clientsBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create or get clients bucket: %w", err)
 		}
-
-		prefix := []byte(clientName + "\x00")
-		c := secretsB.Cursor()
-		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
-			if err := c.Delete(); err != nil {
-				log.Printf("error deleting secret %s for revoked client %s: %v", string(k), clientName, err)
+		for _, c := range clients {
+			if !overwrite && b.Get([]byte(c.Name)) != nil {
+				skipped++
+				continue
+			}
+			if err := b.Put([]byte(c.Name), []byte(c.TimeCreated)); err != nil {
+				return fmt.Errorf("failed to register client '%s': %w", c.Name, err)
 			}
+			imported++
 		}
-
 		return nil
 	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	gaialog.Get().Info("clients imported", slog.Int("count", imported), slog.Int("skipped", skipped))
+	return imported, skipped, nil
 }
 
-// ListNamespaces retrieves all unique namespaces associated with a given client.
-func (d *Daemon) ListNamespaces(clientName string) ([]string, error) {
+// RevokeImpact describes the data a client revocation removes.
+type RevokeImpact struct {
+	Registered  bool
+	Namespaces  []string
+	SecretCount int
+}
+
+// DescribeRevoke reports what RevokeClient would remove for clientName without
+// modifying the database.
+func (d *Daemon) DescribeRevoke(clientName string) (*RevokeImpact, error) {
 	d.dbLock.RLock()
 	defer d.dbLock.RUnlock()
 
 	if d.isLocked || d.db == nil {
-		return nil, errors.New("daemon is in a locked state, cannot list namespaces")
+		return nil, errors.New("daemon is in a locked state, cannot describe clients")
 	}
 
-	namespaceSet := make(map[string]struct{})
-	prefix := []byte(clientName + "\x00")
-
+	var impact *RevokeImpact
 	err := d.db.View(func(tx *bbolt.Tx) error {
-		b := tx.Bucket([]byte(secretsBucket))
-		if b == nil {
-			return nil // No secrets, so no namespaces.
-		}
-
-		c := b.Cursor()
-		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
-			// The key format is clientName\x00namespace\x00id
-			// We need to extract the 'namespace' part.
-			trimmedKey := bytes.TrimPrefix(k, prefix)
-			parts := bytes.SplitN(trimmedKey, []byte("\x00"), 2)
-			if len(parts) > 0 {
-				namespaceSet[string(parts[0])] = struct{}{}
-			}
-		}
+		impact = revokeImpact(tx, clientName)
 		return nil
 	})
-
 	if err != nil {
-		return nil, fmt.Errorf("failed to list namespaces for client '%s': %w", clientName, err)
+		return nil, err
 	}
+	return impact, nil
+}
 
-	// Convert the set of namespaces to a slice.
-	namespaces := make([]string, 0, len(namespaceSet))
-	for ns := range namespaceSet {
-		namespaces = append(namespaces, ns)
+// revokeImpact collects the registration state, namespaces, and secret count of a
+// client within a transaction.
+func revokeImpact(tx *bbolt.Tx, clientName string) *RevokeImpact {
+	impact := &RevokeImpact{}
+	if clientsB := tx.Bucket([]byte(clientsBucket)); clientsB != nil {
+		impact.Registered = clientsB.Get([]byte(clientName)) != nil
 	}
-
-	return namespaces, nil
+	impact.Namespaces = secretNamespaces(tx, clientName)
+	for _, ns := range impact.Namespaces {
+		impact.SecretCount += countSecrets(namespaceBucket(tx, clientName, ns))
+	}
+	return impact
 }
 
-// AddSecret stores an encrypted secret for a specific client and namespace.
-func (d *Daemon) AddSecret(clientName, namespace, id, value string) error {
-	d.dbLock.RLock()
-	defer d.dbLock.RUnlock()
+// RevokeClient removes a client's registration and all of its associated secrets,
+// returning what was removed.
+func (d *Daemon) RevokeClient(clientName string) (*RevokeImpact, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
 
 	if d.isLocked || d.db == nil {
-		return errors.New("daemon is in a locked state, cannot write secrets")
+		return nil, errors.New("daemon is in a locked state, cannot revoke clients")
 	}
 
-	key := constructDBKey(clientName, namespace, id)
-
-	encValue, err := encrypt.Encrypt(d.key, []byte(value))
+	var impact *RevokeImpact
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		impact = revokeImpact(tx, clientName)
+		if err := deleteClientRecords(tx, clientName); err != nil {
+			return err
+		}
+		return deleteClientSecrets(tx, clientName)
+	})
+	d.grants.invalidate(clientName)
 	if err != nil {
-		return fmt.Errorf("failed to encrypt secret: %w", err)
+		return nil, err
 	}
+	return impact, nil
+}
 
-	err = d.db.Update(func(tx *bbolt.Tx) error {
-		b, err := tx.CreateBucketIfNotExists([]byte(secretsBucket))
-		if err != nil {
-			return fmt.Errorf("failed to create or get bucket: %w", err)
+// deleteClientRecords removes the registration of clientName and everything
+// kept alongside it: common grants, namespace patterns, namespace TTLs and the
+// certificate record. Its secrets are left alone.
+func deleteClientRecords(tx *bbolt.Tx, clientName string) error {
+	if clientsB := tx.Bucket([]byte(clientsBucket)); clientsB != nil {
+		if err := clientsB.Delete([]byte(clientName)); err != nil {
+			return fmt.Errorf("failed to delete client from registry: %w", err)
 		}
-		return b.Put(key, []byte(encValue))
-	})
-
-	if err == nil {
-		gaialog.Get().Info("secret added/updated",
-			slog.String("client_name", clientName),
-			slog.String("namespace", namespace),
-			slog.String("id", id),
-		)
 	}
-	return err
+	if err := deleteCommonGrants(tx, clientName); err != nil {
+		return fmt.Errorf("failed to delete common grants: %w", err)
+	}
+	if err := deleteNamespacePatterns(tx, clientName); err != nil {
+		return fmt.Errorf("failed to delete namespace patterns: %w", err)
+	}
+	if err := deleteNamespaceTTLs(tx, clientName); err != nil {
+		return fmt.Errorf("failed to delete namespace TTLs: %w", err)
+	}
+	if err := deleteNamespaceRotations(tx, clientName); err != nil {
+		return fmt.Errorf("failed to delete namespace rotation settings: %w", err)
+	}
+	if certsB := tx.Bucket([]byte(clientCertsBucket)); certsB != nil {
+		if err := certsB.Delete([]byte(clientName)); err != nil {
+			return fmt.Errorf("failed to delete certificate record: %w", err)
+		}
+	}
+	return nil
 }
 
-// GetSecret retrieves and decrypts a secret, enforcing authorization.
-func (d *Daemon) GetSecret(clientName, namespace, id string) (string, error) {
-	d.dbLock.RLock()
-	defer d.dbLock.RUnlock()
-
-	if d.isLocked {
-		return "", errors.New("daemon is locked")
+// deleteClientSecrets removes every secret of clientName with its format, tag,
+// expiry and access records.
+func deleteClientSecrets(tx *bbolt.Tx, clientName string) error {
+	if clientSecretsBucket(tx, clientName) != nil {
+		if err := tx.Bucket([]byte(secretsBucket)).DeleteBucket([]byte(clientName)); err != nil {
+			return fmt.Errorf("failed to delete secrets of client '%s': %w", clientName, err)
+		}
 	}
 
-	if d.db == nil {
-		return "", errors.New("database not open")
+	prefix := keyPrefix(clientName)
+	if err := deleteSecretFormats(tx, prefix); err != nil {
+		return err
 	}
-
-	// Authorization: A client can access its own namespace or the "common" namespace.
-	if namespace != commonNamespace && namespace != clientName {
-		return "", fmt.Errorf("permission denied: client '%s' is not authorized for namespace '%s'", clientName, namespace)
+	if err := deleteAllSecretTags(tx, prefix); err != nil {
+		return err
 	}
-
-	// Secrets in the common namespace are stored under the 'common' client name.
-	// Otherwise, they are stored under the requesting client's name.
-	var lookupClient string
-	if namespace == commonNamespace {
-		lookupClient = commonNamespace
-	} else {
-		lookupClient = clientName
+	if err := deleteAllSecretExpiries(tx, prefix); err != nil {
+		return err
 	}
+	return deleteAccessRecords(tx, prefix)
+}
 
-	key := constructDBKey(lookupClient, namespace, id)
+// ListNamespaces retrieves all unique namespaces associated with a given client.
+func (d *Daemon) ListNamespaces(clientName string) ([]string, error) {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
 
-	var encValue []byte
+	if d.isLocked || d.db == nil {
+		return nil, errors.New("daemon is in a locked state, cannot list namespaces")
+	}
+
+	var namespaces []string
 	err := d.db.View(func(tx *bbolt.Tx) error {
-		b := tx.Bucket([]byte(secretsBucket))
-		if b == nil {
-			return errors.New("bucket not found")
-		}
-		encValue = b.Get(key)
-		if encValue == nil {
-			return errors.New("secret not found")
-		}
+		namespaces = secretNamespaces(tx, clientName)
 		return nil
 	})
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to list namespaces for client '%s': %w", clientName, err)
 	}
-
-	decValue, err := encrypt.Decrypt(d.key, string(encValue))
-	if err != nil {
-		gaialog.Get().Error("secret failed to decrypt",
-			"client", clientName,
-			"namespace", namespace,
-			"id", id,
-		)
-		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	if namespaces == nil {
+		namespaces = []string{}
 	}
-
-	gaialog.Get().Info("secret accessed",
-		slog.String("client_name", clientName),
-		slog.String("namespace", namespace),
-		slog.String("id", id),
-	)
-	return string(decValue), nil
+	return namespaces, nil
 }
 
-// DeleteSecret removes a specific secret from the database.
-func (d *Daemon) DeleteSecret(clientName, namespace, id string) error {
-	d.dbLock.Lock()
-	defer d.dbLock.Unlock()
+// encryptValue encrypts a secret value for storage in the configured ciphertext
+// encoding. Values already stored in the other encoding still decrypt.
+func (d *Daemon) encryptValue(plaintext []byte) ([]byte, error) {
+	return encrypt.EncryptValue(d.key, plaintext, d.config.CiphertextEncoding)
+}
 
-	if d.isLocked || d.db == nil {
-		return errors.New("daemon is in a locked state, cannot delete secrets")
+// encryptFields encrypts a secret value with key, only at fields when set and as
+// a whole in the configured ciphertext encoding otherwise.
+func (d *Daemon) encryptFields(key, plaintext []byte, fields []string) ([]byte, error) {
+	if len(fields) > 0 {
+		return encrypt.EncryptFields(key, plaintext, fields)
 	}
+	return encrypt.EncryptValue(key, plaintext, d.config.CiphertextEncoding)
+}
 
-	key := constructDBKey(clientName, namespace, id)
+// AddSecretOptions are the optional settings of AddSecret. The zero value
+// keeps the format constraint, tags and encrypted fields of an existing secret
+// and applies the default TTL of the namespace.
+type AddSecretOptions struct {
+	// Format sets the format constraint of the secret: the value must parse in
+	// Format, which is enforced on every later write. Empty keeps the current
+	// constraint and FormatNone removes it.
+	Format string
+	// Tags replace the tags of the secret. Empty keeps the current ones.
+	Tags map[string]string
+	// EncryptFields, when set, require the value to be a JSON object and encrypt
+	// only the fields at those paths, so SearchSecrets can match on the others.
+	// Empty keeps the fields of a structured secret encrypted, and stores any
+	// other secret encrypted as a whole.
+	EncryptFields []string
+	// TTL makes the secret expire TTL after the write. Zero applies the default
+	// TTL of the namespace, and without one the secret never expires.
+	TTL time.Duration
+}
 
-	err := d.db.Update(func(tx *bbolt.Tx) error {
-		b := tx.Bucket([]byte(secretsBucket))
-		if b == nil {
-			// If the bucket doesn't exist, the secret can't exist either.
+// AddSecret stores an encrypted secret for a specific client and namespace.
+// The value must match the format constraint of the secret, if it has one.
+// Expired secrets are not found by GetSecret and are deleted by the expiry
+// sweeper. The returned warnings describe problems that did not stop the write:
+// a namespace clientName cannot read, and each warn value rule the value
+// matches.
+func (d *Daemon) AddSecret(clientName, namespace, id, value string, opts AddSecretOptions) ([]string, error) {
+	if err := validateTags(opts.Tags); err != nil {
+		return nil, err
+	}
+	if opts.TTL < 0 {
+		return nil, fmt.Errorf("%w: secret TTL must not be negative", ErrInvalidTTL)
+	}
+
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked || d.db == nil {
+		return nil, errors.New("daemon is in a locked state, cannot write secrets")
+	}
+
+	var warnings []string
+	warning, err := d.checkWriteNamespace(clientName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if warning != "" {
+		warnings = append(warnings, warning)
+		gaialog.Get().Warn("secret written to unreachable namespace",
+			slog.String("client_name", clientName),
+			slog.String("namespace", namespace),
+			slog.String("id", id),
+		)
+	}
+	valueWarnings, err := d.checkValues([]*pb.ImportSecretItem{{ClientName: clientName, Namespace: namespace, Id: id, Value: value}})
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range valueWarnings {
+		warnings = append(warnings, fmt.Sprintf("value matches rule '%s'", w.Rule))
+	}
+
+	key := constructDBKey(clientName, namespace, id)
+
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		if err := checkNamespaceAllowed(tx, clientName, namespace); err != nil {
+			return err
+		}
+		if err := checkSecretFormat(tx, key, id, value, opts.Format); err != nil {
+			return err
+		}
+		if err := d.checkQuota(tx, clientName, namespace, id); err != nil {
+			return err
+		}
+		if len(opts.Tags) > 0 {
+			if err := putSecretTags(tx, key, opts.Tags); err != nil {
+				return err
+			}
+		}
+		fields := opts.EncryptFields
+		if len(fields) == 0 {
+			fields = encrypt.EncryptedFields(getSecretValue(tx, clientName, namespace, id))
+		}
+		encValue, err := d.encryptFields(d.key, []byte(value), fields)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt secret: %w", err)
+		}
+		if err := setSecretExpiry(tx, clientName, namespace, id, opts.TTL, time.Now()); err != nil {
+			return err
+		}
+		return putSecretValue(tx, clientName, namespace, id, encValue)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.counters.secretsWritten.Add(1)
+	logValueWarnings(valueWarnings)
+	gaialog.Get().Info("secret added/updated",
+		slog.String("client_name", clientName),
+		slog.String("namespace", namespace),
+		slog.String("id", id),
+	)
+	return warnings, nil
+}
+
+// CreateSecretIfAbsent stores value under id in clientName's own namespace unless
+// the secret already exists, and returns the stored value together with whether
+// it was created. The check and the write run in one transaction, so concurrent
+// callers all receive the value of whichever write came first. An expired
+// secret counts as absent, and a created secret gets the default TTL of the
+// namespace.
+func (d *Daemon) CreateSecretIfAbsent(clientName, namespace, id, value string) (string, bool, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return "", false, errors.New("daemon is in a locked state, cannot write secrets")
+	}
+	if namespace != clientName {
+		d.counters.accessDenied.Add(1)
+		return "", false, fmt.Errorf("%w: client '%s' can only create secrets in its own namespace", ErrPermissionDenied, clientName)
+	}
+
+	encValue, err := d.encryptValue([]byte(value))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	key := constructDBKey(clientName, namespace, id)
+	var existing []byte
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		if err := checkNamespaceAllowed(tx, clientName, namespace); err != nil {
+			return err
+		}
+		now := time.Now()
+		if v := getSecretValue(tx, clientName, namespace, id); v != nil {
+			expired, err := secretExpired(tx, key, now)
+			if err != nil {
+				return err
+			}
+			if !expired {
+				existing = bytes.Clone(v)
+				return nil
+			}
+		}
+		if err := d.checkQuota(tx, clientName, namespace, id); err != nil {
+			return err
+		}
+		if err := setSecretExpiry(tx, clientName, namespace, id, 0, now); err != nil {
+			return err
+		}
+		return putSecretValue(tx, clientName, namespace, id, encValue)
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	if existing != nil {
+		decValue, err := encrypt.DecryptValue(d.key, existing)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to decrypt secret: %w", err)
+		}
+		d.counters.secretsServed.Add(1)
+		d.recordAccess(key)
+		return string(decValue), false, nil
+	}
+
+	d.counters.secretsWritten.Add(1)
+	gaialog.Get().Info("secret created by client",
+		slog.String("client_name", clientName),
+		slog.String("namespace", namespace),
+		slog.String("id", id),
+	)
+	return value, true, nil
+}
+
+// GetSecret retrieves and decrypts a secret, enforcing authorization.
+func (d *Daemon) GetSecret(clientName, namespace, id string) (string, error) {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked {
+		return "", errors.New("daemon is locked")
+	}
+
+	if d.db == nil {
+		return "", errors.New("database not open")
+	}
+
+	// Authorization: A client can access its own namespace, granted namespaces or,
+	// when enabled, the "common" namespace.
+	isCommon := d.config.EnableCommonNamespace && namespace == d.commonName()
+	if !d.canReadNamespace(clientName, namespace) {
+		d.counters.accessDenied.Add(1)
+		return "", fmt.Errorf("%w: client '%s' is not authorized for namespace '%s'", ErrPermissionDenied, clientName, namespace)
+	}
+
+	// Secrets in the common namespace are stored under the 'common' client name.
+	// Otherwise, they are stored under the requesting client's name.
+	var lookupClient string
+	if isCommon {
+		lookupClient = d.commonName()
+	} else {
+		lookupClient = clientName
+	}
+
+	key := constructDBKey(lookupClient, namespace, id)
+
+	var encValue []byte
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		if isCommon && !canReadCommon(d.grants.get(tx, clientName), namespace) {
+			d.counters.accessDenied.Add(1)
+			return fmt.Errorf("%w: client '%s' is not authorized for common namespace '%s'", ErrPermissionDenied, clientName, namespace)
+		}
+		if tx.Bucket([]byte(secretsBucket)) == nil {
+			return errors.New("bucket not found")
+		}
+		encValue = getSecretValue(tx, lookupClient, namespace, id)
+		if encValue == nil {
+			return ErrSecretNotFound
+		}
+		// An expired secret is gone even before the sweeper deletes it.
+		expired, err := secretExpired(tx, key, time.Now())
+		if err != nil {
+			return err
+		}
+		if expired {
+			return ErrSecretNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	decValue, err := encrypt.DecryptValue(d.key, encValue)
+	if err != nil {
+		gaialog.Get().Error("secret failed to decrypt",
+			"client", clientName,
+			"namespace", namespace,
+			"id", id,
+		)
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	d.counters.secretsServed.Add(1)
+	d.recordAccess(key)
+	gaialog.Get().Info("secret accessed",
+		slog.String("client_name", clientName),
+		slog.String("namespace", namespace),
+		slog.String("id", id),
+	)
+	return string(decValue), nil
+}
+
+// GetCommonSecrets returns the secrets of the common area grouped by namespace,
+// limited to the common namespaces clientName has been granted. Clients without
+// grants may read every common namespace. If namespace is not empty, only that
+// namespace is returned. Expired secrets are left out.
+func (d *Daemon) GetCommonSecrets(clientName, namespace string) (map[string]map[string]string, error) {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked {
+		return nil, errors.New("daemon is locked")
+	}
+
+	if d.db == nil {
+		return nil, errors.New("database not open")
+	}
+
+	if !d.config.EnableCommonNamespace {
+		return nil, errors.New("the common namespace is disabled")
+	}
+
+	commonSecrets := make(map[string]map[string]string)
+
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		grants := d.grants.get(tx, clientName)
+		if namespace != "" && !canReadCommon(grants, namespace) {
+			d.counters.accessDenied.Add(1)
+			return fmt.Errorf("%w: client '%s' is not authorized for common namespace '%s'", ErrPermissionDenied, clientName, namespace)
+		}
+
+		return forEachSecret(tx, d.commonName(), namespace, skipExpired(tx, time.Now(), func(_, ns, id string, v []byte) error {
+			if !canReadCommon(grants, ns) {
+				return nil
+			}
+
+			decryptedValue, err := encrypt.DecryptValue(d.key, v)
+			if err != nil {
+				gaialog.Get().Warn("failed to decrypt secret, skipping", "namespace", ns, "id", id, "error", err)
+				return nil
+			}
+
+			if _, ok := commonSecrets[ns]; !ok {
+				commonSecrets[ns] = make(map[string]string)
+			}
+			commonSecrets[ns][id] = string(decryptedValue)
+			d.counters.secretsServed.Add(1)
 			return nil
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gaialog.Get().Info("common secrets accessed",
+		slog.String("client_name", clientName),
+		slog.String("namespace", namespace),
+	)
+	return commonSecrets, nil
+}
+
+// SetCommonGrants replaces the set of common namespaces clientName may read. An
+// empty list removes the restriction.
+func (d *Daemon) SetCommonGrants(clientName string, namespaces []string) error {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return errors.New("daemon is in a locked state, cannot change grants")
+	}
+
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(commonGrantsBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create or get bucket: %w", err)
+		}
+		if err := deleteCommonGrants(tx, clientName); err != nil {
+			return err
+		}
+		for _, ns := range namespaces {
+			if err := b.Put(append(keyPrefix(clientName), escapeKeyPart(ns)...), []byte{}); err != nil {
+				return err
+			}
 		}
-		// b.Delete does not return an error if the key does not exist.
-		return b.Delete(key)
+		return nil
 	})
+	d.grants.invalidate(clientName)
 
 	if err == nil {
+		gaialog.Get().Info("common grants updated",
+			slog.String("client_name", clientName),
+			slog.String("namespaces", strings.Join(namespaces, ",")),
+		)
+	}
+	return err
+}
+
+// VerifyPassphrase checks passphrase against the stored key hash without changing
+// the daemon's state. It is used to confirm destructive operations. A
+// passphrase verified within Config.PassphraseCheckCacheTTL is accepted
+// without deriving the key again.
+func (d *Daemon) VerifyPassphrase(passphrase string) error {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked || d.db == nil {
+		return errors.New("daemon is in a locked state, cannot verify passphrase")
+	}
+
+	now := time.Now()
+	if d.passCheck.matches(d.key, passphrase, now) {
+		return nil
+	}
+
+	var salt, storedHash []byte
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		salt, storedHash, err = readKeyMaterial(tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	derivedKey, err := encrypt.DeriveKey([]byte(passphrase), salt)
+	if err != nil {
+		return err
+	}
+	defer wipe(derivedKey)
+	derivedKeyHash := sha256.Sum256(derivedKey)
+	if subtle.ConstantTimeCompare(derivedKeyHash[:], storedHash) != 1 {
+		return ErrInvalidPassphrase
+	}
+	if ttl := d.config.PassphraseCheckCacheTTL; ttl > 0 {
+		d.passCheck.store(d.key, passphrase, now.Add(ttl))
+	}
+	return nil
+}
+
+// DeleteSecret removes a specific secret from the database.
+func (d *Daemon) DeleteSecret(clientName, namespace, id string) error {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return errors.New("daemon is in a locked state, cannot delete secrets")
+	}
+
+	key := constructDBKey(clientName, namespace, id)
+
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		// Deleting a secret that does not exist is not an error.
+		if err := deleteSecretValue(tx, clientName, namespace, id); err != nil {
+			return err
+		}
+		if formatsB := tx.Bucket([]byte(secretFormatsBucket)); formatsB != nil {
+			if err := formatsB.Delete(key); err != nil {
+				return err
+			}
+		}
+		if err := deleteSecretTags(tx, key); err != nil {
+			return err
+		}
+		if err := deleteSecretExpiry(tx, key); err != nil {
+			return err
+		}
+		if accessB := tx.Bucket([]byte(secretAccessBucket)); accessB != nil {
+			return accessB.Delete(key)
+		}
+		return nil
+	})
+
+	if err == nil {
+		d.counters.secretsDeleted.Add(1)
 		gaialog.Get().Info("secret deleted successfully",
 			slog.String("client_name", clientName),
 			slog.String("namespace", namespace),
@@ -607,7 +1366,24 @@ func (d *Daemon) DeleteSecret(clientName, namespace, id string) error {
 }
 
 // ListSecrets retrieves all namespaces and their secrets for a given client.
-func (d *Daemon) ListSecrets(clientName string) (map[string]map[string]string, error) {
+// The scan stops early if ctx is cancelled, since decrypting a large client is costly.
+func (d *Daemon) ListSecrets(ctx context.Context, clientName string) (map[string]map[string]string, error) {
+	return d.listSecrets(ctx, clientName, "")
+}
+
+// ListNamespaceSecrets retrieves the secrets of one namespace of a client, so a
+// large client can be read a namespace at a time.
+func (d *Daemon) ListNamespaceSecrets(ctx context.Context, clientName, namespace string) (map[string]string, error) {
+	secrets, err := d.listSecrets(ctx, clientName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return secrets[namespace], nil
+}
+
+// listSecrets retrieves the secrets of clientName grouped by namespace, only of
+// namespace when it is set.
+func (d *Daemon) listSecrets(ctx context.Context, clientName, namespace string) (map[string]map[string]string, error) {
 	d.dbLock.RLock()
 	defer d.dbLock.RUnlock()
 
@@ -616,34 +1392,20 @@ func (d *Daemon) ListSecrets(clientName string) (map[string]map[string]string, e
 	}
 
 	allSecrets := make(map[string]map[string]string)
-	prefix := []byte(clientName + "\x00")
 
 	err := d.db.View(func(tx *bbolt.Tx) error {
-		c := tx.Bucket([]byte(secretsBucket)).Cursor()
-
-		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
-			parts := strings.SplitN(string(k), "\x00", 3)
-			if len(parts) != 3 {
-				continue // Skip malformed keys
-			}
-			// parts[0] is clientName, parts[1] is namespace, parts[2] is key
-
-			namespace := parts[1]
-			secretKey := parts[2]
-
-			decryptedValue, err := encrypt.Decrypt(d.key, string(v))
+		return d.decryptSecrets(ctx, tx, clientName, namespace, func(_, namespace, secretKey string, value []byte, err error) error {
 			if err != nil {
 				// Log the error but continue, so one bad secret doesn't fail the whole list
-				gaialog.Get().Warn("failed to decrypt secret, skipping", "key", string(k), "error", err)
-				continue
+				gaialog.Get().Warn("failed to decrypt secret, skipping", "namespace", namespace, "id", secretKey, "error", err)
+				return nil
 			}
-
 			if _, ok := allSecrets[namespace]; !ok {
 				allSecrets[namespace] = make(map[string]string)
 			}
-			allSecrets[namespace][secretKey] = string(decryptedValue)
-		}
-		return nil
+			allSecrets[namespace][secretKey] = string(value)
+			return nil
+		})
 	})
 
 	if err != nil {
@@ -653,93 +1415,638 @@ func (d *Daemon) ListSecrets(clientName string) (map[string]map[string]string, e
 	return allSecrets, nil
 }
 
-// ImportSecrets performs a bulk, transactional import of secrets.
-func (d *Daemon) ImportSecrets(secrets []*pb.ImportSecretItem, overwrite bool) (int, error) {
+// decryptSecrets calls fn with the decrypted value of every secret of client, or
+// of every client if client is empty, or with the decryption error. A namespace
+// limits it to the secrets of that namespace. Expired secrets are skipped. The
+// caller must hold dbLock and the daemon must be unlocked.
+func (d *Daemon) decryptSecrets(ctx context.Context, tx *bbolt.Tx, client, namespace string, fn func(client, namespace, id string, value []byte, err error) error) error {
+	return forEachSecret(tx, client, namespace, skipExpired(tx, time.Now(), func(client, namespace, id string, v []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		value, err := encrypt.DecryptValue(d.key, v)
+		return fn(client, namespace, id, value, err)
+	}))
+}
+
+// ImportMode controls how ImportSecrets treats secrets that already exist.
+type ImportMode int
+
+const (
+	// ImportFailOnConflict aborts the whole import if any secret already exists.
+	// Expired secrets that have not been swept yet count as absent.
+	ImportFailOnConflict ImportMode = iota
+	// ImportOverwrite replaces existing secrets.
+	ImportOverwrite
+	// ImportMerge only adds missing secrets and leaves existing ones untouched.
+	ImportMerge
+)
+
+// ImportResult reports the outcome of ImportSecrets.
+type ImportResult struct {
+	// Imported is the number of secrets written.
+	Imported int
+	// Skipped is the number of existing secrets left untouched by ImportMerge.
+	Skipped int
+	// Replayed is set when the batch ID had already been applied. Nothing was
+	// written and the counts are those of the original import.
+	Replayed bool
+	// ValueWarnings lists the imported secrets whose value matched a warn value
+	// rule.
+	ValueWarnings []ValueViolation
+}
+
+// ImportSecrets performs a bulk, transactional import of secrets. When batchID is
+// not empty, the result is recorded with it and a later import with the same
+// batch ID is not applied again, so interrupted imports can be retried safely.
+// Like AddSecret without a TTL, every imported secret gets the default TTL of
+// its namespace, if it has one.
+func (d *Daemon) ImportSecrets(secrets []*pb.ImportSecretItem, mode ImportMode, batchID string) (ImportResult, error) {
 	d.dbLock.Lock()
 	defer d.dbLock.Unlock()
 
 	if d.isLocked || d.db == nil {
-		return 0, errors.New("daemon is in a locked state, cannot import secrets")
+		return ImportResult{}, errors.New("daemon is in a locked state, cannot import secrets")
 	}
 
-	var importedCount int
-	err := d.db.Update(func(tx *bbolt.Tx) error {
-		secretsB, err := tx.CreateBucketIfNotExists([]byte(secretsBucket))
-		if err != nil {
-			return fmt.Errorf("failed to get secrets bucket: %w", err)
+	// Values are checked up front so a rejected value fails the import before
+	// anything is written.
+	valueWarnings, err := d.checkValues(secrets)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	var result ImportResult
+	var importedCount, skippedCount int
+	now := time.Now().UTC()
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		if batchID != "" {
+			prior, err := lookupImportBatch(tx, batchID, now)
+			if err != nil {
+				return err
+			}
+			if prior != nil {
+				result = ImportResult{Imported: prior.Imported, Skipped: prior.Skipped, Replayed: true}
+				return nil
+			}
 		}
 
 		for _, secret := range secrets {
 			key := constructDBKey(secret.ClientName, secret.Namespace, secret.Id)
 
-			// If not overwriting, check if the secret already exists.
-			if !overwrite && secretsB.Get(key) != nil {
-				return fmt.Errorf("secret '%s' already exists. Use --overwrite to replace it", key)
+			if err := checkNamespaceAllowed(tx, secret.ClientName, secret.Namespace); err != nil {
+				return err
+			}
+			warning, err := d.checkWriteNamespace(secret.ClientName, secret.Namespace)
+			if err != nil {
+				return err
+			}
+			if warning != "" {
+				gaialog.Get().Warn("secret imported to unreachable namespace",
+					slog.String("client_name", secret.ClientName),
+					slog.String("namespace", secret.Namespace),
+					slog.String("id", secret.Id),
+				)
+			}
+
+			exists, err := secretExists(tx, secret.ClientName, secret.Namespace, secret.Id, now)
+			if err != nil {
+				return err
+			}
+			if exists {
+				switch mode {
+				case ImportMerge:
+					skippedCount++
+					continue
+				case ImportFailOnConflict:
+					return fmt.Errorf("%w: '%s/%s/%s'", ErrSecretExists, secret.ClientName, secret.Namespace, secret.Id)
+				}
 			}
 
-			encValue, err := encrypt.Encrypt(d.key, []byte(secret.Value))
+			if err := checkSecretFormat(tx, key, secret.Id, secret.Value, ""); err != nil {
+				return err
+			}
+			if err := d.checkQuota(tx, secret.ClientName, secret.Namespace, secret.Id); err != nil {
+				return err
+			}
+			// An overwritten structured secret keeps the same fields encrypted.
+			existing := getSecretValue(tx, secret.ClientName, secret.Namespace, secret.Id)
+			encValue, err := d.encryptFields(d.key, []byte(secret.Value), encrypt.EncryptedFields(existing))
 			if err != nil {
 				// Failing here will roll back the entire transaction.
-				return fmt.Errorf("failed to encrypt secret %s: %w", key, err)
+				return fmt.Errorf("failed to encrypt secret '%s/%s/%s': %w", secret.ClientName, secret.Namespace, secret.Id, err)
 			}
 
-			if err := secretsB.Put(key, []byte(encValue)); err != nil {
-				return fmt.Errorf("failed to write secret %s to db: %w", key, err)
+			if err := setSecretExpiry(tx, secret.ClientName, secret.Namespace, secret.Id, 0, now); err != nil {
+				return err
+			}
+			if err := putSecretValue(tx, secret.ClientName, secret.Namespace, secret.Id, encValue); err != nil {
+				return fmt.Errorf("failed to write secret '%s/%s/%s' to db: %w", secret.ClientName, secret.Namespace, secret.Id, err)
 			}
 			importedCount++
 		}
+		result = ImportResult{Imported: importedCount, Skipped: skippedCount}
+		if batchID != "" {
+			return recordImportBatch(tx, batchID, importBatch{Imported: importedCount, Skipped: skippedCount, CompletedAt: now})
+		}
 		return nil
 	})
 
 	if err != nil {
-		return 0, err
+		return ImportResult{}, err
+	}
+	if result.Replayed {
+		gaialog.Get().Info("import batch already applied, skipping", slog.String("batch_id", batchID))
+		return result, nil
 	}
 
-	gaialog.Get().Info("bulk secrets imported", slog.Int("count", importedCount))
-	log.Printf("Bulk secrets imported successfully, imported %d secrets", importedCount)
-	return importedCount, nil
+	result.ValueWarnings = valueWarnings
+	logValueWarnings(valueWarnings)
+	d.counters.secretsWritten.Add(uint64(importedCount))
+	gaialog.Get().Info("bulk secrets imported", slog.Int("count", importedCount), slog.Int("skipped", skippedCount))
+	log.Printf("Bulk secrets imported successfully, imported %d secrets, skipped %d", importedCount, skippedCount)
+	return result, nil
 }
 
-// constructDBKey safely joins the parts of a secret's key using a null byte delimiter.
-func constructDBKey(client, namespace, key string) []byte {
-	return bytes.Join([][]byte{[]byte(client), []byte(namespace), []byte(key)}, nullByte)
+// ExportSecrets decrypts and returns secrets grouped by client, namespace, and id,
+// in the same nested shape accepted by the import command. If clientName is empty,
+// secrets of every client are exported. The scan stops early if ctx is cancelled.
+func (d *Daemon) ExportSecrets(ctx context.Context, clientName string) (map[string]map[string]map[string]string, error) {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked || d.db == nil {
+		return nil, errors.New("daemon is in a locked state, cannot export secrets")
+	}
+
+	exported := make(map[string]map[string]map[string]string)
+
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		return d.decryptSecrets(ctx, tx, clientName, "", func(client, namespace, id string, decryptedValue []byte, err error) error {
+			if err != nil {
+				return fmt.Errorf("failed to decrypt secret '%s/%s/%s': %w", client, namespace, id, err)
+			}
+
+			if _, ok := exported[client]; !ok {
+				exported[client] = make(map[string]map[string]string)
+			}
+			if _, ok := exported[client][namespace]; !ok {
+				exported[client][namespace] = make(map[string]string)
+			}
+			exported[client][namespace][id] = string(decryptedValue)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export secrets: %w", err)
+	}
+
+	gaialog.Get().Info("secrets exported", slog.String("client_name", clientName))
+	return exported, nil
 }
 
-// openDB is an internal helper to open the BoltDB file.
-func (d *Daemon) openDB() error {
-	var err error
-	d.db, err = bbolt.Open(d.config.DBFile, 0600, &bbolt.Options{Timeout: 1 * time.Second})
+// RotatedSecret records the previous and replacement value of a rotated secret.
+type RotatedSecret struct {
+	ID       string
+	OldValue string
+	NewValue string
+}
+
+// RotateSecrets replaces every secret in one namespace of a client with a value
+// produced by generate. All values are rewritten in a single transaction, so either
+// every secret is rotated or none is. Rotation is deliberately scoped to a single
+// namespace; there is no way to rotate all of a client's secrets at once.
+// Rotation must be enabled for the namespace with SetNamespaceRotation, and
+// fails with ErrRotationDisabled otherwise. Namespaces holding structured secrets
+// fail with ErrStructuredSecret. Generated values must pass the format
+// constraints and value rules of the secrets, or the rotation fails with
+// ErrInvalidFormat or ErrValueRejected. Expired secrets are not rotated, and
+// rotated ones get the default TTL of the namespace again.
+func (d *Daemon) RotateSecrets(clientName, namespace string, generate func() (string, error)) ([]RotatedSecret, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return nil, errors.New("daemon is in a locked state, cannot rotate secrets")
+	}
+
+	var rotated []RotatedSecret
+	var valueWarnings []ValueViolation
+
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(secretsBucket)) == nil {
+			return errors.New("bucket not found")
+		}
+		if !rotationEnabled(tx, clientName, namespace) {
+			return fmt.Errorf("%w: '%s' of client '%s'", ErrRotationDisabled, namespace, clientName)
+		}
+
+		// Collect first: bbolt cursors must not be used across Puts to the same bucket.
+		type entry struct {
+			id    string
+			value []byte
+		}
+		var entries []entry
+		now := time.Now()
+		err := forEachSecret(tx, clientName, namespace, skipExpired(tx, now, func(_, _, id string, v []byte) error {
+			entries = append(entries, entry{id: id, value: bytes.Clone(v)})
+			return nil
+		}))
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no secrets found in namespace '%s' for client '%s'", namespace, clientName)
+		}
+
+		for _, e := range entries {
+			if len(encrypt.EncryptedFields(e.value)) > 0 {
+				return fmt.Errorf("%w: '%s'", ErrStructuredSecret, e.id)
+			}
+			oldValue, err := encrypt.DecryptValue(d.key, e.value)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt secret '%s': %w", e.id, err)
+			}
+
+			newValue, err := generate()
+			if err != nil {
+				return fmt.Errorf("failed to generate new value: %w", err)
+			}
+			if newValue == string(oldValue) {
+				return fmt.Errorf("generated value for '%s' is identical to the current one", e.id)
+			}
+			if err := checkSecretFormat(tx, constructDBKey(clientName, namespace, e.id), e.id, newValue, ""); err != nil {
+				return err
+			}
+
+			encValue, err := d.encryptValue([]byte(newValue))
+			if err != nil {
+				return fmt.Errorf("failed to encrypt secret: %w", err)
+			}
+			if err := setSecretExpiry(tx, clientName, namespace, e.id, 0, now); err != nil {
+				return err
+			}
+			if err := putSecretValue(tx, clientName, namespace, e.id, encValue); err != nil {
+				return fmt.Errorf("failed to write secret %s to db: %w", e.id, err)
+			}
+
+			rotated = append(rotated, RotatedSecret{
+				ID:       e.id,
+				OldValue: string(oldValue),
+				NewValue: newValue,
+			})
+		}
+
+		// Generated values go through the value rules like any other write.
+		items := make([]*pb.ImportSecretItem, len(rotated))
+		for i, r := range rotated {
+			items[i] = &pb.ImportSecretItem{ClientName: clientName, Namespace: namespace, Id: r.ID, Value: r.NewValue}
+		}
+		valueWarnings, err = d.checkValues(items)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logValueWarnings(valueWarnings)
+	d.counters.secretsWritten.Add(uint64(len(rotated)))
+	gaialog.Get().Info("secrets rotated",
+		slog.String("client_name", clientName),
+		slog.String("namespace", namespace),
+		slog.Int("count", len(rotated)),
+	)
+	return rotated, nil
+}
+
+// MoveNamespace re-keys every secret of srcClient's namespace under dstClient in a
+// single transaction. Secrets that already exist at the destination are replaced
+// when overwrite is set; otherwise the move fails with ErrSecretExists and nothing
+// is changed. Like AddSecret, the move fails when dstClient's namespace patterns
+// or quotas do not allow the namespace. Values are moved as stored, without
+// being re-encrypted.
+func (d *Daemon) MoveNamespace(srcClient, dstClient, namespace string, overwrite bool) (int, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return 0, errors.New("daemon is in a locked state, cannot move namespaces")
+	}
+	if srcClient == dstClient {
+		return 0, errors.New("source and destination client are the same")
+	}
+
+	warning, err := d.checkWriteNamespace(dstClient, namespace)
+	if err != nil {
+		return 0, err
+	}
+	if warning != "" {
+		gaialog.Get().Warn("namespace moved to unreachable namespace",
+			slog.String("client_name", dstClient),
+			slog.String("namespace", namespace),
+		)
+	}
+
+	var moved int
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(secretsBucket)) == nil {
+			return errors.New("bucket not found")
+		}
+		accessB := tx.Bucket([]byte(secretAccessBucket))
+
+		// Collect first: bbolt cursors must not be used across Puts to the same bucket.
+		type entry struct {
+			id    string
+			value []byte
+		}
+		var entries []entry
+		err := forEachSecret(tx, srcClient, namespace, func(_, _, id string, v []byte) error {
+			entries = append(entries, entry{id: id, value: bytes.Clone(v)})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no secrets found in namespace '%s' for client '%s'", namespace, srcClient)
+		}
+		// The destination client must accept the namespace as AddSecret would.
+		if err := checkNamespaceAllowed(tx, dstClient, namespace); err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			srcKey := constructDBKey(srcClient, namespace, e.id)
+			dstKey := constructDBKey(dstClient, namespace, e.id)
+			if !overwrite && getSecretValue(tx, dstClient, namespace, e.id) != nil {
+				return fmt.Errorf("%w: '%s' in namespace '%s' of client '%s'", ErrSecretExists, e.id, namespace, dstClient)
+			}
+			if err := d.checkQuota(tx, dstClient, namespace, e.id); err != nil {
+				return err
+			}
+			if err := putSecretValue(tx, dstClient, namespace, e.id, e.value); err != nil {
+				return fmt.Errorf("failed to write secret %s to db: %w", e.id, err)
+			}
+			if err := moveSecretFormat(tx, srcKey, dstKey); err != nil {
+				return err
+			}
+			if err := moveSecretTags(tx, srcKey, dstKey); err != nil {
+				return err
+			}
+			if err := moveSecretExpiry(tx, srcKey, dstKey); err != nil {
+				return err
+			}
+			if accessB != nil {
+				if err := accessB.Delete(dstKey); err != nil {
+					return err
+				}
+				if rec := accessB.Get(srcKey); rec != nil {
+					if err := accessB.Put(dstKey, bytes.Clone(rec)); err != nil {
+						return err
+					}
+					if err := accessB.Delete(srcKey); err != nil {
+						return err
+					}
+				}
+			}
+			moved++
+		}
+		// The source namespace is dropped as a whole once every secret is copied.
+		return deleteNamespaceBucket(tx, srcClient, namespace)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	gaialog.Get().Info("namespace moved",
+		slog.String("src_client", srcClient),
+		slog.String("dst_client", dstClient),
+		slog.String("namespace", namespace),
+		slog.Int("count", moved),
+	)
+	return moved, nil
+}
+
+// RenameSecret changes the id of a secret from oldID to newID within the same
+// client and namespace. The encrypted value, its tags, expiry and the access
+// record move with it. An existing secret named newID is replaced only when
+// overwrite is set.
+func (d *Daemon) RenameSecret(clientName, namespace, oldID, newID string, overwrite bool) error {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return errors.New("daemon is in a locked state, cannot rename secrets")
+	}
+	if oldID == newID {
+		return errors.New("old and new secret id are the same")
+	}
+	// Buffered reads are written first so they are renamed with the secret.
+	if err := d.flushAccessLocked(); err != nil {
+		return fmt.Errorf("failed to flush secret access records: %w", err)
+	}
+
+	srcKey := constructDBKey(clientName, namespace, oldID)
+	dstKey := constructDBKey(clientName, namespace, newID)
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(secretsBucket)) == nil {
+			return errors.New("bucket not found")
+		}
+		value := getSecretValue(tx, clientName, namespace, oldID)
+		if value == nil {
+			return fmt.Errorf("%w: '%s' in namespace '%s' of client '%s'", ErrSecretNotFound, oldID, namespace, clientName)
+		}
+		if !overwrite && getSecretValue(tx, clientName, namespace, newID) != nil {
+			return fmt.Errorf("%w: '%s' in namespace '%s' of client '%s'", ErrSecretExists, newID, namespace, clientName)
+		}
+		if err := putSecretValue(tx, clientName, namespace, newID, bytes.Clone(value)); err != nil {
+			return fmt.Errorf("failed to write secret %s to db: %w", newID, err)
+		}
+		if err := deleteSecretValue(tx, clientName, namespace, oldID); err != nil {
+			return fmt.Errorf("failed to delete secret %s: %w", oldID, err)
+		}
+		if err := moveSecretFormat(tx, srcKey, dstKey); err != nil {
+			return err
+		}
+		if err := moveSecretTags(tx, srcKey, dstKey); err != nil {
+			return err
+		}
+		if err := moveSecretExpiry(tx, srcKey, dstKey); err != nil {
+			return err
+		}
+
+		accessB := tx.Bucket([]byte(secretAccessBucket))
+		if accessB == nil {
+			return nil
+		}
+		if err := accessB.Delete(dstKey); err != nil {
+			return err
+		}
+		if rec := accessB.Get(srcKey); rec != nil {
+			if err := accessB.Put(dstKey, bytes.Clone(rec)); err != nil {
+				return err
+			}
+			return accessB.Delete(srcKey)
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+
+	gaialog.Get().Info("secret renamed",
+		slog.String("client_name", clientName),
+		slog.String("namespace", namespace),
+		slog.String("id", oldID),
+		slog.String("new_id", newID),
+	)
 	return nil
 }
 
-// loadTLSCredentials is an internal helper to set up mTLS.
+// readKeyMaterial returns the KDF salt and key hash of the database. Databases
+// that are not yet migrated to the meta bucket still keep them in the secrets
+// bucket, which is read instead.
+func readKeyMaterial(tx *bbolt.Tx) (salt, keyHash []byte, err error) {
+	b, saltName, hashName := tx.Bucket([]byte(metaBucket)), saltKey, keyHashKey
+	if b == nil {
+		if b = tx.Bucket([]byte(secretsBucket)); b == nil {
+			return nil, nil, fmt.Errorf("%w: secrets bucket is missing", ErrDatabaseCorrupt)
+		}
+		saltName, hashName = legacySaltKey, legacyKeyHashKey
+	}
+	salt = b.Get([]byte(saltName))
+	if len(salt) != saltLen {
+		return nil, nil, fmt.Errorf("%w: invalid salt", ErrDatabaseCorrupt)
+	}
+	keyHash = b.Get([]byte(hashName))
+	if len(keyHash) != sha256.Size {
+		return nil, nil, fmt.Errorf("%w: invalid key hash", ErrDatabaseCorrupt)
+	}
+	return bytes.Clone(salt), bytes.Clone(keyHash), nil
+}
+
+// openDB opens the database file. While another process holds the file lock,
+// as during a compaction swap, it retries up to DBOpenAttempts times with a
+// growing pause; a lock that outlasts every attempt fails with ErrDatabaseInUse.
+func (d *Daemon) openDB() error {
+	d.db = nil
+	if err := os.MkdirAll(filepath.Dir(d.config.DBFile), 0700); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+	attempts := max(d.config.DBOpenAttempts, 1)
+	pause := dbOpenBackoff
+	for attempt := 1; ; attempt++ {
+		db, err := bbolt.Open(d.config.DBFile, 0600, d.boltOptions())
+		if err == nil {
+			d.db = db
+			return nil
+		}
+		if !errors.Is(err, berrors.ErrTimeout) {
+			return err
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("%w: %s is still locked after %d attempts, is another gaia daemon using it?", ErrDatabaseInUse, d.config.DBFile, attempts)
+		}
+		gaialog.Get().Warn("database file is locked, retrying",
+			slog.Int("attempt", attempt), slog.Duration("pause", pause))
+		time.Sleep(pause)
+		pause *= 2
+	}
+}
+
+// boltOptions returns the options the database is opened with, including the
+// tuning of the configuration.
+func (d *Daemon) boltOptions() *bbolt.Options {
+	opts := &bbolt.Options{
+		Timeout:         dbOpenTimeout,
+		NoSync:          d.config.DBNoSync,
+		NoFreelistSync:  d.config.DBNoFreelistSync,
+		FreelistType:    bbolt.FreelistType(d.config.DBFreelistType),
+		InitialMmapSize: d.config.DBInitialMmapSize,
+	}
+	if opts.FreelistType == "" {
+		opts.FreelistType = bbolt.FreelistArrayType
+	}
+	return opts
+}
+
+// loadTLSCredentials is an internal helper to set up mTLS. Errors name the file
+// at fault and the `gaia certs` command that creates it.
 func (d *Daemon) loadTLSCredentials() (credentials.TransportCredentials, error) {
+	tlsConfig, err := d.serverTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// serverTLSConfig loads the server key pair and the CAs client certificates
+// are verified against: the CA and, during a CA rotation, the previous CA.
+func (d *Daemon) serverTLSConfig() (*tls.Config, error) {
 	caCertPath := filepath.Join(d.config.CertsDirectory, d.config.CACertFile)
 	serverCertPath := filepath.Join(d.config.CertsDirectory, d.config.ServerCertFile)
 	serverKeyPath := filepath.Join(d.config.CertsDirectory, d.config.ServerKeyFile)
 
-	certPool := x509.NewCertPool()
+	createCA := fmt.Sprintf("gaia certs generate -o %s", d.config.CertsDirectory)
+	createServer := fmt.Sprintf("gaia certs create-server <hostname> -o %s", d.config.CertsDirectory)
+
 	caCert, err := os.ReadFile(caCertPath)
 	if err != nil {
-		return nil, fmt.Errorf("could not read CA certificate: %w", err)
+		return nil, tlsFileError("CA certificate", caCertPath, createCA, err)
 	}
+	certPool := x509.NewCertPool()
 	if !certPool.AppendCertsFromPEM(caCert) {
-		return nil, errors.New("could not append CA certificate to pool")
+		return nil, fmt.Errorf("CA certificate %s contains no PEM certificate; recreate it with '%s'", caCertPath, createCA)
+	}
+	if d.config.PreviousCACertFile != "" {
+		previousCAPath := filepath.Join(d.config.CertsDirectory, d.config.PreviousCACertFile)
+		if previousCA, err := os.ReadFile(previousCAPath); err == nil {
+			if !certPool.AppendCertsFromPEM(previousCA) {
+				return nil, fmt.Errorf("previous CA certificate %s contains no PEM certificate; remove it once every client has a certificate of the new CA", previousCAPath)
+			}
+			gaialog.Get().Info("accepting client certificates of the previous CA", slog.String("file", previousCAPath))
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("could not read previous CA certificate %s: %w", previousCAPath, err)
+		}
 	}
 
+	for _, f := range []struct{ what, path string }{
+		{"server certificate", serverCertPath},
+		{"server key", serverKeyPath},
+	} {
+		if _, err := os.ReadFile(f.path); err != nil {
+			return nil, tlsFileError(f.what, f.path, createServer, err)
+		}
+	}
 	serverCert, err := tls.LoadX509KeyPair(serverCertPath, serverKeyPath)
 	if err != nil {
-		return nil, fmt.Errorf("could not load server key pair: %w", err)
+		return nil, fmt.Errorf("server key %s does not match server certificate %s: %w; recreate both with '%s'", serverKeyPath, serverCertPath, err, createServer)
 	}
-	creds := credentials.NewTLS(&tls.Config{
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		Certificates: []tls.Certificate{serverCert},
-		ClientCAs:    certPool,
-	})
-	return creds, nil
+	if _, err := certs.VerifyCertificate(caCertPath, serverCertPath, ""); err != nil {
+		return nil, fmt.Errorf("server certificate %s was not issued by the CA in %s: %w; recreate it with '%s'", serverCertPath, caCertPath, err, createServer)
+	}
+
+	tlsConfig := &tls.Config{
+		ClientAuth:            tls.RequireAndVerifyClientCert,
+		Certificates:          []tls.Certificate{serverCert},
+		ClientCAs:             certPool,
+		VerifyPeerCertificate: verifyClientCertificate(certPool, d.config.ClientCertClockSkew, time.Now),
+	}
+	if d.config.ClientCertClockSkew > 0 {
+		// The TLS stack rejects a certificate outside its validity window before
+		// VerifyPeerCertificate runs, so the chain is only verified there.
+		tlsConfig.ClientAuth = tls.RequireAnyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// tlsFileError describes a TLS file that could not be read, with the command
+// that creates it when it is missing.
+func tlsFileError(what, path, createCmd string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s not found at %s; create it with '%s'", what, path, createCmd)
+	}
+	return fmt.Errorf("could not read %s %s: %w", what, path, err)
 }
 
 // loadCACredentials loads the CA certificate and private key from disk.
@@ -0,0 +1,2000 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/certs"
+	"github.com/stain-win/gaia/apps/gaia/config"
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"go.etcd.io/bbolt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testPassphrase     = "correct-horse-battery-staple-42"
+	testClientCertName = "gaia-cli"
+)
+
+var (
+	testCAOnce sync.Once
+	testCADir  string
+	testCAErr  error
+)
+
+func TestMain(m *testing.M) {
+	gaialog.Init(gaialog.LevelError, "", false)
+	code := m.Run()
+	if testCADir != "" {
+		os.RemoveAll(testCADir)
+	}
+	os.Exit(code)
+}
+
+// testCertsDir returns a directory holding a CA shared by all tests in the package,
+// generating it on first use.
+func testCertsDir(t testing.TB) string {
+	t.Helper()
+	testCAOnce.Do(func() {
+		testCADir, testCAErr = os.MkdirTemp("", "gaia-test-ca")
+		if testCAErr != nil {
+			return
+		}
+		cfg := config.NewDefaultConfig()
+		cfg.CertsDirectory = testCADir
+		if testCAErr = certs.GenerateCA(cfg, "Gaia Test CA"); testCAErr != nil {
+			return
+		}
+		if testCAErr = certs.GenerateServerCertificate(cfg, "localhost"); testCAErr != nil {
+			return
+		}
+		testCAErr = certs.GenerateClientCertificate(cfg, testClientCertName)
+	})
+	if testCAErr != nil {
+		t.Fatalf("failed to generate test CA: %v", testCAErr)
+	}
+	return testCADir
+}
+
+// newTestConfig returns a config pointing at a fresh database in a temporary directory.
+func newTestConfig(t testing.TB, opts ...func(*config.Config)) *config.Config {
+	t.Helper()
+	cfg := config.NewDefaultConfig()
+	cfg.DBFile = filepath.Join(t.TempDir(), "gaia.db")
+	cfg.CertsDirectory = testCertsDir(t)
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// newTestDaemon initializes a database and returns a daemon unlocked with testPassphrase.
+func newTestDaemon(t testing.TB, opts ...func(*config.Config)) *Daemon {
+	t.Helper()
+	d := NewDaemon(newTestConfig(t, opts...))
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() error = %v", err)
+	}
+	t.Cleanup(d.LockDB)
+	return d
+}
+
+func TestInitializeDB_CreatesDataDirectory(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG base directories only apply on Linux")
+	}
+	// On a fresh install neither the data home nor its gaia directory exist.
+	dataHome := filepath.Join(t.TempDir(), "missing", "share")
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	cfg := config.NewDefaultConfig()
+	cfg.CertsDirectory = testCertsDir(t)
+	if want := filepath.Join(dataHome, "gaia", "gaia.db"); cfg.DBFile != want {
+		t.Fatalf("default DBFile = %q, want %q", cfg.DBFile, want)
+	}
+
+	d := NewDaemon(cfg)
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() into a missing data directory error = %v", err)
+	}
+	info, err := os.Stat(filepath.Dir(cfg.DBFile))
+	if err != nil {
+		t.Fatalf("database directory was not created: %v", err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("database directory mode = %v, want 0700", info.Mode().Perm())
+	}
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() error = %v", err)
+	}
+	d.LockDB()
+}
+
+func clientNames(t *testing.T, d *Daemon) []string {
+	t.Helper()
+	clients, err := d.ListClients()
+	if err != nil {
+		t.Fatalf("ListClients() error = %v", err)
+	}
+	names := make([]string, len(clients))
+	for i, c := range clients {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func TestCommonNamespace_Enabled(t *testing.T) {
+	d := newTestDaemon(t)
+
+	if !slices.Contains(clientNames(t, d), commonNamespace) {
+		t.Errorf("expected %q client to be registered at init", commonNamespace)
+	}
+
+	if _, err := d.AddSecret(commonNamespace, commonNamespace, "region", "eu-west-1", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	value, err := d.GetSecret("app-a", commonNamespace, "region")
+	if err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if value != "eu-west-1" {
+		t.Errorf("GetSecret() = %q, want %q", value, "eu-west-1")
+	}
+}
+
+func TestCommonNamespace_Disabled(t *testing.T) {
+	d := newTestDaemon(t, func(cfg *config.Config) { cfg.EnableCommonNamespace = false })
+
+	if slices.Contains(clientNames(t, d), commonNamespace) {
+		t.Errorf("did not expect %q client to be registered at init", commonNamespace)
+	}
+
+	if _, err := d.AddSecret(commonNamespace, commonNamespace, "region", "eu-west-1", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.GetSecret("app-a", commonNamespace, "region"); err == nil {
+		t.Error("GetSecret() on common namespace should be denied when the common namespace is disabled")
+	}
+
+	// A client's own namespace still works as usual.
+	if _, err := d.AddSecret("app-a", "app-a", "token", "t1", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if value, err := d.GetSecret("app-a", "app-a", "token"); err != nil || value != "t1" {
+		t.Errorf("GetSecret() = %q, %v, want %q, nil", value, err, "t1")
+	}
+}
+
+func TestCommonNamespace_CustomName(t *testing.T) {
+	d := newTestDaemon(t, func(cfg *config.Config) { cfg.CommonName = "shared" })
+
+	names := clientNames(t, d)
+	if !slices.Contains(names, "shared") || slices.Contains(names, commonNamespace) {
+		t.Errorf("clients at init = %v, want %q registered instead of %q", names, "shared", commonNamespace)
+	}
+	if _, err := d.AddSecret("shared", "shared", "region", "eu-west-1", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if value, err := d.GetSecret("app-a", "shared", "region"); err != nil || value != "eu-west-1" {
+		t.Errorf("GetSecret(shared) = %q, %v; want %q", value, err, "eu-west-1")
+	}
+	if got, err := d.GetCommonSecrets("app-a", ""); err != nil || got["shared"]["region"] != "eu-west-1" {
+		t.Errorf("GetCommonSecrets() = %v, %v; want shared/region", got, err)
+	}
+
+	// "common" is an ordinary name now: a client called common may be registered
+	// and other clients cannot read its namespace.
+	if err := d.RegisterClient(commonNamespace); err != nil {
+		t.Errorf("RegisterClient(%q) error = %v", commonNamespace, err)
+	}
+	if _, err := d.AddSecret(commonNamespace, commonNamespace, "token", "t1", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.GetSecret("app-a", commonNamespace, "token"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("GetSecret(%q) by another client error = %v, want ErrPermissionDenied", commonNamespace, err)
+	}
+}
+
+func TestRegisterClient_ReservedCommonName(t *testing.T) {
+	d := newTestDaemon(t, func(cfg *config.Config) { cfg.CommonName = "shared" })
+
+	if err := d.RegisterClient("shared"); !errors.Is(err, ErrReservedClientName) {
+		t.Errorf("RegisterClient(shared) error = %v, want ErrReservedClientName", err)
+	}
+	for _, reissue := range []bool{false, true} {
+		_, err := NewAdminServer(d).RegisterClient(context.Background(), &pb.RegisterClientRequest{ClientName: "shared", Reissue: reissue})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("RegisterClient RPC (reissue %v) for the common name error = %v, want InvalidArgument", reissue, err)
+		}
+	}
+}
+
+func TestCommonNamespace_DisablingKeepsExistingDB(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret(commonNamespace, commonNamespace, "region", "eu-west-1", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	d.config.EnableCommonNamespace = false
+
+	if !slices.Contains(clientNames(t, d), commonNamespace) {
+		t.Errorf("existing %q client should remain registered", commonNamespace)
+	}
+	secrets, err := d.ListSecrets(context.Background(), commonNamespace)
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if secrets[commonNamespace]["region"] != "eu-west-1" {
+		t.Errorf("existing common secret should be untouched, got %v", secrets)
+	}
+}
+
+// cancelAfterContext reports cancellation once Err has been consulted n times,
+// simulating a client that gives up part-way through a scan.
+type cancelAfterContext struct {
+	context.Context
+	remaining int
+}
+
+func (c *cancelAfterContext) Err() error {
+	if c.remaining <= 0 {
+		return context.Canceled
+	}
+	c.remaining--
+	return nil
+}
+
+func TestListSecrets_CancelledMidScan(t *testing.T) {
+	d := newTestDaemon(t)
+	for i := 0; i < 50; i++ {
+		if _, err := d.AddSecret("app-a", "app-a", fmt.Sprintf("key-%02d", i), "value", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+
+	ctx := &cancelAfterContext{Context: context.Background(), remaining: 10}
+	start := time.Now()
+	secrets, err := d.ListSecrets(ctx, "app-a")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ListSecrets() error = %v, want context.Canceled", err)
+	}
+	if secrets != nil {
+		t.Errorf("ListSecrets() returned %d namespaces after cancellation, want nil", len(secrets))
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ListSecrets() took %v to honour cancellation", elapsed)
+	}
+}
+
+func TestListSecrets_NoSecretsBucket(t *testing.T) {
+	d := newTestDaemon(t)
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		return tx.DeleteBucket([]byte(secretsBucket))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secrets, err := d.ListSecrets(context.Background(), "app-a")
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if secrets == nil || len(secrets) != 0 {
+		t.Errorf("ListSecrets() = %v, want an empty map", secrets)
+	}
+}
+
+func TestExportSecrets_CancelledContext(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app-a", "app-a", "key", "value", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.ExportSecrets(ctx, ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ExportSecrets() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestCiphertextEncoding_Raw(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "old", "written as base64", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	d.config.CiphertextEncoding = encrypt.EncodingRaw
+	if _, err := d.AddSecret("app", "app", "new", "written as raw", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	stored := func(id string) []byte {
+		var v []byte
+		err := d.db.View(func(tx *bbolt.Tx) error {
+			v = bytes.Clone(getSecretValue(tx, "app", "app", id))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	oldValue, newValue := stored("old"), stored("new")
+	if _, err := base64.StdEncoding.DecodeString(string(oldValue)); err != nil {
+		t.Errorf("secret written with base64 encoding is not base64: %v", err)
+	}
+	if _, err := base64.StdEncoding.DecodeString(string(newValue)); err == nil {
+		t.Error("secret written with raw encoding is stored as base64")
+	}
+
+	// Both encodings are read after the switch, as during a migration.
+	for id, want := range map[string]string{"old": "written as base64", "new": "written as raw"} {
+		if got, err := d.GetSecret("app", "app", id); err != nil || got != want {
+			t.Errorf("GetSecret(%s) = %q, %v; want %q", id, got, err, want)
+		}
+	}
+	if _, failures, err := d.VerifyIntegrity(t.Context()); err != nil || len(failures) != 0 {
+		t.Errorf("VerifyIntegrity() with mixed encodings = %v, %v; want no failures", failures, err)
+	}
+}
+
+func TestRotateSecrets(t *testing.T) {
+	d := newTestDaemon(t)
+	original := map[string]string{"api_key": "old-api", "db_password": "old-db", "token": "old-token"}
+	for id, value := range original {
+		if _, err := d.AddSecret("app-a", "app-a", id, value, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	if _, err := d.AddSecret("app-a", "other", "api_key", "untouched", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	enableRotation(t, d, "app-a", "app-a")
+
+	n := 0
+	generate := func() (string, error) {
+		n++
+		return fmt.Sprintf("new-%d", n), nil
+	}
+	rotated, err := d.RotateSecrets("app-a", "app-a", generate)
+	if err != nil {
+		t.Fatalf("RotateSecrets() error = %v", err)
+	}
+	if len(rotated) != len(original) {
+		t.Fatalf("RotateSecrets() rotated %d secrets, want %d", len(rotated), len(original))
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range rotated {
+		if seen[r.ID] {
+			t.Errorf("secret %q returned more than once", r.ID)
+		}
+		seen[r.ID] = true
+		if r.OldValue != original[r.ID] {
+			t.Errorf("secret %q old value = %q, want %q", r.ID, r.OldValue, original[r.ID])
+		}
+		got, err := d.GetSecret("app-a", "app-a", r.ID)
+		if err != nil {
+			t.Fatalf("GetSecret(%q) error = %v", r.ID, err)
+		}
+		if got == original[r.ID] || got != r.NewValue {
+			t.Errorf("secret %q = %q after rotation, want new value %q", r.ID, got, r.NewValue)
+		}
+	}
+
+	secrets, err := d.ListSecrets(context.Background(), "app-a")
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if got := secrets["other"]["api_key"]; got != "untouched" {
+		t.Errorf("secret in another namespace = %q, want it left unchanged", got)
+	}
+}
+
+func TestRotateSecrets_FailureRollsBack(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := d.AddSecret("app-a", "app-a", id, "old-"+id, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+
+	enableRotation(t, d, "app-a", "app-a")
+
+	calls := 0
+	generate := func() (string, error) {
+		calls++
+		if calls == 2 {
+			return "", errors.New("generator exhausted")
+		}
+		return "new", nil
+	}
+	if _, err := d.RotateSecrets("app-a", "app-a", generate); err == nil {
+		t.Fatal("RotateSecrets() succeeded despite a generator failure")
+	}
+
+	for _, id := range []string{"a", "b", "c"} {
+		got, err := d.GetSecret("app-a", "app-a", id)
+		if err != nil {
+			t.Fatalf("GetSecret(%q) error = %v", id, err)
+		}
+		if got != "old-"+id {
+			t.Errorf("secret %q = %q after failed rotation, want it unchanged", id, got)
+		}
+	}
+}
+
+func TestRotateSecrets_EmptyNamespace(t *testing.T) {
+	d := newTestDaemon(t)
+	enableRotation(t, d, "app-a", "missing")
+	generate := func() (string, error) { return "new", nil }
+	if _, err := d.RotateSecrets("app-a", "missing", generate); err == nil {
+		t.Fatal("RotateSecrets() succeeded on a namespace without secrets")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	d := newTestDaemon(t)
+
+	for _, id := range []string{"a", "b"} {
+		if _, err := d.AddSecret("app-a", "app-a", id, "value", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := d.GetSecret("app-a", "app-a", "a"); err != nil {
+			t.Fatalf("GetSecret() error = %v", err)
+		}
+	}
+	if _, err := d.GetSecret("app-b", "app-a", "a"); err == nil {
+		t.Fatal("GetSecret() succeeded for another client's namespace")
+	}
+	if err := d.DeleteSecret("app-a", "app-a", "b"); err != nil {
+		t.Fatalf("DeleteSecret() error = %v", err)
+	}
+	// An unlocked daemon does not check the passphrase, so lock it first.
+	d.LockDB()
+	if err := d.UnlockDB("wrong passphrase"); err == nil {
+		t.Fatal("UnlockDB() succeeded with a wrong passphrase")
+	}
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() error = %v", err)
+	}
+
+	m := d.Metrics()
+	want := Metrics{
+		SecretsServed:  3,
+		AccessDenied:   1,
+		SecretsWritten: 2,
+		SecretsDeleted: 1,
+		Unlocks:        2, // From newTestDaemon and after LockDB.
+		FailedUnlocks:  1,
+		Locked:         false,
+	}
+	if m != want {
+		t.Errorf("Metrics() = %+v, want %+v", m, want)
+	}
+
+	d.LockDB()
+	if !d.Metrics().Locked {
+		t.Errorf("Metrics().Locked = false after LockDB")
+	}
+}
+
+func TestGetMetricsRPC(t *testing.T) {
+	d := newTestDaemon(t)
+	srv := NewAdminServer(d)
+
+	if _, err := d.AddSecret("app-a", "app-a", "token", "value", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := d.GetSecret("app-a", "app-a", "token"); err != nil {
+			t.Fatalf("GetSecret() error = %v", err)
+		}
+	}
+	if _, err := d.GetSecret("app-b", "app-a", "token"); err == nil {
+		t.Fatal("GetSecret() succeeded for another client's namespace")
+	}
+
+	res, err := srv.GetMetrics(context.Background(), &pb.GetMetricsRequest{})
+	if err != nil {
+		t.Fatalf("GetMetrics() error = %v", err)
+	}
+	if res.SecretsWritten != 1 || res.SecretsServed != 2 || res.AccessDenied != 1 || res.Unlocks != 1 || res.Locked {
+		t.Errorf("GetMetrics() = %v, want 1 written, 2 served, 1 denied, 1 unlock and unlocked", res)
+	}
+
+	// Monitoring keeps working while the daemon is locked.
+	d.LockDB()
+	if err := d.checkUnlockGate(pb.GaiaAdmin_GetMetrics_FullMethodName); err != nil {
+		t.Errorf("GetMetrics is refused while locked: %v", err)
+	}
+	if res, err := srv.GetMetrics(context.Background(), &pb.GetMetricsRequest{}); err != nil || !res.Locked || res.SecretsServed != 2 {
+		t.Errorf("GetMetrics() on a locked daemon = %v, %v; want locked with the counters kept", res, err)
+	}
+}
+
+func TestGetStatusRPC_Uptime(t *testing.T) {
+	d := newTestDaemon(t)
+	srv := NewAdminServer(d)
+	// Back-date the start, so the reported seconds are not zero.
+	d.createdAt = time.Now().UTC().Add(-time.Hour)
+
+	first := d.Uptime()
+	time.Sleep(10 * time.Millisecond)
+	if second := d.Uptime(); second <= first {
+		t.Errorf("Uptime() = %v after %v, want it to increase", second, first)
+	}
+
+	res, err := srv.GetStatus(context.Background(), &pb.GetStatusRequest{})
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if started, err := time.Parse(time.RFC3339, res.StartedAt); err != nil || !started.Equal(d.createdAt.Truncate(time.Second)) {
+		t.Errorf("GetStatus() started_at = %q, %v; want %s", res.StartedAt, err, d.createdAt.Format(time.RFC3339))
+	}
+	if res.UptimeSeconds < 3600 {
+		t.Errorf("GetStatus() uptime_seconds = %d, want at least 3600", res.UptimeSeconds)
+	}
+	if res.GoVersion != runtime.Version() || res.Os != runtime.GOOS+"/"+runtime.GOARCH {
+		t.Errorf("GetStatus() build = %q %q, want %q %s/%s", res.GoVersion, res.Os, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	}
+}
+
+func TestGetStatusRPC_Banner(t *testing.T) {
+	const banner = "staging-eu · maintenance window Friday 18:00 UTC"
+	d := newTestDaemon(t, func(c *config.Config) { c.Banner = banner })
+	res, err := NewAdminServer(d).GetStatus(context.Background(), &pb.GetStatusRequest{})
+	if err != nil || res.Banner != banner {
+		t.Errorf("GetStatus() banner = %q, %v; want %q", res.GetBanner(), err, banner)
+	}
+
+	// The banner is shown while the daemon is locked too.
+	d.LockDB()
+	if res, err := NewAdminServer(d).GetStatus(context.Background(), &pb.GetStatusRequest{}); err != nil || res.Banner != banner {
+		t.Errorf("GetStatus() banner of a locked daemon = %q, %v; want %q", res.GetBanner(), err, banner)
+	}
+}
+
+func TestRevokeClient_DryRun(t *testing.T) {
+	d := newTestDaemon(t)
+	if err := d.RegisterClient("app-a"); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	secrets := []struct{ namespace, id string }{
+		{"app-a", "api_key"}, {"app-a", "db_password"}, {"staging", "api_key"},
+	}
+	for _, s := range secrets {
+		if _, err := d.AddSecret("app-a", s.namespace, s.id, "value", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	if _, err := d.AddSecret("app-ab", "app-ab", "api_key", "value", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	res, err := NewAdminServer(d).RevokeClient(context.Background(), &pb.RevokeClientRequest{ClientName: "app-a", DryRun: true})
+	if err != nil {
+		t.Fatalf("RevokeClient(dry run) error = %v", err)
+	}
+	if res.SecretCount != int32(len(secrets)) {
+		t.Errorf("dry run secret count = %d, want %d", res.SecretCount, len(secrets))
+	}
+	if want := []string{"app-a", "staging"}; !slices.Equal(res.Namespaces, want) {
+		t.Errorf("dry run namespaces = %v, want %v", res.Namespaces, want)
+	}
+
+	if !slices.Contains(clientNames(t, d), "app-a") {
+		t.Errorf("dry run removed the client registration")
+	}
+	listed, err := d.ListSecrets(context.Background(), "app-a")
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if got := len(listed["app-a"]) + len(listed["staging"]); got != len(secrets) {
+		t.Errorf("dry run left %d secrets, want %d", got, len(secrets))
+	}
+
+	impact, err := d.RevokeClient("app-a")
+	if err != nil {
+		t.Fatalf("RevokeClient() error = %v", err)
+	}
+	if impact.SecretCount != len(secrets) || !impact.Registered {
+		t.Errorf("RevokeClient() impact = %+v, want %d secrets of a registered client", impact, len(secrets))
+	}
+	listed, err = d.ListSecrets(context.Background(), "app-a")
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if len(listed) != 0 {
+		t.Errorf("RevokeClient() left secrets behind: %v", listed)
+	}
+	if _, err := d.GetSecret("app-ab", "app-ab", "api_key"); err != nil {
+		t.Errorf("RevokeClient() removed a secret of a client sharing the name prefix: %v", err)
+	}
+}
+
+func TestRevokeClient_RequireReauth(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.RequireReauthForDestructive = true })
+	if err := d.RegisterClient("app-a"); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	if _, err := d.AddSecret("app-a", "app-a", "api_key", "value", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	srv := NewAdminServer(d)
+	ctx := context.Background()
+
+	for _, passphrase := range []string{"", "wrong passphrase"} {
+		_, err := srv.RevokeClient(ctx, &pb.RevokeClientRequest{ClientName: "app-a", Passphrase: passphrase})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("RevokeClient(passphrase %q) error = %v, want Unauthenticated", passphrase, err)
+		}
+	}
+	if !slices.Contains(clientNames(t, d), "app-a") {
+		t.Fatal("rejected re-authentication still revoked the client")
+	}
+
+	if _, err := srv.RevokeClient(ctx, &pb.RevokeClientRequest{ClientName: "app-a", DryRun: true}); err != nil {
+		t.Errorf("RevokeClient(dry run) without passphrase error = %v", err)
+	}
+
+	res, err := srv.RevokeClient(ctx, &pb.RevokeClientRequest{ClientName: "app-a", Passphrase: testPassphrase})
+	if err != nil {
+		t.Fatalf("RevokeClient() with passphrase error = %v", err)
+	}
+	if res.SecretCount != 1 {
+		t.Errorf("RevokeClient() secret count = %d, want 1", res.SecretCount)
+	}
+	if slices.Contains(clientNames(t, d), "app-a") {
+		t.Error("RevokeClient() with passphrase left the client registered")
+	}
+}
+
+func TestRegisterClient_Duplicate(t *testing.T) {
+	d := newTestDaemon(t)
+	srv := NewAdminServer(d)
+	ctx := context.Background()
+
+	if _, err := srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "app-a"}); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	before, err := d.GetClient("app-a")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+
+	_, err = srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "app-a"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("RegisterClient() duplicate error = %v, want AlreadyExists", err)
+	}
+	if err := d.RegisterClient("app-a"); !errors.Is(err, ErrClientExists) {
+		t.Errorf("Daemon.RegisterClient() duplicate error = %v, want ErrClientExists", err)
+	}
+
+	after, err := d.GetClient("app-a")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	if *after != *before {
+		t.Errorf("duplicate registration changed the client from %+v to %+v", before, after)
+	}
+}
+
+func TestRegisterClient_CertificateOptions(t *testing.T) {
+	d := newTestDaemon(t)
+	srv := NewAdminServer(d)
+	ctx := context.Background()
+
+	parse := func(t *testing.T, certPEM string) *x509.Certificate {
+		t.Helper()
+		block, _ := pem.Decode([]byte(certPEM))
+		if block == nil {
+			t.Fatal("RegisterClient() returned no PEM certificate")
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("failed to parse certificate: %v", err)
+		}
+		return cert
+	}
+
+	res, err := srv.RegisterClient(ctx, &pb.RegisterClientRequest{
+		ClientName:         "billing",
+		Organization:       "Example Corp",
+		OrganizationalUnit: "admins",
+		UriSans:            []string{"spiffe://example.org/gaia/billing"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	cert := parse(t, res.Certificate)
+	if cert.Subject.CommonName != "billing" {
+		t.Errorf("CommonName = %q, want billing", cert.Subject.CommonName)
+	}
+	if !slices.Equal(cert.Subject.Organization, []string{"Example Corp"}) || !slices.Equal(cert.Subject.OrganizationalUnit, []string{"admins"}) {
+		t.Errorf("subject O = %v, OU = %v; want [Example Corp] and [admins]", cert.Subject.Organization, cert.Subject.OrganizationalUnit)
+	}
+	if len(cert.URIs) != 1 || cert.URIs[0].String() != "spiffe://example.org/gaia/billing" {
+		t.Errorf("URI SANs = %v, want [spiffe://example.org/gaia/billing]", cert.URIs)
+	}
+
+	// Without options the certificate only carries the Common Name, as before.
+	res, err = srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "orders"})
+	if err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	cert = parse(t, res.Certificate)
+	if len(cert.Subject.Organization) != 0 || len(cert.Subject.OrganizationalUnit) != 0 || len(cert.URIs) != 0 {
+		t.Errorf("default certificate has O = %v, OU = %v, URIs = %v; want none", cert.Subject.Organization, cert.Subject.OrganizationalUnit, cert.URIs)
+	}
+
+	_, err = srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "shipping", UriSans: []string{"not a uri"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("RegisterClient() with a relative URI SAN error = %v, want InvalidArgument", err)
+	}
+	if _, err := d.GetClient("shipping"); !errors.Is(err, ErrClientNotFound) {
+		t.Error("RegisterClient() with an invalid URI SAN registered the client")
+	}
+}
+
+func TestRegisterClient_Reissue(t *testing.T) {
+	d := newTestDaemon(t)
+	srv := NewAdminServer(d)
+	ctx := context.Background()
+
+	first, err := srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "app-a"})
+	if err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	before, err := d.GetClient("app-a")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+
+	second, err := srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "app-a", Reissue: true})
+	if err != nil {
+		t.Fatalf("RegisterClient(reissue) error = %v", err)
+	}
+	if second.Certificate == "" || second.Certificate == first.Certificate {
+		t.Errorf("RegisterClient(reissue) did not return a new certificate")
+	}
+
+	after, err := d.GetClient("app-a")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	if after.TimeCreated != before.TimeCreated {
+		t.Errorf("reissue changed the creation time from %q to %q", before.TimeCreated, after.TimeCreated)
+	}
+
+	_, err = srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "unknown", Reissue: true})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("RegisterClient(reissue) for an unknown client error = %v, want NotFound", err)
+	}
+	if _, err := d.GetClient("unknown"); !errors.Is(err, ErrClientNotFound) {
+		t.Errorf("reissue for an unknown client registered it")
+	}
+}
+
+func certSerial(t *testing.T, certPEM string) string {
+	t.Helper()
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		t.Fatal("failed to decode certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return cert.SerialNumber.Text(16)
+}
+
+func TestExportClientManifest(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.EnableCommonNamespace = true })
+	srv := NewAdminServer(d)
+	ctx := context.Background()
+
+	first, err := srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "app-a"})
+	if err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	reissued, err := srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "app-a", Reissue: true})
+	if err != nil {
+		t.Fatalf("RegisterClient(reissue) error = %v", err)
+	}
+	if _, err := srv.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: "app-b"}); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+
+	res, err := srv.ExportClientManifest(ctx, &pb.ExportClientManifestRequest{})
+	if err != nil {
+		t.Fatalf("ExportClientManifest() error = %v", err)
+	}
+	byName := make(map[string]*pb.ClientManifestEntry)
+	for _, c := range res.Clients {
+		byName[c.Name] = c
+	}
+	if len(byName) != 3 {
+		t.Fatalf("manifest has %d clients, want 3 (common, app-a, app-b)", len(byName))
+	}
+
+	a := byName["app-a"]
+	if _, err := time.Parse(time.RFC3339, a.TimeCreated); err != nil {
+		t.Errorf("app-a time_created = %q, want an RFC3339 timestamp", a.TimeCreated)
+	}
+	if want := certSerial(t, reissued.Certificate); a.Serial != want {
+		t.Errorf("app-a serial = %q, want the reissued serial %q", a.Serial, want)
+	}
+	if a.Serial == certSerial(t, first.Certificate) {
+		t.Errorf("app-a serial still refers to the first certificate")
+	}
+	if len(a.Fingerprint) != 64 || a.NotAfter == "" || a.IssuedAt == "" {
+		t.Errorf("app-a certificate metadata incomplete: %+v", a)
+	}
+
+	if common := byName[commonNamespace]; common.TimeCreated == "" || common.Serial != "" {
+		t.Errorf("common entry = %+v, want a registration time and no certificate", common)
+	}
+
+	if _, err := d.RevokeClient("app-b"); err != nil {
+		t.Fatalf("RevokeClient() error = %v", err)
+	}
+	entries, err := d.ClientManifest()
+	if err != nil {
+		t.Fatalf("ClientManifest() error = %v", err)
+	}
+	for _, e := range entries {
+		if e.Name == "app-b" {
+			t.Errorf("revoked client still listed in manifest")
+		}
+	}
+}
+
+func listReflectedServices(t *testing.T, enabled bool) ([]string, error) {
+	t.Helper()
+	d := NewDaemon(newTestConfig(t, func(c *config.Config) { c.EnableReflection = enabled }))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	d.registerServices(srv)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req := &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, s := range res.GetListServicesResponse().GetService() {
+		names = append(names, s.Name)
+	}
+	return names, nil
+}
+
+func TestReflection(t *testing.T) {
+	names, err := listReflectedServices(t, true)
+	if err != nil {
+		t.Fatalf("ListServices with reflection enabled: error = %v", err)
+	}
+	for _, want := range []string{"gaia.GaiaAdmin", "gaia.GaiaClient"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("reflection services = %v, want %s listed", names, want)
+		}
+	}
+
+	if _, err := listReflectedServices(t, false); status.Code(err) != codes.Unimplemented {
+		t.Errorf("ListServices with reflection disabled: error = %v, want Unimplemented", err)
+	}
+}
+
+// overwriteMeta replaces a metadata value in an initialized, closed database.
+func overwriteMeta(t *testing.T, dbFile, key string, value []byte) {
+	t.Helper()
+	db, err := bbolt.Open(dbFile, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	err = db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(metaBucket)).Put([]byte(key), value)
+	})
+	if err != nil {
+		t.Fatalf("failed to overwrite %s: %v", key, err)
+	}
+}
+
+func TestUnlockDB_CorruptMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   []byte
+		wantMsg string
+	}{
+		{name: "zero-length salt", key: saltKey, value: []byte{}, wantMsg: "database corrupt: invalid salt"},
+		{name: "truncated salt", key: saltKey, value: make([]byte, saltLen/2), wantMsg: "database corrupt: invalid salt"},
+		{name: "truncated key hash", key: keyHashKey, value: make([]byte, 16), wantMsg: "database corrupt: invalid key hash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			d := NewDaemon(cfg)
+			if err := d.InitializeDB(testPassphrase); err != nil {
+				t.Fatalf("InitializeDB() error = %v", err)
+			}
+			overwriteMeta(t, cfg.DBFile, tt.key, tt.value)
+
+			err := d.UnlockDB(testPassphrase)
+			if !errors.Is(err, ErrDatabaseCorrupt) {
+				t.Fatalf("UnlockDB() error = %v, want ErrDatabaseCorrupt", err)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("UnlockDB() error = %q, want %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestUnlockDB_RetriesBrieflyLockedFile(t *testing.T) {
+	d := newTestDaemon(t)
+	d.LockDB()
+
+	// bbolt holds an exclusive file lock while the database is open, so a second
+	// handle stands in for another process.
+	hold, err := bbolt.Open(d.config.DBFile, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	released := make(chan struct{})
+	go func() {
+		defer close(released)
+		time.Sleep(dbOpenTimeout + dbOpenBackoff/2)
+		hold.Close()
+	}()
+
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() with a briefly locked file error = %v", err)
+	}
+	<-released
+
+	d.LockDB()
+	hold, err = bbolt.Open(d.config.DBFile, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer hold.Close()
+	d.config.DBOpenAttempts = 1
+	if err := d.UnlockDB(testPassphrase); !errors.Is(err, ErrDatabaseInUse) {
+		t.Errorf("UnlockDB() with a held lock error = %v, want ErrDatabaseInUse", err)
+	}
+}
+
+func TestUnlockDB_AlreadyUnlocked(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app-a", "app-a", "api_key", "s3cret", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	db := d.db
+
+	if err := d.UnlockDB(testPassphrase); !errors.Is(err, ErrAlreadyUnlocked) {
+		t.Fatalf("second UnlockDB() error = %v, want ErrAlreadyUnlocked", err)
+	}
+	if d.db != db {
+		t.Error("second UnlockDB() reopened the database")
+	}
+	if got, err := d.GetSecret("app-a", "app-a", "api_key"); err != nil || got != "s3cret" {
+		t.Errorf("GetSecret() after second unlock = %q, %v; want %q", got, err, "s3cret")
+	}
+	if m := d.Metrics(); m.Unlocks != 1 || m.FailedUnlocks != 0 {
+		t.Errorf("Metrics() unlocks = %d, failed = %d; want 1 and 0", m.Unlocks, m.FailedUnlocks)
+	}
+
+	res, err := (&gaiaAdminServer{d: d}).Unlock(t.Context(), &pb.UnlockRequest{Passphrase: testPassphrase})
+	if err != nil || !res.Success || !res.AlreadyUnlocked {
+		t.Errorf("Unlock RPC on an unlocked daemon = %v, %v; want success with already_unlocked", res, err)
+	}
+}
+
+// freePort returns a TCP port that was free at the time of the call.
+func freePort(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer lis.Close()
+	return fmt.Sprint(lis.Addr().(*net.TCPAddr).Port)
+}
+
+// startTestDaemon runs Start in the background and waits until it accepts connections.
+func startTestDaemon(t *testing.T, d *Daemon, cfg *config.Config) <-chan error {
+	t.Helper()
+	errCh := make(chan error, 1)
+	go func() { errCh <- d.Start(cfg) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", "127.0.0.1:"+cfg.GRPCPort)
+		if err == nil {
+			conn.Close()
+			return errCh
+		}
+		select {
+		case err := <-errCh:
+			t.Fatalf("Start() error = %v", err)
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("daemon did not start listening: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestStop_FreesListener(t *testing.T) {
+	port := freePort(t)
+	cfg := newTestConfig(t, func(c *config.Config) { c.GRPCPort = port })
+	d := NewDaemon(cfg)
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	errCh := startTestDaemon(t, d, cfg)
+
+	if err := d.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := d.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("Start() returned error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start() did not return after Stop()")
+	}
+	if got := d.Status(); got != StatusStopped {
+		t.Errorf("Status() = %q, want %q", got, StatusStopped)
+	}
+
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		t.Fatalf("listener not freed after Stop(): %v", err)
+	}
+	lis.Close()
+}
+
+// dialTestDaemon opens an mTLS connection to a daemon started with startTestDaemon.
+func TestGetDaemonStatus_ResolvesCertsDirectory(t *testing.T) {
+	port := freePort(t)
+	cfg := newTestConfig(t, func(c *config.Config) { c.GRPCPort = port })
+	d := NewDaemon(cfg)
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	startTestDaemon(t, d, cfg)
+	t.Cleanup(func() { d.Close() })
+
+	// The status check used to read the certificate files relative to the
+	// working directory instead of the certs directory.
+	t.Chdir(t.TempDir())
+	status, err := getDaemonStatus(cfg)
+	if err != nil {
+		t.Fatalf("getDaemonStatus() error = %v", err)
+	}
+	if status != StatusRunning {
+		t.Errorf("getDaemonStatus() = %q, want %q", status, StatusRunning)
+	}
+}
+
+func TestGetDaemonStatus_NonLocalServerName(t *testing.T) {
+	port := freePort(t)
+	cfg := newTestConfig(t, func(c *config.Config) {
+		c.GRPCPort = port
+		c.GRPCServerName = "gaia.internal"
+		c.CertsDirectory = t.TempDir()
+	})
+	if err := certs.GenerateCA(cfg, "Gaia Test CA"); err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	if err := certs.GenerateServerCertificate(cfg, cfg.GRPCServerName); err != nil {
+		t.Fatalf("GenerateServerCertificate() error = %v", err)
+	}
+	if err := certs.GenerateClientCertificate(cfg, testClientCertName); err != nil {
+		t.Fatalf("GenerateClientCertificate() error = %v", err)
+	}
+	d := NewDaemon(cfg)
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	startTestDaemon(t, d, cfg)
+	t.Cleanup(func() { d.Close() })
+
+	// The client verifies the certificate against the configured server name
+	// rather than localhost, which the certificate still covers as a fallback.
+	status, err := getDaemonStatus(cfg)
+	if err != nil {
+		t.Fatalf("getDaemonStatus() error = %v", err)
+	}
+	if status != StatusRunning {
+		t.Errorf("getDaemonStatus() = %q, want %q", status, StatusRunning)
+	}
+	serverCert := filepath.Join(cfg.CertsDirectory, cfg.ServerCertFile)
+	caCert := filepath.Join(cfg.CertsDirectory, cfg.CACertFile)
+	for _, name := range []string{"gaia.internal", "localhost", "127.0.0.1"} {
+		if _, err := certs.VerifyCertificate(caCert, serverCert, name); err != nil {
+			t.Errorf("server certificate does not cover %q: %v", name, err)
+		}
+	}
+
+	cfg.GRPCServerName = "other.internal"
+	if _, err := getDaemonStatus(cfg); err == nil {
+		t.Error("getDaemonStatus() accepted a certificate that does not cover the server name")
+	}
+}
+
+func dialTestDaemon(t *testing.T, cfg *config.Config) *grpc.ClientConn {
+	t.Helper()
+	caPEM, err := os.ReadFile(filepath.Join(cfg.CertsDirectory, cfg.CACertFile))
+	if err != nil {
+		t.Fatalf("failed to read CA certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caPEM)
+	clientCert, err := tls.LoadX509KeyPair(
+		filepath.Join(cfg.CertsDirectory, testClientCertName+".crt"),
+		filepath.Join(cfg.CertsDirectory, testClientCertName+".key"),
+	)
+	if err != nil {
+		t.Fatalf("failed to load client key pair: %v", err)
+	}
+	creds := credentials.NewTLS(&tls.Config{
+		ServerName:   "localhost",
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{clientCert},
+	})
+	conn, err := grpc.NewClient("127.0.0.1:"+cfg.GRPCPort, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestStop_ForcesCloseAfterGracePeriod(t *testing.T) {
+	const grace = 300 * time.Millisecond
+	cfg := newTestConfig(t, func(c *config.Config) {
+		c.GRPCPort = freePort(t)
+		c.ShutdownGracePeriod = grace
+	})
+	d := NewDaemon(cfg)
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() error = %v", err)
+	}
+	errCh := startTestDaemon(t, d, cfg)
+
+	// Hold an import stream open: the handler keeps waiting for more items.
+	stream, err := pb.NewGaiaAdminClient(dialTestDaemon(t, cfg)).ImportSecrets(context.Background())
+	if err != nil {
+		t.Fatalf("ImportSecrets() error = %v", err)
+	}
+	configReq := &pb.ImportSecretsRequest{
+		Payload: &pb.ImportSecretsRequest_Config{Config: &pb.ImportSecretsConfig{}},
+	}
+	if err := stream.Send(configReq); err != nil {
+		t.Fatalf("failed to send import config: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	if err := d.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	elapsed := time.Since(start)
+	if elapsed < grace {
+		t.Errorf("Stop() returned after %v, before the grace period; the stream was not held", elapsed)
+	}
+	if elapsed > grace+2*time.Second {
+		t.Errorf("Stop() took %v, want it to finish shortly after the %v grace period", elapsed, grace)
+	}
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("Start() returned error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start() did not return after Stop()")
+	}
+}
+
+func TestMoveNamespace(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, id := range []string{"db_url", "api_key"} {
+		if _, err := d.AddSecret("app-a", "shared", id, "value-"+id, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	if _, err := d.AddSecret("app-a", "other", "keep", "stays", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	moved, err := d.MoveNamespace("app-a", "app-b", "shared", false)
+	if err != nil {
+		t.Fatalf("MoveNamespace() error = %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("MoveNamespace() moved %d secrets, want 2", moved)
+	}
+
+	dst, err := d.ListSecrets(context.Background(), "app-b")
+	if err != nil {
+		t.Fatalf("ListSecrets(app-b) error = %v", err)
+	}
+	if got := dst["shared"]["db_url"]; got != "value-db_url" {
+		t.Errorf("moved db_url = %q, want %q", got, "value-db_url")
+	}
+	src, err := d.ListSecrets(context.Background(), "app-a")
+	if err != nil {
+		t.Fatalf("ListSecrets(app-a) error = %v", err)
+	}
+	if _, ok := src["shared"]; ok {
+		t.Errorf("namespace 'shared' still present for the source client: %v", src["shared"])
+	}
+	if src["other"]["keep"] != "stays" {
+		t.Errorf("unrelated namespace was changed: %v", src)
+	}
+
+	if _, err := d.MoveNamespace("app-a", "app-b", "shared", false); err == nil {
+		t.Error("moving an empty namespace succeeded, want an error")
+	}
+}
+
+func TestMoveNamespace_Collision(t *testing.T) {
+	setup := func(t *testing.T) *Daemon {
+		d := newTestDaemon(t)
+		for client, value := range map[string]string{"app-a": "from-a", "app-b": "from-b"} {
+			if _, err := d.AddSecret(client, "shared", "token", value, AddSecretOptions{}); err != nil {
+				t.Fatalf("AddSecret() error = %v", err)
+			}
+		}
+		if _, err := d.AddSecret("app-a", "shared", "extra", "only-a", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+		return d
+	}
+
+	t.Run("without overwrite", func(t *testing.T) {
+		d := setup(t)
+		_, err := d.MoveNamespace("app-a", "app-b", "shared", false)
+		if !errors.Is(err, ErrSecretExists) {
+			t.Fatalf("MoveNamespace() error = %v, want ErrSecretExists", err)
+		}
+		// The whole move is rolled back.
+		src, _ := d.ListSecrets(context.Background(), "app-a")
+		if len(src["shared"]) != 2 {
+			t.Errorf("source namespace = %v, want both secrets kept", src["shared"])
+		}
+		dst, _ := d.ListSecrets(context.Background(), "app-b")
+		if dst["shared"]["token"] != "from-b" || len(dst["shared"]) != 1 {
+			t.Errorf("destination namespace = %v, want it unchanged", dst["shared"])
+		}
+	})
+
+	t.Run("with overwrite", func(t *testing.T) {
+		d := setup(t)
+		moved, err := d.MoveNamespace("app-a", "app-b", "shared", true)
+		if err != nil {
+			t.Fatalf("MoveNamespace() error = %v", err)
+		}
+		if moved != 2 {
+			t.Errorf("MoveNamespace() moved %d secrets, want 2", moved)
+		}
+		dst, _ := d.ListSecrets(context.Background(), "app-b")
+		if dst["shared"]["token"] != "from-a" || dst["shared"]["extra"] != "only-a" {
+			t.Errorf("destination namespace = %v, want the source values", dst["shared"])
+		}
+	})
+}
+
+func TestMoveNamespace_DestinationRules(t *testing.T) {
+	setup := func(t *testing.T, opts ...func(*config.Config)) *Daemon {
+		d := newTestDaemon(t, opts...)
+		for _, id := range []string{"db_url", "api_key"} {
+			if _, err := d.AddSecret("app-a", "shared", id, "value-"+id, AddSecretOptions{}); err != nil {
+				t.Fatalf("AddSecret() error = %v", err)
+			}
+		}
+		return d
+	}
+
+	t.Run("namespace patterns", func(t *testing.T) {
+		d := setup(t)
+		if err := d.SetNamespacePatterns("app-b", []string{"app-b-.*"}); err != nil {
+			t.Fatalf("SetNamespacePatterns() error = %v", err)
+		}
+		_, err := NewAdminServer(d).MoveNamespace(context.Background(), &pb.MoveNamespaceRequest{
+			SrcClient: "app-a", DstClient: "app-b", Namespace: "shared",
+		})
+		if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "app-b-.*") {
+			t.Errorf("MoveNamespace() to a disallowed namespace error = %v, want InvalidArgument", err)
+		}
+		if src, _ := d.ListSecrets(context.Background(), "app-a"); len(src["shared"]) != 2 {
+			t.Errorf("source namespace after a refused move = %v, want both secrets kept", src["shared"])
+		}
+		if namespaces, _ := d.ListNamespaces("app-b"); slices.Contains(namespaces, "shared") {
+			t.Error("refused move wrote to the destination client")
+		}
+	})
+
+	t.Run("quota", func(t *testing.T) {
+		d := setup(t, func(c *config.Config) { c.MaxSecretsPerNamespace = 2 })
+		if _, err := d.AddSecret("app-b", "shared", "own", "v", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+		_, err := NewAdminServer(d).MoveNamespace(context.Background(), &pb.MoveNamespaceRequest{
+			SrcClient: "app-a", DstClient: "app-b", Namespace: "shared",
+		})
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("MoveNamespace() beyond the quota error = %v, want ResourceExhausted", err)
+		}
+		if src, _ := d.ListSecrets(context.Background(), "app-a"); len(src["shared"]) != 2 {
+			t.Errorf("source namespace after a refused move = %v, want both secrets kept", src["shared"])
+		}
+	})
+}
+
+func TestMoveNamespace_Validation(t *testing.T) {
+	d := newTestDaemon(t)
+	srv := NewAdminServer(d)
+	_, err := srv.MoveNamespace(context.Background(), &pb.MoveNamespaceRequest{
+		SrcClient: "app-a", DstClient: "bad name!", Namespace: "shared",
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("MoveNamespace() with an invalid destination error = %v, want InvalidArgument", err)
+	}
+
+	d.LockDB()
+	if _, err := d.MoveNamespace("app-a", "app-b", "shared", false); err == nil {
+		t.Error("MoveNamespace() on a locked daemon succeeded, want an error")
+	}
+}
+
+func TestRenameSecret(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.TrackSecretAccess = true })
+	for id, value := range map[string]string{"databse_url": "postgres://db", "api_key": "k"} {
+		if _, err := d.AddSecret("app", "app", id, value, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret(%s) error = %v", id, err)
+		}
+	}
+	for range 2 {
+		if _, err := d.GetSecret("app", "app", "databse_url"); err != nil {
+			t.Fatalf("GetSecret() error = %v", err)
+		}
+	}
+
+	if err := d.RenameSecret("app", "app", "databse_url", "database_url", false); err != nil {
+		t.Fatalf("RenameSecret() error = %v", err)
+	}
+	if got, err := d.GetSecret("app", "app", "database_url"); err != nil || got != "postgres://db" {
+		t.Errorf("GetSecret(database_url) = %q, %v; want %q", got, err, "postgres://db")
+	}
+	if _, err := d.GetSecret("app", "app", "databse_url"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("GetSecret(databse_url) after rename error = %v, want ErrSecretNotFound", err)
+	}
+	usage, err := d.SecretUsage("app")
+	if err != nil {
+		t.Fatalf("SecretUsage() error = %v", err)
+	}
+	for _, u := range usage {
+		// Two reads before the rename and one after it.
+		if u.ID == "database_url" && u.AccessCount != 3 {
+			t.Errorf("AccessCount of the renamed secret = %d, want 3", u.AccessCount)
+		}
+	}
+
+	// A collision fails without changing either secret, unless overwrite is set.
+	srv := NewAdminServer(d)
+	_, err = srv.RenameSecret(context.Background(), &pb.RenameSecretRequest{
+		ClientName: "app", Namespace: "app", OldId: "database_url", NewId: "api_key",
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("RenameSecret() onto an existing id error = %v, want AlreadyExists", err)
+	}
+	if got, _ := d.GetSecret("app", "app", "api_key"); got != "k" {
+		t.Errorf("api_key after a rejected rename = %q, want %q", got, "k")
+	}
+	if err := d.RenameSecret("app", "app", "database_url", "api_key", true); err != nil {
+		t.Fatalf("RenameSecret(overwrite) error = %v", err)
+	}
+	if got, _ := d.GetSecret("app", "app", "api_key"); got != "postgres://db" {
+		t.Errorf("api_key after an overwriting rename = %q, want %q", got, "postgres://db")
+	}
+
+	_, err = srv.RenameSecret(context.Background(), &pb.RenameSecretRequest{
+		ClientName: "app", Namespace: "app", OldId: "missing", NewId: "other",
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("RenameSecret() of a missing secret error = %v, want NotFound", err)
+	}
+	_, err = srv.RenameSecret(context.Background(), &pb.RenameSecretRequest{
+		ClientName: "app", Namespace: "app", OldId: "api_key", NewId: "bad id!",
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("RenameSecret() to an invalid id error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestSetSecrets_Atomic(t *testing.T) {
+	d := newTestDaemon(t)
+	srv := NewAdminServer(d)
+	if _, err := d.AddSecret("app", "app", "db_url", "existing", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	count := func() int {
+		secrets, err := d.ListSecrets(context.Background(), "app")
+		if err != nil {
+			t.Fatalf("ListSecrets() error = %v", err)
+		}
+		return len(secrets["app"])
+	}
+
+	_, err := srv.SetSecrets(context.Background(), &pb.SetSecretsRequest{Secrets: []*pb.ImportSecretItem{
+		{ClientName: "app", Namespace: "app", Id: "api_key", Value: "k"},
+		{ClientName: "app", Namespace: "app", Id: "bad id!", Value: "v"},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SetSecrets() with an invalid id error = %v, want InvalidArgument", err)
+	}
+
+	// The conflict on the last secret rolls back the ones before it.
+	_, err = srv.SetSecrets(context.Background(), &pb.SetSecretsRequest{Secrets: []*pb.ImportSecretItem{
+		{ClientName: "app", Namespace: "app", Id: "api_key", Value: "k"},
+		{ClientName: "app", Namespace: "app", Id: "db_url", Value: "replaced"},
+	}})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("SetSecrets() over an existing secret error = %v, want AlreadyExists", err)
+	}
+	if n := count(); n != 1 {
+		t.Errorf("client has %d secrets after rejected requests, want 1", n)
+	}
+
+	res, err := srv.SetSecrets(context.Background(), &pb.SetSecretsRequest{Overwrite: true, Secrets: []*pb.ImportSecretItem{
+		{ClientName: "app", Namespace: "app", Id: "api_key", Value: "k"},
+		{ClientName: "app", Namespace: "app", Id: "db_url", Value: "replaced"},
+		{ClientName: "app", Namespace: "app", Id: "timeout", Value: "30s"},
+	}})
+	if err != nil {
+		t.Fatalf("SetSecrets(overwrite) error = %v", err)
+	}
+	if res.SecretsSet != 3 || res.Unreachable != 0 {
+		t.Errorf("SetSecrets(overwrite) = %d set, %d unreachable; want 3 and 0", res.SecretsSet, res.Unreachable)
+	}
+	if got, err := d.GetSecret("app", "app", "db_url"); err != nil || got != "replaced" {
+		t.Errorf("GetSecret(db_url) = %q, %v; want %q", got, err, "replaced")
+	}
+	if n := count(); n != 3 {
+		t.Errorf("client has %d secrets, want 3", n)
+	}
+}
+
+func TestImportSecrets_Merge(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "db_url", "existing", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	items := []*pb.ImportSecretItem{
+		{ClientName: "app", Namespace: "app", Id: "db_url", Value: "from-file"},
+		{ClientName: "app", Namespace: "app", Id: "api_key", Value: "new-key"},
+		{ClientName: "app", Namespace: "app", Id: "timeout", Value: "30s"},
+	}
+
+	if _, err := d.ImportSecrets(items, ImportFailOnConflict, ""); !errors.Is(err, ErrSecretExists) {
+		t.Fatalf("ImportSecrets(fail on conflict) error = %v, want ErrSecretExists", err)
+	}
+
+	res, err := d.ImportSecrets(items, ImportMerge, "")
+	if err != nil {
+		t.Fatalf("ImportSecrets(merge) error = %v", err)
+	}
+	if res.Imported != 2 || res.Skipped != 1 {
+		t.Errorf("ImportSecrets(merge) = %d added, %d skipped, want 2 added, 1 skipped", res.Imported, res.Skipped)
+	}
+
+	got, err := d.ListSecrets(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	want := map[string]string{"db_url": "existing", "api_key": "new-key", "timeout": "30s"}
+	for id, value := range want {
+		if got["app"][id] != value {
+			t.Errorf("secret %q = %q, want %q", id, got["app"][id], value)
+		}
+	}
+}
+
+func TestImportSecrets_BatchID(t *testing.T) {
+	d := newTestDaemon(t)
+	items := []*pb.ImportSecretItem{
+		{ClientName: "app", Namespace: "app", Id: "db_url", Value: "postgres://a"},
+		{ClientName: "app", Namespace: "app", Id: "api_key", Value: "k1"},
+	}
+
+	first, err := d.ImportSecrets(items, ImportFailOnConflict, "deploy-42")
+	if err != nil {
+		t.Fatalf("ImportSecrets() error = %v", err)
+	}
+	if first.Imported != 2 || first.Replayed {
+		t.Fatalf("first ImportSecrets() = %+v, want 2 imported", first)
+	}
+
+	// A retry must not conflict with the secrets written by the first attempt.
+	second, err := d.ImportSecrets(items, ImportFailOnConflict, "deploy-42")
+	if err != nil {
+		t.Fatalf("retried ImportSecrets() error = %v", err)
+	}
+	if !second.Replayed || second.Imported != first.Imported {
+		t.Errorf("retried ImportSecrets() = %+v, want a replay reporting %d imported", second, first.Imported)
+	}
+
+	changed := []*pb.ImportSecretItem{{ClientName: "app", Namespace: "app", Id: "api_key", Value: "k2"}}
+	if _, err := d.ImportSecrets(changed, ImportOverwrite, "deploy-42"); err != nil {
+		t.Fatalf("ImportSecrets() error = %v", err)
+	}
+	if got, _ := d.GetSecret("app", "app", "api_key"); got != "k1" {
+		t.Errorf("replayed batch changed api_key to %q", got)
+	}
+
+	// Once the record has expired, the batch ID is applied again.
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		return recordImportBatch(tx, "deploy-42", importBatch{Imported: 2, CompletedAt: time.Now().Add(-2 * importBatchRetention)})
+	})
+	if err != nil {
+		t.Fatalf("failed to age the batch record: %v", err)
+	}
+	res, err := d.ImportSecrets(changed, ImportOverwrite, "deploy-42")
+	if err != nil {
+		t.Fatalf("ImportSecrets() after expiry error = %v", err)
+	}
+	if res.Replayed || res.Imported != 1 {
+		t.Errorf("ImportSecrets() after expiry = %+v, want 1 imported", res)
+	}
+}
+
+func TestCreateSecretIfAbsent_Concurrent(t *testing.T) {
+	d := newTestDaemon(t)
+
+	const callers = 8
+	values := make([]string, callers)
+	created := make([]bool, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], created[i], errs[i] = d.CreateSecretIfAbsent("app-a", "app-a", "session_key", fmt.Sprintf("generated-%d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	creates := 0
+	for i := range callers {
+		if errs[i] != nil {
+			t.Fatalf("CreateSecretIfAbsent() caller %d error = %v", i, errs[i])
+		}
+		if values[i] != values[0] {
+			t.Errorf("caller %d got %q, want %q like caller 0", i, values[i], values[0])
+		}
+		if created[i] {
+			creates++
+		}
+	}
+	if creates != 1 {
+		t.Errorf("secret created %d times, want exactly once", creates)
+	}
+	if got, err := d.GetSecret("app-a", "app-a", "session_key"); err != nil || got != values[0] {
+		t.Errorf("GetSecret() = %q, %v; want %q", got, err, values[0])
+	}
+
+	if _, _, err := d.CreateSecretIfAbsent("app-a", "app-b", "session_key", "v"); err == nil {
+		t.Error("CreateSecretIfAbsent() in another client's namespace succeeded")
+	}
+}
+
+func TestLoadTLSCredentials_Diagnostics(t *testing.T) {
+	// newCertsDir generates a fresh CA and server certificate, then applies breakage.
+	newCertsDir := func(t *testing.T, breakage func(cfg *config.Config)) *config.Config {
+		t.Helper()
+		cfg := newTestConfig(t, func(c *config.Config) { c.CertsDirectory = t.TempDir() })
+		if err := certs.GenerateCA(cfg, "Gaia Test CA"); err != nil {
+			t.Fatalf("GenerateCA() error = %v", err)
+		}
+		if err := certs.GenerateServerCertificate(cfg, "localhost"); err != nil {
+			t.Fatalf("GenerateServerCertificate() error = %v", err)
+		}
+		breakage(cfg)
+		return cfg
+	}
+	remove := func(file func(*config.Config) string) func(*config.Config) {
+		return func(cfg *config.Config) {
+			if err := os.Remove(filepath.Join(cfg.CertsDirectory, file(cfg))); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name    string
+		breakIt func(*config.Config)
+		want    []string
+	}{
+		{
+			name:    "missing CA certificate",
+			breakIt: remove(func(c *config.Config) string { return c.CACertFile }),
+			want:    []string{"CA certificate not found", "ca.crt", "gaia certs generate"},
+		},
+		{
+			name:    "missing server certificate",
+			breakIt: remove(func(c *config.Config) string { return c.ServerCertFile }),
+			want:    []string{"server certificate not found", "server.crt", "gaia certs create-server"},
+		},
+		{
+			name:    "missing server key",
+			breakIt: remove(func(c *config.Config) string { return c.ServerKeyFile }),
+			want:    []string{"server key not found", "server.key", "gaia certs create-server"},
+		},
+		{
+			name: "server key of another certificate",
+			breakIt: func(cfg *config.Config) {
+				// Issuing a client certificate leaves a key that does not match server.crt.
+				if err := certs.GenerateClientCertificate(cfg, "other"); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Rename(filepath.Join(cfg.CertsDirectory, "other.key"), filepath.Join(cfg.CertsDirectory, cfg.ServerKeyFile)); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"does not match server certificate", "gaia certs create-server"},
+		},
+		{
+			name: "server certificate from another CA",
+			breakIt: func(cfg *config.Config) {
+				if err := certs.GenerateCA(cfg, "Another CA"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"was not issued by the CA", "gaia certs create-server"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newCertsDir(t, tt.breakIt)
+			_, err := NewDaemon(cfg).loadTLSCredentials()
+			if err == nil {
+				t.Fatal("loadTLSCredentials() succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("loadTLSCredentials() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+
+	cfg := newCertsDir(t, func(*config.Config) {})
+	if _, err := NewDaemon(cfg).loadTLSCredentials(); err != nil {
+		t.Errorf("loadTLSCredentials() with complete TLS material error = %v", err)
+	}
+}
+
+func TestServerTLSConfig_CARotationTransition(t *testing.T) {
+	readCert := func(t *testing.T, path string) *x509.Certificate {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			t.Fatalf("%s holds no PEM block", path)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert
+	}
+	// rotated returns a certs directory whose CA was rotated after issuing the
+	// client certificate old-app, and the certificates of old-app and of new-app,
+	// issued after the rotation.
+	rotated := func(t *testing.T, keepPrevious bool) (*config.Config, *x509.Certificate, *x509.Certificate, *x509.Certificate) {
+		t.Helper()
+		cfg := newTestConfig(t, func(c *config.Config) { c.CertsDirectory = t.TempDir() })
+		if err := certs.GenerateCA(cfg, "Gaia Test CA"); err != nil {
+			t.Fatalf("GenerateCA() error = %v", err)
+		}
+		if err := certs.GenerateServerCertificate(cfg, "localhost"); err != nil {
+			t.Fatalf("GenerateServerCertificate() error = %v", err)
+		}
+		if err := certs.GenerateClientCertificate(cfg, "old-app"); err != nil {
+			t.Fatalf("GenerateClientCertificate() error = %v", err)
+		}
+		oldCA := readCert(t, filepath.Join(cfg.CertsDirectory, cfg.CACertFile))
+		if _, err := certs.RotateCA(cfg, "Gaia Test CA 2", keepPrevious); err != nil {
+			t.Fatalf("RotateCA() error = %v", err)
+		}
+		if err := certs.GenerateClientCertificate(cfg, "new-app"); err != nil {
+			t.Fatalf("GenerateClientCertificate() error = %v", err)
+		}
+		return cfg, oldCA,
+			readCert(t, filepath.Join(cfg.CertsDirectory, "old-app.crt")),
+			readCert(t, filepath.Join(cfg.CertsDirectory, "new-app.crt"))
+	}
+	clientAuth := []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	cfg, oldCA, oldClient, newClient := rotated(t, true)
+	tlsConfig, err := NewDaemon(cfg).serverTLSConfig()
+	if err != nil {
+		t.Fatalf("serverTLSConfig() error = %v", err)
+	}
+	for name, cert := range map[string]*x509.Certificate{"old-app": oldClient, "new-app": newClient} {
+		if _, err := cert.Verify(x509.VerifyOptions{Roots: tlsConfig.ClientCAs, KeyUsages: clientAuth}); err != nil {
+			t.Errorf("client certificate of %s does not verify during the transition: %v", name, err)
+		}
+	}
+
+	// Clients that trust only the old or only the new CA both accept the server.
+	chain := tlsConfig.Certificates[0].Certificate
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediates := x509.NewCertPool()
+	for _, der := range chain[1:] {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		intermediates.AddCert(cert)
+	}
+	newCA := readCert(t, filepath.Join(cfg.CertsDirectory, cfg.CACertFile))
+	for name, ca := range map[string]*x509.Certificate{"old": oldCA, "new": newCA} {
+		roots := x509.NewCertPool()
+		roots.AddCert(ca)
+		if _, err := leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: roots, Intermediates: intermediates}); err != nil {
+			t.Errorf("server certificate does not verify against the %s CA: %v", name, err)
+		}
+	}
+
+	cfg, _, oldClient, newClient = rotated(t, false)
+	tlsConfig, err = NewDaemon(cfg).serverTLSConfig()
+	if err != nil {
+		t.Fatalf("serverTLSConfig() without transition error = %v", err)
+	}
+	if _, err := oldClient.Verify(x509.VerifyOptions{Roots: tlsConfig.ClientCAs, KeyUsages: clientAuth}); err == nil {
+		t.Error("client certificate of the old CA verifies without a transition")
+	}
+	if _, err := newClient.Verify(x509.VerifyOptions{Roots: tlsConfig.ClientCAs, KeyUsages: clientAuth}); err != nil {
+		t.Errorf("client certificate of the new CA does not verify: %v", err)
+	}
+}
+
+func TestExportImportClients_RoundTrip(t *testing.T) {
+	src := newTestDaemon(t)
+	for _, name := range []string{"billing", "reports"} {
+		if err := src.RegisterClient(name); err != nil {
+			t.Fatalf("RegisterClient(%s) error = %v", name, err)
+		}
+	}
+	// Give one client an older creation time, as a long-lived registration has.
+	if _, _, err := src.ImportClients([]Client{{Name: "legacy", TimeCreated: "2021-03-04T05:06:07Z"}}, false); err != nil {
+		t.Fatalf("ImportClients() error = %v", err)
+	}
+	exported, err := NewAdminServer(src).ExportClients(context.Background(), &pb.ExportClientsRequest{})
+	if err != nil {
+		t.Fatalf("ExportClients() error = %v", err)
+	}
+
+	dst := newTestDaemon(t)
+	dstSrv := NewAdminServer(dst)
+	res, err := dstSrv.ImportClients(context.Background(), &pb.ImportClientsRequest{Clients: exported.Clients})
+	if err != nil {
+		t.Fatalf("ImportClients() error = %v", err)
+	}
+	// The common client is registered at init in both databases.
+	if res.ClientsImported != 3 || res.ClientsSkipped != 1 {
+		t.Errorf("ImportClients() = %d imported, %d skipped; want 3 and 1", res.ClientsImported, res.ClientsSkipped)
+	}
+	restored, err := dstSrv.ExportClients(context.Background(), &pb.ExportClientsRequest{})
+	if err != nil {
+		t.Fatalf("ExportClients() error = %v", err)
+	}
+	want := make(map[string]string)
+	for _, c := range exported.Clients {
+		want[c.Name] = c.TimeCreated
+	}
+	got := make(map[string]string)
+	for _, c := range restored.Clients {
+		got[c.Name] = c.TimeCreated
+	}
+	if got["legacy"] != "2021-03-04T05:06:07Z" {
+		t.Errorf("restored creation time of legacy = %q, want 2021-03-04T05:06:07Z", got["legacy"])
+	}
+	for name, created := range want {
+		if name != commonNamespace && got[name] != created {
+			t.Errorf("restored client %s created %q, want %q", name, got[name], created)
+		}
+	}
+
+	_, err = dstSrv.ImportClients(context.Background(), &pb.ImportClientsRequest{Clients: []*pb.Client{{Name: "x", TimeCreated: "yesterday"}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ImportClients() with a bad creation time error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestImportSecretsWithProgress(t *testing.T) {
+	d := newTestDaemon(t)
+	conn := serveWithUnlockGate(t, d)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := pb.NewGaiaAdminClient(conn).ImportSecretsWithProgress(ctx)
+	if err != nil {
+		t.Fatalf("ImportSecretsWithProgress() error = %v", err)
+	}
+	const total = 2*importProgressInterval + 10
+	go func() {
+		defer stream.CloseSend()
+		if err := stream.Send(&pb.ImportSecretsRequest{Payload: &pb.ImportSecretsRequest_Config{Config: &pb.ImportSecretsConfig{}}}); err != nil {
+			return
+		}
+		for i := range total {
+			item := &pb.ImportSecretItem{ClientName: "app", Namespace: "app", Id: fmt.Sprintf("key_%d", i), Value: "v"}
+			if err := stream.Send(&pb.ImportSecretsRequest{Payload: &pb.ImportSecretsRequest_Item{Item: item}}); err != nil {
+				return
+			}
+		}
+	}()
+
+	var progress []int32
+	var result *pb.ImportSecretsResponse
+	for result == nil {
+		msg, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		switch p := msg.Payload.(type) {
+		case *pb.ImportSecretsProgress_ItemsReceived:
+			progress = append(progress, p.ItemsReceived)
+		case *pb.ImportSecretsProgress_Result:
+			result = p.Result
+		}
+	}
+	if want := []int32{importProgressInterval, 2 * importProgressInterval}; !slices.Equal(progress, want) {
+		t.Errorf("progress before the result = %v, want %v", progress, want)
+	}
+	if result.SecretsImported != total {
+		t.Errorf("SecretsImported = %d, want %d", result.SecretsImported, total)
+	}
+}
+
+func withBoltTuning(cfg *config.Config) {
+	cfg.DBNoSync = true
+	cfg.DBNoFreelistSync = true
+	cfg.DBFreelistType = string(bbolt.FreelistMapType)
+	cfg.DBInitialMmapSize = 64 << 20
+}
+
+func TestBoltTuning_SurvivesClose(t *testing.T) {
+	d := newTestDaemon(t, withBoltTuning)
+	if _, err := d.AddSecret("app", "app", "token", "v1", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.ImportSecrets(importItems(100), ImportFailOnConflict, ""); err != nil {
+		t.Fatalf("ImportSecrets() error = %v", err)
+	}
+
+	d.LockDB()
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() after close error = %v", err)
+	}
+	if v, err := d.GetSecret("app", "app", "token"); err != nil || v != "v1" {
+		t.Errorf("GetSecret() after close = %q, %v; want v1", v, err)
+	}
+	if v, err := d.GetSecret("app", "app", "key_99"); err != nil || v != "value_99" {
+		t.Errorf("GetSecret() of an imported secret after close = %q, %v; want value_99", v, err)
+	}
+}
+
+func TestStart_InvalidFreelistType(t *testing.T) {
+	cfg := newTestConfig(t, func(c *config.Config) { c.DBFreelistType = "tree" })
+	d := NewDaemon(cfg)
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	if err := d.Start(cfg); err == nil || !strings.Contains(err.Error(), "db_freelist_type") {
+		t.Errorf("Start() error = %v, want an invalid db_freelist_type error", err)
+	}
+}
+
+// importItems returns n secrets of the client "app" for an import.
+func importItems(n int) []*pb.ImportSecretItem {
+	items := make([]*pb.ImportSecretItem, n)
+	for i := range items {
+		items[i] = &pb.ImportSecretItem{ClientName: "app", Namespace: "app", Id: fmt.Sprintf("key_%d", i), Value: fmt.Sprintf("value_%d", i)}
+	}
+	return items
+}
+
+func BenchmarkImportSecrets(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		opts []func(*config.Config)
+	}{
+		{name: "default"},
+		{name: "tuned", opts: []func(*config.Config){withBoltTuning}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			d := newTestDaemon(b, bc.opts...)
+			items := importItems(1000)
+			for b.Loop() {
+				if _, err := d.ImportSecrets(items, ImportOverwrite, ""); err != nil {
+					b.Fatalf("ImportSecrets() error = %v", err)
+				}
+			}
+		})
+	}
+}
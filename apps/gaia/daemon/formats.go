@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/stain-win/gaia/apps/gaia/formats"
+	"go.etcd.io/bbolt"
+)
+
+// secretFormatsBucket holds the format constraint of a secret, keyed like the
+// secrets bucket.
+const secretFormatsBucket = "secret_formats"
+
+// FormatNone removes the format constraint of a secret.
+const FormatNone = "none"
+
+// ErrInvalidFormat is returned for writes of a value that does not parse in the
+// format of its secret, and for unknown formats. Nothing is written.
+var ErrInvalidFormat = errors.New("secret value does not match its format")
+
+// resolveSecretFormat returns the format a write of key must satisfy: format
+// when it is set, or else the format stored for the secret.
+func resolveSecretFormat(tx *bbolt.Tx, key []byte, format string) (string, error) {
+	switch format {
+	case FormatNone:
+		return "", nil
+	case "":
+		if b := tx.Bucket([]byte(secretFormatsBucket)); b != nil {
+			return string(b.Get(key)), nil
+		}
+		return "", nil
+	}
+	if !formats.Known(format) {
+		return "", fmt.Errorf("%w: %w", ErrInvalidFormat, formats.Validate(format, ""))
+	}
+	return format, nil
+}
+
+// checkSecretFormat validates value against the format of the write of key,
+// see resolveSecretFormat, and records that format for the secret.
+func checkSecretFormat(tx *bbolt.Tx, key []byte, id, value, format string) error {
+	resolved, err := resolveSecretFormat(tx, key, format)
+	if err != nil {
+		return err
+	}
+	if resolved != "" {
+		if err := formats.Validate(resolved, value); err != nil {
+			return fmt.Errorf("%w: '%s' is not %s: %v", ErrInvalidFormat, id, resolved, err)
+		}
+	}
+	if format == "" {
+		return nil
+	}
+	b, err := tx.CreateBucketIfNotExists([]byte(secretFormatsBucket))
+	if err != nil {
+		return fmt.Errorf("failed to create or get secret formats bucket: %w", err)
+	}
+	if resolved == "" {
+		return b.Delete(key)
+	}
+	return b.Put(key, []byte(resolved))
+}
+
+// moveSecretFormat moves the format constraint of srcKey to dstKey, replacing
+// any constraint of dstKey.
+func moveSecretFormat(tx *bbolt.Tx, srcKey, dstKey []byte) error {
+	b := tx.Bucket([]byte(secretFormatsBucket))
+	if b == nil {
+		return nil
+	}
+	if err := b.Delete(dstKey); err != nil {
+		return err
+	}
+	if f := b.Get(srcKey); f != nil {
+		if err := b.Put(dstKey, bytes.Clone(f)); err != nil {
+			return err
+		}
+		return b.Delete(srcKey)
+	}
+	return nil
+}
+
+// deleteSecretFormats removes the format constraints of every key starting
+// with prefix.
+func deleteSecretFormats(tx *bbolt.Tx, prefix []byte) error {
+	b := tx.Bucket([]byte(secretFormatsBucket))
+	if b == nil {
+		return nil
+	}
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, bytes.Clone(k))
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
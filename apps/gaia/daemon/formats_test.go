@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAddSecret_Format(t *testing.T) {
+	d := newTestDaemon(t)
+	srv := NewAdminServer(d)
+
+	_, err := srv.AddSecret(context.Background(), &pb.AddSecretRequest{ClientName: "app", Namespace: "app", Id: "endpoint", Value: "not a url", Format: "url"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("AddSecret RPC with a malformed url error = %v, want InvalidArgument", err)
+	}
+	if _, err := d.GetSecret("app", "app", "endpoint"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("malformed secret was stored, GetSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "endpoint", "https://api.example.com", AddSecretOptions{Format: "url"}); err != nil {
+		t.Fatalf("AddSecret() with a valid url error = %v", err)
+	}
+
+	// The constraint holds for later writes that do not name a format.
+	if _, err := d.AddSecret("app", "app", "endpoint", "api.example.com", AddSecretOptions{}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("AddSecret() of a malformed value error = %v, want ErrInvalidFormat", err)
+	}
+	_, err = d.ImportSecrets([]*pb.ImportSecretItem{{ClientName: "app", Namespace: "app", Id: "endpoint", Value: "nope"}}, ImportOverwrite, "")
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ImportSecrets() of a malformed value error = %v, want ErrInvalidFormat", err)
+	}
+	if v, _ := d.GetSecret("app", "app", "endpoint"); v != "https://api.example.com" {
+		t.Errorf("GetSecret() = %q after rejected writes, want the valid url", v)
+	}
+
+	// The constraint moves with a rename and goes with a delete.
+	if err := d.RenameSecret("app", "app", "endpoint", "api_url", false); err != nil {
+		t.Fatalf("RenameSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "api_url", "plain", AddSecretOptions{}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("AddSecret() after a rename error = %v, want ErrInvalidFormat", err)
+	}
+	if _, err := d.AddSecret("app", "app", "endpoint", "plain", AddSecretOptions{}); err != nil {
+		t.Errorf("AddSecret() of the old id after a rename error = %v", err)
+	}
+	if err := d.DeleteSecret("app", "app", "api_url"); err != nil {
+		t.Fatalf("DeleteSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "api_url", "plain", AddSecretOptions{}); err != nil {
+		t.Errorf("AddSecret() after a delete error = %v, want the constraint gone", err)
+	}
+
+	if _, err := d.AddSecret("app", "app", "config", "{}", AddSecretOptions{Format: "json"}); err != nil {
+		t.Fatalf("AddSecret(json) error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "config", "plain", AddSecretOptions{Format: FormatNone}); err != nil {
+		t.Errorf("AddSecret(none) error = %v, want the constraint removed", err)
+	}
+	if _, err := d.AddSecret("app", "app", "config", "a: b", AddSecretOptions{Format: "yaml"}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("AddSecret() of an unknown format error = %v, want ErrInvalidFormat", err)
+	}
+}
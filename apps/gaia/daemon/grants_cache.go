@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"sync"
+
+	"go.etcd.io/bbolt"
+)
+
+// grantsCache keeps the common grants of every client read so far, so reads of
+// the common area do not walk commonGrantsBucket each time. Grants only change
+// under dbLock held for writing, which also invalidates the cache, so readers
+// holding dbLock for reading never see a stale entry. The mutex guards the map
+// against concurrent readers.
+type grantsCache struct {
+	mu     sync.Mutex
+	grants map[string][]string // nil value: no restriction
+}
+
+// get returns commonGrants(tx, clientName), from the cache when possible. The
+// result must not be modified.
+func (c *grantsCache) get(tx *bbolt.Tx, clientName string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if grants, ok := c.grants[clientName]; ok {
+		return grants
+	}
+	if c.grants == nil {
+		c.grants = make(map[string][]string)
+	}
+	grants := commonGrants(tx, clientName)
+	c.grants[clientName] = grants
+	return grants
+}
+
+// invalidate drops the cached grants of clientName after they changed.
+func (c *grantsCache) invalidate(clientName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.grants, clientName)
+}
+
+// reset drops every cached entry, e.g. when the database is closed.
+func (c *grantsCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.grants = nil
+}
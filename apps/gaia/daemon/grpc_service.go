@@ -5,31 +5,29 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
+	"net/url"
+	"runtime"
+	"slices"
+	"time"
 
 	"github.com/stain-win/gaia/apps/gaia/certs"
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
 	"github.com/stain-win/gaia/apps/gaia/validation"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
-func getClientIdentity(ctx context.Context) (string, error) {
-	p, ok := peer.FromContext(ctx)
-	if !ok {
-		return "", errors.New("could not get peer from context")
-	}
-	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
-	if !ok {
-		return "", errors.New("peer auth info is not TLS")
+// contextStatus maps a cancelled or expired context to the matching gRPC status,
+// leaving any other error untouched.
+func contextStatus(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
 	}
-	if len(tlsInfo.State.PeerCertificates) == 0 {
-		return "", errors.New("no peer certificates found")
-	}
-	// The client's certificate is the first in the chain.
-	clientCert := tlsInfo.State.PeerCertificates[0]
-	return clientCert.Subject.CommonName, nil
+	return err
 }
 
 // NewAdminServer creates a new server for the GaiaAdmin service.
@@ -44,10 +42,6 @@ func NewClientServer(d *Daemon) pb.GaiaClientServer {
 
 // AddSecret handles the AddSecret RPC call.
 func (s *gaiaAdminServer) AddSecret(_ context.Context, req *pb.AddSecretRequest) (*pb.AddSecretResponse, error) {
-	if s.d.isLocked {
-		return nil, errors.New("daemon is in a locked state, cannot add secrets")
-	}
-
 	if err := validation.ValidateName(req.ClientName); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
 	}
@@ -58,17 +52,32 @@ func (s *gaiaAdminServer) AddSecret(_ context.Context, req *pb.AddSecretRequest)
 		return nil, status.Errorf(codes.InvalidArgument, "invalid secret id: %v", err)
 	}
 
-	err := s.d.AddSecret(req.ClientName, req.Namespace, req.Id, req.Value)
+	warnings, err := s.d.AddSecret(req.ClientName, req.Namespace, req.Id, req.Value, AddSecretOptions{
+		Format:        req.Format,
+		Tags:          req.Tags,
+		EncryptFields: req.EncryptFields,
+		TTL:           time.Duration(req.TtlSeconds) * time.Second,
+	})
+	if errors.Is(err, ErrNamespaceNotAllowed) || errors.Is(err, ErrValueRejected) || errors.Is(err, ErrInvalidFormat) || errors.Is(err, ErrInvalidTag) || errors.Is(err, ErrInvalidTTL) ||
+		errors.Is(err, encrypt.ErrNotObject) || errors.Is(err, encrypt.ErrFieldNotFound) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 	if err != nil {
 		return &pb.AddSecretResponse{Success: false, Message: err.Error()}, nil
 	}
+	if len(warnings) > 0 {
+		return &pb.AddSecretResponse{Success: true, Message: "Secret added with warnings", Warnings: warnings}, nil
+	}
 	return &pb.AddSecretResponse{Success: true, Message: "Secret added successfully"}, nil
 }
 
 // DeleteSecret handles the gRPC request to delete a secret.
 func (s *gaiaAdminServer) DeleteSecret(_ context.Context, req *pb.DeleteSecretRequest) (*pb.DeleteSecretResponse, error) {
-	if s.d.isLocked {
-		return nil, errors.New("daemon is in a locked state, cannot delete secrets")
+	if err := s.checkReauth(req.Passphrase); err != nil {
+		return nil, err
 	}
 
 	if err := s.d.DeleteSecret(req.ClientName, req.Namespace, req.Id); err != nil {
@@ -78,25 +87,434 @@ func (s *gaiaAdminServer) DeleteSecret(_ context.Context, req *pb.DeleteSecretRe
 	return &pb.DeleteSecretResponse{Success: true}, nil
 }
 
+// checkReauth confirms a destructive RPC with the master passphrase when
+// RequireReauthForDestructive is set.
+func (s *gaiaAdminServer) checkReauth(passphrase string) error {
+	if !s.d.config.RequireReauthForDestructive {
+		return nil
+	}
+	if passphrase == "" {
+		return status.Error(codes.Unauthenticated, "this operation requires the master passphrase")
+	}
+	if err := s.d.VerifyPassphrase(passphrase); err != nil {
+		if errors.Is(err, ErrInvalidPassphrase) {
+			return status.Error(codes.Unauthenticated, "invalid passphrase")
+		}
+		return fmt.Errorf("failed to verify passphrase: %w", err)
+	}
+	return nil
+}
+
 // GetStatus handles the GetStatus RPC call.
 func (s *gaiaAdminServer) GetStatus(_ context.Context, _ *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
-	return &pb.GetStatusResponse{Status: s.d.Status()}, nil
+	locked, reason, since := s.d.LockState()
+	started := s.d.StartedAt()
+	return &pb.GetStatusResponse{
+		Status:        s.d.Status(),
+		Locked:        locked,
+		LockReason:    string(reason),
+		LockChanged:   since.Format(time.RFC3339),
+		StartedAt:     started.Format(time.RFC3339),
+		UptimeSeconds: int64(s.d.Uptime().Seconds()),
+		GoVersion:     runtime.Version(),
+		Os:            runtime.GOOS + "/" + runtime.GOARCH,
+		Banner:        s.d.GetConfig().Banner,
+	}, nil
+}
+
+// GetMetrics returns the Metrics snapshot, so monitoring can pull the counters
+// over the admin channel.
+func (s *gaiaAdminServer) GetMetrics(_ context.Context, _ *pb.GetMetricsRequest) (*pb.GetMetricsResponse, error) {
+	m := s.d.Metrics()
+	return &pb.GetMetricsResponse{
+		SecretsServed:  m.SecretsServed,
+		AccessDenied:   m.AccessDenied,
+		SecretsWritten: m.SecretsWritten,
+		SecretsDeleted: m.SecretsDeleted,
+		Unlocks:        m.Unlocks,
+		FailedUnlocks:  m.FailedUnlocks,
+		Locked:         m.Locked,
+	}, nil
 }
 
 // GetSecret handles the GetSecret RPC call.
 func (s *gaiaClientServer) GetSecret(ctx context.Context, req *pb.GetSecretRequest) (*pb.Secret, error) {
-	clientName, err := getClientIdentity(ctx)
+	clientName, err := getClientIdentity(ctx, s.daemon.config.IdentitySource, s.daemon.config.IdentityTrustDomain)
 	if err != nil {
 		return nil, fmt.Errorf("could not identify client: %w", err)
 	}
 
+	_, end := s.daemon.startAccessSpan(ctx, pb.GaiaClient_GetSecret_FullMethodName, clientName, req.Namespace)
 	value, err := s.daemon.GetSecret(clientName, req.Namespace, req.Id)
+	end(err)
+	if errors.Is(err, ErrSecretNotFound) || s.hideDenied(err) {
+		return nil, status.Error(codes.NotFound, ErrSecretNotFound.Error())
+	}
+	if errors.Is(err, ErrPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &pb.Secret{Id: req.Id, Value: value}, nil
 }
 
+// hideDenied reports whether err is a permission error that the client must not
+// be able to tell apart from a missing secret.
+func (s *gaiaClientServer) hideDenied(err error) bool {
+	return s.daemon.config.HideUnauthorizedAsNotFound && errors.Is(err, ErrPermissionDenied)
+}
+
+// CreateSecretIfAbsent handles the CreateSecretIfAbsent RPC call.
+func (s *gaiaClientServer) CreateSecretIfAbsent(ctx context.Context, req *pb.CreateSecretIfAbsentRequest) (*pb.CreateSecretIfAbsentResponse, error) {
+	clientName, err := getClientIdentity(ctx, s.daemon.config.IdentitySource, s.daemon.config.IdentityTrustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("could not identify client: %w", err)
+	}
+
+	if err := validation.ValidateName(req.Namespace); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+	}
+	if err := validation.ValidateName(req.Id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid secret id: %v", err)
+	}
+
+	_, end := s.daemon.startAccessSpan(ctx, pb.GaiaClient_CreateSecretIfAbsent_FullMethodName, clientName, req.Namespace)
+	value, created, err := s.daemon.CreateSecretIfAbsent(clientName, req.Namespace, req.Id, req.Value)
+	end(err)
+	if errors.Is(err, ErrNamespaceNotAllowed) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if errors.Is(err, ErrPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateSecretIfAbsentResponse{Value: value, Created: created}, nil
+}
+
+// ListOwnSecrets handles the ListOwnSecrets RPC call.
+func (s *gaiaClientServer) ListOwnSecrets(ctx context.Context, req *pb.ListOwnSecretsRequest) (*pb.ListOwnSecretsResponse, error) {
+	clientName, err := getClientIdentity(ctx, s.daemon.config.IdentitySource, s.daemon.config.IdentityTrustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("could not identify client: %w", err)
+	}
+
+	if err := validation.ValidateName(req.Namespace); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+	}
+
+	_, end := s.daemon.startAccessSpan(ctx, pb.GaiaClient_ListOwnSecrets_FullMethodName, clientName, req.Namespace)
+	matches, next, err := s.daemon.ListOwnSecrets(ctx, clientName, req.Namespace, int(req.PageSize), req.PageToken)
+	end(err)
+	if errors.Is(err, ErrInvalidPageToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if s.hideDenied(err) {
+		// Answer as for a namespace without secrets.
+		return &pb.ListOwnSecretsResponse{}, nil
+	}
+	if errors.Is(err, ErrPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListOwnSecretsResponse{Secrets: make([]*pb.Secret, len(matches)), NextPageToken: next}
+	for i, m := range matches {
+		res.Secrets[i] = &pb.Secret{Id: m.ID, Value: m.Value}
+	}
+	return res, nil
+}
+
+// ListOwnSecretIds handles the ListOwnSecretIds RPC call.
+func (s *gaiaClientServer) ListOwnSecretIds(ctx context.Context, req *pb.ListOwnSecretIdsRequest) (*pb.ListOwnSecretIdsResponse, error) {
+	clientName, err := getClientIdentity(ctx, s.daemon.config.IdentitySource, s.daemon.config.IdentityTrustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("could not identify client: %w", err)
+	}
+
+	if err := validation.ValidateName(req.Namespace); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+	}
+
+	_, end := s.daemon.startAccessSpan(ctx, pb.GaiaClient_ListOwnSecretIds_FullMethodName, clientName, req.Namespace)
+	ids, next, err := s.daemon.ListOwnSecretIDs(ctx, clientName, req.Namespace, int(req.PageSize), req.PageToken)
+	end(err)
+	if errors.Is(err, ErrInvalidPageToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if s.hideDenied(err) {
+		return &pb.ListOwnSecretIdsResponse{}, nil
+	}
+	if errors.Is(err, ErrPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListOwnSecretIdsResponse{Ids: ids, NextPageToken: next}, nil
+}
+
+// GetCommonSecrets handles the GetCommonSecrets RPC call.
+func (s *gaiaClientServer) GetCommonSecrets(ctx context.Context, req *pb.GetCommonSecretsRequest) (*pb.GetCommonSecretsResponse, error) {
+	clientName, err := getClientIdentity(ctx, s.daemon.config.IdentitySource, s.daemon.config.IdentityTrustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("could not identify client: %w", err)
+	}
+
+	_, end := s.daemon.startAccessSpan(ctx, pb.GaiaClient_GetCommonSecrets_FullMethodName, clientName, req.GetNamespace())
+	commonSecrets, err := s.daemon.GetCommonSecrets(clientName, req.GetNamespace())
+	end(err)
+	if s.hideDenied(err) {
+		// Answer as for a common namespace without secrets.
+		return &pb.GetCommonSecretsResponse{}, nil
+	}
+	if errors.Is(err, ErrPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var namespaces []*pb.Namespace
+	for nsName, secretsMap := range commonSecrets {
+		ns := &pb.Namespace{Name: nsName}
+		for key, value := range secretsMap {
+			ns.Secrets = append(ns.Secrets, &pb.Secret{Id: key, Value: value})
+		}
+		namespaces = append(namespaces, ns)
+	}
+	return &pb.GetCommonSecretsResponse{Namespaces: namespaces}, nil
+}
+
+// SetCommonGrants handles the gRPC request to restrict a client's common namespaces.
+func (s *gaiaAdminServer) SetCommonGrants(_ context.Context, req *pb.SetCommonGrantsRequest) (*pb.SetCommonGrantsResponse, error) {
+	if err := validation.ValidateName(req.ClientName); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+	}
+	for _, ns := range req.Namespaces {
+		if err := validation.ValidateName(ns); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+		}
+	}
+
+	if err := s.d.SetCommonGrants(req.ClientName, req.Namespaces); err != nil {
+		return nil, fmt.Errorf("failed to set common grants for client '%s': %w", req.ClientName, err)
+	}
+	return &pb.SetCommonGrantsResponse{Success: true}, nil
+}
+
+// SetNamespacePatterns handles the gRPC request to restrict the namespaces a
+// client's secrets may be written to.
+func (s *gaiaAdminServer) SetNamespacePatterns(_ context.Context, req *pb.SetNamespacePatternsRequest) (*pb.SetNamespacePatternsResponse, error) {
+	if err := validation.ValidateName(req.ClientName); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+	}
+	if _, err := compileNamespacePatterns(req.Patterns); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.d.SetNamespacePatterns(req.ClientName, req.Patterns); err != nil {
+		return nil, fmt.Errorf("failed to set namespace patterns for client '%s': %w", req.ClientName, err)
+	}
+	return &pb.SetNamespacePatternsResponse{Success: true}, nil
+}
+
+// SetNamespaceTTL handles the gRPC request to set the default TTL of a client's
+// namespace.
+func (s *gaiaAdminServer) SetNamespaceTTL(_ context.Context, req *pb.SetNamespaceTTLRequest) (*pb.SetNamespaceTTLResponse, error) {
+	if err := validation.ValidateName(req.ClientName); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+	}
+	if err := validation.ValidateName(req.Namespace); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+	}
+
+	err := s.d.SetNamespaceTTL(req.ClientName, req.Namespace, time.Duration(req.TtlSeconds)*time.Second)
+	if errors.Is(err, ErrInvalidTTL) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set namespace TTL for client '%s': %w", req.ClientName, err)
+	}
+	return &pb.SetNamespaceTTLResponse{Success: true}, nil
+}
+
+// ClearNamespaceTTL handles the gRPC request to remove the default TTL of a
+// client's namespace.
+func (s *gaiaAdminServer) ClearNamespaceTTL(_ context.Context, req *pb.ClearNamespaceTTLRequest) (*pb.ClearNamespaceTTLResponse, error) {
+	if err := validation.ValidateName(req.ClientName); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+	}
+	if err := validation.ValidateName(req.Namespace); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+	}
+
+	if err := s.d.ClearNamespaceTTL(req.ClientName, req.Namespace); err != nil {
+		return nil, fmt.Errorf("failed to clear namespace TTL for client '%s': %w", req.ClientName, err)
+	}
+	return &pb.ClearNamespaceTTLResponse{Success: true}, nil
+}
+
+// SetNamespaceRotation handles the gRPC request to enable or disable rotation of
+// a client's namespace.
+func (s *gaiaAdminServer) SetNamespaceRotation(_ context.Context, req *pb.SetNamespaceRotationRequest) (*pb.SetNamespaceRotationResponse, error) {
+	if err := validation.ValidateName(req.ClientName); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+	}
+	if err := validation.ValidateName(req.Namespace); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+	}
+
+	if err := s.d.SetNamespaceRotation(req.ClientName, req.Namespace, req.Enabled); err != nil {
+		return nil, fmt.Errorf("failed to set namespace rotation for client '%s': %w", req.ClientName, err)
+	}
+	return &pb.SetNamespaceRotationResponse{Success: true}, nil
+}
+
+// ExportClientManifest returns every registered client with its last issued certificate.
+func (s *gaiaAdminServer) ExportClientManifest(_ context.Context, _ *pb.ExportClientManifestRequest) (*pb.ExportClientManifestResponse, error) {
+	entries, err := s.d.ClientManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client manifest: %w", err)
+	}
+
+	clients := make([]*pb.ClientManifestEntry, len(entries))
+	for i, e := range entries {
+		clients[i] = &pb.ClientManifestEntry{
+			Name:        e.Name,
+			TimeCreated: e.TimeCreated,
+			Serial:      e.Serial,
+			Fingerprint: e.Fingerprint,
+			NotAfter:    e.NotAfter,
+			IssuedAt:    e.IssuedAt,
+		}
+	}
+	return &pb.ExportClientManifestResponse{Clients: clients}, nil
+}
+
+// GetSecretUsage reports read statistics for every secret of a client.
+func (s *gaiaAdminServer) GetSecretUsage(_ context.Context, req *pb.GetSecretUsageRequest) (*pb.GetSecretUsageResponse, error) {
+	if err := validation.ValidateName(req.ClientName); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+	}
+
+	usage, err := s.d.SecretUsage(req.ClientName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret usage for client '%s': %w", req.ClientName, err)
+	}
+
+	secrets := make([]*pb.SecretUsage, len(usage))
+	for i, u := range usage {
+		secrets[i] = &pb.SecretUsage{
+			Namespace:   u.Namespace,
+			Id:          u.ID,
+			AccessCount: u.AccessCount,
+		}
+		if !u.LastAccessed.IsZero() {
+			secrets[i].LastAccessed = u.LastAccessed.Format(time.RFC3339)
+		}
+	}
+	return &pb.GetSecretUsageResponse{Secrets: secrets, TrackingEnabled: s.d.config.TrackSecretAccess}, nil
+}
+
+// SearchSecrets finds secrets across all clients by client, namespace, id and
+// unencrypted field.
+func (s *gaiaAdminServer) SearchSecrets(ctx context.Context, req *pb.SearchSecretsRequest) (*pb.SearchSecretsResponse, error) {
+	if req.ClientName != "" {
+		if err := validation.ValidateName(req.ClientName); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+		}
+	}
+	if req.Namespace != "" {
+		if err := validation.ValidateName(req.Namespace); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+		}
+	}
+
+	search := SecretSearch{
+		Client:        req.ClientName,
+		Namespace:     req.Namespace,
+		IDContains:    req.IdContains,
+		Field:         req.Field,
+		FieldValue:    req.FieldValue,
+		IncludeValues: req.IncludeValues,
+	}
+	matches, next, err := s.d.SearchSecrets(ctx, search, int(req.PageSize), req.PageToken)
+	if errors.Is(err, ErrInvalidPageToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search secrets: %w", err)
+	}
+
+	res := &pb.SearchSecretsResponse{Matches: make([]*pb.SecretMatch, len(matches)), NextPageToken: next}
+	for i, m := range matches {
+		res.Matches[i] = &pb.SecretMatch{ClientName: m.Client, Namespace: m.Namespace, Id: m.ID, Value: m.Value}
+	}
+	return res, nil
+}
+
+// VerifyIntegrity decrypts every secret and reports the ones that fail.
+func (s *gaiaAdminServer) VerifyIntegrity(ctx context.Context, _ *pb.VerifyIntegrityRequest) (*pb.VerifyIntegrityResponse, error) {
+	checked, failures, err := s.d.VerifyIntegrity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify secrets: %w", err)
+	}
+
+	res := &pb.VerifyIntegrityResponse{Checked: int32(checked), Failures: make([]*pb.IntegrityFailure, len(failures))}
+	for i, f := range failures {
+		res.Failures[i] = &pb.IntegrityFailure{ClientName: f.Client, Namespace: f.Namespace, Id: f.ID, Reason: f.Reason}
+	}
+	return res, nil
+}
+
+// RekeyDryRun validates the old passphrase and reports the secrets that would
+// make a rekey fail.
+func (s *gaiaAdminServer) RekeyDryRun(ctx context.Context, req *pb.RekeyDryRunRequest) (*pb.RekeyDryRunResponse, error) {
+	if req.OldPassphrase == "" {
+		return nil, status.Error(codes.InvalidArgument, "old passphrase is required")
+	}
+	plan, err := s.d.RekeyDryRun(ctx, req.OldPassphrase)
+	if errors.Is(err, ErrInvalidPassphrase) {
+		return nil, status.Error(codes.Unauthenticated, "invalid passphrase")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rekey: %w", err)
+	}
+
+	res := &pb.RekeyDryRunResponse{Secrets: int32(plan.Secrets), Failures: make([]*pb.IntegrityFailure, len(plan.Failures))}
+	for i, f := range plan.Failures {
+		res.Failures[i] = &pb.IntegrityFailure{ClientName: f.Client, Namespace: f.Namespace, Id: f.ID, Reason: f.Reason}
+	}
+	return res, nil
+}
+
+// Rekey changes the master passphrase and re-encrypts every secret.
+func (s *gaiaAdminServer) Rekey(_ context.Context, req *pb.RekeyRequest) (*pb.RekeyResponse, error) {
+	if req.OldPassphrase == "" || req.NewPassphrase == "" {
+		return nil, status.Error(codes.InvalidArgument, "old and new passphrase are required")
+	}
+	n, err := s.d.Rekey(req.OldPassphrase, req.NewPassphrase, req.VerifyFirst)
+	switch {
+	case errors.Is(err, ErrInvalidPassphrase):
+		return nil, status.Error(codes.Unauthenticated, "invalid passphrase")
+	case errors.Is(err, ErrWeakPassphrase):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrKeySharesRequired), errors.Is(err, ErrRekeyBlocked):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, fmt.Errorf("failed to rekey: %w", err)
+	}
+	return &pb.RekeyResponse{Secrets: int32(n)}, nil
+}
+
 // Lock handles the Lock RPC call.
 func (s *gaiaAdminServer) Lock(_ context.Context, _ *pb.LockRequest) (*pb.LockResponse, error) {
 	s.d.LockDB()
@@ -105,31 +523,86 @@ func (s *gaiaAdminServer) Lock(_ context.Context, _ *pb.LockRequest) (*pb.LockRe
 
 // Unlock handles the Unlock RPC call.
 func (s *gaiaAdminServer) Unlock(_ context.Context, req *pb.UnlockRequest) (*pb.UnlockResponse, error) {
+	if req.Share != "" {
+		return s.unlockWithShare(req.Share)
+	}
 	err := s.d.UnlockDB(req.Passphrase)
+	if errors.Is(err, ErrKeySharesRequired) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, ErrAlreadyUnlocked) {
+		return &pb.UnlockResponse{Success: true, AlreadyUnlocked: true}, nil
+	}
 	if err != nil {
 		return &pb.UnlockResponse{Success: false}, err
 	}
 	return &pb.UnlockResponse{Success: true}, nil
 }
 
-func (s *gaiaAdminServer) RegisterClient(_ context.Context, req *pb.RegisterClientRequest) (*pb.RegisterClientResponse, error) {
-	if s.d.isLocked {
-		return nil, errors.New("daemon is in a locked state, cannot register new clients")
+// unlockWithShare submits a key share and reports the progress of the unlock.
+func (s *gaiaAdminServer) unlockWithShare(share string) (*pb.UnlockResponse, error) {
+	collected, required, err := s.d.UnlockWithShare(share)
+	if errors.Is(err, ErrAlreadyUnlocked) {
+		return &pb.UnlockResponse{Success: true, AlreadyUnlocked: true}, nil
+	}
+	if errors.Is(err, ErrInvalidShare) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-
-	certPEM, keyPEM, err := certs.GenerateClientCertificateData(req.ClientName, s.d.caCert, s.d.caKey, s.d.config.CertExpiryDays)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate client certificate: %w", err)
+		return nil, err
 	}
+	return &pb.UnlockResponse{
+		Success:         collected >= required,
+		SharesCollected: int32(collected),
+		SharesRequired:  int32(required),
+	}, nil
+}
 
+func (s *gaiaAdminServer) RegisterClient(_ context.Context, req *pb.RegisterClientRequest) (*pb.RegisterClientResponse, error) {
 	if err := validation.ValidateName(req.ClientName); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
 	}
+	// Checked before a certificate is issued, as a reissue does not register.
+	if err := s.d.checkClientName(req.ClientName); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	opts := certs.ClientCertOptions{Organization: req.Organization, OrganizationalUnit: req.OrganizationalUnit}
+	for _, raw := range req.UriSans {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid URI SAN '%s': must be an absolute URI", raw)
+		}
+		opts.URIs = append(opts.URIs, u)
+	}
+
+	certPEM, keyPEM, err := certs.GenerateClientCertificateData(req.ClientName, s.d.caCert, s.d.caKey, s.d.config.CertExpiryDays, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client certificate: %w", err)
+	}
 
-	if err := s.d.RegisterClient(req.ClientName); err != nil {
+	// A reissue only hands out a new certificate; the registration and its
+	// creation time are left untouched.
+	if req.Reissue {
+		if _, err := s.d.GetClient(req.ClientName); err != nil {
+			if errors.Is(err, ErrClientNotFound) {
+				return nil, status.Errorf(codes.NotFound, "client '%s' is not registered", req.ClientName)
+			}
+			return nil, err
+		}
+		gaialog.Get().Info("client certificate reissued", slog.String("client_name", req.ClientName))
+	} else if err := s.d.RegisterClient(req.ClientName); err != nil {
+		if errors.Is(err, ErrClientExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "client '%s' is already registered", req.ClientName)
+		}
 		return nil, fmt.Errorf("failed to register client in database: %w", err)
 	}
 
+	if err := s.d.RecordIssuedCert(req.ClientName, certPEM); err != nil {
+		gaialog.Get().Warn("failed to record issued certificate",
+			slog.String("client_name", req.ClientName), slog.Any("error", err))
+	}
+
 	return &pb.RegisterClientResponse{
 		Certificate: string(certPEM),
 		PrivateKey:  string(keyPEM),
@@ -137,10 +610,6 @@ func (s *gaiaAdminServer) RegisterClient(_ context.Context, req *pb.RegisterClie
 }
 
 func (s *gaiaAdminServer) ListClients(_ context.Context, _ *pb.ListClientsRequest) (*pb.ListClientsResponse, error) {
-	if s.d.isLocked {
-		return nil, errors.New("daemon is in a locked state, cannot list clients")
-	}
-
 	clients, err := s.d.ListClients()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get client list: %w", err)
@@ -157,28 +626,97 @@ func (s *gaiaAdminServer) ListClients(_ context.Context, _ *pb.ListClientsReques
 	return &pb.ListClientsResponse{Clients: pbClients}, nil
 }
 
-// RevokeClient handles the gRPC request to revoke a client.
-func (s *gaiaAdminServer) RevokeClient(_ context.Context, req *pb.RevokeClientRequest) (*pb.RevokeClientResponse, error) {
-	if s.d.isLocked {
-		return nil, errors.New("daemon is in a locked state, cannot revoke clients")
+// ExportClients handles the gRPC request to export the client registry.
+func (s *gaiaAdminServer) ExportClients(ctx context.Context, _ *pb.ExportClientsRequest) (*pb.ExportClientsResponse, error) {
+	res, err := s.ListClients(ctx, &pb.ListClientsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ExportClientsResponse{Clients: res.Clients}, nil
+}
+
+// ImportClients handles the gRPC request to restore client registrations.
+func (s *gaiaAdminServer) ImportClients(_ context.Context, req *pb.ImportClientsRequest) (*pb.ImportClientsResponse, error) {
+	clients := make([]Client, len(req.Clients))
+	for i, c := range req.Clients {
+		if err := validation.ValidateName(c.Name); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "client %d: invalid client name: %v", i, err)
+		}
+		if _, err := time.Parse(time.RFC3339, c.TimeCreated); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "client '%s': invalid creation time '%s', want RFC 3339", c.Name, c.TimeCreated)
+		}
+		clients[i] = Client{Name: c.Name, TimeCreated: c.TimeCreated}
+	}
+
+	imported, skipped, err := s.d.ImportClients(clients, req.Overwrite)
+	if err != nil {
+		return nil, err
 	}
+	return &pb.ImportClientsResponse{ClientsImported: int32(imported), ClientsSkipped: int32(skipped)}, nil
+}
 
+// RevokeClient handles the gRPC request to revoke a client.
+func (s *gaiaAdminServer) RevokeClient(_ context.Context, req *pb.RevokeClientRequest) (*pb.RevokeClientResponse, error) {
 	if err := validation.ValidateName(req.ClientName); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
 	}
 
-	if err := s.d.RevokeClient(req.ClientName); err != nil {
+	var impact *RevokeImpact
+	var err error
+	if req.DryRun {
+		impact, err = s.d.DescribeRevoke(req.ClientName)
+	} else {
+		if err := s.checkReauth(req.Passphrase); err != nil {
+			return nil, err
+		}
+		impact, err = s.d.RevokeClient(req.ClientName)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to revoke client '%s': %w", req.ClientName, err)
 	}
 
-	return &pb.RevokeClientResponse{Success: true}, nil
+	return &pb.RevokeClientResponse{
+		Success:     true,
+		Namespaces:  impact.Namespaces,
+		SecretCount: int32(impact.SecretCount),
+	}, nil
 }
 
-func (s *gaiaAdminServer) ListNamespaces(_ context.Context, req *pb.ListNamespacesRequest) (*pb.ListNamespacesResponse, error) {
-	if s.d.isLocked {
-		return nil, errors.New("daemon is in a locked state, cannot list namespaces")
+// PruneOrphans removes the secrets of unregistered clients and, when asked, the
+// registrations without secrets.
+func (s *gaiaAdminServer) PruneOrphans(_ context.Context, req *pb.PruneOrphansRequest) (*pb.PruneOrphansResponse, error) {
+	if !req.DryRun {
+		if err := s.checkReauth(req.Passphrase); err != nil {
+			return nil, err
+		}
+	}
+	report, err := s.d.PruneOrphans(req.DryRun, req.IncludeEmptyClients)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune orphans: %w", err)
 	}
 
+	res := &pb.PruneOrphansResponse{EmptyClients: report.EmptyClients}
+	for _, o := range report.Orphaned {
+		res.Orphaned = append(res.Orphaned, &pb.OrphanedClient{ClientName: o.Name, Secrets: int32(o.Secrets)})
+	}
+	return res, nil
+}
+
+func (s *gaiaAdminServer) DeleteSecretsByTag(_ context.Context, req *pb.DeleteSecretsByTagRequest) (*pb.DeleteSecretsByTagResponse, error) {
+	if err := s.checkReauth(req.Passphrase); err != nil {
+		return nil, err
+	}
+	deleted, err := s.d.DeleteSecretsByTag(req.ClientName, req.TagKey, req.TagValue)
+	if errors.Is(err, ErrInvalidTag) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete secrets of client '%s' by tag: %w", req.ClientName, err)
+	}
+	return &pb.DeleteSecretsByTagResponse{Deleted: int32(deleted)}, nil
+}
+
+func (s *gaiaAdminServer) ListNamespaces(_ context.Context, req *pb.ListNamespacesRequest) (*pb.ListNamespacesResponse, error) {
 	namespaces, err := s.d.ListNamespaces(req.ClientName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get namespace list for client '%s': %w", req.ClientName, err)
@@ -187,64 +725,301 @@ func (s *gaiaAdminServer) ListNamespaces(_ context.Context, req *pb.ListNamespac
 	return &pb.ListNamespacesResponse{Namespaces: namespaces}, nil
 }
 
+// importProgressInterval is the number of items ImportSecretsWithProgress
+// receives between two progress messages.
+const importProgressInterval = 500
+
 // ImportSecrets handles the client-streaming RPC for bulk secret import.
 func (s *gaiaAdminServer) ImportSecrets(stream pb.GaiaAdmin_ImportSecretsServer) error {
-	if s.d.isLocked {
-		return errors.New("daemon is in a locked state, cannot import secrets")
+	res, err := s.importSecrets(stream.Recv, nil)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(res)
+}
+
+// ImportSecretsWithProgress handles the bidirectional streaming variant of
+// ImportSecrets, which reports the number of items received every
+// importProgressInterval items before sending the result.
+func (s *gaiaAdminServer) ImportSecretsWithProgress(stream pb.GaiaAdmin_ImportSecretsWithProgressServer) error {
+	res, err := s.importSecrets(stream.Recv, func(received int) error {
+		return stream.Send(&pb.ImportSecretsProgress{Payload: &pb.ImportSecretsProgress_ItemsReceived{ItemsReceived: int32(received)}})
+	})
+	if err != nil {
+		return err
 	}
+	return stream.Send(&pb.ImportSecretsProgress{Payload: &pb.ImportSecretsProgress_Result{Result: res}})
+}
 
-	initialReq, err := stream.Recv()
+// importSecrets receives an import configuration and its items with recv and
+// imports them. When progress is not nil, it is called with the number of
+// items received every importProgressInterval items.
+func (s *gaiaAdminServer) importSecrets(recv func() (*pb.ImportSecretsRequest, error), progress func(received int) error) (*pb.ImportSecretsResponse, error) {
+	initialReq, err := recv()
 	if err != nil {
-		return fmt.Errorf("error receiving initial import request: %w", err)
+		return nil, fmt.Errorf("error receiving initial import request: %w", err)
 	}
 
 	configPayload, ok := initialReq.GetPayload().(*pb.ImportSecretsRequest_Config)
 	if !ok {
-		return errors.New("expected the first message to be import configuration")
+		return nil, errors.New("expected the first message to be import configuration")
+	}
+	batchID := configPayload.Config.GetBatchId()
+	mode := ImportFailOnConflict
+	switch cfg := configPayload.Config; {
+	case cfg.GetOverwrite() && cfg.GetMerge():
+		return nil, status.Error(codes.InvalidArgument, "overwrite and merge cannot be combined")
+	case cfg.GetOverwrite():
+		mode = ImportOverwrite
+	case cfg.GetMerge():
+		mode = ImportMerge
 	}
-	overwrite := configPayload.Config.GetOverwrite()
 
 	var receivedSecrets []*pb.ImportSecretItem
 	for {
-		req, err := stream.Recv()
+		req, err := recv()
 		if err == io.EOF {
 			// The client has finished sending.
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("error receiving stream: %w", err)
+			return nil, fmt.Errorf("error receiving stream: %w", err)
 		}
 
 		itemPayload, ok := req.GetPayload().(*pb.ImportSecretsRequest_Item)
 		if !ok {
-			return errors.New("expected subsequent messages to be secret items")
+			return nil, errors.New("expected subsequent messages to be secret items")
 		}
 		receivedSecrets = append(receivedSecrets, itemPayload.Item)
+		if progress != nil && len(receivedSecrets)%importProgressInterval == 0 {
+			if err := progress(len(receivedSecrets)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result, err := s.d.ImportSecrets(receivedSecrets, mode, batchID)
+	if errors.Is(err, ErrNamespaceNotAllowed) || errors.Is(err, ErrValueRejected) || errors.Is(err, ErrInvalidFormat) ||
+		errors.Is(err, encrypt.ErrNotObject) || errors.Is(err, encrypt.ErrFieldNotFound) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if errors.Is(err, ErrSecretExists) {
+		return nil, status.Errorf(codes.AlreadyExists, "%v. Use --overwrite to replace it or --merge to keep it", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.Replayed {
+		return &pb.ImportSecretsResponse{
+			SecretsImported: int32(result.Imported),
+			SecretsSkipped:  int32(result.Skipped),
+			Replayed:        true,
+			Message:         fmt.Sprintf("Batch '%s' was already imported, nothing was changed.", batchID),
+		}, nil
+	}
+
+	message := "Secrets imported successfully."
+	if result.Skipped > 0 {
+		message = fmt.Sprintf("Secrets imported successfully, %d existing secrets left untouched.", result.Skipped)
+	}
+	unreachable := 0
+	for _, secret := range receivedSecrets {
+		if warning, _ := s.d.checkWriteNamespace(secret.ClientName, secret.Namespace); warning != "" {
+			unreachable++
+		}
+	}
+	if unreachable > 0 {
+		message = fmt.Sprintf("%s Warning: %d secrets were written to namespaces their client cannot read.", message, unreachable)
+	}
+	if n := len(result.ValueWarnings); n > 0 {
+		message = fmt.Sprintf("%s Warning: %d values matched value rules.", message, n)
+	}
+
+	return &pb.ImportSecretsResponse{
+		SecretsImported: int32(result.Imported),
+		SecretsSkipped:  int32(result.Skipped),
+		Message:         message,
+		Violations:      valueViolationsToPB(result.ValueWarnings),
+	}, nil
+}
+
+// SetSecrets handles the SetSecrets RPC call. It writes a handful of secrets in
+// one transaction, for callers that do not want to manage an import stream.
+func (s *gaiaAdminServer) SetSecrets(_ context.Context, req *pb.SetSecretsRequest) (*pb.SetSecretsResponse, error) {
+	for i, secret := range req.Secrets {
+		if err := validation.ValidateName(secret.ClientName); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "secret %d: invalid client name: %v", i, err)
+		}
+		if err := validation.ValidateName(secret.Namespace); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "secret %d: invalid namespace: %v", i, err)
+		}
+		if err := validation.ValidateName(secret.Id); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "secret %d: invalid secret id: %v", i, err)
+		}
+	}
+
+	mode := ImportFailOnConflict
+	if req.Overwrite {
+		mode = ImportOverwrite
+	}
+	result, err := s.d.ImportSecrets(req.Secrets, mode, "")
+	switch {
+	case errors.Is(err, ErrNamespaceNotAllowed), errors.Is(err, ErrValueRejected), errors.Is(err, ErrInvalidFormat):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrQuotaExceeded):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, ErrSecretExists):
+		return nil, status.Errorf(codes.AlreadyExists, "%v; set overwrite to replace existing secrets", err)
+	case err != nil:
+		return nil, err
 	}
 
-	count, err := s.d.ImportSecrets(receivedSecrets, overwrite)
+	res := &pb.SetSecretsResponse{SecretsSet: int32(result.Imported), Violations: valueViolationsToPB(result.ValueWarnings)}
+	for _, secret := range req.Secrets {
+		if warning, _ := s.d.checkWriteNamespace(secret.ClientName, secret.Namespace); warning != "" {
+			res.Unreachable++
+		}
+	}
+	return res, nil
+}
+
+// ExportSecrets handles the gRPC request to export decrypted secrets.
+func (s *gaiaAdminServer) ExportSecrets(ctx context.Context, req *pb.ExportSecretsRequest) (*pb.ExportSecretsResponse, error) {
+	if req.ClientName != "" {
+		if err := validation.ValidateName(req.ClientName); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+		}
+	}
+
+	exported, err := s.d.ExportSecrets(ctx, req.ClientName)
 	if err != nil {
-		return err
+		return nil, contextStatus(err)
 	}
 
-	return stream.SendAndClose(&pb.ImportSecretsResponse{
-		SecretsImported: int32(count),
-		Message:         "Secrets imported successfully.",
-	})
+	var items []*pb.ImportSecretItem
+	for clientName, namespaces := range exported {
+		for namespace, secrets := range namespaces {
+			for id, value := range secrets {
+				items = append(items, &pb.ImportSecretItem{
+					ClientName: clientName,
+					Namespace:  namespace,
+					Id:         id,
+					Value:      value,
+				})
+			}
+		}
+	}
+
+	return &pb.ExportSecretsResponse{Items: items}, nil
 }
 
-func (s *gaiaAdminServer) ListSecrets(ctx context.Context, req *pb.ListSecretsRequest) (*pb.ListSecretsResponse, error) {
-	if s.d.isLocked {
-		return nil, errors.New("daemon is in a locked state")
+// defaultRotateLength is the length of generated values when the request does not set one.
+const defaultRotateLength = 32
+
+// RotateSecrets handles the gRPC request to regenerate every secret in a namespace.
+func (s *gaiaAdminServer) RotateSecrets(_ context.Context, req *pb.RotateSecretsRequest) (*pb.RotateSecretsResponse, error) {
+	if err := validation.ValidateName(req.ClientName); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+	}
+	if err := validation.ValidateName(req.Namespace); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+	}
+	if req.Length < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid length: %d", req.Length)
+	}
+
+	length := int(req.Length)
+	if length == 0 {
+		length = defaultRotateLength
+	}
+	generate := func() (string, error) {
+		return encrypt.RandomString(length, req.Charset)
+	}
+
+	rotated, err := s.d.RotateSecrets(req.ClientName, req.Namespace, generate)
+	if errors.Is(err, ErrStructuredSecret) || errors.Is(err, ErrRotationDisabled) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, ErrInvalidFormat) || errors.Is(err, ErrValueRejected) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate secrets for client '%s': %w", req.ClientName, err)
 	}
 
+	res := &pb.RotateSecretsResponse{}
+	for _, r := range rotated {
+		res.Secrets = append(res.Secrets, &pb.RotatedSecret{Id: r.ID, OldValue: r.OldValue, NewValue: r.NewValue})
+	}
+	return res, nil
+}
+
+// MoveNamespace moves a namespace and all of its secrets to another client.
+func (s *gaiaAdminServer) MoveNamespace(_ context.Context, req *pb.MoveNamespaceRequest) (*pb.MoveNamespaceResponse, error) {
+	if err := validation.ValidateName(req.SrcClient); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid source client name: %v", err)
+	}
+	if err := validation.ValidateName(req.DstClient); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid destination client name: %v", err)
+	}
+	if err := validation.ValidateName(req.Namespace); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid namespace: %v", err)
+	}
+
+	moved, err := s.d.MoveNamespace(req.SrcClient, req.DstClient, req.Namespace, req.Overwrite)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSecretExists):
+			return nil, status.Errorf(codes.AlreadyExists, "%v", err)
+		case errors.Is(err, ErrNamespaceNotAllowed):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, ErrQuotaExceeded):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, fmt.Errorf("failed to move namespace '%s': %w", req.Namespace, err)
+	}
+	return &pb.MoveNamespaceResponse{MovedCount: int32(moved)}, nil
+}
+
+// RenameSecret handles the RenameSecret RPC call.
+func (s *gaiaAdminServer) RenameSecret(_ context.Context, req *pb.RenameSecretRequest) (*pb.RenameSecretResponse, error) {
+	for _, f := range []struct{ what, name string }{
+		{"client name", req.ClientName},
+		{"namespace", req.Namespace},
+		{"secret id", req.OldId},
+		{"new secret id", req.NewId},
+	} {
+		if err := validation.ValidateName(f.name); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s: %v", f.what, err)
+		}
+	}
+	if req.OldId == req.NewId {
+		return nil, status.Error(codes.InvalidArgument, "old and new secret id are the same")
+	}
+
+	err := s.d.RenameSecret(req.ClientName, req.Namespace, req.OldId, req.NewId, req.Overwrite)
+	switch {
+	case errors.Is(err, ErrSecretNotFound):
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, ErrSecretExists):
+		return nil, status.Errorf(codes.AlreadyExists, "%v", err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to rename secret '%s': %w", req.OldId, err)
+	}
+	return &pb.RenameSecretResponse{}, nil
+}
+
+func (s *gaiaAdminServer) ListSecrets(ctx context.Context, req *pb.ListSecretsRequest) (*pb.ListSecretsResponse, error) {
 	if err := validation.ValidateName(req.ClientName); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
 	}
 
-	allData, err := s.d.ListSecrets(req.ClientName)
+	allData, err := s.d.ListSecrets(ctx, req.ClientName)
 	if err != nil {
-		return nil, err
+		return nil, contextStatus(err)
 	}
 
 	var namespaces []*pb.Namespace
@@ -258,3 +1033,37 @@ func (s *gaiaAdminServer) ListSecrets(ctx context.Context, req *pb.ListSecretsRe
 
 	return &pb.ListSecretsResponse{Namespaces: namespaces}, nil
 }
+
+// StreamSecrets handles the StreamSecrets RPC call. It sends a client's secrets
+// one namespace per message, in name order, so large clients stay below the
+// message size limit and callers can show progress.
+func (s *gaiaAdminServer) StreamSecrets(req *pb.ListSecretsRequest, stream pb.GaiaAdmin_StreamSecretsServer) error {
+	if err := validation.ValidateName(req.ClientName); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid client name: %v", err)
+	}
+
+	names, err := s.d.ListNamespaces(req.ClientName)
+	if err != nil {
+		return err
+	}
+
+	// Each namespace is read and decrypted only when it is sent, so the whole
+	// client is never held in memory. Writes between two namespaces may show.
+	for _, nsName := range names {
+		secrets, err := s.d.ListNamespaceSecrets(stream.Context(), req.ClientName, nsName)
+		if err != nil {
+			return contextStatus(err)
+		}
+		if len(secrets) == 0 {
+			continue
+		}
+		ns := &pb.Namespace{Name: nsName}
+		for _, key := range slices.Sorted(maps.Keys(secrets)) {
+			ns.Secrets = append(ns.Secrets, &pb.Secret{Id: key, Value: secrets[key]})
+		}
+		if err := stream.Send(&pb.StreamSecretsResponse{Namespace: ns, TotalNamespaces: int32(len(names))}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
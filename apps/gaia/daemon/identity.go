@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Identity sources, the values of Config.IdentitySource.
+const (
+	IdentityCN       = "cn"
+	IdentityURISAN   = "uri-san"
+	IdentityEmailSAN = "email-san"
+	IdentityOU       = "ou"
+)
+
+// ErrNoIdentity is returned when the client certificate lacks the field the
+// configured identity source reads.
+var ErrNoIdentity = errors.New("client certificate has no identity")
+
+// validIdentitySource reports whether source is a known identity source. An
+// empty source means IdentityCN.
+func validIdentitySource(source string) bool {
+	switch source {
+	case "", IdentityCN, IdentityURISAN, IdentityEmailSAN, IdentityOU:
+		return true
+	}
+	return false
+}
+
+// parseTrustDomain parses the trust domain URI SANs must belong to for
+// IdentityURISAN, a scheme and host such as spiffe://example.org.
+func parseTrustDomain(trustDomain string) (*url.URL, error) {
+	u, err := url.Parse(trustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid identity_trust_domain '%s': %w", trustDomain, err)
+	}
+	if u.Scheme == "" || u.Host == "" || strings.Trim(u.Path, "/") != "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid identity_trust_domain '%s', want a scheme and host such as spiffe://example.org", trustDomain)
+	}
+	return u, nil
+}
+
+// ClientIdentity returns the client name carried by cert according to source:
+//   - IdentityCN: the Subject Common Name.
+//   - IdentityURISAN: the last path segment of the one URI SAN in trustDomain,
+//     so spiffe://example.org/gaia/billing identifies "billing" in the trust
+//     domain spiffe://example.org. URI SANs of other trust domains are
+//     ignored, and a certificate with several in trustDomain is rejected.
+//   - IdentityEmailSAN: the local part of the first email SAN.
+//   - IdentityOU: the first Subject Organizational Unit.
+//
+// An empty source means IdentityCN. ErrNoIdentity is returned when the field
+// is absent or empty.
+func ClientIdentity(cert *x509.Certificate, source, trustDomain string) (string, error) {
+	var name string
+	switch source {
+	case "", IdentityCN:
+		name = cert.Subject.CommonName
+	case IdentityURISAN:
+		td, err := parseTrustDomain(trustDomain)
+		if err != nil {
+			return "", err
+		}
+		var matching []*url.URL
+		for _, u := range cert.URIs {
+			if strings.EqualFold(u.Scheme, td.Scheme) && strings.EqualFold(u.Host, td.Host) {
+				matching = append(matching, u)
+			}
+		}
+		if len(matching) > 1 {
+			return "", fmt.Errorf("%w: %d URI SANs in trust domain %s, want one", ErrNoIdentity, len(matching), trustDomain)
+		}
+		if len(matching) == 1 {
+			name = path.Base(strings.TrimSuffix(matching[0].Path, "/"))
+			if name == "." || name == "/" {
+				name = ""
+			}
+		}
+	case IdentityEmailSAN:
+		if len(cert.EmailAddresses) > 0 {
+			name, _, _ = strings.Cut(cert.EmailAddresses[0], "@")
+		}
+	case IdentityOU:
+		if len(cert.Subject.OrganizationalUnit) > 0 {
+			name = cert.Subject.OrganizationalUnit[0]
+		}
+	default:
+		return "", fmt.Errorf("unknown identity source '%s'", source)
+	}
+	if name == "" {
+		if source == IdentityURISAN {
+			return "", fmt.Errorf("%w: no URI SAN in trust domain %s in certificate", ErrNoIdentity, trustDomain)
+		}
+		return "", fmt.Errorf("%w: no %s in certificate", ErrNoIdentity, identitySourceName(source))
+	}
+	return name, nil
+}
+
+// identitySourceName returns the certificate field read by source, for errors.
+func identitySourceName(source string) string {
+	switch source {
+	case IdentityURISAN:
+		return "URI SAN"
+	case IdentityEmailSAN:
+		return "email SAN"
+	case IdentityOU:
+		return "Organizational Unit"
+	}
+	return "Common Name"
+}
+
+// getClientIdentity returns the name of the client calling over mTLS, read from
+// its certificate according to source and trustDomain.
+func getClientIdentity(ctx context.Context, source, trustDomain string) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", errors.New("could not get peer from context")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", errors.New("peer auth info is not TLS")
+	}
+	if len(tlsInfo.State.PeerCertificates) == 0 {
+		return "", errors.New("no peer certificates found")
+	}
+	// The client's certificate is the first in the chain.
+	return ClientIdentity(tlsInfo.State.PeerCertificates[0], source, trustDomain)
+}
@@ -0,0 +1,135 @@
+package daemon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// certContext returns a context of a client presenting cert.
+func certContext(cert *x509.Certificate) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+	})
+}
+
+func TestClientIdentity(t *testing.T) {
+	spiffe, _ := url.Parse("spiffe://example.org/gaia/billing")
+	full := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "from-cn", OrganizationalUnit: []string{"from-ou", "other"}},
+		URIs:           []*url.URL{spiffe},
+		EmailAddresses: []string{"from-email@example.org"},
+	}
+	bare := &x509.Certificate{}
+	hostOnly, _ := url.Parse("spiffe://example.org")
+	foreign, _ := url.Parse("spiffe://evil.example/gaia/billing")
+	other, _ := url.Parse("spiffe://example.org/gaia/shipping")
+
+	tests := []struct {
+		name    string
+		cert    *x509.Certificate
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{"default is cn", full, "", "from-cn", false},
+		{"cn", full, IdentityCN, "from-cn", false},
+		{"uri-san", full, IdentityURISAN, "billing", false},
+		{"email-san", full, IdentityEmailSAN, "from-email", false},
+		{"ou", full, IdentityOU, "from-ou", false},
+		{"cn absent", bare, IdentityCN, "", true},
+		{"uri-san absent", bare, IdentityURISAN, "", true},
+		{"uri-san without path", &x509.Certificate{URIs: []*url.URL{hostOnly}}, IdentityURISAN, "", true},
+		{"uri-san of a foreign trust domain", &x509.Certificate{URIs: []*url.URL{foreign}}, IdentityURISAN, "", true},
+		{"uri-san after a foreign one", &x509.Certificate{URIs: []*url.URL{foreign, spiffe}}, IdentityURISAN, "billing", false},
+		{"uri-san twice in the trust domain", &x509.Certificate{URIs: []*url.URL{spiffe, other}}, IdentityURISAN, "", true},
+		{"email-san absent", bare, IdentityEmailSAN, "", true},
+		{"ou absent", bare, IdentityOU, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ClientIdentity(tt.cert, tt.source, "spiffe://example.org")
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoIdentity) {
+					t.Errorf("ClientIdentity() error = %v, want ErrNoIdentity", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ClientIdentity() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ClientIdentity(full, "serial", ""); err == nil || errors.Is(err, ErrNoIdentity) {
+		t.Errorf("ClientIdentity() with an unknown source error = %v", err)
+	}
+	for _, td := range []string{"", "example.org", "spiffe://example.org/gaia"} {
+		if _, err := ClientIdentity(full, IdentityURISAN, td); err == nil || errors.Is(err, ErrNoIdentity) {
+			t.Errorf("ClientIdentity() with trust domain %q error = %v, want an invalid trust domain error", td, err)
+		}
+	}
+}
+
+func TestGetSecret_IdentitySource(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) {
+		c.IdentitySource = IdentityURISAN
+		c.IdentityTrustDomain = "spiffe://example.org"
+	})
+	if _, err := d.AddSecret("billing", "billing", "api_key", "s3cret", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	srv := NewClientServer(d)
+	req := &pb.GetSecretRequest{Namespace: "billing", Id: "api_key"}
+
+	spiffe, _ := url.Parse("spiffe://example.org/gaia/billing")
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "someone-else"}, URIs: []*url.URL{spiffe}}
+	resp, err := srv.GetSecret(certContext(cert), req)
+	if err != nil || resp.Value != "s3cret" {
+		t.Fatalf("GetSecret() with a URI SAN identity = %v, %v", resp, err)
+	}
+
+	// The Common Name is ignored when another source is configured.
+	if _, err := srv.GetSecret(clientContext("billing"), req); !errors.Is(err, ErrNoIdentity) {
+		t.Errorf("GetSecret() without a URI SAN error = %v, want ErrNoIdentity", err)
+	}
+
+	// A certificate from another trust domain does not identify the client.
+	foreign, _ := url.Parse("spiffe://evil.example/gaia/billing")
+	cert = &x509.Certificate{URIs: []*url.URL{foreign}}
+	if _, err := srv.GetSecret(certContext(cert), req); !errors.Is(err, ErrNoIdentity) {
+		t.Errorf("GetSecret() with a foreign trust domain error = %v, want ErrNoIdentity", err)
+	}
+}
+
+func TestStart_URISANRequiresTrustDomain(t *testing.T) {
+	cfg := newTestConfig(t, func(c *config.Config) { c.IdentitySource = IdentityURISAN })
+	d := NewDaemon(cfg)
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	if err := d.Start(cfg); err == nil || !strings.Contains(err.Error(), "identity_trust_domain") {
+		t.Errorf("Start() error = %v, want a missing identity_trust_domain error", err)
+	}
+}
+
+func TestStart_InvalidIdentitySource(t *testing.T) {
+	cfg := newTestConfig(t, func(c *config.Config) { c.IdentitySource = "serial" })
+	d := NewDaemon(cfg)
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	if err := d.Start(cfg); err == nil || !strings.Contains(err.Error(), "identity_source") {
+		t.Errorf("Start() error = %v, want an invalid identity_source error", err)
+	}
+}
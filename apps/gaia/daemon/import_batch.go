@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// importBatchesBucket records the result of every import sent with a batch ID.
+	importBatchesBucket = "import_batches"
+	// importBatchRetention is how long a batch ID is remembered.
+	importBatchRetention = 7 * 24 * time.Hour
+)
+
+// importBatch is the recorded result of a completed import batch.
+type importBatch struct {
+	Imported    int       `json:"imported"`
+	Skipped     int       `json:"skipped"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// lookupImportBatch returns the recorded result of batchID, or nil when the batch
+// is unknown or its record has expired.
+func lookupImportBatch(tx *bbolt.Tx, batchID string, now time.Time) (*importBatch, error) {
+	b := tx.Bucket([]byte(importBatchesBucket))
+	if b == nil {
+		return nil, nil
+	}
+	v := b.Get([]byte(batchID))
+	if v == nil {
+		return nil, nil
+	}
+	var batch importBatch
+	if err := json.Unmarshal(v, &batch); err != nil {
+		return nil, fmt.Errorf("corrupt import batch record '%s': %w", batchID, err)
+	}
+	if now.Sub(batch.CompletedAt) > importBatchRetention {
+		return nil, nil
+	}
+	return &batch, nil
+}
+
+// recordImportBatch stores the result of batchID and drops records older than
+// importBatchRetention.
+func recordImportBatch(tx *bbolt.Tx, batchID string, batch importBatch) error {
+	b, err := tx.CreateBucketIfNotExists([]byte(importBatchesBucket))
+	if err != nil {
+		return fmt.Errorf("failed to create or get import batches bucket: %w", err)
+	}
+
+	var expired [][]byte
+	err = b.ForEach(func(k, v []byte) error {
+		var old importBatch
+		if err := json.Unmarshal(v, &old); err != nil || batch.CompletedAt.Sub(old.CompletedAt) > importBatchRetention {
+			expired = append(expired, bytes.Clone(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range expired {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(batchID), data)
+}
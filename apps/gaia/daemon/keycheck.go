@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+// ErrKeyHashMismatch is returned by CheckKeyHash when the key hash stored in the
+// database no longer matches the loaded key.
+var ErrKeyHashMismatch = errors.New("database key hash does not match the loaded key")
+
+// CheckKeyHash re-reads the key hash from the database and compares it with the
+// hash of the loaded key. On a mismatch the database was changed underneath the
+// daemon, so it logs an alert, locks the daemon and returns ErrKeyHashMismatch.
+// A locked daemon is not checked.
+func (d *Daemon) CheckKeyHash() error {
+	d.dbLock.RLock()
+	if d.isLocked || d.db == nil {
+		d.dbLock.RUnlock()
+		return nil
+	}
+	var storedHash []byte
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		_, storedHash, err = readKeyMaterial(tx)
+		return err
+	})
+	keyHash := sha256.Sum256(d.key)
+	d.dbLock.RUnlock()
+
+	if err == nil && subtle.ConstantTimeCompare(keyHash[:], storedHash) == 1 {
+		return nil
+	}
+	if err == nil {
+		err = ErrKeyHashMismatch
+	}
+	gaialog.Get().Error("Database key hash check failed, locking the daemon",
+		slog.String("db_file", d.config.DBFile),
+		slog.String("error", err.Error()),
+	)
+	d.closeDB(LockReasonKeyMismatch)
+	return err
+}
+
+// runKeyChecker checks the key hash every interval until stopped is closed.
+func (d *Daemon) runKeyChecker(interval time.Duration, stopped <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.CheckKeyHash()
+		case <-stopped:
+			return
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+func TestKeyChecker_LocksOnKeyHashChange(t *testing.T) {
+	d := newTestDaemon(t)
+	if err := d.CheckKeyHash(); err != nil {
+		t.Fatalf("CheckKeyHash() on an untouched database error = %v", err)
+	}
+
+	var logs bytes.Buffer
+	gaialog.SetOutput(&logs)
+	t.Cleanup(func() { gaialog.SetOutput(os.Stdout) })
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go d.runKeyChecker(10*time.Millisecond, stopped)
+
+	d.dbLock.Lock()
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(metaBucket)).Put([]byte(keyHashKey), bytes.Repeat([]byte{0xff}, 32))
+	})
+	d.dbLock.Unlock()
+	if err != nil {
+		t.Fatalf("failed to overwrite the key hash: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		locked, reason, _ := d.LockState()
+		if locked {
+			if reason != LockReasonKeyMismatch {
+				t.Errorf("lock reason = %q, want %q", reason, LockReasonKeyMismatch)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("daemon still unlocked after the key hash changed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !strings.Contains(logs.String(), "key hash check failed") {
+		t.Errorf("log = %q, want an alert about the key hash", logs.String())
+	}
+}
@@ -0,0 +1,211 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"go.etcd.io/bbolt"
+)
+
+// Composite keys of the secret formats, secret access and common grants buckets are
+// built from escaped parts, each followed by keySep. A null byte inside a part is
+// written as keyEscape, so keySep never occurs within a part and a client's
+// records always sort together under keyPrefix(client). Schema versions before
+// 4 keyed the secrets bucket the same way.
+var (
+	keySep    = []byte{0x00, 0x01}
+	keyEscape = []byte{0x00, 0xff}
+	nullByte  = []byte{0x00}
+)
+
+const (
+	// schemaVersionKey records the layout of the database in the meta bucket.
+	// Databases without it use schema version 1, which joined key parts with a
+	// bare null byte. Version 2 escaped the key parts, version 3 moved the
+	// metadata out of the secrets bucket and version 4 moved the secrets from
+	// composite keys into client and namespace buckets.
+	schemaVersionKey = "schema_version"
+	// schemaVersion is the version written by this build.
+	schemaVersion = 4
+
+	// Before schema version 3 the metadata was kept in the secrets bucket under
+	// these keys.
+	legacyMetaPrefix       = "gaia:internal:cmfk1rbd000000m74bic9evy3"
+	legacySaltKey          = legacyMetaPrefix + "__salt__"
+	legacyKeyHashKey       = legacyMetaPrefix + "__key_hash__"
+	legacySchemaVersionKey = legacyMetaPrefix + "__schema_version__"
+)
+
+func escapeKeyPart(s string) []byte {
+	return bytes.ReplaceAll([]byte(s), nullByte, keyEscape)
+}
+
+func unescapeKeyPart(b []byte) string {
+	return string(bytes.ReplaceAll(b, keyEscape, nullByte))
+}
+
+// keyPrefix returns the prefix shared by every key that starts with parts, e.g.
+// keyPrefix(client, namespace) for all secrets of a namespace.
+func keyPrefix(parts ...string) []byte {
+	var key []byte
+	for _, p := range parts {
+		key = append(key, escapeKeyPart(p)...)
+		key = append(key, keySep...)
+	}
+	return key
+}
+
+// constructDBKey builds the key a secret is stored under.
+func constructDBKey(client, namespace, id string) []byte {
+	return append(keyPrefix(client, namespace), escapeKeyPart(id)...)
+}
+
+// splitDBKey decodes the parts of a composite key. Keys without a separator, such
+// as the salt and key hash, decode to a single part.
+func splitDBKey(key []byte) []string {
+	raw := bytes.Split(key, keySep)
+	parts := make([]string, len(raw))
+	for i, p := range raw {
+		parts[i] = unescapeKeyPart(p)
+	}
+	return parts
+}
+
+// parseSecretKey decodes a key built by constructDBKey. ok is false for metadata
+// and malformed keys.
+func parseSecretKey(key []byte) (client, namespace, id string, ok bool) {
+	parts := splitDBKey(key)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// readSchemaVersion returns the schema version of the database.
+func readSchemaVersion(tx *bbolt.Tx) (int, error) {
+	var v []byte
+	if metaB := tx.Bucket([]byte(metaBucket)); metaB != nil {
+		v = metaB.Get([]byte(schemaVersionKey))
+	} else {
+		v = tx.Bucket([]byte(secretsBucket)).Get([]byte(legacySchemaVersionKey))
+	}
+	if v == nil {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(string(v))
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid schema version %q", ErrDatabaseCorrupt, v)
+	}
+	return version, nil
+}
+
+// migrateSchema upgrades a database written by an older build and records the
+// current schema version. It reports whether anything was rewritten.
+func migrateSchema(tx *bbolt.Tx) (bool, error) {
+	secretsB := tx.Bucket([]byte(secretsBucket))
+	if secretsB == nil {
+		return false, fmt.Errorf("%w: secrets bucket is missing", ErrDatabaseCorrupt)
+	}
+	version, err := readSchemaVersion(tx)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case version == schemaVersion:
+		return false, nil
+	case version > schemaVersion:
+		return false, fmt.Errorf("database schema version %d is newer than the supported version %d", version, schemaVersion)
+	}
+
+	// The metadata moves first, so only secrets are left to re-encode.
+	metaB, err := moveMetadata(tx)
+	if err != nil {
+		return false, fmt.Errorf("failed to migrate metadata: %w", err)
+	}
+	if version < 2 {
+		for _, bucket := range []struct {
+			name  string
+			parts int
+		}{
+			{secretsBucket, 3},
+			{secretAccessBucket, 3},
+			{commonGrantsBucket, 2},
+		} {
+			if err := reencodeKeys(tx.Bucket([]byte(bucket.name)), bucket.parts); err != nil {
+				return false, fmt.Errorf("failed to migrate %s bucket: %w", bucket.name, err)
+			}
+		}
+	}
+	if version < 4 {
+		if err := nestSecrets(tx); err != nil {
+			return false, fmt.Errorf("failed to migrate secrets to namespace buckets: %w", err)
+		}
+	}
+	if err := metaB.Put([]byte(schemaVersionKey), []byte(strconv.Itoa(schemaVersion))); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// moveMetadata moves the salt and key hash from the secrets bucket, where schema
+// versions before 3 kept them, to the meta bucket and drops the old schema
+// version key. It returns the meta bucket.
+func moveMetadata(tx *bbolt.Tx) (*bbolt.Bucket, error) {
+	metaB, err := tx.CreateBucketIfNotExists([]byte(metaBucket))
+	if err != nil {
+		return nil, err
+	}
+	secretsB := tx.Bucket([]byte(secretsBucket))
+	for _, m := range []struct{ from, to string }{
+		{legacySaltKey, saltKey},
+		{legacyKeyHashKey, keyHashKey},
+		{legacySchemaVersionKey, ""},
+	} {
+		v := secretsB.Get([]byte(m.from))
+		if v == nil {
+			continue
+		}
+		if m.to != "" {
+			if err := metaB.Put([]byte(m.to), bytes.Clone(v)); err != nil {
+				return nil, err
+			}
+		}
+		if err := secretsB.Delete([]byte(m.from)); err != nil {
+			return nil, err
+		}
+	}
+	return metaB, nil
+}
+
+// reencodeKeys rewrites every schema version 1 key of b that has the given number
+// of null byte separated parts.
+func reencodeKeys(b *bbolt.Bucket, parts int) error {
+	if b == nil {
+		return nil
+	}
+	type entry struct{ key, value []byte }
+	var entries []entry
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if bytes.Count(k, nullByte) != parts-1 {
+			continue
+		}
+		entries = append(entries, entry{bytes.Clone(k), bytes.Clone(v)})
+	}
+	for _, e := range entries {
+		old := bytes.Split(e.key, nullByte)
+		names := make([]string, len(old))
+		for i, p := range old {
+			names[i] = string(p)
+		}
+		key := append(keyPrefix(names[:parts-1]...), escapeKeyPart(names[parts-1])...)
+		if err := b.Delete(e.key); err != nil {
+			return err
+		}
+		if err := b.Put(key, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
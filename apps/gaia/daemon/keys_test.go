@@ -0,0 +1,232 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"go.etcd.io/bbolt"
+)
+
+func TestSecretKey_RoundTrip(t *testing.T) {
+	names := []string{"", "app", "a\x00b", "\x00", "trailing\x00", "a\x00\x01b", "\xff\x00\xff"}
+	for _, client := range names {
+		for _, ns := range names {
+			for _, id := range names {
+				key := constructDBKey(client, ns, id)
+				c, n, i, ok := parseSecretKey(key)
+				if !ok || c != client || n != ns || i != id {
+					t.Errorf("parseSecretKey(constructDBKey(%q, %q, %q)) = %q, %q, %q, %v", client, ns, id, c, n, i, ok)
+				}
+				if !bytes.HasPrefix(key, keyPrefix(client, ns)) {
+					t.Errorf("key %q does not start with keyPrefix(%q, %q)", key, client, ns)
+				}
+			}
+		}
+	}
+
+	// A client's prefix never matches the keys of a client whose name extends it
+	// with a null byte, which the old encoding could not tell apart.
+	if bytes.HasPrefix(constructDBKey("app\x00evil", "ns", "id"), keyPrefix("app")) {
+		t.Error("keyPrefix(\"app\") matches a key of client \"app\\x00evil\"")
+	}
+	if _, _, _, ok := parseSecretKey([]byte(legacySaltKey)); ok {
+		t.Error("parseSecretKey() accepted the legacy salt key")
+	}
+}
+
+func TestSecretKey_NamesWithNullBytes(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "ns\x00x", "id\x00y", "v1", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app\x00evil", "ns", "id", "v2", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	namespaces, err := d.ListNamespaces("app")
+	if err != nil || !slices.Equal(namespaces, []string{"ns\x00x"}) {
+		t.Errorf("ListNamespaces(app) = %q, %v; want [\"ns\\x00x\"]", namespaces, err)
+	}
+	secrets, err := d.ListSecrets(t.Context(), "app")
+	if err != nil || len(secrets) != 1 || secrets["ns\x00x"]["id\x00y"] != "v1" {
+		t.Errorf("ListSecrets(app) = %q, %v", secrets, err)
+	}
+
+	impact, err := d.RevokeClient("app")
+	if err != nil {
+		t.Fatalf("RevokeClient() error = %v", err)
+	}
+	if impact.SecretCount != 1 {
+		t.Errorf("RevokeClient(app) removed %d secrets, want 1", impact.SecretCount)
+	}
+	if got, err := d.ListSecrets(t.Context(), "app\x00evil"); err != nil || got["ns"]["id"] != "v2" {
+		t.Errorf("secrets of another client after revoke = %q, %v; want ns/id = %q", got, err, "v2")
+	}
+}
+
+func TestUnlockDB_MigratesSchemaVersion1(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "api_key", "s3cret", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if err := d.SetCommonGrants("app", []string{"shared"}); err != nil {
+		t.Fatalf("SetCommonGrants() error = %v", err)
+	}
+	d.LockDB()
+
+	// Rewrite the database as an older build left it: bare null byte separators,
+	// metadata in the secrets bucket and no schema version.
+	downgradeMetadata(t, d.config.DBFile, 0)
+	db, err := bbolt.Open(d.config.DBFile, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		secretsB := tx.Bucket([]byte(secretsBucket))
+		value := bytes.Clone(secretsB.Get(constructDBKey("app", "app", "api_key")))
+		if err := secretsB.Delete(constructDBKey("app", "app", "api_key")); err != nil {
+			return err
+		}
+		if err := secretsB.Put([]byte("app\x00app\x00api_key"), value); err != nil {
+			return err
+		}
+		grantsB := tx.Bucket([]byte(commonGrantsBucket))
+		if err := grantsB.Delete(append(keyPrefix("app"), "shared"...)); err != nil {
+			return err
+		}
+		return grantsB.Put([]byte("app\x00shared"), []byte{})
+	})
+	db.Close()
+	if err != nil {
+		t.Fatalf("failed to downgrade database: %v", err)
+	}
+
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() error = %v", err)
+	}
+	if got, err := d.GetSecret("app", "app", "api_key"); err != nil || got != "s3cret" {
+		t.Errorf("GetSecret() after migration = %q, %v; want %q", got, err, "s3cret")
+	}
+	err = d.db.View(func(tx *bbolt.Tx) error {
+		if v := tx.Bucket([]byte(metaBucket)).Get([]byte(schemaVersionKey)); string(v) != "4" {
+			t.Errorf("schema version after migration = %q, want 4", v)
+		}
+		if grants := commonGrants(tx, "app"); !slices.Equal(grants, []string{"shared"}) {
+			t.Errorf("common grants after migration = %q, want [shared]", grants)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// downgradeMetadata moves the secrets of dbFile back to composite keys and its
+// metadata into the secrets bucket, as builds before schema version 3 kept
+// them. A version of 0 leaves out the schema version key, as schema version 1
+// did.
+func downgradeMetadata(t *testing.T, dbFile string, version int) {
+	t.Helper()
+	db, err := bbolt.Open(dbFile, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	err = db.Update(func(tx *bbolt.Tx) error {
+		if err := flattenSecrets(tx); err != nil {
+			return err
+		}
+		metaB, secretsB := tx.Bucket([]byte(metaBucket)), tx.Bucket([]byte(secretsBucket))
+		if err := secretsB.Put([]byte(legacySaltKey), bytes.Clone(metaB.Get([]byte(saltKey)))); err != nil {
+			return err
+		}
+		if err := secretsB.Put([]byte(legacyKeyHashKey), bytes.Clone(metaB.Get([]byte(keyHashKey)))); err != nil {
+			return err
+		}
+		if version > 0 {
+			if err := secretsB.Put([]byte(legacySchemaVersionKey), []byte(strconv.Itoa(version))); err != nil {
+				return err
+			}
+		}
+		return tx.DeleteBucket([]byte(metaBucket))
+	})
+	if err != nil {
+		t.Fatalf("failed to downgrade metadata: %v", err)
+	}
+}
+
+// flattenSecrets moves the secrets from client and namespace buckets back to
+// composite keys in the secrets bucket, as builds before schema version 4 kept
+// them.
+func flattenSecrets(tx *bbolt.Tx) error {
+	type entry struct{ key, value []byte }
+	var entries []entry
+	err := forEachSecret(tx, "", "", func(client, namespace, id string, v []byte) error {
+		entries = append(entries, entry{constructDBKey(client, namespace, id), bytes.Clone(v)})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := tx.DeleteBucket([]byte(secretsBucket)); err != nil {
+		return err
+	}
+	secretsB, err := tx.CreateBucket([]byte(secretsBucket))
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := secretsB.Put(e.key, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestUnlockDB_MovesMetadataOutOfSecrets(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "api_key", "s3cret", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	d.LockDB()
+	downgradeMetadata(t, d.config.DBFile, 2)
+
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() with metadata in the secrets bucket error = %v", err)
+	}
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		secretsB := tx.Bucket([]byte(secretsBucket))
+		for _, key := range []string{legacySaltKey, legacyKeyHashKey, legacySchemaVersionKey} {
+			if secretsB.Get([]byte(key)) != nil {
+				t.Errorf("secrets bucket still holds %q after migration", key)
+			}
+		}
+		if v := tx.Bucket([]byte(metaBucket)).Get([]byte(schemaVersionKey)); string(v) != "4" {
+			t.Errorf("schema version after migration = %q, want 4", v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := d.ExportSecrets(t.Context(), "")
+	if err != nil {
+		t.Fatalf("ExportSecrets() error = %v", err)
+	}
+	if len(exported) != 1 || len(exported["app"]) != 1 || exported["app"]["app"]["api_key"] != "s3cret" {
+		t.Errorf("ExportSecrets() = %v, want only app/app/api_key", exported)
+	}
+
+	// The passphrase is still checked against the moved key hash.
+	d.LockDB()
+	if err := d.UnlockDB("wrong passphrase"); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("UnlockDB() with a wrong passphrase error = %v, want ErrInvalidPassphrase", err)
+	}
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Errorf("UnlockDB() after migration error = %v", err)
+	}
+}
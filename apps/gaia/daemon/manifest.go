@@ -0,0 +1,117 @@
+package daemon
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// clientCertsBucket records the last certificate issued to each client, keyed by
+// client name.
+const clientCertsBucket = "client_certs"
+
+// IssuedCert is the metadata of a client certificate recorded at issue time. The
+// certificate itself and its private key are never stored.
+type IssuedCert struct {
+	Serial      string `json:"serial"`
+	Fingerprint string `json:"fingerprint"`
+	NotAfter    string `json:"not_after"`
+	IssuedAt    string `json:"issued_at"`
+}
+
+// ClientManifestEntry describes a registered client and its last issued certificate.
+type ClientManifestEntry struct {
+	Name        string
+	TimeCreated string
+	IssuedCert
+}
+
+// issuedCertFromPEM extracts the recorded metadata from a PEM encoded certificate.
+func issuedCertFromPEM(certPEM []byte, issuedAt time.Time) (*IssuedCert, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	sum := sha256.Sum256(cert.Raw)
+	return &IssuedCert{
+		Serial:      cert.SerialNumber.Text(16),
+		Fingerprint: hex.EncodeToString(sum[:]),
+		NotAfter:    cert.NotAfter.UTC().Format(time.RFC3339),
+		IssuedAt:    issuedAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// RecordIssuedCert stores the serial, fingerprint and expiry of a certificate
+// issued to clientName, replacing any previous record.
+func (d *Daemon) RecordIssuedCert(clientName string, certPEM []byte) error {
+	issued, err := issuedCertFromPEM(certPEM, time.Now())
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(issued)
+	if err != nil {
+		return err
+	}
+
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return errors.New("daemon is in a locked state, cannot record certificates")
+	}
+
+	return d.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(clientCertsBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create or get client certificates bucket: %w", err)
+		}
+		return b.Put([]byte(clientName), data)
+	})
+}
+
+// ClientManifest joins every client registration with its last issued certificate.
+func (d *Daemon) ClientManifest() ([]ClientManifestEntry, error) {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked || d.db == nil {
+		return nil, errors.New("daemon is in a locked state, cannot export client manifest")
+	}
+
+	var entries []ClientManifestEntry
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		clientsB := tx.Bucket([]byte(clientsBucket))
+		if clientsB == nil {
+			return nil
+		}
+		certsB := tx.Bucket([]byte(clientCertsBucket))
+
+		return clientsB.ForEach(func(k, v []byte) error {
+			entry := ClientManifestEntry{Name: string(k), TimeCreated: string(v)}
+			if certsB != nil {
+				if data := certsB.Get(k); data != nil {
+					if err := json.Unmarshal(data, &entry.IssuedCert); err != nil {
+						return fmt.Errorf("corrupt certificate record for client '%s': %w", k, err)
+					}
+				}
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"log/slog"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+)
+
+// wipe overwrites b with zeros.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// setKey installs key as the master key and wipes the caller's copy. With
+// LockKeyMemory the key is copied into locked memory outside the Go heap; if that
+// fails, it stays in ordinary memory and a warning is logged. The caller must
+// hold dbLock for writing.
+func (d *Daemon) setKey(key []byte) {
+	d.wipeKey()
+	if d.config.LockKeyMemory {
+		buf, err := allocLocked(len(key))
+		if err == nil {
+			copy(buf, key)
+			wipe(key)
+			d.key = buf
+			d.keyLocked = true
+			return
+		}
+		gaialog.Get().Warn("Failed to lock the master key in memory, it may be swapped to disk",
+			slog.String("error", err.Error()))
+	}
+	d.key = key
+}
+
+// wipeKey zeroes the master key and releases its locked memory, if any. The
+// caller must hold dbLock for writing.
+func (d *Daemon) wipeKey() {
+	wipe(d.key)
+	if d.keyLocked {
+		if err := freeLocked(d.key); err != nil {
+			gaialog.Get().Warn("Failed to release locked key memory", slog.String("error", err.Error()))
+		}
+		d.keyLocked = false
+	}
+	d.key = nil
+}
@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+// lockedKiB returns the VmLck value of the current process in KiB.
+func lockedKiB(t *testing.T) int {
+	t.Helper()
+	data, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		t.Skipf("cannot read /proc/self/status: %v", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, "VmLck:"); ok {
+			kib, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(rest), " kB"))
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v", line, err)
+			}
+			return kib
+		}
+	}
+	t.Skip("VmLck not reported by the kernel")
+	return 0
+}
+
+func TestLockKeyMemory(t *testing.T) {
+	before := lockedKiB(t)
+	d := newTestDaemon(t, func(c *config.Config) { c.LockKeyMemory = true })
+	if !d.keyLocked {
+		t.Skip("memory locking is not permitted in this environment")
+	}
+	if after := lockedKiB(t); after <= before {
+		t.Errorf("VmLck = %d kB after unlock, want more than %d kB", after, before)
+	}
+
+	d.LockDB()
+	if d.keyLocked || d.key != nil {
+		t.Error("LockDB() left the key in place")
+	}
+	if got := lockedKiB(t); got != before {
+		t.Errorf("VmLck = %d kB after lock, want %d kB", got, before)
+	}
+}
+
+func TestLockKeyMemory_Disabled(t *testing.T) {
+	d := newTestDaemon(t)
+	if d.keyLocked {
+		t.Error("key memory locked without lock_key_memory")
+	}
+	if len(d.key) == 0 || bytes.Equal(d.key, make([]byte, len(d.key))) {
+		t.Error("key missing after unlock")
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+package daemon
+
+import "errors"
+
+func allocLocked(n int) ([]byte, error) {
+	return nil, errors.New("memory locking is not supported on this platform")
+}
+
+func freeLocked(b []byte) error {
+	return nil
+}
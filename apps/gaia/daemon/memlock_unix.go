@@ -0,0 +1,28 @@
+//go:build unix
+
+package daemon
+
+import "golang.org/x/sys/unix"
+
+// allocLocked maps n bytes of anonymous memory and locks them into RAM. The
+// mapping is outside the Go heap, so the garbage collector never copies it.
+func allocLocked(n int) ([]byte, error) {
+	b, err := unix.Mmap(-1, 0, n, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Mlock(b); err != nil {
+		unix.Munmap(b)
+		return nil, err
+	}
+	return b, nil
+}
+
+// freeLocked unlocks and unmaps memory returned by allocLocked.
+func freeLocked(b []byte) error {
+	if err := unix.Munlock(b); err != nil {
+		unix.Munmap(b)
+		return err
+	}
+	return unix.Munmap(b)
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package daemon
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// allocLocked allocates n bytes and locks them into the working set with
+// VirtualLock. The Go garbage collector does not move heap objects, so the
+// locked range stays valid until freeLocked.
+func allocLocked(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if err := windows.VirtualLock(uintptr(unsafe.Pointer(unsafe.SliceData(b))), uintptr(n)); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// freeLocked unlocks memory returned by allocLocked.
+func freeLocked(b []byte) error {
+	return windows.VirtualUnlock(uintptr(unsafe.Pointer(unsafe.SliceData(b))), uintptr(len(b)))
+}
@@ -0,0 +1,43 @@
+package daemon
+
+import "sync/atomic"
+
+// Metrics is a point-in-time snapshot of the daemon's operational counters. It is
+// intended for programs that embed the daemon package and want the numbers without
+// going through an external metrics endpoint.
+type Metrics struct {
+	SecretsServed  uint64 // Successful GetSecret calls.
+	AccessDenied   uint64 // GetSecret calls rejected by namespace authorization.
+	SecretsWritten uint64 // Secrets stored by AddSecret, ImportSecrets and RotateSecrets.
+	SecretsDeleted uint64 // Successful DeleteSecret calls.
+	Unlocks        uint64 // Successful unlocks.
+	FailedUnlocks  uint64 // Unlock attempts that returned an error.
+	Locked         bool   // Whether the daemon is currently locked.
+}
+
+// counters holds the live values behind Metrics. The zero value is ready to use.
+type counters struct {
+	secretsServed  atomic.Uint64
+	accessDenied   atomic.Uint64
+	secretsWritten atomic.Uint64
+	secretsDeleted atomic.Uint64
+	unlocks        atomic.Uint64
+	failedUnlocks  atomic.Uint64
+}
+
+// Metrics returns a snapshot of the daemon's counters and current lock state.
+func (d *Daemon) Metrics() Metrics {
+	d.dbLock.RLock()
+	locked := d.isLocked
+	d.dbLock.RUnlock()
+
+	return Metrics{
+		SecretsServed:  d.counters.secretsServed.Load(),
+		AccessDenied:   d.counters.accessDenied.Load(),
+		SecretsWritten: d.counters.secretsWritten.Load(),
+		SecretsDeleted: d.counters.secretsDeleted.Load(),
+		Unlocks:        d.counters.unlocks.Load(),
+		FailedUnlocks:  d.counters.failedUnlocks.Load(),
+		Locked:         locked,
+	}
+}
@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+// namespacePatternsBucket holds the namespaces each client may be written to, keyed
+// by client name. Clients without an entry accept writes to any namespace.
+const namespacePatternsBucket = "namespace_patterns"
+
+// ErrNamespaceNotAllowed is returned for admin writes to a namespace that does not
+// match any of the patterns set for the client.
+var ErrNamespaceNotAllowed = errors.New("namespace is not allowed for client")
+
+// compileNamespacePatterns compiles patterns as regular expressions that must match
+// the whole namespace, so a plain name such as "prod" only allows "prod" itself.
+func compileNamespacePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		re, err := regexp.Compile("^(?:" + p + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace pattern '%s': %w", p, err)
+		}
+		res[i] = re
+	}
+	return res, nil
+}
+
+// namespacePatterns returns the namespace patterns set for clientName, or nil when
+// the client is not restricted.
+func namespacePatterns(tx *bbolt.Tx, clientName string) ([]string, error) {
+	b := tx.Bucket([]byte(namespacePatternsBucket))
+	if b == nil {
+		return nil, nil
+	}
+	v := b.Get([]byte(clientName))
+	if v == nil {
+		return nil, nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(v, &patterns); err != nil {
+		return nil, fmt.Errorf("corrupt namespace patterns for client '%s': %w", clientName, err)
+	}
+	return patterns, nil
+}
+
+// checkNamespaceAllowed returns ErrNamespaceNotAllowed when clientName has namespace
+// patterns and none of them matches namespace.
+func checkNamespaceAllowed(tx *bbolt.Tx, clientName, namespace string) error {
+	patterns, err := namespacePatterns(tx, clientName)
+	if err != nil || len(patterns) == 0 {
+		return err
+	}
+	res, err := compileNamespacePatterns(patterns)
+	if err != nil {
+		return err
+	}
+	for _, re := range res {
+		if re.MatchString(namespace) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: client '%s' only accepts namespaces matching %s", ErrNamespaceNotAllowed, clientName, strings.Join(patterns, ", "))
+}
+
+// SetNamespacePatterns replaces the patterns a namespace must match for secrets of
+// clientName to be written to it. An empty list removes the restriction.
+func (d *Daemon) SetNamespacePatterns(clientName string, patterns []string) error {
+	if _, err := compileNamespacePatterns(patterns); err != nil {
+		return err
+	}
+	data, err := json.Marshal(patterns)
+	if err != nil {
+		return err
+	}
+
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return errors.New("daemon is in a locked state, cannot change namespace patterns")
+	}
+
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		if len(patterns) == 0 {
+			return deleteNamespacePatterns(tx, clientName)
+		}
+		b, err := tx.CreateBucketIfNotExists([]byte(namespacePatternsBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create or get namespace patterns bucket: %w", err)
+		}
+		return b.Put([]byte(clientName), data)
+	})
+
+	if err == nil {
+		gaialog.Get().Info("namespace patterns updated",
+			slog.String("client_name", clientName),
+			slog.String("patterns", strings.Join(patterns, ",")),
+		)
+	}
+	return err
+}
+
+// deleteNamespacePatterns removes the namespace patterns of clientName.
+func deleteNamespacePatterns(tx *bbolt.Tx, clientName string) error {
+	b := tx.Bucket([]byte(namespacePatternsBucket))
+	if b == nil {
+		return nil
+	}
+	return b.Delete([]byte(clientName))
+}
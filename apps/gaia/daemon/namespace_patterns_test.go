@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNamespacePatterns_AllowedWrite(t *testing.T) {
+	d := newTestDaemon(t)
+	if err := d.SetNamespacePatterns("app-a", []string{"app-a", "staging-.*"}); err != nil {
+		t.Fatalf("SetNamespacePatterns() error = %v", err)
+	}
+
+	if res := addSecretViaAdmin(t, d, "app-a", "app-a", "api_key", "s3cret"); !res.Success {
+		t.Fatalf("AddSecret() to an allowed namespace failed: %s", res.Message)
+	}
+	if _, err := d.ImportSecrets([]*pb.ImportSecretItem{
+		{ClientName: "app-a", Namespace: "staging-eu", Id: "token", Value: "v"},
+	}, ImportFailOnConflict, ""); err != nil {
+		t.Fatalf("ImportSecrets() to an allowed namespace error = %v", err)
+	}
+	if got, err := d.GetSecret("app-a", "app-a", "api_key"); err != nil || got != "s3cret" {
+		t.Errorf("GetSecret() = %q, %v; want %q", got, err, "s3cret")
+	}
+}
+
+func TestNamespacePatterns_DisallowedWrite(t *testing.T) {
+	d := newTestDaemon(t)
+	if err := d.SetNamespacePatterns("app-a", []string{"app-a", "production"}); err != nil {
+		t.Fatalf("SetNamespacePatterns() error = %v", err)
+	}
+
+	_, err := NewAdminServer(d).AddSecret(context.Background(), &pb.AddSecretRequest{
+		ClientName: "app-a", Namespace: "prod", Id: "api_key", Value: "s3cret",
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("AddSecret() to a disallowed namespace error = %v, want InvalidArgument", err)
+	}
+
+	// A full match is required, so a pattern does not allow namespaces containing it.
+	_, err = d.ImportSecrets([]*pb.ImportSecretItem{
+		{ClientName: "app-a", Namespace: "app-a", Id: "ok", Value: "v"},
+		{ClientName: "app-a", Namespace: "production-old", Id: "token", Value: "v"},
+	}, ImportFailOnConflict, "")
+	if !errors.Is(err, ErrNamespaceNotAllowed) {
+		t.Fatalf("ImportSecrets() error = %v, want ErrNamespaceNotAllowed", err)
+	}
+	if _, err := d.GetSecret("app-a", "app-a", "ok"); err == nil {
+		t.Errorf("ImportSecrets() partially applied a rejected import")
+	}
+
+	// Other clients and a cleared restriction are not affected.
+	if _, err := d.AddSecret("app-b", "prod", "api_key", "v", AddSecretOptions{}); err != nil {
+		t.Errorf("AddSecret() for an unrestricted client error = %v", err)
+	}
+	if err := d.SetNamespacePatterns("app-a", nil); err != nil {
+		t.Fatalf("SetNamespacePatterns(nil) error = %v", err)
+	}
+	if _, err := d.AddSecret("app-a", "prod", "api_key", "v", AddSecretOptions{}); err != nil {
+		t.Errorf("AddSecret() after clearing patterns error = %v", err)
+	}
+}
+
+func TestSetNamespacePatterns_InvalidPattern(t *testing.T) {
+	d := newTestDaemon(t)
+	if err := d.SetNamespacePatterns("app-a", []string{"prod("}); err == nil {
+		t.Fatal("SetNamespacePatterns() with an invalid regular expression succeeded")
+	}
+}
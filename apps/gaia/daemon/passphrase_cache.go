@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// passphraseCheckCache remembers the last passphrase VerifyPassphrase accepted,
+// so repeated checks within its TTL skip the slow key derivation. It holds one
+// entry: an HMAC of the passphrase keyed with the master key, never the
+// passphrase itself, and is reset when the daemon locks or is rekeyed.
+type passphraseCheckCache struct {
+	mu      sync.Mutex
+	mac     []byte
+	expires time.Time
+}
+
+// passphraseMAC returns the HMAC-SHA256 of passphrase under key.
+func passphraseMAC(key []byte, passphrase string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(passphrase))
+	return h.Sum(nil)
+}
+
+// matches reports whether passphrase is the cached one and the entry has not
+// expired at now. The comparison is constant-time.
+func (c *passphraseCheckCache) matches(key []byte, passphrase string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.mac == nil || len(key) == 0 {
+		return false
+	}
+	if !now.Before(c.expires) {
+		c.mac = nil
+		return false
+	}
+	return hmac.Equal(c.mac, passphraseMAC(key, passphrase))
+}
+
+// store caches passphrase as verified until expires, replacing any entry.
+func (c *passphraseCheckCache) store(key []byte, passphrase string, expires time.Time) {
+	if len(key) == 0 {
+		return
+	}
+	mac := passphraseMAC(key, passphrase)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mac, c.expires = mac, expires
+}
+
+// reset drops the cached entry.
+func (c *passphraseCheckCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mac = nil
+}
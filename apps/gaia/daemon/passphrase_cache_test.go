@@ -0,0 +1,55 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+func TestVerifyPassphrase_Cached(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.PassphraseCheckCacheTTL = time.Minute })
+
+	start := time.Now()
+	if err := d.VerifyPassphrase(testPassphrase); err != nil {
+		t.Fatalf("VerifyPassphrase() error = %v", err)
+	}
+	derived := time.Since(start)
+
+	start = time.Now()
+	if err := d.VerifyPassphrase(testPassphrase); err != nil {
+		t.Fatalf("cached VerifyPassphrase() error = %v", err)
+	}
+	if cached := time.Since(start); cached > derived/4 {
+		t.Errorf("cached VerifyPassphrase() took %v, key derivation took %v", cached, derived)
+	}
+
+	if err := d.VerifyPassphrase("wrong passphrase"); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("VerifyPassphrase() with a wrong passphrase error = %v, want ErrInvalidPassphrase", err)
+	}
+	if !d.passCheck.matches(d.key, testPassphrase, time.Now()) {
+		t.Error("a failed verification evicted the cached passphrase")
+	}
+	if d.passCheck.matches(d.key, testPassphrase, time.Now().Add(time.Minute)) {
+		t.Error("cached passphrase still matches after the TTL")
+	}
+
+	if err := d.VerifyPassphrase(testPassphrase); err != nil {
+		t.Fatalf("VerifyPassphrase() error = %v", err)
+	}
+	d.LockDB()
+	if d.passCheck.mac != nil {
+		t.Error("LockDB() kept the cached passphrase")
+	}
+}
+
+func TestVerifyPassphrase_CacheDisabled(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.PassphraseCheckCacheTTL = 0 })
+	if err := d.VerifyPassphrase(testPassphrase); err != nil {
+		t.Fatalf("VerifyPassphrase() error = %v", err)
+	}
+	if d.passCheck.mac != nil {
+		t.Error("passphrase was cached with a zero TTL")
+	}
+}
@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+// OrphanedClient is a client that has secrets but no registration.
+type OrphanedClient struct {
+	Name    string
+	Secrets int
+}
+
+// PruneReport lists what PruneOrphans removed, or would remove on a dry run.
+type PruneReport struct {
+	// Orphaned are the unregistered clients whose secrets are removed.
+	Orphaned []OrphanedClient
+	// EmptyClients are the registered clients without secrets. They are only
+	// removed when asked for.
+	EmptyClients []string
+}
+
+// PruneOrphans removes the secrets of clients that are not registered, as left
+// behind when a registration was deleted out of band. With emptyClients, it
+// also removes registrations that hold no secrets, like RevokeClient does. The
+// common area is never pruned. With dryRun, nothing is changed and the report
+// lists what would be removed.
+func (d *Daemon) PruneOrphans(dryRun, emptyClients bool) (*PruneReport, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return nil, errors.New("daemon is in a locked state, cannot prune orphans")
+	}
+
+	report := &PruneReport{}
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		registered := make(map[string]bool)
+		if clientsB := tx.Bucket([]byte(clientsBucket)); clientsB != nil {
+			if err := clientsB.ForEach(func(k, _ []byte) error {
+				registered[string(k)] = true
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		secrets := make(map[string]int)
+		if err := forEachSecret(tx, "", "", func(client, _, _ string, _ []byte) error {
+			secrets[client]++
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		common := d.commonName()
+		for client, n := range secrets {
+			if !registered[client] && client != common {
+				report.Orphaned = append(report.Orphaned, OrphanedClient{Name: client, Secrets: n})
+			}
+		}
+		slices.SortFunc(report.Orphaned, func(a, b OrphanedClient) int { return strings.Compare(a.Name, b.Name) })
+		if emptyClients {
+			for client := range registered {
+				if secrets[client] == 0 && client != common {
+					report.EmptyClients = append(report.EmptyClients, client)
+				}
+			}
+			slices.Sort(report.EmptyClients)
+		}
+
+		if dryRun {
+			return nil
+		}
+		for _, o := range report.Orphaned {
+			if err := deleteClientSecrets(tx, o.Name); err != nil {
+				return err
+			}
+		}
+		for _, client := range report.EmptyClients {
+			if err := deleteClientRecords(tx, client); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		for _, client := range report.EmptyClients {
+			d.grants.invalidate(client)
+		}
+		for _, o := range report.Orphaned {
+			gaialog.Get().Info("orphaned secrets pruned", slog.String("client_name", o.Name), slog.Int("secrets", o.Secrets))
+		}
+		for _, client := range report.EmptyClients {
+			gaialog.Get().Info("empty client registration pruned", slog.String("client_name", client))
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestPruneOrphans(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, name := range []string{"billing", "idle"} {
+		if err := d.RegisterClient(name); err != nil {
+			t.Fatalf("RegisterClient(%s) error = %v", name, err)
+		}
+	}
+	// ghost has secrets but no registration, as when it was removed out of band.
+	for _, s := range []struct{ client, namespace, id string }{
+		{"billing", "billing", "api_key"},
+		{"ghost", "ghost", "api_key"},
+		{"ghost", "ghost-extra", "token"},
+		{d.commonName(), "shared", "global_key"},
+	} {
+		if _, err := d.AddSecret(s.client, s.namespace, s.id, "value", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret(%s) error = %v", s.client, err)
+		}
+	}
+	wantOrphans := []OrphanedClient{{Name: "ghost", Secrets: 2}}
+
+	report, err := d.PruneOrphans(true, true)
+	if err != nil {
+		t.Fatalf("PruneOrphans(dry run) error = %v", err)
+	}
+	if !reflect.DeepEqual(report.Orphaned, wantOrphans) || !reflect.DeepEqual(report.EmptyClients, []string{"idle"}) {
+		t.Errorf("PruneOrphans(dry run) = %+v, want ghost's secrets and idle", report)
+	}
+	if _, err := d.GetSecret("ghost", "ghost", "api_key"); err != nil {
+		t.Errorf("dry run deleted an orphaned secret: %v", err)
+	}
+
+	report, err = d.PruneOrphans(false, false)
+	if err != nil {
+		t.Fatalf("PruneOrphans() error = %v", err)
+	}
+	if !reflect.DeepEqual(report.Orphaned, wantOrphans) || report.EmptyClients != nil {
+		t.Errorf("PruneOrphans() = %+v, want only ghost's secrets", report)
+	}
+	if ns, err := d.ListNamespaces("ghost"); err != nil || len(ns) != 0 {
+		t.Errorf("ghost still has namespaces %v after the prune (error %v)", ns, err)
+	}
+	if _, err := d.GetSecret("billing", "billing", "api_key"); err != nil {
+		t.Errorf("prune deleted a secret of a registered client: %v", err)
+	}
+	if ns, err := d.ListNamespaces(d.commonName()); err != nil || !slices.Equal(ns, []string{"shared"}) {
+		t.Errorf("common namespaces after the prune = %v, %v, want shared", ns, err)
+	}
+	if names := clientNames(t, d); !slices.Contains(names, "idle") {
+		t.Errorf("prune without empty clients removed idle: %v", names)
+	}
+
+	if _, err := d.PruneOrphans(false, true); err != nil {
+		t.Fatalf("PruneOrphans(empty clients) error = %v", err)
+	}
+	names := clientNames(t, d)
+	if slices.Contains(names, "idle") || !slices.Contains(names, "billing") || !slices.Contains(names, d.commonName()) {
+		t.Errorf("clients after pruning empty registrations = %v, want billing and %s without idle", names, d.commonName())
+	}
+}
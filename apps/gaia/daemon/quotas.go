@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+)
+
+// ErrQuotaExceeded is returned for writes of a new secret that would exceed the
+// configured number of namespaces of a client or secrets of a namespace.
+// Updates of existing secrets are always allowed.
+var ErrQuotaExceeded = errors.New("secret quota exceeded")
+
+// checkQuota returns ErrQuotaExceeded when storing the secret id would add a
+// secret or namespace beyond the configured limits. It counts the secrets of the
+// transaction, so secrets written earlier in the same import are included.
+func (d *Daemon) checkQuota(tx *bbolt.Tx, clientName, namespace, id string) error {
+	maxNamespaces, maxSecrets := d.config.MaxNamespacesPerClient, d.config.MaxSecretsPerNamespace
+	if maxNamespaces <= 0 && maxSecrets <= 0 {
+		return nil
+	}
+	if getSecretValue(tx, clientName, namespace, id) != nil {
+		return nil
+	}
+
+	secrets := countSecrets(namespaceBucket(tx, clientName, namespace))
+	if maxSecrets > 0 && secrets >= maxSecrets {
+		return fmt.Errorf("%w: namespace '%s' of client '%s' already holds %d secrets", ErrQuotaExceeded, namespace, clientName, maxSecrets)
+	}
+	if maxNamespaces > 0 && secrets == 0 {
+		if n := len(secretNamespaces(tx, clientName)); n >= maxNamespaces {
+			return fmt.Errorf("%w: client '%s' already has %d namespaces", ErrQuotaExceeded, clientName, maxNamespaces)
+		}
+	}
+	return nil
+}
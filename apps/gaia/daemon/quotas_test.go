@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func withQuotas(cfg *config.Config) {
+	cfg.MaxNamespacesPerClient = 2
+	cfg.MaxSecretsPerNamespace = 2
+}
+
+func TestAddSecret_MaxSecretsPerNamespace(t *testing.T) {
+	d := newTestDaemon(t, withQuotas)
+	srv := NewAdminServer(d)
+
+	for _, id := range []string{"one", "two"} {
+		if _, err := d.AddSecret("app", "app", id, "v", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret(%s) error = %v", id, err)
+		}
+	}
+	if _, err := d.AddSecret("app", "app", "three", "v", AddSecretOptions{}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("AddSecret() beyond the limit error = %v, want ErrQuotaExceeded", err)
+	}
+	_, err := srv.AddSecret(context.Background(), &pb.AddSecretRequest{ClientName: "app", Namespace: "app", Id: "three", Value: "v"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("AddSecret RPC beyond the limit error = %v, want ResourceExhausted", err)
+	}
+
+	if _, err := d.AddSecret("app", "app", "two", "updated", AddSecretOptions{}); err != nil {
+		t.Errorf("AddSecret() updating a secret at the limit error = %v", err)
+	}
+	if v, _ := d.GetSecret("app", "app", "two"); v != "updated" {
+		t.Errorf("GetSecret() = %q, want %q", v, "updated")
+	}
+}
+
+func TestAddSecret_MaxNamespacesPerClient(t *testing.T) {
+	d := newTestDaemon(t, withQuotas)
+
+	for _, ns := range []string{"app", "db"} {
+		if _, err := d.AddSecret("app", ns, "key", "v", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret(%s) error = %v", ns, err)
+		}
+	}
+	if _, err := d.AddSecret("app", "cache", "key", "v", AddSecretOptions{}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("AddSecret() to a namespace beyond the limit error = %v, want ErrQuotaExceeded", err)
+	}
+	if _, err := d.AddSecret("app", "db", "other", "v", AddSecretOptions{}); err != nil {
+		t.Errorf("AddSecret() to an existing namespace at the limit error = %v", err)
+	}
+	if _, err := d.AddSecret("other", "cache", "key", "v", AddSecretOptions{}); err != nil {
+		t.Errorf("AddSecret() for another client error = %v", err)
+	}
+}
+
+func TestImportSecrets_Quotas(t *testing.T) {
+	d := newTestDaemon(t, withQuotas)
+	srv := NewAdminServer(d)
+
+	_, err := srv.SetSecrets(context.Background(), &pb.SetSecretsRequest{Secrets: []*pb.ImportSecretItem{
+		{ClientName: "app", Namespace: "app", Id: "one", Value: "v"},
+		{ClientName: "app", Namespace: "app", Id: "two", Value: "v"},
+		{ClientName: "app", Namespace: "app", Id: "three", Value: "v"},
+	}})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("SetSecrets() beyond the limit error = %v, want ResourceExhausted", err)
+	}
+	if _, err := d.GetSecret("app", "app", "one"); err == nil {
+		t.Error("secret imported alongside one beyond the limit")
+	}
+
+	secrets := []*pb.ImportSecretItem{
+		{ClientName: "app", Namespace: "app", Id: "one", Value: "v"},
+		{ClientName: "app", Namespace: "app", Id: "two", Value: "v"},
+	}
+	if _, err := d.ImportSecrets(secrets, ImportFailOnConflict, ""); err != nil {
+		t.Fatalf("ImportSecrets() at the limit error = %v", err)
+	}
+	if _, err := d.ImportSecrets(secrets, ImportOverwrite, ""); err != nil {
+		t.Errorf("ImportSecrets() overwriting secrets at the limit error = %v", err)
+	}
+}
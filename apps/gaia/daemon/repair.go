@@ -0,0 +1,281 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+// ErrWeakPassphrase is returned by Rekey for a new passphrase that does not pass
+// the strength check of encrypt.ValidatePassword.
+var ErrWeakPassphrase = errors.New("passphrase is too weak")
+
+// ErrRekeyBlocked is returned by Rekey with verifyFirst when secrets fail to
+// decrypt. It lists every failing secret and nothing is changed.
+var ErrRekeyBlocked = errors.New("secrets fail to decrypt")
+
+// requiredBuckets are recreated empty by VerifyAndRepair when they are missing.
+// The meta and secrets buckets are not among them: the salt and key hash cannot
+// be recreated, and an empty secrets bucket would hide the loss of every secret.
+var requiredBuckets = []string{clientsBucket}
+
+// VerifyAndRepair checks that the secrets bucket and the metadata keys are
+// present and recreates missing required buckets without touching any data. It
+// returns the names of the buckets it recreated.
+func (d *Daemon) VerifyAndRepair() ([]string, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return nil, errors.New("daemon is in a locked state, cannot verify the database")
+	}
+	return d.verifyAndRepairLocked()
+}
+
+// verifyAndRepairLocked implements VerifyAndRepair. The caller must hold dbLock
+// for writing and the database must be open.
+func (d *Daemon) verifyAndRepairLocked() ([]string, error) {
+	var repaired []string
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(secretsBucket)) == nil {
+			return fmt.Errorf("%w: secrets bucket is missing, restore the database from a backup", ErrDatabaseCorrupt)
+		}
+		if _, _, err := readKeyMaterial(tx); err != nil {
+			return err
+		}
+
+		for _, name := range requiredBuckets {
+			if tx.Bucket([]byte(name)) != nil {
+				continue
+			}
+			if _, err := tx.CreateBucket([]byte(name)); err != nil {
+				return fmt.Errorf("failed to recreate bucket '%s': %w", name, err)
+			}
+			repaired = append(repaired, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range repaired {
+		gaialog.Get().Warn("Recreated missing database bucket", slog.String("bucket", name))
+	}
+	return repaired, nil
+}
+
+// IntegrityFailure identifies a secret whose stored value could not be
+// decrypted. It never carries the value.
+type IntegrityFailure struct {
+	Client    string
+	Namespace string
+	ID        string
+	Reason    string
+}
+
+// VerifyIntegrity decrypts every secret of every client and returns the number
+// checked together with the secrets that failed, so corruption of the database
+// file is found before a client asks for the damaged secret.
+func (d *Daemon) VerifyIntegrity(ctx context.Context) (int, []IntegrityFailure, error) {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked || d.db == nil {
+		return 0, nil, errors.New("daemon is in a locked state, cannot verify secrets")
+	}
+
+	checked := 0
+	var failures []IntegrityFailure
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(secretsBucket)) == nil {
+			return fmt.Errorf("%w: secrets bucket is missing", ErrDatabaseCorrupt)
+		}
+		// Expired secrets are checked too: Rekey re-encrypts them until they are swept.
+		return forEachSecret(tx, "", "", func(client, namespace, id string, v []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			checked++
+			value, err := encrypt.DecryptValue(d.key, v)
+			wipe(value)
+			if err != nil {
+				failures = append(failures, IntegrityFailure{Client: client, Namespace: namespace, ID: id, Reason: err.Error()})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, f := range failures {
+		gaialog.Get().Warn("secret failed to decrypt",
+			slog.String("client_name", f.Client), slog.String("namespace", f.Namespace), slog.String("id", f.ID))
+	}
+	return checked, failures, nil
+}
+
+// RekeyPlan is the result of RekeyDryRun.
+type RekeyPlan struct {
+	// Secrets is the number of secrets a rekey re-encrypts.
+	Secrets int
+	// Failures are the secrets that do not decrypt and would make a rekey fail.
+	Failures []IntegrityFailure
+}
+
+// Ready reports whether a rekey can run without hitting undecryptable secrets.
+func (p *RekeyPlan) Ready() bool {
+	return len(p.Failures) == 0
+}
+
+// RekeyDryRun checks oldPassphrase against the stored key hash and decrypts
+// every secret without writing anything, so corruption is found and fixed before
+// a rekey re-encrypts the database. It returns ErrInvalidPassphrase when
+// oldPassphrase is wrong.
+func (d *Daemon) RekeyDryRun(ctx context.Context, oldPassphrase string) (*RekeyPlan, error) {
+	if err := d.VerifyPassphrase(oldPassphrase); err != nil {
+		return nil, err
+	}
+	checked, failures, err := d.VerifyIntegrity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &RekeyPlan{Secrets: checked, Failures: failures}, nil
+}
+
+// Rekey changes the master passphrase from oldPassphrase to newPassphrase. It
+// derives a new key with a fresh salt and re-encrypts every secret in a single
+// transaction, so either the whole database uses the new key or nothing changes.
+// With verifyFirst, every secret is decrypted before any is rewritten, under the
+// same lock and transaction, and ErrRekeyBlocked lists all that fail; otherwise
+// Rekey fails on the first one. It returns ErrInvalidPassphrase when
+// oldPassphrase is wrong and ErrWeakPassphrase when newPassphrase is too weak.
+// Databases unlocked with key shares have no passphrase and return
+// ErrKeySharesRequired.
+func (d *Daemon) Rekey(oldPassphrase, newPassphrase string, verifyFirst bool) (int, error) {
+	if _, err := encrypt.ValidatePassword(newPassphrase); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrWeakPassphrase, err)
+	}
+
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return 0, errors.New("daemon is in a locked state, cannot rekey the database")
+	}
+
+	newSalt := make([]byte, saltLen)
+	if _, err := rand.Read(newSalt); err != nil {
+		return 0, err
+	}
+	newKey, err := encrypt.DeriveKey([]byte(newPassphrase), newSalt)
+	if err != nil {
+		return 0, err
+	}
+
+	rekeyed := 0
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		threshold, err := readUnlockThreshold(tx)
+		if err != nil {
+			return err
+		}
+		if threshold > 0 {
+			return ErrKeySharesRequired
+		}
+		salt, storedHash, err := readKeyMaterial(tx)
+		if err != nil {
+			return err
+		}
+		oldKey, err := encrypt.DeriveKey([]byte(oldPassphrase), salt)
+		if err != nil {
+			return err
+		}
+		defer wipe(oldKey)
+		oldHash := sha256.Sum256(oldKey)
+		if subtle.ConstantTimeCompare(oldHash[:], storedHash) != 1 {
+			return ErrInvalidPassphrase
+		}
+
+		if tx.Bucket([]byte(secretsBucket)) == nil {
+			return fmt.Errorf("%w: secrets bucket is missing", ErrDatabaseCorrupt)
+		}
+		// Collect first: bbolt cursors must not be used across Puts to the same bucket.
+		type entry struct {
+			ref   secretRef
+			value []byte
+		}
+		var entries []entry
+		err = forEachSecret(tx, "", "", func(client, namespace, id string, v []byte) error {
+			entries = append(entries, entry{ref: secretRef{client, namespace, id}, value: bytes.Clone(v)})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if verifyFirst {
+			plan := &RekeyPlan{Secrets: len(entries)}
+			for _, e := range entries {
+				value, err := encrypt.DecryptValue(d.key, e.value)
+				wipe(value)
+				if err != nil {
+					plan.Failures = append(plan.Failures, IntegrityFailure{Client: e.ref.client, Namespace: e.ref.namespace, ID: e.ref.id, Reason: err.Error()})
+				}
+			}
+			if !plan.Ready() {
+				failed := make([]string, len(plan.Failures))
+				for i, f := range plan.Failures {
+					failed[i] = fmt.Sprintf("%s: %s", secretRef{f.Client, f.Namespace, f.ID}, f.Reason)
+				}
+				return fmt.Errorf("%w: %d of %d: %s", ErrRekeyBlocked, len(plan.Failures), plan.Secrets, strings.Join(failed, "; "))
+			}
+		}
+		for _, e := range entries {
+			value, err := encrypt.DecryptValue(d.key, e.value)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt secret '%s', run 'gaia db rekey-check': %w", e.ref, err)
+			}
+			// Structured secrets keep the same fields encrypted.
+			encValue, err := d.encryptFields(newKey, value, encrypt.EncryptedFields(e.value))
+			wipe(value)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt secret: %w", err)
+			}
+			if err := putSecretValue(tx, e.ref.client, e.ref.namespace, e.ref.id, encValue); err != nil {
+				return fmt.Errorf("failed to write secret %s to db: %w", e.ref, err)
+			}
+		}
+		rekeyed = len(entries)
+
+		metaB := tx.Bucket([]byte(metaBucket))
+		if metaB == nil {
+			return fmt.Errorf("%w: meta bucket is missing", ErrDatabaseCorrupt)
+		}
+		newHash := sha256.Sum256(newKey)
+		if err := metaB.Put([]byte(saltKey), newSalt); err != nil {
+			return fmt.Errorf("failed to store salt: %w", err)
+		}
+		return metaB.Put([]byte(keyHashKey), newHash[:])
+	})
+	if err != nil {
+		wipe(newKey)
+		return 0, err
+	}
+
+	d.setKey(newKey)
+	d.passCheck.reset()
+	if d.config.EncryptAuditLog {
+		gaialog.SetEncryptionKey(d.key, newSalt)
+	}
+	gaialog.Get().Info("database rekeyed", slog.Int("secrets", rekeyed))
+	return rekeyed, nil
+}
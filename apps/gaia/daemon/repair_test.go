@@ -0,0 +1,255 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	"go.etcd.io/bbolt"
+)
+
+// deleteBucket removes a top-level bucket from the database file of a locked daemon.
+func deleteBucket(t *testing.T, dbFile, name string) {
+	t.Helper()
+	db, err := bbolt.Open(dbFile, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	if err := db.Update(func(tx *bbolt.Tx) error { return tx.DeleteBucket([]byte(name)) }); err != nil {
+		t.Fatalf("failed to delete bucket %s: %v", name, err)
+	}
+}
+
+func TestVerifyAndRepair_RecreatesClientsBucket(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.RepairOnUnlock = true })
+	if _, err := d.AddSecret("app", "app", "api_key", "value", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	d.LockDB()
+	deleteBucket(t, d.config.DBFile, clientsBucket)
+
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() error = %v", err)
+	}
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(clientsBucket)) == nil {
+			return errors.New("clients bucket is still missing")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if got, err := d.GetSecret("app", "app", "api_key"); err != nil || got != "value" {
+		t.Errorf("GetSecret() after repair = %q, %v, want %q", got, err, "value")
+	}
+
+	repaired, err := d.VerifyAndRepair()
+	if err != nil {
+		t.Fatalf("VerifyAndRepair() error = %v", err)
+	}
+	if len(repaired) != 0 {
+		t.Errorf("VerifyAndRepair() on a healthy database repaired %v", repaired)
+	}
+}
+
+func TestVerifyAndRepair_OffByDefault(t *testing.T) {
+	d := newTestDaemon(t)
+	d.LockDB()
+	deleteBucket(t, d.config.DBFile, clientsBucket)
+
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() error = %v", err)
+	}
+	if _, err := d.GetClient(commonNamespace); !errors.Is(err, ErrClientNotFound) {
+		t.Errorf("GetClient() error = %v, want ErrClientNotFound", err)
+	}
+
+	repaired, err := d.VerifyAndRepair()
+	if err != nil {
+		t.Fatalf("VerifyAndRepair() error = %v", err)
+	}
+	if !slices.Equal(repaired, []string{clientsBucket}) {
+		t.Errorf("VerifyAndRepair() repaired %v, want [%s]", repaired, clientsBucket)
+	}
+}
+
+func TestUnlockDB_MissingSecretsBucket(t *testing.T) {
+	d := newTestDaemon(t, func(c *config.Config) { c.RepairOnUnlock = true })
+	d.LockDB()
+	deleteBucket(t, d.config.DBFile, secretsBucket)
+
+	if err := d.UnlockDB(testPassphrase); !errors.Is(err, ErrDatabaseCorrupt) {
+		t.Errorf("UnlockDB() error = %v, want ErrDatabaseCorrupt", err)
+	}
+}
+
+func TestVerifyIntegrity_ReportsCorruptSecret(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, id := range []string{"api_key", "db_password"} {
+		if _, err := d.AddSecret("app", "app", id, "s3cret-"+id, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	if _, err := d.AddSecret("other", "other", "token", "t0ken", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	checked, failures, err := d.VerifyIntegrity(t.Context())
+	if err != nil || checked != 3 || len(failures) != 0 {
+		t.Fatalf("VerifyIntegrity() on a healthy database = %d, %v, %v; want 3 checked and no failures", checked, failures, err)
+	}
+
+	// Flip a byte in the middle of one ciphertext, as bit rot would.
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		b := namespaceBucket(tx, "app", "app")
+		v := bytes.Clone(b.Get([]byte("db_password")))
+		v[len(v)/2] ^= 0x01
+		return b.Put([]byte("db_password"), v)
+	})
+	if err != nil {
+		t.Fatalf("failed to corrupt secret: %v", err)
+	}
+
+	checked, failures, err = d.VerifyIntegrity(t.Context())
+	if err != nil {
+		t.Fatalf("VerifyIntegrity() error = %v", err)
+	}
+	if checked != 3 {
+		t.Errorf("VerifyIntegrity() checked %d secrets, want 3", checked)
+	}
+	if len(failures) != 1 || failures[0].Client != "app" || failures[0].Namespace != "app" || failures[0].ID != "db_password" {
+		t.Fatalf("VerifyIntegrity() failures = %+v, want app/app/db_password", failures)
+	}
+	if strings.Contains(failures[0].Reason, "s3cret") {
+		t.Errorf("failure reason %q contains the secret value", failures[0].Reason)
+	}
+
+	d.LockDB()
+	if _, _, err := d.VerifyIntegrity(t.Context()); err == nil {
+		t.Error("VerifyIntegrity() on a locked daemon succeeded")
+	}
+}
+
+func TestRekeyDryRun_FlagsCorruptSecret(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, id := range []string{"api_key", "db_password"} {
+		if _, err := d.AddSecret("app", "app", id, "s3cret-"+id, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+
+	if _, err := d.RekeyDryRun(t.Context(), "wrong passphrase"); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("RekeyDryRun() with a wrong passphrase error = %v, want ErrInvalidPassphrase", err)
+	}
+	plan, err := d.RekeyDryRun(t.Context(), testPassphrase)
+	if err != nil || plan.Secrets != 2 || !plan.Ready() {
+		t.Fatalf("RekeyDryRun() on a healthy database = %+v, %v; want 2 secrets and ready", plan, err)
+	}
+
+	err = d.db.Update(func(tx *bbolt.Tx) error {
+		b := namespaceBucket(tx, "app", "app")
+		v := bytes.Clone(b.Get([]byte("api_key")))
+		v[len(v)/2] ^= 0x01
+		return b.Put([]byte("api_key"), v)
+	})
+	if err != nil {
+		t.Fatalf("failed to corrupt secret: %v", err)
+	}
+
+	plan, err = d.RekeyDryRun(t.Context(), testPassphrase)
+	if err != nil {
+		t.Fatalf("RekeyDryRun() error = %v", err)
+	}
+	if plan.Ready() || len(plan.Failures) != 1 || plan.Failures[0].ID != "api_key" {
+		t.Errorf("RekeyDryRun() = %+v, want api_key flagged and the rekey blocked", plan)
+	}
+}
+
+func TestRekey(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "api_key", "s3cret", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	const newPassphrase = "another-long-passphrase-for-tests-42"
+
+	if _, err := d.Rekey("wrong passphrase", newPassphrase, false); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("Rekey() with a wrong passphrase error = %v, want ErrInvalidPassphrase", err)
+	}
+	if _, err := d.Rekey(testPassphrase, "short", false); !errors.Is(err, ErrWeakPassphrase) {
+		t.Errorf("Rekey() to a weak passphrase error = %v, want ErrWeakPassphrase", err)
+	}
+
+	n, err := d.Rekey(testPassphrase, newPassphrase, true)
+	if err != nil || n != 1 {
+		t.Fatalf("Rekey() = %d, %v; want 1 secret", n, err)
+	}
+	if v, err := d.GetSecret("app", "app", "api_key"); err != nil || v != "s3cret" {
+		t.Errorf("GetSecret() after rekey = %q, %v", v, err)
+	}
+	if err := d.CheckKeyHash(); err != nil {
+		t.Errorf("CheckKeyHash() after rekey error = %v", err)
+	}
+
+	d.LockDB()
+	if err := d.UnlockDB(testPassphrase); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("UnlockDB() with the old passphrase error = %v, want ErrInvalidPassphrase", err)
+	}
+	if err := d.UnlockDB(newPassphrase); err != nil {
+		t.Fatalf("UnlockDB() with the new passphrase error = %v", err)
+	}
+	if v, err := d.GetSecret("app", "app", "api_key"); err != nil || v != "s3cret" {
+		t.Errorf("GetSecret() after unlocking with the new passphrase = %q, %v", v, err)
+	}
+}
+
+func TestRekey_CorruptSecretBlocksTheRekey(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, id := range []string{"api_key", "db_password", "token"} {
+		if _, err := d.AddSecret("app", "app", id, "s3cret-"+id, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		b := namespaceBucket(tx, "app", "app")
+		for _, id := range []string{"api_key", "token"} {
+			v := bytes.Clone(b.Get([]byte(id)))
+			v[len(v)/2] ^= 0x01
+			if err := b.Put([]byte(id), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to corrupt secrets: %v", err)
+	}
+
+	_, err = d.Rekey(testPassphrase, "another-long-passphrase-for-tests-42", true)
+	if !errors.Is(err, ErrRekeyBlocked) {
+		t.Fatalf("Rekey() error = %v, want ErrRekeyBlocked", err)
+	}
+	for _, id := range []string{"api_key", "token"} {
+		if !strings.Contains(err.Error(), id) {
+			t.Errorf("Rekey() error = %q, want %s listed", err, id)
+		}
+	}
+	if strings.Contains(err.Error(), "db_password") {
+		t.Errorf("Rekey() error = %q, want db_password not listed", err)
+	}
+
+	if err := d.CheckKeyHash(); err != nil {
+		t.Errorf("CheckKeyHash() after a blocked rekey error = %v", err)
+	}
+	if v, err := d.GetSecret("app", "app", "db_password"); err != nil || v != "s3cret-db_password" {
+		t.Errorf("GetSecret() after a blocked rekey = %q, %v", v, err)
+	}
+	d.LockDB()
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Errorf("UnlockDB() with the old passphrase after a blocked rekey error = %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+// namespaceRotationBucket holds the namespaces RotateSecrets may rotate, keyed by
+// namespaceKey. Namespaces without an entry are not rotated.
+const namespaceRotationBucket = "namespace_rotation"
+
+// ErrRotationDisabled is returned by RotateSecrets for a namespace that rotation
+// has not been enabled for. Nothing is written.
+var ErrRotationDisabled = errors.New("rotation is not enabled for namespace")
+
+// rotationEnabled reports whether rotation is enabled for clientName's namespace.
+func rotationEnabled(tx *bbolt.Tx, clientName, namespace string) bool {
+	b := tx.Bucket([]byte(namespaceRotationBucket))
+	return b != nil && b.Get(namespaceKey(clientName, namespace)) != nil
+}
+
+// SetNamespaceRotation enables or disables RotateSecrets for clientName's
+// namespace. Rotation is disabled until it is enabled here.
+func (d *Daemon) SetNamespaceRotation(clientName, namespace string, enabled bool) error {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return errors.New("daemon is in a locked state, cannot change namespace rotation")
+	}
+
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		key := namespaceKey(clientName, namespace)
+		if !enabled {
+			if b := tx.Bucket([]byte(namespaceRotationBucket)); b != nil {
+				return b.Delete(key)
+			}
+			return nil
+		}
+		b, err := tx.CreateBucketIfNotExists([]byte(namespaceRotationBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create or get namespace rotation bucket: %w", err)
+		}
+		return b.Put(key, []byte{1})
+	})
+
+	if err == nil {
+		gaialog.Get().Info("namespace rotation changed",
+			slog.String("client_name", clientName),
+			slog.String("namespace", namespace),
+			slog.Bool("enabled", enabled),
+		)
+	}
+	return err
+}
+
+// deleteNamespaceRotations disables rotation for every namespace of clientName.
+func deleteNamespaceRotations(tx *bbolt.Tx, clientName string) error {
+	return deleteKeysWithPrefix(tx.Bucket([]byte(namespaceRotationBucket)), keyPrefix(clientName))
+}
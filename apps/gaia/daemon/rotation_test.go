@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+)
+
+// enableRotation enables rotation for clientName's namespace.
+func enableRotation(t *testing.T, d *Daemon, clientName, namespace string) {
+	t.Helper()
+	if err := d.SetNamespaceRotation(clientName, namespace, true); err != nil {
+		t.Fatalf("SetNamespaceRotation() error = %v", err)
+	}
+}
+
+func TestRotateSecrets_RequiresOptIn(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "api_key", "old-key", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	generate := func() (string, error) { return "new-key", nil }
+
+	if _, err := d.RotateSecrets("app", "app", generate); !errors.Is(err, ErrRotationDisabled) {
+		t.Fatalf("RotateSecrets() without opt-in error = %v, want ErrRotationDisabled", err)
+	}
+	enableRotation(t, d, "app", "app")
+	if _, err := d.RotateSecrets("app", "app", generate); err != nil {
+		t.Fatalf("RotateSecrets() after opt-in error = %v", err)
+	}
+
+	if err := d.SetNamespaceRotation("app", "app", false); err != nil {
+		t.Fatalf("SetNamespaceRotation(false) error = %v", err)
+	}
+	if _, err := d.RotateSecrets("app", "app", generate); !errors.Is(err, ErrRotationDisabled) {
+		t.Errorf("RotateSecrets() after disabling error = %v, want ErrRotationDisabled", err)
+	}
+	if got, err := d.GetSecret("app", "app", "api_key"); err != nil || got != "new-key" {
+		t.Errorf("GetSecret() = %q, %v; want new-key", got, err)
+	}
+}
+
+func TestRotateSecrets_ChecksFormatAndValueRules(t *testing.T) {
+	d := newTestDaemon(t, withValueRules)
+	if _, err := d.AddSecret("app", "app", "endpoint", "https://api.example.com", AddSecretOptions{Format: "url"}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app-b", "app-b", "api_key", "old-key", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	enableRotation(t, d, "app", "app")
+	enableRotation(t, d, "app-b", "app-b")
+
+	if _, err := d.RotateSecrets("app", "app", func() (string, error) { return "not a url", nil }); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("RotateSecrets() to a value breaking the format error = %v, want ErrInvalidFormat", err)
+	}
+	if got, err := d.GetSecret("app", "app", "endpoint"); err != nil || got != "https://api.example.com" {
+		t.Errorf("GetSecret() after a failed rotation = %q, %v; want it unchanged", got, err)
+	}
+
+	if _, err := d.RotateSecrets("app-b", "app-b", func() (string, error) { return "", nil }); !errors.Is(err, ErrValueRejected) {
+		t.Errorf("RotateSecrets() to a rejected value error = %v, want ErrValueRejected", err)
+	}
+	if got, err := d.GetSecret("app-b", "app-b", "api_key"); err != nil || got != "old-key" {
+		t.Errorf("GetSecret() after a rejected rotation = %q, %v; want it unchanged", got, err)
+	}
+}
+
+func TestDeleteClient_ClearsRotation(t *testing.T) {
+	d := newTestDaemon(t)
+	if err := d.RegisterClient("app"); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	enableRotation(t, d, "app", "app")
+	if _, err := d.RevokeClient("app"); err != nil {
+		t.Fatalf("RevokeClient() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "api_key", "old-key", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.RotateSecrets("app", "app", func() (string, error) { return "new-key", nil }); !errors.Is(err, ErrRotationDisabled) {
+		t.Errorf("RotateSecrets() after the client was removed error = %v, want ErrRotationDisabled", err)
+	}
+}
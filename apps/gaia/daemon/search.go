@@ -0,0 +1,159 @@
+package daemon
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// defaultSearchPageSize is used when a search does not ask for a page size.
+	defaultSearchPageSize = 100
+	// maxSearchPageSize caps the number of matches returned per page.
+	maxSearchPageSize = 1000
+)
+
+// ErrInvalidPageToken is returned when a page token was not produced by a search.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// errPageFull ends the walk of a search once the page holds one match more than
+// it returns.
+var errPageFull = errors.New("search page is full")
+
+// SecretSearch filters a search across all clients. Empty fields match everything.
+type SecretSearch struct {
+	Client     string
+	Namespace  string
+	IDContains string
+	// Field limits the search to structured secrets whose unencrypted field at
+	// this path equals FieldValue. Encrypted fields never match.
+	Field      string
+	FieldValue string
+	// IncludeValues decrypts and returns the value of every match.
+	IncludeValues bool
+}
+
+// SecretMatch is a single search result. Value is empty unless values were requested.
+type SecretMatch struct {
+	Client    string
+	Namespace string
+	ID        string
+	Value     string
+}
+
+// SearchSecrets walks the secrets of every client, or only of search.Client when
+// set, and returns up to pageSize matches ordered by client, namespace and id.
+// The returned token is passed back as pageToken to continue after the last
+// match; it is empty once there are no more matches. Expired secrets are not
+// matched.
+func (d *Daemon) SearchSecrets(ctx context.Context, search SecretSearch, pageSize int, pageToken string) ([]SecretMatch, string, error) {
+	if pageSize <= 0 {
+		pageSize = defaultSearchPageSize
+	}
+	pageSize = min(pageSize, maxSearchPageSize)
+
+	var after *secretRef
+	if pageToken != "" {
+		key, err := base64.RawURLEncoding.DecodeString(pageToken)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+		}
+		client, namespace, id, ok := parseSecretKey(key)
+		if !ok {
+			return nil, "", ErrInvalidPageToken
+		}
+		after = &secretRef{client, namespace, id}
+	}
+
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+
+	if d.isLocked || d.db == nil {
+		return nil, "", errors.New("daemon is in a locked state, cannot search secrets")
+	}
+
+	var matches []SecretMatch
+	var last []byte
+	var next string
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		err := forEachSecretAfter(tx, search.Client, search.Namespace, after, skipExpired(tx, time.Now(), func(client, namespace, id string, v []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if !strings.Contains(id, search.IDContains) {
+				return nil
+			}
+			if search.Field != "" {
+				if v, ok := encrypt.PlainField(v, search.Field); !ok || v != search.FieldValue {
+					return nil
+				}
+			}
+			if len(matches) == pageSize {
+				// There is at least one more match, so continue after the last one returned.
+				next = base64.RawURLEncoding.EncodeToString(last)
+				return errPageFull
+			}
+
+			match := SecretMatch{Client: client, Namespace: namespace, ID: id}
+			if search.IncludeValues {
+				value, err := encrypt.DecryptValue(d.key, v)
+				if err != nil {
+					gaialog.Get().Warn("failed to decrypt secret, skipping", "namespace", namespace, "id", id, "error", err)
+					return nil
+				}
+				match.Value = string(value)
+			}
+			matches = append(matches, match)
+			last = constructDBKey(client, namespace, id)
+			return nil
+		}))
+		if errors.Is(err, errPageFull) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return matches, next, nil
+}
+
+// ListOwnSecrets returns a page of the secrets clientName stores in namespace,
+// with their values, paged like SearchSecrets so a client can read a large
+// namespace without holding all of it in memory. Secrets of the common area are
+// served by GetCommonSecrets instead.
+func (d *Daemon) ListOwnSecrets(ctx context.Context, clientName, namespace string, pageSize int, pageToken string) ([]SecretMatch, string, error) {
+	return d.listOwn(ctx, clientName, namespace, true, pageSize, pageToken)
+}
+
+// ListOwnSecretIDs returns a page of the ids of the secrets clientName stores
+// in namespace. Values are not decrypted.
+func (d *Daemon) ListOwnSecretIDs(ctx context.Context, clientName, namespace string, pageSize int, pageToken string) ([]string, string, error) {
+	matches, next, err := d.listOwn(ctx, clientName, namespace, false, pageSize, pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	return ids, next, nil
+}
+
+func (d *Daemon) listOwn(ctx context.Context, clientName, namespace string, includeValues bool, pageSize int, pageToken string) ([]SecretMatch, string, error) {
+	isCommon := d.config.EnableCommonNamespace && namespace == d.commonName()
+	if isCommon || !d.canReadNamespace(clientName, namespace) {
+		d.counters.accessDenied.Add(1)
+		return nil, "", fmt.Errorf("%w: client '%s' is not authorized for namespace '%s'", ErrPermissionDenied, clientName, namespace)
+	}
+	// The page token names a secret, but the walk never leaves the caller's
+	// namespace, so a forged token cannot reveal other secrets.
+	return d.SearchSecrets(ctx, SecretSearch{Client: clientName, Namespace: namespace, IncludeValues: includeValues}, pageSize, pageToken)
+}
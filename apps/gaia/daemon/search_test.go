@@ -0,0 +1,248 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"go.etcd.io/bbolt"
+)
+
+func TestSearchSecrets_AcrossClients(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, s := range []struct{ client, namespace, id string }{
+		{"app-a", "app-a", "api_key"},
+		{"app-a", "app-a", "db_password"},
+		{"app-b", "app-b", "stripe_api_key"},
+		{"app-b", "app-b", "smtp_user"},
+		{"app-c", "app-c", "api_key"},
+	} {
+		if _, err := d.AddSecret(s.client, s.namespace, s.id, s.client+"-value", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret(%s/%s/%s) error = %v", s.client, s.namespace, s.id, err)
+		}
+	}
+	keys := func(matches []SecretMatch) []string {
+		var out []string
+		for _, m := range matches {
+			out = append(out, m.Client+"/"+m.Namespace+"/"+m.ID)
+		}
+		return out
+	}
+
+	matches, next, err := d.SearchSecrets(context.Background(), SecretSearch{IDContains: "api_key"}, 0, "")
+	if err != nil {
+		t.Fatalf("SearchSecrets() error = %v", err)
+	}
+	want := []string{"app-a/app-a/api_key", "app-b/app-b/stripe_api_key", "app-c/app-c/api_key"}
+	if got := keys(matches); !slices.Equal(got, want) || next != "" {
+		t.Errorf("SearchSecrets(id api_key) = %v, next %q; want %v on one page", got, next, want)
+	}
+	for _, m := range matches {
+		if m.Value != "" {
+			t.Errorf("match %s/%s returned value %q without IncludeValues", m.Client, m.ID, m.Value)
+		}
+	}
+
+	matches, _, err = d.SearchSecrets(context.Background(), SecretSearch{Client: "app-b", IncludeValues: true}, 0, "")
+	if err != nil {
+		t.Fatalf("SearchSecrets() error = %v", err)
+	}
+	if got := keys(matches); !slices.Equal(got, []string{"app-b/app-b/smtp_user", "app-b/app-b/stripe_api_key"}) {
+		t.Errorf("SearchSecrets(client app-b) = %v", got)
+	}
+	if matches[0].Value != "app-b-value" {
+		t.Errorf("Value = %q, want %q with IncludeValues", matches[0].Value, "app-b-value")
+	}
+
+	// Paging returns every match exactly once.
+	var paged []string
+	token := ""
+	for range 10 {
+		matches, token, err = d.SearchSecrets(context.Background(), SecretSearch{IDContains: "api_key"}, 2, token)
+		if err != nil {
+			t.Fatalf("SearchSecrets() page error = %v", err)
+		}
+		paged = append(paged, keys(matches)...)
+		if token == "" {
+			break
+		}
+	}
+	if !slices.Equal(paged, want) {
+		t.Errorf("paged SearchSecrets() = %v, want %v", paged, want)
+	}
+
+	if _, _, err := d.SearchSecrets(context.Background(), SecretSearch{}, 0, "not base64!"); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("SearchSecrets() with a bad token error = %v, want ErrInvalidPageToken", err)
+	}
+
+	d.LockDB()
+	if _, _, err := d.SearchSecrets(context.Background(), SecretSearch{}, 0, ""); err == nil {
+		t.Error("SearchSecrets() on a locked daemon succeeded")
+	}
+}
+
+func TestListOwnSecrets_Paged(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		if _, err := d.AddSecret("billing", "billing", id, "v-"+id, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	if _, err := d.AddSecret("billing2", "billing2", "other", "x", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	var ids []string
+	token := ""
+	for range 10 {
+		matches, next, err := d.ListOwnSecrets(context.Background(), "billing", "billing", 2, token)
+		if err != nil {
+			t.Fatalf("ListOwnSecrets() error = %v", err)
+		}
+		for _, m := range matches {
+			if m.Value != "v-"+m.ID {
+				t.Errorf("value of %s = %q, want %q", m.ID, m.Value, "v-"+m.ID)
+			}
+			ids = append(ids, m.ID)
+		}
+		if token = next; token == "" {
+			break
+		}
+	}
+	if !slices.Equal(ids, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("paged ListOwnSecrets() = %v", ids)
+	}
+
+	if _, _, err := d.ListOwnSecrets(context.Background(), "billing", "billing2", 0, ""); err == nil {
+		t.Error("ListOwnSecrets() of another client's namespace succeeded")
+	}
+	// A token pointing at another client's key does not leave the caller's namespace.
+	forged := base64.RawURLEncoding.EncodeToString(constructDBKey("billing2", "billing2", ""))
+	if matches, _, err := d.ListOwnSecrets(context.Background(), "billing", "billing", 0, forged); err != nil || len(matches) != 0 {
+		t.Errorf("ListOwnSecrets() with a forged token = %v, %v; want no matches", matches, err)
+	}
+}
+
+func TestStructuredSecret_EncryptsOneField(t *testing.T) {
+	d := newTestDaemon(t)
+	config := `{"env":"prod","host":"db.internal","password":"hunter2"}`
+	if _, err := d.AddSecret("app", "app", "database", config, AddSecretOptions{EncryptFields: []string{"password"}}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "cache", `{"env":"staging","password":"x"}`, AddSecretOptions{EncryptFields: []string{"password"}}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "plain", `{"env":"prod"}`, AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	if got, err := d.GetSecret("app", "app", "database"); err != nil || got != config {
+		t.Errorf("GetSecret() = %q, %v; want %q", got, err, config)
+	}
+	d.db.View(func(tx *bbolt.Tx) error {
+		if v := getSecretValue(tx, "app", "app", "database"); bytes.Contains(v, []byte("hunter2")) || !bytes.Contains(v, []byte("db.internal")) {
+			t.Errorf("stored value = %s, want only the password encrypted", v)
+		}
+		return nil
+	})
+
+	// Only the structured secret with the field matches; the password does not.
+	search := func(field, value string) []string {
+		t.Helper()
+		matches, _, err := d.SearchSecrets(context.Background(), SecretSearch{Field: field, FieldValue: value}, 0, "")
+		if err != nil {
+			t.Fatalf("SearchSecrets(%s=%s) error = %v", field, value, err)
+		}
+		var ids []string
+		for _, m := range matches {
+			ids = append(ids, m.ID)
+		}
+		return ids
+	}
+	if ids := search("env", "prod"); !slices.Equal(ids, []string{"database"}) {
+		t.Errorf("SearchSecrets(env=prod) = %q, want [database]", ids)
+	}
+	if ids := search("password", "hunter2"); len(ids) != 0 {
+		t.Errorf("SearchSecrets() matched an encrypted field: %q", ids)
+	}
+
+	// Later writes keep the field encrypted, and a value without it is rejected.
+	updated := `{"env":"prod","host":"db2.internal","password":"hunter3"}`
+	if _, err := d.AddSecret("app", "app", "database", updated, AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() of a structured secret error = %v", err)
+	}
+	if ids := search("host", "db2.internal"); !slices.Equal(ids, []string{"database"}) {
+		t.Errorf("SearchSecrets(host) after update = %q, want [database]", ids)
+	}
+	if _, err := d.AddSecret("app", "app", "database", "not json", AddSecretOptions{}); !errors.Is(err, encrypt.ErrNotObject) {
+		t.Errorf("AddSecret() of a non-object value error = %v, want ErrNotObject", err)
+	}
+
+	// Rekeying keeps the field encrypted.
+	if _, err := d.Rekey(testPassphrase, "another-long-passphrase-for-tests-42", false); err != nil {
+		t.Fatalf("Rekey() error = %v", err)
+	}
+	if got, err := d.GetSecret("app", "app", "database"); err != nil || got != updated {
+		t.Errorf("GetSecret() after rekey = %q, %v; want %q", got, err, updated)
+	}
+	if ids := search("host", "db2.internal"); !slices.Equal(ids, []string{"database"}) {
+		t.Errorf("SearchSecrets(host) after rekey = %q, want [database]", ids)
+	}
+}
+
+func TestImportSecrets_OverwriteKeepsEncryptedFields(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "database", `{"env":"prod","password":"hunter2"}`, AddSecretOptions{EncryptFields: []string{"password"}}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	updated := `{"env":"staging","password":"hunter3"}`
+	items := []*pb.ImportSecretItem{{ClientName: "app", Namespace: "app", Id: "database", Value: updated}}
+	if _, err := d.ImportSecrets(items, ImportOverwrite, ""); err != nil {
+		t.Fatalf("ImportSecrets(overwrite) error = %v", err)
+	}
+	if got, err := d.GetSecret("app", "app", "database"); err != nil || got != updated {
+		t.Errorf("GetSecret() after import = %q, %v; want %q", got, err, updated)
+	}
+	d.db.View(func(tx *bbolt.Tx) error {
+		v := getSecretValue(tx, "app", "app", "database")
+		if bytes.Contains(v, []byte("hunter3")) || !bytes.Contains(v, []byte("staging")) {
+			t.Errorf("stored value = %s, want only the password encrypted", v)
+		}
+		return nil
+	})
+
+	items[0].Value = "not json"
+	if _, err := d.ImportSecrets(items, ImportOverwrite, ""); !errors.Is(err, encrypt.ErrNotObject) {
+		t.Errorf("ImportSecrets() of a non-object value error = %v, want ErrNotObject", err)
+	}
+}
+
+func TestRotateSecrets_RejectsStructuredSecrets(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "api_key", "old-key", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	config := `{"env":"prod","password":"hunter2"}`
+	if _, err := d.AddSecret("app", "app", "database", config, AddSecretOptions{EncryptFields: []string{"password"}}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	enableRotation(t, d, "app", "app")
+
+	generate := func() (string, error) { return "generated", nil }
+	if _, err := d.RotateSecrets("app", "app", generate); !errors.Is(err, ErrStructuredSecret) {
+		t.Fatalf("RotateSecrets() error = %v, want ErrStructuredSecret", err)
+	}
+	if got, err := d.GetSecret("app", "app", "api_key"); err != nil || got != "old-key" {
+		t.Errorf("GetSecret() after a rejected rotation = %q, %v; want old-key", got, err)
+	}
+	if got, err := d.GetSecret("app", "app", "database"); err != nil || got != config {
+		t.Errorf("GetSecret() after a rejected rotation = %q, %v; want %q", got, err, config)
+	}
+}
@@ -0,0 +1,251 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+// Since schema version 4 the secrets bucket holds a bucket per client, which
+// holds a bucket per namespace mapping secret ids to encrypted values. Names
+// are used as bucket keys as they are, so they need no escaping, and a
+// namespace is found, listed or dropped without walking the secrets of any
+// other namespace. A namespace bucket is removed with its last secret and a
+// client bucket with its last namespace, so a namespace exists exactly as long
+// as it holds secrets.
+
+// ErrEmptyName is returned for writes of a secret with an empty client name,
+// namespace or id, which the bucket layout cannot store.
+var ErrEmptyName = errors.New("client name, namespace and secret id must not be empty")
+
+// clientSecretsBucket returns the bucket of the namespaces of client, or nil if
+// it holds no secrets.
+func clientSecretsBucket(tx *bbolt.Tx, client string) *bbolt.Bucket {
+	secretsB := tx.Bucket([]byte(secretsBucket))
+	if secretsB == nil || client == "" {
+		return nil
+	}
+	return secretsB.Bucket([]byte(client))
+}
+
+// namespaceBucket returns the bucket of the secrets of namespace of client, or
+// nil if the namespace holds no secrets.
+func namespaceBucket(tx *bbolt.Tx, client, namespace string) *bbolt.Bucket {
+	clientB := clientSecretsBucket(tx, client)
+	if clientB == nil || namespace == "" {
+		return nil
+	}
+	return clientB.Bucket([]byte(namespace))
+}
+
+// getSecretValue returns the encrypted value of a secret, or nil if it does not
+// exist. The value is only valid for the life of the transaction.
+func getSecretValue(tx *bbolt.Tx, client, namespace, id string) []byte {
+	b := namespaceBucket(tx, client, namespace)
+	if b == nil || id == "" {
+		return nil
+	}
+	return b.Get([]byte(id))
+}
+
+// putSecretValue stores the encrypted value of a secret, creating its client and
+// namespace buckets as needed.
+func putSecretValue(tx *bbolt.Tx, client, namespace, id string, value []byte) error {
+	if client == "" || namespace == "" || id == "" {
+		return ErrEmptyName
+	}
+	secretsB, err := tx.CreateBucketIfNotExists([]byte(secretsBucket))
+	if err != nil {
+		return fmt.Errorf("failed to create or get bucket: %w", err)
+	}
+	clientB, err := secretsB.CreateBucketIfNotExists([]byte(client))
+	if err != nil {
+		return fmt.Errorf("failed to create bucket of client '%s': %w", client, err)
+	}
+	nsB, err := clientB.CreateBucketIfNotExists([]byte(namespace))
+	if err != nil {
+		return fmt.Errorf("failed to create bucket of namespace '%s': %w", namespace, err)
+	}
+	return nsB.Put([]byte(id), value)
+}
+
+// deleteSecretValue removes a secret and drops its namespace and client buckets
+// when it was their last secret. Deleting a missing secret is not an error.
+func deleteSecretValue(tx *bbolt.Tx, client, namespace, id string) error {
+	b := namespaceBucket(tx, client, namespace)
+	if b == nil || id == "" {
+		return nil
+	}
+	if err := b.Delete([]byte(id)); err != nil {
+		return err
+	}
+	if k, _ := b.Cursor().First(); k != nil {
+		return nil
+	}
+	return deleteNamespaceBucket(tx, client, namespace)
+}
+
+// deleteNamespaceBucket drops the bucket of namespace of client with all of its
+// secrets, and the client bucket when it was its last namespace. Format and
+// access records are left alone.
+func deleteNamespaceBucket(tx *bbolt.Tx, client, namespace string) error {
+	clientB := clientSecretsBucket(tx, client)
+	if clientB == nil || namespace == "" || clientB.Bucket([]byte(namespace)) == nil {
+		return nil
+	}
+	if err := clientB.DeleteBucket([]byte(namespace)); err != nil {
+		return err
+	}
+	if k, _ := clientB.Cursor().First(); k != nil {
+		return nil
+	}
+	return tx.Bucket([]byte(secretsBucket)).DeleteBucket([]byte(client))
+}
+
+// secretNamespaces returns the namespaces client holds secrets in, in key order.
+func secretNamespaces(tx *bbolt.Tx, client string) []string {
+	var namespaces []string
+	if clientB := clientSecretsBucket(tx, client); clientB != nil {
+		_ = walkBuckets(clientB, "", "", func(ns string, _ *bbolt.Bucket) error {
+			namespaces = append(namespaces, ns)
+			return nil
+		})
+	}
+	return namespaces
+}
+
+// countSecrets returns the number of secrets in the namespace bucket b.
+func countSecrets(b *bbolt.Bucket) int {
+	if b == nil {
+		return 0
+	}
+	n := 0
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if v != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// secretRef names a stored secret.
+type secretRef struct {
+	client, namespace, id string
+}
+
+func (r secretRef) String() string {
+	return r.client + "/" + r.namespace + "/" + r.id
+}
+
+// forEachSecret calls fn with the encrypted value of every secret, ordered by
+// client, namespace and id. A non-empty client limits the walk to its secrets
+// and a non-empty namespace further to that namespace. The walk stops at the
+// first error of fn, which it returns. fn must not write to the secrets bucket;
+// callers that rewrite secrets collect them first.
+func forEachSecret(tx *bbolt.Tx, client, namespace string, fn func(client, namespace, id string, value []byte) error) error {
+	return forEachSecretAfter(tx, client, namespace, nil, fn)
+}
+
+// forEachSecretAfter walks the secrets like forEachSecret, skipping every secret
+// up to and including after when it is not nil.
+func forEachSecretAfter(tx *bbolt.Tx, client, namespace string, after *secretRef, fn func(client, namespace, id string, value []byte) error) error {
+	secretsB := tx.Bucket([]byte(secretsBucket))
+	if secretsB == nil {
+		return nil
+	}
+	var from secretRef
+	if after != nil {
+		from = *after
+	}
+	return walkBuckets(secretsB, client, from.client, func(c string, clientB *bbolt.Bucket) error {
+		nsFrom := ""
+		if after != nil && c == after.client {
+			nsFrom = after.namespace
+		}
+		return walkBuckets(clientB, namespace, nsFrom, func(ns string, nsB *bbolt.Bucket) error {
+			cur := nsB.Cursor()
+			k, v := cur.First()
+			if after != nil && c == after.client && ns == after.namespace {
+				if k, v = cur.Seek([]byte(after.id)); k != nil && string(k) == after.id {
+					k, v = cur.Next()
+				}
+			}
+			for ; k != nil; k, v = cur.Next() {
+				if v == nil {
+					continue
+				}
+				if err := fn(c, ns, string(k), v); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// walkBuckets calls fn with the nested buckets of b whose names are not before
+// from, in key order. A non-empty only limits the walk to the bucket of that
+// name. Plain values in b are skipped.
+func walkBuckets(b *bbolt.Bucket, only, from string, fn func(name string, b *bbolt.Bucket) error) error {
+	if only != "" {
+		if only < from {
+			return nil
+		}
+		if nested := b.Bucket([]byte(only)); nested != nil {
+			return fn(only, nested)
+		}
+		return nil
+	}
+	c := b.Cursor()
+	for k, v := c.Seek([]byte(from)); k != nil; k, v = c.Next() {
+		if v != nil {
+			continue
+		}
+		if err := fn(string(k), b.Bucket(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nestSecrets moves the secrets of a schema version 3 database, which kept them
+// under composite keys in the secrets bucket itself, into client and namespace
+// buckets. Keys that do not decode to a secret, or to one with an empty name,
+// are left where they are and logged.
+func nestSecrets(tx *bbolt.Tx) error {
+	secretsB := tx.Bucket([]byte(secretsBucket))
+	type entry struct {
+		key, value []byte
+	}
+	var entries []entry
+	c := secretsB.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if v == nil {
+			continue
+		}
+		client, namespace, id, ok := parseSecretKey(k)
+		if !ok || client == "" || namespace == "" || id == "" {
+			gaialog.Get().Warn("secret key cannot be migrated, leaving it in place", slog.String("key", fmt.Sprintf("%q", k)))
+			continue
+		}
+		entries = append(entries, entry{bytes.Clone(k), bytes.Clone(v)})
+	}
+	// Delete every flat key first, so none of them is in the way of a bucket.
+	for _, e := range entries {
+		if err := secretsB.Delete(e.key); err != nil {
+			return err
+		}
+	}
+	for _, e := range entries {
+		client, namespace, id, _ := parseSecretKey(e.key)
+		if err := putSecretValue(tx, client, namespace, id, e.value); err != nil {
+			return fmt.Errorf("failed to move secret '%s/%s/%s': %w", client, namespace, id, err)
+		}
+	}
+	return nil
+}
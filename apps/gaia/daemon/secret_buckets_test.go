@@ -0,0 +1,229 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.etcd.io/bbolt"
+)
+
+func TestUnlockDB_NestsSchemaVersion3Secrets(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, s := range []struct{ client, namespace, id, value string }{
+		{"app", "app", "api_key", "s3cret"},
+		{"app", "app", "db_password", "hunter2"},
+		{"app", "ns\x00x", "id\x00y", "nulls"},
+		{"other", "other", "token", "t0ken"},
+	} {
+		if _, err := d.AddSecret(s.client, s.namespace, s.id, s.value, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	d.LockDB()
+
+	// Rewrite the secrets as schema version 3 kept them: composite keys in the
+	// secrets bucket itself.
+	db, err := bbolt.Open(d.config.DBFile, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		if err := flattenSecrets(tx); err != nil {
+			return err
+		}
+		return tx.Bucket([]byte(metaBucket)).Put([]byte(schemaVersionKey), []byte("3"))
+	})
+	db.Close()
+	if err != nil {
+		t.Fatalf("failed to downgrade database: %v", err)
+	}
+
+	if err := d.UnlockDB(testPassphrase); err != nil {
+		t.Fatalf("UnlockDB() error = %v", err)
+	}
+	err = d.db.View(func(tx *bbolt.Tx) error {
+		if v := tx.Bucket([]byte(metaBucket)).Get([]byte(schemaVersionKey)); string(v) != "4" {
+			t.Errorf("schema version after migration = %q, want 4", v)
+		}
+		// Only client buckets are left at the top of the secrets bucket.
+		c := tx.Bucket([]byte(secretsBucket)).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if v != nil {
+				t.Errorf("secrets bucket still holds the flat key %q", k)
+			}
+		}
+		if v := getSecretValue(tx, "app", "ns\x00x", "id\x00y"); v == nil {
+			t.Error("secret with null bytes in its names was not moved to its namespace bucket")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Get, list, add and delete work on the migrated layout.
+	if got, err := d.GetSecret("app", "app", "api_key"); err != nil || got != "s3cret" {
+		t.Errorf("GetSecret() after migration = %q, %v; want %q", got, err, "s3cret")
+	}
+	if namespaces, err := d.ListNamespaces("app"); err != nil || !slices.Equal(namespaces, []string{"app", "ns\x00x"}) {
+		t.Errorf("ListNamespaces(app) after migration = %q, %v", namespaces, err)
+	}
+	secrets, err := d.ListSecrets(t.Context(), "app")
+	if err != nil || len(secrets["app"]) != 2 || secrets["ns\x00x"]["id\x00y"] != "nulls" {
+		t.Errorf("ListSecrets(app) after migration = %q, %v", secrets, err)
+	}
+	if _, err := d.AddSecret("app", "app", "new_key", "n3w", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() after migration error = %v", err)
+	}
+	if got, err := d.GetSecret("app", "app", "new_key"); err != nil || got != "n3w" {
+		t.Errorf("GetSecret() of a secret added after migration = %q, %v", got, err)
+	}
+	if err := d.DeleteSecret("app", "ns\x00x", "id\x00y"); err != nil {
+		t.Fatalf("DeleteSecret() after migration error = %v", err)
+	}
+	if namespaces, err := d.ListNamespaces("app"); err != nil || !slices.Equal(namespaces, []string{"app"}) {
+		t.Errorf("ListNamespaces(app) after deleting the last secret of a namespace = %q, %v", namespaces, err)
+	}
+	if exported, err := d.ExportSecrets(t.Context(), "other"); err != nil || exported["other"]["other"]["token"] != "t0ken" {
+		t.Errorf("ExportSecrets(other) after migration = %v, %v", exported, err)
+	}
+}
+
+func TestDeleteSecret_DropsEmptyBuckets(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, id := range []string{"a", "b"} {
+		if _, err := d.AddSecret("app", "app", id, "v-"+id, AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+
+	buckets := func() (client, namespace bool) {
+		d.db.View(func(tx *bbolt.Tx) error {
+			client = clientSecretsBucket(tx, "app") != nil
+			namespace = namespaceBucket(tx, "app", "app") != nil
+			return nil
+		})
+		return client, namespace
+	}
+	if err := d.DeleteSecret("app", "app", "a"); err != nil {
+		t.Fatalf("DeleteSecret() error = %v", err)
+	}
+	if client, namespace := buckets(); !client || !namespace {
+		t.Errorf("buckets after deleting one of two secrets: client %v, namespace %v; want both", client, namespace)
+	}
+	if err := d.DeleteSecret("app", "app", "b"); err != nil {
+		t.Fatalf("DeleteSecret() error = %v", err)
+	}
+	if client, namespace := buckets(); client || namespace {
+		t.Errorf("buckets after deleting the last secret: client %v, namespace %v; want neither", client, namespace)
+	}
+	// Deleting again is not an error.
+	if err := d.DeleteSecret("app", "app", "b"); err != nil {
+		t.Errorf("DeleteSecret() of a missing secret error = %v", err)
+	}
+}
+
+func TestAddSecret_EmptyName(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, s := range []struct{ client, namespace, id string }{
+		{"app", "app", ""},
+		{"app", "", "id"},
+	} {
+		if _, err := d.AddSecret(s.client, s.namespace, s.id, "v", AddSecretOptions{}); !errors.Is(err, ErrEmptyName) {
+			t.Errorf("AddSecret(%q, %q, %q) error = %v, want ErrEmptyName", s.client, s.namespace, s.id, err)
+		}
+	}
+}
+
+// BenchmarkDeleteNamespace compares dropping one namespace of a client that
+// holds several, with the composite keys of schema version 3 and with the
+// namespace buckets of schema version 4.
+func BenchmarkDeleteNamespace(b *testing.B) {
+	const namespaces, secrets = 10, 1000
+	db, err := bbolt.Open(filepath.Join(b.TempDir(), "bench.db"), 0600, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+	value := bytes.Repeat([]byte{0xab}, 64)
+
+	for _, bc := range []struct {
+		name   string
+		fill   func(tx *bbolt.Tx, namespace string) error
+		delete func(tx *bbolt.Tx, namespace string) error
+	}{
+		{
+			name: "flat",
+			fill: func(tx *bbolt.Tx, namespace string) error {
+				b, err := tx.CreateBucketIfNotExists([]byte("flat"))
+				if err != nil {
+					return err
+				}
+				for i := range secrets {
+					if err := b.Put(constructDBKey("app", namespace, fmt.Sprintf("key_%d", i)), value); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			delete: func(tx *bbolt.Tx, namespace string) error {
+				b := tx.Bucket([]byte("flat"))
+				var keys [][]byte
+				prefix := keyPrefix("app", namespace)
+				c := b.Cursor()
+				for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+					keys = append(keys, bytes.Clone(k))
+				}
+				for _, k := range keys {
+					if err := b.Delete(k); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "nested",
+			fill: func(tx *bbolt.Tx, namespace string) error {
+				for i := range secrets {
+					if err := putSecretValue(tx, "app", namespace, fmt.Sprintf("key_%d", i), value); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			delete: func(tx *bbolt.Tx, namespace string) error {
+				return deleteNamespaceBucket(tx, "app", namespace)
+			},
+		},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			err := db.Update(func(tx *bbolt.Tx) error {
+				for i := range namespaces {
+					if err := bc.fill(tx, fmt.Sprintf("ns-%02d", i)); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			const target = "ns-05"
+			for b.Loop() {
+				if err := db.Update(func(tx *bbolt.Tx) error { return bc.delete(tx, target) }); err != nil {
+					b.Fatal(err)
+				}
+				b.StopTimer()
+				if err := db.Update(func(tx *bbolt.Tx) error { return bc.fill(tx, target) }); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+			}
+		})
+	}
+}
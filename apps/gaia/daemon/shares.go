@@ -0,0 +1,181 @@
+package daemon
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+// unlockThresholdKey holds the number of key shares needed to unlock a
+// database initialized with InitializeDBWithShares.
+const unlockThresholdKey = "unlock_threshold"
+
+// ErrInvalidShare is returned by UnlockWithShare for a malformed or repeated
+// share, and once enough shares are collected but they do not recover the key.
+var ErrInvalidShare = errors.New("invalid key share")
+
+// InitializeDBWithShares creates the encrypted database under a random master
+// key and splits the key into n hex-encoded shares, threshold of which unlock
+// the daemon. No passphrase is set, so no single share holder can unlock alone.
+func (d *Daemon) InitializeDBWithShares(n, threshold int) ([]string, error) {
+	if _, err := os.Stat(d.config.DBFile); err == nil {
+		return nil, errors.New("database already exists")
+	}
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key := make([]byte, encrypt.KeyLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	defer wipe(key)
+
+	shares, err := encrypt.SplitSecret(key, n, threshold)
+	if err != nil {
+		return nil, err
+	}
+	meta := map[string][]byte{unlockThresholdKey: []byte(strconv.Itoa(threshold))}
+	if err := d.createDB(key, salt, meta); err != nil {
+		return nil, err
+	}
+	res := make([]string, len(shares))
+	for i, share := range shares {
+		res[i] = hex.EncodeToString(share)
+		wipe(share)
+	}
+	return res, nil
+}
+
+// UnlockWithShare submits one key share. Shares are held in memory until
+// threshold of them are collected, then the master key is recovered from them
+// and the daemon is unlocked. It returns the number of shares collected and
+// required; the daemon is unlocked once collected reaches required.
+func (d *Daemon) UnlockWithShare(share string) (collected, required int, err error) {
+	collected, required, err = d.unlockWithShare(share)
+	switch {
+	case errors.Is(err, ErrAlreadyUnlocked):
+	case err != nil:
+		d.counters.failedUnlocks.Add(1)
+	case collected >= required:
+		d.counters.unlocks.Add(1)
+	}
+	return collected, required, err
+}
+
+func (d *Daemon) unlockWithShare(share string) (int, int, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if !d.isLocked && d.db != nil {
+		return 0, 0, ErrAlreadyUnlocked
+	}
+	raw, err := hex.DecodeString(strings.TrimSpace(share))
+	if err != nil || len(raw) != encrypt.KeyLen+1 || raw[0] == 0 {
+		return 0, 0, fmt.Errorf("%w: malformed share", ErrInvalidShare)
+	}
+	if d.db != nil {
+		d.db.Close()
+	}
+	if err := d.openDB(); err != nil {
+		return 0, 0, err
+	}
+
+	var salt, storedHash []byte
+	var threshold int
+	err = d.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		if salt, storedHash, err = readKeyMaterial(tx); err != nil {
+			return err
+		}
+		threshold, err = readUnlockThreshold(tx)
+		return err
+	})
+	if err == nil && threshold == 0 {
+		err = errors.New("database is unlocked with a passphrase, not key shares")
+	}
+	if err != nil {
+		d.closeSharesDB()
+		return 0, 0, err
+	}
+
+	for _, s := range d.shares {
+		if s[0] == raw[0] {
+			d.closeSharesDB()
+			return len(d.shares), threshold, fmt.Errorf("%w: share %d was already submitted", ErrInvalidShare, raw[0])
+		}
+	}
+	d.shares = append(d.shares, raw)
+	gaialog.Get().Info("key share submitted",
+		slog.Int("shares_collected", len(d.shares)),
+		slog.Int("shares_required", threshold),
+	)
+	if len(d.shares) < threshold {
+		d.closeSharesDB()
+		return len(d.shares), threshold, nil
+	}
+
+	collected := len(d.shares)
+	key, err := encrypt.CombineShares(d.shares)
+	d.clearSharesLocked()
+	if err != nil {
+		d.closeSharesDB()
+		return 0, threshold, fmt.Errorf("%w: %v", ErrInvalidShare, err)
+	}
+	keyHash := sha256.Sum256(key)
+	if subtle.ConstantTimeCompare(keyHash[:], storedHash) != 1 {
+		wipe(key)
+		d.closeSharesDB()
+		return 0, threshold, fmt.Errorf("%w: the shares do not recover the master key", ErrInvalidShare)
+	}
+	if err := d.completeUnlockLocked(key, salt); err != nil {
+		return 0, threshold, err
+	}
+	return collected, threshold, nil
+}
+
+// closeSharesDB closes the database opened to check a share while the daemon
+// stays locked.
+func (d *Daemon) closeSharesDB() {
+	d.db.Close()
+	d.db = nil
+}
+
+// clearSharesLocked wipes the submitted key shares. The caller must hold dbLock
+// for writing.
+func (d *Daemon) clearSharesLocked() {
+	for _, s := range d.shares {
+		wipe(s)
+	}
+	d.shares = nil
+}
+
+// readUnlockThreshold returns the number of key shares that unlock the
+// database, or 0 for a database unlocked with a passphrase.
+func readUnlockThreshold(tx *bbolt.Tx) (int, error) {
+	b := tx.Bucket([]byte(metaBucket))
+	if b == nil {
+		return 0, nil
+	}
+	v := b.Get([]byte(unlockThresholdKey))
+	if v == nil {
+		return 0, nil
+	}
+	threshold, err := strconv.Atoi(string(bytes.TrimSpace(v)))
+	if err != nil || threshold < 2 {
+		return 0, fmt.Errorf("%w: invalid unlock threshold", ErrDatabaseCorrupt)
+	}
+	return threshold, nil
+}
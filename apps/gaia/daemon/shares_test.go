@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+)
+
+func newSharedDaemon(t *testing.T) (*Daemon, []string) {
+	t.Helper()
+	d := NewDaemon(newTestConfig(t))
+	shares, err := d.InitializeDBWithShares(5, 3)
+	if err != nil {
+		t.Fatalf("InitializeDBWithShares() error = %v", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("InitializeDBWithShares() returned %d shares, want 5", len(shares))
+	}
+	t.Cleanup(d.LockDB)
+	return d, shares
+}
+
+func TestUnlockWithShare_Threshold(t *testing.T) {
+	d, shares := newSharedDaemon(t)
+
+	for i, share := range []string{shares[4], shares[1], shares[2]} {
+		collected, required, err := d.UnlockWithShare(share)
+		if err != nil {
+			t.Fatalf("UnlockWithShare() #%d error = %v", i+1, err)
+		}
+		if collected != i+1 || required != 3 {
+			t.Errorf("UnlockWithShare() #%d = %d of %d, want %d of 3", i+1, collected, required, i+1)
+		}
+		if locked, _, _ := d.LockState(); locked != (i < 2) {
+			t.Errorf("locked after %d shares = %v, want %v", i+1, locked, i < 2)
+		}
+	}
+
+	if _, err := d.AddSecret("app", "app", "token", "v", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() after unlocking with shares error = %v", err)
+	}
+	if _, _, err := d.UnlockWithShare(shares[0]); !errors.Is(err, ErrAlreadyUnlocked) {
+		t.Errorf("UnlockWithShare() on an unlocked daemon error = %v, want ErrAlreadyUnlocked", err)
+	}
+}
+
+func TestUnlockWithShare_TooFewShares(t *testing.T) {
+	d, shares := newSharedDaemon(t)
+
+	for _, share := range shares[:2] {
+		if _, _, err := d.UnlockWithShare(share); err != nil {
+			t.Fatalf("UnlockWithShare() error = %v", err)
+		}
+	}
+	if _, _, err := d.UnlockWithShare(shares[1]); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("UnlockWithShare() with a repeated share error = %v, want ErrInvalidShare", err)
+	}
+	if locked, _, _ := d.LockState(); !locked {
+		t.Fatal("daemon unlocked with 2 of 3 shares")
+	}
+	if err := d.UnlockDB(testPassphrase); !errors.Is(err, ErrKeySharesRequired) {
+		t.Errorf("UnlockDB() with a passphrase error = %v, want ErrKeySharesRequired", err)
+	}
+
+	// Locking discards the submitted shares.
+	d.LockDB()
+	if collected, _, err := d.UnlockWithShare(shares[3]); err != nil || collected != 1 {
+		t.Errorf("UnlockWithShare() after a lock = %d, %v; want 1 share collected", collected, err)
+	}
+}
+
+func TestUnlockWithShare_Invalid(t *testing.T) {
+	d, shares := newSharedDaemon(t)
+	if _, _, err := d.UnlockWithShare("not hex"); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("UnlockWithShare() with a malformed share error = %v, want ErrInvalidShare", err)
+	}
+
+	// A share of another database has the same shape but recovers another key.
+	_, other := newSharedDaemon(t)
+	for _, share := range []string{shares[0], shares[1]} {
+		if _, _, err := d.UnlockWithShare(share); err != nil {
+			t.Fatalf("UnlockWithShare() error = %v", err)
+		}
+	}
+	if _, _, err := d.UnlockWithShare(other[2]); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("UnlockWithShare() with a foreign share error = %v, want ErrInvalidShare", err)
+	}
+	if locked, _, _ := d.LockState(); !locked {
+		t.Error("daemon unlocked with a foreign share")
+	}
+}
@@ -2,12 +2,10 @@ package daemon
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
-	"os"
 	"time"
 
+	"github.com/stain-win/gaia/apps/gaia/certs"
 	"github.com/stain-win/gaia/apps/gaia/config" // Import the config package
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
 	"google.golang.org/grpc"
@@ -44,31 +42,16 @@ func getDaemonStatus(cfg *config.Config) (string, error) {
 // This method now correctly takes a context and config parameter.
 func getClientConn(_ context.Context, cfg *config.Config) (*grpc.ClientConn, error) {
 	daemonAddress := fmt.Sprintf("localhost:%s", cfg.GRPCPort)
-	caCertFile := cfg.CACertFile
-	clientCertFile := cfg.GaiaClientCertFile
-	clientKeyFile := cfg.GaianClientKeyFile
-
-	clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+	paths, err := certs.AdminClientPaths(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("could not load client key pair: %w", err)
+		return nil, err
 	}
-
-	caCert, err := os.ReadFile(caCertFile)
+	tlsConfig, err := certs.ClientTLSConfig(paths, certs.ServerName(cfg))
 	if err != nil {
-		return nil, fmt.Errorf("could not read CA certificate: %w", err)
+		return nil, err
 	}
-	certPool := x509.NewCertPool()
-	if !certPool.AppendCertsFromPEM(caCert) {
-		return nil, fmt.Errorf("could not append CA certificate to pool")
-	}
-
-	creds := credentials.NewTLS(&tls.Config{
-		ServerName:   "localhost",
-		Certificates: []tls.Certificate{clientCert},
-		RootCAs:      certPool,
-	})
 
-	conn, err := grpc.NewClient(daemonAddress, grpc.WithTransportCredentials(creds))
+	conn, err := grpc.NewClient(daemonAddress, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -0,0 +1,177 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+// secretTagsBucket holds the tags of a secret as a JSON object, keyed like the
+// secret formats bucket.
+const secretTagsBucket = "secret_tags"
+
+// ErrInvalidTag is returned for tags with an empty key. Nothing is written.
+var ErrInvalidTag = errors.New("invalid secret tag")
+
+// validateTags checks that every tag has a key.
+func validateTags(tags map[string]string) error {
+	for k := range tags {
+		if k == "" {
+			return fmt.Errorf("%w: tag key must not be empty", ErrInvalidTag)
+		}
+	}
+	return nil
+}
+
+// putSecretTags replaces the tags of the secret stored under key. Empty tags
+// remove them.
+func putSecretTags(tx *bbolt.Tx, key []byte, tags map[string]string) error {
+	if len(tags) == 0 {
+		return deleteSecretTags(tx, key)
+	}
+	b, err := tx.CreateBucketIfNotExists([]byte(secretTagsBucket))
+	if err != nil {
+		return fmt.Errorf("failed to create or get secret tags bucket: %w", err)
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
+
+// secretTags returns the tags of the secret stored under key.
+func secretTags(tx *bbolt.Tx, key []byte) (map[string]string, error) {
+	b := tx.Bucket([]byte(secretTagsBucket))
+	if b == nil {
+		return nil, nil
+	}
+	v := b.Get(key)
+	if v == nil {
+		return nil, nil
+	}
+	var tags map[string]string
+	if err := json.Unmarshal(v, &tags); err != nil {
+		return nil, fmt.Errorf("corrupt tags of secret: %w", err)
+	}
+	return tags, nil
+}
+
+// deleteSecretTags removes the tags of the secret stored under key.
+func deleteSecretTags(tx *bbolt.Tx, key []byte) error {
+	if b := tx.Bucket([]byte(secretTagsBucket)); b != nil {
+		return b.Delete(key)
+	}
+	return nil
+}
+
+// moveSecretTags moves the tags of srcKey to dstKey, replacing any tags of
+// dstKey.
+func moveSecretTags(tx *bbolt.Tx, srcKey, dstKey []byte) error {
+	b := tx.Bucket([]byte(secretTagsBucket))
+	if b == nil {
+		return nil
+	}
+	if err := b.Delete(dstKey); err != nil {
+		return err
+	}
+	if v := b.Get(srcKey); v != nil {
+		if err := b.Put(dstKey, bytes.Clone(v)); err != nil {
+			return err
+		}
+		return b.Delete(srcKey)
+	}
+	return nil
+}
+
+// deleteAllSecretTags removes the tags of every key starting with prefix.
+func deleteAllSecretTags(tx *bbolt.Tx, prefix []byte) error {
+	b := tx.Bucket([]byte(secretTagsBucket))
+	if b == nil {
+		return nil
+	}
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, bytes.Clone(k))
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteSecretsByTag deletes every secret of clientName tagged tagKey=tagValue,
+// in all of its namespaces, and returns the number deleted. The secrets are
+// deleted in a single transaction, so either all of them are removed or none.
+func (d *Daemon) DeleteSecretsByTag(clientName, tagKey, tagValue string) (int, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return 0, errors.New("daemon is in a locked state, cannot delete secrets")
+	}
+	if tagKey == "" {
+		return 0, fmt.Errorf("%w: tag key must not be empty", ErrInvalidTag)
+	}
+
+	var deleted []secretRef
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		// Collect first: bbolt cursors must not be used across Deletes in the same bucket.
+		err := forEachSecret(tx, clientName, "", func(client, namespace, id string, _ []byte) error {
+			tags, err := secretTags(tx, constructDBKey(client, namespace, id))
+			if err != nil {
+				return err
+			}
+			if v, ok := tags[tagKey]; ok && v == tagValue {
+				deleted = append(deleted, secretRef{client, namespace, id})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, r := range deleted {
+			if err := deleteSecretValue(tx, r.client, r.namespace, r.id); err != nil {
+				return fmt.Errorf("failed to delete secret %s: %w", r, err)
+			}
+			key := constructDBKey(r.client, r.namespace, r.id)
+			if err := deleteSecretTags(tx, key); err != nil {
+				return err
+			}
+			if err := deleteSecretExpiry(tx, key); err != nil {
+				return err
+			}
+			if formatsB := tx.Bucket([]byte(secretFormatsBucket)); formatsB != nil {
+				if err := formatsB.Delete(key); err != nil {
+					return err
+				}
+			}
+			if accessB := tx.Bucket([]byte(secretAccessBucket)); accessB != nil {
+				if err := accessB.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	d.counters.secretsDeleted.Add(uint64(len(deleted)))
+	gaialog.Get().Info("secrets deleted by tag",
+		slog.String("client_name", clientName),
+		slog.String("tag", tagKey+"="+tagValue),
+		slog.Int("count", len(deleted)),
+	)
+	return len(deleted), nil
+}
@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeleteSecretsByTag(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, s := range []struct {
+		client, namespace, id string
+		tags                  map[string]string
+	}{
+		{"app", "app", "old_key", map[string]string{"env": "staging"}},
+		{"app", "billing", "old_token", map[string]string{"env": "staging", "team": "pay"}},
+		{"app", "app", "api_key", map[string]string{"env": "prod"}},
+		{"app", "app", "plain", nil},
+		{"other", "other", "old_key", map[string]string{"env": "staging"}},
+	} {
+		if _, err := d.AddSecret(s.client, s.namespace, s.id, "v", AddSecretOptions{Tags: s.tags}); err != nil {
+			t.Fatalf("AddSecret(%s/%s/%s) error = %v", s.client, s.namespace, s.id, err)
+		}
+	}
+
+	deleted, err := d.DeleteSecretsByTag("app", "env", "staging")
+	if err != nil {
+		t.Fatalf("DeleteSecretsByTag() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("DeleteSecretsByTag() = %d, want 2", deleted)
+	}
+	d.db.View(func(tx *bbolt.Tx) error {
+		for _, s := range []struct{ client, namespace, id string }{{"app", "app", "old_key"}, {"app", "billing", "old_token"}} {
+			if getSecretValue(tx, s.client, s.namespace, s.id) != nil {
+				t.Errorf("secret %s/%s/%s tagged env=staging was not deleted", s.client, s.namespace, s.id)
+			}
+		}
+		for _, s := range []struct{ client, namespace, id string }{{"app", "app", "api_key"}, {"app", "app", "plain"}, {"other", "other", "old_key"}} {
+			if getSecretValue(tx, s.client, s.namespace, s.id) == nil {
+				t.Errorf("secret %s/%s/%s that does not match was deleted", s.client, s.namespace, s.id)
+			}
+		}
+		return nil
+	})
+	d.db.View(func(tx *bbolt.Tx) error {
+		if tags, _ := secretTags(tx, constructDBKey("app", "app", "old_key")); tags != nil {
+			t.Errorf("tags of a deleted secret = %v, want none", tags)
+		}
+		return nil
+	})
+
+	// A tag key without a matching value deletes nothing.
+	if deleted, err := d.DeleteSecretsByTag("app", "env", "dev"); err != nil || deleted != 0 {
+		t.Errorf("DeleteSecretsByTag(env=dev) = %d, %v; want 0", deleted, err)
+	}
+	if _, err := d.DeleteSecretsByTag("app", "", "staging"); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("DeleteSecretsByTag() with an empty key error = %v, want ErrInvalidTag", err)
+	}
+
+	srv := NewAdminServer(d)
+	_, err = srv.AddSecret(context.Background(), &pb.AddSecretRequest{
+		ClientName: "app", Namespace: "app", Id: "k", Value: "v", Tags: map[string]string{"": "x"},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("AddSecret() with an empty tag key error = %v, want InvalidArgument", err)
+	}
+	res, err := srv.DeleteSecretsByTag(context.Background(), &pb.DeleteSecretsByTagRequest{ClientName: "other", TagKey: "env", TagValue: "staging"})
+	if err != nil || res.Deleted != 1 {
+		t.Errorf("DeleteSecretsByTag RPC = %v, %v; want 1 deleted", res, err)
+	}
+}
+
+func TestRenameSecret_MovesTags(t *testing.T) {
+	d := newTestDaemon(t)
+	tags := map[string]string{"env": "staging"}
+	if _, err := d.AddSecret("app", "app", "databse_url", "postgres://db", AddSecretOptions{Tags: tags}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if err := d.RenameSecret("app", "app", "databse_url", "database_url", false); err != nil {
+		t.Fatalf("RenameSecret() error = %v", err)
+	}
+	d.db.View(func(tx *bbolt.Tx) error {
+		if got, _ := secretTags(tx, constructDBKey("app", "app", "database_url")); !maps.Equal(got, tags) {
+			t.Errorf("tags of the renamed secret = %v, want %v", got, tags)
+		}
+		return nil
+	})
+	if deleted, err := d.DeleteSecretsByTag("app", "env", "staging"); err != nil || deleted != 1 {
+		t.Errorf("DeleteSecretsByTag() after rename = %d, %v; want 1", deleted, err)
+	}
+}
@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName identifies the daemon's instrumentation scope.
+const tracerName = "github.com/stain-win/gaia/apps/gaia/daemon"
+
+// SetTracerProvider makes the daemon record secret-access spans with tp. Embedders
+// use this to plug in their own provider; Start calls it when an OTLP endpoint is
+// configured. Without a provider, tracing is a no-op.
+func (d *Daemon) SetTracerProvider(tp trace.TracerProvider) {
+	d.tracer = tp.Tracer(tracerName)
+}
+
+// tracerOrNoop returns the configured tracer, or a no-op tracer when none is set.
+func (d *Daemon) tracerOrNoop() trace.Tracer {
+	if d.tracer == nil {
+		return noop.NewTracerProvider().Tracer(tracerName)
+	}
+	return d.tracer
+}
+
+// startAccessSpan starts a span for a secret-access RPC. The returned function ends
+// the span and records the outcome. Secret values are never attached to spans.
+func (d *Daemon) startAccessSpan(ctx context.Context, method, clientName, namespace string) (context.Context, func(error)) {
+	ctx, span := d.tracerOrNoop().Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.method", method),
+			attribute.String("gaia.client_cn", clientName),
+			attribute.String("gaia.namespace", namespace),
+		),
+	)
+	return ctx, func(err error) {
+		if err != nil {
+			span.SetAttributes(attribute.String("gaia.result", "error"))
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			span.SetAttributes(attribute.String("gaia.result", "ok"))
+		}
+		span.End()
+	}
+}
+
+// shutdownTracing flushes and stops the tracer provider created by Start, if any.
+func (d *Daemon) shutdownTracing() {
+	if d.tracerStop == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.tracerStop(ctx); err != nil {
+		log.Printf("failed to flush traces: %v", err)
+	}
+	d.tracerStop = nil
+}
+
+// newOTLPTracerProvider builds a tracer provider that batches spans to the OTLP/gRPC
+// collector at endpoint.
+func newOTLPTracerProvider(ctx context.Context, endpoint string, insecure bool) (*sdktrace.TracerProvider, error) {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(semconv.ServiceName("gaia"))
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
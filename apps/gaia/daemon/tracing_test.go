@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// clientContext returns a context carrying a TLS peer identified as clientName.
+func clientContext(clientName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: clientName}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+	})
+}
+
+func TestGetSecret_RecordsSpan(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app-a", "app-a", "api_key", "s3cret", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	d.SetTracerProvider(tp)
+
+	srv := NewClientServer(d)
+	if _, err := srv.GetSecret(clientContext("app-a"), &pb.GetSecretRequest{Namespace: "app-a", Id: "api_key"}); err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if _, err := srv.GetSecret(clientContext("app-b"), &pb.GetSecretRequest{Namespace: "app-a", Id: "api_key"}); err == nil {
+		t.Fatal("GetSecret() succeeded for another client's namespace")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+
+	wantResults := []string{"ok", "error"}
+	for i, span := range spans {
+		if span.Name != pb.GaiaClient_GetSecret_FullMethodName {
+			t.Errorf("span %d name = %q, want %q", i, span.Name, pb.GaiaClient_GetSecret_FullMethodName)
+		}
+		attrs := make(map[attribute.Key]string)
+		for _, kv := range span.Attributes {
+			attrs[kv.Key] = kv.Value.Emit()
+			if kv.Value.Emit() == "s3cret" {
+				t.Errorf("span %d leaks the secret value in attribute %q", i, kv.Key)
+			}
+		}
+		if attrs["gaia.namespace"] != "app-a" {
+			t.Errorf("span %d namespace = %q, want %q", i, attrs["gaia.namespace"], "app-a")
+		}
+		if attrs["gaia.result"] != wantResults[i] {
+			t.Errorf("span %d result = %q, want %q", i, attrs["gaia.result"], wantResults[i])
+		}
+	}
+	if got := spans[0].Attributes; !containsAttr(got, attribute.String("gaia.client_cn", "app-a")) {
+		t.Errorf("span attributes %v do not record the client CN", got)
+	}
+}
+
+func TestGetSecret_NoTracerIsNoop(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app-a", "app-a", "api_key", "s3cret", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := NewClientServer(d).GetSecret(clientContext("app-a"), &pb.GetSecretRequest{Namespace: "app-a", Id: "api_key"}); err != nil {
+		t.Fatalf("GetSecret() without a tracer error = %v", err)
+	}
+}
+
+func containsAttr(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if kv == want {
+			return true
+		}
+	}
+	return false
+}
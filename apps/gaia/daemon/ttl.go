@@ -0,0 +1,318 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// secretExpiryBucket holds the time a secret expires at, keyed like the
+	// secret formats bucket. Secrets without an entry never expire.
+	secretExpiryBucket = "secret_expiry"
+	// namespaceTTLBucket holds the default TTL of a namespace, keyed by client
+	// and namespace.
+	namespaceTTLBucket = "namespace_ttl"
+)
+
+// ErrInvalidTTL is returned for a negative secret TTL or a namespace TTL that is
+// not positive. Nothing is written.
+var ErrInvalidTTL = errors.New("invalid TTL")
+
+// namespaceKey returns the key of clientName's namespace in the per-namespace
+// buckets, such as the default TTLs.
+func namespaceKey(clientName, namespace string) []byte {
+	return append(keyPrefix(clientName), escapeKeyPart(namespace)...)
+}
+
+// namespaceTTL returns the default TTL of clientName's namespace, or zero when it
+// has none.
+func namespaceTTL(tx *bbolt.Tx, clientName, namespace string) (time.Duration, error) {
+	b := tx.Bucket([]byte(namespaceTTLBucket))
+	if b == nil {
+		return 0, nil
+	}
+	v := b.Get(namespaceKey(clientName, namespace))
+	if v == nil {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(string(v))
+	if err != nil {
+		return 0, fmt.Errorf("corrupt TTL of namespace '%s' of client '%s': %w", namespace, clientName, err)
+	}
+	return ttl, nil
+}
+
+// SetNamespaceTTL sets the default TTL of clientName's namespace: secrets written
+// to it by AddSecret without a TTL of their own expire ttl after the write.
+// Secrets already in the namespace keep their expiry.
+func (d *Daemon) SetNamespaceTTL(clientName, namespace string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("%w: namespace TTL must be positive", ErrInvalidTTL)
+	}
+
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return errors.New("daemon is in a locked state, cannot change namespace TTLs")
+	}
+
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(namespaceTTLBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create or get namespace TTL bucket: %w", err)
+		}
+		return b.Put(namespaceKey(clientName, namespace), []byte(ttl.String()))
+	})
+
+	if err == nil {
+		gaialog.Get().Info("namespace TTL set",
+			slog.String("client_name", clientName),
+			slog.String("namespace", namespace),
+			slog.Duration("ttl", ttl),
+		)
+	}
+	return err
+}
+
+// ClearNamespaceTTL removes the default TTL of clientName's namespace. Secrets
+// already in the namespace keep their expiry.
+func (d *Daemon) ClearNamespaceTTL(clientName, namespace string) error {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return errors.New("daemon is in a locked state, cannot change namespace TTLs")
+	}
+
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		if b := tx.Bucket([]byte(namespaceTTLBucket)); b != nil {
+			return b.Delete(namespaceKey(clientName, namespace))
+		}
+		return nil
+	})
+
+	if err == nil {
+		gaialog.Get().Info("namespace TTL cleared",
+			slog.String("client_name", clientName),
+			slog.String("namespace", namespace),
+		)
+	}
+	return err
+}
+
+// deleteNamespaceTTLs removes the default TTLs of every namespace of clientName.
+func deleteNamespaceTTLs(tx *bbolt.Tx, clientName string) error {
+	return deleteKeysWithPrefix(tx.Bucket([]byte(namespaceTTLBucket)), keyPrefix(clientName))
+}
+
+// setSecretExpiry sets the expiry of the secret of clientName's namespace stored
+// under id after a write at now: ttl after now when ttl is set, otherwise the
+// namespace's default TTL after now, and no expiry when neither is set.
+func setSecretExpiry(tx *bbolt.Tx, clientName, namespace, id string, ttl time.Duration, now time.Time) error {
+	if ttl == 0 {
+		var err error
+		if ttl, err = namespaceTTL(tx, clientName, namespace); err != nil {
+			return err
+		}
+	}
+	key := constructDBKey(clientName, namespace, id)
+	if ttl == 0 {
+		return deleteSecretExpiry(tx, key)
+	}
+	b, err := tx.CreateBucketIfNotExists([]byte(secretExpiryBucket))
+	if err != nil {
+		return fmt.Errorf("failed to create or get secret expiry bucket: %w", err)
+	}
+	return b.Put(key, []byte(now.Add(ttl).UTC().Format(time.RFC3339Nano)))
+}
+
+// secretExpiry returns the time the secret stored under key expires at, or the
+// zero time when it does not expire.
+func secretExpiry(tx *bbolt.Tx, key []byte) (time.Time, error) {
+	b := tx.Bucket([]byte(secretExpiryBucket))
+	if b == nil {
+		return time.Time{}, nil
+	}
+	v := b.Get(key)
+	if v == nil {
+		return time.Time{}, nil
+	}
+	expires, err := time.Parse(time.RFC3339Nano, string(v))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("corrupt expiry of secret: %w", err)
+	}
+	return expires, nil
+}
+
+// secretExpired reports whether the secret stored under key has expired at now.
+// It may still be stored until the next sweep.
+func secretExpired(tx *bbolt.Tx, key []byte, now time.Time) (bool, error) {
+	expires, err := secretExpiry(tx, key)
+	if err != nil || expires.IsZero() {
+		return false, err
+	}
+	return !now.Before(expires), nil
+}
+
+// secretExists reports whether the secret of clientName's namespace stored under
+// id exists and has not expired at now.
+func secretExists(tx *bbolt.Tx, clientName, namespace, id string, now time.Time) (bool, error) {
+	if getSecretValue(tx, clientName, namespace, id) == nil {
+		return false, nil
+	}
+	expired, err := secretExpired(tx, constructDBKey(clientName, namespace, id), now)
+	return !expired, err
+}
+
+// skipExpired wraps fn for forEachSecret so that it is not called for the
+// secrets that have expired at now but are still stored until the next sweep.
+func skipExpired(tx *bbolt.Tx, now time.Time, fn func(client, namespace, id string, value []byte) error) func(client, namespace, id string, value []byte) error {
+	return func(client, namespace, id string, value []byte) error {
+		expired, err := secretExpired(tx, constructDBKey(client, namespace, id), now)
+		if err != nil || expired {
+			return err
+		}
+		return fn(client, namespace, id, value)
+	}
+}
+
+// deleteSecretExpiry removes the expiry of the secret stored under key.
+func deleteSecretExpiry(tx *bbolt.Tx, key []byte) error {
+	if b := tx.Bucket([]byte(secretExpiryBucket)); b != nil {
+		return b.Delete(key)
+	}
+	return nil
+}
+
+// moveSecretExpiry moves the expiry of srcKey to dstKey, replacing any expiry of
+// dstKey.
+func moveSecretExpiry(tx *bbolt.Tx, srcKey, dstKey []byte) error {
+	b := tx.Bucket([]byte(secretExpiryBucket))
+	if b == nil {
+		return nil
+	}
+	if err := b.Delete(dstKey); err != nil {
+		return err
+	}
+	if v := b.Get(srcKey); v != nil {
+		if err := b.Put(dstKey, bytes.Clone(v)); err != nil {
+			return err
+		}
+		return b.Delete(srcKey)
+	}
+	return nil
+}
+
+// deleteAllSecretExpiries removes the expiry of every key starting with prefix.
+func deleteAllSecretExpiries(tx *bbolt.Tx, prefix []byte) error {
+	return deleteKeysWithPrefix(tx.Bucket([]byte(secretExpiryBucket)), prefix)
+}
+
+// deleteKeysWithPrefix removes every key of b starting with prefix. A nil bucket
+// holds no keys.
+func deleteKeysWithPrefix(b *bbolt.Bucket, prefix []byte) error {
+	if b == nil {
+		return nil
+	}
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, bytes.Clone(k))
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SweepExpiredSecrets deletes every secret that has expired at now, with its
+// format, tag, access and expiry records, and returns the number deleted. A
+// locked daemon is not swept.
+func (d *Daemon) SweepExpiredSecrets(now time.Time) (int, error) {
+	d.dbLock.Lock()
+	defer d.dbLock.Unlock()
+
+	if d.isLocked || d.db == nil {
+		return 0, nil
+	}
+
+	var expired []secretRef
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(secretExpiryBucket))
+		if b == nil {
+			return nil
+		}
+		// Collect first: bbolt cursors must not be used across Deletes in the same bucket.
+		err := b.ForEach(func(k, v []byte) error {
+			expires, err := time.Parse(time.RFC3339Nano, string(v))
+			if err != nil || now.Before(expires) {
+				return nil
+			}
+			if client, namespace, id, ok := parseSecretKey(k); ok {
+				expired = append(expired, secretRef{client, namespace, id})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, r := range expired {
+			if err := deleteSecretValue(tx, r.client, r.namespace, r.id); err != nil {
+				return fmt.Errorf("failed to delete secret %s: %w", r, err)
+			}
+			key := constructDBKey(r.client, r.namespace, r.id)
+			if err := deleteSecretExpiry(tx, key); err != nil {
+				return err
+			}
+			if err := deleteSecretTags(tx, key); err != nil {
+				return err
+			}
+			if formatsB := tx.Bucket([]byte(secretFormatsBucket)); formatsB != nil {
+				if err := formatsB.Delete(key); err != nil {
+					return err
+				}
+			}
+			if accessB := tx.Bucket([]byte(secretAccessBucket)); accessB != nil {
+				if err := accessB.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(expired) > 0 {
+		d.counters.secretsDeleted.Add(uint64(len(expired)))
+		gaialog.Get().Info("expired secrets deleted", slog.Int("count", len(expired)))
+	}
+	return len(expired), nil
+}
+
+// runExpirySweeper deletes expired secrets every interval until stopped is closed.
+func (d *Daemon) runExpirySweeper(interval time.Duration, stopped <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if _, err := d.SweepExpiredSecrets(now); err != nil {
+				gaialog.Get().Error("failed to delete expired secrets", slog.String("error", err.Error()))
+			}
+		case <-stopped:
+			return
+		}
+	}
+}
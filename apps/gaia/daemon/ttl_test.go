@@ -0,0 +1,265 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"go.etcd.io/bbolt"
+)
+
+func TestNamespaceTTL_Inherited(t *testing.T) {
+	d := newTestDaemon(t)
+	if err := d.SetNamespaceTTL("app", "ephemeral", time.Hour); err != nil {
+		t.Fatalf("SetNamespaceTTL() error = %v", err)
+	}
+
+	before := time.Now()
+	if _, err := d.AddSecret("app", "ephemeral", "token", "v", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "ephemeral", "short", "v", AddSecretOptions{TTL: time.Minute}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "kept", "v", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	after := time.Now()
+
+	d.db.View(func(tx *bbolt.Tx) error {
+		// A secret added without a TTL expires after the namespace default.
+		expires, err := secretExpiry(tx, constructDBKey("app", "ephemeral", "token"))
+		if err != nil || expires.Before(before.Add(time.Hour)) || expires.After(after.Add(time.Hour)) {
+			t.Errorf("expiry of a secret without a TTL = %v, %v; want an hour after the write", expires, err)
+		}
+		// An explicit TTL wins over the default.
+		expires, err = secretExpiry(tx, constructDBKey("app", "ephemeral", "short"))
+		if err != nil || expires.After(after.Add(time.Minute)) {
+			t.Errorf("expiry of a secret with a TTL = %v, %v; want a minute after the write", expires, err)
+		}
+		// Other namespaces are not affected.
+		if expires, _ := secretExpiry(tx, constructDBKey("app", "app", "kept")); !expires.IsZero() {
+			t.Errorf("expiry of a secret outside the namespace = %v, want none", expires)
+		}
+		return nil
+	})
+
+	// Once cleared, new writes no longer expire.
+	if err := d.ClearNamespaceTTL("app", "ephemeral"); err != nil {
+		t.Fatalf("ClearNamespaceTTL() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "ephemeral", "token", "v2", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	d.db.View(func(tx *bbolt.Tx) error {
+		if expires, _ := secretExpiry(tx, constructDBKey("app", "ephemeral", "token")); !expires.IsZero() {
+			t.Errorf("expiry after clearing the namespace TTL = %v, want none", expires)
+		}
+		return nil
+	})
+}
+
+func TestNamespaceTTL_Invalid(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, ttl := range []time.Duration{0, -time.Hour} {
+		if err := d.SetNamespaceTTL("app", "app", ttl); !errors.Is(err, ErrInvalidTTL) {
+			t.Errorf("SetNamespaceTTL(%v) error = %v, want ErrInvalidTTL", ttl, err)
+		}
+	}
+	if _, err := d.AddSecret("app", "app", "id", "v", AddSecretOptions{TTL: -time.Second}); !errors.Is(err, ErrInvalidTTL) {
+		t.Errorf("AddSecret() with a negative TTL error = %v, want ErrInvalidTTL", err)
+	}
+}
+
+func TestSweepExpiredSecrets(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "expiring", "v", AddSecretOptions{Tags: map[string]string{"env": "dev"}, TTL: time.Hour}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "kept", "v", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	if n, err := d.SweepExpiredSecrets(time.Now()); err != nil || n != 0 {
+		t.Fatalf("SweepExpiredSecrets() before the expiry = %d, %v; want 0", n, err)
+	}
+	if _, err := d.GetSecret("app", "app", "expiring"); err != nil {
+		t.Fatalf("GetSecret() before the expiry error = %v", err)
+	}
+
+	if n, err := d.SweepExpiredSecrets(time.Now().Add(2 * time.Hour)); err != nil || n != 1 {
+		t.Fatalf("SweepExpiredSecrets() after the expiry = %d, %v; want 1", n, err)
+	}
+	if _, err := d.GetSecret("app", "app", "expiring"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("GetSecret() of a swept secret error = %v, want ErrSecretNotFound", err)
+	}
+	if _, err := d.GetSecret("app", "app", "kept"); err != nil {
+		t.Errorf("GetSecret() of a secret without expiry error = %v", err)
+	}
+	d.db.View(func(tx *bbolt.Tx) error {
+		if getSecretValue(tx, "app", "app", "expiring") != nil {
+			t.Error("swept secret is still stored")
+		}
+		key := constructDBKey("app", "app", "expiring")
+		if tags, _ := secretTags(tx, key); tags != nil {
+			t.Errorf("tags of a swept secret = %v, want none", tags)
+		}
+		if expires, _ := secretExpiry(tx, key); !expires.IsZero() {
+			t.Errorf("expiry of a swept secret = %v, want none", expires)
+		}
+		return nil
+	})
+}
+
+func TestGetSecret_ExpiredBeforeSweep(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "token", "v", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	// Expire the secret in the past without sweeping it.
+	d.db.Update(func(tx *bbolt.Tx) error {
+		return setSecretExpiry(tx, "app", "app", "token", time.Minute, time.Now().Add(-time.Hour))
+	})
+
+	if _, err := d.GetSecret("app", "app", "token"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("GetSecret() of an expired secret error = %v, want ErrSecretNotFound", err)
+	}
+	// The expired secret counts as absent, and the new one does not expire.
+	value, created, err := d.CreateSecretIfAbsent("app", "app", "token", "fresh")
+	if err != nil || !created || value != "fresh" {
+		t.Errorf("CreateSecretIfAbsent() = %q, %v, %v; want fresh, true", value, created, err)
+	}
+	if got, err := d.GetSecret("app", "app", "token"); err != nil || got != "fresh" {
+		t.Errorf("GetSecret() = %q, %v; want fresh", got, err)
+	}
+}
+
+// expireSecret sets the secret to have expired an hour ago without sweeping it.
+func expireSecret(t *testing.T, d *Daemon, client, namespace, id string) {
+	t.Helper()
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		return setSecretExpiry(tx, client, namespace, id, time.Minute, time.Now().Add(-time.Hour))
+	})
+	if err != nil {
+		t.Fatalf("setSecretExpiry() error = %v", err)
+	}
+}
+
+func TestImportSecrets_Expiry(t *testing.T) {
+	d := newTestDaemon(t)
+	if err := d.SetNamespaceTTL("app", "app", time.Hour); err != nil {
+		t.Fatalf("SetNamespaceTTL() error = %v", err)
+	}
+	if _, err := d.AddSecret("app", "app", "stale", "old", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	expireSecret(t, d, "app", "app", "stale")
+	if _, err := d.AddSecret("app-b", "app-b", "token", "old", AddSecretOptions{TTL: time.Hour}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+
+	// The expired secret is not a conflict, and takes the namespace default.
+	before := time.Now()
+	_, err := d.ImportSecrets([]*pb.ImportSecretItem{
+		{ClientName: "app", Namespace: "app", Id: "stale", Value: "new"},
+	}, ImportFailOnConflict, "")
+	if err != nil {
+		t.Fatalf("ImportSecrets() over an expired secret error = %v", err)
+	}
+	if got, err := d.GetSecret("app", "app", "stale"); err != nil || got != "new" {
+		t.Errorf("GetSecret() after the import = %q, %v; want new", got, err)
+	}
+
+	// Overwriting drops the TTL of the replaced secret.
+	_, err = d.ImportSecrets([]*pb.ImportSecretItem{
+		{ClientName: "app-b", Namespace: "app-b", Id: "token", Value: "new"},
+	}, ImportOverwrite, "")
+	if err != nil {
+		t.Fatalf("ImportSecrets() with overwrite error = %v", err)
+	}
+
+	d.db.View(func(tx *bbolt.Tx) error {
+		expires, _ := secretExpiry(tx, constructDBKey("app", "app", "stale"))
+		if expires.Before(before.Add(time.Hour)) || expires.After(time.Now().Add(time.Hour)) {
+			t.Errorf("expiry of the imported secret = %v, want an hour after the import", expires)
+		}
+		if expires, _ := secretExpiry(tx, constructDBKey("app-b", "app-b", "token")); !expires.IsZero() {
+			t.Errorf("expiry of an overwritten secret = %v, want none", expires)
+		}
+		return nil
+	})
+}
+
+func TestRotateSecrets_SkipsExpired(t *testing.T) {
+	d := newTestDaemon(t)
+	for _, id := range []string{"live", "stale"} {
+		if _, err := d.AddSecret("app", "app", id, "old-"+id, AddSecretOptions{TTL: time.Hour}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	expireSecret(t, d, "app", "app", "stale")
+	enableRotation(t, d, "app", "app")
+
+	rotated, err := d.RotateSecrets("app", "app", func() (string, error) { return "rotated", nil })
+	if err != nil {
+		t.Fatalf("RotateSecrets() error = %v", err)
+	}
+	if len(rotated) != 1 || rotated[0].ID != "live" {
+		t.Errorf("RotateSecrets() = %+v, want only the live secret", rotated)
+	}
+	d.db.View(func(tx *bbolt.Tx) error {
+		if expires, _ := secretExpiry(tx, constructDBKey("app", "app", "live")); !expires.IsZero() {
+			t.Errorf("expiry of a rotated secret = %v, want none", expires)
+		}
+		return nil
+	})
+}
+
+func TestListSecrets_SkipsExpired(t *testing.T) {
+	d := newCommonAreaDaemon(t)
+	for _, id := range []string{"live", "stale"} {
+		if _, err := d.AddSecret("app", "app", id, "v", AddSecretOptions{}); err != nil {
+			t.Fatalf("AddSecret() error = %v", err)
+		}
+	}
+	expireSecret(t, d, "app", "app", "stale")
+	expireSecret(t, d, commonNamespace, "shared", "smtp_host")
+	ctx := context.Background()
+
+	listed, err := d.ListSecrets(ctx, "app")
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if _, ok := listed["app"]["stale"]; ok || len(listed["app"]) != 1 {
+		t.Errorf("ListSecrets() = %v, want only the live secret", listed)
+	}
+
+	exported, err := d.ExportSecrets(ctx, "app")
+	if err != nil {
+		t.Fatalf("ExportSecrets() error = %v", err)
+	}
+	if _, ok := exported["app"]["app"]["stale"]; ok {
+		t.Errorf("ExportSecrets() = %v, want the expired secret left out", exported)
+	}
+
+	own, _, err := d.ListOwnSecrets(ctx, "app", "app", 0, "")
+	if err != nil {
+		t.Fatalf("ListOwnSecrets() error = %v", err)
+	}
+	if len(own) != 1 || own[0].ID != "live" {
+		t.Errorf("ListOwnSecrets() = %+v, want only the live secret", own)
+	}
+
+	common, err := d.GetCommonSecrets("app", "")
+	if err != nil {
+		t.Fatalf("GetCommonSecrets() error = %v", err)
+	}
+	if _, ok := common["shared"]; ok {
+		t.Errorf("GetCommonSecrets() = %v, want the expired secret left out", common)
+	}
+	if _, ok := common["billing"]; !ok {
+		t.Errorf("GetCommonSecrets() = %v, want the live secrets", common)
+	}
+}
@@ -0,0 +1,196 @@
+package daemon
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LockReason records why the daemon was last locked.
+type LockReason string
+
+const (
+	// LockReasonStartup means the daemon has not been unlocked since it started.
+	LockReasonStartup LockReason = "startup"
+	// LockReasonManual means an admin locked the daemon with the Lock RPC.
+	LockReasonManual LockReason = "manual"
+	// LockReasonShutdown means the daemon locked itself while stopping.
+	LockReasonShutdown LockReason = "shutdown"
+	// LockReasonKeyMismatch means the key hash stored in the database stopped
+	// matching the loaded key while the daemon was unlocked.
+	LockReasonKeyMismatch LockReason = "key_mismatch"
+)
+
+// errorInfoDomain and errorInfoLocked identify the ErrorInfo detail attached to
+// errors of the unlock gate.
+const (
+	errorInfoDomain = "gaia"
+	errorInfoLocked = "DAEMON_LOCKED"
+)
+
+// LockState reports whether the daemon is locked, why, and since when. Reason
+// is empty while the daemon is unlocked.
+func (d *Daemon) LockState() (locked bool, reason LockReason, since time.Time) {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+	return d.isLocked, d.lockReason, d.lockChanged
+}
+
+// setLockStateLocked records a lock or unlock. The caller must hold dbLock for
+// writing.
+func (d *Daemon) setLockStateLocked(locked bool, reason LockReason) {
+	d.isLocked = locked
+	d.lockReason = reason
+	d.lockChanged = time.Now().UTC()
+}
+
+// DescribeLock returns a human-readable explanation of a lock, such as "locked
+// at 12:03:04 by a manual lock". The time is shown in the local time zone.
+func DescribeLock(reason LockReason, since time.Time) string {
+	var why string
+	switch reason {
+	case LockReasonStartup:
+		why = "on daemon startup"
+	case LockReasonManual:
+		why = "by a manual lock"
+	case LockReasonShutdown:
+		why = "while shutting down"
+	case LockReasonKeyMismatch:
+		why = "after the database key hash changed"
+	default:
+		why = "(" + string(reason) + ")"
+	}
+	if since.IsZero() {
+		return "locked " + why
+	}
+	return "locked at " + since.Local().Format(time.TimeOnly) + " " + why
+}
+
+// DescribeUptime returns a human-readable account of how long a daemon started
+// at started has been running, such as "up 3h2m5s since 2026-01-02 12:03:04".
+func DescribeUptime(started time.Time, uptime time.Duration) string {
+	return "up " + uptime.Round(time.Second).String() + " since " + started.Local().Format(time.DateTime)
+}
+
+// LockDetails extracts the lock reason and time attached to a FailedPrecondition
+// error of the unlock gate. ok is false for any other error.
+func LockDetails(err error) (reason LockReason, since time.Time, ok bool) {
+	st, isStatus := status.FromError(err)
+	if !isStatus || err == nil {
+		return "", time.Time{}, false
+	}
+	for _, detail := range st.Details() {
+		info, isInfo := detail.(*errdetails.ErrorInfo)
+		if !isInfo || info.Domain != errorInfoDomain || info.Reason != errorInfoLocked {
+			continue
+		}
+		since, _ = time.Parse(time.RFC3339, info.Metadata["locked_at"])
+		return LockReason(info.Metadata["lock_reason"]), since, true
+	}
+	return "", time.Time{}, false
+}
+
+// lockedError is the error returned for RPCs refused by the unlock gate. The
+// lock reason and time are attached as an ErrorInfo detail.
+func (d *Daemon) lockedError() error {
+	_, reason, since := d.LockState()
+	st := status.New(codes.FailedPrecondition, "daemon is locked")
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: errorInfoLocked,
+		Domain: errorInfoDomain,
+		Metadata: map[string]string{
+			"lock_reason": string(reason),
+			"locked_at":   since.Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// lockPolicy says whether an RPC is served while the database is locked.
+type lockPolicy int
+
+const (
+	// requiresUnlock RPCs need the decryption key and are refused while locked.
+	requiresUnlock lockPolicy = iota
+	// servedWhileLocked RPCs are always served.
+	servedWhileLocked
+	// servedUnlessGated RPCs are served while locked, except when
+	// RequireUnlockToServe is set.
+	servedUnlessGated
+)
+
+// lockPolicies lists the Gaia RPCs that do not require an unlocked daemon. Every
+// other Gaia RPC requires it.
+var lockPolicies = map[string]lockPolicy{
+	pb.GaiaAdmin_Unlock_FullMethodName:     servedWhileLocked,
+	pb.GaiaAdmin_GetStatus_FullMethodName:  servedWhileLocked,
+	pb.GaiaAdmin_GetMetrics_FullMethodName: servedWhileLocked,
+	pb.GaiaAdmin_Lock_FullMethodName:       servedUnlessGated,
+	pb.GaiaAdmin_Stop_FullMethodName:       servedUnlessGated,
+}
+
+// lockPolicyFor returns the policy of method. Services other than Gaia's own,
+// such as server reflection, never touch the database.
+func lockPolicyFor(method string) lockPolicy {
+	if p, ok := lockPolicies[method]; ok {
+		return p
+	}
+	if strings.HasPrefix(method, "/gaia.") {
+		return requiresUnlock
+	}
+	return servedUnlessGated
+}
+
+// IsLocked reports whether the database is currently locked.
+func (d *Daemon) IsLocked() bool {
+	d.dbLock.RLock()
+	defer d.dbLock.RUnlock()
+	return d.isLocked
+}
+
+// unlockGateOptions returns the server options that refuse RPCs needing an
+// unlocked daemon while it is locked, with a uniform FailedPrecondition error.
+func (d *Daemon) unlockGateOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(d.unlockGateUnary),
+		grpc.ChainStreamInterceptor(d.unlockGateStream),
+	}
+}
+
+func (d *Daemon) checkUnlockGate(method string) error {
+	if !d.IsLocked() {
+		return nil
+	}
+	switch lockPolicyFor(method) {
+	case servedWhileLocked:
+		return nil
+	case servedUnlessGated:
+		if !d.config.RequireUnlockToServe {
+			return nil
+		}
+	}
+	return d.lockedError()
+}
+
+func (d *Daemon) unlockGateUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := d.checkUnlockGate(info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (d *Daemon) unlockGateStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := d.checkUnlockGate(info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
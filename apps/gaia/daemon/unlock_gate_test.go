@@ -0,0 +1,220 @@
+package daemon
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// serveWithUnlockGate serves d's services behind the unlock gate on a loopback
+// listener and returns a connection to it.
+func serveWithUnlockGate(t *testing.T, d *Daemon) *grpc.ClientConn {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer(d.unlockGateOptions()...)
+	pb.RegisterGaiaAdminServer(srv, NewAdminServer(d))
+	pb.RegisterGaiaClientServer(srv, NewClientServer(d))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestUnlockGate_RefusesRPCsBeforeUnlock(t *testing.T) {
+	d := NewDaemon(newTestConfig(t, func(c *config.Config) { c.RequireUnlockToServe = true }))
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	t.Cleanup(d.LockDB)
+
+	conn := serveWithUnlockGate(t, d)
+	admin := pb.NewGaiaAdminClient(conn)
+	client := pb.NewGaiaClientClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	refused := map[string]func() error{
+		"AddSecret": func() error {
+			_, err := admin.AddSecret(ctx, &pb.AddSecretRequest{ClientName: "app", Namespace: "app", Id: "k", Value: "v"})
+			return err
+		},
+		"ListClients": func() error {
+			_, err := admin.ListClients(ctx, &pb.ListClientsRequest{})
+			return err
+		},
+		"Lock": func() error {
+			_, err := admin.Lock(ctx, &pb.LockRequest{})
+			return err
+		},
+		"Stop": func() error {
+			_, err := admin.Stop(ctx, &pb.StopRequest{})
+			return err
+		},
+		"ImportSecrets": func() error {
+			stream, err := admin.ImportSecrets(ctx)
+			if err != nil {
+				return err
+			}
+			_, err = stream.CloseAndRecv()
+			return err
+		},
+		"GetSecret": func() error {
+			_, err := client.GetSecret(ctx, &pb.GetSecretRequest{Namespace: "app", Id: "k"})
+			return err
+		},
+	}
+	for name, call := range refused {
+		if err := call(); status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("%s before unlock: error = %v, want FailedPrecondition", name, err)
+		}
+	}
+
+	if _, err := admin.GetStatus(ctx, &pb.GetStatusRequest{}); err != nil {
+		t.Errorf("GetStatus before unlock: error = %v", err)
+	}
+	if _, err := admin.Unlock(ctx, &pb.UnlockRequest{Passphrase: "wrong passphrase"}); status.Code(err) == codes.FailedPrecondition {
+		t.Errorf("Unlock with wrong passphrase was refused by the gate: %v", err)
+	}
+	if _, err := admin.Unlock(ctx, &pb.UnlockRequest{Passphrase: testPassphrase}); err != nil {
+		t.Fatalf("Unlock error = %v", err)
+	}
+
+	if _, err := admin.ListClients(ctx, &pb.ListClientsRequest{}); err != nil {
+		t.Errorf("ListClients after unlock: error = %v", err)
+	}
+}
+
+func TestStart_RequireUnlockToServeWhileLocked(t *testing.T) {
+	cfg := newTestConfig(t, func(c *config.Config) { c.RequireUnlockToServe = true })
+	d := NewDaemon(cfg)
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+
+	if err := d.Start(cfg); err == nil {
+		t.Fatal("Start() on a locked daemon succeeded, want an error")
+	}
+	if got := d.Status(); got != StatusStopped {
+		t.Errorf("Status() = %q, want %q", got, StatusStopped)
+	}
+}
+
+func TestUnlockGate_LockedDaemonRejectsGetSecret(t *testing.T) {
+	d := newTestDaemon(t)
+	if _, err := d.AddSecret("app", "app", "k", "v", AddSecretOptions{}); err != nil {
+		t.Fatalf("AddSecret() error = %v", err)
+	}
+	d.LockDB()
+
+	conn := serveWithUnlockGate(t, d)
+	admin := pb.NewGaiaAdminClient(conn)
+	client := pb.NewGaiaClientClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := client.GetSecret(ctx, &pb.GetSecretRequest{Namespace: "app", Id: "k"})
+	if st := status.Convert(err); st.Code() != codes.FailedPrecondition || st.Message() != "daemon is locked" {
+		t.Errorf("GetSecret on a locked daemon: error = %v, want FailedPrecondition: daemon is locked", err)
+	}
+	if _, err := admin.ListClients(ctx, &pb.ListClientsRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ListClients on a locked daemon: error = %v, want FailedPrecondition", err)
+	}
+
+	// Without require_unlock_to_serve, locking again and checking the status work.
+	if _, err := admin.GetStatus(ctx, &pb.GetStatusRequest{}); err != nil {
+		t.Errorf("GetStatus on a locked daemon: error = %v", err)
+	}
+	if _, err := admin.Lock(ctx, &pb.LockRequest{}); err != nil {
+		t.Errorf("Lock on a locked daemon: error = %v", err)
+	}
+}
+
+func TestLockState_Reasons(t *testing.T) {
+	d := NewDaemon(newTestConfig(t))
+	if err := d.InitializeDB(testPassphrase); err != nil {
+		t.Fatalf("InitializeDB() error = %v", err)
+	}
+	conn := serveWithUnlockGate(t, d)
+	admin := pb.NewGaiaAdminClient(conn)
+	client := pb.NewGaiaClientClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// checkLocked asserts the lock reason reported by GetStatus and attached to
+	// the error of a refused GetSecret.
+	checkLocked := func(t *testing.T, want LockReason) {
+		t.Helper()
+		res, err := admin.GetStatus(ctx, &pb.GetStatusRequest{})
+		if err != nil {
+			t.Fatalf("GetStatus() error = %v", err)
+		}
+		if !res.Locked || res.LockReason != string(want) {
+			t.Errorf("GetStatus() locked = %v, reason = %q; want locked with %q", res.Locked, res.LockReason, want)
+		}
+		if _, err := time.Parse(time.RFC3339, res.LockChanged); err != nil {
+			t.Errorf("GetStatus() lock_changed = %q is not RFC 3339: %v", res.LockChanged, err)
+		}
+
+		_, err = client.GetSecret(ctx, &pb.GetSecretRequest{Namespace: "app", Id: "k"})
+		reason, since, ok := LockDetails(err)
+		if !ok || reason != want || since.IsZero() {
+			t.Errorf("LockDetails(GetSecret error) = %q, %v, %v; want %q with a time", reason, since, ok, want)
+		}
+	}
+
+	t.Run("startup", func(t *testing.T) {
+		checkLocked(t, LockReasonStartup)
+		_, _, since := d.LockState()
+		if got := DescribeLock(LockReasonStartup, since); got != "locked at "+since.Local().Format(time.TimeOnly)+" on daemon startup" {
+			t.Errorf("DescribeLock() = %q", got)
+		}
+	})
+
+	t.Run("unlocked", func(t *testing.T) {
+		if err := d.UnlockDB(testPassphrase); err != nil {
+			t.Fatalf("UnlockDB() error = %v", err)
+		}
+		res, err := admin.GetStatus(ctx, &pb.GetStatusRequest{})
+		if err != nil || res.Locked || res.LockReason != "" {
+			t.Errorf("GetStatus() after unlock = %v, %v; want unlocked without a reason", res, err)
+		}
+	})
+
+	t.Run("manual", func(t *testing.T) {
+		if _, err := admin.Lock(ctx, &pb.LockRequest{}); err != nil {
+			t.Fatalf("Lock() error = %v", err)
+		}
+		checkLocked(t, LockReasonManual)
+		if got := DescribeLock(LockReasonManual, time.Time{}); got != "locked by a manual lock" {
+			t.Errorf("DescribeLock() without a time = %q", got)
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		if err := d.UnlockDB(testPassphrase); err != nil {
+			t.Fatalf("UnlockDB() error = %v", err)
+		}
+		d.closeDB(LockReasonShutdown)
+		checkLocked(t, LockReasonShutdown)
+	})
+
+	if _, _, ok := LockDetails(status.Error(codes.FailedPrecondition, "daemon is locked")); ok {
+		t.Error("LockDetails() reported details for an error without them")
+	}
+}
@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	"github.com/stain-win/gaia/apps/gaia/gaialog"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+)
+
+// Actions of a value rule.
+const (
+	ValueRuleReject = "reject"
+	ValueRuleWarn   = "warn"
+)
+
+// ErrValueRejected is returned for writes of a secret value that matches a value
+// rule with the reject action. Nothing is written.
+var ErrValueRejected = errors.New("secret value rejected by value rule")
+
+// ValueViolation reports a secret whose value matched a value rule. The value
+// itself is never included.
+type ValueViolation struct {
+	Client    string
+	Namespace string
+	ID        string
+	Rule      string
+	Action    string
+}
+
+type valueRule struct {
+	name   string
+	action string
+	re     *regexp.Regexp
+}
+
+// compileValueRules compiles the value rules of the configuration.
+func compileValueRules(rules []config.ValueRule) ([]valueRule, error) {
+	res := make([]valueRule, len(rules))
+	for i, r := range rules {
+		if r.Action != ValueRuleReject && r.Action != ValueRuleWarn {
+			return nil, fmt.Errorf("invalid action '%s' for value rule '%s', want %s or %s", r.Action, r.Pattern, ValueRuleReject, ValueRuleWarn)
+		}
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value rule pattern '%s': %w", r.Pattern, err)
+		}
+		name := r.Name
+		if name == "" {
+			name = r.Pattern
+		}
+		res[i] = valueRule{name: name, action: r.Action, re: re}
+	}
+	return res, nil
+}
+
+// checkValues matches the values of secrets against the configured value rules.
+// It returns the violations of warn rules, or ErrValueRejected naming every
+// secret that matched a reject rule.
+func (d *Daemon) checkValues(secrets []*pb.ImportSecretItem) ([]ValueViolation, error) {
+	if len(d.config.ValueRules) == 0 {
+		return nil, nil
+	}
+	rules, err := compileValueRules(d.config.ValueRules)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []ValueViolation
+	var rejected []string
+	for _, secret := range secrets {
+		for _, rule := range rules {
+			if !rule.re.MatchString(secret.Value) {
+				continue
+			}
+			if rule.action == ValueRuleReject {
+				rejected = append(rejected, fmt.Sprintf("'%s/%s/%s' matches '%s'", secret.ClientName, secret.Namespace, secret.Id, rule.name))
+				continue
+			}
+			warnings = append(warnings, ValueViolation{
+				Client:    secret.ClientName,
+				Namespace: secret.Namespace,
+				ID:        secret.Id,
+				Rule:      rule.name,
+				Action:    rule.action,
+			})
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrValueRejected, strings.Join(rejected, "; "))
+	}
+	return warnings, nil
+}
+
+// logValueWarnings logs the violations of warn rules by a write.
+func logValueWarnings(warnings []ValueViolation) {
+	for _, w := range warnings {
+		gaialog.Get().Warn("secret value matches value rule",
+			slog.String("client_name", w.Client),
+			slog.String("namespace", w.Namespace),
+			slog.String("id", w.ID),
+			slog.String("rule", w.Rule),
+		)
+	}
+}
+
+// valueViolationsToPB converts violations for an RPC response.
+func valueViolationsToPB(violations []ValueViolation) []*pb.ValueViolation {
+	if len(violations) == 0 {
+		return nil
+	}
+	res := make([]*pb.ValueViolation, len(violations))
+	for i, v := range violations {
+		res[i] = &pb.ValueViolation{ClientName: v.Client, Namespace: v.Namespace, Id: v.ID, Rule: v.Rule, Action: v.Action}
+	}
+	return res
+}
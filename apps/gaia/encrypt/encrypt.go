@@ -5,6 +5,8 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"io"
 
 	"golang.org/x/crypto/scrypt"
@@ -14,6 +16,23 @@ const (
 	KeyLen = 32 // AES-256
 )
 
+// Encodings of stored values written by EncryptValue.
+const (
+	// EncodingBase64 stores the output of Encrypt.
+	EncodingBase64 = "base64"
+	// EncodingRaw stores rawVersion followed by the binary nonce and ciphertext,
+	// a third smaller than EncodingBase64.
+	EncodingRaw = "raw"
+)
+
+// ErrCiphertextTooShort is returned for ciphertext that is too short to hold a
+// nonce, such as a truncated value.
+var ErrCiphertextTooShort = errors.New("ciphertext is shorter than the nonce")
+
+// rawVersion is the header byte of a raw value. It never occurs in base64 text,
+// so DecryptValue tells the encodings apart by the first byte.
+const rawVersion byte = 0x01
+
 // DeriveKey derives a key from the passphrase and salt using scrypt.
 func DeriveKey(passphrase, salt []byte) ([]byte, error) {
 	return scrypt.Key(passphrase, salt, 1<<15, 8, 1, KeyLen)
@@ -21,19 +40,10 @@ func DeriveKey(passphrase, salt []byte) ([]byte, error) {
 
 // Encrypt encrypts plaintext using AES-256-GCM.
 func Encrypt(key, plaintext []byte) (string, error) {
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return "", err
-	}
-	gcm, err := cipher.NewGCM(block)
+	ciphertext, err := seal(key, plaintext, nil)
 	if err != nil {
 		return "", err
 	}
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", err
-	}
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
 	return base64.StdEncoding.EncodeToString(ciphertext), nil
 }
 
@@ -43,6 +53,41 @@ func Decrypt(key []byte, enc string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	return open(key, ciphertext, nil)
+}
+
+// EncryptValue encrypts plaintext for storage in the given encoding.
+func EncryptValue(key, plaintext []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case EncodingBase64, "":
+		enc, err := Encrypt(key, plaintext)
+		return []byte(enc), err
+	case EncodingRaw:
+		ciphertext, err := seal(key, plaintext, nil)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{rawVersion}, ciphertext...), nil
+	default:
+		return nil, fmt.Errorf("unknown ciphertext encoding '%s'", encoding)
+	}
+}
+
+// DecryptValue decrypts a value written by EncryptValue in either encoding, or
+// a structured value written by EncryptFields.
+func DecryptValue(key, stored []byte) ([]byte, error) {
+	if len(stored) > 0 && stored[0] == rawVersion {
+		return open(key, stored[1:], nil)
+	}
+	if len(stored) > 0 && stored[0] == structuredVersion {
+		return decryptFields(key, stored[1:])
+	}
+	return Decrypt(key, string(stored))
+}
+
+// seal returns the nonce followed by the AES-256-GCM ciphertext of plaintext,
+// authenticating additionalData along with it.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -51,10 +96,29 @@ func Decrypt(key []byte, enc string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	if len(ciphertext) < gcm.NonceSize() {
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, err
 	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts the output of seal, which must have been given the same
+// additionalData.
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrCiphertextTooShort
+	}
 	nonce := ciphertext[:gcm.NonceSize()]
 	ciphertext = ciphertext[gcm.NonceSize():]
-	return gcm.Open(nil, nonce, ciphertext, nil)
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
 }
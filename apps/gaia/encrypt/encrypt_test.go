@@ -2,6 +2,8 @@ package encrypt
 
 import (
 	"bytes"
+	"encoding/base64"
+	"errors"
 	"testing"
 )
 
@@ -66,3 +68,100 @@ func TestDecrypt_MalformedCiphertext(t *testing.T) {
 		t.Error("Decrypt() with malformed ciphertext should have failed, but it did not")
 	}
 }
+
+func TestDecrypt_ShortCiphertext(t *testing.T) {
+	key, _ := DeriveKey([]byte("password"), []byte("salt"))
+	for name, decrypt := range map[string]func() ([]byte, error){
+		"empty":       func() ([]byte, error) { return Decrypt(key, "") },
+		"base64":      func() ([]byte, error) { return Decrypt(key, base64.StdEncoding.EncodeToString([]byte("short"))) },
+		"raw":         func() ([]byte, error) { return DecryptValue(key, []byte{rawVersion, 1, 2}) },
+		"raw no body": func() ([]byte, error) { return DecryptValue(key, []byte{rawVersion}) },
+	} {
+		got, err := decrypt()
+		if !errors.Is(err, ErrCiphertextTooShort) {
+			t.Errorf("%s: error = %v, want ErrCiphertextTooShort", name, err)
+		}
+		if got != nil {
+			t.Errorf("%s: plaintext = %q, want nil", name, got)
+		}
+	}
+}
+
+// nonceTracker fails the test when a nonce is seen twice.
+type nonceTracker map[string]struct{}
+
+func (n nonceTracker) add(tb testing.TB, ciphertext []byte) {
+	tb.Helper()
+	nonce := string(ciphertext[:nonceSize])
+	if _, ok := n[nonce]; ok {
+		tb.Fatalf("nonce %x reused after %d encryptions", nonce, len(n))
+	}
+	n[nonce] = struct{}{}
+}
+
+// nonceSize is the size of the standard GCM nonce prepended by seal.
+const nonceSize = 12
+
+func TestEncrypt_UniqueNonces(t *testing.T) {
+	key, _ := DeriveKey([]byte("password"), []byte("salt"))
+	n := 100_000
+	if testing.Short() {
+		n = 10_000
+	}
+	seen := make(nonceTracker, n)
+	for range n {
+		ciphertext, err := seal(key, []byte("same plaintext"), nil)
+		if err != nil {
+			t.Fatalf("seal() error = %v", err)
+		}
+		seen.add(t, ciphertext)
+	}
+}
+
+func BenchmarkEncrypt(b *testing.B) {
+	key, _ := DeriveKey([]byte("password"), []byte("salt"))
+	plaintext := bytes.Repeat([]byte("s3cret"), 10)
+	seen := make(nonceTracker)
+	for b.Loop() {
+		ciphertext, err := seal(key, plaintext, nil)
+		if err != nil {
+			b.Fatalf("seal() error = %v", err)
+		}
+		seen.add(b, ciphertext)
+	}
+}
+
+func TestEncryptValue_Encodings(t *testing.T) {
+	key, _ := DeriveKey([]byte("password"), []byte("salt"))
+	plaintext := bytes.Repeat([]byte("s3cret"), 50)
+
+	b64, err := EncryptValue(key, plaintext, EncodingBase64)
+	if err != nil {
+		t.Fatalf("EncryptValue(base64) error = %v", err)
+	}
+	raw, err := EncryptValue(key, plaintext, EncodingRaw)
+	if err != nil {
+		t.Fatalf("EncryptValue(raw) error = %v", err)
+	}
+	for name, stored := range map[string][]byte{"base64": b64, "raw": raw} {
+		got, err := DecryptValue(key, stored)
+		if err != nil || !bytes.Equal(got, plaintext) {
+			t.Errorf("DecryptValue(%s) = %q, %v; want the plaintext", name, got, err)
+		}
+	}
+	// Values written by Encrypt before encodings existed still decrypt.
+	legacy, _ := Encrypt(key, plaintext)
+	if got, err := DecryptValue(key, []byte(legacy)); err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("DecryptValue(Encrypt output) = %q, %v; want the plaintext", got, err)
+	}
+
+	// Raw values save the base64 overhead, about a quarter of the stored size.
+	t.Logf("stored size: base64 %d bytes, raw %d bytes", len(b64), len(raw))
+	if len(raw)*4 > len(b64)*3+4 {
+		t.Errorf("raw value is %d bytes, want about 3/4 of the %d-byte base64 value", len(raw), len(b64))
+	}
+
+	if _, err := EncryptValue(key, plaintext, "hex"); err == nil {
+		t.Error("EncryptValue() with an unknown encoding succeeded")
+	}
+}
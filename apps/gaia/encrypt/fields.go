@@ -0,0 +1,213 @@
+package encrypt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A structured value is a JSON object of which only some fields are encrypted,
+// so the others can be read and searched without the key. It is stored as
+// structuredVersion followed by a JSON envelope holding the paths of the
+// encrypted fields and the object, in which the value of every encrypted field
+// is replaced by the base64 ciphertext of its JSON encoding. A path names a
+// field by its keys joined with dots, such as "database.password".
+//
+// The plaintext part is authenticated too: every field is sealed with its path
+// and the masked envelope, the paths and the object with every encrypted field
+// set to null, as additional data. Editing a plaintext field or the list of
+// paths makes every field fail to decrypt.
+
+// structuredVersion is the header byte of a structured value. Like rawVersion it
+// never occurs in base64 text.
+const structuredVersion byte = 0x02
+
+var (
+	// ErrNotObject is returned when a value with encrypted fields, or a field on
+	// the path to one, is not a JSON object.
+	ErrNotObject = errors.New("value is not a JSON object")
+	// ErrFieldNotFound is returned when a field to encrypt is not in the value.
+	ErrFieldNotFound = errors.New("field not found")
+)
+
+// structuredEnvelope is the stored form of a structured value.
+type structuredEnvelope struct {
+	Fields []string        `json:"fields"`
+	Doc    json.RawMessage `json:"doc"`
+}
+
+// EncryptFields encrypts the fields of the JSON object plaintext at paths and
+// returns the structured value to store. Every path must name a field of the
+// object. The object is stored compacted, with its keys sorted.
+func EncryptFields(key, plaintext []byte, paths []string) ([]byte, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no fields to encrypt")
+	}
+	masked, err := maskedEnvelope(plaintext, paths)
+	if err != nil {
+		return nil, err
+	}
+	doc := json.RawMessage(plaintext)
+	for _, path := range paths {
+		doc, err = updateField(doc, path, strings.Split(path, "."), func(v json.RawMessage) (json.RawMessage, error) {
+			ciphertext, err := seal(key, v, fieldAdditionalData(path, masked))
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(base64.StdEncoding.EncodeToString(ciphertext))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(structuredEnvelope{Fields: paths, Doc: doc})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{structuredVersion}, data...), nil
+}
+
+// EncryptedFields returns the paths of the encrypted fields of a stored value,
+// or nil if it is not a structured value.
+func EncryptedFields(stored []byte) []string {
+	env, ok := parseStructured(stored)
+	if !ok {
+		return nil
+	}
+	return env.Fields
+}
+
+// PlainField returns the field at path of a structured value without
+// decrypting it: the text of a JSON string, or the JSON encoding of any other
+// value. It reports false for values that are not structured, for missing
+// fields and for encrypted fields and their children.
+func PlainField(stored []byte, path string) (string, bool) {
+	env, ok := parseStructured(stored)
+	if !ok {
+		return "", false
+	}
+	for _, encrypted := range env.Fields {
+		if path == encrypted || strings.HasPrefix(path, encrypted+".") {
+			return "", false
+		}
+	}
+	v := env.Doc
+	for _, k := range strings.Split(path, ".") {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(v, &obj); err != nil {
+			return "", false
+		}
+		if v, ok = obj[k]; !ok {
+			return "", false
+		}
+	}
+	var s string
+	if err := json.Unmarshal(v, &s); err == nil {
+		return s, true
+	}
+	return string(v), true
+}
+
+// decryptFields decrypts a structured value, without its header byte, back to
+// the JSON object.
+func decryptFields(key, stored []byte) ([]byte, error) {
+	var env structuredEnvelope
+	if err := json.Unmarshal(stored, &env); err != nil {
+		return nil, fmt.Errorf("corrupt structured value: %w", err)
+	}
+	// Without paths nothing would be authenticated.
+	if len(env.Fields) == 0 {
+		return nil, errors.New("corrupt structured value: no encrypted fields")
+	}
+	masked, err := maskedEnvelope(env.Doc, env.Fields)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt structured value: %w", err)
+	}
+	doc := env.Doc
+	for i := len(env.Fields) - 1; i >= 0; i-- {
+		path := env.Fields[i]
+		doc, err = updateField(doc, path, strings.Split(path, "."), func(v json.RawMessage) (json.RawMessage, error) {
+			var enc string
+			if err := json.Unmarshal(v, &enc); err != nil {
+				return nil, fmt.Errorf("encrypted field '%s' is not a string", path)
+			}
+			ciphertext, err := base64.StdEncoding.DecodeString(enc)
+			if err != nil {
+				return nil, err
+			}
+			plain, err := open(key, ciphertext, fieldAdditionalData(path, masked))
+			if err != nil {
+				return nil, fmt.Errorf("field '%s' failed to decrypt: %w", path, err)
+			}
+			if !json.Valid(plain) {
+				return nil, fmt.Errorf("decrypted field '%s' is not JSON", path)
+			}
+			return plain, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// maskedEnvelope returns the canonical JSON of the envelope of doc with the
+// fields at paths set to null, which every field is sealed with. The encrypted
+// values are masked because they are not known before sealing.
+func maskedEnvelope(doc []byte, paths []string) ([]byte, error) {
+	masked := json.RawMessage(doc)
+	for _, path := range paths {
+		var err error
+		masked, err = updateField(masked, path, strings.Split(path, "."), func(json.RawMessage) (json.RawMessage, error) {
+			return json.RawMessage("null"), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(structuredEnvelope{Fields: paths, Doc: masked})
+}
+
+// fieldAdditionalData binds the ciphertext of the field at path to its path and
+// to the masked envelope.
+func fieldAdditionalData(path string, masked []byte) []byte {
+	return append([]byte(path+"\x00"), masked...)
+}
+
+// parseStructured decodes the envelope of a structured value.
+func parseStructured(stored []byte) (structuredEnvelope, bool) {
+	var env structuredEnvelope
+	if len(stored) == 0 || stored[0] != structuredVersion {
+		return env, false
+	}
+	if err := json.Unmarshal(stored[1:], &env); err != nil {
+		return env, false
+	}
+	return env, true
+}
+
+// updateField replaces the field of the JSON object doc at keys with the result
+// of fn and returns the updated object. path is the full path, for errors.
+func updateField(doc json.RawMessage, path string, keys []string, fn func(json.RawMessage) (json.RawMessage, error)) (json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &obj); err != nil || obj == nil {
+		return nil, fmt.Errorf("%w: cannot reach field '%s'", ErrNotObject, path)
+	}
+	v, ok := obj[keys[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrFieldNotFound, path)
+	}
+	var err error
+	if len(keys) == 1 {
+		v, err = fn(v)
+	} else {
+		v, err = updateField(v, path, keys[1:], fn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	obj[keys[0]] = v
+	return json.Marshal(obj)
+}
@@ -0,0 +1,112 @@
+package encrypt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestEncryptFields_Roundtrip(t *testing.T) {
+	key, _ := DeriveKey([]byte("password"), []byte("salt"))
+	// Compact with sorted keys, the form the object is stored in.
+	plaintext := []byte(`{"database":{"host":"db.internal","password":"hunter2","port":5432},"env":"prod"}`)
+
+	stored, err := EncryptFields(key, plaintext, []string{"database.password"})
+	if err != nil {
+		t.Fatalf("EncryptFields() error = %v", err)
+	}
+	if bytes.Contains(stored, []byte("hunter2")) {
+		t.Errorf("stored value holds the plaintext of the encrypted field: %s", stored)
+	}
+	got, err := DecryptValue(key, stored)
+	if err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("DecryptValue() = %s, %v; want %s", got, err, plaintext)
+	}
+	if fields := EncryptedFields(stored); !slices.Equal(fields, []string{"database.password"}) {
+		t.Errorf("EncryptedFields() = %q", fields)
+	}
+
+	// The other fields are readable without the key.
+	for path, want := range map[string]string{"env": "prod", "database.host": "db.internal", "database.port": "5432"} {
+		if v, ok := PlainField(stored, path); !ok || v != want {
+			t.Errorf("PlainField(%s) = %q, %v; want %q", path, v, ok, want)
+		}
+	}
+	for _, path := range []string{"database.password", "database.missing", "env.x"} {
+		if v, ok := PlainField(stored, path); ok {
+			t.Errorf("PlainField(%s) = %q, want not found", path, v)
+		}
+	}
+
+	other, _ := DeriveKey([]byte("other"), []byte("salt"))
+	if _, err := DecryptValue(other, stored); err == nil {
+		t.Error("DecryptValue() with the wrong key succeeded")
+	}
+}
+
+func TestEncryptFields_Errors(t *testing.T) {
+	key, _ := DeriveKey([]byte("password"), []byte("salt"))
+	for _, tt := range []struct {
+		name      string
+		plaintext string
+		paths     []string
+		want      error
+	}{
+		{"not an object", `["a"]`, []string{"a"}, ErrNotObject},
+		{"missing field", `{"a":1}`, []string{"b"}, ErrFieldNotFound},
+		{"through a scalar", `{"a":1}`, []string{"a.b"}, ErrNotObject},
+		{"overlapping paths", `{"a":{"b":1}}`, []string{"a", "a.b"}, ErrNotObject},
+	} {
+		if _, err := EncryptFields(key, []byte(tt.plaintext), tt.paths); !errors.Is(err, tt.want) {
+			t.Errorf("EncryptFields(%s) error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// Values that are not structured have no fields.
+	b64, _ := EncryptValue(key, []byte(`{"a":1}`), EncodingBase64)
+	if fields := EncryptedFields(b64); fields != nil {
+		t.Errorf("EncryptedFields() of a whole encrypted value = %q, want nil", fields)
+	}
+	if _, ok := PlainField(b64, "a"); ok {
+		t.Error("PlainField() of a whole encrypted value found a field")
+	}
+}
+
+func TestEncryptFields_Tampered(t *testing.T) {
+	key, _ := DeriveKey([]byte("password"), []byte("salt"))
+	plaintext := []byte(`{"env":"prod","password":"hunter2","token":"abc"}`)
+	stored, err := EncryptFields(key, plaintext, []string{"password", "token"})
+	if err != nil {
+		t.Fatalf("EncryptFields() error = %v", err)
+	}
+	env, _ := parseStructured(stored)
+
+	for name, edit := range map[string]func(e *structuredEnvelope){
+		"plaintext field": func(e *structuredEnvelope) {
+			e.Doc = bytes.Replace(e.Doc, []byte(`"prod"`), []byte(`"dev"`), 1)
+		},
+		"path dropped": func(e *structuredEnvelope) {
+			e.Fields = e.Fields[:1]
+		},
+		"no paths": func(e *structuredEnvelope) {
+			e.Fields = nil
+		},
+		"ciphertexts swapped": func(e *structuredEnvelope) {
+			var doc map[string]json.RawMessage
+			_ = json.Unmarshal(e.Doc, &doc)
+			doc["password"], doc["token"] = doc["token"], doc["password"]
+			e.Doc, _ = json.Marshal(doc)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tampered := structuredEnvelope{Fields: slices.Clone(env.Fields), Doc: slices.Clone(env.Doc)}
+			edit(&tampered)
+			data, _ := json.Marshal(tampered)
+			if got, err := DecryptValue(key, append([]byte{structuredVersion}, data...)); err == nil {
+				t.Errorf("DecryptValue() of a tampered value = %s, want an error", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package encrypt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	sealedBundleVersion = 1
+	sealedBundleAlg     = "RSA-OAEP-256+A256GCM"
+)
+
+// sealedBundle is the on-disk envelope of a sealed export. The payload is encrypted
+// with a random AES-256-GCM key, which is in turn encrypted to the recipient's RSA
+// public key with OAEP (SHA-256).
+type sealedBundle struct {
+	Version    int    `json:"version"`
+	Alg        string `json:"alg"`
+	Key        string `json:"key"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// Seal encrypts plaintext to the given RSA public key and returns a JSON envelope.
+func Seal(pub *rsa.PublicKey, plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, KeyLen)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	defer wipe(dataKey)
+
+	ciphertext, err := Encrypt(dataKey, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt bundle: %w", err)
+	}
+
+	wrappedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, dataKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap bundle key: %w", err)
+	}
+
+	return json.MarshalIndent(sealedBundle{
+		Version:    sealedBundleVersion,
+		Alg:        sealedBundleAlg,
+		Key:        base64.StdEncoding.EncodeToString(wrappedKey),
+		Ciphertext: ciphertext,
+	}, "", "  ")
+}
+
+// Unseal decrypts an envelope produced by Seal with the matching RSA private key.
+func Unseal(priv *rsa.PrivateKey, sealed []byte) ([]byte, error) {
+	var bundle sealedBundle
+	if err := json.Unmarshal(sealed, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse sealed bundle: %w", err)
+	}
+	if bundle.Version != sealedBundleVersion || bundle.Alg != sealedBundleAlg {
+		return nil, fmt.Errorf("unsupported sealed bundle (version %d, alg %q)", bundle.Version, bundle.Alg)
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(bundle.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bundle key: %w", err)
+	}
+	dataKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv, wrappedKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap bundle key: %w", err)
+	}
+	defer wipe(dataKey)
+
+	plaintext, err := Decrypt(dataKey, bundle.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt bundle: %w", err)
+	}
+	return plaintext, nil
+}
+
+// LoadRSAPublicKey reads an RSA public key from a PEM file. PKIX and PKCS#1 public
+// keys are accepted, as well as a certificate whose key is RSA.
+func LoadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode public key PEM")
+	}
+
+	var key any
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported public key PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an RSA key")
+	}
+	return pub, nil
+}
+
+// LoadRSAPrivateKey reads an RSA private key in PKCS#1 or PKCS#8 PEM form.
+func LoadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode private key PEM")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		priv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("private key is not an RSA key")
+		}
+		return priv, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key PEM type %q", block.Type)
+	}
+}
+
+// wipe zeroes a buffer holding key material.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
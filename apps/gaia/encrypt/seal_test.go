@@ -0,0 +1,45 @@
+package encrypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+)
+
+func TestSealUnseal_Roundtrip(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	plaintext := []byte(`{"app":{"prod":{"db":"postgres://..."}}}`)
+
+	sealed, err := Seal(&priv.PublicKey, plaintext)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if bytes.Contains(sealed, []byte("postgres")) {
+		t.Fatal("Seal() output contains plaintext")
+	}
+
+	opened, err := Unseal(priv, sealed)
+	if err != nil {
+		t.Fatalf("Unseal() error = %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("Unseal() = %s, want %s", opened, plaintext)
+	}
+}
+
+func TestUnseal_WrongKey(t *testing.T) {
+	priv1, _ := rsa.GenerateKey(rand.Reader, 2048)
+	priv2, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	sealed, err := Seal(&priv1.PublicKey, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if _, err := Unseal(priv2, sealed); err == nil {
+		t.Error("Unseal() with wrong key should have failed, but it did not")
+	}
+}
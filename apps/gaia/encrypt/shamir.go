@@ -0,0 +1,120 @@
+package encrypt
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// Shamir secret sharing over GF(2^8), with the AES reduction polynomial
+// x^8 + x^4 + x^3 + x + 1. Every byte of the secret is the constant term of its
+// own random polynomial of degree threshold-1. A share is its x coordinate
+// followed by the value of each polynomial at x.
+
+var gfExp, gfLog [256]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfLog[x] = byte(i)
+		// Multiply by the generator 3.
+		hi := x & 0x80
+		x ^= x << 1
+		if hi != 0 {
+			x ^= 0x1b
+		}
+	}
+	gfExp[255] = gfExp[0]
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[(int(gfLog[a])+int(gfLog[b]))%255]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[(int(gfLog[a])+255-int(gfLog[b]))%255]
+}
+
+// ErrInvalidShares is returned by CombineShares for shares that cannot belong
+// to the same split.
+var ErrInvalidShares = errors.New("invalid key shares")
+
+// SplitSecret splits secret into n shares, any threshold of which recover it
+// with CombineShares. Fewer shares reveal nothing about the secret.
+func SplitSecret(secret []byte, n, threshold int) ([][]byte, error) {
+	if threshold < 2 || threshold > n || n > 255 {
+		return nil, fmt.Errorf("invalid split of %d shares with threshold %d: want 2 <= threshold <= shares <= 255", n, threshold)
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("cannot split an empty secret")
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][0] = byte(i + 1)
+	}
+	coeffs := make([]byte, threshold)
+	defer wipe(coeffs)
+	for j, b := range secret {
+		coeffs[0] = b
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, err
+		}
+		for _, share := range shares {
+			// Horner's rule, from the highest coefficient down.
+			x, y := share[0], byte(0)
+			for k := threshold - 1; k >= 0; k-- {
+				y = gfMul(y, x) ^ coeffs[k]
+			}
+			share[j+1] = y
+		}
+	}
+	return shares, nil
+}
+
+// CombineShares recovers the secret from shares made by SplitSecret. Given
+// fewer shares than the threshold it returns a wrong secret, so the result must
+// be checked by the caller.
+func CombineShares(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("%w: need at least 2 shares", ErrInvalidShares)
+	}
+	size := len(shares[0])
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if len(share) != size || size < 2 || share[0] == 0 {
+			return nil, fmt.Errorf("%w: malformed share", ErrInvalidShares)
+		}
+		if seen[share[0]] {
+			return nil, fmt.Errorf("%w: duplicate share %d", ErrInvalidShares, share[0])
+		}
+		seen[share[0]] = true
+	}
+
+	secret := make([]byte, size-1)
+	for j := range secret {
+		// Lagrange interpolation at x = 0.
+		var y byte
+		for i, si := range shares {
+			num, den := byte(1), byte(1)
+			for k, sk := range shares {
+				if k == i {
+					continue
+				}
+				num = gfMul(num, sk[0])
+				den = gfMul(den, si[0]^sk[0])
+			}
+			y ^= gfMul(si[j+1], gfDiv(num, den))
+		}
+		secret[j] = y
+	}
+	return secret, nil
+}
@@ -0,0 +1,57 @@
+package encrypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSplitSecret_Combine(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x00, 0x5a, 0xff, 0x13}, 8)
+	shares, err := SplitSecret(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitSecret() error = %v", err)
+	}
+
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var picked [][]byte
+		for _, i := range subset {
+			picked = append(picked, shares[i])
+		}
+		got, err := CombineShares(picked)
+		if err != nil {
+			t.Fatalf("CombineShares(%v) error = %v", subset, err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Errorf("CombineShares(%v) = %x, want %x", subset, got, secret)
+		}
+	}
+
+	got, err := CombineShares(shares[:2])
+	if err != nil {
+		t.Fatalf("CombineShares() with 2 of 3 shares error = %v", err)
+	}
+	if bytes.Equal(got, secret) {
+		t.Error("CombineShares() recovered the secret from fewer shares than the threshold")
+	}
+}
+
+func TestCombineShares_Invalid(t *testing.T) {
+	shares, err := SplitSecret([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("SplitSecret() error = %v", err)
+	}
+	for name, in := range map[string][][]byte{
+		"one share": shares[:1],
+		"duplicate": {shares[0], shares[0]},
+		"size":      {shares[0], shares[1][:3]},
+		"zero x":    {shares[0], append([]byte{0}, shares[1][1:]...)},
+	} {
+		if _, err := CombineShares(in); !errors.Is(err, ErrInvalidShares) {
+			t.Errorf("CombineShares(%s) error = %v, want ErrInvalidShares", name, err)
+		}
+	}
+	if _, err := SplitSecret([]byte("secret"), 3, 4); err == nil {
+		t.Error("SplitSecret() with a threshold above the share count succeeded")
+	}
+}
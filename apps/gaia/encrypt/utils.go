@@ -1,14 +1,69 @@
 package encrypt
 
 import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+
 	passwordvalidator "github.com/wagslane/go-password-validator"
 )
 
+// DefaultCharset is used by RandomString when no charset is given.
+const DefaultCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+const (
+	// MinEntropy is the entropy, in bits, a passphrase needs to pass ValidatePassword.
+	MinEntropy = 60.0
+	// strongEntropy is the entropy from which a passphrase is reported as strong.
+	strongEntropy = 80.0
+)
+
 func ValidatePassword(password string) (bool, error) {
-	minEntropy := 60.0
-	err := passwordvalidator.Validate(password, minEntropy)
+	err := passwordvalidator.Validate(password, MinEntropy)
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
+
+// EstimateEntropy returns the estimated entropy of password in bits, using the same
+// estimate as ValidatePassword.
+func EstimateEntropy(password string) float64 {
+	return passwordvalidator.GetEntropy(password)
+}
+
+// Strength classifies an entropy estimate as "weak" (rejected by ValidatePassword),
+// "fair", or "strong".
+func Strength(entropy float64) string {
+	switch {
+	case entropy < MinEntropy:
+		return "weak"
+	case entropy < strongEntropy:
+		return "fair"
+	default:
+		return "strong"
+	}
+}
+
+// RandomString returns a cryptographically random string of the given length drawn
+// uniformly from charset. An empty charset selects DefaultCharset.
+func RandomString(length int, charset string) (string, error) {
+	if length <= 0 {
+		return "", errors.New("length must be positive")
+	}
+	if charset == "" {
+		charset = DefaultCharset
+	}
+	chars := []rune(charset)
+	max := big.NewInt(int64(len(chars)))
+
+	out := make([]rune, length)
+	for i := range out {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		out[i] = chars[n.Int64()]
+	}
+	return string(out), nil
+}
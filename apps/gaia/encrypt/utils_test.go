@@ -19,3 +19,39 @@ func TestValidatePassword(t *testing.T) {
 		}
 	})
 }
+
+func TestRandomString(t *testing.T) {
+	s, err := RandomString(64, "ab")
+	if err != nil {
+		t.Fatalf("RandomString() error = %v", err)
+	}
+	if len(s) != 64 {
+		t.Errorf("RandomString() length = %d, want 64", len(s))
+	}
+	for _, r := range s {
+		if r != 'a' && r != 'b' {
+			t.Fatalf("RandomString() produced %q outside the charset", r)
+		}
+	}
+
+	if _, err := RandomString(0, ""); err == nil {
+		t.Error("Expected zero length to be rejected")
+	}
+}
+
+func TestEstimateEntropy(t *testing.T) {
+	weak := EstimateEntropy("password")
+	strong := EstimateEntropy("CorrectHorseBatteryStaple123!")
+	if weak >= strong {
+		t.Errorf("Expected weak passphrase entropy (%.1f) to be below strong passphrase entropy (%.1f)", weak, strong)
+	}
+	if got := Strength(weak); got != "weak" {
+		t.Errorf("Strength(%.1f) = %q, want %q", weak, got, "weak")
+	}
+	if got := Strength(strong); got != "strong" {
+		t.Errorf("Strength(%.1f) = %q, want %q", strong, got, "strong")
+	}
+	if got := Strength(MinEntropy); got != "fair" {
+		t.Errorf("Strength(%.1f) = %q, want %q", MinEntropy, got, "fair")
+	}
+}
@@ -0,0 +1,130 @@
+// Package formats checks that secret values are in an expected format, such as
+// a URL or a PEM block, so malformed values are caught when they are written.
+package formats
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Names of the built-in formats.
+const (
+	URL    = "url"
+	JSON   = "json"
+	PEM    = "pem"
+	Base64 = "base64"
+)
+
+// ErrUnknownFormat is returned for a format that is not registered.
+var ErrUnknownFormat = errors.New("unknown secret format")
+
+// Validator checks that a value is in a format. Validate returns an error
+// describing why the value does not parse; it must never include the value.
+type Validator interface {
+	Validate(value string) error
+}
+
+// ValidatorFunc adapts a function to a Validator.
+type ValidatorFunc func(value string) error
+
+// Validate calls f(value).
+func (f ValidatorFunc) Validate(value string) error {
+	return f(value)
+}
+
+var (
+	mu         sync.RWMutex
+	validators = map[string]Validator{
+		URL:    ValidatorFunc(validateURL),
+		JSON:   ValidatorFunc(validateJSON),
+		PEM:    ValidatorFunc(validatePEM),
+		Base64: ValidatorFunc(validateBase64),
+	}
+)
+
+// Register adds a format under name, replacing any format of the same name.
+func Register(name string, v Validator) {
+	mu.Lock()
+	defer mu.Unlock()
+	validators[name] = v
+}
+
+// Names returns the names of the registered formats, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(validators))
+	for name := range validators {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Known reports whether name is a registered format.
+func Known(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := validators[name]
+	return ok
+}
+
+// Validate checks value against the format name.
+func Validate(name, value string) error {
+	mu.RLock()
+	v, ok := validators[name]
+	mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w '%s', want one of %s", ErrUnknownFormat, name, strings.Join(Names(), ", "))
+	}
+	return v.Validate(value)
+}
+
+// validateURL accepts absolute URLs with a host, such as https://example.com.
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return errors.New("not a valid URL")
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return errors.New("not an absolute URL with a scheme and host")
+	}
+	return nil
+}
+
+func validateJSON(value string) error {
+	if !json.Valid([]byte(value)) {
+		return errors.New("not valid JSON")
+	}
+	return nil
+}
+
+// validatePEM accepts one or more PEM blocks with nothing but whitespace around
+// them.
+func validatePEM(value string) error {
+	block, rest := pem.Decode([]byte(value))
+	if block == nil {
+		return errors.New("no PEM block found")
+	}
+	for len(strings.TrimSpace(string(rest))) > 0 {
+		if block, rest = pem.Decode(rest); block == nil {
+			return errors.New("unexpected data after the PEM blocks")
+		}
+	}
+	return nil
+}
+
+// validateBase64 accepts standard base64 with padding.
+func validateBase64(value string) error {
+	if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+		return errors.New("not valid base64")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package formats
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testPEM = `-----BEGIN CERTIFICATE-----
+MIIBszCCAVmgAwIBAgIUQ2FzZSBmb3IgdGVzdGluZyBvbmx5MAoGCCqGSM49BAMC
+-----END CERTIFICATE-----
+`
+
+func TestValidate_BuiltinFormats(t *testing.T) {
+	tests := []struct {
+		format  string
+		valid   []string
+		invalid []string
+	}{
+		{
+			format:  URL,
+			valid:   []string{"https://example.com", "postgres://user:pw@db.internal:5432/app?sslmode=require"},
+			invalid: []string{"", "example.com", "/relative/path", "https://", "http://[::1"},
+		},
+		{
+			format:  JSON,
+			valid:   []string{`{"user":"app","port":5432}`, `[1,2,3]`, `"text"`},
+			invalid: []string{"", `{"user":}`, `{'single':'quotes'}`},
+		},
+		{
+			format:  PEM,
+			valid:   []string{testPEM, testPEM + "\n" + testPEM, "  \n" + testPEM},
+			invalid: []string{"", "not pem", testPEM + "trailing garbage"},
+		},
+		{
+			format:  Base64,
+			valid:   []string{"", "c2VjcmV0", "c2VjcmV0IQ=="},
+			invalid: []string{"not base64!", "c2VjcmV0IQ", "c2VjcmV0IQ="},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			for _, v := range tt.valid {
+				if err := Validate(tt.format, v); err != nil {
+					t.Errorf("Validate(%q) error = %v, want nil", v, err)
+				}
+			}
+			for _, v := range tt.invalid {
+				if err := Validate(tt.format, v); err == nil {
+					t.Errorf("Validate(%q) succeeded, want an error", v)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_UnknownFormat(t *testing.T) {
+	err := Validate("yaml", "a: b")
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("Validate() of an unknown format error = %v, want ErrUnknownFormat", err)
+	}
+	if !strings.Contains(err.Error(), "url") {
+		t.Errorf("error %q does not list the known formats", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("hex", ValidatorFunc(func(value string) error {
+		if strings.Trim(value, "0123456789abcdef") != "" {
+			return errors.New("not hex")
+		}
+		return nil
+	}))
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(validators, "hex")
+		mu.Unlock()
+	})
+
+	if !Known("hex") || !slices.Contains(Names(), "hex") {
+		t.Fatalf("registered format missing from Names() = %v", Names())
+	}
+	if err := Validate("hex", "deadbeef"); err != nil {
+		t.Errorf("Validate(hex) error = %v", err)
+	}
+	if err := Validate("hex", "xyz"); err == nil {
+		t.Error("Validate(hex) of a non-hex value succeeded")
+	}
+}
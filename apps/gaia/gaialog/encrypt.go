@@ -0,0 +1,140 @@
+package gaialog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+)
+
+// encryptedLine is how an encrypted log line is written. Salt is the KDF salt of
+// the database, so the line can be decrypted with the master passphrase alone.
+type encryptedLine struct {
+	Data string `json:"gaia_encrypted"`
+	Salt string `json:"salt"`
+}
+
+// ErrAuditKey is returned by DecryptLog when a line does not decrypt with the
+// given passphrase.
+var ErrAuditKey = errors.New("audit log line does not decrypt with this passphrase")
+
+// lineEncrypter encrypts every line written to it while a key is set and
+// passes lines through unchanged otherwise. The JSON handler writes one whole
+// line per Write.
+type lineEncrypter struct {
+	mu   sync.Mutex
+	out  io.Writer
+	key  []byte
+	salt string
+}
+
+func (e *lineEncrypter) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.key == nil {
+		return e.out.Write(p)
+	}
+	data, err := encrypt.Encrypt(e.key, bytes.TrimSuffix(p, []byte("\n")))
+	if err != nil {
+		return 0, err
+	}
+	line, err := json.Marshal(encryptedLine{Data: data, Salt: e.salt})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := e.out.Write(append(line, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// reset writes to out from now on, in plaintext.
+func (e *lineEncrypter) reset(out io.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	wipe(e.key)
+	e.key = nil
+	e.out = out
+}
+
+func (e *lineEncrypter) setKey(key, salt []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	wipe(e.key)
+	e.key = nil
+	if key != nil {
+		e.key = bytes.Clone(key)
+		e.salt = base64.StdEncoding.EncodeToString(salt)
+	}
+}
+
+// SetEncryptionKey encrypts every following log line with key, the master key
+// derived from the passphrase with salt.
+func SetEncryptionKey(key, salt []byte) {
+	encrypter.setKey(key, salt)
+}
+
+// ClearEncryptionKey wipes the log encryption key; following lines are written
+// in plaintext.
+func ClearEncryptionKey() {
+	encrypter.setKey(nil, nil)
+}
+
+// DecryptLog copies the log read from r to w, decrypting every encrypted line
+// with the key derived from passphrase. Plaintext lines are copied unchanged.
+func DecryptLog(r io.Reader, w io.Writer, passphrase []byte) error {
+	keys := make(map[string][]byte) // Derived keys by salt
+	defer func() {
+		for _, key := range keys {
+			wipe(key)
+		}
+	}()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Bytes()
+		var enc encryptedLine
+		if json.Unmarshal(line, &enc) != nil || enc.Data == "" {
+			if _, err := fmt.Fprintf(w, "%s\n", line); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key, ok := keys[enc.Salt]
+		if !ok {
+			salt, err := base64.StdEncoding.DecodeString(enc.Salt)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid salt: %w", n, err)
+			}
+			if key, err = encrypt.DeriveKey(passphrase, salt); err != nil {
+				return err
+			}
+			keys[enc.Salt] = key
+		}
+		plain, err := encrypt.Decrypt(key, enc.Data)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, ErrAuditKey)
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", plain); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,58 @@
+package gaialog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+)
+
+func TestDecryptLog_RoundTrip(t *testing.T) {
+	const passphrase = "correct horse battery staple"
+	salt := []byte("0123456789abcdef")
+	key, err := encrypt.DeriveKey([]byte(passphrase), salt)
+	if err != nil {
+		t.Fatalf("DeriveKey() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	e := &lineEncrypter{out: &out}
+	logger := slog.New(slog.NewJSONHandler(e, nil))
+
+	logger.Info("daemon started")
+	e.setKey(key, salt)
+	logger.Info("secret read", slog.String("client_name", "billing"), slog.String("id", "stripe_api_key"))
+	e.setKey(nil, nil)
+	logger.Info("daemon locked")
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("log has %d lines, want 3:\n%s", len(lines), out.String())
+	}
+	if strings.Contains(lines[1], "billing") || strings.Contains(lines[1], "stripe_api_key") || !strings.Contains(lines[1], "gaia_encrypted") {
+		t.Errorf("line written while a key was set is not encrypted: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], "daemon locked") {
+		t.Errorf("line written after the key was cleared is not plaintext: %s", lines[2])
+	}
+
+	var decrypted bytes.Buffer
+	if err := DecryptLog(strings.NewReader(out.String()), &decrypted, []byte(passphrase)); err != nil {
+		t.Fatalf("DecryptLog() error = %v", err)
+	}
+	got := strings.Split(strings.TrimSuffix(decrypted.String(), "\n"), "\n")
+	if len(got) != 3 || got[0] != lines[0] || got[2] != lines[2] {
+		t.Errorf("DecryptLog() changed the plaintext lines:\n%s", decrypted.String())
+	}
+	if !strings.Contains(got[1], `"msg":"secret read"`) || !strings.Contains(got[1], `"client_name":"billing"`) {
+		t.Errorf("DecryptLog() line 2 = %s, want the original entry", got[1])
+	}
+
+	err = DecryptLog(strings.NewReader(out.String()), &bytes.Buffer{}, []byte("wrong passphrase"))
+	if !errors.Is(err, ErrAuditKey) {
+		t.Errorf("DecryptLog() with a wrong passphrase error = %v, want ErrAuditKey", err)
+	}
+}
@@ -11,6 +11,9 @@ import (
 var (
 	logger   *slog.Logger
 	logLevel = &slog.LevelVar{}
+	// encrypter sits between the handler and the output so lines can be
+	// encrypted while the daemon is unlocked.
+	encrypter = &lineEncrypter{}
 )
 
 // Init initializes the global logger with rotation and a default level.
@@ -32,7 +35,8 @@ func Init(level Level, filename string, isProduction bool) {
 
 	logLevel.Set(slogLevel(level))
 
-	handler := slog.NewJSONHandler(output, &slog.HandlerOptions{
+	encrypter.reset(output)
+	handler := slog.NewJSONHandler(encrypter, &slog.HandlerOptions{
 		Level: logLevel,
 	})
 
@@ -44,6 +48,11 @@ func Get() *slog.Logger {
 	return logger
 }
 
+// SetOutput writes following log lines to w.
+func SetOutput(w io.Writer) {
+	encrypter.reset(w)
+}
+
 // SetLevel dynamically changes the log level of the global logger.
 func SetLevel(level Level) {
 	logLevel.Set(slogLevel(level))
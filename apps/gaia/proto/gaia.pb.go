@@ -73,28 +73,31 @@ func (x *Secret) GetValue() string {
 	return ""
 }
 
-type Namespace struct {
+// CreateSecretIfAbsentRequest stores a secret in the caller's own namespace
+// unless it already exists.
+type CreateSecretIfAbsentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Secrets       []*Secret              `protobuf:"bytes,2,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Namespace) Reset() {
-	*x = Namespace{}
+func (x *CreateSecretIfAbsentRequest) Reset() {
+	*x = CreateSecretIfAbsentRequest{}
 	mi := &file_gaia_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Namespace) String() string {
+func (x *CreateSecretIfAbsentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Namespace) ProtoMessage() {}
+func (*CreateSecretIfAbsentRequest) ProtoMessage() {}
 
-func (x *Namespace) ProtoReflect() protoreflect.Message {
+func (x *CreateSecretIfAbsentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -106,49 +109,56 @@ func (x *Namespace) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Namespace.ProtoReflect.Descriptor instead.
-func (*Namespace) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateSecretIfAbsentRequest.ProtoReflect.Descriptor instead.
+func (*CreateSecretIfAbsentRequest) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *Namespace) GetName() string {
+func (x *CreateSecretIfAbsentRequest) GetNamespace() string {
 	if x != nil {
-		return x.Name
+		return x.Namespace
 	}
 	return ""
 }
 
-func (x *Namespace) GetSecrets() []*Secret {
+func (x *CreateSecretIfAbsentRequest) GetId() string {
 	if x != nil {
-		return x.Secrets
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-type AddSecretRequest struct {
+func (x *CreateSecretIfAbsentRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// CreateSecretIfAbsentResponse carries the stored value, which is the existing
+// one when another caller created the secret first.
+type CreateSecretIfAbsentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
-	ClientName    string                 `protobuf:"bytes,4,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"` // Add this field for the admin
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Created       bool                   `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddSecretRequest) Reset() {
-	*x = AddSecretRequest{}
+func (x *CreateSecretIfAbsentResponse) Reset() {
+	*x = CreateSecretIfAbsentResponse{}
 	mi := &file_gaia_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddSecretRequest) String() string {
+func (x *CreateSecretIfAbsentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddSecretRequest) ProtoMessage() {}
+func (*CreateSecretIfAbsentResponse) ProtoMessage() {}
 
-func (x *AddSecretRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateSecretIfAbsentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -160,61 +170,52 @@ func (x *AddSecretRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddSecretRequest.ProtoReflect.Descriptor instead.
-func (*AddSecretRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateSecretIfAbsentResponse.ProtoReflect.Descriptor instead.
+func (*CreateSecretIfAbsentResponse) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *AddSecretRequest) GetNamespace() string {
-	if x != nil {
-		return x.Namespace
-	}
-	return ""
-}
-
-func (x *AddSecretRequest) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *AddSecretRequest) GetValue() string {
+func (x *CreateSecretIfAbsentResponse) GetValue() string {
 	if x != nil {
 		return x.Value
 	}
 	return ""
 }
 
-func (x *AddSecretRequest) GetClientName() string {
+func (x *CreateSecretIfAbsentResponse) GetCreated() bool {
 	if x != nil {
-		return x.ClientName
+		return x.Created
 	}
-	return ""
+	return false
 }
 
-type AddSecretResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+// ListOwnSecretsRequest pages through the secrets of one of the caller's own
+// namespaces in id order.
+type ListOwnSecretsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// page_size defaults to 100 and is capped at 1000.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous response.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddSecretResponse) Reset() {
-	*x = AddSecretResponse{}
+func (x *ListOwnSecretsRequest) Reset() {
+	*x = ListOwnSecretsRequest{}
 	mi := &file_gaia_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddSecretResponse) String() string {
+func (x *ListOwnSecretsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddSecretResponse) ProtoMessage() {}
+func (*ListOwnSecretsRequest) ProtoMessage() {}
 
-func (x *AddSecretResponse) ProtoReflect() protoreflect.Message {
+func (x *ListOwnSecretsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -226,47 +227,55 @@ func (x *AddSecretResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddSecretResponse.ProtoReflect.Descriptor instead.
-func (*AddSecretResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListOwnSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ListOwnSecretsRequest) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *AddSecretResponse) GetSuccess() bool {
+func (x *ListOwnSecretsRequest) GetNamespace() string {
 	if x != nil {
-		return x.Success
+		return x.Namespace
 	}
-	return false
+	return ""
 }
 
-func (x *AddSecretResponse) GetMessage() string {
+func (x *ListOwnSecretsRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.Message
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOwnSecretsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
 	}
 	return ""
 }
 
-type GetSecretRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"` // Change from just 'id'
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+type ListOwnSecretsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Secrets []*Secret              `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	// next_page_token is empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSecretRequest) Reset() {
-	*x = GetSecretRequest{}
+func (x *ListOwnSecretsResponse) Reset() {
+	*x = ListOwnSecretsResponse{}
 	mi := &file_gaia_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSecretRequest) String() string {
+func (x *ListOwnSecretsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSecretRequest) ProtoMessage() {}
+func (*ListOwnSecretsResponse) ProtoMessage() {}
 
-func (x *GetSecretRequest) ProtoReflect() protoreflect.Message {
+func (x *ListOwnSecretsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -278,45 +287,52 @@ func (x *GetSecretRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSecretRequest.ProtoReflect.Descriptor instead.
-func (*GetSecretRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListOwnSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ListOwnSecretsResponse) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *GetSecretRequest) GetNamespace() string {
+func (x *ListOwnSecretsResponse) GetSecrets() []*Secret {
 	if x != nil {
-		return x.Namespace
+		return x.Secrets
 	}
-	return ""
+	return nil
 }
 
-func (x *GetSecretRequest) GetId() string {
+func (x *ListOwnSecretsResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Id
+		return x.NextPageToken
 	}
 	return ""
 }
 
-type GetStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+// ListOwnSecretIdsRequest pages through the ids of the secrets in one of the
+// caller's own namespaces, without their values.
+type ListOwnSecretIdsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// page_size defaults to 100 and is capped at 1000.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous response.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatusRequest) Reset() {
-	*x = GetStatusRequest{}
+func (x *ListOwnSecretIdsRequest) Reset() {
+	*x = ListOwnSecretIdsRequest{}
 	mi := &file_gaia_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatusRequest) String() string {
+func (x *ListOwnSecretIdsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatusRequest) ProtoMessage() {}
+func (*ListOwnSecretIdsRequest) ProtoMessage() {}
 
-func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *ListOwnSecretIdsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -328,32 +344,55 @@ func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListOwnSecretIdsRequest.ProtoReflect.Descriptor instead.
+func (*ListOwnSecretIdsRequest) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{5}
 }
 
-type GetStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+func (x *ListOwnSecretIdsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ListOwnSecretIdsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOwnSecretIdsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListOwnSecretIdsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ids   []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// next_page_token is empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatusResponse) Reset() {
-	*x = GetStatusResponse{}
+func (x *ListOwnSecretIdsResponse) Reset() {
+	*x = ListOwnSecretIdsResponse{}
 	mi := &file_gaia_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatusResponse) String() string {
+func (x *ListOwnSecretIdsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatusResponse) ProtoMessage() {}
+func (*ListOwnSecretIdsResponse) ProtoMessage() {}
 
-func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
+func (x *ListOwnSecretIdsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -365,38 +404,47 @@ func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListOwnSecretIdsResponse.ProtoReflect.Descriptor instead.
+func (*ListOwnSecretIdsResponse) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetStatusResponse) GetStatus() string {
+func (x *ListOwnSecretIdsResponse) GetIds() []string {
 	if x != nil {
-		return x.Status
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *ListOwnSecretIdsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
 	}
 	return ""
 }
 
-type StopRequest struct {
+type Namespace struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Secrets       []*Secret              `protobuf:"bytes,2,rep,name=secrets,proto3" json:"secrets,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StopRequest) Reset() {
-	*x = StopRequest{}
+func (x *Namespace) Reset() {
+	*x = Namespace{}
 	mi := &file_gaia_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopRequest) String() string {
+func (x *Namespace) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopRequest) ProtoMessage() {}
+func (*Namespace) ProtoMessage() {}
 
-func (x *StopRequest) ProtoReflect() protoreflect.Message {
+func (x *Namespace) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -408,32 +456,62 @@ func (x *StopRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
-func (*StopRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Namespace.ProtoReflect.Descriptor instead.
+func (*Namespace) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{7}
 }
 
-type StopResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+func (x *Namespace) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Namespace) GetSecrets() []*Secret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+type AddSecretRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Namespace  string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Id         string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Value      string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	ClientName string                 `protobuf:"bytes,4,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"` // Add this field for the admin
+	// ttl_seconds makes the secret expire that many seconds after this write.
+	// Zero applies the default TTL of the namespace, if it has one.
+	TtlSeconds int64 `protobuf:"varint,5,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	// format sets the format constraint of the secret, such as "url" or "json",
+	// enforced on this and every later write. "none" removes it; empty keeps it.
+	Format string `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`
+	// tags replace the tags of the secret, such as {"lifetime": "temporary"}.
+	// Empty keeps the current tags.
+	Tags map[string]string `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// encrypt_fields stores a JSON object value with only the fields at these
+	// dot-separated paths encrypted, such as "database.password", so the others
+	// can be searched. Empty keeps the encrypted fields of a structured secret.
+	EncryptFields []string `protobuf:"bytes,8,rep,name=encrypt_fields,json=encryptFields,proto3" json:"encrypt_fields,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StopResponse) Reset() {
-	*x = StopResponse{}
+func (x *AddSecretRequest) Reset() {
+	*x = AddSecretRequest{}
 	mi := &file_gaia_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopResponse) String() string {
+func (x *AddSecretRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopResponse) ProtoMessage() {}
+func (*AddSecretRequest) ProtoMessage() {}
 
-func (x *StopResponse) ProtoReflect() protoreflect.Message {
+func (x *AddSecretRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -445,39 +523,92 @@ func (x *StopResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopResponse.ProtoReflect.Descriptor instead.
-func (*StopResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddSecretRequest.ProtoReflect.Descriptor instead.
+func (*AddSecretRequest) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *StopResponse) GetSuccess() bool {
+func (x *AddSecretRequest) GetNamespace() string {
 	if x != nil {
-		return x.Success
+		return x.Namespace
 	}
-	return false
+	return ""
 }
 
-type UnlockRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Passphrase    string                 `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+func (x *AddSecretRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddSecretRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *AddSecretRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *AddSecretRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *AddSecretRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *AddSecretRequest) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AddSecretRequest) GetEncryptFields() []string {
+	if x != nil {
+		return x.EncryptFields
+	}
+	return nil
+}
+
+type AddSecretResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Problems that did not stop the write, such as a namespace the client
+	// cannot read or a value matching a warn value rule.
+	Warnings      []string `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UnlockRequest) Reset() {
-	*x = UnlockRequest{}
+func (x *AddSecretResponse) Reset() {
+	*x = AddSecretResponse{}
 	mi := &file_gaia_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UnlockRequest) String() string {
+func (x *AddSecretResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UnlockRequest) ProtoMessage() {}
+func (*AddSecretResponse) ProtoMessage() {}
 
-func (x *UnlockRequest) ProtoReflect() protoreflect.Message {
+func (x *AddSecretResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -489,39 +620,54 @@ func (x *UnlockRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UnlockRequest.ProtoReflect.Descriptor instead.
-func (*UnlockRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddSecretResponse.ProtoReflect.Descriptor instead.
+func (*AddSecretResponse) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UnlockRequest) GetPassphrase() string {
+func (x *AddSecretResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Passphrase
+		return x.Success
+	}
+	return false
+}
+
+func (x *AddSecretResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-type UnlockResponse struct {
+func (x *AddSecretResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type GetSecretRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"` // Change from just 'id'
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UnlockResponse) Reset() {
-	*x = UnlockResponse{}
+func (x *GetSecretRequest) Reset() {
+	*x = GetSecretRequest{}
 	mi := &file_gaia_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UnlockResponse) String() string {
+func (x *GetSecretRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UnlockResponse) ProtoMessage() {}
+func (*GetSecretRequest) ProtoMessage() {}
 
-func (x *UnlockResponse) ProtoReflect() protoreflect.Message {
+func (x *GetSecretRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -533,38 +679,45 @@ func (x *UnlockResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UnlockResponse.ProtoReflect.Descriptor instead.
-func (*UnlockResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetSecretRequest.ProtoReflect.Descriptor instead.
+func (*GetSecretRequest) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *UnlockResponse) GetSuccess() bool {
+func (x *GetSecretRequest) GetNamespace() string {
 	if x != nil {
-		return x.Success
+		return x.Namespace
 	}
-	return false
+	return ""
 }
 
-type LockRequest struct {
+func (x *GetSecretRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LockRequest) Reset() {
-	*x = LockRequest{}
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
 	mi := &file_gaia_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LockRequest) String() string {
+func (x *GetStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LockRequest) ProtoMessage() {}
+func (*GetStatusRequest) ProtoMessage() {}
 
-func (x *LockRequest) ProtoReflect() protoreflect.Message {
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -576,32 +729,50 @@ func (x *LockRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LockRequest.ProtoReflect.Descriptor instead.
-func (*LockRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{11}
 }
 
-type LockResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+type GetStatusResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// locked is true while the database is locked and admin RPCs are refused.
+	Locked bool `protobuf:"varint,2,opt,name=locked,proto3" json:"locked,omitempty"`
+	// lock_reason says why the daemon was locked: "startup", "manual" or
+	// "shutdown". It is empty while the daemon is unlocked.
+	LockReason string `protobuf:"bytes,3,opt,name=lock_reason,json=lockReason,proto3" json:"lock_reason,omitempty"`
+	// lock_changed is the RFC 3339 time the daemon was last locked or unlocked.
+	LockChanged string `protobuf:"bytes,4,opt,name=lock_changed,json=lockChanged,proto3" json:"lock_changed,omitempty"`
+	// started_at is the RFC 3339 time the daemon started.
+	StartedAt string `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	// uptime_seconds is how long the daemon has been running.
+	UptimeSeconds int64 `protobuf:"varint,6,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	// go_version and os identify the daemon's build for support diagnostics;
+	// os is GOOS/GOARCH.
+	GoVersion string `protobuf:"bytes,7,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	Os        string `protobuf:"bytes,8,opt,name=os,proto3" json:"os,omitempty"`
+	// banner is the configured banner of the daemon, such as its environment or
+	// an operational notice. It is empty when none is set.
+	Banner        string `protobuf:"bytes,9,opt,name=banner,proto3" json:"banner,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LockResponse) Reset() {
-	*x = LockResponse{}
+func (x *GetStatusResponse) Reset() {
+	*x = GetStatusResponse{}
 	mi := &file_gaia_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LockResponse) String() string {
+func (x *GetStatusResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LockResponse) ProtoMessage() {}
+func (*GetStatusResponse) ProtoMessage() {}
 
-func (x *LockResponse) ProtoReflect() protoreflect.Message {
+func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_gaia_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -613,40 +784,3418 @@ func (x *LockResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LockResponse.ProtoReflect.Descriptor instead.
-func (*LockResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
 	return file_gaia_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *LockResponse) GetSuccess() bool {
+func (x *GetStatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *GetStatusResponse) GetLockReason() string {
+	if x != nil {
+		return x.LockReason
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetLockChanged() string {
+	if x != nil {
+		return x.LockChanged
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetBanner() string {
+	if x != nil {
+		return x.Banner
+	}
+	return ""
+}
+
+type GetMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsRequest) Reset() {
+	*x = GetMetricsRequest{}
+	mi := &file_gaia_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsRequest) ProtoMessage() {}
+
+func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{13}
+}
+
+// GetMetricsResponse is a snapshot of the daemon's operational counters since it
+// started.
+type GetMetricsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SecretsServed  uint64                 `protobuf:"varint,1,opt,name=secrets_served,json=secretsServed,proto3" json:"secrets_served,omitempty"`
+	AccessDenied   uint64                 `protobuf:"varint,2,opt,name=access_denied,json=accessDenied,proto3" json:"access_denied,omitempty"`
+	SecretsWritten uint64                 `protobuf:"varint,3,opt,name=secrets_written,json=secretsWritten,proto3" json:"secrets_written,omitempty"`
+	SecretsDeleted uint64                 `protobuf:"varint,4,opt,name=secrets_deleted,json=secretsDeleted,proto3" json:"secrets_deleted,omitempty"`
+	Unlocks        uint64                 `protobuf:"varint,5,opt,name=unlocks,proto3" json:"unlocks,omitempty"`
+	FailedUnlocks  uint64                 `protobuf:"varint,6,opt,name=failed_unlocks,json=failedUnlocks,proto3" json:"failed_unlocks,omitempty"`
+	Locked         bool                   `protobuf:"varint,7,opt,name=locked,proto3" json:"locked,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetMetricsResponse) Reset() {
+	*x = GetMetricsResponse{}
+	mi := &file_gaia_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsResponse) ProtoMessage() {}
+
+func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetMetricsResponse) GetSecretsServed() uint64 {
+	if x != nil {
+		return x.SecretsServed
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetAccessDenied() uint64 {
+	if x != nil {
+		return x.AccessDenied
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetSecretsWritten() uint64 {
+	if x != nil {
+		return x.SecretsWritten
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetSecretsDeleted() uint64 {
+	if x != nil {
+		return x.SecretsDeleted
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetUnlocks() uint64 {
+	if x != nil {
+		return x.Unlocks
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetFailedUnlocks() uint64 {
+	if x != nil {
+		return x.FailedUnlocks
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+type StopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopRequest) Reset() {
+	*x = StopRequest{}
+	mi := &file_gaia_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRequest) ProtoMessage() {}
+
+func (x *StopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
+func (*StopRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{15}
+}
+
+type StopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopResponse) Reset() {
+	*x = StopResponse{}
+	mi := &file_gaia_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopResponse) ProtoMessage() {}
+
+func (x *StopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopResponse.ProtoReflect.Descriptor instead.
+func (*StopResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StopResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type UnlockRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Passphrase string                 `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	// share is a hex-encoded key share, for a database initialized with key
+	// shares. It is sent instead of the passphrase.
+	Share         string `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockRequest) Reset() {
+	*x = UnlockRequest{}
+	mi := &file_gaia_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockRequest) ProtoMessage() {}
+
+func (x *UnlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockRequest.ProtoReflect.Descriptor instead.
+func (*UnlockRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UnlockRequest) GetPassphrase() string {
+	if x != nil {
+		return x.Passphrase
+	}
+	return ""
+}
+
+func (x *UnlockRequest) GetShare() string {
+	if x != nil {
+		return x.Share
+	}
+	return ""
+}
+
+type UnlockResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// already_unlocked is set when the daemon was unlocked before the call, in
+	// which case the passphrase is not checked and nothing changes.
+	AlreadyUnlocked bool `protobuf:"varint,2,opt,name=already_unlocked,json=alreadyUnlocked,proto3" json:"already_unlocked,omitempty"`
+	// shares_collected and shares_required report the progress of an unlock with
+	// key shares; the daemon stays locked until enough shares are submitted.
+	SharesCollected int32 `protobuf:"varint,3,opt,name=shares_collected,json=sharesCollected,proto3" json:"shares_collected,omitempty"`
+	SharesRequired  int32 `protobuf:"varint,4,opt,name=shares_required,json=sharesRequired,proto3" json:"shares_required,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UnlockResponse) Reset() {
+	*x = UnlockResponse{}
+	mi := &file_gaia_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockResponse) ProtoMessage() {}
+
+func (x *UnlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockResponse.ProtoReflect.Descriptor instead.
+func (*UnlockResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UnlockResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UnlockResponse) GetAlreadyUnlocked() bool {
+	if x != nil {
+		return x.AlreadyUnlocked
+	}
+	return false
+}
+
+func (x *UnlockResponse) GetSharesCollected() int32 {
+	if x != nil {
+		return x.SharesCollected
+	}
+	return 0
+}
+
+func (x *UnlockResponse) GetSharesRequired() int32 {
+	if x != nil {
+		return x.SharesRequired
+	}
+	return 0
+}
+
+type LockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockRequest) Reset() {
+	*x = LockRequest{}
+	mi := &file_gaia_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockRequest) ProtoMessage() {}
+
+func (x *LockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockRequest.ProtoReflect.Descriptor instead.
+func (*LockRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{19}
+}
+
+type LockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockResponse) Reset() {
+	*x = LockResponse{}
+	mi := &file_gaia_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockResponse) ProtoMessage() {}
+
+func (x *LockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockResponse.ProtoReflect.Descriptor instead.
+func (*LockResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LockResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RegisterClientRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ClientName string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Reissue    bool                   `protobuf:"varint,2,opt,name=reissue,proto3" json:"reissue,omitempty"` // Issue a new certificate for an already registered client.
+	// Optional certificate fields. When empty, the certificate carries only the
+	// client name as its Common Name.
+	Organization       string   `protobuf:"bytes,3,opt,name=organization,proto3" json:"organization,omitempty"`
+	OrganizationalUnit string   `protobuf:"bytes,4,opt,name=organizational_unit,json=organizationalUnit,proto3" json:"organizational_unit,omitempty"`
+	UriSans            []string `protobuf:"bytes,5,rep,name=uri_sans,json=uriSans,proto3" json:"uri_sans,omitempty"` // Absolute URIs, e.g. spiffe://example.org/gaia/billing.
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *RegisterClientRequest) Reset() {
+	*x = RegisterClientRequest{}
+	mi := &file_gaia_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterClientRequest) ProtoMessage() {}
+
+func (x *RegisterClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterClientRequest.ProtoReflect.Descriptor instead.
+func (*RegisterClientRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RegisterClientRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *RegisterClientRequest) GetReissue() bool {
+	if x != nil {
+		return x.Reissue
+	}
+	return false
+}
+
+func (x *RegisterClientRequest) GetOrganization() string {
+	if x != nil {
+		return x.Organization
+	}
+	return ""
+}
+
+func (x *RegisterClientRequest) GetOrganizationalUnit() string {
+	if x != nil {
+		return x.OrganizationalUnit
+	}
+	return ""
+}
+
+func (x *RegisterClientRequest) GetUriSans() []string {
+	if x != nil {
+		return x.UriSans
+	}
+	return nil
+}
+
+type RegisterClientResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Certificate   string                 `protobuf:"bytes,1,opt,name=certificate,proto3" json:"certificate,omitempty"`                 // PEM-encoded cert
+	PrivateKey    string                 `protobuf:"bytes,2,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"` // PEM-encoded key
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterClientResponse) Reset() {
+	*x = RegisterClientResponse{}
+	mi := &file_gaia_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterClientResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterClientResponse) ProtoMessage() {}
+
+func (x *RegisterClientResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterClientResponse.ProtoReflect.Descriptor instead.
+func (*RegisterClientResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RegisterClientResponse) GetCertificate() string {
+	if x != nil {
+		return x.Certificate
+	}
+	return ""
+}
+
+func (x *RegisterClientResponse) GetPrivateKey() string {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return ""
+}
+
+type Client struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TimeCreated   string                 `protobuf:"bytes,2,opt,name=time_created,json=timeCreated,proto3" json:"time_created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Client) Reset() {
+	*x = Client{}
+	mi := &file_gaia_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Client) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Client) ProtoMessage() {}
+
+func (x *Client) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Client.ProtoReflect.Descriptor instead.
+func (*Client) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Client) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Client) GetTimeCreated() string {
+	if x != nil {
+		return x.TimeCreated
+	}
+	return ""
+}
+
+type ListClientsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListClientsRequest) Reset() {
+	*x = ListClientsRequest{}
+	mi := &file_gaia_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListClientsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClientsRequest) ProtoMessage() {}
+
+func (x *ListClientsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClientsRequest.ProtoReflect.Descriptor instead.
+func (*ListClientsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{24}
+}
+
+type ListClientsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clients       []*Client              `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListClientsResponse) Reset() {
+	*x = ListClientsResponse{}
+	mi := &file_gaia_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListClientsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClientsResponse) ProtoMessage() {}
+
+func (x *ListClientsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClientsResponse.ProtoReflect.Descriptor instead.
+func (*ListClientsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListClientsResponse) GetClients() []*Client {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+type ListNamespacesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNamespacesRequest) Reset() {
+	*x = ListNamespacesRequest{}
+	mi := &file_gaia_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNamespacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNamespacesRequest) ProtoMessage() {}
+
+func (x *ListNamespacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNamespacesRequest.ProtoReflect.Descriptor instead.
+func (*ListNamespacesRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListNamespacesRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+type ListNamespacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespaces    []string               `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNamespacesResponse) Reset() {
+	*x = ListNamespacesResponse{}
+	mi := &file_gaia_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNamespacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNamespacesResponse) ProtoMessage() {}
+
+func (x *ListNamespacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNamespacesResponse.ProtoReflect.Descriptor instead.
+func (*ListNamespacesResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListNamespacesResponse) GetNamespaces() []string {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+type RevokeClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // Report what would be removed without deleting anything.
+	Passphrase    string                 `protobuf:"bytes,3,opt,name=passphrase,proto3" json:"passphrase,omitempty"`        // Master passphrase, required with require_reauth_for_destructive.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeClientRequest) Reset() {
+	*x = RevokeClientRequest{}
+	mi := &file_gaia_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeClientRequest) ProtoMessage() {}
+
+func (x *RevokeClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeClientRequest.ProtoReflect.Descriptor instead.
+func (*RevokeClientRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RevokeClientRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *RevokeClientRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *RevokeClientRequest) GetPassphrase() string {
+	if x != nil {
+		return x.Passphrase
+	}
+	return ""
+}
+
+type RevokeClientResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Namespaces    []string               `protobuf:"bytes,2,rep,name=namespaces,proto3" json:"namespaces,omitempty"`                       // Namespaces holding the client's secrets.
+	SecretCount   int32                  `protobuf:"varint,3,opt,name=secret_count,json=secretCount,proto3" json:"secret_count,omitempty"` // Number of secrets removed, or that would be removed.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeClientResponse) Reset() {
+	*x = RevokeClientResponse{}
+	mi := &file_gaia_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeClientResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeClientResponse) ProtoMessage() {}
+
+func (x *RevokeClientResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeClientResponse.ProtoReflect.Descriptor instead.
+func (*RevokeClientResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RevokeClientResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RevokeClientResponse) GetNamespaces() []string {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+func (x *RevokeClientResponse) GetSecretCount() int32 {
+	if x != nil {
+		return x.SecretCount
+	}
+	return 0
+}
+
+type DeleteSecretRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Passphrase    string                 `protobuf:"bytes,4,opt,name=passphrase,proto3" json:"passphrase,omitempty"` // Master passphrase, required with require_reauth_for_destructive.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSecretRequest) Reset() {
+	*x = DeleteSecretRequest{}
+	mi := &file_gaia_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSecretRequest) ProtoMessage() {}
+
+func (x *DeleteSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSecretRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSecretRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeleteSecretRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *DeleteSecretRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *DeleteSecretRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteSecretRequest) GetPassphrase() string {
+	if x != nil {
+		return x.Passphrase
+	}
+	return ""
+}
+
+type DeleteSecretResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSecretResponse) Reset() {
+	*x = DeleteSecretResponse{}
+	mi := &file_gaia_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSecretResponse) ProtoMessage() {}
+
+func (x *DeleteSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSecretResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSecretResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeleteSecretResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ImportSecretsConfig struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Overwrite bool                   `protobuf:"varint,1,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	// merge only adds secrets that do not exist yet and skips the others. It
+	// cannot be combined with overwrite.
+	Merge bool `protobuf:"varint,2,opt,name=merge,proto3" json:"merge,omitempty"`
+	// batch_id makes the import retryable: a batch ID that was already applied
+	// within the retention window is not imported again.
+	BatchId       string `protobuf:"bytes,3,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportSecretsConfig) Reset() {
+	*x = ImportSecretsConfig{}
+	mi := &file_gaia_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSecretsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSecretsConfig) ProtoMessage() {}
+
+func (x *ImportSecretsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSecretsConfig.ProtoReflect.Descriptor instead.
+func (*ImportSecretsConfig) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ImportSecretsConfig) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+func (x *ImportSecretsConfig) GetMerge() bool {
+	if x != nil {
+		return x.Merge
+	}
+	return false
+}
+
+func (x *ImportSecretsConfig) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+type ImportSecretItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Value         string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportSecretItem) Reset() {
+	*x = ImportSecretItem{}
+	mi := &file_gaia_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSecretItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSecretItem) ProtoMessage() {}
+
+func (x *ImportSecretItem) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSecretItem.ProtoReflect.Descriptor instead.
+func (*ImportSecretItem) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ImportSecretItem) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *ImportSecretItem) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ImportSecretItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportSecretItem) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// Wrapper message for the stream to handle config and items
+type ImportSecretsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ImportSecretsRequest_Config
+	//	*ImportSecretsRequest_Item
+	Payload       isImportSecretsRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportSecretsRequest) Reset() {
+	*x = ImportSecretsRequest{}
+	mi := &file_gaia_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSecretsRequest) ProtoMessage() {}
+
+func (x *ImportSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ImportSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ImportSecretsRequest) GetPayload() isImportSecretsRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ImportSecretsRequest) GetConfig() *ImportSecretsConfig {
+	if x != nil {
+		if x, ok := x.Payload.(*ImportSecretsRequest_Config); ok {
+			return x.Config
+		}
+	}
+	return nil
+}
+
+func (x *ImportSecretsRequest) GetItem() *ImportSecretItem {
+	if x != nil {
+		if x, ok := x.Payload.(*ImportSecretsRequest_Item); ok {
+			return x.Item
+		}
+	}
+	return nil
+}
+
+type isImportSecretsRequest_Payload interface {
+	isImportSecretsRequest_Payload()
+}
+
+type ImportSecretsRequest_Config struct {
+	Config *ImportSecretsConfig `protobuf:"bytes,1,opt,name=config,proto3,oneof"`
+}
+
+type ImportSecretsRequest_Item struct {
+	Item *ImportSecretItem `protobuf:"bytes,2,opt,name=item,proto3,oneof"`
+}
+
+func (*ImportSecretsRequest_Config) isImportSecretsRequest_Payload() {}
+
+func (*ImportSecretsRequest_Item) isImportSecretsRequest_Payload() {}
+
+type ImportSecretsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SecretsImported int32                  `protobuf:"varint,1,opt,name=secrets_imported,json=secretsImported,proto3" json:"secrets_imported,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	SecretsSkipped  int32                  `protobuf:"varint,3,opt,name=secrets_skipped,json=secretsSkipped,proto3" json:"secrets_skipped,omitempty"`
+	Replayed        bool                   `protobuf:"varint,4,opt,name=replayed,proto3" json:"replayed,omitempty"` // The batch ID was already applied; counts are from that import.
+	// Secrets that were written although their value matched a warn value rule.
+	Violations    []*ValueViolation `protobuf:"bytes,5,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportSecretsResponse) Reset() {
+	*x = ImportSecretsResponse{}
+	mi := &file_gaia_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSecretsResponse) ProtoMessage() {}
+
+func (x *ImportSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ImportSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ImportSecretsResponse) GetSecretsImported() int32 {
+	if x != nil {
+		return x.SecretsImported
+	}
+	return 0
+}
+
+func (x *ImportSecretsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ImportSecretsResponse) GetSecretsSkipped() int32 {
+	if x != nil {
+		return x.SecretsSkipped
+	}
+	return 0
+}
+
+func (x *ImportSecretsResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *ImportSecretsResponse) GetViolations() []*ValueViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+// ImportSecretsProgress is streamed by ImportSecretsWithProgress. Progress
+// messages report the number of items received so far; the last message
+// carries the result of the import.
+type ImportSecretsProgress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ImportSecretsProgress_ItemsReceived
+	//	*ImportSecretsProgress_Result
+	Payload       isImportSecretsProgress_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportSecretsProgress) Reset() {
+	*x = ImportSecretsProgress{}
+	mi := &file_gaia_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSecretsProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSecretsProgress) ProtoMessage() {}
+
+func (x *ImportSecretsProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSecretsProgress.ProtoReflect.Descriptor instead.
+func (*ImportSecretsProgress) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ImportSecretsProgress) GetPayload() isImportSecretsProgress_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ImportSecretsProgress) GetItemsReceived() int32 {
+	if x != nil {
+		if x, ok := x.Payload.(*ImportSecretsProgress_ItemsReceived); ok {
+			return x.ItemsReceived
+		}
+	}
+	return 0
+}
+
+func (x *ImportSecretsProgress) GetResult() *ImportSecretsResponse {
+	if x != nil {
+		if x, ok := x.Payload.(*ImportSecretsProgress_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isImportSecretsProgress_Payload interface {
+	isImportSecretsProgress_Payload()
+}
+
+type ImportSecretsProgress_ItemsReceived struct {
+	ItemsReceived int32 `protobuf:"varint,1,opt,name=items_received,json=itemsReceived,proto3,oneof"`
+}
+
+type ImportSecretsProgress_Result struct {
+	Result *ImportSecretsResponse `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*ImportSecretsProgress_ItemsReceived) isImportSecretsProgress_Payload() {}
+
+func (*ImportSecretsProgress_Result) isImportSecretsProgress_Payload() {}
+
+// ValueViolation reports a secret whose value matched one of the daemon's
+// value rules. Values matching a reject rule fail the request instead.
+type ValueViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Rule          string                 `protobuf:"bytes,4,opt,name=rule,proto3" json:"rule,omitempty"`
+	Action        string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"` // "reject" or "warn"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValueViolation) Reset() {
+	*x = ValueViolation{}
+	mi := &file_gaia_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValueViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValueViolation) ProtoMessage() {}
+
+func (x *ValueViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValueViolation.ProtoReflect.Descriptor instead.
+func (*ValueViolation) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ValueViolation) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *ValueViolation) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ValueViolation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ValueViolation) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *ValueViolation) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+// SetSecretsRequest writes a small set of secrets in one transaction: either all
+// of them are written or none is.
+type SetSecretsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Secrets []*ImportSecretItem    `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	// overwrite replaces existing secrets. Without it, an existing secret fails
+	// the whole request.
+	Overwrite     bool `protobuf:"varint,2,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSecretsRequest) Reset() {
+	*x = SetSecretsRequest{}
+	mi := &file_gaia_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSecretsRequest) ProtoMessage() {}
+
+func (x *SetSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSecretsRequest.ProtoReflect.Descriptor instead.
+func (*SetSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SetSecretsRequest) GetSecrets() []*ImportSecretItem {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *SetSecretsRequest) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+type SetSecretsResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	SecretsSet int32                  `protobuf:"varint,1,opt,name=secrets_set,json=secretsSet,proto3" json:"secrets_set,omitempty"`
+	// Number of secrets written to namespaces their client cannot read.
+	Unreachable int32 `protobuf:"varint,2,opt,name=unreachable,proto3" json:"unreachable,omitempty"`
+	// Secrets that were written although their value matched a warn value rule.
+	Violations    []*ValueViolation `protobuf:"bytes,3,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSecretsResponse) Reset() {
+	*x = SetSecretsResponse{}
+	mi := &file_gaia_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSecretsResponse) ProtoMessage() {}
+
+func (x *SetSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSecretsResponse.ProtoReflect.Descriptor instead.
+func (*SetSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SetSecretsResponse) GetSecretsSet() int32 {
+	if x != nil {
+		return x.SecretsSet
+	}
+	return 0
+}
+
+func (x *SetSecretsResponse) GetUnreachable() int32 {
+	if x != nil {
+		return x.Unreachable
+	}
+	return 0
+}
+
+func (x *SetSecretsResponse) GetViolations() []*ValueViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type ListSecretsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespaces    []*Namespace           `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSecretsResponse) Reset() {
+	*x = ListSecretsResponse{}
+	mi := &file_gaia_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSecretsResponse) ProtoMessage() {}
+
+func (x *ListSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ListSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListSecretsResponse) GetNamespaces() []*Namespace {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+type ListSecretsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSecretsRequest) Reset() {
+	*x = ListSecretsRequest{}
+	mi := &file_gaia_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSecretsRequest) ProtoMessage() {}
+
+func (x *ListSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ListSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListSecretsRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+// StreamSecretsResponse carries one namespace of a client's secrets.
+type StreamSecretsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Namespace       *Namespace             `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	TotalNamespaces int32                  `protobuf:"varint,2,opt,name=total_namespaces,json=totalNamespaces,proto3" json:"total_namespaces,omitempty"` // Number of namespaces the stream sends in all.
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StreamSecretsResponse) Reset() {
+	*x = StreamSecretsResponse{}
+	mi := &file_gaia_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSecretsResponse) ProtoMessage() {}
+
+func (x *StreamSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSecretsResponse.ProtoReflect.Descriptor instead.
+func (*StreamSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *StreamSecretsResponse) GetNamespace() *Namespace {
+	if x != nil {
+		return x.Namespace
+	}
+	return nil
+}
+
+func (x *StreamSecretsResponse) GetTotalNamespaces() int32 {
+	if x != nil {
+		return x.TotalNamespaces
+	}
+	return 0
+}
+
+type ExportSecretsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"` // Optional. If empty, secrets of all clients are exported.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportSecretsRequest) Reset() {
+	*x = ExportSecretsRequest{}
+	mi := &file_gaia_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportSecretsRequest) ProtoMessage() {}
+
+func (x *ExportSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ExportSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ExportSecretsRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+type ExportSecretsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ImportSecretItem    `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportSecretsResponse) Reset() {
+	*x = ExportSecretsResponse{}
+	mi := &file_gaia_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportSecretsResponse) ProtoMessage() {}
+
+func (x *ExportSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ExportSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ExportSecretsResponse) GetItems() []*ImportSecretItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type RotateSecretsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Length        int32                  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`  // Length of each generated value. Defaults to 32.
+	Charset       string                 `protobuf:"bytes,4,opt,name=charset,proto3" json:"charset,omitempty"` // Characters to draw from. Defaults to alphanumeric.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateSecretsRequest) Reset() {
+	*x = RotateSecretsRequest{}
+	mi := &file_gaia_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSecretsRequest) ProtoMessage() {}
+
+func (x *RotateSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSecretsRequest.ProtoReflect.Descriptor instead.
+func (*RotateSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RotateSecretsRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *RotateSecretsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *RotateSecretsRequest) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *RotateSecretsRequest) GetCharset() string {
+	if x != nil {
+		return x.Charset
+	}
+	return ""
+}
+
+type RotatedSecret struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OldValue      string                 `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue      string                 `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotatedSecret) Reset() {
+	*x = RotatedSecret{}
+	mi := &file_gaia_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotatedSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotatedSecret) ProtoMessage() {}
+
+func (x *RotatedSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotatedSecret.ProtoReflect.Descriptor instead.
+func (*RotatedSecret) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RotatedSecret) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RotatedSecret) GetOldValue() string {
+	if x != nil {
+		return x.OldValue
+	}
+	return ""
+}
+
+func (x *RotatedSecret) GetNewValue() string {
+	if x != nil {
+		return x.NewValue
+	}
+	return ""
+}
+
+type RotateSecretsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Secrets       []*RotatedSecret       `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateSecretsResponse) Reset() {
+	*x = RotateSecretsResponse{}
+	mi := &file_gaia_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSecretsResponse) ProtoMessage() {}
+
+func (x *RotateSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSecretsResponse.ProtoReflect.Descriptor instead.
+func (*RotateSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RotateSecretsResponse) GetSecrets() []*RotatedSecret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+// Request for getting secrets from the common area.
+type GetCommonSecretsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If provided, returns secrets only for this namespace.
+	// If omitted, returns secrets for all common namespaces the client may read.
+	Namespace     *string `protobuf:"bytes,1,opt,name=namespace,proto3,oneof" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommonSecretsRequest) Reset() {
+	*x = GetCommonSecretsRequest{}
+	mi := &file_gaia_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommonSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommonSecretsRequest) ProtoMessage() {}
+
+func (x *GetCommonSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommonSecretsRequest.ProtoReflect.Descriptor instead.
+func (*GetCommonSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *GetCommonSecretsRequest) GetNamespace() string {
+	if x != nil && x.Namespace != nil {
+		return *x.Namespace
+	}
+	return ""
+}
+
+// Response containing secrets from the common area, grouped by namespace.
+type GetCommonSecretsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespaces    []*Namespace           `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommonSecretsResponse) Reset() {
+	*x = GetCommonSecretsResponse{}
+	mi := &file_gaia_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommonSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommonSecretsResponse) ProtoMessage() {}
+
+func (x *GetCommonSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommonSecretsResponse.ProtoReflect.Descriptor instead.
+func (*GetCommonSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetCommonSecretsResponse) GetNamespaces() []*Namespace {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+// Restricts which common namespaces a client may read. An empty list removes the
+// restriction, so the client can read every common namespace again.
+type SetCommonGrantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespaces    []string               `protobuf:"bytes,2,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCommonGrantsRequest) Reset() {
+	*x = SetCommonGrantsRequest{}
+	mi := &file_gaia_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCommonGrantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCommonGrantsRequest) ProtoMessage() {}
+
+func (x *SetCommonGrantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCommonGrantsRequest.ProtoReflect.Descriptor instead.
+func (*SetCommonGrantsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *SetCommonGrantsRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *SetCommonGrantsRequest) GetNamespaces() []string {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+type SetCommonGrantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCommonGrantsResponse) Reset() {
+	*x = SetCommonGrantsResponse{}
+	mi := &file_gaia_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCommonGrantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCommonGrantsResponse) ProtoMessage() {}
+
+func (x *SetCommonGrantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCommonGrantsResponse.ProtoReflect.Descriptor instead.
+func (*SetCommonGrantsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *SetCommonGrantsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// SetNamespacePatternsRequest restricts the namespaces secrets of a client may be
+// written to. Patterns are regular expressions matched against the whole
+// namespace; an empty list removes the restriction.
+type SetNamespacePatternsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Patterns      []string               `protobuf:"bytes,2,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNamespacePatternsRequest) Reset() {
+	*x = SetNamespacePatternsRequest{}
+	mi := &file_gaia_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNamespacePatternsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNamespacePatternsRequest) ProtoMessage() {}
+
+func (x *SetNamespacePatternsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNamespacePatternsRequest.ProtoReflect.Descriptor instead.
+func (*SetNamespacePatternsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SetNamespacePatternsRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *SetNamespacePatternsRequest) GetPatterns() []string {
+	if x != nil {
+		return x.Patterns
+	}
+	return nil
+}
+
+type SetNamespacePatternsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNamespacePatternsResponse) Reset() {
+	*x = SetNamespacePatternsResponse{}
+	mi := &file_gaia_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNamespacePatternsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNamespacePatternsResponse) ProtoMessage() {}
+
+func (x *SetNamespacePatternsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNamespacePatternsResponse.ProtoReflect.Descriptor instead.
+func (*SetNamespacePatternsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *SetNamespacePatternsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// SetNamespaceTTLRequest sets the default TTL of a client's namespace: secrets
+// added to it without a TTL of their own expire after ttl_seconds.
+type SetNamespaceTTLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	TtlSeconds    int64                  `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNamespaceTTLRequest) Reset() {
+	*x = SetNamespaceTTLRequest{}
+	mi := &file_gaia_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNamespaceTTLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNamespaceTTLRequest) ProtoMessage() {}
+
+func (x *SetNamespaceTTLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNamespaceTTLRequest.ProtoReflect.Descriptor instead.
+func (*SetNamespaceTTLRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *SetNamespaceTTLRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *SetNamespaceTTLRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *SetNamespaceTTLRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type SetNamespaceTTLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNamespaceTTLResponse) Reset() {
+	*x = SetNamespaceTTLResponse{}
+	mi := &file_gaia_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNamespaceTTLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNamespaceTTLResponse) ProtoMessage() {}
+
+func (x *SetNamespaceTTLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNamespaceTTLResponse.ProtoReflect.Descriptor instead.
+func (*SetNamespaceTTLResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *SetNamespaceTTLResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// ClearNamespaceTTLRequest removes the default TTL of a client's namespace.
+type ClearNamespaceTTLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearNamespaceTTLRequest) Reset() {
+	*x = ClearNamespaceTTLRequest{}
+	mi := &file_gaia_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearNamespaceTTLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearNamespaceTTLRequest) ProtoMessage() {}
+
+func (x *ClearNamespaceTTLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearNamespaceTTLRequest.ProtoReflect.Descriptor instead.
+func (*ClearNamespaceTTLRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ClearNamespaceTTLRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *ClearNamespaceTTLRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ClearNamespaceTTLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearNamespaceTTLResponse) Reset() {
+	*x = ClearNamespaceTTLResponse{}
+	mi := &file_gaia_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearNamespaceTTLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearNamespaceTTLResponse) ProtoMessage() {}
+
+func (x *ClearNamespaceTTLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearNamespaceTTLResponse.ProtoReflect.Descriptor instead.
+func (*ClearNamespaceTTLResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ClearNamespaceTTLResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// SetNamespaceRotationRequest enables or disables RotateSecrets for a client's
+// namespace. Rotation is disabled until it is enabled.
+type SetNamespaceRotationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Enabled       bool                   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNamespaceRotationRequest) Reset() {
+	*x = SetNamespaceRotationRequest{}
+	mi := &file_gaia_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNamespaceRotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNamespaceRotationRequest) ProtoMessage() {}
+
+func (x *SetNamespaceRotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNamespaceRotationRequest.ProtoReflect.Descriptor instead.
+func (*SetNamespaceRotationRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *SetNamespaceRotationRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *SetNamespaceRotationRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *SetNamespaceRotationRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type SetNamespaceRotationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNamespaceRotationResponse) Reset() {
+	*x = SetNamespaceRotationResponse{}
+	mi := &file_gaia_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNamespaceRotationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNamespaceRotationResponse) ProtoMessage() {}
+
+func (x *SetNamespaceRotationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNamespaceRotationResponse.ProtoReflect.Descriptor instead.
+func (*SetNamespaceRotationResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SetNamespaceRotationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ExportClientManifestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportClientManifestRequest) Reset() {
+	*x = ExportClientManifestRequest{}
+	mi := &file_gaia_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportClientManifestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportClientManifestRequest) ProtoMessage() {}
+
+func (x *ExportClientManifestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportClientManifestRequest.ProtoReflect.Descriptor instead.
+func (*ExportClientManifestRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{60}
+}
+
+// ClientManifestEntry joins a client's registration with the last certificate
+// issued to it. Certificate fields are empty for clients registered before
+// issued certificates were recorded.
+type ClientManifestEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TimeCreated   string                 `protobuf:"bytes,2,opt,name=time_created,json=timeCreated,proto3" json:"time_created,omitempty"`
+	Serial        string                 `protobuf:"bytes,3,opt,name=serial,proto3" json:"serial,omitempty"`
+	Fingerprint   string                 `protobuf:"bytes,4,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	NotAfter      string                 `protobuf:"bytes,5,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	IssuedAt      string                 `protobuf:"bytes,6,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientManifestEntry) Reset() {
+	*x = ClientManifestEntry{}
+	mi := &file_gaia_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientManifestEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientManifestEntry) ProtoMessage() {}
+
+func (x *ClientManifestEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientManifestEntry.ProtoReflect.Descriptor instead.
+func (*ClientManifestEntry) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ClientManifestEntry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ClientManifestEntry) GetTimeCreated() string {
+	if x != nil {
+		return x.TimeCreated
+	}
+	return ""
+}
+
+func (x *ClientManifestEntry) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+func (x *ClientManifestEntry) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *ClientManifestEntry) GetNotAfter() string {
+	if x != nil {
+		return x.NotAfter
+	}
+	return ""
+}
+
+func (x *ClientManifestEntry) GetIssuedAt() string {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return ""
+}
+
+type ExportClientManifestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clients       []*ClientManifestEntry `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportClientManifestResponse) Reset() {
+	*x = ExportClientManifestResponse{}
+	mi := &file_gaia_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportClientManifestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportClientManifestResponse) ProtoMessage() {}
+
+func (x *ExportClientManifestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportClientManifestResponse.ProtoReflect.Descriptor instead.
+func (*ExportClientManifestResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ExportClientManifestResponse) GetClients() []*ClientManifestEntry {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+type ExportClientsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportClientsRequest) Reset() {
+	*x = ExportClientsRequest{}
+	mi := &file_gaia_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportClientsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportClientsRequest) ProtoMessage() {}
+
+func (x *ExportClientsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportClientsRequest.ProtoReflect.Descriptor instead.
+func (*ExportClientsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{63}
+}
+
+type ExportClientsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clients       []*Client              `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportClientsResponse) Reset() {
+	*x = ExportClientsResponse{}
+	mi := &file_gaia_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportClientsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportClientsResponse) ProtoMessage() {}
+
+func (x *ExportClientsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportClientsResponse.ProtoReflect.Descriptor instead.
+func (*ExportClientsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ExportClientsResponse) GetClients() []*Client {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+// ImportClientsRequest restores client registrations, keeping their original
+// creation times. It is applied in one transaction.
+type ImportClientsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Clients []*Client              `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	// overwrite replaces the creation time of clients that are already
+	// registered. Without it, they are left untouched and counted as skipped.
+	Overwrite     bool `protobuf:"varint,2,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportClientsRequest) Reset() {
+	*x = ImportClientsRequest{}
+	mi := &file_gaia_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportClientsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportClientsRequest) ProtoMessage() {}
+
+func (x *ImportClientsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportClientsRequest.ProtoReflect.Descriptor instead.
+func (*ImportClientsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ImportClientsRequest) GetClients() []*Client {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+func (x *ImportClientsRequest) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+type ImportClientsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ClientsImported int32                  `protobuf:"varint,1,opt,name=clients_imported,json=clientsImported,proto3" json:"clients_imported,omitempty"`
+	ClientsSkipped  int32                  `protobuf:"varint,2,opt,name=clients_skipped,json=clientsSkipped,proto3" json:"clients_skipped,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ImportClientsResponse) Reset() {
+	*x = ImportClientsResponse{}
+	mi := &file_gaia_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportClientsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportClientsResponse) ProtoMessage() {}
+
+func (x *ImportClientsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportClientsResponse.ProtoReflect.Descriptor instead.
+func (*ImportClientsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ImportClientsResponse) GetClientsImported() int32 {
+	if x != nil {
+		return x.ClientsImported
+	}
+	return 0
+}
+
+func (x *ImportClientsResponse) GetClientsSkipped() int32 {
+	if x != nil {
+		return x.ClientsSkipped
+	}
+	return 0
+}
+
+type GetSecretUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSecretUsageRequest) Reset() {
+	*x = GetSecretUsageRequest{}
+	mi := &file_gaia_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSecretUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSecretUsageRequest) ProtoMessage() {}
+
+func (x *GetSecretUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSecretUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetSecretUsageRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *GetSecretUsageRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+// SecretUsage reports how often a secret was read. last_accessed is an RFC3339
+// timestamp, empty if the secret was never read while tracking was enabled.
+type SecretUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	AccessCount   uint64                 `protobuf:"varint,3,opt,name=access_count,json=accessCount,proto3" json:"access_count,omitempty"`
+	LastAccessed  string                 `protobuf:"bytes,4,opt,name=last_accessed,json=lastAccessed,proto3" json:"last_accessed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SecretUsage) Reset() {
+	*x = SecretUsage{}
+	mi := &file_gaia_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SecretUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretUsage) ProtoMessage() {}
+
+func (x *SecretUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretUsage.ProtoReflect.Descriptor instead.
+func (*SecretUsage) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *SecretUsage) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *SecretUsage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SecretUsage) GetAccessCount() uint64 {
+	if x != nil {
+		return x.AccessCount
+	}
+	return 0
+}
+
+func (x *SecretUsage) GetLastAccessed() string {
+	if x != nil {
+		return x.LastAccessed
+	}
+	return ""
+}
+
+type GetSecretUsageResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Secrets         []*SecretUsage         `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	TrackingEnabled bool                   `protobuf:"varint,2,opt,name=tracking_enabled,json=trackingEnabled,proto3" json:"tracking_enabled,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetSecretUsageResponse) Reset() {
+	*x = GetSecretUsageResponse{}
+	mi := &file_gaia_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSecretUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSecretUsageResponse) ProtoMessage() {}
+
+func (x *GetSecretUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSecretUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetSecretUsageResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GetSecretUsageResponse) GetSecrets() []*SecretUsage {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *GetSecretUsageResponse) GetTrackingEnabled() bool {
+	if x != nil {
+		return x.TrackingEnabled
+	}
+	return false
+}
+
+// SearchSecretsRequest finds secrets across all clients. Empty filters match
+// everything; id_contains matches a substring of the secret id. Values are only
+// returned when include_values is set.
+type SearchSecretsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	IdContains    string                 `protobuf:"bytes,3,opt,name=id_contains,json=idContains,proto3" json:"id_contains,omitempty"`
+	IncludeValues bool                   `protobuf:"varint,4,opt,name=include_values,json=includeValues,proto3" json:"include_values,omitempty"`
+	// page_size defaults to 100 and is capped at 1000.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous response.
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// field limits the search to structured secrets whose unencrypted field at
+	// this path equals field_value.
+	Field         string `protobuf:"bytes,7,opt,name=field,proto3" json:"field,omitempty"`
+	FieldValue    string `protobuf:"bytes,8,opt,name=field_value,json=fieldValue,proto3" json:"field_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchSecretsRequest) Reset() {
+	*x = SearchSecretsRequest{}
+	mi := &file_gaia_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchSecretsRequest) ProtoMessage() {}
+
+func (x *SearchSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchSecretsRequest.ProtoReflect.Descriptor instead.
+func (*SearchSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *SearchSecretsRequest) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *SearchSecretsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *SearchSecretsRequest) GetIdContains() string {
+	if x != nil {
+		return x.IdContains
+	}
+	return ""
+}
+
+func (x *SearchSecretsRequest) GetIncludeValues() bool {
+	if x != nil {
+		return x.IncludeValues
+	}
+	return false
+}
+
+func (x *SearchSecretsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchSecretsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *SearchSecretsRequest) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *SearchSecretsRequest) GetFieldValue() string {
+	if x != nil {
+		return x.FieldValue
+	}
+	return ""
+}
+
+type SecretMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Value         string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SecretMatch) Reset() {
+	*x = SecretMatch{}
+	mi := &file_gaia_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SecretMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretMatch) ProtoMessage() {}
+
+func (x *SecretMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretMatch.ProtoReflect.Descriptor instead.
+func (*SecretMatch) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *SecretMatch) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *SecretMatch) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *SecretMatch) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SecretMatch) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type SearchSecretsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Matches []*SecretMatch         `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	// next_page_token is empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchSecretsResponse) Reset() {
+	*x = SearchSecretsResponse{}
+	mi := &file_gaia_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchSecretsResponse) ProtoMessage() {}
+
+func (x *SearchSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchSecretsResponse.ProtoReflect.Descriptor instead.
+func (*SearchSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *SearchSecretsResponse) GetMatches() []*SecretMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *SearchSecretsResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Success
+		return x.NextPageToken
 	}
-	return false
+	return ""
 }
 
-type RegisterClientRequest struct {
+type MoveNamespaceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	SrcClient     string                 `protobuf:"bytes,1,opt,name=src_client,json=srcClient,proto3" json:"src_client,omitempty"`
+	DstClient     string                 `protobuf:"bytes,2,opt,name=dst_client,json=dstClient,proto3" json:"dst_client,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Overwrite     bool                   `protobuf:"varint,4,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterClientRequest) Reset() {
-	*x = RegisterClientRequest{}
-	mi := &file_gaia_proto_msgTypes[13]
+func (x *MoveNamespaceRequest) Reset() {
+	*x = MoveNamespaceRequest{}
+	mi := &file_gaia_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterClientRequest) String() string {
+func (x *MoveNamespaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterClientRequest) ProtoMessage() {}
+func (*MoveNamespaceRequest) ProtoMessage() {}
 
-func (x *RegisterClientRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[13]
+func (x *MoveNamespaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -657,41 +4206,61 @@ func (x *RegisterClientRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterClientRequest.ProtoReflect.Descriptor instead.
-func (*RegisterClientRequest) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use MoveNamespaceRequest.ProtoReflect.Descriptor instead.
+func (*MoveNamespaceRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *RegisterClientRequest) GetClientName() string {
+func (x *MoveNamespaceRequest) GetSrcClient() string {
 	if x != nil {
-		return x.ClientName
+		return x.SrcClient
 	}
 	return ""
 }
 
-type RegisterClientResponse struct {
+func (x *MoveNamespaceRequest) GetDstClient() string {
+	if x != nil {
+		return x.DstClient
+	}
+	return ""
+}
+
+func (x *MoveNamespaceRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *MoveNamespaceRequest) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+type MoveNamespaceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Certificate   string                 `protobuf:"bytes,1,opt,name=certificate,proto3" json:"certificate,omitempty"`                 // PEM-encoded cert
-	PrivateKey    string                 `protobuf:"bytes,2,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"` // PEM-encoded key
+	MovedCount    int32                  `protobuf:"varint,1,opt,name=moved_count,json=movedCount,proto3" json:"moved_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterClientResponse) Reset() {
-	*x = RegisterClientResponse{}
-	mi := &file_gaia_proto_msgTypes[14]
+func (x *MoveNamespaceResponse) Reset() {
+	*x = MoveNamespaceResponse{}
+	mi := &file_gaia_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterClientResponse) String() string {
+func (x *MoveNamespaceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterClientResponse) ProtoMessage() {}
+func (*MoveNamespaceResponse) ProtoMessage() {}
 
-func (x *RegisterClientResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[14]
+func (x *MoveNamespaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -702,48 +4271,46 @@ func (x *RegisterClientResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterClientResponse.ProtoReflect.Descriptor instead.
-func (*RegisterClientResponse) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{14}
-}
-
-func (x *RegisterClientResponse) GetCertificate() string {
-	if x != nil {
-		return x.Certificate
-	}
-	return ""
+// Deprecated: Use MoveNamespaceResponse.ProtoReflect.Descriptor instead.
+func (*MoveNamespaceResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *RegisterClientResponse) GetPrivateKey() string {
+func (x *MoveNamespaceResponse) GetMovedCount() int32 {
 	if x != nil {
-		return x.PrivateKey
+		return x.MovedCount
 	}
-	return ""
+	return 0
 }
 
-type Client struct {
+// RenameSecretRequest changes the id of a secret within its namespace, keeping
+// its value and access record.
+type RenameSecretRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	TimeCreated   string                 `protobuf:"bytes,2,opt,name=time_created,json=timeCreated,proto3" json:"time_created,omitempty"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	OldId         string                 `protobuf:"bytes,3,opt,name=old_id,json=oldId,proto3" json:"old_id,omitempty"`
+	NewId         string                 `protobuf:"bytes,4,opt,name=new_id,json=newId,proto3" json:"new_id,omitempty"`
+	Overwrite     bool                   `protobuf:"varint,5,opt,name=overwrite,proto3" json:"overwrite,omitempty"` // Replace an existing secret named new_id.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Client) Reset() {
-	*x = Client{}
-	mi := &file_gaia_proto_msgTypes[15]
+func (x *RenameSecretRequest) Reset() {
+	*x = RenameSecretRequest{}
+	mi := &file_gaia_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Client) String() string {
+func (x *RenameSecretRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Client) ProtoMessage() {}
+func (*RenameSecretRequest) ProtoMessage() {}
 
-func (x *Client) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[15]
+func (x *RenameSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -754,46 +4321,67 @@ func (x *Client) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Client.ProtoReflect.Descriptor instead.
-func (*Client) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use RenameSecretRequest.ProtoReflect.Descriptor instead.
+func (*RenameSecretRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *Client) GetName() string {
+func (x *RenameSecretRequest) GetClientName() string {
 	if x != nil {
-		return x.Name
+		return x.ClientName
 	}
 	return ""
 }
 
-func (x *Client) GetTimeCreated() string {
+func (x *RenameSecretRequest) GetNamespace() string {
 	if x != nil {
-		return x.TimeCreated
+		return x.Namespace
 	}
 	return ""
 }
 
-type ListClientsRequest struct {
+func (x *RenameSecretRequest) GetOldId() string {
+	if x != nil {
+		return x.OldId
+	}
+	return ""
+}
+
+func (x *RenameSecretRequest) GetNewId() string {
+	if x != nil {
+		return x.NewId
+	}
+	return ""
+}
+
+func (x *RenameSecretRequest) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+type RenameSecretResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListClientsRequest) Reset() {
-	*x = ListClientsRequest{}
-	mi := &file_gaia_proto_msgTypes[16]
+func (x *RenameSecretResponse) Reset() {
+	*x = RenameSecretResponse{}
+	mi := &file_gaia_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListClientsRequest) String() string {
+func (x *RenameSecretResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListClientsRequest) ProtoMessage() {}
+func (*RenameSecretResponse) ProtoMessage() {}
 
-func (x *ListClientsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[16]
+func (x *RenameSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -804,33 +4392,33 @@ func (x *ListClientsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListClientsRequest.ProtoReflect.Descriptor instead.
-func (*ListClientsRequest) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use RenameSecretResponse.ProtoReflect.Descriptor instead.
+func (*RenameSecretResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{76}
 }
 
-type ListClientsResponse struct {
+// VerifyIntegrityRequest asks the daemon to decrypt every stored secret.
+type VerifyIntegrityRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Clients       []*Client              `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListClientsResponse) Reset() {
-	*x = ListClientsResponse{}
-	mi := &file_gaia_proto_msgTypes[17]
+func (x *VerifyIntegrityRequest) Reset() {
+	*x = VerifyIntegrityRequest{}
+	mi := &file_gaia_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListClientsResponse) String() string {
+func (x *VerifyIntegrityRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListClientsResponse) ProtoMessage() {}
+func (*VerifyIntegrityRequest) ProtoMessage() {}
 
-func (x *ListClientsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[17]
+func (x *VerifyIntegrityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -841,40 +4429,38 @@ func (x *ListClientsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListClientsResponse.ProtoReflect.Descriptor instead.
-func (*ListClientsResponse) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{17}
-}
-
-func (x *ListClientsResponse) GetClients() []*Client {
-	if x != nil {
-		return x.Clients
-	}
-	return nil
+// Deprecated: Use VerifyIntegrityRequest.ProtoReflect.Descriptor instead.
+func (*VerifyIntegrityRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{77}
 }
 
-type ListNamespacesRequest struct {
+// IntegrityFailure names a secret that failed to decrypt. Values are never
+// returned.
+type IntegrityFailure struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListNamespacesRequest) Reset() {
-	*x = ListNamespacesRequest{}
-	mi := &file_gaia_proto_msgTypes[18]
+func (x *IntegrityFailure) Reset() {
+	*x = IntegrityFailure{}
+	mi := &file_gaia_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListNamespacesRequest) String() string {
+func (x *IntegrityFailure) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListNamespacesRequest) ProtoMessage() {}
+func (*IntegrityFailure) ProtoMessage() {}
 
-func (x *ListNamespacesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[18]
+func (x *IntegrityFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -885,84 +4471,62 @@ func (x *ListNamespacesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListNamespacesRequest.ProtoReflect.Descriptor instead.
-func (*ListNamespacesRequest) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use IntegrityFailure.ProtoReflect.Descriptor instead.
+func (*IntegrityFailure) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *ListNamespacesRequest) GetClientName() string {
+func (x *IntegrityFailure) GetClientName() string {
 	if x != nil {
 		return x.ClientName
 	}
 	return ""
 }
 
-type ListNamespacesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Namespaces    []string               `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ListNamespacesResponse) Reset() {
-	*x = ListNamespacesResponse{}
-	mi := &file_gaia_proto_msgTypes[19]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *ListNamespacesResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ListNamespacesResponse) ProtoMessage() {}
-
-func (x *ListNamespacesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[19]
+func (x *IntegrityFailure) GetNamespace() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Namespace
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use ListNamespacesResponse.ProtoReflect.Descriptor instead.
-func (*ListNamespacesResponse) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{19}
+func (x *IntegrityFailure) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-func (x *ListNamespacesResponse) GetNamespaces() []string {
+func (x *IntegrityFailure) GetReason() string {
 	if x != nil {
-		return x.Namespaces
+		return x.Reason
 	}
-	return nil
+	return ""
 }
 
-type RevokeClientRequest struct {
+type VerifyIntegrityResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Checked       int32                  `protobuf:"varint,1,opt,name=checked,proto3" json:"checked,omitempty"`
+	Failures      []*IntegrityFailure    `protobuf:"bytes,2,rep,name=failures,proto3" json:"failures,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RevokeClientRequest) Reset() {
-	*x = RevokeClientRequest{}
-	mi := &file_gaia_proto_msgTypes[20]
+func (x *VerifyIntegrityResponse) Reset() {
+	*x = VerifyIntegrityResponse{}
+	mi := &file_gaia_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RevokeClientRequest) String() string {
+func (x *VerifyIntegrityResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RevokeClientRequest) ProtoMessage() {}
+func (*VerifyIntegrityResponse) ProtoMessage() {}
 
-func (x *RevokeClientRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[20]
+func (x *VerifyIntegrityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -973,40 +4537,48 @@ func (x *RevokeClientRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RevokeClientRequest.ProtoReflect.Descriptor instead.
-func (*RevokeClientRequest) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use VerifyIntegrityResponse.ProtoReflect.Descriptor instead.
+func (*VerifyIntegrityResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{79}
 }
 
-func (x *RevokeClientRequest) GetClientName() string {
+func (x *VerifyIntegrityResponse) GetChecked() int32 {
 	if x != nil {
-		return x.ClientName
+		return x.Checked
 	}
-	return ""
+	return 0
 }
 
-type RevokeClientResponse struct {
+func (x *VerifyIntegrityResponse) GetFailures() []*IntegrityFailure {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+// RekeyDryRunRequest checks that the database can be rekeyed from old_passphrase.
+type RekeyDryRunRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	OldPassphrase string                 `protobuf:"bytes,1,opt,name=old_passphrase,json=oldPassphrase,proto3" json:"old_passphrase,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RevokeClientResponse) Reset() {
-	*x = RevokeClientResponse{}
-	mi := &file_gaia_proto_msgTypes[21]
+func (x *RekeyDryRunRequest) Reset() {
+	*x = RekeyDryRunRequest{}
+	mi := &file_gaia_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RevokeClientResponse) String() string {
+func (x *RekeyDryRunRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RevokeClientResponse) ProtoMessage() {}
+func (*RekeyDryRunRequest) ProtoMessage() {}
 
-func (x *RevokeClientResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[21]
+func (x *RekeyDryRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1017,42 +4589,43 @@ func (x *RevokeClientResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RevokeClientResponse.ProtoReflect.Descriptor instead.
-func (*RevokeClientResponse) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use RekeyDryRunRequest.ProtoReflect.Descriptor instead.
+func (*RekeyDryRunRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{80}
 }
 
-func (x *RevokeClientResponse) GetSuccess() bool {
+func (x *RekeyDryRunRequest) GetOldPassphrase() string {
 	if x != nil {
-		return x.Success
+		return x.OldPassphrase
 	}
-	return false
+	return ""
 }
 
-type DeleteSecretRequest struct {
+// RekeyDryRunResponse reports the number of secrets a rekey re-encrypts and the
+// ones that fail to decrypt and would make it fail.
+type RekeyDryRunResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
-	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Secrets       int32                  `protobuf:"varint,1,opt,name=secrets,proto3" json:"secrets,omitempty"`
+	Failures      []*IntegrityFailure    `protobuf:"bytes,2,rep,name=failures,proto3" json:"failures,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteSecretRequest) Reset() {
-	*x = DeleteSecretRequest{}
-	mi := &file_gaia_proto_msgTypes[22]
+func (x *RekeyDryRunResponse) Reset() {
+	*x = RekeyDryRunResponse{}
+	mi := &file_gaia_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteSecretRequest) String() string {
+func (x *RekeyDryRunResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteSecretRequest) ProtoMessage() {}
+func (*RekeyDryRunResponse) ProtoMessage() {}
 
-func (x *DeleteSecretRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[22]
+func (x *RekeyDryRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1063,54 +4636,53 @@ func (x *DeleteSecretRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteSecretRequest.ProtoReflect.Descriptor instead.
-func (*DeleteSecretRequest) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{22}
-}
-
-func (x *DeleteSecretRequest) GetClientName() string {
-	if x != nil {
-		return x.ClientName
-	}
-	return ""
+// Deprecated: Use RekeyDryRunResponse.ProtoReflect.Descriptor instead.
+func (*RekeyDryRunResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{81}
 }
 
-func (x *DeleteSecretRequest) GetNamespace() string {
+func (x *RekeyDryRunResponse) GetSecrets() int32 {
 	if x != nil {
-		return x.Namespace
+		return x.Secrets
 	}
-	return ""
+	return 0
 }
 
-func (x *DeleteSecretRequest) GetId() string {
+func (x *RekeyDryRunResponse) GetFailures() []*IntegrityFailure {
 	if x != nil {
-		return x.Id
+		return x.Failures
 	}
-	return ""
+	return nil
 }
 
-type DeleteSecretResponse struct {
+// RekeyRequest changes the master passphrase from old_passphrase to
+// new_passphrase, re-encrypting every secret. With verify_first, every secret
+// is decrypted before any is rewritten and the rekey fails with all the secrets
+// that do not decrypt; otherwise it fails on the first one.
+type RekeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	OldPassphrase string                 `protobuf:"bytes,1,opt,name=old_passphrase,json=oldPassphrase,proto3" json:"old_passphrase,omitempty"`
+	NewPassphrase string                 `protobuf:"bytes,2,opt,name=new_passphrase,json=newPassphrase,proto3" json:"new_passphrase,omitempty"`
+	VerifyFirst   bool                   `protobuf:"varint,3,opt,name=verify_first,json=verifyFirst,proto3" json:"verify_first,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteSecretResponse) Reset() {
-	*x = DeleteSecretResponse{}
-	mi := &file_gaia_proto_msgTypes[23]
+func (x *RekeyRequest) Reset() {
+	*x = RekeyRequest{}
+	mi := &file_gaia_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteSecretResponse) String() string {
+func (x *RekeyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteSecretResponse) ProtoMessage() {}
+func (*RekeyRequest) ProtoMessage() {}
 
-func (x *DeleteSecretResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[23]
+func (x *RekeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1121,40 +4693,55 @@ func (x *DeleteSecretResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteSecretResponse.ProtoReflect.Descriptor instead.
-func (*DeleteSecretResponse) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use RekeyRequest.ProtoReflect.Descriptor instead.
+func (*RekeyRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *DeleteSecretResponse) GetSuccess() bool {
+func (x *RekeyRequest) GetOldPassphrase() string {
 	if x != nil {
-		return x.Success
+		return x.OldPassphrase
+	}
+	return ""
+}
+
+func (x *RekeyRequest) GetNewPassphrase() string {
+	if x != nil {
+		return x.NewPassphrase
+	}
+	return ""
+}
+
+func (x *RekeyRequest) GetVerifyFirst() bool {
+	if x != nil {
+		return x.VerifyFirst
 	}
 	return false
 }
 
-type ImportSecretsConfig struct {
+// RekeyResponse reports the number of secrets re-encrypted with the new key.
+type RekeyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Overwrite     bool                   `protobuf:"varint,1,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	Secrets       int32                  `protobuf:"varint,1,opt,name=secrets,proto3" json:"secrets,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ImportSecretsConfig) Reset() {
-	*x = ImportSecretsConfig{}
-	mi := &file_gaia_proto_msgTypes[24]
+func (x *RekeyResponse) Reset() {
+	*x = RekeyResponse{}
+	mi := &file_gaia_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ImportSecretsConfig) String() string {
+func (x *RekeyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ImportSecretsConfig) ProtoMessage() {}
+func (*RekeyResponse) ProtoMessage() {}
 
-func (x *ImportSecretsConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[24]
+func (x *RekeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1165,43 +4752,43 @@ func (x *ImportSecretsConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ImportSecretsConfig.ProtoReflect.Descriptor instead.
-func (*ImportSecretsConfig) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use RekeyResponse.ProtoReflect.Descriptor instead.
+func (*RekeyResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{83}
 }
 
-func (x *ImportSecretsConfig) GetOverwrite() bool {
+func (x *RekeyResponse) GetSecrets() int32 {
 	if x != nil {
-		return x.Overwrite
+		return x.Secrets
 	}
-	return false
+	return 0
 }
 
-type ImportSecretItem struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
-	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
-	Value         string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// PruneOrphansRequest removes secrets of clients that are no longer registered.
+type PruneOrphansRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	DryRun              bool                   `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                                          // Report what would be removed without deleting anything.
+	IncludeEmptyClients bool                   `protobuf:"varint,2,opt,name=include_empty_clients,json=includeEmptyClients,proto3" json:"include_empty_clients,omitempty"` // Also remove registrations without any secrets.
+	Passphrase          string                 `protobuf:"bytes,3,opt,name=passphrase,proto3" json:"passphrase,omitempty"`                                                 // Master passphrase, required with require_reauth_for_destructive.
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
-func (x *ImportSecretItem) Reset() {
-	*x = ImportSecretItem{}
-	mi := &file_gaia_proto_msgTypes[25]
+func (x *PruneOrphansRequest) Reset() {
+	*x = PruneOrphansRequest{}
+	mi := &file_gaia_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ImportSecretItem) String() string {
+func (x *PruneOrphansRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ImportSecretItem) ProtoMessage() {}
+func (*PruneOrphansRequest) ProtoMessage() {}
 
-func (x *ImportSecretItem) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[25]
+func (x *PruneOrphansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1212,66 +4799,56 @@ func (x *ImportSecretItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ImportSecretItem.ProtoReflect.Descriptor instead.
-func (*ImportSecretItem) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{25}
-}
-
-func (x *ImportSecretItem) GetClientName() string {
-	if x != nil {
-		return x.ClientName
-	}
-	return ""
+// Deprecated: Use PruneOrphansRequest.ProtoReflect.Descriptor instead.
+func (*PruneOrphansRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *ImportSecretItem) GetNamespace() string {
+func (x *PruneOrphansRequest) GetDryRun() bool {
 	if x != nil {
-		return x.Namespace
+		return x.DryRun
 	}
-	return ""
+	return false
 }
 
-func (x *ImportSecretItem) GetId() string {
+func (x *PruneOrphansRequest) GetIncludeEmptyClients() bool {
 	if x != nil {
-		return x.Id
+		return x.IncludeEmptyClients
 	}
-	return ""
+	return false
 }
 
-func (x *ImportSecretItem) GetValue() string {
+func (x *PruneOrphansRequest) GetPassphrase() string {
 	if x != nil {
-		return x.Value
+		return x.Passphrase
 	}
 	return ""
 }
 
-// Wrapper message for the stream to handle config and items
-type ImportSecretsRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Payload:
-	//
-	//	*ImportSecretsRequest_Config
-	//	*ImportSecretsRequest_Item
-	Payload       isImportSecretsRequest_Payload `protobuf_oneof:"payload"`
+// OrphanedClient is an unregistered client that still has secrets.
+type OrphanedClient struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Secrets       int32                  `protobuf:"varint,2,opt,name=secrets,proto3" json:"secrets,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ImportSecretsRequest) Reset() {
-	*x = ImportSecretsRequest{}
-	mi := &file_gaia_proto_msgTypes[26]
+func (x *OrphanedClient) Reset() {
+	*x = OrphanedClient{}
+	mi := &file_gaia_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ImportSecretsRequest) String() string {
+func (x *OrphanedClient) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ImportSecretsRequest) ProtoMessage() {}
+func (*OrphanedClient) ProtoMessage() {}
 
-func (x *ImportSecretsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[26]
+func (x *OrphanedClient) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1282,75 +4859,49 @@ func (x *ImportSecretsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ImportSecretsRequest.ProtoReflect.Descriptor instead.
-func (*ImportSecretsRequest) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{26}
-}
-
-func (x *ImportSecretsRequest) GetPayload() isImportSecretsRequest_Payload {
-	if x != nil {
-		return x.Payload
-	}
-	return nil
+// Deprecated: Use OrphanedClient.ProtoReflect.Descriptor instead.
+func (*OrphanedClient) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *ImportSecretsRequest) GetConfig() *ImportSecretsConfig {
+func (x *OrphanedClient) GetClientName() string {
 	if x != nil {
-		if x, ok := x.Payload.(*ImportSecretsRequest_Config); ok {
-			return x.Config
-		}
+		return x.ClientName
 	}
-	return nil
+	return ""
 }
 
-func (x *ImportSecretsRequest) GetItem() *ImportSecretItem {
+func (x *OrphanedClient) GetSecrets() int32 {
 	if x != nil {
-		if x, ok := x.Payload.(*ImportSecretsRequest_Item); ok {
-			return x.Item
-		}
+		return x.Secrets
 	}
-	return nil
-}
-
-type isImportSecretsRequest_Payload interface {
-	isImportSecretsRequest_Payload()
-}
-
-type ImportSecretsRequest_Config struct {
-	Config *ImportSecretsConfig `protobuf:"bytes,1,opt,name=config,proto3,oneof"`
-}
-
-type ImportSecretsRequest_Item struct {
-	Item *ImportSecretItem `protobuf:"bytes,2,opt,name=item,proto3,oneof"`
+	return 0
 }
 
-func (*ImportSecretsRequest_Config) isImportSecretsRequest_Payload() {}
-
-func (*ImportSecretsRequest_Item) isImportSecretsRequest_Payload() {}
-
-type ImportSecretsResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	SecretsImported int32                  `protobuf:"varint,1,opt,name=secrets_imported,json=secretsImported,proto3" json:"secrets_imported,omitempty"`
-	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// PruneOrphansResponse lists what was removed, or would be removed on a dry run.
+type PruneOrphansResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orphaned      []*OrphanedClient      `protobuf:"bytes,1,rep,name=orphaned,proto3" json:"orphaned,omitempty"`
+	EmptyClients  []string               `protobuf:"bytes,2,rep,name=empty_clients,json=emptyClients,proto3" json:"empty_clients,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ImportSecretsResponse) Reset() {
-	*x = ImportSecretsResponse{}
-	mi := &file_gaia_proto_msgTypes[27]
+func (x *PruneOrphansResponse) Reset() {
+	*x = PruneOrphansResponse{}
+	mi := &file_gaia_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ImportSecretsResponse) String() string {
+func (x *PruneOrphansResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ImportSecretsResponse) ProtoMessage() {}
+func (*PruneOrphansResponse) ProtoMessage() {}
 
-func (x *ImportSecretsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[27]
+func (x *PruneOrphansResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1361,47 +4912,51 @@ func (x *ImportSecretsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ImportSecretsResponse.ProtoReflect.Descriptor instead.
-func (*ImportSecretsResponse) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use PruneOrphansResponse.ProtoReflect.Descriptor instead.
+func (*PruneOrphansResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{86}
 }
 
-func (x *ImportSecretsResponse) GetSecretsImported() int32 {
+func (x *PruneOrphansResponse) GetOrphaned() []*OrphanedClient {
 	if x != nil {
-		return x.SecretsImported
+		return x.Orphaned
 	}
-	return 0
+	return nil
 }
 
-func (x *ImportSecretsResponse) GetMessage() string {
+func (x *PruneOrphansResponse) GetEmptyClients() []string {
 	if x != nil {
-		return x.Message
+		return x.EmptyClients
 	}
-	return ""
+	return nil
 }
 
-type ListSecretsResponse struct {
+// DeleteSecretsByTagRequest deletes every secret of a client carrying a tag.
+type DeleteSecretsByTagRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Namespaces    []*Namespace           `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	TagKey        string                 `protobuf:"bytes,2,opt,name=tag_key,json=tagKey,proto3" json:"tag_key,omitempty"`
+	TagValue      string                 `protobuf:"bytes,3,opt,name=tag_value,json=tagValue,proto3" json:"tag_value,omitempty"`
+	Passphrase    string                 `protobuf:"bytes,4,opt,name=passphrase,proto3" json:"passphrase,omitempty"` // Master passphrase, required with require_reauth_for_destructive.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListSecretsResponse) Reset() {
-	*x = ListSecretsResponse{}
-	mi := &file_gaia_proto_msgTypes[28]
+func (x *DeleteSecretsByTagRequest) Reset() {
+	*x = DeleteSecretsByTagRequest{}
+	mi := &file_gaia_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSecretsResponse) String() string {
+func (x *DeleteSecretsByTagRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSecretsResponse) ProtoMessage() {}
+func (*DeleteSecretsByTagRequest) ProtoMessage() {}
 
-func (x *ListSecretsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[28]
+func (x *DeleteSecretsByTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1412,40 +4967,61 @@ func (x *ListSecretsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSecretsResponse.ProtoReflect.Descriptor instead.
-func (*ListSecretsResponse) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use DeleteSecretsByTagRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSecretsByTagRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *ListSecretsResponse) GetNamespaces() []*Namespace {
+func (x *DeleteSecretsByTagRequest) GetClientName() string {
 	if x != nil {
-		return x.Namespaces
+		return x.ClientName
 	}
-	return nil
+	return ""
 }
 
-type ListSecretsRequest struct {
+func (x *DeleteSecretsByTagRequest) GetTagKey() string {
+	if x != nil {
+		return x.TagKey
+	}
+	return ""
+}
+
+func (x *DeleteSecretsByTagRequest) GetTagValue() string {
+	if x != nil {
+		return x.TagValue
+	}
+	return ""
+}
+
+func (x *DeleteSecretsByTagRequest) GetPassphrase() string {
+	if x != nil {
+		return x.Passphrase
+	}
+	return ""
+}
+
+type DeleteSecretsByTagResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Deleted       int32                  `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListSecretsRequest) Reset() {
-	*x = ListSecretsRequest{}
-	mi := &file_gaia_proto_msgTypes[29]
+func (x *DeleteSecretsByTagResponse) Reset() {
+	*x = DeleteSecretsByTagResponse{}
+	mi := &file_gaia_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSecretsRequest) String() string {
+func (x *DeleteSecretsByTagResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSecretsRequest) ProtoMessage() {}
+func (*DeleteSecretsByTagResponse) ProtoMessage() {}
 
-func (x *ListSecretsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gaia_proto_msgTypes[29]
+func (x *DeleteSecretsByTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1456,16 +5032,16 @@ func (x *ListSecretsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSecretsRequest.ProtoReflect.Descriptor instead.
-func (*ListSecretsRequest) Descriptor() ([]byte, []int) {
-	return file_gaia_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use DeleteSecretsByTagResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSecretsByTagResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *ListSecretsRequest) GetClientName() string {
+func (x *DeleteSecretsByTagResponse) GetDeleted() int32 {
 	if x != nil {
-		return x.ClientName
+		return x.Deleted
 	}
-	return ""
+	return 0
 }
 
 var File_gaia_proto protoreflect.FileDescriptor
@@ -1476,40 +5052,100 @@ const file_gaia_proto_rawDesc = "" +
 	"gaia.proto\x12\x04gaia\".\n" +
 	"\x06Secret\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"G\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"a\n" +
+	"\x1bCreateSecretIfAbsentRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"N\n" +
+	"\x1cCreateSecretIfAbsentResponse\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\"q\n" +
+	"\x15ListOwnSecretsRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"h\n" +
+	"\x16ListOwnSecretsResponse\x12&\n" +
+	"\asecrets\x18\x01 \x03(\v2\f.gaia.SecretR\asecrets\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"s\n" +
+	"\x17ListOwnSecretIdsRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"T\n" +
+	"\x18ListOwnSecretIdsResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"G\n" +
 	"\tNamespace\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12&\n" +
-	"\asecrets\x18\x02 \x03(\v2\f.gaia.SecretR\asecrets\"w\n" +
+	"\asecrets\x18\x02 \x03(\v2\f.gaia.SecretR\asecrets\"\xc6\x02\n" +
 	"\x10AddSecretRequest\x12\x1c\n" +
 	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
 	"\x05value\x18\x03 \x01(\tR\x05value\x12\x1f\n" +
 	"\vclient_name\x18\x04 \x01(\tR\n" +
-	"clientName\"G\n" +
+	"clientName\x12\x1f\n" +
+	"\vttl_seconds\x18\x05 \x01(\x03R\n" +
+	"ttlSeconds\x12\x16\n" +
+	"\x06format\x18\x06 \x01(\tR\x06format\x124\n" +
+	"\x04tags\x18\a \x03(\v2 .gaia.AddSecretRequest.TagsEntryR\x04tags\x12%\n" +
+	"\x0eencrypt_fields\x18\b \x03(\tR\rencryptFields\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"c\n" +
 	"\x11AddSecretResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"@\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1a\n" +
+	"\bwarnings\x18\x03 \x03(\tR\bwarnings\"@\n" +
 	"\x10GetSecretRequest\x12\x1c\n" +
 	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\"\x12\n" +
-	"\x10GetStatusRequest\"+\n" +
+	"\x10GetStatusRequest\"\x94\x02\n" +
 	"\x11GetStatusResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"\r\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x16\n" +
+	"\x06locked\x18\x02 \x01(\bR\x06locked\x12\x1f\n" +
+	"\vlock_reason\x18\x03 \x01(\tR\n" +
+	"lockReason\x12!\n" +
+	"\flock_changed\x18\x04 \x01(\tR\vlockChanged\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\tR\tstartedAt\x12%\n" +
+	"\x0euptime_seconds\x18\x06 \x01(\x03R\ruptimeSeconds\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\a \x01(\tR\tgoVersion\x12\x0e\n" +
+	"\x02os\x18\b \x01(\tR\x02os\x12\x16\n" +
+	"\x06banner\x18\t \x01(\tR\x06banner\"\x13\n" +
+	"\x11GetMetricsRequest\"\x8b\x02\n" +
+	"\x12GetMetricsResponse\x12%\n" +
+	"\x0esecrets_served\x18\x01 \x01(\x04R\rsecretsServed\x12#\n" +
+	"\raccess_denied\x18\x02 \x01(\x04R\faccessDenied\x12'\n" +
+	"\x0fsecrets_written\x18\x03 \x01(\x04R\x0esecretsWritten\x12'\n" +
+	"\x0fsecrets_deleted\x18\x04 \x01(\x04R\x0esecretsDeleted\x12\x18\n" +
+	"\aunlocks\x18\x05 \x01(\x04R\aunlocks\x12%\n" +
+	"\x0efailed_unlocks\x18\x06 \x01(\x04R\rfailedUnlocks\x12\x16\n" +
+	"\x06locked\x18\a \x01(\bR\x06locked\"\r\n" +
 	"\vStopRequest\"(\n" +
 	"\fStopResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"/\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"E\n" +
 	"\rUnlockRequest\x12\x1e\n" +
 	"\n" +
 	"passphrase\x18\x01 \x01(\tR\n" +
-	"passphrase\"*\n" +
+	"passphrase\x12\x14\n" +
+	"\x05share\x18\x02 \x01(\tR\x05share\"\xa9\x01\n" +
 	"\x0eUnlockResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\r\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12)\n" +
+	"\x10already_unlocked\x18\x02 \x01(\bR\x0falreadyUnlocked\x12)\n" +
+	"\x10shares_collected\x18\x03 \x01(\x05R\x0fsharesCollected\x12'\n" +
+	"\x0fshares_required\x18\x04 \x01(\x05R\x0esharesRequired\"\r\n" +
 	"\vLockRequest\"(\n" +
 	"\fLockResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"8\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xc2\x01\n" +
 	"\x15RegisterClientRequest\x12\x1f\n" +
 	"\vclient_name\x18\x01 \x01(\tR\n" +
-	"clientName\"[\n" +
+	"clientName\x12\x18\n" +
+	"\areissue\x18\x02 \x01(\bR\areissue\x12\"\n" +
+	"\forganization\x18\x03 \x01(\tR\forganization\x12/\n" +
+	"\x13organizational_unit\x18\x04 \x01(\tR\x12organizationalUnit\x12\x19\n" +
+	"\buri_sans\x18\x05 \x03(\tR\auriSans\"[\n" +
 	"\x16RegisterClientResponse\x12 \n" +
 	"\vcertificate\x18\x01 \x01(\tR\vcertificate\x12\x1f\n" +
 	"\vprivate_key\x18\x02 \x01(\tR\n" +
@@ -1526,21 +5162,34 @@ const file_gaia_proto_rawDesc = "" +
 	"\x16ListNamespacesResponse\x12\x1e\n" +
 	"\n" +
 	"namespaces\x18\x01 \x03(\tR\n" +
-	"namespaces\"6\n" +
+	"namespaces\"o\n" +
 	"\x13RevokeClientRequest\x12\x1f\n" +
 	"\vclient_name\x18\x01 \x01(\tR\n" +
-	"clientName\"0\n" +
+	"clientName\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x1e\n" +
+	"\n" +
+	"passphrase\x18\x03 \x01(\tR\n" +
+	"passphrase\"s\n" +
 	"\x14RevokeClientResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"d\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1e\n" +
+	"\n" +
+	"namespaces\x18\x02 \x03(\tR\n" +
+	"namespaces\x12!\n" +
+	"\fsecret_count\x18\x03 \x01(\x05R\vsecretCount\"\x84\x01\n" +
 	"\x13DeleteSecretRequest\x12\x1f\n" +
 	"\vclient_name\x18\x01 \x01(\tR\n" +
 	"clientName\x12\x1c\n" +
 	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x0e\n" +
-	"\x02id\x18\x03 \x01(\tR\x02id\"0\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x1e\n" +
+	"\n" +
+	"passphrase\x18\x04 \x01(\tR\n" +
+	"passphrase\"0\n" +
 	"\x14DeleteSecretResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"3\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"d\n" +
 	"\x13ImportSecretsConfig\x12\x1c\n" +
-	"\toverwrite\x18\x01 \x01(\bR\toverwrite\"w\n" +
+	"\toverwrite\x18\x01 \x01(\bR\toverwrite\x12\x14\n" +
+	"\x05merge\x18\x02 \x01(\bR\x05merge\x12\x19\n" +
+	"\bbatch_id\x18\x03 \x01(\tR\abatchId\"w\n" +
 	"\x10ImportSecretItem\x12\x1f\n" +
 	"\vclient_name\x18\x01 \x01(\tR\n" +
 	"clientName\x12\x1c\n" +
@@ -1550,22 +5199,228 @@ const file_gaia_proto_rawDesc = "" +
 	"\x14ImportSecretsRequest\x123\n" +
 	"\x06config\x18\x01 \x01(\v2\x19.gaia.ImportSecretsConfigH\x00R\x06config\x12,\n" +
 	"\x04item\x18\x02 \x01(\v2\x16.gaia.ImportSecretItemH\x00R\x04itemB\t\n" +
-	"\apayload\"\\\n" +
+	"\apayload\"\xd7\x01\n" +
 	"\x15ImportSecretsResponse\x12)\n" +
 	"\x10secrets_imported\x18\x01 \x01(\x05R\x0fsecretsImported\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"F\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x0fsecrets_skipped\x18\x03 \x01(\x05R\x0esecretsSkipped\x12\x1a\n" +
+	"\breplayed\x18\x04 \x01(\bR\breplayed\x124\n" +
+	"\n" +
+	"violations\x18\x05 \x03(\v2\x14.gaia.ValueViolationR\n" +
+	"violations\"\x82\x01\n" +
+	"\x15ImportSecretsProgress\x12'\n" +
+	"\x0eitems_received\x18\x01 \x01(\x05H\x00R\ritemsReceived\x125\n" +
+	"\x06result\x18\x02 \x01(\v2\x1b.gaia.ImportSecretsResponseH\x00R\x06resultB\t\n" +
+	"\apayload\"\x8b\x01\n" +
+	"\x0eValueViolation\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x12\n" +
+	"\x04rule\x18\x04 \x01(\tR\x04rule\x12\x16\n" +
+	"\x06action\x18\x05 \x01(\tR\x06action\"c\n" +
+	"\x11SetSecretsRequest\x120\n" +
+	"\asecrets\x18\x01 \x03(\v2\x16.gaia.ImportSecretItemR\asecrets\x12\x1c\n" +
+	"\toverwrite\x18\x02 \x01(\bR\toverwrite\"\x8d\x01\n" +
+	"\x12SetSecretsResponse\x12\x1f\n" +
+	"\vsecrets_set\x18\x01 \x01(\x05R\n" +
+	"secretsSet\x12 \n" +
+	"\vunreachable\x18\x02 \x01(\x05R\vunreachable\x124\n" +
+	"\n" +
+	"violations\x18\x03 \x03(\v2\x14.gaia.ValueViolationR\n" +
+	"violations\"F\n" +
 	"\x13ListSecretsResponse\x12/\n" +
 	"\n" +
 	"namespaces\x18\x01 \x03(\v2\x0f.gaia.NamespaceR\n" +
 	"namespaces\"5\n" +
 	"\x12ListSecretsRequest\x12\x1f\n" +
 	"\vclient_name\x18\x01 \x01(\tR\n" +
-	"clientName2\x96\x06\n" +
+	"clientName\"q\n" +
+	"\x15StreamSecretsResponse\x12-\n" +
+	"\tnamespace\x18\x01 \x01(\v2\x0f.gaia.NamespaceR\tnamespace\x12)\n" +
+	"\x10total_namespaces\x18\x02 \x01(\x05R\x0ftotalNamespaces\"7\n" +
+	"\x14ExportSecretsRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\"E\n" +
+	"\x15ExportSecretsResponse\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.gaia.ImportSecretItemR\x05items\"\x87\x01\n" +
+	"\x14RotateSecretsRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x05R\x06length\x12\x18\n" +
+	"\acharset\x18\x04 \x01(\tR\acharset\"Y\n" +
+	"\rRotatedSecret\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\told_value\x18\x02 \x01(\tR\boldValue\x12\x1b\n" +
+	"\tnew_value\x18\x03 \x01(\tR\bnewValue\"F\n" +
+	"\x15RotateSecretsResponse\x12-\n" +
+	"\asecrets\x18\x01 \x03(\v2\x13.gaia.RotatedSecretR\asecrets\"J\n" +
+	"\x17GetCommonSecretsRequest\x12!\n" +
+	"\tnamespace\x18\x01 \x01(\tH\x00R\tnamespace\x88\x01\x01B\f\n" +
+	"\n" +
+	"_namespace\"K\n" +
+	"\x18GetCommonSecretsResponse\x12/\n" +
+	"\n" +
+	"namespaces\x18\x01 \x03(\v2\x0f.gaia.NamespaceR\n" +
+	"namespaces\"Y\n" +
+	"\x16SetCommonGrantsRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1e\n" +
+	"\n" +
+	"namespaces\x18\x02 \x03(\tR\n" +
+	"namespaces\"3\n" +
+	"\x17SetCommonGrantsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"Z\n" +
+	"\x1bSetNamespacePatternsRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1a\n" +
+	"\bpatterns\x18\x02 \x03(\tR\bpatterns\"8\n" +
+	"\x1cSetNamespacePatternsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"x\n" +
+	"\x16SetNamespaceTTLRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x1f\n" +
+	"\vttl_seconds\x18\x03 \x01(\x03R\n" +
+	"ttlSeconds\"3\n" +
+	"\x17SetNamespaceTTLResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"Y\n" +
+	"\x18ClearNamespaceTTLRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"5\n" +
+	"\x19ClearNamespaceTTLResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"v\n" +
+	"\x1bSetNamespaceRotationRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x18\n" +
+	"\aenabled\x18\x03 \x01(\bR\aenabled\"8\n" +
+	"\x1cSetNamespaceRotationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x1d\n" +
+	"\x1bExportClientManifestRequest\"\xc0\x01\n" +
+	"\x13ClientManifestEntry\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\ftime_created\x18\x02 \x01(\tR\vtimeCreated\x12\x16\n" +
+	"\x06serial\x18\x03 \x01(\tR\x06serial\x12 \n" +
+	"\vfingerprint\x18\x04 \x01(\tR\vfingerprint\x12\x1b\n" +
+	"\tnot_after\x18\x05 \x01(\tR\bnotAfter\x12\x1b\n" +
+	"\tissued_at\x18\x06 \x01(\tR\bissuedAt\"S\n" +
+	"\x1cExportClientManifestResponse\x123\n" +
+	"\aclients\x18\x01 \x03(\v2\x19.gaia.ClientManifestEntryR\aclients\"\x16\n" +
+	"\x14ExportClientsRequest\"?\n" +
+	"\x15ExportClientsResponse\x12&\n" +
+	"\aclients\x18\x01 \x03(\v2\f.gaia.ClientR\aclients\"\\\n" +
+	"\x14ImportClientsRequest\x12&\n" +
+	"\aclients\x18\x01 \x03(\v2\f.gaia.ClientR\aclients\x12\x1c\n" +
+	"\toverwrite\x18\x02 \x01(\bR\toverwrite\"k\n" +
+	"\x15ImportClientsResponse\x12)\n" +
+	"\x10clients_imported\x18\x01 \x01(\x05R\x0fclientsImported\x12'\n" +
+	"\x0fclients_skipped\x18\x02 \x01(\x05R\x0eclientsSkipped\"8\n" +
+	"\x15GetSecretUsageRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\"\x83\x01\n" +
+	"\vSecretUsage\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12!\n" +
+	"\faccess_count\x18\x03 \x01(\x04R\vaccessCount\x12#\n" +
+	"\rlast_accessed\x18\x04 \x01(\tR\flastAccessed\"p\n" +
+	"\x16GetSecretUsageResponse\x12+\n" +
+	"\asecrets\x18\x01 \x03(\v2\x11.gaia.SecretUsageR\asecrets\x12)\n" +
+	"\x10tracking_enabled\x18\x02 \x01(\bR\x0ftrackingEnabled\"\x90\x02\n" +
+	"\x14SearchSecretsRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x1f\n" +
+	"\vid_contains\x18\x03 \x01(\tR\n" +
+	"idContains\x12%\n" +
+	"\x0einclude_values\x18\x04 \x01(\bR\rincludeValues\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x12\x14\n" +
+	"\x05field\x18\a \x01(\tR\x05field\x12\x1f\n" +
+	"\vfield_value\x18\b \x01(\tR\n" +
+	"fieldValue\"r\n" +
+	"\vSecretMatch\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\tR\x05value\"l\n" +
+	"\x15SearchSecretsResponse\x12+\n" +
+	"\amatches\x18\x01 \x03(\v2\x11.gaia.SecretMatchR\amatches\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x90\x01\n" +
+	"\x14MoveNamespaceRequest\x12\x1d\n" +
+	"\n" +
+	"src_client\x18\x01 \x01(\tR\tsrcClient\x12\x1d\n" +
+	"\n" +
+	"dst_client\x18\x02 \x01(\tR\tdstClient\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x1c\n" +
+	"\toverwrite\x18\x04 \x01(\bR\toverwrite\"8\n" +
+	"\x15MoveNamespaceResponse\x12\x1f\n" +
+	"\vmoved_count\x18\x01 \x01(\x05R\n" +
+	"movedCount\"\xa0\x01\n" +
+	"\x13RenameSecretRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x15\n" +
+	"\x06old_id\x18\x03 \x01(\tR\x05oldId\x12\x15\n" +
+	"\x06new_id\x18\x04 \x01(\tR\x05newId\x12\x1c\n" +
+	"\toverwrite\x18\x05 \x01(\bR\toverwrite\"\x16\n" +
+	"\x14RenameSecretResponse\"\x18\n" +
+	"\x16VerifyIntegrityRequest\"y\n" +
+	"\x10IntegrityFailure\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"g\n" +
+	"\x17VerifyIntegrityResponse\x12\x18\n" +
+	"\achecked\x18\x01 \x01(\x05R\achecked\x122\n" +
+	"\bfailures\x18\x02 \x03(\v2\x16.gaia.IntegrityFailureR\bfailures\";\n" +
+	"\x12RekeyDryRunRequest\x12%\n" +
+	"\x0eold_passphrase\x18\x01 \x01(\tR\roldPassphrase\"c\n" +
+	"\x13RekeyDryRunResponse\x12\x18\n" +
+	"\asecrets\x18\x01 \x01(\x05R\asecrets\x122\n" +
+	"\bfailures\x18\x02 \x03(\v2\x16.gaia.IntegrityFailureR\bfailures\"\x7f\n" +
+	"\fRekeyRequest\x12%\n" +
+	"\x0eold_passphrase\x18\x01 \x01(\tR\roldPassphrase\x12%\n" +
+	"\x0enew_passphrase\x18\x02 \x01(\tR\rnewPassphrase\x12!\n" +
+	"\fverify_first\x18\x03 \x01(\bR\vverifyFirst\")\n" +
+	"\rRekeyResponse\x12\x18\n" +
+	"\asecrets\x18\x01 \x01(\x05R\asecrets\"\x82\x01\n" +
+	"\x13PruneOrphansRequest\x12\x17\n" +
+	"\adry_run\x18\x01 \x01(\bR\x06dryRun\x122\n" +
+	"\x15include_empty_clients\x18\x02 \x01(\bR\x13includeEmptyClients\x12\x1e\n" +
+	"\n" +
+	"passphrase\x18\x03 \x01(\tR\n" +
+	"passphrase\"K\n" +
+	"\x0eOrphanedClient\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x18\n" +
+	"\asecrets\x18\x02 \x01(\x05R\asecrets\"m\n" +
+	"\x14PruneOrphansResponse\x120\n" +
+	"\borphaned\x18\x01 \x03(\v2\x14.gaia.OrphanedClientR\borphaned\x12#\n" +
+	"\rempty_clients\x18\x02 \x03(\tR\femptyClients\"\x92\x01\n" +
+	"\x19DeleteSecretsByTagRequest\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x17\n" +
+	"\atag_key\x18\x02 \x01(\tR\x06tagKey\x12\x1b\n" +
+	"\ttag_value\x18\x03 \x01(\tR\btagValue\x12\x1e\n" +
+	"\n" +
+	"passphrase\x18\x04 \x01(\tR\n" +
+	"passphrase\"6\n" +
+	"\x1aDeleteSecretsByTagResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\x05R\adeleted2\x85\x14\n" +
 	"\tGaiaAdmin\x12<\n" +
 	"\tAddSecret\x12\x16.gaia.AddSecretRequest\x1a\x17.gaia.AddSecretResponse\x12E\n" +
 	"\fDeleteSecret\x12\x19.gaia.DeleteSecretRequest\x1a\x1a.gaia.DeleteSecretResponse\x12B\n" +
-	"\vListSecrets\x12\x18.gaia.ListSecretsRequest\x1a\x19.gaia.ListSecretsResponse\x12<\n" +
-	"\tGetStatus\x12\x16.gaia.GetStatusRequest\x1a\x17.gaia.GetStatusResponse\x12-\n" +
+	"\vListSecrets\x12\x18.gaia.ListSecretsRequest\x1a\x19.gaia.ListSecretsResponse\x12H\n" +
+	"\rStreamSecrets\x12\x18.gaia.ListSecretsRequest\x1a\x1b.gaia.StreamSecretsResponse0\x01\x12<\n" +
+	"\tGetStatus\x12\x16.gaia.GetStatusRequest\x1a\x17.gaia.GetStatusResponse\x12?\n" +
+	"\n" +
+	"GetMetrics\x12\x17.gaia.GetMetricsRequest\x1a\x18.gaia.GetMetricsResponse\x12-\n" +
 	"\x04Stop\x12\x11.gaia.StopRequest\x1a\x12.gaia.StopResponse\x123\n" +
 	"\x06Unlock\x12\x13.gaia.UnlockRequest\x1a\x14.gaia.UnlockResponse\x12-\n" +
 	"\x04Lock\x12\x11.gaia.LockRequest\x1a\x12.gaia.LockResponse\x12K\n" +
@@ -1573,10 +5428,36 @@ const file_gaia_proto_rawDesc = "" +
 	"\vListClients\x12\x18.gaia.ListClientsRequest\x1a\x19.gaia.ListClientsResponse\x12K\n" +
 	"\x0eListNamespaces\x12\x1b.gaia.ListNamespacesRequest\x1a\x1c.gaia.ListNamespacesResponse\x12E\n" +
 	"\fRevokeClient\x12\x19.gaia.RevokeClientRequest\x1a\x1a.gaia.RevokeClientResponse\x12J\n" +
-	"\rImportSecrets\x12\x1a.gaia.ImportSecretsRequest\x1a\x1b.gaia.ImportSecretsResponse(\x012?\n" +
+	"\rImportSecrets\x12\x1a.gaia.ImportSecretsRequest\x1a\x1b.gaia.ImportSecretsResponse(\x01\x12X\n" +
+	"\x19ImportSecretsWithProgress\x12\x1a.gaia.ImportSecretsRequest\x1a\x1b.gaia.ImportSecretsProgress(\x010\x01\x12?\n" +
+	"\n" +
+	"SetSecrets\x12\x17.gaia.SetSecretsRequest\x1a\x18.gaia.SetSecretsResponse\x12H\n" +
+	"\rExportSecrets\x12\x1a.gaia.ExportSecretsRequest\x1a\x1b.gaia.ExportSecretsResponse\x12H\n" +
+	"\rRotateSecrets\x12\x1a.gaia.RotateSecretsRequest\x1a\x1b.gaia.RotateSecretsResponse\x12N\n" +
+	"\x0fSetCommonGrants\x12\x1c.gaia.SetCommonGrantsRequest\x1a\x1d.gaia.SetCommonGrantsResponse\x12]\n" +
+	"\x14ExportClientManifest\x12!.gaia.ExportClientManifestRequest\x1a\".gaia.ExportClientManifestResponse\x12H\n" +
+	"\rExportClients\x12\x1a.gaia.ExportClientsRequest\x1a\x1b.gaia.ExportClientsResponse\x12H\n" +
+	"\rImportClients\x12\x1a.gaia.ImportClientsRequest\x1a\x1b.gaia.ImportClientsResponse\x12K\n" +
+	"\x0eGetSecretUsage\x12\x1b.gaia.GetSecretUsageRequest\x1a\x1c.gaia.GetSecretUsageResponse\x12H\n" +
+	"\rMoveNamespace\x12\x1a.gaia.MoveNamespaceRequest\x1a\x1b.gaia.MoveNamespaceResponse\x12E\n" +
+	"\fRenameSecret\x12\x19.gaia.RenameSecretRequest\x1a\x1a.gaia.RenameSecretResponse\x12]\n" +
+	"\x14SetNamespacePatterns\x12!.gaia.SetNamespacePatternsRequest\x1a\".gaia.SetNamespacePatternsResponse\x12N\n" +
+	"\x0fSetNamespaceTTL\x12\x1c.gaia.SetNamespaceTTLRequest\x1a\x1d.gaia.SetNamespaceTTLResponse\x12T\n" +
+	"\x11ClearNamespaceTTL\x12\x1e.gaia.ClearNamespaceTTLRequest\x1a\x1f.gaia.ClearNamespaceTTLResponse\x12]\n" +
+	"\x14SetNamespaceRotation\x12!.gaia.SetNamespaceRotationRequest\x1a\".gaia.SetNamespaceRotationResponse\x12H\n" +
+	"\rSearchSecrets\x12\x1a.gaia.SearchSecretsRequest\x1a\x1b.gaia.SearchSecretsResponse\x12N\n" +
+	"\x0fVerifyIntegrity\x12\x1c.gaia.VerifyIntegrityRequest\x1a\x1d.gaia.VerifyIntegrityResponse\x12B\n" +
+	"\vRekeyDryRun\x12\x18.gaia.RekeyDryRunRequest\x1a\x19.gaia.RekeyDryRunResponse\x120\n" +
+	"\x05Rekey\x12\x12.gaia.RekeyRequest\x1a\x13.gaia.RekeyResponse\x12E\n" +
+	"\fPruneOrphans\x12\x19.gaia.PruneOrphansRequest\x1a\x1a.gaia.PruneOrphansResponse\x12W\n" +
+	"\x12DeleteSecretsByTag\x12\x1f.gaia.DeleteSecretsByTagRequest\x1a .gaia.DeleteSecretsByTagResponse2\x91\x03\n" +
 	"\n" +
 	"GaiaClient\x121\n" +
-	"\tGetSecret\x12\x16.gaia.GetSecretRequest\x1a\f.gaia.SecretB+Z)github.com/stain-win/gaia/apps/gaia/protob\x06proto3"
+	"\tGetSecret\x12\x16.gaia.GetSecretRequest\x1a\f.gaia.Secret\x12Q\n" +
+	"\x10GetCommonSecrets\x12\x1d.gaia.GetCommonSecretsRequest\x1a\x1e.gaia.GetCommonSecretsResponse\x12]\n" +
+	"\x14CreateSecretIfAbsent\x12!.gaia.CreateSecretIfAbsentRequest\x1a\".gaia.CreateSecretIfAbsentResponse\x12K\n" +
+	"\x0eListOwnSecrets\x12\x1b.gaia.ListOwnSecretsRequest\x1a\x1c.gaia.ListOwnSecretsResponse\x12Q\n" +
+	"\x10ListOwnSecretIds\x12\x1d.gaia.ListOwnSecretIdsRequest\x1a\x1e.gaia.ListOwnSecretIdsResponseB+Z)github.com/stain-win/gaia/apps/gaia/protob\x06proto3"
 
 var (
 	file_gaia_proto_rawDescOnce sync.Once
@@ -1590,76 +5471,208 @@ func file_gaia_proto_rawDescGZIP() []byte {
 	return file_gaia_proto_rawDescData
 }
 
-var file_gaia_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_gaia_proto_msgTypes = make([]protoimpl.MessageInfo, 90)
 var file_gaia_proto_goTypes = []any{
-	(*Secret)(nil),                 // 0: gaia.Secret
-	(*Namespace)(nil),              // 1: gaia.Namespace
-	(*AddSecretRequest)(nil),       // 2: gaia.AddSecretRequest
-	(*AddSecretResponse)(nil),      // 3: gaia.AddSecretResponse
-	(*GetSecretRequest)(nil),       // 4: gaia.GetSecretRequest
-	(*GetStatusRequest)(nil),       // 5: gaia.GetStatusRequest
-	(*GetStatusResponse)(nil),      // 6: gaia.GetStatusResponse
-	(*StopRequest)(nil),            // 7: gaia.StopRequest
-	(*StopResponse)(nil),           // 8: gaia.StopResponse
-	(*UnlockRequest)(nil),          // 9: gaia.UnlockRequest
-	(*UnlockResponse)(nil),         // 10: gaia.UnlockResponse
-	(*LockRequest)(nil),            // 11: gaia.LockRequest
-	(*LockResponse)(nil),           // 12: gaia.LockResponse
-	(*RegisterClientRequest)(nil),  // 13: gaia.RegisterClientRequest
-	(*RegisterClientResponse)(nil), // 14: gaia.RegisterClientResponse
-	(*Client)(nil),                 // 15: gaia.Client
-	(*ListClientsRequest)(nil),     // 16: gaia.ListClientsRequest
-	(*ListClientsResponse)(nil),    // 17: gaia.ListClientsResponse
-	(*ListNamespacesRequest)(nil),  // 18: gaia.ListNamespacesRequest
-	(*ListNamespacesResponse)(nil), // 19: gaia.ListNamespacesResponse
-	(*RevokeClientRequest)(nil),    // 20: gaia.RevokeClientRequest
-	(*RevokeClientResponse)(nil),   // 21: gaia.RevokeClientResponse
-	(*DeleteSecretRequest)(nil),    // 22: gaia.DeleteSecretRequest
-	(*DeleteSecretResponse)(nil),   // 23: gaia.DeleteSecretResponse
-	(*ImportSecretsConfig)(nil),    // 24: gaia.ImportSecretsConfig
-	(*ImportSecretItem)(nil),       // 25: gaia.ImportSecretItem
-	(*ImportSecretsRequest)(nil),   // 26: gaia.ImportSecretsRequest
-	(*ImportSecretsResponse)(nil),  // 27: gaia.ImportSecretsResponse
-	(*ListSecretsResponse)(nil),    // 28: gaia.ListSecretsResponse
-	(*ListSecretsRequest)(nil),     // 29: gaia.ListSecretsRequest
+	(*Secret)(nil),                       // 0: gaia.Secret
+	(*CreateSecretIfAbsentRequest)(nil),  // 1: gaia.CreateSecretIfAbsentRequest
+	(*CreateSecretIfAbsentResponse)(nil), // 2: gaia.CreateSecretIfAbsentResponse
+	(*ListOwnSecretsRequest)(nil),        // 3: gaia.ListOwnSecretsRequest
+	(*ListOwnSecretsResponse)(nil),       // 4: gaia.ListOwnSecretsResponse
+	(*ListOwnSecretIdsRequest)(nil),      // 5: gaia.ListOwnSecretIdsRequest
+	(*ListOwnSecretIdsResponse)(nil),     // 6: gaia.ListOwnSecretIdsResponse
+	(*Namespace)(nil),                    // 7: gaia.Namespace
+	(*AddSecretRequest)(nil),             // 8: gaia.AddSecretRequest
+	(*AddSecretResponse)(nil),            // 9: gaia.AddSecretResponse
+	(*GetSecretRequest)(nil),             // 10: gaia.GetSecretRequest
+	(*GetStatusRequest)(nil),             // 11: gaia.GetStatusRequest
+	(*GetStatusResponse)(nil),            // 12: gaia.GetStatusResponse
+	(*GetMetricsRequest)(nil),            // 13: gaia.GetMetricsRequest
+	(*GetMetricsResponse)(nil),           // 14: gaia.GetMetricsResponse
+	(*StopRequest)(nil),                  // 15: gaia.StopRequest
+	(*StopResponse)(nil),                 // 16: gaia.StopResponse
+	(*UnlockRequest)(nil),                // 17: gaia.UnlockRequest
+	(*UnlockResponse)(nil),               // 18: gaia.UnlockResponse
+	(*LockRequest)(nil),                  // 19: gaia.LockRequest
+	(*LockResponse)(nil),                 // 20: gaia.LockResponse
+	(*RegisterClientRequest)(nil),        // 21: gaia.RegisterClientRequest
+	(*RegisterClientResponse)(nil),       // 22: gaia.RegisterClientResponse
+	(*Client)(nil),                       // 23: gaia.Client
+	(*ListClientsRequest)(nil),           // 24: gaia.ListClientsRequest
+	(*ListClientsResponse)(nil),          // 25: gaia.ListClientsResponse
+	(*ListNamespacesRequest)(nil),        // 26: gaia.ListNamespacesRequest
+	(*ListNamespacesResponse)(nil),       // 27: gaia.ListNamespacesResponse
+	(*RevokeClientRequest)(nil),          // 28: gaia.RevokeClientRequest
+	(*RevokeClientResponse)(nil),         // 29: gaia.RevokeClientResponse
+	(*DeleteSecretRequest)(nil),          // 30: gaia.DeleteSecretRequest
+	(*DeleteSecretResponse)(nil),         // 31: gaia.DeleteSecretResponse
+	(*ImportSecretsConfig)(nil),          // 32: gaia.ImportSecretsConfig
+	(*ImportSecretItem)(nil),             // 33: gaia.ImportSecretItem
+	(*ImportSecretsRequest)(nil),         // 34: gaia.ImportSecretsRequest
+	(*ImportSecretsResponse)(nil),        // 35: gaia.ImportSecretsResponse
+	(*ImportSecretsProgress)(nil),        // 36: gaia.ImportSecretsProgress
+	(*ValueViolation)(nil),               // 37: gaia.ValueViolation
+	(*SetSecretsRequest)(nil),            // 38: gaia.SetSecretsRequest
+	(*SetSecretsResponse)(nil),           // 39: gaia.SetSecretsResponse
+	(*ListSecretsResponse)(nil),          // 40: gaia.ListSecretsResponse
+	(*ListSecretsRequest)(nil),           // 41: gaia.ListSecretsRequest
+	(*StreamSecretsResponse)(nil),        // 42: gaia.StreamSecretsResponse
+	(*ExportSecretsRequest)(nil),         // 43: gaia.ExportSecretsRequest
+	(*ExportSecretsResponse)(nil),        // 44: gaia.ExportSecretsResponse
+	(*RotateSecretsRequest)(nil),         // 45: gaia.RotateSecretsRequest
+	(*RotatedSecret)(nil),                // 46: gaia.RotatedSecret
+	(*RotateSecretsResponse)(nil),        // 47: gaia.RotateSecretsResponse
+	(*GetCommonSecretsRequest)(nil),      // 48: gaia.GetCommonSecretsRequest
+	(*GetCommonSecretsResponse)(nil),     // 49: gaia.GetCommonSecretsResponse
+	(*SetCommonGrantsRequest)(nil),       // 50: gaia.SetCommonGrantsRequest
+	(*SetCommonGrantsResponse)(nil),      // 51: gaia.SetCommonGrantsResponse
+	(*SetNamespacePatternsRequest)(nil),  // 52: gaia.SetNamespacePatternsRequest
+	(*SetNamespacePatternsResponse)(nil), // 53: gaia.SetNamespacePatternsResponse
+	(*SetNamespaceTTLRequest)(nil),       // 54: gaia.SetNamespaceTTLRequest
+	(*SetNamespaceTTLResponse)(nil),      // 55: gaia.SetNamespaceTTLResponse
+	(*ClearNamespaceTTLRequest)(nil),     // 56: gaia.ClearNamespaceTTLRequest
+	(*ClearNamespaceTTLResponse)(nil),    // 57: gaia.ClearNamespaceTTLResponse
+	(*SetNamespaceRotationRequest)(nil),  // 58: gaia.SetNamespaceRotationRequest
+	(*SetNamespaceRotationResponse)(nil), // 59: gaia.SetNamespaceRotationResponse
+	(*ExportClientManifestRequest)(nil),  // 60: gaia.ExportClientManifestRequest
+	(*ClientManifestEntry)(nil),          // 61: gaia.ClientManifestEntry
+	(*ExportClientManifestResponse)(nil), // 62: gaia.ExportClientManifestResponse
+	(*ExportClientsRequest)(nil),         // 63: gaia.ExportClientsRequest
+	(*ExportClientsResponse)(nil),        // 64: gaia.ExportClientsResponse
+	(*ImportClientsRequest)(nil),         // 65: gaia.ImportClientsRequest
+	(*ImportClientsResponse)(nil),        // 66: gaia.ImportClientsResponse
+	(*GetSecretUsageRequest)(nil),        // 67: gaia.GetSecretUsageRequest
+	(*SecretUsage)(nil),                  // 68: gaia.SecretUsage
+	(*GetSecretUsageResponse)(nil),       // 69: gaia.GetSecretUsageResponse
+	(*SearchSecretsRequest)(nil),         // 70: gaia.SearchSecretsRequest
+	(*SecretMatch)(nil),                  // 71: gaia.SecretMatch
+	(*SearchSecretsResponse)(nil),        // 72: gaia.SearchSecretsResponse
+	(*MoveNamespaceRequest)(nil),         // 73: gaia.MoveNamespaceRequest
+	(*MoveNamespaceResponse)(nil),        // 74: gaia.MoveNamespaceResponse
+	(*RenameSecretRequest)(nil),          // 75: gaia.RenameSecretRequest
+	(*RenameSecretResponse)(nil),         // 76: gaia.RenameSecretResponse
+	(*VerifyIntegrityRequest)(nil),       // 77: gaia.VerifyIntegrityRequest
+	(*IntegrityFailure)(nil),             // 78: gaia.IntegrityFailure
+	(*VerifyIntegrityResponse)(nil),      // 79: gaia.VerifyIntegrityResponse
+	(*RekeyDryRunRequest)(nil),           // 80: gaia.RekeyDryRunRequest
+	(*RekeyDryRunResponse)(nil),          // 81: gaia.RekeyDryRunResponse
+	(*RekeyRequest)(nil),                 // 82: gaia.RekeyRequest
+	(*RekeyResponse)(nil),                // 83: gaia.RekeyResponse
+	(*PruneOrphansRequest)(nil),          // 84: gaia.PruneOrphansRequest
+	(*OrphanedClient)(nil),               // 85: gaia.OrphanedClient
+	(*PruneOrphansResponse)(nil),         // 86: gaia.PruneOrphansResponse
+	(*DeleteSecretsByTagRequest)(nil),    // 87: gaia.DeleteSecretsByTagRequest
+	(*DeleteSecretsByTagResponse)(nil),   // 88: gaia.DeleteSecretsByTagResponse
+	nil,                                  // 89: gaia.AddSecretRequest.TagsEntry
 }
 var file_gaia_proto_depIdxs = []int32{
-	0,  // 0: gaia.Namespace.secrets:type_name -> gaia.Secret
-	15, // 1: gaia.ListClientsResponse.clients:type_name -> gaia.Client
-	24, // 2: gaia.ImportSecretsRequest.config:type_name -> gaia.ImportSecretsConfig
-	25, // 3: gaia.ImportSecretsRequest.item:type_name -> gaia.ImportSecretItem
-	1,  // 4: gaia.ListSecretsResponse.namespaces:type_name -> gaia.Namespace
-	2,  // 5: gaia.GaiaAdmin.AddSecret:input_type -> gaia.AddSecretRequest
-	22, // 6: gaia.GaiaAdmin.DeleteSecret:input_type -> gaia.DeleteSecretRequest
-	29, // 7: gaia.GaiaAdmin.ListSecrets:input_type -> gaia.ListSecretsRequest
-	5,  // 8: gaia.GaiaAdmin.GetStatus:input_type -> gaia.GetStatusRequest
-	7,  // 9: gaia.GaiaAdmin.Stop:input_type -> gaia.StopRequest
-	9,  // 10: gaia.GaiaAdmin.Unlock:input_type -> gaia.UnlockRequest
-	11, // 11: gaia.GaiaAdmin.Lock:input_type -> gaia.LockRequest
-	13, // 12: gaia.GaiaAdmin.RegisterClient:input_type -> gaia.RegisterClientRequest
-	16, // 13: gaia.GaiaAdmin.ListClients:input_type -> gaia.ListClientsRequest
-	18, // 14: gaia.GaiaAdmin.ListNamespaces:input_type -> gaia.ListNamespacesRequest
-	20, // 15: gaia.GaiaAdmin.RevokeClient:input_type -> gaia.RevokeClientRequest
-	26, // 16: gaia.GaiaAdmin.ImportSecrets:input_type -> gaia.ImportSecretsRequest
-	4,  // 17: gaia.GaiaClient.GetSecret:input_type -> gaia.GetSecretRequest
-	3,  // 18: gaia.GaiaAdmin.AddSecret:output_type -> gaia.AddSecretResponse
-	23, // 19: gaia.GaiaAdmin.DeleteSecret:output_type -> gaia.DeleteSecretResponse
-	28, // 20: gaia.GaiaAdmin.ListSecrets:output_type -> gaia.ListSecretsResponse
-	6,  // 21: gaia.GaiaAdmin.GetStatus:output_type -> gaia.GetStatusResponse
-	8,  // 22: gaia.GaiaAdmin.Stop:output_type -> gaia.StopResponse
-	10, // 23: gaia.GaiaAdmin.Unlock:output_type -> gaia.UnlockResponse
-	12, // 24: gaia.GaiaAdmin.Lock:output_type -> gaia.LockResponse
-	14, // 25: gaia.GaiaAdmin.RegisterClient:output_type -> gaia.RegisterClientResponse
-	17, // 26: gaia.GaiaAdmin.ListClients:output_type -> gaia.ListClientsResponse
-	19, // 27: gaia.GaiaAdmin.ListNamespaces:output_type -> gaia.ListNamespacesResponse
-	21, // 28: gaia.GaiaAdmin.RevokeClient:output_type -> gaia.RevokeClientResponse
-	27, // 29: gaia.GaiaAdmin.ImportSecrets:output_type -> gaia.ImportSecretsResponse
-	0,  // 30: gaia.GaiaClient.GetSecret:output_type -> gaia.Secret
-	18, // [18:31] is the sub-list for method output_type
-	5,  // [5:18] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	0,  // 0: gaia.ListOwnSecretsResponse.secrets:type_name -> gaia.Secret
+	0,  // 1: gaia.Namespace.secrets:type_name -> gaia.Secret
+	89, // 2: gaia.AddSecretRequest.tags:type_name -> gaia.AddSecretRequest.TagsEntry
+	23, // 3: gaia.ListClientsResponse.clients:type_name -> gaia.Client
+	32, // 4: gaia.ImportSecretsRequest.config:type_name -> gaia.ImportSecretsConfig
+	33, // 5: gaia.ImportSecretsRequest.item:type_name -> gaia.ImportSecretItem
+	37, // 6: gaia.ImportSecretsResponse.violations:type_name -> gaia.ValueViolation
+	35, // 7: gaia.ImportSecretsProgress.result:type_name -> gaia.ImportSecretsResponse
+	33, // 8: gaia.SetSecretsRequest.secrets:type_name -> gaia.ImportSecretItem
+	37, // 9: gaia.SetSecretsResponse.violations:type_name -> gaia.ValueViolation
+	7,  // 10: gaia.ListSecretsResponse.namespaces:type_name -> gaia.Namespace
+	7,  // 11: gaia.StreamSecretsResponse.namespace:type_name -> gaia.Namespace
+	33, // 12: gaia.ExportSecretsResponse.items:type_name -> gaia.ImportSecretItem
+	46, // 13: gaia.RotateSecretsResponse.secrets:type_name -> gaia.RotatedSecret
+	7,  // 14: gaia.GetCommonSecretsResponse.namespaces:type_name -> gaia.Namespace
+	61, // 15: gaia.ExportClientManifestResponse.clients:type_name -> gaia.ClientManifestEntry
+	23, // 16: gaia.ExportClientsResponse.clients:type_name -> gaia.Client
+	23, // 17: gaia.ImportClientsRequest.clients:type_name -> gaia.Client
+	68, // 18: gaia.GetSecretUsageResponse.secrets:type_name -> gaia.SecretUsage
+	71, // 19: gaia.SearchSecretsResponse.matches:type_name -> gaia.SecretMatch
+	78, // 20: gaia.VerifyIntegrityResponse.failures:type_name -> gaia.IntegrityFailure
+	78, // 21: gaia.RekeyDryRunResponse.failures:type_name -> gaia.IntegrityFailure
+	85, // 22: gaia.PruneOrphansResponse.orphaned:type_name -> gaia.OrphanedClient
+	8,  // 23: gaia.GaiaAdmin.AddSecret:input_type -> gaia.AddSecretRequest
+	30, // 24: gaia.GaiaAdmin.DeleteSecret:input_type -> gaia.DeleteSecretRequest
+	41, // 25: gaia.GaiaAdmin.ListSecrets:input_type -> gaia.ListSecretsRequest
+	41, // 26: gaia.GaiaAdmin.StreamSecrets:input_type -> gaia.ListSecretsRequest
+	11, // 27: gaia.GaiaAdmin.GetStatus:input_type -> gaia.GetStatusRequest
+	13, // 28: gaia.GaiaAdmin.GetMetrics:input_type -> gaia.GetMetricsRequest
+	15, // 29: gaia.GaiaAdmin.Stop:input_type -> gaia.StopRequest
+	17, // 30: gaia.GaiaAdmin.Unlock:input_type -> gaia.UnlockRequest
+	19, // 31: gaia.GaiaAdmin.Lock:input_type -> gaia.LockRequest
+	21, // 32: gaia.GaiaAdmin.RegisterClient:input_type -> gaia.RegisterClientRequest
+	24, // 33: gaia.GaiaAdmin.ListClients:input_type -> gaia.ListClientsRequest
+	26, // 34: gaia.GaiaAdmin.ListNamespaces:input_type -> gaia.ListNamespacesRequest
+	28, // 35: gaia.GaiaAdmin.RevokeClient:input_type -> gaia.RevokeClientRequest
+	34, // 36: gaia.GaiaAdmin.ImportSecrets:input_type -> gaia.ImportSecretsRequest
+	34, // 37: gaia.GaiaAdmin.ImportSecretsWithProgress:input_type -> gaia.ImportSecretsRequest
+	38, // 38: gaia.GaiaAdmin.SetSecrets:input_type -> gaia.SetSecretsRequest
+	43, // 39: gaia.GaiaAdmin.ExportSecrets:input_type -> gaia.ExportSecretsRequest
+	45, // 40: gaia.GaiaAdmin.RotateSecrets:input_type -> gaia.RotateSecretsRequest
+	50, // 41: gaia.GaiaAdmin.SetCommonGrants:input_type -> gaia.SetCommonGrantsRequest
+	60, // 42: gaia.GaiaAdmin.ExportClientManifest:input_type -> gaia.ExportClientManifestRequest
+	63, // 43: gaia.GaiaAdmin.ExportClients:input_type -> gaia.ExportClientsRequest
+	65, // 44: gaia.GaiaAdmin.ImportClients:input_type -> gaia.ImportClientsRequest
+	67, // 45: gaia.GaiaAdmin.GetSecretUsage:input_type -> gaia.GetSecretUsageRequest
+	73, // 46: gaia.GaiaAdmin.MoveNamespace:input_type -> gaia.MoveNamespaceRequest
+	75, // 47: gaia.GaiaAdmin.RenameSecret:input_type -> gaia.RenameSecretRequest
+	52, // 48: gaia.GaiaAdmin.SetNamespacePatterns:input_type -> gaia.SetNamespacePatternsRequest
+	54, // 49: gaia.GaiaAdmin.SetNamespaceTTL:input_type -> gaia.SetNamespaceTTLRequest
+	56, // 50: gaia.GaiaAdmin.ClearNamespaceTTL:input_type -> gaia.ClearNamespaceTTLRequest
+	58, // 51: gaia.GaiaAdmin.SetNamespaceRotation:input_type -> gaia.SetNamespaceRotationRequest
+	70, // 52: gaia.GaiaAdmin.SearchSecrets:input_type -> gaia.SearchSecretsRequest
+	77, // 53: gaia.GaiaAdmin.VerifyIntegrity:input_type -> gaia.VerifyIntegrityRequest
+	80, // 54: gaia.GaiaAdmin.RekeyDryRun:input_type -> gaia.RekeyDryRunRequest
+	82, // 55: gaia.GaiaAdmin.Rekey:input_type -> gaia.RekeyRequest
+	84, // 56: gaia.GaiaAdmin.PruneOrphans:input_type -> gaia.PruneOrphansRequest
+	87, // 57: gaia.GaiaAdmin.DeleteSecretsByTag:input_type -> gaia.DeleteSecretsByTagRequest
+	10, // 58: gaia.GaiaClient.GetSecret:input_type -> gaia.GetSecretRequest
+	48, // 59: gaia.GaiaClient.GetCommonSecrets:input_type -> gaia.GetCommonSecretsRequest
+	1,  // 60: gaia.GaiaClient.CreateSecretIfAbsent:input_type -> gaia.CreateSecretIfAbsentRequest
+	3,  // 61: gaia.GaiaClient.ListOwnSecrets:input_type -> gaia.ListOwnSecretsRequest
+	5,  // 62: gaia.GaiaClient.ListOwnSecretIds:input_type -> gaia.ListOwnSecretIdsRequest
+	9,  // 63: gaia.GaiaAdmin.AddSecret:output_type -> gaia.AddSecretResponse
+	31, // 64: gaia.GaiaAdmin.DeleteSecret:output_type -> gaia.DeleteSecretResponse
+	40, // 65: gaia.GaiaAdmin.ListSecrets:output_type -> gaia.ListSecretsResponse
+	42, // 66: gaia.GaiaAdmin.StreamSecrets:output_type -> gaia.StreamSecretsResponse
+	12, // 67: gaia.GaiaAdmin.GetStatus:output_type -> gaia.GetStatusResponse
+	14, // 68: gaia.GaiaAdmin.GetMetrics:output_type -> gaia.GetMetricsResponse
+	16, // 69: gaia.GaiaAdmin.Stop:output_type -> gaia.StopResponse
+	18, // 70: gaia.GaiaAdmin.Unlock:output_type -> gaia.UnlockResponse
+	20, // 71: gaia.GaiaAdmin.Lock:output_type -> gaia.LockResponse
+	22, // 72: gaia.GaiaAdmin.RegisterClient:output_type -> gaia.RegisterClientResponse
+	25, // 73: gaia.GaiaAdmin.ListClients:output_type -> gaia.ListClientsResponse
+	27, // 74: gaia.GaiaAdmin.ListNamespaces:output_type -> gaia.ListNamespacesResponse
+	29, // 75: gaia.GaiaAdmin.RevokeClient:output_type -> gaia.RevokeClientResponse
+	35, // 76: gaia.GaiaAdmin.ImportSecrets:output_type -> gaia.ImportSecretsResponse
+	36, // 77: gaia.GaiaAdmin.ImportSecretsWithProgress:output_type -> gaia.ImportSecretsProgress
+	39, // 78: gaia.GaiaAdmin.SetSecrets:output_type -> gaia.SetSecretsResponse
+	44, // 79: gaia.GaiaAdmin.ExportSecrets:output_type -> gaia.ExportSecretsResponse
+	47, // 80: gaia.GaiaAdmin.RotateSecrets:output_type -> gaia.RotateSecretsResponse
+	51, // 81: gaia.GaiaAdmin.SetCommonGrants:output_type -> gaia.SetCommonGrantsResponse
+	62, // 82: gaia.GaiaAdmin.ExportClientManifest:output_type -> gaia.ExportClientManifestResponse
+	64, // 83: gaia.GaiaAdmin.ExportClients:output_type -> gaia.ExportClientsResponse
+	66, // 84: gaia.GaiaAdmin.ImportClients:output_type -> gaia.ImportClientsResponse
+	69, // 85: gaia.GaiaAdmin.GetSecretUsage:output_type -> gaia.GetSecretUsageResponse
+	74, // 86: gaia.GaiaAdmin.MoveNamespace:output_type -> gaia.MoveNamespaceResponse
+	76, // 87: gaia.GaiaAdmin.RenameSecret:output_type -> gaia.RenameSecretResponse
+	53, // 88: gaia.GaiaAdmin.SetNamespacePatterns:output_type -> gaia.SetNamespacePatternsResponse
+	55, // 89: gaia.GaiaAdmin.SetNamespaceTTL:output_type -> gaia.SetNamespaceTTLResponse
+	57, // 90: gaia.GaiaAdmin.ClearNamespaceTTL:output_type -> gaia.ClearNamespaceTTLResponse
+	59, // 91: gaia.GaiaAdmin.SetNamespaceRotation:output_type -> gaia.SetNamespaceRotationResponse
+	72, // 92: gaia.GaiaAdmin.SearchSecrets:output_type -> gaia.SearchSecretsResponse
+	79, // 93: gaia.GaiaAdmin.VerifyIntegrity:output_type -> gaia.VerifyIntegrityResponse
+	81, // 94: gaia.GaiaAdmin.RekeyDryRun:output_type -> gaia.RekeyDryRunResponse
+	83, // 95: gaia.GaiaAdmin.Rekey:output_type -> gaia.RekeyResponse
+	86, // 96: gaia.GaiaAdmin.PruneOrphans:output_type -> gaia.PruneOrphansResponse
+	88, // 97: gaia.GaiaAdmin.DeleteSecretsByTag:output_type -> gaia.DeleteSecretsByTagResponse
+	0,  // 98: gaia.GaiaClient.GetSecret:output_type -> gaia.Secret
+	49, // 99: gaia.GaiaClient.GetCommonSecrets:output_type -> gaia.GetCommonSecretsResponse
+	2,  // 100: gaia.GaiaClient.CreateSecretIfAbsent:output_type -> gaia.CreateSecretIfAbsentResponse
+	4,  // 101: gaia.GaiaClient.ListOwnSecrets:output_type -> gaia.ListOwnSecretsResponse
+	6,  // 102: gaia.GaiaClient.ListOwnSecretIds:output_type -> gaia.ListOwnSecretIdsResponse
+	63, // [63:103] is the sub-list for method output_type
+	23, // [23:63] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_gaia_proto_init() }
@@ -1667,17 +5680,22 @@ func file_gaia_proto_init() {
 	if File_gaia_proto != nil {
 		return
 	}
-	file_gaia_proto_msgTypes[26].OneofWrappers = []any{
+	file_gaia_proto_msgTypes[34].OneofWrappers = []any{
 		(*ImportSecretsRequest_Config)(nil),
 		(*ImportSecretsRequest_Item)(nil),
 	}
+	file_gaia_proto_msgTypes[36].OneofWrappers = []any{
+		(*ImportSecretsProgress_ItemsReceived)(nil),
+		(*ImportSecretsProgress_Result)(nil),
+	}
+	file_gaia_proto_msgTypes[48].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gaia_proto_rawDesc), len(file_gaia_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   30,
+			NumMessages:   90,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
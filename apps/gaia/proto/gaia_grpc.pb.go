@@ -19,18 +19,41 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	GaiaAdmin_AddSecret_FullMethodName      = "/gaia.GaiaAdmin/AddSecret"
-	GaiaAdmin_DeleteSecret_FullMethodName   = "/gaia.GaiaAdmin/DeleteSecret"
-	GaiaAdmin_ListSecrets_FullMethodName    = "/gaia.GaiaAdmin/ListSecrets"
-	GaiaAdmin_GetStatus_FullMethodName      = "/gaia.GaiaAdmin/GetStatus"
-	GaiaAdmin_Stop_FullMethodName           = "/gaia.GaiaAdmin/Stop"
-	GaiaAdmin_Unlock_FullMethodName         = "/gaia.GaiaAdmin/Unlock"
-	GaiaAdmin_Lock_FullMethodName           = "/gaia.GaiaAdmin/Lock"
-	GaiaAdmin_RegisterClient_FullMethodName = "/gaia.GaiaAdmin/RegisterClient"
-	GaiaAdmin_ListClients_FullMethodName    = "/gaia.GaiaAdmin/ListClients"
-	GaiaAdmin_ListNamespaces_FullMethodName = "/gaia.GaiaAdmin/ListNamespaces"
-	GaiaAdmin_RevokeClient_FullMethodName   = "/gaia.GaiaAdmin/RevokeClient"
-	GaiaAdmin_ImportSecrets_FullMethodName  = "/gaia.GaiaAdmin/ImportSecrets"
+	GaiaAdmin_AddSecret_FullMethodName                 = "/gaia.GaiaAdmin/AddSecret"
+	GaiaAdmin_DeleteSecret_FullMethodName              = "/gaia.GaiaAdmin/DeleteSecret"
+	GaiaAdmin_ListSecrets_FullMethodName               = "/gaia.GaiaAdmin/ListSecrets"
+	GaiaAdmin_StreamSecrets_FullMethodName             = "/gaia.GaiaAdmin/StreamSecrets"
+	GaiaAdmin_GetStatus_FullMethodName                 = "/gaia.GaiaAdmin/GetStatus"
+	GaiaAdmin_GetMetrics_FullMethodName                = "/gaia.GaiaAdmin/GetMetrics"
+	GaiaAdmin_Stop_FullMethodName                      = "/gaia.GaiaAdmin/Stop"
+	GaiaAdmin_Unlock_FullMethodName                    = "/gaia.GaiaAdmin/Unlock"
+	GaiaAdmin_Lock_FullMethodName                      = "/gaia.GaiaAdmin/Lock"
+	GaiaAdmin_RegisterClient_FullMethodName            = "/gaia.GaiaAdmin/RegisterClient"
+	GaiaAdmin_ListClients_FullMethodName               = "/gaia.GaiaAdmin/ListClients"
+	GaiaAdmin_ListNamespaces_FullMethodName            = "/gaia.GaiaAdmin/ListNamespaces"
+	GaiaAdmin_RevokeClient_FullMethodName              = "/gaia.GaiaAdmin/RevokeClient"
+	GaiaAdmin_ImportSecrets_FullMethodName             = "/gaia.GaiaAdmin/ImportSecrets"
+	GaiaAdmin_ImportSecretsWithProgress_FullMethodName = "/gaia.GaiaAdmin/ImportSecretsWithProgress"
+	GaiaAdmin_SetSecrets_FullMethodName                = "/gaia.GaiaAdmin/SetSecrets"
+	GaiaAdmin_ExportSecrets_FullMethodName             = "/gaia.GaiaAdmin/ExportSecrets"
+	GaiaAdmin_RotateSecrets_FullMethodName             = "/gaia.GaiaAdmin/RotateSecrets"
+	GaiaAdmin_SetCommonGrants_FullMethodName           = "/gaia.GaiaAdmin/SetCommonGrants"
+	GaiaAdmin_ExportClientManifest_FullMethodName      = "/gaia.GaiaAdmin/ExportClientManifest"
+	GaiaAdmin_ExportClients_FullMethodName             = "/gaia.GaiaAdmin/ExportClients"
+	GaiaAdmin_ImportClients_FullMethodName             = "/gaia.GaiaAdmin/ImportClients"
+	GaiaAdmin_GetSecretUsage_FullMethodName            = "/gaia.GaiaAdmin/GetSecretUsage"
+	GaiaAdmin_MoveNamespace_FullMethodName             = "/gaia.GaiaAdmin/MoveNamespace"
+	GaiaAdmin_RenameSecret_FullMethodName              = "/gaia.GaiaAdmin/RenameSecret"
+	GaiaAdmin_SetNamespacePatterns_FullMethodName      = "/gaia.GaiaAdmin/SetNamespacePatterns"
+	GaiaAdmin_SetNamespaceTTL_FullMethodName           = "/gaia.GaiaAdmin/SetNamespaceTTL"
+	GaiaAdmin_ClearNamespaceTTL_FullMethodName         = "/gaia.GaiaAdmin/ClearNamespaceTTL"
+	GaiaAdmin_SetNamespaceRotation_FullMethodName      = "/gaia.GaiaAdmin/SetNamespaceRotation"
+	GaiaAdmin_SearchSecrets_FullMethodName             = "/gaia.GaiaAdmin/SearchSecrets"
+	GaiaAdmin_VerifyIntegrity_FullMethodName           = "/gaia.GaiaAdmin/VerifyIntegrity"
+	GaiaAdmin_RekeyDryRun_FullMethodName               = "/gaia.GaiaAdmin/RekeyDryRun"
+	GaiaAdmin_Rekey_FullMethodName                     = "/gaia.GaiaAdmin/Rekey"
+	GaiaAdmin_PruneOrphans_FullMethodName              = "/gaia.GaiaAdmin/PruneOrphans"
+	GaiaAdmin_DeleteSecretsByTag_FullMethodName        = "/gaia.GaiaAdmin/DeleteSecretsByTag"
 )
 
 // GaiaAdminClient is the client API for GaiaAdmin service.
@@ -40,7 +63,9 @@ type GaiaAdminClient interface {
 	AddSecret(ctx context.Context, in *AddSecretRequest, opts ...grpc.CallOption) (*AddSecretResponse, error)
 	DeleteSecret(ctx context.Context, in *DeleteSecretRequest, opts ...grpc.CallOption) (*DeleteSecretResponse, error)
 	ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error)
+	StreamSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamSecretsResponse], error)
 	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	GetMetrics(ctx context.Context, in *GetMetricsRequest, opts ...grpc.CallOption) (*GetMetricsResponse, error)
 	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
 	Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error)
 	Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResponse, error)
@@ -49,6 +74,27 @@ type GaiaAdminClient interface {
 	ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error)
 	RevokeClient(ctx context.Context, in *RevokeClientRequest, opts ...grpc.CallOption) (*RevokeClientResponse, error)
 	ImportSecrets(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportSecretsRequest, ImportSecretsResponse], error)
+	ImportSecretsWithProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportSecretsRequest, ImportSecretsProgress], error)
+	SetSecrets(ctx context.Context, in *SetSecretsRequest, opts ...grpc.CallOption) (*SetSecretsResponse, error)
+	ExportSecrets(ctx context.Context, in *ExportSecretsRequest, opts ...grpc.CallOption) (*ExportSecretsResponse, error)
+	RotateSecrets(ctx context.Context, in *RotateSecretsRequest, opts ...grpc.CallOption) (*RotateSecretsResponse, error)
+	SetCommonGrants(ctx context.Context, in *SetCommonGrantsRequest, opts ...grpc.CallOption) (*SetCommonGrantsResponse, error)
+	ExportClientManifest(ctx context.Context, in *ExportClientManifestRequest, opts ...grpc.CallOption) (*ExportClientManifestResponse, error)
+	ExportClients(ctx context.Context, in *ExportClientsRequest, opts ...grpc.CallOption) (*ExportClientsResponse, error)
+	ImportClients(ctx context.Context, in *ImportClientsRequest, opts ...grpc.CallOption) (*ImportClientsResponse, error)
+	GetSecretUsage(ctx context.Context, in *GetSecretUsageRequest, opts ...grpc.CallOption) (*GetSecretUsageResponse, error)
+	MoveNamespace(ctx context.Context, in *MoveNamespaceRequest, opts ...grpc.CallOption) (*MoveNamespaceResponse, error)
+	RenameSecret(ctx context.Context, in *RenameSecretRequest, opts ...grpc.CallOption) (*RenameSecretResponse, error)
+	SetNamespacePatterns(ctx context.Context, in *SetNamespacePatternsRequest, opts ...grpc.CallOption) (*SetNamespacePatternsResponse, error)
+	SetNamespaceTTL(ctx context.Context, in *SetNamespaceTTLRequest, opts ...grpc.CallOption) (*SetNamespaceTTLResponse, error)
+	ClearNamespaceTTL(ctx context.Context, in *ClearNamespaceTTLRequest, opts ...grpc.CallOption) (*ClearNamespaceTTLResponse, error)
+	SetNamespaceRotation(ctx context.Context, in *SetNamespaceRotationRequest, opts ...grpc.CallOption) (*SetNamespaceRotationResponse, error)
+	SearchSecrets(ctx context.Context, in *SearchSecretsRequest, opts ...grpc.CallOption) (*SearchSecretsResponse, error)
+	VerifyIntegrity(ctx context.Context, in *VerifyIntegrityRequest, opts ...grpc.CallOption) (*VerifyIntegrityResponse, error)
+	RekeyDryRun(ctx context.Context, in *RekeyDryRunRequest, opts ...grpc.CallOption) (*RekeyDryRunResponse, error)
+	Rekey(ctx context.Context, in *RekeyRequest, opts ...grpc.CallOption) (*RekeyResponse, error)
+	PruneOrphans(ctx context.Context, in *PruneOrphansRequest, opts ...grpc.CallOption) (*PruneOrphansResponse, error)
+	DeleteSecretsByTag(ctx context.Context, in *DeleteSecretsByTagRequest, opts ...grpc.CallOption) (*DeleteSecretsByTagResponse, error)
 }
 
 type gaiaAdminClient struct {
@@ -89,6 +135,25 @@ func (c *gaiaAdminClient) ListSecrets(ctx context.Context, in *ListSecretsReques
 	return out, nil
 }
 
+func (c *gaiaAdminClient) StreamSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamSecretsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GaiaAdmin_ServiceDesc.Streams[0], GaiaAdmin_StreamSecrets_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListSecretsRequest, StreamSecretsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GaiaAdmin_StreamSecretsClient = grpc.ServerStreamingClient[StreamSecretsResponse]
+
 func (c *gaiaAdminClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetStatusResponse)
@@ -99,6 +164,16 @@ func (c *gaiaAdminClient) GetStatus(ctx context.Context, in *GetStatusRequest, o
 	return out, nil
 }
 
+func (c *gaiaAdminClient) GetMetrics(ctx context.Context, in *GetMetricsRequest, opts ...grpc.CallOption) (*GetMetricsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMetricsResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_GetMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gaiaAdminClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(StopResponse)
@@ -171,7 +246,7 @@ func (c *gaiaAdminClient) RevokeClient(ctx context.Context, in *RevokeClientRequ
 
 func (c *gaiaAdminClient) ImportSecrets(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportSecretsRequest, ImportSecretsResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &GaiaAdmin_ServiceDesc.Streams[0], GaiaAdmin_ImportSecrets_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &GaiaAdmin_ServiceDesc.Streams[1], GaiaAdmin_ImportSecrets_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -182,6 +257,219 @@ func (c *gaiaAdminClient) ImportSecrets(ctx context.Context, opts ...grpc.CallOp
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type GaiaAdmin_ImportSecretsClient = grpc.ClientStreamingClient[ImportSecretsRequest, ImportSecretsResponse]
 
+func (c *gaiaAdminClient) ImportSecretsWithProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportSecretsRequest, ImportSecretsProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GaiaAdmin_ServiceDesc.Streams[2], GaiaAdmin_ImportSecretsWithProgress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportSecretsRequest, ImportSecretsProgress]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GaiaAdmin_ImportSecretsWithProgressClient = grpc.BidiStreamingClient[ImportSecretsRequest, ImportSecretsProgress]
+
+func (c *gaiaAdminClient) SetSecrets(ctx context.Context, in *SetSecretsRequest, opts ...grpc.CallOption) (*SetSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetSecretsResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_SetSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) ExportSecrets(ctx context.Context, in *ExportSecretsRequest, opts ...grpc.CallOption) (*ExportSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportSecretsResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_ExportSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) RotateSecrets(ctx context.Context, in *RotateSecretsRequest, opts ...grpc.CallOption) (*RotateSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateSecretsResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_RotateSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) SetCommonGrants(ctx context.Context, in *SetCommonGrantsRequest, opts ...grpc.CallOption) (*SetCommonGrantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetCommonGrantsResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_SetCommonGrants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) ExportClientManifest(ctx context.Context, in *ExportClientManifestRequest, opts ...grpc.CallOption) (*ExportClientManifestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportClientManifestResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_ExportClientManifest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) ExportClients(ctx context.Context, in *ExportClientsRequest, opts ...grpc.CallOption) (*ExportClientsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportClientsResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_ExportClients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) ImportClients(ctx context.Context, in *ImportClientsRequest, opts ...grpc.CallOption) (*ImportClientsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportClientsResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_ImportClients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) GetSecretUsage(ctx context.Context, in *GetSecretUsageRequest, opts ...grpc.CallOption) (*GetSecretUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSecretUsageResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_GetSecretUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) MoveNamespace(ctx context.Context, in *MoveNamespaceRequest, opts ...grpc.CallOption) (*MoveNamespaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveNamespaceResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_MoveNamespace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) RenameSecret(ctx context.Context, in *RenameSecretRequest, opts ...grpc.CallOption) (*RenameSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameSecretResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_RenameSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) SetNamespacePatterns(ctx context.Context, in *SetNamespacePatternsRequest, opts ...grpc.CallOption) (*SetNamespacePatternsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetNamespacePatternsResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_SetNamespacePatterns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) SetNamespaceTTL(ctx context.Context, in *SetNamespaceTTLRequest, opts ...grpc.CallOption) (*SetNamespaceTTLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetNamespaceTTLResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_SetNamespaceTTL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) ClearNamespaceTTL(ctx context.Context, in *ClearNamespaceTTLRequest, opts ...grpc.CallOption) (*ClearNamespaceTTLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearNamespaceTTLResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_ClearNamespaceTTL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) SetNamespaceRotation(ctx context.Context, in *SetNamespaceRotationRequest, opts ...grpc.CallOption) (*SetNamespaceRotationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetNamespaceRotationResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_SetNamespaceRotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) SearchSecrets(ctx context.Context, in *SearchSecretsRequest, opts ...grpc.CallOption) (*SearchSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchSecretsResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_SearchSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) VerifyIntegrity(ctx context.Context, in *VerifyIntegrityRequest, opts ...grpc.CallOption) (*VerifyIntegrityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyIntegrityResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_VerifyIntegrity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) RekeyDryRun(ctx context.Context, in *RekeyDryRunRequest, opts ...grpc.CallOption) (*RekeyDryRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RekeyDryRunResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_RekeyDryRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) Rekey(ctx context.Context, in *RekeyRequest, opts ...grpc.CallOption) (*RekeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RekeyResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_Rekey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) PruneOrphans(ctx context.Context, in *PruneOrphansRequest, opts ...grpc.CallOption) (*PruneOrphansResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PruneOrphansResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_PruneOrphans_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaAdminClient) DeleteSecretsByTag(ctx context.Context, in *DeleteSecretsByTagRequest, opts ...grpc.CallOption) (*DeleteSecretsByTagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSecretsByTagResponse)
+	err := c.cc.Invoke(ctx, GaiaAdmin_DeleteSecretsByTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GaiaAdminServer is the server API for GaiaAdmin service.
 // All implementations must embed UnimplementedGaiaAdminServer
 // for forward compatibility.
@@ -189,7 +477,9 @@ type GaiaAdminServer interface {
 	AddSecret(context.Context, *AddSecretRequest) (*AddSecretResponse, error)
 	DeleteSecret(context.Context, *DeleteSecretRequest) (*DeleteSecretResponse, error)
 	ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error)
+	StreamSecrets(*ListSecretsRequest, grpc.ServerStreamingServer[StreamSecretsResponse]) error
 	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	GetMetrics(context.Context, *GetMetricsRequest) (*GetMetricsResponse, error)
 	Stop(context.Context, *StopRequest) (*StopResponse, error)
 	Unlock(context.Context, *UnlockRequest) (*UnlockResponse, error)
 	Lock(context.Context, *LockRequest) (*LockResponse, error)
@@ -198,6 +488,27 @@ type GaiaAdminServer interface {
 	ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error)
 	RevokeClient(context.Context, *RevokeClientRequest) (*RevokeClientResponse, error)
 	ImportSecrets(grpc.ClientStreamingServer[ImportSecretsRequest, ImportSecretsResponse]) error
+	ImportSecretsWithProgress(grpc.BidiStreamingServer[ImportSecretsRequest, ImportSecretsProgress]) error
+	SetSecrets(context.Context, *SetSecretsRequest) (*SetSecretsResponse, error)
+	ExportSecrets(context.Context, *ExportSecretsRequest) (*ExportSecretsResponse, error)
+	RotateSecrets(context.Context, *RotateSecretsRequest) (*RotateSecretsResponse, error)
+	SetCommonGrants(context.Context, *SetCommonGrantsRequest) (*SetCommonGrantsResponse, error)
+	ExportClientManifest(context.Context, *ExportClientManifestRequest) (*ExportClientManifestResponse, error)
+	ExportClients(context.Context, *ExportClientsRequest) (*ExportClientsResponse, error)
+	ImportClients(context.Context, *ImportClientsRequest) (*ImportClientsResponse, error)
+	GetSecretUsage(context.Context, *GetSecretUsageRequest) (*GetSecretUsageResponse, error)
+	MoveNamespace(context.Context, *MoveNamespaceRequest) (*MoveNamespaceResponse, error)
+	RenameSecret(context.Context, *RenameSecretRequest) (*RenameSecretResponse, error)
+	SetNamespacePatterns(context.Context, *SetNamespacePatternsRequest) (*SetNamespacePatternsResponse, error)
+	SetNamespaceTTL(context.Context, *SetNamespaceTTLRequest) (*SetNamespaceTTLResponse, error)
+	ClearNamespaceTTL(context.Context, *ClearNamespaceTTLRequest) (*ClearNamespaceTTLResponse, error)
+	SetNamespaceRotation(context.Context, *SetNamespaceRotationRequest) (*SetNamespaceRotationResponse, error)
+	SearchSecrets(context.Context, *SearchSecretsRequest) (*SearchSecretsResponse, error)
+	VerifyIntegrity(context.Context, *VerifyIntegrityRequest) (*VerifyIntegrityResponse, error)
+	RekeyDryRun(context.Context, *RekeyDryRunRequest) (*RekeyDryRunResponse, error)
+	Rekey(context.Context, *RekeyRequest) (*RekeyResponse, error)
+	PruneOrphans(context.Context, *PruneOrphansRequest) (*PruneOrphansResponse, error)
+	DeleteSecretsByTag(context.Context, *DeleteSecretsByTagRequest) (*DeleteSecretsByTagResponse, error)
 	mustEmbedUnimplementedGaiaAdminServer()
 }
 
@@ -217,9 +528,15 @@ func (UnimplementedGaiaAdminServer) DeleteSecret(context.Context, *DeleteSecretR
 func (UnimplementedGaiaAdminServer) ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListSecrets not implemented")
 }
+func (UnimplementedGaiaAdminServer) StreamSecrets(*ListSecretsRequest, grpc.ServerStreamingServer[StreamSecretsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSecrets not implemented")
+}
 func (UnimplementedGaiaAdminServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
 }
+func (UnimplementedGaiaAdminServer) GetMetrics(context.Context, *GetMetricsRequest) (*GetMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetrics not implemented")
+}
 func (UnimplementedGaiaAdminServer) Stop(context.Context, *StopRequest) (*StopResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
 }
@@ -244,6 +561,69 @@ func (UnimplementedGaiaAdminServer) RevokeClient(context.Context, *RevokeClientR
 func (UnimplementedGaiaAdminServer) ImportSecrets(grpc.ClientStreamingServer[ImportSecretsRequest, ImportSecretsResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method ImportSecrets not implemented")
 }
+func (UnimplementedGaiaAdminServer) ImportSecretsWithProgress(grpc.BidiStreamingServer[ImportSecretsRequest, ImportSecretsProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportSecretsWithProgress not implemented")
+}
+func (UnimplementedGaiaAdminServer) SetSecrets(context.Context, *SetSecretsRequest) (*SetSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSecrets not implemented")
+}
+func (UnimplementedGaiaAdminServer) ExportSecrets(context.Context, *ExportSecretsRequest) (*ExportSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSecrets not implemented")
+}
+func (UnimplementedGaiaAdminServer) RotateSecrets(context.Context, *RotateSecretsRequest) (*RotateSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateSecrets not implemented")
+}
+func (UnimplementedGaiaAdminServer) SetCommonGrants(context.Context, *SetCommonGrantsRequest) (*SetCommonGrantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCommonGrants not implemented")
+}
+func (UnimplementedGaiaAdminServer) ExportClientManifest(context.Context, *ExportClientManifestRequest) (*ExportClientManifestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportClientManifest not implemented")
+}
+func (UnimplementedGaiaAdminServer) ExportClients(context.Context, *ExportClientsRequest) (*ExportClientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportClients not implemented")
+}
+func (UnimplementedGaiaAdminServer) ImportClients(context.Context, *ImportClientsRequest) (*ImportClientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportClients not implemented")
+}
+func (UnimplementedGaiaAdminServer) GetSecretUsage(context.Context, *GetSecretUsageRequest) (*GetSecretUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSecretUsage not implemented")
+}
+func (UnimplementedGaiaAdminServer) MoveNamespace(context.Context, *MoveNamespaceRequest) (*MoveNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveNamespace not implemented")
+}
+func (UnimplementedGaiaAdminServer) RenameSecret(context.Context, *RenameSecretRequest) (*RenameSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameSecret not implemented")
+}
+func (UnimplementedGaiaAdminServer) SetNamespacePatterns(context.Context, *SetNamespacePatternsRequest) (*SetNamespacePatternsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNamespacePatterns not implemented")
+}
+func (UnimplementedGaiaAdminServer) SetNamespaceTTL(context.Context, *SetNamespaceTTLRequest) (*SetNamespaceTTLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNamespaceTTL not implemented")
+}
+func (UnimplementedGaiaAdminServer) ClearNamespaceTTL(context.Context, *ClearNamespaceTTLRequest) (*ClearNamespaceTTLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearNamespaceTTL not implemented")
+}
+func (UnimplementedGaiaAdminServer) SetNamespaceRotation(context.Context, *SetNamespaceRotationRequest) (*SetNamespaceRotationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNamespaceRotation not implemented")
+}
+func (UnimplementedGaiaAdminServer) SearchSecrets(context.Context, *SearchSecretsRequest) (*SearchSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchSecrets not implemented")
+}
+func (UnimplementedGaiaAdminServer) VerifyIntegrity(context.Context, *VerifyIntegrityRequest) (*VerifyIntegrityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyIntegrity not implemented")
+}
+func (UnimplementedGaiaAdminServer) RekeyDryRun(context.Context, *RekeyDryRunRequest) (*RekeyDryRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RekeyDryRun not implemented")
+}
+func (UnimplementedGaiaAdminServer) Rekey(context.Context, *RekeyRequest) (*RekeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rekey not implemented")
+}
+func (UnimplementedGaiaAdminServer) PruneOrphans(context.Context, *PruneOrphansRequest) (*PruneOrphansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneOrphans not implemented")
+}
+func (UnimplementedGaiaAdminServer) DeleteSecretsByTag(context.Context, *DeleteSecretsByTagRequest) (*DeleteSecretsByTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSecretsByTag not implemented")
+}
 func (UnimplementedGaiaAdminServer) mustEmbedUnimplementedGaiaAdminServer() {}
 func (UnimplementedGaiaAdminServer) testEmbeddedByValue()                   {}
 
@@ -319,6 +699,17 @@ func _GaiaAdmin_ListSecrets_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GaiaAdmin_StreamSecrets_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListSecretsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GaiaAdminServer).StreamSecrets(m, &grpc.GenericServerStream[ListSecretsRequest, StreamSecretsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GaiaAdmin_StreamSecretsServer = grpc.ServerStreamingServer[StreamSecretsResponse]
+
 func _GaiaAdmin_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetStatusRequest)
 	if err := dec(in); err != nil {
@@ -337,6 +728,24 @@ func _GaiaAdmin_GetStatus_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GaiaAdmin_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).GetMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_GetMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).GetMetrics(ctx, req.(*GetMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GaiaAdmin_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StopRequest)
 	if err := dec(in); err != nil {
@@ -470,70 +879,536 @@ func _GaiaAdmin_ImportSecrets_Handler(srv interface{}, stream grpc.ServerStream)
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type GaiaAdmin_ImportSecretsServer = grpc.ClientStreamingServer[ImportSecretsRequest, ImportSecretsResponse]
 
-// GaiaAdmin_ServiceDesc is the grpc.ServiceDesc for GaiaAdmin service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var GaiaAdmin_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "gaia.GaiaAdmin",
-	HandlerType: (*GaiaAdminServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "AddSecret",
-			Handler:    _GaiaAdmin_AddSecret_Handler,
-		},
-		{
-			MethodName: "DeleteSecret",
-			Handler:    _GaiaAdmin_DeleteSecret_Handler,
-		},
-		{
-			MethodName: "ListSecrets",
-			Handler:    _GaiaAdmin_ListSecrets_Handler,
-		},
-		{
-			MethodName: "GetStatus",
-			Handler:    _GaiaAdmin_GetStatus_Handler,
-		},
-		{
-			MethodName: "Stop",
-			Handler:    _GaiaAdmin_Stop_Handler,
-		},
-		{
-			MethodName: "Unlock",
-			Handler:    _GaiaAdmin_Unlock_Handler,
-		},
-		{
-			MethodName: "Lock",
-			Handler:    _GaiaAdmin_Lock_Handler,
-		},
-		{
-			MethodName: "RegisterClient",
-			Handler:    _GaiaAdmin_RegisterClient_Handler,
-		},
-		{
-			MethodName: "ListClients",
-			Handler:    _GaiaAdmin_ListClients_Handler,
-		},
-		{
-			MethodName: "ListNamespaces",
-			Handler:    _GaiaAdmin_ListNamespaces_Handler,
+func _GaiaAdmin_ImportSecretsWithProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GaiaAdminServer).ImportSecretsWithProgress(&grpc.GenericServerStream[ImportSecretsRequest, ImportSecretsProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GaiaAdmin_ImportSecretsWithProgressServer = grpc.BidiStreamingServer[ImportSecretsRequest, ImportSecretsProgress]
+
+func _GaiaAdmin_SetSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).SetSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_SetSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).SetSecrets(ctx, req.(*SetSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_ExportSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).ExportSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_ExportSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).ExportSecrets(ctx, req.(*ExportSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_RotateSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).RotateSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_RotateSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).RotateSecrets(ctx, req.(*RotateSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_SetCommonGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCommonGrantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).SetCommonGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_SetCommonGrants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).SetCommonGrants(ctx, req.(*SetCommonGrantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_ExportClientManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportClientManifestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).ExportClientManifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_ExportClientManifest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).ExportClientManifest(ctx, req.(*ExportClientManifestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_ExportClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).ExportClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_ExportClients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).ExportClients(ctx, req.(*ExportClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_ImportClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).ImportClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_ImportClients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).ImportClients(ctx, req.(*ImportClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_GetSecretUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSecretUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).GetSecretUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_GetSecretUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).GetSecretUsage(ctx, req.(*GetSecretUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_MoveNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).MoveNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_MoveNamespace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).MoveNamespace(ctx, req.(*MoveNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_RenameSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).RenameSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_RenameSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).RenameSecret(ctx, req.(*RenameSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_SetNamespacePatterns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNamespacePatternsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).SetNamespacePatterns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_SetNamespacePatterns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).SetNamespacePatterns(ctx, req.(*SetNamespacePatternsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_SetNamespaceTTL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNamespaceTTLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).SetNamespaceTTL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_SetNamespaceTTL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).SetNamespaceTTL(ctx, req.(*SetNamespaceTTLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_ClearNamespaceTTL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearNamespaceTTLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).ClearNamespaceTTL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_ClearNamespaceTTL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).ClearNamespaceTTL(ctx, req.(*ClearNamespaceTTLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_SetNamespaceRotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNamespaceRotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).SetNamespaceRotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_SetNamespaceRotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).SetNamespaceRotation(ctx, req.(*SetNamespaceRotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_SearchSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).SearchSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_SearchSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).SearchSecrets(ctx, req.(*SearchSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_VerifyIntegrity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyIntegrityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).VerifyIntegrity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_VerifyIntegrity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).VerifyIntegrity(ctx, req.(*VerifyIntegrityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_RekeyDryRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RekeyDryRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).RekeyDryRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_RekeyDryRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).RekeyDryRun(ctx, req.(*RekeyDryRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_Rekey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RekeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).Rekey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_Rekey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).Rekey(ctx, req.(*RekeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_PruneOrphans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneOrphansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).PruneOrphans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_PruneOrphans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).PruneOrphans(ctx, req.(*PruneOrphansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaAdmin_DeleteSecretsByTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSecretsByTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaAdminServer).DeleteSecretsByTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaAdmin_DeleteSecretsByTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaAdminServer).DeleteSecretsByTag(ctx, req.(*DeleteSecretsByTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GaiaAdmin_ServiceDesc is the grpc.ServiceDesc for GaiaAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GaiaAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gaia.GaiaAdmin",
+	HandlerType: (*GaiaAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddSecret",
+			Handler:    _GaiaAdmin_AddSecret_Handler,
+		},
+		{
+			MethodName: "DeleteSecret",
+			Handler:    _GaiaAdmin_DeleteSecret_Handler,
+		},
+		{
+			MethodName: "ListSecrets",
+			Handler:    _GaiaAdmin_ListSecrets_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _GaiaAdmin_GetStatus_Handler,
+		},
+		{
+			MethodName: "GetMetrics",
+			Handler:    _GaiaAdmin_GetMetrics_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _GaiaAdmin_Stop_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _GaiaAdmin_Unlock_Handler,
+		},
+		{
+			MethodName: "Lock",
+			Handler:    _GaiaAdmin_Lock_Handler,
+		},
+		{
+			MethodName: "RegisterClient",
+			Handler:    _GaiaAdmin_RegisterClient_Handler,
+		},
+		{
+			MethodName: "ListClients",
+			Handler:    _GaiaAdmin_ListClients_Handler,
+		},
+		{
+			MethodName: "ListNamespaces",
+			Handler:    _GaiaAdmin_ListNamespaces_Handler,
 		},
 		{
 			MethodName: "RevokeClient",
 			Handler:    _GaiaAdmin_RevokeClient_Handler,
 		},
+		{
+			MethodName: "SetSecrets",
+			Handler:    _GaiaAdmin_SetSecrets_Handler,
+		},
+		{
+			MethodName: "ExportSecrets",
+			Handler:    _GaiaAdmin_ExportSecrets_Handler,
+		},
+		{
+			MethodName: "RotateSecrets",
+			Handler:    _GaiaAdmin_RotateSecrets_Handler,
+		},
+		{
+			MethodName: "SetCommonGrants",
+			Handler:    _GaiaAdmin_SetCommonGrants_Handler,
+		},
+		{
+			MethodName: "ExportClientManifest",
+			Handler:    _GaiaAdmin_ExportClientManifest_Handler,
+		},
+		{
+			MethodName: "ExportClients",
+			Handler:    _GaiaAdmin_ExportClients_Handler,
+		},
+		{
+			MethodName: "ImportClients",
+			Handler:    _GaiaAdmin_ImportClients_Handler,
+		},
+		{
+			MethodName: "GetSecretUsage",
+			Handler:    _GaiaAdmin_GetSecretUsage_Handler,
+		},
+		{
+			MethodName: "MoveNamespace",
+			Handler:    _GaiaAdmin_MoveNamespace_Handler,
+		},
+		{
+			MethodName: "RenameSecret",
+			Handler:    _GaiaAdmin_RenameSecret_Handler,
+		},
+		{
+			MethodName: "SetNamespacePatterns",
+			Handler:    _GaiaAdmin_SetNamespacePatterns_Handler,
+		},
+		{
+			MethodName: "SetNamespaceTTL",
+			Handler:    _GaiaAdmin_SetNamespaceTTL_Handler,
+		},
+		{
+			MethodName: "ClearNamespaceTTL",
+			Handler:    _GaiaAdmin_ClearNamespaceTTL_Handler,
+		},
+		{
+			MethodName: "SetNamespaceRotation",
+			Handler:    _GaiaAdmin_SetNamespaceRotation_Handler,
+		},
+		{
+			MethodName: "SearchSecrets",
+			Handler:    _GaiaAdmin_SearchSecrets_Handler,
+		},
+		{
+			MethodName: "VerifyIntegrity",
+			Handler:    _GaiaAdmin_VerifyIntegrity_Handler,
+		},
+		{
+			MethodName: "RekeyDryRun",
+			Handler:    _GaiaAdmin_RekeyDryRun_Handler,
+		},
+		{
+			MethodName: "Rekey",
+			Handler:    _GaiaAdmin_Rekey_Handler,
+		},
+		{
+			MethodName: "PruneOrphans",
+			Handler:    _GaiaAdmin_PruneOrphans_Handler,
+		},
+		{
+			MethodName: "DeleteSecretsByTag",
+			Handler:    _GaiaAdmin_DeleteSecretsByTag_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSecrets",
+			Handler:       _GaiaAdmin_StreamSecrets_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ImportSecrets",
 			Handler:       _GaiaAdmin_ImportSecrets_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ImportSecretsWithProgress",
+			Handler:       _GaiaAdmin_ImportSecretsWithProgress_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "gaia.proto",
 }
 
 const (
-	GaiaClient_GetSecret_FullMethodName = "/gaia.GaiaClient/GetSecret"
+	GaiaClient_GetSecret_FullMethodName            = "/gaia.GaiaClient/GetSecret"
+	GaiaClient_GetCommonSecrets_FullMethodName     = "/gaia.GaiaClient/GetCommonSecrets"
+	GaiaClient_CreateSecretIfAbsent_FullMethodName = "/gaia.GaiaClient/CreateSecretIfAbsent"
+	GaiaClient_ListOwnSecrets_FullMethodName       = "/gaia.GaiaClient/ListOwnSecrets"
+	GaiaClient_ListOwnSecretIds_FullMethodName     = "/gaia.GaiaClient/ListOwnSecretIds"
 )
 
 // GaiaClientClient is the client API for GaiaClient service.
@@ -541,6 +1416,10 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type GaiaClientClient interface {
 	GetSecret(ctx context.Context, in *GetSecretRequest, opts ...grpc.CallOption) (*Secret, error)
+	GetCommonSecrets(ctx context.Context, in *GetCommonSecretsRequest, opts ...grpc.CallOption) (*GetCommonSecretsResponse, error)
+	CreateSecretIfAbsent(ctx context.Context, in *CreateSecretIfAbsentRequest, opts ...grpc.CallOption) (*CreateSecretIfAbsentResponse, error)
+	ListOwnSecrets(ctx context.Context, in *ListOwnSecretsRequest, opts ...grpc.CallOption) (*ListOwnSecretsResponse, error)
+	ListOwnSecretIds(ctx context.Context, in *ListOwnSecretIdsRequest, opts ...grpc.CallOption) (*ListOwnSecretIdsResponse, error)
 }
 
 type gaiaClientClient struct {
@@ -561,11 +1440,55 @@ func (c *gaiaClientClient) GetSecret(ctx context.Context, in *GetSecretRequest,
 	return out, nil
 }
 
+func (c *gaiaClientClient) GetCommonSecrets(ctx context.Context, in *GetCommonSecretsRequest, opts ...grpc.CallOption) (*GetCommonSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCommonSecretsResponse)
+	err := c.cc.Invoke(ctx, GaiaClient_GetCommonSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaClientClient) CreateSecretIfAbsent(ctx context.Context, in *CreateSecretIfAbsentRequest, opts ...grpc.CallOption) (*CreateSecretIfAbsentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSecretIfAbsentResponse)
+	err := c.cc.Invoke(ctx, GaiaClient_CreateSecretIfAbsent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaClientClient) ListOwnSecrets(ctx context.Context, in *ListOwnSecretsRequest, opts ...grpc.CallOption) (*ListOwnSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOwnSecretsResponse)
+	err := c.cc.Invoke(ctx, GaiaClient_ListOwnSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaClientClient) ListOwnSecretIds(ctx context.Context, in *ListOwnSecretIdsRequest, opts ...grpc.CallOption) (*ListOwnSecretIdsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOwnSecretIdsResponse)
+	err := c.cc.Invoke(ctx, GaiaClient_ListOwnSecretIds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GaiaClientServer is the server API for GaiaClient service.
 // All implementations must embed UnimplementedGaiaClientServer
 // for forward compatibility.
 type GaiaClientServer interface {
 	GetSecret(context.Context, *GetSecretRequest) (*Secret, error)
+	GetCommonSecrets(context.Context, *GetCommonSecretsRequest) (*GetCommonSecretsResponse, error)
+	CreateSecretIfAbsent(context.Context, *CreateSecretIfAbsentRequest) (*CreateSecretIfAbsentResponse, error)
+	ListOwnSecrets(context.Context, *ListOwnSecretsRequest) (*ListOwnSecretsResponse, error)
+	ListOwnSecretIds(context.Context, *ListOwnSecretIdsRequest) (*ListOwnSecretIdsResponse, error)
 	mustEmbedUnimplementedGaiaClientServer()
 }
 
@@ -579,6 +1502,18 @@ type UnimplementedGaiaClientServer struct{}
 func (UnimplementedGaiaClientServer) GetSecret(context.Context, *GetSecretRequest) (*Secret, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSecret not implemented")
 }
+func (UnimplementedGaiaClientServer) GetCommonSecrets(context.Context, *GetCommonSecretsRequest) (*GetCommonSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCommonSecrets not implemented")
+}
+func (UnimplementedGaiaClientServer) CreateSecretIfAbsent(context.Context, *CreateSecretIfAbsentRequest) (*CreateSecretIfAbsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSecretIfAbsent not implemented")
+}
+func (UnimplementedGaiaClientServer) ListOwnSecrets(context.Context, *ListOwnSecretsRequest) (*ListOwnSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOwnSecrets not implemented")
+}
+func (UnimplementedGaiaClientServer) ListOwnSecretIds(context.Context, *ListOwnSecretIdsRequest) (*ListOwnSecretIdsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOwnSecretIds not implemented")
+}
 func (UnimplementedGaiaClientServer) mustEmbedUnimplementedGaiaClientServer() {}
 func (UnimplementedGaiaClientServer) testEmbeddedByValue()                    {}
 
@@ -618,6 +1553,78 @@ func _GaiaClient_GetSecret_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GaiaClient_GetCommonSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommonSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaClientServer).GetCommonSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaClient_GetCommonSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaClientServer).GetCommonSecrets(ctx, req.(*GetCommonSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaClient_CreateSecretIfAbsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSecretIfAbsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaClientServer).CreateSecretIfAbsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaClient_CreateSecretIfAbsent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaClientServer).CreateSecretIfAbsent(ctx, req.(*CreateSecretIfAbsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaClient_ListOwnSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOwnSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaClientServer).ListOwnSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaClient_ListOwnSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaClientServer).ListOwnSecrets(ctx, req.(*ListOwnSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaClient_ListOwnSecretIds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOwnSecretIdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaClientServer).ListOwnSecretIds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaClient_ListOwnSecretIds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaClientServer).ListOwnSecretIds(ctx, req.(*ListOwnSecretIdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GaiaClient_ServiceDesc is the grpc.ServiceDesc for GaiaClient service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -629,6 +1636,22 @@ var GaiaClient_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSecret",
 			Handler:    _GaiaClient_GetSecret_Handler,
 		},
+		{
+			MethodName: "GetCommonSecrets",
+			Handler:    _GaiaClient_GetCommonSecrets_Handler,
+		},
+		{
+			MethodName: "CreateSecretIfAbsent",
+			Handler:    _GaiaClient_CreateSecretIfAbsent_Handler,
+		},
+		{
+			MethodName: "ListOwnSecrets",
+			Handler:    _GaiaClient_ListOwnSecrets_Handler,
+		},
+		{
+			MethodName: "ListOwnSecretIds",
+			Handler:    _GaiaClient_ListOwnSecretIds_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "gaia.proto",
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stain-win/gaia/apps/gaia/encrypt"
+)
+
+// ChangePassphraseMsg signals the main TUI that the master passphrase form was
+// completed and the daemon should be rekeyed.
+type ChangePassphraseMsg struct {
+	OldPassphrase string
+	NewPassphrase string
+}
+
+// changePassphraseFormModel is the form for changing the master passphrase. The
+// passphrases are masked and the new one must pass the same strength check as
+// at init.
+type changePassphraseFormModel struct {
+	form          *huh.Form
+	oldPassphrase string
+	newPassphrase string
+	confirm       string
+	// errMsg is shown above the form, e.g. when the daemon rejected the current
+	// passphrase of the previous attempt.
+	errMsg string
+	// pending is set while the daemon re-encrypts the secrets.
+	pending bool
+}
+
+func newChangePassphraseFormModel(errMsg string) *changePassphraseFormModel {
+	m := &changePassphraseFormModel{errMsg: errMsg}
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(lipgloss.NewStyle().Bold(true).Render("Current master passphrase")).
+				Password(true).
+				Value(&m.oldPassphrase).
+				Validate(func(s string) error {
+					if s == "" {
+						return errors.New("current passphrase is required")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title(lipgloss.NewStyle().Bold(true).Render("New master passphrase")).
+				Password(true).
+				Value(&m.newPassphrase).
+				Validate(func(s string) error {
+					_, err := encrypt.ValidatePassword(s)
+					return err
+				}),
+			huh.NewInput().
+				Title(lipgloss.NewStyle().Bold(true).Render("Confirm new passphrase")).
+				Password(true).
+				Value(&m.confirm).
+				Validate(func(s string) error {
+					if s != m.newPassphrase {
+						return errors.New("passphrases do not match")
+					}
+					return nil
+				}),
+		),
+	).WithWidth(50)
+	return m
+}
+
+func (m *changePassphraseFormModel) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m *changePassphraseFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.pending {
+		return m, nil
+	}
+
+	updatedForm, cmd := m.form.Update(msg)
+	m.form = updatedForm.(*huh.Form)
+
+	if m.form.State == huh.StateCompleted {
+		m.pending = true
+		oldPassphrase, newPassphrase := m.oldPassphrase, m.newPassphrase
+		return m, func() tea.Msg {
+			return ChangePassphraseMsg{OldPassphrase: oldPassphrase, NewPassphrase: newPassphrase}
+		}
+	}
+	return m, cmd
+}
+
+func (m *changePassphraseFormModel) View() string {
+	if m.pending {
+		return "Re-encrypting secrets with the new passphrase..."
+	}
+	if m.errMsg != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, errorStyle.Render(m.errMsg), m.form.View())
+	}
+	return m.form.View()
+}
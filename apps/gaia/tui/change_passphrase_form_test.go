@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeRekeyServer accepts a rekey from its passphrase.
+type fakeRekeyServer struct {
+	pb.UnimplementedGaiaAdminServer
+	passphrase string
+}
+
+func (s *fakeRekeyServer) Rekey(_ context.Context, req *pb.RekeyRequest) (*pb.RekeyResponse, error) {
+	if req.OldPassphrase != s.passphrase {
+		return nil, status.Error(codes.Unauthenticated, "invalid passphrase")
+	}
+	s.passphrase = req.NewPassphrase
+	return &pb.RekeyResponse{Secrets: 3}, nil
+}
+
+// newFakeAdminConn serves srv in process and returns a shared connection to it.
+func newFakeAdminConn(t *testing.T, cfg *config.Config, srv pb.GaiaAdminServer) *adminConn {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pb.RegisterGaiaAdminServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &adminConn{addr: daemonAddress(cfg), conn: conn}
+}
+
+// submitPassphraseForm completes the passphrase form and runs the resulting
+// commands through the model until the RPC result is handled.
+func submitPassphraseForm(t *testing.T, m *model, oldPassphrase, newPassphrase string) {
+	t.Helper()
+	f := m.changePassphraseForm
+	f.oldPassphrase, f.newPassphrase, f.confirm = oldPassphrase, newPassphrase, newPassphrase
+	f.form.State = huh.StateCompleted
+
+	_, cmd := m.Update(nil)
+	if cmd == nil {
+		t.Fatal("completed form sent no message")
+	}
+	msg, ok := cmd().(ChangePassphraseMsg)
+	if !ok || msg.OldPassphrase != oldPassphrase || msg.NewPassphrase != newPassphrase {
+		t.Fatalf("completed form sent %#v", msg)
+	}
+	if view := m.changePassphraseForm.View(); !strings.Contains(view, "Re-encrypting") {
+		t.Errorf("form view while rekeying = %q, want progress", view)
+	}
+
+	_, cmd = m.Update(msg)
+	if cmd == nil {
+		t.Fatal("ChangePassphraseMsg started no RPC")
+	}
+	m.Update(cmd())
+}
+
+func TestChangePassphrase_FormToRPC(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	srv := &fakeRekeyServer{passphrase: "old-passphrase"}
+	m := initialModel(cfg)
+	m.conn = newFakeAdminConn(t, cfg, srv)
+	m.activeScreen = changePassphrase
+
+	const newPassphrase = "correct-horse-battery-staple-42"
+	submitPassphraseForm(t, m, "wrong", newPassphrase)
+	if m.activeScreen != changePassphrase {
+		t.Fatalf("screen after a wrong passphrase = %v, want the form", m.activeScreen)
+	}
+	if !strings.Contains(m.changePassphraseForm.View(), "current passphrase is incorrect") {
+		t.Errorf("form does not show the wrong passphrase error: %q", m.changePassphraseForm.View())
+	}
+	if srv.passphrase != "old-passphrase" {
+		t.Error("passphrase changed despite a wrong current passphrase")
+	}
+
+	submitPassphraseForm(t, m, "old-passphrase", newPassphrase)
+	if m.activeScreen != certManagement {
+		t.Errorf("screen after a successful change = %v, want certificate management", m.activeScreen)
+	}
+	if srv.passphrase != newPassphrase {
+		t.Errorf("daemon passphrase = %q, want %q", srv.passphrase, newPassphrase)
+	}
+	if !strings.Contains(m.statusMessage, "3 secrets re-encrypted") {
+		t.Errorf("statusMessage = %q, want the number of re-encrypted secrets", m.statusMessage)
+	}
+}
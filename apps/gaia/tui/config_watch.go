@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+// configReloadedMsg is sent when the watched config file changed and was loaded again.
+type configReloadedMsg struct {
+	cfg     *config.Config
+	modTime time.Time
+	err     error
+}
+
+// reloadNoticeTimeout is how long a successful config reload is reported in the
+// status bar.
+const reloadNoticeTimeout = 5 * time.Second
+
+// clearStatusMsg clears the status message if it is still message.
+type clearStatusMsg struct {
+	message string
+}
+
+// clearStatusCmd clears message from the status bar after timeout, unless it was
+// replaced by then.
+func clearStatusCmd(message string, timeout time.Duration) tea.Cmd {
+	return tea.Tick(timeout, func(time.Time) tea.Msg {
+		return clearStatusMsg{message: message}
+	})
+}
+
+// watchConfig starts watching path for changes. The file is polled on every tick,
+// so changes are picked up within one tick interval.
+func (m *model) watchConfig(path string) {
+	if path == "" {
+		return
+	}
+	m.configPath = path
+	if info, err := os.Stat(path); err == nil {
+		m.configModTime = info.ModTime()
+	}
+}
+
+// checkConfigCmd reloads the config file at path when its modification time
+// differs from lastMod. It produces no message when nothing changed.
+func checkConfigCmd(path string, lastMod time.Time) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(lastMod) {
+			return nil
+		}
+		cfg, err := config.Load(path)
+		return configReloadedMsg{cfg: cfg, modTime: info.ModTime(), err: err}
+	}
+}
+
+// applyConfigReload returns a copy of cur with the settings the TUI can apply
+// while running taken from loaded, together with the names of those that
+// changed. cur itself is left untouched, because commands that are still in
+// flight may read it.
+func applyConfigReload(cur, loaded *config.Config) (*config.Config, []string) {
+	next := *cur
+	var changed []string
+	if loaded.GaiaTuiTickInterval > 0 && loaded.GaiaTuiTickInterval != cur.GaiaTuiTickInterval {
+		next.GaiaTuiTickInterval = loaded.GaiaTuiTickInterval
+		changed = append(changed, "tick interval")
+	}
+	if loaded.GRPCServerName != cur.GRPCServerName || loaded.GRPCPort != cur.GRPCPort {
+		next.GRPCServerName = loaded.GRPCServerName
+		next.GRPCPort = loaded.GRPCPort
+		changed = append(changed, "daemon address")
+	}
+	return &next, changed
+}
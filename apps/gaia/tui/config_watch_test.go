@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+func TestApplyConfigReload(t *testing.T) {
+	cur := config.NewDefaultConfig()
+	cur.CertsDirectory = "/etc/gaia/certs"
+
+	loaded := config.NewDefaultConfig()
+	loaded.GaiaTuiTickInterval = 500 * time.Millisecond
+	loaded.GRPCServerName = "gaia.internal"
+	loaded.GRPCPort = "6000"
+	loaded.CertsDirectory = "/elsewhere"
+
+	next, changed := applyConfigReload(cur, loaded)
+	if want := []string{"tick interval", "daemon address"}; !slices.Equal(changed, want) {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+	if next.GaiaTuiTickInterval != 500*time.Millisecond || next.GRPCServerName != "gaia.internal" || next.GRPCPort != "6000" {
+		t.Errorf("reload not applied: tick %v, address %s:%s", next.GaiaTuiTickInterval, next.GRPCServerName, next.GRPCPort)
+	}
+	if next.CertsDirectory != "/etc/gaia/certs" {
+		t.Errorf("CertsDirectory = %q, want settings other than the live ones kept", next.CertsDirectory)
+	}
+	if cur.GRPCServerName != "localhost" {
+		t.Errorf("applyConfigReload() modified the current config")
+	}
+
+	loaded.GaiaTuiTickInterval = 0
+	if again, changed := applyConfigReload(next, loaded); len(changed) != 0 || again.GaiaTuiTickInterval != 500*time.Millisecond {
+		t.Errorf("reloading an unchanged config reported %v, tick %v", changed, again.GaiaTuiTickInterval)
+	}
+}
+
+func TestConfigReloadNotice_Clears(t *testing.T) {
+	m := initialModel(config.NewDefaultConfig())
+	reloaded := config.NewDefaultConfig()
+	reloaded.GaiaTuiTickInterval = 500 * time.Millisecond
+	updated, cmd := m.Update(configReloadedMsg{cfg: reloaded, modTime: time.Now()})
+	m = updated.(*model)
+	if m.statusMessage != "Config reloaded: tick interval updated." {
+		t.Fatalf("statusMessage = %q, want the reload notice", m.statusMessage)
+	}
+	if cmd == nil {
+		t.Fatal("reload scheduled no command to clear its notice")
+	}
+
+	// The notice is cleared once it times out.
+	updated, _ = m.Update(clearStatusMsg{message: m.statusMessage})
+	if m = updated.(*model); m.statusMessage != "" {
+		t.Errorf("statusMessage after the timeout = %q, want it cleared", m.statusMessage)
+	}
+
+	// A message that replaced the notice is kept.
+	m.statusMessage = "Record added successfully!"
+	updated, _ = m.Update(clearStatusMsg{message: "Config reloaded: tick interval updated."})
+	if m = updated.(*model); m.statusMessage != "Record added successfully!" {
+		t.Errorf("statusMessage = %q, want a newer message kept", m.statusMessage)
+	}
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stain-win/gaia/apps/gaia/certs"
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+// defaultCAName is the Common Name offered for a new CA, as in 'gaia certs generate'.
+const defaultCAName = "Gaia Root CA"
+
+// CreateCertsMsg signals the main TUI that the certificate form was completed
+// and a new CA, server and client certificate should be generated.
+type CreateCertsMsg struct {
+	CAName     string
+	ServerName string
+	ClientName string
+	OutputDir  string
+}
+
+// createCertsFormModel is the form for generating a new set of mTLS
+// certificates. It is pre-filled from the configuration, so the defaults
+// produce certificates the daemon and CLI find without further settings.
+type createCertsFormModel struct {
+	form       *huh.Form
+	caName     string
+	serverName string
+	clientName string
+	outputDir  string
+	// errMsg is shown above the form, e.g. when the previous attempt failed.
+	errMsg string
+	// pending is set while the certificates are generated; steps lists the
+	// completed ones.
+	pending bool
+	steps   []string
+}
+
+func newCreateCertsFormModel(cfg *config.Config, errMsg string) *createCertsFormModel {
+	m := &createCertsFormModel{
+		caName:     defaultCAName,
+		serverName: cfg.GRPCServerName,
+		clientName: certs.DefaultAdminClientName,
+		outputDir:  cfg.CertsDirectory,
+		errMsg:     errMsg,
+	}
+	required := func(field string) func(string) error {
+		return func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return errors.New(field + " is required")
+			}
+			return nil
+		}
+	}
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("CA Common Name").Value(&m.caName).Validate(required("CA name")),
+			huh.NewInput().Title("Server Common Name").Value(&m.serverName).Validate(required("server name")),
+			huh.NewInput().Title("Client Common Name").Value(&m.clientName).Validate(required("client name")),
+			huh.NewInput().Title("Output Directory").Value(&m.outputDir).Validate(required("output directory")),
+		),
+	).WithWidth(50)
+	return m
+}
+
+func (m *createCertsFormModel) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m *createCertsFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.pending {
+		return m, nil
+	}
+
+	updatedForm, cmd := m.form.Update(msg)
+	m.form = updatedForm.(*huh.Form)
+
+	if m.form.State == huh.StateCompleted {
+		m.pending = true
+		req := CreateCertsMsg{
+			CAName:     strings.TrimSpace(m.caName),
+			ServerName: strings.TrimSpace(m.serverName),
+			ClientName: strings.TrimSpace(m.clientName),
+			OutputDir:  strings.TrimSpace(m.outputDir),
+		}
+		return m, func() tea.Msg { return req }
+	}
+	return m, cmd
+}
+
+// stepDone records a completed generation step for the progress view.
+func (m *createCertsFormModel) stepDone(step certs.CertStep) {
+	m.steps = append(m.steps, fmt.Sprintf("✔ Generated %s: %s", step.Name, step.CertPath))
+}
+
+func (m *createCertsFormModel) View() string {
+	if m.pending {
+		return lipgloss.JoinVertical(lipgloss.Left, append([]string{"Generating certificates..."}, m.steps...)...)
+	}
+	if m.errMsg != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, errorStyle.Render(m.errMsg), m.form.View())
+	}
+	return m.form.View()
+}
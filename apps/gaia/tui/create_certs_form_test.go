@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	"github.com/stain-win/gaia/apps/gaia/certs"
+	"github.com/stain-win/gaia/apps/gaia/config"
+)
+
+func TestCreateCerts_FormToFiles(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.CertsDirectory = filepath.Join(t.TempDir(), "certs")
+	cfg.GRPCServerName = "gaia.example.com"
+	m := initialModel(cfg)
+	m.activeScreen = createCerts
+
+	// The form offers the configured names, so accepting it as is produces
+	// certificates the daemon and CLI find.
+	f := m.createCertsForm
+	if f.serverName != cfg.GRPCServerName || f.outputDir != cfg.CertsDirectory || f.clientName != certs.DefaultAdminClientName {
+		t.Errorf("form defaults = %q, %q, %q, want the configured values", f.serverName, f.outputDir, f.clientName)
+	}
+	f.form.State = huh.StateCompleted
+
+	_, cmd := m.Update(nil)
+	if cmd == nil {
+		t.Fatal("completed form sent no message")
+	}
+	msg, ok := cmd().(CreateCertsMsg)
+	if !ok {
+		t.Fatalf("completed form sent %#v", msg)
+	}
+	_, cmd = m.Update(msg)
+	for cmd != nil {
+		next := cmd()
+		if _, ok := next.(certStepMsg); ok && !m.createCertsForm.pending {
+			t.Fatal("form is not pending while certificates are generated")
+		}
+		_, cmd = m.Update(next)
+	}
+
+	if m.activeScreen != certManagement {
+		t.Errorf("screen after generation = %v, want certificate management", m.activeScreen)
+	}
+	if got := len(f.steps); got != 3 {
+		t.Errorf("form showed %d steps, want 3", got)
+	}
+	if !strings.Contains(m.statusMessage, cfg.CertsDirectory) {
+		t.Errorf("statusMessage = %q, want the output directory", m.statusMessage)
+	}
+	for _, name := range []string{cfg.CACertFile, cfg.ServerCertFile, certs.DefaultAdminClientName + ".crt"} {
+		if _, err := os.Stat(filepath.Join(cfg.CertsDirectory, name)); err != nil {
+			t.Errorf("%s was not written: %v", name, err)
+		}
+	}
+}
@@ -2,47 +2,50 @@ package tui
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
-	"os"
-	"path/filepath"
+	"net"
+	"sync"
+	"time"
 
+	"github.com/stain-win/gaia/apps/gaia/certs"
 	"github.com/stain-win/gaia/apps/gaia/config"
+	"github.com/stain-win/gaia/apps/gaia/daemon"
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	// Registers gzip, so responses the daemon compresses are accepted.
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
 )
 
+// reconnectBackoff bounds how often the TUI redials a daemon that is down. gRPC
+// applies the jitter, so several TUIs do not retry in lockstep.
+var reconnectBackoff = backoff.Config{
+	BaseDelay:  500 * time.Millisecond,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   10 * time.Second,
+}
+
 // getAdminClientConn establishes a secure gRPC connection to the daemon.
 func getAdminClientConn(cfg *config.Config) (*grpc.ClientConn, error) {
-	caCertPath := filepath.Join(cfg.CertsDirectory, "ca.crt")
-	// For admin actions from the TUI, we can use a generic "client" cert
-	// In a more advanced setup, this could be made configurable.
-	gaiaCertPath := filepath.Join(cfg.CertsDirectory, "gaia.crt")
-	gaiaKeyPath := filepath.Join(cfg.CertsDirectory, "gaia.key")
-
-	clientCert, err := tls.LoadX509KeyPair(gaiaCertPath, gaiaKeyPath)
+	// The TUI uses the same admin certificate as the CLI.
+	paths, err := certs.AdminClientPaths(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("could not load TUI client key pair: %w", err)
+		return nil, err
 	}
-
-	caCert, err := os.ReadFile(caCertPath)
+	tlsConfig, err := certs.ClientTLSConfig(paths, certs.ServerName(cfg))
 	if err != nil {
-		return nil, fmt.Errorf("could not read CA certificate for TUI: %w", err)
-	}
-	certPool := x509.NewCertPool()
-	if !certPool.AppendCertsFromPEM(caCert) {
-		return nil, fmt.Errorf("could not append CA certificate to TUI pool")
+		return nil, fmt.Errorf("TUI: %w", err)
 	}
 
-	creds := credentials.NewTLS(&tls.Config{
-		ServerName:   "localhost",
-		Certificates: []tls.Certificate{clientCert},
-		RootCAs:      certPool,
-	})
-	daemonAddress := fmt.Sprintf("%s:%s", cfg.GRPCServerName, cfg.GRPCPort)
-	conn, err := grpc.NewClient(daemonAddress, grpc.WithTransportCredentials(creds), grpc.WithUserAgent(GaiaTui))
+	conn, err := grpc.NewClient(daemonAddress(cfg),
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		grpc.WithUserAgent(GaiaTui),
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: reconnectBackoff, MinConnectTimeout: 2 * time.Second}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("TUI failed to connect to daemon: %w", err)
 	}
@@ -50,22 +53,160 @@ func getAdminClientConn(cfg *config.Config) (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
-func GetDaemonStatus(cfg *config.Config) (string, error) {
+func daemonAddress(cfg *config.Config) string {
+	return net.JoinHostPort(cfg.GRPCServerName, cfg.GRPCPort)
+}
+
+// adminConn is the single admin connection shared by the TUI's commands. gRPC
+// reconnects it in the background when the daemon goes away, so it is only
+// dialed again when the daemon address changes.
+type adminConn struct {
+	mu   sync.Mutex
+	addr string
+	conn *grpc.ClientConn
+}
+
+// client returns an admin client on the shared connection, dialing it first if
+// there is none yet or cfg points at a different daemon.
+func (a *adminConn) client(cfg *config.Config) (pb.GaiaAdminClient, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.conn != nil && a.addr == daemonAddress(cfg) {
+		return pb.NewGaiaAdminClient(a.conn), nil
+	}
+	if a.conn != nil {
+		a.conn.Close()
+		a.conn = nil
+	}
 	conn, err := getAdminClientConn(cfg)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	a.conn, a.addr = conn, daemonAddress(cfg)
+	return pb.NewGaiaAdminClient(conn), nil
+}
+
+// Close closes the shared connection.
+func (a *adminConn) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.conn == nil {
+		return nil
+	}
+	err := a.conn.Close()
+	a.conn = nil
+	return err
+}
+
+// Daemon states shown in the status bar.
+const (
+	stateOffline      = "offline"
+	stateLocked       = "locked"
+	stateRunning      = "running"
+	stateUnauthorized = "unauthorized"
+	stateError        = "error"
+)
+
+// classifyStatus maps the result of a GetStatus call to the state shown to the
+// user.
+func classifyStatus(res *pb.GetStatusResponse, err error) string {
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+			return stateOffline
+		case codes.FailedPrecondition:
+			return stateLocked
+		case codes.Unauthenticated, codes.PermissionDenied:
+			return stateUnauthorized
+		default:
+			return stateError
+		}
+	}
+	switch {
+	case res.Locked:
+		return stateLocked
+	case res.Status == daemon.StatusRunning:
+		return stateRunning
+	default:
+		return res.Status
+	}
+}
+
+// lockDescription explains why the daemon is locked, from a GetStatus response
+// or the details of an unlock gate error. It is empty when the daemon is not
+// known to be locked.
+func lockDescription(res *pb.GetStatusResponse, err error) string {
+	if err == nil && res != nil && res.Locked {
+		since, _ := time.Parse(time.RFC3339, res.LockChanged)
+		return daemon.DescribeLock(daemon.LockReason(res.LockReason), since)
+	}
+	if reason, since, ok := daemon.LockDetails(err); ok {
+		return daemon.DescribeLock(reason, since)
+	}
+	return ""
+}
+
+// uptimeDescription says how long a running daemon has been up, from a
+// GetStatus response. It is empty when the daemon did not report it.
+func uptimeDescription(res *pb.GetStatusResponse, err error) string {
+	if err != nil || res == nil {
+		return ""
 	}
-	defer conn.Close()
+	started, perr := time.Parse(time.RFC3339, res.StartedAt)
+	if perr != nil {
+		return ""
+	}
+	return daemon.DescribeUptime(started, time.Duration(res.UptimeSeconds)*time.Second)
+}
+
+// describeState returns the status bar text for a classified daemon state.
+// detail is the lockDescription of a locked daemon and the uptimeDescription of
+// a running one.
+func describeState(state, detail string, err error) string {
+	switch state {
+	case stateOffline:
+		return "Daemon offline, reconnecting..."
+	case stateLocked:
+		if detail == "" {
+			detail = "locked"
+		}
+		return "Daemon " + detail + ", run 'gaia unlock' to manage secrets"
+	case stateRunning:
+		if detail != "" {
+			return "Daemon running, " + detail
+		}
+		return "Daemon running"
+	case stateUnauthorized:
+		return "Daemon rejected the admin certificate: " + status.Convert(err).Message()
+	case stateError:
+		return "Daemon error: " + status.Convert(err).Message()
+	default:
+		return "Daemon " + state
+	}
+}
 
-	client := pb.NewGaiaAdminClient(conn)
+// GetDaemonStatus asks the daemon for its status over conn and returns the
+// classified state, with the lockDescription when it is locked and the
+// uptimeDescription when it is running, and the daemon's banner.
+func GetDaemonStatus(conn *adminConn, cfg *config.Config) (state, detail, banner string, err error) {
+	client, err := conn.client(cfg)
+	if err != nil {
+		// Dialing is lazy, so this is a local problem such as missing certificates.
+		return stateError, "", "", err
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.GRPCClientTimeout)
 	defer cancel()
 
 	res, err := client.GetStatus(ctx, &pb.GetStatusRequest{})
-	if err != nil {
-		return "nil", err
+	state = classifyStatus(res, err)
+	if err == nil {
+		banner = res.Banner
 	}
-
-	return res.Status, nil
+	if state == stateRunning {
+		return state, uptimeDescription(res, err), banner, err
+	}
+	return state, lockDescription(res, err), banner, err
 }
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *pb.GetStatusResponse
+		err  error
+		want string
+	}{
+		{"running", &pb.GetStatusResponse{Status: "running"}, nil, stateRunning},
+		{"locked", &pb.GetStatusResponse{Status: "running", Locked: true}, nil, stateLocked},
+		{"stopping", &pb.GetStatusResponse{Status: "stopped"}, nil, "stopped"},
+		{"unavailable", nil, status.Error(codes.Unavailable, "connection refused"), stateOffline},
+		{"deadline", nil, status.Error(codes.DeadlineExceeded, "timeout"), stateOffline},
+		{"unlock gate", nil, status.Error(codes.FailedPrecondition, "daemon is locked"), stateLocked},
+		{"bad certificate", nil, status.Error(codes.Unauthenticated, "bad certificate"), stateUnauthorized},
+		{"denied", nil, status.Error(codes.PermissionDenied, "not an admin"), stateUnauthorized},
+		{"internal", nil, status.Error(codes.Internal, "boom"), stateError},
+		{"non-status error", nil, errors.New("boom"), stateError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyStatus(tt.res, tt.err); got != tt.want {
+				t.Errorf("classifyStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeState_Locked(t *testing.T) {
+	since := time.Date(2026, 1, 2, 12, 3, 4, 0, time.UTC)
+	res := &pb.GetStatusResponse{Status: "running", Locked: true, LockReason: "manual", LockChanged: since.Format(time.RFC3339)}
+	want := "Daemon locked at " + since.Local().Format(time.TimeOnly) + " by a manual lock, run 'gaia unlock' to manage secrets"
+	if got := describeState(stateLocked, lockDescription(res, nil), nil); got != want {
+		t.Errorf("describeState() = %q, want %q", got, want)
+	}
+
+	err := status.Error(codes.FailedPrecondition, "daemon is locked")
+	if got := describeState(classifyStatus(nil, err), lockDescription(nil, err), err); got != "Daemon locked, run 'gaia unlock' to manage secrets" {
+		t.Errorf("describeState() without lock details = %q", got)
+	}
+}
+
+func TestDescribeState_RunningUptime(t *testing.T) {
+	started := time.Date(2026, 1, 2, 12, 3, 4, 0, time.UTC)
+	res := &pb.GetStatusResponse{Status: "running", StartedAt: started.Format(time.RFC3339), UptimeSeconds: 3*3600 + 125}
+	want := "Daemon running, up 3h2m5s since " + started.Local().Format(time.DateTime)
+	if got := describeState(classifyStatus(res, nil), uptimeDescription(res, nil), nil); got != want {
+		t.Errorf("describeState() = %q, want %q", got, want)
+	}
+
+	// An older daemon does not report its start time.
+	res = &pb.GetStatusResponse{Status: "running"}
+	if got := describeState(classifyStatus(res, nil), uptimeDescription(res, nil), nil); got != "Daemon running" {
+		t.Errorf("describeState() without uptime = %q", got)
+	}
+}
+
+func TestModel_ShowsBanner(t *testing.T) {
+	m := initialModel(config.NewDefaultConfig())
+	m.width, m.height = 100, 40
+	updated, _ := m.Update(statusUpdatedMsg{status: stateRunning, banner: "staging-eu"})
+	m = updated.(*model)
+	if !strings.Contains(m.View(), "staging-eu") {
+		t.Error("View() does not show the daemon banner")
+	}
+
+	// A failed status check keeps the last banner.
+	updated, _ = m.Update(statusUpdatedMsg{status: stateOffline, err: status.Error(codes.Unavailable, "connection refused")})
+	if m = updated.(*model); m.daemonBanner != "staging-eu" {
+		t.Errorf("daemonBanner after a failed status check = %q, want it kept", m.daemonBanner)
+	}
+}
+
+func TestDaemonAddress(t *testing.T) {
+	tests := []struct{ host, port, want string }{
+		{"localhost", "50051", "localhost:50051"},
+		{"gaia.internal", "6000", "gaia.internal:6000"},
+		{"::1", "6000", "[::1]:6000"},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{GRPCServerName: tt.host, GRPCPort: tt.port}
+		if got := daemonAddress(cfg); got != tt.want {
+			t.Errorf("daemonAddress(%s, %s) = %q, want %q", tt.host, tt.port, got, tt.want)
+		}
+	}
+}
@@ -14,6 +14,8 @@ type keyMap struct {
 	Help     key.Binding
 	Tab      key.Binding
 	ShiftTab key.Binding
+	New      key.Binding
+	Register key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the short help view.
@@ -24,8 +26,8 @@ func (k keyMap) ShortHelp() []key.Binding {
 // FullHelp returns keybindings for the expanded help view.
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Enter},                     // first column
-		{k.Tab, k.ShiftTab, k.Back, k.Help, k.Quit}, // second column
+		{k.Up, k.Down, k.Enter}, // first column
+		{k.Tab, k.ShiftTab, k.New, k.Register, k.Back, k.Help, k.Quit}, // second column
 	}
 }
 
@@ -62,4 +64,12 @@ var keys = keyMap{
 		key.WithKeys("shift+tab"),
 		key.WithHelp("shift+tab", "reverse cycle focus"),
 	),
+	New: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "new namespace"),
+	),
+	Register: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "register client"),
+	),
 }
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -12,6 +13,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/stain-win/gaia/apps/gaia/config"
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"github.com/stain-win/gaia/apps/gaia/validation"
 )
 
 var (
@@ -22,6 +24,10 @@ var (
 	focusedPaneStyle = paneStyle.BorderForeground(lipgloss.Color("69"))
 )
 
+// multilineEditThreshold is the value length above which the edit form switches
+// to a multi-line text area, even for values without newlines.
+const multilineEditThreshold = 80
+
 type inspectorPane int
 
 const (
@@ -33,6 +39,7 @@ const (
 // inspectorModel holds the state for our three-pane view.
 type inspectorModel struct {
 	config *config.Config
+	conn   *adminConn
 	width  int
 	height int
 
@@ -43,6 +50,8 @@ type inspectorModel struct {
 	tbl         table.Model
 
 	allData           map[string][]*pb.Namespace
+	secretsGen        map[string]int // latest load of each client's secrets
+	clientsLoaded     bool           // the client list was loaded at least once
 	selectedClient    string
 	lastNamespaceName string // To restore selection after updates
 	statusMessage     string
@@ -53,9 +62,17 @@ type inspectorModel struct {
 	editKey       string
 	editValue     string
 	editNamespace string
+	// confirmingEmpty is set while the edit form asks whether an empty value
+	// should really be saved.
+	confirmingEmpty bool
+	emptyConfirmed  bool
+
+	// Namespace creation form state
+	creating   bool
+	createForm *huh.Form
 }
 
-func newInspectorModel(cfg *config.Config) *inspectorModel {
+func newInspectorModel(cfg *config.Config, conn *adminConn) *inspectorModel {
 	clientsList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
 	clientsList.Title = "Clients"
 	clientsList.SetShowHelp(false)
@@ -68,17 +85,19 @@ func newInspectorModel(cfg *config.Config) *inspectorModel {
 
 	return &inspectorModel{
 		config:            cfg,
+		conn:              conn,
 		clientsList:       clientsList,
 		secretsList:       secretsList,
 		viewport:          vp,
 		focusedPane:       clientsPane,
 		allData:           make(map[string][]*pb.Namespace),
+		secretsGen:        make(map[string]int),
 		lastNamespaceName: "",
 	}
 }
 
 func (m *inspectorModel) Init() tea.Cmd {
-	return fetchAllClientsCmd(m.config)
+	return fetchAllClientsCmd(m.conn, m.config)
 }
 
 // Update is the main message handler for the inspector view.
@@ -86,6 +105,9 @@ func (m *inspectorModel) Update(msg tea.Msg) (*inspectorModel, tea.Cmd) {
 	if m.editing {
 		return m.updateEditView(msg)
 	}
+	if m.creating {
+		return m.updateCreateView(msg)
+	}
 
 	var cmds []tea.Cmd
 
@@ -104,13 +126,16 @@ func (m *inspectorModel) Update(msg tea.Msg) (*inspectorModel, tea.Cmd) {
 		if msg.err != nil {
 			m.statusMessage = fmt.Sprintf("Error: %v. Reverting.", msg.err)
 			// Re-fetch to get the true state from the server
-			return m, fetchSecretsForClientCmd(m.config, m.selectedClient)
+			return m, m.fetchSecrets(m.selectedClient)
 		}
 		m.statusMessage = "Secret updated successfully!"
 		// No need to re-fetch, optimistic update was successful
 		m.lastNamespaceName = "" // Clear the saved name
 		return m, nil
 
+	case namespaceCreatedMsg:
+		return m.handleNamespaceCreated(msg)
+
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, keys.Back):
@@ -138,6 +163,11 @@ func (m *inspectorModel) Update(msg tea.Msg) (*inspectorModel, tea.Cmd) {
 
 // updateEditView handles all updates when the edit form is active.
 func (m *inspectorModel) updateEditView(msg tea.Msg) (*inspectorModel, tea.Cmd) {
+	if sizeMsg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.SetSize(sizeMsg.Width, sizeMsg.Height)
+		m.editForm = m.editForm.WithWidth(m.editFormWidth())
+	}
+
 	var cmds []tea.Cmd
 	form, cmd := m.editForm.Update(msg)
 	if f, ok := form.(*huh.Form); ok {
@@ -146,9 +176,18 @@ func (m *inspectorModel) updateEditView(msg tea.Msg) (*inspectorModel, tea.Cmd)
 	cmds = append(cmds, cmd)
 
 	if m.editForm.State == huh.StateCompleted {
-		newValue := m.editForm.GetString("value")
+		if m.confirmingEmpty {
+			m.confirmingEmpty = false
+			if !m.emptyConfirmed {
+				// Back to the editor rather than saving an empty value.
+				return m, m.openEditForm()
+			}
+		} else if strings.TrimSpace(m.editValue) == "" {
+			return m, m.openConfirmEmptyForm()
+		}
+
+		newValue := m.editValue
 		m.editing = false
-		m.editValue = newValue // Store for optimistic update
 
 		// Optimistically update local data
 		if namespaces, ok := m.allData[m.selectedClient]; ok {
@@ -168,9 +207,10 @@ func (m *inspectorModel) updateEditView(msg tea.Msg) (*inspectorModel, tea.Cmd)
 		m.updateSecretsList()
 
 		// Send the update to the daemon
-		cmds = append(cmds, addRecordToDaemonCmd(m.config, m.selectedClient, m.editNamespace, m.editKey, newValue))
+		cmds = append(cmds, addRecordToDaemonCmd(m.conn, m.config, m.selectedClient, m.editNamespace, m.editKey, newValue))
 	} else if m.editForm.State == huh.StateAborted {
 		m.editing = false
+		m.confirmingEmpty = false
 		m.statusMessage = "Edit cancelled."
 		m.lastNamespaceName = ""
 	}
@@ -180,6 +220,12 @@ func (m *inspectorModel) updateEditView(msg tea.Msg) (*inspectorModel, tea.Cmd)
 
 // updateClientsPane handles updates when the clients list is focused.
 func (m *inspectorModel) updateClientsPane(msg tea.Msg) tea.Cmd {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.clientsList.FilterState() != list.Filtering {
+		if key.Matches(keyMsg, keys.Register) {
+			return func() tea.Msg { return openRegisterClientMsg{} }
+		}
+	}
+
 	var cmd tea.Cmd
 	m.clientsList, cmd = m.clientsList.Update(msg)
 
@@ -192,7 +238,7 @@ func (m *inspectorModel) updateClientsPane(msg tea.Msg) tea.Cmd {
 			m.lastNamespaceName = "" // Reset when client changes
 
 			if _, ok := m.allData[m.selectedClient]; !ok {
-				return fetchSecretsForClientCmd(m.config, m.selectedClient)
+				return m.fetchSecrets(m.selectedClient)
 			}
 			m.updateSecretsList()
 		}
@@ -204,11 +250,14 @@ func (m *inspectorModel) updateClientsPane(msg tea.Msg) tea.Cmd {
 func (m *inspectorModel) updateSecretsPane(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
 
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.secretsList.FilterState() != list.Filtering {
 		if key.Matches(keyMsg, keys.Enter) {
 			_, cmd = m.cycleFocus(true) // Cycle forward to the view pane
 			return cmd
 		}
+		if key.Matches(keyMsg, keys.New) && m.selectedClient != "" {
+			return m.startCreateNamespace()
+		}
 	}
 
 	m.secretsList, cmd = m.secretsList.Update(msg)
@@ -231,20 +280,164 @@ func (m *inspectorModel) updateViewPane(msg tea.Msg) tea.Cmd {
 				m.editNamespace = nsItem.name
 			}
 			m.editKey = row[0]
-			m.editValue = row[1]
-
-			// Create and initialize the form
-			input := huh.NewInput().
-				Title(fmt.Sprintf("New value for %s", m.editKey)).
-				Value(&m.editValue).Key("value")
-
-			m.editForm = huh.NewForm(huh.NewGroup(input)).WithTheme(huh.ThemeBase())
-			return m.editForm.Init()
+			// The table cell may be truncated or flattened, so edit the stored value.
+			m.editValue = m.secretValue(m.editNamespace, m.editKey, row[1])
+			return m.openEditForm()
 		}
 	}
 	return cmd
 }
 
+// secretValue returns the locally cached value of a secret of the selected client,
+// or fallback if it is not cached.
+func (m *inspectorModel) secretValue(namespace, id, fallback string) string {
+	for _, ns := range m.allData[m.selectedClient] {
+		if ns.Name != namespace {
+			continue
+		}
+		for _, secret := range ns.Secrets {
+			if secret.Id == id {
+				return secret.Value
+			}
+		}
+	}
+	return fallback
+}
+
+// openEditForm builds the edit form for m.editValue. Values with newlines or longer
+// than multilineEditThreshold are edited in a multi-line text area.
+func (m *inspectorModel) openEditForm() tea.Cmd {
+	title := fmt.Sprintf("New value for %s", m.editKey)
+
+	var field huh.Field
+	if strings.Contains(m.editValue, "\n") || len(m.editValue) > multilineEditThreshold {
+		field = huh.NewText().
+			Title(title).
+			Description("alt+enter inserts a new line, ctrl+e opens $EDITOR").
+			Lines(m.editTextLines()).
+			Value(&m.editValue).Key("value")
+	} else {
+		field = huh.NewInput().
+			Title(title).
+			Value(&m.editValue).Key("value")
+	}
+
+	m.editForm = huh.NewForm(huh.NewGroup(field)).
+		WithTheme(huh.ThemeBase()).
+		WithWidth(m.editFormWidth())
+	return m.editForm.Init()
+}
+
+// openConfirmEmptyForm asks whether an empty value should be saved.
+func (m *inspectorModel) openConfirmEmptyForm() tea.Cmd {
+	m.confirmingEmpty = true
+	m.emptyConfirmed = false
+	confirm := huh.NewConfirm().
+		Title(fmt.Sprintf("Save an empty value for %s?", m.editKey)).
+		Affirmative("Save").
+		Negative("Keep editing").
+		Value(&m.emptyConfirmed)
+
+	m.editForm = huh.NewForm(huh.NewGroup(confirm)).
+		WithTheme(huh.ThemeBase()).
+		WithWidth(m.editFormWidth())
+	return m.editForm.Init()
+}
+
+// editFormWidth is the width of the edit form, leaving room for the pane border.
+func (m *inspectorModel) editFormWidth() int {
+	return max(20, min(m.width-6, 100))
+}
+
+// editTextLines is the height of the multi-line editor: enough for the value, but
+// never taller than the terminal allows.
+func (m *inspectorModel) editTextLines() int {
+	lines := strings.Count(m.editValue, "\n") + 2
+	return max(3, min(lines, m.height-10))
+}
+
+// startCreateNamespace opens the form for a new namespace and its first secret.
+func (m *inspectorModel) startCreateNamespace() tea.Cmd {
+	m.creating = true
+
+	var namespace, secretKey, value string
+	m.createForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("New namespace for %s", m.selectedClient)).
+				Key("namespace").
+				Value(&namespace).
+				Validate(func(s string) error {
+					if err := validation.ValidateName(s); err != nil {
+						return err
+					}
+					for _, ns := range m.allData[m.selectedClient] {
+						if ns.Name == s {
+							return fmt.Errorf("namespace '%s' already exists", s)
+						}
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("First key").
+				Key("key").
+				Value(&secretKey).
+				Validate(validation.ValidateName),
+			huh.NewInput().
+				Title("Value").
+				Key("value").
+				Value(&value),
+		),
+	).WithTheme(huh.ThemeBase())
+	return m.createForm.Init()
+}
+
+// updateCreateView handles all updates when the namespace creation form is active.
+func (m *inspectorModel) updateCreateView(msg tea.Msg) (*inspectorModel, tea.Cmd) {
+	form, cmd := m.createForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.createForm = f
+	}
+
+	switch m.createForm.State {
+	case huh.StateCompleted:
+		m.creating = false
+		namespace := m.createForm.GetString("namespace")
+		m.statusMessage = fmt.Sprintf("Creating namespace %s...", namespace)
+		return m, createNamespaceCmd(m.conn, m.config, m.selectedClient, namespace,
+			m.createForm.GetString("key"), m.createForm.GetString("value"))
+	case huh.StateAborted:
+		m.creating = false
+		m.statusMessage = "Namespace creation cancelled."
+		return m, nil
+	}
+	return m, cmd
+}
+
+// handleNamespaceCreated adds a newly created namespace to the local data and selects it.
+func (m *inspectorModel) handleNamespaceCreated(msg namespaceCreatedMsg) (*inspectorModel, tea.Cmd) {
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Error creating namespace: %v", msg.err)
+		return m, nil
+	}
+
+	m.allData[msg.clientName] = append(m.allData[msg.clientName], &pb.Namespace{
+		Name:    msg.namespace,
+		Secrets: []*pb.Secret{msg.secret},
+	})
+	m.statusMessage = fmt.Sprintf("Namespace %s created.", msg.namespace)
+	if len(msg.warnings) > 0 {
+		m.statusMessage = fmt.Sprintf("Namespace %s created with warnings: %s", msg.namespace, strings.Join(msg.warnings, "; "))
+	}
+
+	if msg.clientName == m.selectedClient {
+		m.lastNamespaceName = msg.namespace
+		m.updateSecretsList()
+		m.lastNamespaceName = ""
+	}
+	return m, nil
+}
+
 // cycleFocus moves the focus between the three panes.
 func (m *inspectorModel) cycleFocus(forward bool) (*inspectorModel, tea.Cmd) {
 	if forward {
@@ -265,8 +458,10 @@ func (m *inspectorModel) cycleFocus(forward bool) (*inspectorModel, tea.Cmd) {
 // handleClientsLoaded processes the message with the list of all clients.
 func (m *inspectorModel) handleClientsLoaded(msg allClientsLoadedMsg) (*inspectorModel, tea.Cmd) {
 	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Error loading clients: %v", msg.err)
 		return m, nil
 	}
+	m.clientsLoaded = true
 	var items []list.Item
 	for _, client := range msg.clients {
 		items = append(items, listItem{title: client.Name, description: "Client"})
@@ -275,21 +470,56 @@ func (m *inspectorModel) handleClientsLoaded(msg allClientsLoadedMsg) (*inspecto
 
 	if len(msg.clients) > 0 {
 		m.selectedClient = msg.clients[0].Name
-		return m, fetchSecretsForClientCmd(m.config, m.selectedClient)
+		return m, m.fetchSecrets(m.selectedClient)
 	}
+	m.selectedClient = ""
+	m.secretsList.SetItems(nil)
+	m.viewport.SetContent("")
 	return m, nil
 }
 
-// handleSecretsLoaded processes the message with secrets for a specific client.
+// fetchSecrets starts a new load of clientName's secrets. Messages of earlier
+// loads of the client are dropped from then on.
+func (m *inspectorModel) fetchSecrets(clientName string) tea.Cmd {
+	m.secretsGen[clientName]++
+	return fetchSecretsForClientCmd(m.conn, m.config, clientName, m.secretsGen[clientName])
+}
+
+// loadingSecretsStatus starts the status message shown while secrets stream in.
+const loadingSecretsStatus = "Loading secrets:"
+
+// handleSecretsLoaded adds the streamed namespaces of a client to the loaded
+// secrets and asks for the next part of the stream.
 func (m *inspectorModel) handleSecretsLoaded(msg secretsForClientLoadedMsg) (*inspectorModel, tea.Cmd) {
+	if msg.gen != m.secretsGen[msg.clientName] {
+		// A later load of the client replaced this stream.
+		if msg.cancel != nil {
+			msg.cancel()
+		}
+		return m, nil
+	}
 	if msg.err != nil {
+		// Drop what was loaded so selecting the client again reloads it.
+		delete(m.allData, msg.clientName)
+		if msg.clientName == m.selectedClient {
+			m.statusMessage = fmt.Sprintf("Error loading secrets: %v", msg.err)
+			m.updateSecretsList()
+		}
 		return m, nil
 	}
-	m.allData[msg.clientName] = msg.namespaces
+	if msg.first {
+		m.allData[msg.clientName] = nil
+	}
+	m.allData[msg.clientName] = append(m.allData[msg.clientName], msg.namespaces...)
 	if msg.clientName == m.selectedClient {
+		if !msg.done {
+			m.statusMessage = fmt.Sprintf("%s %d of %d namespaces", loadingSecretsStatus, len(m.allData[msg.clientName]), msg.total)
+		} else if strings.HasPrefix(m.statusMessage, loadingSecretsStatus) {
+			m.statusMessage = ""
+		}
 		m.updateSecretsList()
 	}
-	return m, nil
+	return m, msg.next
 }
 
 // updateSecretsList populates the secrets list based on the selected client.
@@ -345,11 +575,23 @@ func (m *inspectorModel) View() string {
 	if m.editing {
 		return m.renderEditView()
 	}
+	if m.creating {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+			paneStyle.Render(m.createForm.View()),
+		)
+	}
 
 	// Build the main three-pane view
-	clientsView := m.clientsList.View()
-	secretsView := m.secretsList.View()
-	viewportView := m.viewport.View()
+	clientsView, secretsView, viewportView := m.clientsList.View(), m.secretsList.View(), m.viewport.View()
+	if msg := m.emptyClientsMessage(); msg != "" {
+		clientsView = m.renderEmptyPane(m.clientsList, msg)
+	}
+	if msg := m.emptySecretsMessage(); msg != "" {
+		secretsView = m.renderEmptyPane(m.secretsList, msg)
+	}
+	if msg := m.emptyViewMessage(); msg != "" {
+		viewportView = lipgloss.NewStyle().Width(m.viewport.Width).Height(m.viewport.Height).Render(msg)
+	}
 
 	clientsStyle, secretsStyle, viewportStyle := paneStyle, paneStyle, paneStyle
 	switch m.focusedPane {
@@ -387,6 +629,55 @@ func (m *inspectorModel) View() string {
 	)
 }
 
+// emptyClientsMessage is the guidance shown in place of an empty clients list.
+func (m *inspectorModel) emptyClientsMessage() string {
+	if !m.clientsLoaded || len(m.clientsList.Items()) > 0 {
+		return ""
+	}
+	return "No clients registered. Press r to register one."
+}
+
+// emptySecretsMessage is the guidance shown in place of an empty namespaces
+// list, once the secrets of the selected client are loaded.
+func (m *inspectorModel) emptySecretsMessage() string {
+	if m.selectedClient == "" {
+		if m.clientsLoaded {
+			return "Register a client to store secrets."
+		}
+		return ""
+	}
+	if namespaces, loaded := m.allData[m.selectedClient]; !loaded || len(namespaces) > 0 {
+		return ""
+	}
+	return fmt.Sprintf("No secrets for %s. Press n to create a namespace.", m.selectedClient)
+}
+
+// emptyViewMessage is the guidance shown in the value pane when there is no
+// secret to show.
+func (m *inspectorModel) emptyViewMessage() string {
+	nsItem, ok := m.secretsList.SelectedItem().(namespaceListItem)
+	switch {
+	case !ok && m.emptySecretsMessage() != "":
+		return "Nothing to show yet."
+	case ok && len(nsItem.secrets) == 0:
+		return "No secrets in this namespace."
+	}
+	return ""
+}
+
+// renderEmptyPane renders msg under the title of l, in the size of l.
+func (m *inspectorModel) renderEmptyPane(l list.Model, msg string) string {
+	title := l.Styles.Title.Render(l.Title)
+	body := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Width(l.Width()).
+		Padding(1, 0).
+		Render(msg)
+	return lipgloss.NewStyle().Width(l.Width()).Height(l.Height()).Render(
+		lipgloss.JoinVertical(lipgloss.Left, title, body),
+	)
+}
+
 // renderEditView renders the form for editing a secret's value.
 func (m *inspectorModel) renderEditView() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
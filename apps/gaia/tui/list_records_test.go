@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stain-win/gaia/apps/gaia/config"
+	pb "github.com/stain-win/gaia/apps/gaia/proto"
+	"google.golang.org/grpc"
+)
+
+// fakeSecretsStream replays StreamSecrets responses.
+type fakeSecretsStream struct {
+	grpc.ClientStream
+	responses []*pb.StreamSecretsResponse
+}
+
+func (s *fakeSecretsStream) Recv() (*pb.StreamSecretsResponse, error) {
+	if len(s.responses) == 0 {
+		return nil, io.EOF
+	}
+	res := s.responses[0]
+	s.responses = s.responses[1:]
+	return res, nil
+}
+
+func TestInspector_AccumulatesStreamedSecrets(t *testing.T) {
+	const total = 250
+	stream := &fakeSecretsStream{}
+	for i := range total {
+		stream.responses = append(stream.responses, &pb.StreamSecretsResponse{
+			Namespace:       &pb.Namespace{Name: fmt.Sprintf("ns-%03d", i), Secrets: []*pb.Secret{{Id: "key", Value: "value"}}},
+			TotalNamespaces: total,
+		})
+	}
+
+	m := newInspectorModel(config.NewDefaultConfig(), nil)
+	m.selectedClient = "app"
+	m.allData["app"] = []*pb.Namespace{{Name: "stale"}}
+
+	cancelled := 0
+	cmd := receiveSecretsCmd("app", 0, stream, func() { cancelled++ }, true)
+	for i := 0; cmd != nil; i++ {
+		if i > total {
+			t.Fatal("stream did not end")
+		}
+		m, cmd = m.Update(cmd())
+		if cmd != nil && m.statusMessage == "" {
+			t.Error("no progress shown while secrets stream in")
+		}
+	}
+
+	got := m.allData["app"]
+	if len(got) != total {
+		t.Fatalf("inspector holds %d namespaces, want %d", len(got), total)
+	}
+	if got[0].Name != "ns-000" || got[total-1].Name != fmt.Sprintf("ns-%03d", total-1) {
+		t.Errorf("namespaces = %s ... %s, want ns-000 ... ns-%03d", got[0].Name, got[total-1].Name, total-1)
+	}
+	if n := len(m.secretsList.Items()); n != total {
+		t.Errorf("namespace list shows %d items, want %d", n, total)
+	}
+	if m.statusMessage != "" {
+		t.Errorf("statusMessage = %q after the stream ended, want it cleared", m.statusMessage)
+	}
+	if cancelled != 1 {
+		t.Errorf("stream cancelled %d times, want once", cancelled)
+	}
+}
+
+func TestInspector_DropsStaleStream(t *testing.T) {
+	m := newInspectorModel(config.NewDefaultConfig(), nil)
+	m.selectedClient = "app"
+	m.fetchSecrets("app")
+	stale := m.secretsGen["app"]
+	m.fetchSecrets("app")
+
+	m, _ = m.Update(secretsForClientLoadedMsg{clientName: "app", gen: m.secretsGen["app"], first: true,
+		namespaces: []*pb.Namespace{{Name: "current"}}, total: 1})
+
+	cancelled := 0
+	m, cmd := m.Update(secretsForClientLoadedMsg{clientName: "app", gen: stale,
+		namespaces: []*pb.Namespace{{Name: "old"}}, total: 1, next: func() tea.Msg { return nil }, cancel: func() { cancelled++ }})
+	if cmd != nil {
+		t.Error("a stale stream was asked for its next message")
+	}
+	if cancelled != 1 {
+		t.Errorf("stale stream cancelled %d times, want once", cancelled)
+	}
+	if got := m.allData["app"]; len(got) != 1 || got[0].Name != "current" {
+		t.Errorf("allData = %v, want only the namespace of the current stream", got)
+	}
+}
+
+func TestInspector_StreamErrorDropsSecrets(t *testing.T) {
+	m := newInspectorModel(config.NewDefaultConfig(), nil)
+	m.selectedClient = "app"
+	m.fetchSecrets("app")
+	gen := m.secretsGen["app"]
+
+	m, _ = m.Update(secretsForClientLoadedMsg{clientName: "app", gen: gen, first: true,
+		namespaces: []*pb.Namespace{{Name: "partial"}}, total: 2})
+	m, _ = m.Update(secretsForClientLoadedMsg{clientName: "app", gen: gen, err: io.ErrUnexpectedEOF})
+	if _, ok := m.allData["app"]; ok {
+		t.Error("secrets of a failed stream kept, so the client is not reloaded")
+	}
+	if n := len(m.secretsList.Items()); n != 0 {
+		t.Errorf("namespace list shows %d items after a failed stream, want none", n)
+	}
+	if !strings.Contains(m.statusMessage, "Error loading secrets") {
+		t.Errorf("statusMessage = %q, want the stream error", m.statusMessage)
+	}
+}
+
+func TestInspector_EmptyStore(t *testing.T) {
+	m := newInspectorModel(config.NewDefaultConfig(), nil)
+	m.SetSize(120, 40)
+	if strings.Contains(m.View(), "No clients registered") {
+		t.Error("empty clients message shown before the clients were loaded")
+	}
+
+	m, cmd := m.Update(allClientsLoadedMsg{})
+	if cmd != nil {
+		t.Error("loading an empty client list fetched secrets")
+	}
+	if got := m.emptyClientsMessage(); got != "No clients registered. Press r to register one." {
+		t.Errorf("emptyClientsMessage() = %q", got)
+	}
+	if got := m.emptySecretsMessage(); got != "Register a client to store secrets." {
+		t.Errorf("emptySecretsMessage() = %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "No clients registered.") {
+		t.Errorf("view of an empty store lacks the clients hint:\n%s", view)
+	}
+
+	// The register shortcut works from the empty clients pane.
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if cmd == nil {
+		t.Fatal("r in the clients pane returned no command")
+	}
+	if _, ok := cmd().(openRegisterClientMsg); !ok {
+		t.Errorf("r in the clients pane sent %T, want openRegisterClientMsg", cmd())
+	}
+
+	// A client without secrets gets its own hint.
+	m, _ = m.Update(allClientsLoadedMsg{clients: []*pb.Client{{Name: "app"}}})
+	m, _ = m.Update(secretsForClientLoadedMsg{clientName: "app", gen: m.secretsGen["app"], first: true, done: true})
+	if got := m.emptySecretsMessage(); got != "No secrets for app. Press n to create a namespace." {
+		t.Errorf("emptySecretsMessage() for a client without secrets = %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "No secrets for app.") || strings.Contains(view, "No clients registered") {
+		t.Errorf("view of a client without secrets shows the wrong hints:\n%s", view)
+	}
+}
+
+func TestModel_RegisterFromInspector(t *testing.T) {
+	m := &model{activeScreen: listRecords, registerReturnScreen: certManagement, registerClientFormModel: newRegisterClientFormModel()}
+	updated, _ := m.Update(openRegisterClientMsg{})
+	m = updated.(*model)
+	if m.activeScreen != registerClient {
+		t.Fatalf("activeScreen = %v, want registerClient", m.activeScreen)
+	}
+	updated, _ = m.Update(BackMsg{})
+	if m = updated.(*model); m.activeScreen != listRecords {
+		t.Errorf("activeScreen after cancelling = %v, want listRecords", m.activeScreen)
+	}
+}
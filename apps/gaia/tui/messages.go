@@ -2,8 +2,11 @@ package tui
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
 
+	"github.com/stain-win/gaia/apps/gaia/certs"
 	"github.com/stain-win/gaia/apps/gaia/config"
 	pb "github.com/stain-win/gaia/apps/gaia/proto"
 )
@@ -25,6 +28,10 @@ type SecretsFetchedMsg struct {
 
 type backToDataManagementMsg struct{}
 
+// openRegisterClientMsg asks to open the register client form from the
+// inspector, returning to it once the form is done.
+type openRegisterClientMsg struct{}
+
 type recordAddResultMsg struct {
 	err error
 }
@@ -40,8 +47,47 @@ type recordAddedMsg struct {
 	err error
 }
 
+// namespaceCreatedMsg is sent when the AddSecret RPC creating a namespace is complete.
+type namespaceCreatedMsg struct {
+	clientName string
+	namespace  string
+	secret     *pb.Secret
+	warnings   []string
+	err        error
+}
+
+// clientRegisteredMsg is sent when the RegisterClient RPC is complete and the
+// certificate has been written.
+type clientRegisteredMsg struct {
+	clientName string
+	certPath   string
+	configPath string // Empty unless the connection file was requested.
+	err        error
+}
+
+// passphraseChangedMsg is sent when the Rekey RPC is complete.
+type passphraseChangedMsg struct {
+	secrets int
+	err     error
+}
+
+// certStepMsg is sent after each step of a certificate generation. The next
+// progress message is read from ch.
+type certStepMsg struct {
+	step certs.CertStep
+	ch   <-chan tea.Msg
+}
+
+// certsGeneratedMsg is sent when a certificate generation has finished.
+type certsGeneratedMsg struct {
+	dir string
+	err error
+}
+
 type statusUpdatedMsg struct {
 	status string
+	detail string
+	banner string
 	err    error
 }
 
@@ -51,11 +97,24 @@ type allClientsLoadedMsg struct {
 	err     error
 }
 
-// secretsForClientLoadedMsg is sent when ListSecrets RPC is complete.
+// secretsForClientLoadedMsg is sent for every namespace a StreamSecrets call
+// receives, and once more with done set when the stream ends.
 type secretsForClientLoadedMsg struct {
 	clientName string
+	// gen is the load of the client the message belongs to. Messages of a load
+	// that a later one replaced are dropped.
+	gen        int
 	namespaces []*pb.Namespace
-	err        error
+	// first marks the first message of a load, which replaces the client's
+	// previously loaded secrets.
+	first bool
+	done  bool
+	total int // Namespaces the stream sends in all.
+	// next receives the following message of the stream, and cancel releases
+	// the stream when it is dropped instead.
+	next   tea.Cmd
+	cancel context.CancelFunc
+	err    error
 }
 
 // A mock function to simulate fetching namespaces from the daemon.
@@ -93,15 +152,12 @@ func FetchSecretsForNamespace(namespace string) tea.Cmd {
 }
 
 // fetchClientsCmd is a command that fetches the list of registered clients.
-func fetchClientsCmd(cfg *config.Config) tea.Cmd {
+func fetchClientsCmd(conn *adminConn, cfg *config.Config) tea.Cmd {
 	return func() tea.Msg {
-		conn, err := getAdminClientConn(cfg)
+		client, err := conn.client(cfg)
 		if err != nil {
 			return clientsLoadedMsg{err: err}
 		}
-		defer conn.Close()
-
-		client := pb.NewGaiaAdminClient(conn)
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
@@ -116,15 +172,12 @@ func fetchClientsCmd(cfg *config.Config) tea.Cmd {
 }
 
 // addRecordToDaemonCmd makes the gRPC call to add a new secret.
-func addRecordToDaemonCmd(cfg *config.Config, clientName, namespace, key, value string) tea.Cmd {
+func addRecordToDaemonCmd(conn *adminConn, cfg *config.Config, clientName, namespace, key, value string) tea.Cmd {
 	return func() tea.Msg {
-		conn, err := getAdminClientConn(cfg)
+		client, err := conn.client(cfg)
 		if err != nil {
 			return recordAddResultMsg{err: err}
 		}
-		defer conn.Close()
-
-		client := pb.NewGaiaAdminClient(conn)
 		ctx, cancel := context.WithTimeout(context.Background(), cfg.GRPCClientTimeout)
 		defer cancel()
 
@@ -138,33 +191,136 @@ func addRecordToDaemonCmd(cfg *config.Config, clientName, namespace, key, value
 	}
 }
 
-func checkStatusCmd(cfg *config.Config) tea.Cmd {
+// createNamespaceCmd materializes a new namespace by adding its first secret.
+func createNamespaceCmd(conn *adminConn, cfg *config.Config, clientName, namespace, key, value string) tea.Cmd {
+	return func() tea.Msg {
+		result := namespaceCreatedMsg{
+			clientName: clientName,
+			namespace:  namespace,
+			secret:     &pb.Secret{Id: key, Value: value},
+		}
+
+		client, err := conn.client(cfg)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.GRPCClientTimeout)
+		defer cancel()
+
+		res, err := client.AddSecret(ctx, &pb.AddSecretRequest{
+			ClientName: clientName,
+			Namespace:  namespace,
+			Id:         key,
+			Value:      value,
+		})
+		if err != nil {
+			result.err = err
+			return result
+		}
+		if !res.Success {
+			result.err = errors.New(res.Message)
+			return result
+		}
+		result.warnings = res.Warnings
+		return result
+	}
+}
+
+// registerClientCmd registers clientName, writes its certificate and key to the
+// configured certs directory and, with emitConfig, its connection file.
+func registerClientCmd(conn *adminConn, cfg *config.Config, clientName string, emitConfig bool) tea.Cmd {
+	return func() tea.Msg {
+		result := clientRegisteredMsg{clientName: clientName}
+
+		client, err := conn.client(cfg)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.GRPCClientTimeout)
+		defer cancel()
+
+		res, err := client.RegisterClient(ctx, &pb.RegisterClientRequest{ClientName: clientName})
+		if err != nil {
+			result.err = err
+			return result
+		}
+		paths, err := certs.SaveClientCertificate(cfg, cfg.CertsDirectory, clientName, []byte(res.Certificate), []byte(res.PrivateKey))
+		if err != nil {
+			result.err = err
+			return result
+		}
+		result.certPath = paths.Cert
+		if emitConfig {
+			result.configPath, result.err = certs.WriteClientConnConfig(cfg, paths, clientName)
+		}
+		return result
+	}
+}
+
+// rekeyTimeout bounds a Rekey call, which re-encrypts every secret.
+const rekeyTimeout = 5 * time.Minute
+
+// changePassphraseCmd asks the daemon to change the master passphrase.
+func changePassphraseCmd(conn *adminConn, cfg *config.Config, oldPassphrase, newPassphrase string) tea.Cmd {
 	return func() tea.Msg {
-		status, err := GetDaemonStatus(cfg)
+		client, err := conn.client(cfg)
 		if err != nil {
-			return statusUpdatedMsg{
-				status: "offline",
-				err:    err,
-			}
+			return passphraseChangedMsg{err: err}
 		}
+		ctx, cancel := context.WithTimeout(context.Background(), rekeyTimeout)
+		defer cancel()
 
-		return statusUpdatedMsg{
-			status: status,
-			err:    nil,
+		res, err := client.Rekey(ctx, &pb.RekeyRequest{OldPassphrase: oldPassphrase, NewPassphrase: newPassphrase, VerifyFirst: true})
+		if err != nil {
+			return passphraseChangedMsg{err: err}
 		}
+		return passphraseChangedMsg{secrets: int(res.Secrets)}
+	}
+}
+
+// generateCertsCmd generates the certificates requested by req with the
+// settings of cfg, such as the validity and file names. It reports every
+// completed step with a certStepMsg and ends with a certsGeneratedMsg.
+func generateCertsCmd(cfg *config.Config, req CreateCertsMsg) tea.Cmd {
+	return func() tea.Msg {
+		certCfg := *cfg
+		certCfg.CertsDirectory = req.OutputDir
+
+		ch := make(chan tea.Msg, 1)
+		go func() {
+			defer close(ch)
+			err := certs.GenerateTLSCertificates(&certCfg, req.CAName, req.ServerName, req.ClientName, func(step certs.CertStep) {
+				ch <- certStepMsg{step: step, ch: ch}
+			})
+			ch <- certsGeneratedMsg{dir: req.OutputDir, err: err}
+		}()
+		return <-ch
+	}
+}
+
+// waitForCertStep returns the next message of a running certificate generation.
+func waitForCertStep(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+func checkStatusCmd(conn *adminConn, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		status, detail, banner, err := GetDaemonStatus(conn, cfg)
+		return statusUpdatedMsg{status: status, detail: detail, banner: banner, err: err}
 	}
 }
 
 // fetchAllClientsCmd makes the gRPC call to get all client names.
-func fetchAllClientsCmd(cfg *config.Config) tea.Cmd {
+func fetchAllClientsCmd(conn *adminConn, cfg *config.Config) tea.Cmd {
 	return func() tea.Msg {
-		conn, err := getAdminClientConn(cfg)
+		client, err := conn.client(cfg)
 		if err != nil {
 			return allClientsLoadedMsg{err: err}
 		}
-		defer conn.Close()
-
-		client := pb.NewGaiaAdminClient(conn)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -177,24 +333,51 @@ func fetchAllClientsCmd(cfg *config.Config) tea.Cmd {
 	}
 }
 
-// fetchSecretsForClientCmd makes the gRPC call to get all secrets for a client.
-func fetchSecretsForClientCmd(cfg *config.Config, clientName string) tea.Cmd {
+// secretsStreamTimeout bounds a whole StreamSecrets call, which takes longer
+// than a unary call for clients with many secrets.
+const secretsStreamTimeout = time.Minute
+
+// fetchSecretsForClientCmd streams all secrets of a client, one namespace per
+// message, so the inspector fills in as they arrive. gen tags the messages of
+// this load.
+func fetchSecretsForClientCmd(conn *adminConn, cfg *config.Config, clientName string, gen int) tea.Cmd {
 	return func() tea.Msg {
-		conn, err := getAdminClientConn(cfg)
+		client, err := conn.client(cfg)
 		if err != nil {
-			return secretsForClientLoadedMsg{clientName: clientName, err: err}
+			return secretsForClientLoadedMsg{clientName: clientName, gen: gen, err: err}
 		}
-		defer conn.Close()
 
-		client := pb.NewGaiaAdminClient(conn)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+		ctx, cancel := context.WithTimeout(context.Background(), secretsStreamTimeout)
+		stream, err := client.StreamSecrets(ctx, &pb.ListSecretsRequest{ClientName: clientName})
+		if err != nil {
+			cancel()
+			return secretsForClientLoadedMsg{clientName: clientName, gen: gen, err: err}
+		}
+		return receiveSecretsCmd(clientName, gen, stream, cancel, true)()
+	}
+}
 
-		res, err := client.ListSecrets(ctx, &pb.ListSecretsRequest{ClientName: clientName})
+// receiveSecretsCmd receives the next namespace of a StreamSecrets call. cancel
+// releases the stream once it ends.
+func receiveSecretsCmd(clientName string, gen int, stream pb.GaiaAdmin_StreamSecretsClient, cancel context.CancelFunc, first bool) tea.Cmd {
+	return func() tea.Msg {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			cancel()
+			return secretsForClientLoadedMsg{clientName: clientName, gen: gen, first: first, done: true}
+		}
 		if err != nil {
-			return secretsForClientLoadedMsg{clientName: clientName, err: err}
+			cancel()
+			return secretsForClientLoadedMsg{clientName: clientName, gen: gen, err: err}
+		}
+		return secretsForClientLoadedMsg{
+			clientName: clientName,
+			gen:        gen,
+			namespaces: []*pb.Namespace{res.Namespace},
+			first:      first,
+			total:      int(res.TotalNamespaces),
+			next:       receiveSecretsCmd(clientName, gen, stream, cancel, false),
+			cancel:     cancel,
 		}
-		return secretsForClientLoadedMsg{clientName: clientName, namespaces: res.Namespaces}
 	}
 }
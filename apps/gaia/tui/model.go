@@ -1,9 +1,10 @@
 package tui
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/list"
-	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/stain-win/gaia/apps/gaia/config"
 )
@@ -18,6 +19,7 @@ const (
 	createCerts
 	registerClient
 	listRecords // New screen
+	changePassphrase
 )
 
 // A custom item type for our list.
@@ -38,22 +40,24 @@ type model struct {
 	certMenu                list.Model
 	addRecordFormModel      *addRecordFormModel
 	registerClientFormModel *registerClientFormModel
+	registerReturnScreen    screen // the screen the register client form returns to
+	changePassphraseForm    *changePassphraseFormModel
 	quitting                bool
-	certForm                *huh.Form
-	caName                  string
-	serverName              string
-	clientName              string
-	outputPath              string
+	createCertsForm         *createCertsFormModel
 	help                    help.Model
 	width                   int
 	height                  int
 	clients                 []string
 	namespaces              []string
 	daemonStatus            string
+	daemonBanner            string // banner of the daemon, shown under the logo
 	config                  *config.Config
+	conn                    *adminConn
 	//listRecords             listRecordsModel // New model state
 	inspector     *inspectorModel
 	statusMessage string
+	configPath    string    // config file polled for changes, empty when not watched
+	configModTime time.Time // modification time of configPath at the last load
 }
 
 // menuItems defines the items for the main menu.
@@ -75,6 +79,7 @@ var certMenuItems = []list.Item{
 	menuItem{"Create New Certificates", "Generate a new set of mTLS certificates"},
 	menuItem{"Register Client", "Register a new client for namespacing and mTLS"},
 	menuItem{"List Existing Certificates", "View all certificates known to Gaia"},
+	menuItem{"Change Master Passphrase", "Rekey the database with a new passphrase"},
 	menuItem{"Back", "Return to the main menu (b)"},
 }
 
@@ -105,6 +110,7 @@ func initialModel(config *config.Config) *model {
 	certList.SetShowStatusBar(false)
 	certList.SetFilteringEnabled(false)
 
+	conn := &adminConn{}
 	m := model{
 		activeScreen:            mainMenu,
 		mainMenu:                mainList,
@@ -113,19 +119,14 @@ func initialModel(config *config.Config) *model {
 		help:                    help.New(),
 		addRecordFormModel:      newAddRecordFormModel(nil, nil),
 		registerClientFormModel: newRegisterClientFormModel(),
+		registerReturnScreen:    certManagement,
+		changePassphraseForm:    newChangePassphraseFormModel(""),
+		createCertsForm:         newCreateCertsFormModel(config, ""),
 		daemonStatus:            "",
 		config:                  config,
-		inspector:               newInspectorModel(config),
+		conn:                    conn,
+		inspector:               newInspectorModel(config, conn),
 	}
 
-	m.certForm = huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().Key("caName").Title("CA Common Name").Value(&m.caName),
-			huh.NewInput().Key("serverName").Title("Server Common Name").Value(&m.serverName),
-			huh.NewInput().Key("clientName").Title("Client Common Name").Value(&m.clientName),
-			huh.NewInput().Key("outputPath").Title("Output Directory").Placeholder("./certs").Value(&m.outputPath),
-		),
-	)
-
 	return &m
 }
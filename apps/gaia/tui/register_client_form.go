@@ -11,6 +11,8 @@ import (
 // RegisterClientMsg is a message that signals the main TUI that a new client needs to be registered.
 type RegisterClientMsg struct {
 	ClientName string
+	// EmitConfig also writes the client's connection file.
+	EmitConfig bool
 }
 
 // registerClientFormModel represents the state of the form for registering a new client.
@@ -22,6 +24,7 @@ type registerClientFormModel struct {
 
 func newRegisterClientFormModel() *registerClientFormModel {
 	var clientName string
+	var emitConfig bool
 
 	form := huh.NewForm(
 		huh.NewGroup(
@@ -31,6 +34,11 @@ func newRegisterClientFormModel() *registerClientFormModel {
 				Prompt(lipgloss.NewStyle().Foreground(lipgloss.Color("#00BFFF")).Render(">")).
 				Placeholder("e.g., 'web-app-A'").
 				Value(&clientName),
+			huh.NewConfirm().
+				Key("emitConfig").
+				Title("Write client config file?").
+				Description("Address, server name and certificate paths for the Go client").
+				Value(&emitConfig),
 		),
 	).WithWidth(40)
 
@@ -54,6 +62,7 @@ func (m *registerClientFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, func() tea.Msg {
 			return RegisterClientMsg{
 				ClientName: strings.TrimSpace(m.form.GetString("clientName")),
+				EmitConfig: m.form.GetBool("emitConfig"),
 			}
 		}
 	}
@@ -30,6 +30,14 @@ var (
 			MarginRight(1).
 			Border(lipgloss.RoundedBorder(), false, true, false, false)
 
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF5F5F")) // Light Red
+
+	bannerStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FF8C00")). // Orange
+			MarginBottom(1)
+
 	statusBarStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.AdaptiveColor{Light: "#343433", Dark: "#C1C6B2"}).
 			Background(lipgloss.AdaptiveColor{Light: "#D9DCCF", Dark: "#353533"})
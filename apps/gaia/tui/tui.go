@@ -22,9 +22,14 @@ const gaiaLogo = `
 
 `
 
-// Run initializes and runs the TUI application.
-func Run(cfg *config.Config) error {
-	p := tea.NewProgram(initialModel(cfg), tea.WithAltScreen())
+// Run initializes and runs the TUI application. When configPath is not empty, the
+// file is watched and changes to the tick interval and daemon address are
+// applied without restarting.
+func Run(cfg *config.Config, configPath string) error {
+	m := initialModel(cfg)
+	m.watchConfig(configPath)
+	defer m.conn.Close()
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
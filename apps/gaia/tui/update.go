@@ -2,32 +2,57 @@ package tui
 
 import (
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/stain-win/gaia/apps/gaia/certs"
 	"github.com/stain-win/gaia/apps/gaia/daemon"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (m *model) Init() tea.Cmd {
 	return tea.Batch(
-		checkStatusCmd(m.config),
-		tea.Tick(m.config.GaiaTuiTickInterval, func(t time.Time) tea.Msg {
-			return t
-		}),
+		checkStatusCmd(m.conn, m.config),
+		m.tickCmd(),
 	)
 }
 
+// tickCmd schedules the next status refresh with the current tick interval.
+func (m *model) tickCmd() tea.Cmd {
+	return tea.Tick(m.config.GaiaTuiTickInterval, func(t time.Time) tea.Msg {
+		return t
+	})
+}
+
 // Update is called when a message is received.
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Global handling for messages that apply to all screens
 	switch msg := msg.(type) {
 	case time.Time:
-		return m, checkStatusCmd(m.config)
+		return m, tea.Batch(checkStatusCmd(m.conn, m.config), checkConfigCmd(m.configPath, m.configModTime), m.tickCmd())
+	case configReloadedMsg:
+		m.configModTime = msg.modTime
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Config reload failed: %v", msg.err)
+			return m, nil
+		}
+		var changed []string
+		m.config, changed = applyConfigReload(m.config, msg.cfg)
+		m.inspector.config = m.config
+		if len(changed) == 0 {
+			m.statusMessage = "Config reloaded, nothing to apply."
+			return m, clearStatusCmd(m.statusMessage, reloadNoticeTimeout)
+		}
+		m.statusMessage = fmt.Sprintf("Config reloaded: %s updated.", strings.Join(changed, ", "))
+		return m, tea.Batch(checkStatusCmd(m.conn, m.config), clearStatusCmd(m.statusMessage, reloadNoticeTimeout))
+	case clearStatusMsg:
+		if m.statusMessage == msg.message {
+			m.statusMessage = ""
+		}
+		return m, nil
 	case tea.WindowSizeMsg:
 		h, v := lipgloss.NewStyle().Margin(8, 2).GetFrameSize()
 		m.mainMenu.SetSize(msg.Width-h, min(len(m.mainMenu.Items())*5, msg.Height-v))
@@ -44,17 +69,35 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, keys.Quit):
+		case key.Matches(msg, keys.Quit) && !(m.onTextForm() && msg.String() == "q"):
+			// A passphrase or name may contain a q, so forms only quit on ctrl+c.
 			m.quitting = true
 			return m, tea.Quit
 		}
 	case statusUpdatedMsg:
-		if msg.err != nil {
-			m.daemonStatus = fmt.Sprintf("%s - %s", msg.status, "could not connect to daemon")
-		} else {
-			m.daemonStatus = msg.status
+		m.daemonStatus = describeState(msg.status, msg.detail, msg.err)
+		if msg.err == nil {
+			m.daemonBanner = msg.banner
+		}
+		return m, nil
+	case clientRegisteredMsg:
+		switch {
+		case msg.err != nil:
+			m.statusMessage = fmt.Sprintf("Error registering client: %v", msg.err)
+		case msg.configPath != "":
+			m.statusMessage = fmt.Sprintf("Client %s registered: %s, %s", msg.clientName, msg.certPath, msg.configPath)
+		default:
+			m.statusMessage = fmt.Sprintf("Client %s registered: %s", msg.clientName, msg.certPath)
+		}
+		if msg.err == nil && m.activeScreen == listRecords {
+			// Show the new client in the inspector.
+			return m, m.inspector.Init()
 		}
 		return m, nil
+	case openRegisterClientMsg:
+		m.registerReturnScreen = listRecords
+		m.activeScreen = registerClient
+		return m, m.registerClientFormModel.Init()
 	case backToDataManagementMsg:
 		m.activeScreen = dataManagement
 		return m, nil
@@ -74,6 +117,8 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateCreateCerts(msg)
 	case registerClient:
 		return m.updateRegisterClient(msg)
+	case changePassphrase:
+		return m.updateChangePassphrase(msg)
 	case listRecords:
 		var cmd tea.Cmd
 		m.inspector, cmd = m.inspector.Update(msg)
@@ -117,7 +162,7 @@ func (m *model) updateDataManagement(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "Add New Record":
 				m.statusMessage = "Loading clients..."
 				// Fire the command to fetch the list of clients from the daemon.
-				return m, fetchClientsCmd(m.config)
+				return m, fetchClientsCmd(m.conn, m.config)
 
 			case "List All Records":
 				m.activeScreen = listRecords
@@ -159,7 +204,7 @@ func (m *model) updateAddRecord(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case AddRecordMsg:
 		m.statusMessage = "Adding record..."
 		// Fire the command to add the secret via gRPC.
-		return m, addRecordToDaemonCmd(m.config, msg.ClientName, msg.Namespace, msg.Key, msg.Value)
+		return m, addRecordToDaemonCmd(m.conn, m.config, msg.ClientName, msg.Namespace, msg.Key, msg.Value)
 	// This new case handles the result of the addRecordCmd.
 	case recordAddedMsg:
 		if msg.err != nil {
@@ -194,13 +239,19 @@ func (m *model) updateCertManagement(msg tea.Msg) (tea.Model, tea.Cmd) {
 		selected := m.certMenu.SelectedItem().(menuItem)
 		switch selected.title {
 		case "Create New Certificates":
+			m.createCertsForm = newCreateCertsFormModel(m.config, "")
 			m.activeScreen = createCerts
-			return m, m.certForm.Init()
+			return m, m.createCertsForm.Init()
 		case "Register Client":
+			m.registerReturnScreen = certManagement
 			m.activeScreen = registerClient
 			return m, m.registerClientFormModel.Init()
 		case "List Existing Certificates":
 			// TODO: Implement list functionality
+		case "Change Master Passphrase":
+			m.changePassphraseForm = newChangePassphraseFormModel("")
+			m.activeScreen = changePassphrase
+			return m, m.changePassphraseForm.Init()
 		case "Back":
 			m.activeScreen = mainMenu
 		}
@@ -210,54 +261,89 @@ func (m *model) updateCertManagement(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 // updateCreateCerts handles updates for the 'Create Certificates' form screen.
+// Generation runs in the background and its steps are shown as they complete.
 func (m *model) updateCreateCerts(msg tea.Msg) (tea.Model, tea.Cmd) {
-	updatedForm, cmd := m.certForm.Update(msg)
-	m.certForm = updatedForm.(*huh.Form)
-
-	if m.certForm.State == huh.StateCompleted {
-		outPath := m.certForm.GetString("outputPath")
-		caName := m.certForm.GetString("caName")
-		serverName := m.certForm.GetString("serverName")
-		clientName := m.certForm.GetString("clientName")
-
-		cfg := *m.config
-		cfg.CertsDirectory = outPath
-
-		var err error
-		if err = certs.GenerateCA(&cfg, caName); err != nil {
-			err = fmt.Errorf("generating CA failed: %w", err)
-		} else if err = certs.GenerateServerCertificate(&cfg, serverName); err != nil {
-			err = fmt.Errorf("generating server certificate failed: %w", err)
-		} else if err = certs.GenerateClientCertificate(&cfg, clientName); err != nil {
-			err = fmt.Errorf("generating client certificate failed: %w", err)
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.Back) && !m.createCertsForm.pending {
+			m.activeScreen = certManagement
+			return m, nil
 		}
-
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Error generating certificates: %v\n", err)
-		} else {
-			fmt.Println("Certificates generated successfully!")
+	case CreateCertsMsg:
+		m.statusMessage = "Generating certificates..."
+		return m, generateCertsCmd(m.config, msg)
+	case certStepMsg:
+		m.createCertsForm.stepDone(msg.step)
+		return m, waitForCertStep(msg.ch)
+	case certsGeneratedMsg:
+		if msg.err != nil {
+			m.statusMessage = ""
+			m.createCertsForm = newCreateCertsFormModel(m.config, fmt.Sprintf("Certificate generation failed: %v", msg.err))
+			return m, m.createCertsForm.Init()
 		}
-
+		m.statusMessage = fmt.Sprintf("Certificates generated in %s.", msg.dir)
 		m.activeScreen = certManagement
-		return m, tea.ClearScreen
+		return m, nil
 	}
+
+	updatedModel, cmd := m.createCertsForm.Update(msg)
+	m.createCertsForm = updatedModel.(*createCertsFormModel)
 	return m, cmd
 }
 
+// onTextForm reports whether the active screen is a form with text inputs.
+func (m *model) onTextForm() bool {
+	return m.activeScreen == changePassphrase || m.activeScreen == createCerts
+}
+
 // updateRegisterClient handles updates for the 'Register Client' form screen.
 func (m *model) updateRegisterClient(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if _, ok := msg.(BackMsg); ok {
-		m.activeScreen = certManagement
+		m.activeScreen = m.registerReturnScreen
 		return m, nil
 	}
 	if regMsg, ok := msg.(RegisterClientMsg); ok {
-		// TODO: Call gRPC client to register the client.
-		fmt.Printf("Received RegisterClientMsg: ClientName=%s\n", regMsg.ClientName)
-		m.activeScreen = certManagement
-		return m, nil
+		m.statusMessage = fmt.Sprintf("Registering client %s...", regMsg.ClientName)
+		m.registerClientFormModel = newRegisterClientFormModel()
+		m.activeScreen = m.registerReturnScreen
+		return m, registerClientCmd(m.conn, m.config, regMsg.ClientName, regMsg.EmitConfig)
 	}
 
 	updatedModel, cmd := m.registerClientFormModel.Update(msg)
 	m.registerClientFormModel = updatedModel.(*registerClientFormModel)
 	return m, cmd
 }
+
+// updateChangePassphrase handles updates for the 'Change Master Passphrase' form
+// screen. A rejected current passphrase is shown on the form, so the operator
+// can try again without leaving the screen.
+func (m *model) updateChangePassphrase(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.Back) && !m.changePassphraseForm.pending {
+			m.activeScreen = certManagement
+			return m, nil
+		}
+	case ChangePassphraseMsg:
+		m.statusMessage = "Changing master passphrase..."
+		return m, changePassphraseCmd(m.conn, m.config, msg.OldPassphrase, msg.NewPassphrase)
+	case passphraseChangedMsg:
+		if msg.err != nil {
+			errMsg := fmt.Sprintf("Passphrase change failed: %v", status.Convert(msg.err).Message())
+			if status.Code(msg.err) == codes.Unauthenticated {
+				errMsg = "The current passphrase is incorrect, nothing was changed."
+			}
+			m.statusMessage = ""
+			m.changePassphraseForm = newChangePassphraseFormModel(errMsg)
+			return m, m.changePassphraseForm.Init()
+		}
+		m.statusMessage = fmt.Sprintf("Master passphrase changed, %d secrets re-encrypted.", msg.secrets)
+		m.changePassphraseForm = newChangePassphraseFormModel("")
+		m.activeScreen = certManagement
+		return m, nil
+	}
+
+	updatedModel, cmd := m.changePassphraseForm.Update(msg)
+	m.changePassphraseForm = updatedModel.(*changePassphraseFormModel)
+	return m, cmd
+}
@@ -5,9 +5,13 @@ import (
 )
 
 func (m *model) statusView() string {
+	status := m.daemonStatus
+	if m.statusMessage != "" {
+		status += " · " + m.statusMessage
+	}
 	return statusBarStyle.
 		Align(lipgloss.Center).
-		Render(m.daemonStatus)
+		Render(status)
 }
 
 func (m *model) View() string {
@@ -15,6 +19,9 @@ func (m *model) View() string {
 		Foreground(lipgloss.Color("#6A5ACD")).
 		Align(lipgloss.Center).
 		Render(gaiaLogo)
+	if m.daemonBanner != "" {
+		logo = lipgloss.JoinVertical(lipgloss.Center, logo, bannerStyle.Render(m.daemonBanner))
+	}
 
 	var screenView string
 	switch m.activeScreen {
@@ -27,14 +34,16 @@ func (m *model) View() string {
 	case certManagement:
 		screenView = lipgloss.JoinVertical(lipgloss.Center, logo, m.certMenu.View())
 	case createCerts:
-		screenView = lipgloss.JoinVertical(lipgloss.Center, logo, m.certForm.View())
+		screenView = lipgloss.JoinVertical(lipgloss.Center, logo, m.createCertsForm.View())
 	case registerClient:
 		screenView = lipgloss.JoinVertical(lipgloss.Center, logo, m.registerClientFormModel.View())
+	case changePassphrase:
+		screenView = lipgloss.JoinVertical(lipgloss.Center, logo, m.changePassphraseForm.View())
 	case listRecords:
 		screenView = m.inspector.View()
 	}
 
-	content := lipgloss.JoinVertical(lipgloss.Left, screenView)
+	content := lipgloss.JoinVertical(lipgloss.Center, screenView, m.statusView())
 	return lipgloss.Place(
 		m.width,
 		m.height,
@@ -4,22 +4,30 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	pb "github.com/stain-win/gaia/libs/go/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	// Registers gzip, so responses the daemon compresses are accepted.
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // Client is a high-level Gaia client for interacting with the Gaia daemon.
 type Client struct {
-	conn   *grpc.ClientConn
-	client pb.GaiaClientClient
+	conn     *grpc.ClientConn
+	client   pb.GaiaClientClient
+	fallback *fallbackCache // nil unless FallbackCacheFile is set
 }
 
 // Config holds the configuration required to connect to the Gaia daemon.
@@ -32,10 +40,30 @@ type Config struct {
 	ClientCertFile string
 	// ClientKeyFile is the path to the client's private key file.
 	ClientKeyFile string
+	// CertsDir and ClientName locate the certificate files when the paths above
+	// are empty: <CertsDir>/<ClientName>.crt, <ClientName>.key and ca.crt, the
+	// layout written by 'gaia certs'.
+	CertsDir   string
+	ClientName string
+	// ServerName is the name the daemon certificate is verified against. It
+	// defaults to the host of Address; set it when connecting by an IP address
+	// the certificate does not list.
+	ServerName string
+	// MinTLSVersion is the lowest TLS version accepted for the connection,
+	// tls.VersionTLS12 or tls.VersionTLS13. It defaults to TLS 1.2.
+	MinTLSVersion uint16
 	// Timeout is the timeout for the initial connection.
 	Timeout time.Duration
 	// Insecure allows connecting without TLS. For development only.
 	Insecure bool
+	// FallbackCacheFile enables the offline fallback, which is off by default.
+	// Common secrets fetched from the daemon are saved to this file, encrypted
+	// with FallbackCacheKey, and GetCommonSecrets and LoadEnv serve them from it
+	// when the daemon cannot be reached, flagging the result with ErrStale.
+	FallbackCacheFile string
+	// FallbackCacheKey is the FallbackKeySize-byte AES-256 key of the cache file.
+	// Keep it outside the file's directory, e.g. in the app's own secret store.
+	FallbackCacheKey []byte
 }
 
 // NewClient creates a new Gaia client. It handles loading TLS credentials
@@ -43,12 +71,34 @@ type Config struct {
 func NewClient(cfg Config) (*Client, error) {
 	var opts []grpc.DialOption
 
+	var fallback *fallbackCache
+	if cfg.FallbackCacheFile != "" {
+		var err error
+		if fallback, err = newFallbackCache(cfg.FallbackCacheFile, cfg.FallbackCacheKey); err != nil {
+			return nil, err
+		}
+	}
+
 	if cfg.Insecure {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	} else {
+		if cfg.ClientCertFile == "" && cfg.ClientKeyFile == "" && cfg.CACertFile == "" && cfg.CertsDir != "" {
+			var err error
+			cfg.CACertFile, cfg.ClientCertFile, cfg.ClientKeyFile, err = ResolveCertPaths(cfg.CertsDir, cfg.ClientName)
+			if err != nil {
+				return nil, err
+			}
+		}
 		if cfg.ClientCertFile == "" || cfg.ClientKeyFile == "" || cfg.CACertFile == "" {
 			return nil, fmt.Errorf("for secure connections, ca_cert, client_cert, and client_key paths are required")
 		}
+		minVersion := cfg.MinTLSVersion
+		if minVersion == 0 {
+			minVersion = tls.VersionTLS12
+		}
+		if minVersion != tls.VersionTLS12 && minVersion != tls.VersionTLS13 {
+			return nil, fmt.Errorf("unsupported minimum TLS version %#04x, want TLS 1.2 or TLS 1.3", minVersion)
+		}
 		// Load client TLS certificates
 		clientCert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
 		if err != nil {
@@ -67,6 +117,8 @@ func NewClient(cfg Config) (*Client, error) {
 		}
 
 		creds := credentials.NewTLS(&tls.Config{
+			ServerName:   cfg.ServerName,
+			MinVersion:   minVersion,
 			Certificates: []tls.Certificate{clientCert},
 			RootCAs:      caCertPool,
 		})
@@ -87,8 +139,68 @@ func NewClient(cfg Config) (*Client, error) {
 	}
 
 	return &Client{
-		conn:   conn,
-		client: pb.NewGaiaClientClient(conn),
+		conn:     conn,
+		client:   pb.NewGaiaClientClient(conn),
+		fallback: fallback,
+	}, nil
+}
+
+// ResolveCertPaths locates <clientName>.crt, <clientName>.key and ca.crt in
+// certsDir and returns their paths.
+func ResolveCertPaths(certsDir, clientName string) (caCertFile, certFile, keyFile string, err error) {
+	if clientName == "" {
+		return "", "", "", fmt.Errorf("a client name is required to locate certificates in '%s'", certsDir)
+	}
+	caCertFile = filepath.Join(certsDir, "ca.crt")
+	certFile = filepath.Join(certsDir, clientName+".crt")
+	keyFile = filepath.Join(certsDir, clientName+".key")
+	for _, path := range []string{certFile, keyFile, caCertFile} {
+		if _, err := os.Stat(path); err != nil {
+			return "", "", "", fmt.Errorf("certificate for client '%s' not found: %w", clientName, err)
+		}
+	}
+	return caCertFile, certFile, keyFile, nil
+}
+
+// configFile is the connection file written by 'gaia clients register
+// --emit-config'.
+type configFile struct {
+	Address        string `json:"address"`
+	ServerName     string `json:"server_name"`
+	CACertFile     string `json:"ca_cert_file"`
+	ClientCertFile string `json:"client_cert_file"`
+	ClientKeyFile  string `json:"client_key_file"`
+}
+
+// LoadConfigFile reads a connection file written by 'gaia clients register
+// --emit-config' into a Config. Relative certificate paths are resolved against
+// the directory of the file, so it can be moved together with the certificates.
+func LoadConfigFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read client config file: %w", err)
+	}
+	var f configFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Config{}, fmt.Errorf("invalid client config file '%s': %w", path, err)
+	}
+	if f.Address == "" || f.CACertFile == "" || f.ClientCertFile == "" || f.ClientKeyFile == "" {
+		return Config{}, fmt.Errorf("client config file '%s' needs address, ca_cert_file, client_cert_file and client_key_file", path)
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+	return Config{
+		Address:        f.Address,
+		ServerName:     f.ServerName,
+		CACertFile:     resolve(f.CACertFile),
+		ClientCertFile: resolve(f.ClientCertFile),
+		ClientKeyFile:  resolve(f.ClientKeyFile),
 	}, nil
 }
 
@@ -112,9 +224,77 @@ func (c *Client) GetSecret(ctx context.Context, namespace, id string) (string, e
 	return resp.Value, nil
 }
 
+// GetOrCreate returns the secret id from namespace, generating it with gen and
+// storing it first when it does not exist yet. Only the client's own namespace
+// can be written to. The daemon creates the secret only if it is still absent,
+// so when several replicas race, all of them receive the value stored by the
+// first one and gen's other results are discarded.
+func (c *Client) GetOrCreate(ctx context.Context, namespace, id string, gen func() string) (string, error) {
+	value, err := c.GetSecret(ctx, namespace, id)
+	if status.Code(err) != codes.NotFound {
+		return value, err
+	}
+
+	resp, err := c.client.CreateSecretIfAbsent(ctx, &pb.CreateSecretIfAbsentRequest{
+		Namespace: namespace,
+		Id:        id,
+		Value:     gen(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Value, nil
+}
+
+// GetOwnSecretsPaged calls fn for every secret the client stores in namespace,
+// fetching pageSize secrets per request (0 uses the daemon's default of 100), so
+// memory stays bounded for namespaces with thousands of secrets. Secrets are
+// visited in id order. Iteration stops at the first error returned by fn, which
+// is returned as is.
+func (c *Client) GetOwnSecretsPaged(ctx context.Context, namespace string, pageSize int, fn func(id, value string) error) error {
+	req := &pb.ListOwnSecretsRequest{Namespace: namespace, PageSize: int32(pageSize)}
+	for {
+		resp, err := c.client.ListOwnSecrets(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, s := range resp.Secrets {
+			if err := fn(s.Id, s.Value); err != nil {
+				return err
+			}
+		}
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// ListIds returns the ids of the secrets the client stores in namespace, in id
+// order, without fetching their values.
+func (c *Client) ListIds(ctx context.Context, namespace string) ([]string, error) {
+	var ids []string
+	req := &pb.ListOwnSecretIdsRequest{Namespace: namespace}
+	for {
+		resp, err := c.client.ListOwnSecretIds(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, resp.Ids...)
+		if resp.NextPageToken == "" {
+			return ids, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
 // GetCommonSecrets fetches secrets from the "common" area.
 // If a namespace is provided, it fetches secrets only for that namespace.
 // If no namespace is provided, it fetches secrets from all namespaces in the common area.
+//
+// With a fallback cache configured, secrets are served from the cache when the
+// daemon cannot be reached, and the returned error is a *StaleError matching
+// ErrStale. Callers that accept stale secrets check for it with errors.Is.
 func (c *Client) GetCommonSecrets(ctx context.Context, namespace ...string) (map[string]map[string]string, error) {
 	req := &pb.GetCommonSecretsRequest{}
 	if len(namespace) > 0 && namespace[0] != "" {
@@ -124,6 +304,9 @@ func (c *Client) GetCommonSecrets(ctx context.Context, namespace ...string) (map
 
 	resp, err := c.client.GetCommonSecrets(ctx, req)
 	if err != nil {
+		if c.fallback != nil && isUnreachable(err) {
+			return c.cachedCommonSecrets(req.GetNamespace(), err)
+		}
 		return nil, err
 	}
 
@@ -134,19 +317,63 @@ func (c *Client) GetCommonSecrets(ctx context.Context, namespace ...string) (map
 			secrets[ns.Name][s.Id] = s.Value
 		}
 	}
+	if c.fallback != nil {
+		// A cache that cannot be written must not fail a successful fetch; the
+		// next successful fetch tries again.
+		_ = c.fallback.store(secrets, req.Namespace == nil)
+	}
 	return secrets, nil
 }
 
-// LoadEnv fetches all secrets from the "common" area and loads them into the
-// current process's environment.
+// cachedCommonSecrets serves the common secrets of namespace, or all of them,
+// from the fallback cache after the daemon call failed with cause. cause is
+// returned unchanged when the cache holds nothing to serve.
+func (c *Client) cachedCommonSecrets(namespace string, cause error) (map[string]map[string]string, error) {
+	data, err := c.fallback.load()
+	if err != nil {
+		return nil, cause
+	}
+	secrets := data.Namespaces
+	if namespace != "" {
+		kv, ok := data.Namespaces[namespace]
+		if !ok {
+			return nil, cause
+		}
+		secrets = map[string]map[string]string{namespace: kv}
+	}
+	return secrets, &StaleError{FetchedAt: data.FetchedAt, Cause: cause}
+}
+
+// LoadEnv fetches secrets from the "common" area and loads them into the
+// current process's environment. Without namespaces, every common namespace is
+// loaded; otherwise only the given ones are.
 //
 // The environment variables are formatted as GAIA_NAMESPACE_KEY.
-func (c *Client) LoadEnv(ctx context.Context) error {
-	secrets, err := c.GetCommonSecrets(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch common secrets: %w", err)
+//
+// When the secrets come from the fallback cache, they are loaded and the
+// *StaleError is returned, so callers can decide whether to keep running.
+func (c *Client) LoadEnv(ctx context.Context, namespaces ...string) error {
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	var staleErr error
+	for _, ns := range namespaces {
+		secrets, err := c.GetCommonSecrets(ctx, ns)
+		if errors.Is(err, ErrStale) {
+			staleErr = err
+		} else if err != nil {
+			return fmt.Errorf("failed to fetch common secrets: %w", err)
+		}
+		if err := setEnv(secrets); err != nil {
+			return err
+		}
 	}
+	return staleErr
+}
 
+// setEnv sets an environment variable for every secret.
+func setEnv(secrets map[string]map[string]string) error {
 	for namespace, kv := range secrets {
 		for key, value := range kv {
 			envVarName := fmt.Sprintf("GAIA_%s_%s", namespace, key)
@@ -1,16 +1,33 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/big"
 	"net"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	pb "github.com/stain-win/gaia/libs/go/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -22,6 +39,9 @@ type mockGaiaClientServer struct {
 	GetStatusFunc                    func(ctx context.Context, in *emptypb.Empty) (*pb.StatusResponse, error)
 	GetNamespacesFunc                func(ctx context.Context, in *emptypb.Empty) (*pb.NamespaceResponse, error)
 	GetCommonSecretsFunc             func(ctx context.Context, in *pb.GetCommonSecretsRequest) (*pb.GetCommonSecretsResponse, error)
+	CreateSecretIfAbsentFunc         func(ctx context.Context, in *pb.CreateSecretIfAbsentRequest) (*pb.CreateSecretIfAbsentResponse, error)
+	ListOwnSecretsFunc               func(ctx context.Context, in *pb.ListOwnSecretsRequest) (*pb.ListOwnSecretsResponse, error)
+	ListOwnSecretIdsFunc             func(ctx context.Context, in *pb.ListOwnSecretIdsRequest) (*pb.ListOwnSecretIdsResponse, error)
 }
 
 func (m *mockGaiaClientServer) GetSecret(ctx context.Context, in *pb.GetSecretRequest) (*pb.Secret, error) {
@@ -40,6 +60,18 @@ func (m *mockGaiaClientServer) GetCommonSecrets(ctx context.Context, in *pb.GetC
 	return m.GetCommonSecretsFunc(ctx, in)
 }
 
+func (m *mockGaiaClientServer) CreateSecretIfAbsent(ctx context.Context, in *pb.CreateSecretIfAbsentRequest) (*pb.CreateSecretIfAbsentResponse, error) {
+	return m.CreateSecretIfAbsentFunc(ctx, in)
+}
+
+func (m *mockGaiaClientServer) ListOwnSecrets(ctx context.Context, in *pb.ListOwnSecretsRequest) (*pb.ListOwnSecretsResponse, error) {
+	return m.ListOwnSecretsFunc(ctx, in)
+}
+
+func (m *mockGaiaClientServer) ListOwnSecretIds(ctx context.Context, in *pb.ListOwnSecretIdsRequest) (*pb.ListOwnSecretIdsResponse, error) {
+	return m.ListOwnSecretIdsFunc(ctx, in)
+}
+
 // startTestServer starts a mock gRPC server for testing purposes.
 func startTestServer(mock pb.GaiaClientServer) (*grpc.ClientConn, func()) {
 	lis := bufconn.Listen(1024 * 1024)
@@ -176,6 +208,33 @@ func TestClient(t *testing.T) {
 		os.Unsetenv("GAIA_NS_ONE_KEY_ONE") // Clean up
 	})
 
+	t.Run("LoadEnvNamespaces", func(t *testing.T) {
+		all := map[string]*pb.Namespace{
+			"ns-one": {Name: "ns-one", Secrets: []*pb.Secret{{Id: "key", Value: "val1"}}},
+			"ns-two": {Name: "ns-two", Secrets: []*pb.Secret{{Id: "key", Value: "val2"}}},
+		}
+		mockServer.GetCommonSecretsFunc = func(ctx context.Context, in *pb.GetCommonSecretsRequest) (*pb.GetCommonSecretsResponse, error) {
+			if in.Namespace == nil {
+				return &pb.GetCommonSecretsResponse{Namespaces: []*pb.Namespace{all["ns-one"], all["ns-two"]}}, nil
+			}
+			return &pb.GetCommonSecretsResponse{Namespaces: []*pb.Namespace{all[in.GetNamespace()]}}, nil
+		}
+		t.Cleanup(func() {
+			os.Unsetenv("GAIA_NS_ONE_KEY")
+			os.Unsetenv("GAIA_NS_TWO_KEY")
+		})
+
+		if err := client.LoadEnv(context.Background(), "ns-two"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if val := os.Getenv("GAIA_NS_TWO_KEY"); val != "val2" {
+			t.Errorf("Expected env var GAIA_NS_TWO_KEY to be 'val2', got '%s'", val)
+		}
+		if val, ok := os.LookupEnv("GAIA_NS_ONE_KEY"); ok {
+			t.Errorf("Expected GAIA_NS_ONE_KEY of an unrequested namespace to be unset, got '%s'", val)
+		}
+	})
+
 	t.Run("GetStatus", func(t *testing.T) {
 		mockServer.GetStatusFunc = func(ctx context.Context, in *emptypb.Empty) (*pb.StatusResponse, error) {
 			return &pb.StatusResponse{Status: "running"}, nil
@@ -205,3 +264,418 @@ func TestClient(t *testing.T) {
 		}
 	})
 }
+
+func TestGetOrCreate_Concurrent(t *testing.T) {
+	// The mock stores secrets like the daemon: the existence check and the write
+	// happen atomically, so only the first create wins.
+	var mu sync.Mutex
+	stored := map[string]string{}
+	creates := 0
+	mockServer := &mockGaiaClientServer{
+		GetSecretFunc: func(ctx context.Context, in *pb.GetSecretRequest) (*pb.Secret, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			v, ok := stored[in.Namespace+"/"+in.Id]
+			if !ok {
+				return nil, status.Error(codes.NotFound, "secret not found")
+			}
+			return &pb.Secret{Id: in.Id, Value: v}, nil
+		},
+		CreateSecretIfAbsentFunc: func(ctx context.Context, in *pb.CreateSecretIfAbsentRequest) (*pb.CreateSecretIfAbsentResponse, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if v, ok := stored[in.Namespace+"/"+in.Id]; ok {
+				return &pb.CreateSecretIfAbsentResponse{Value: v}, nil
+			}
+			stored[in.Namespace+"/"+in.Id] = in.Value
+			creates++
+			return &pb.CreateSecretIfAbsentResponse{Value: in.Value, Created: true}, nil
+		},
+	}
+	conn, cleanup := startTestServer(mockServer)
+	defer cleanup()
+	client := &Client{conn: conn, client: pb.NewGaiaClientClient(conn)}
+
+	// Both callers pass the existence check before either creates the secret.
+	var checked sync.WaitGroup
+	checked.Add(2)
+	results := make([]string, 2)
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = client.GetOrCreate(context.Background(), "billing", "session_key", func() string {
+				checked.Done()
+				checked.Wait()
+				return fmt.Sprintf("generated-%d", i)
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("caller %d: Expected no error, got %v", i, err)
+		}
+	}
+	if results[0] != results[1] {
+		t.Errorf("Expected both callers to get the same value, got %q and %q", results[0], results[1])
+	}
+	if creates != 1 {
+		t.Errorf("Expected the secret to be created once, got %d", creates)
+	}
+
+	// Once stored, the secret is returned without generating a new one.
+	value, err := client.GetOrCreate(context.Background(), "billing", "session_key", func() string {
+		t.Error("Expected gen not to be called for an existing secret")
+		return ""
+	})
+	if err != nil || value != results[0] {
+		t.Errorf("Expected %q, got %q, %v", results[0], value, err)
+	}
+}
+
+func TestGetOwnSecretsPaged(t *testing.T) {
+	ids := make([]string, 25)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("key_%02d", i)
+	}
+	requests := 0
+	mockServer := &mockGaiaClientServer{
+		ListOwnSecretsFunc: func(ctx context.Context, in *pb.ListOwnSecretsRequest) (*pb.ListOwnSecretsResponse, error) {
+			requests++
+			if in.Namespace != "billing" || in.PageSize != 10 {
+				return nil, status.Errorf(codes.InvalidArgument, "unexpected request %v", in)
+			}
+			// The mock's page token is the index of the next secret.
+			start := 0
+			if in.PageToken != "" {
+				fmt.Sscan(in.PageToken, &start)
+			}
+			end := min(start+int(in.PageSize), len(ids))
+			resp := &pb.ListOwnSecretsResponse{}
+			for _, id := range ids[start:end] {
+				resp.Secrets = append(resp.Secrets, &pb.Secret{Id: id, Value: "value-" + id})
+			}
+			if end < len(ids) {
+				resp.NextPageToken = fmt.Sprint(end)
+			}
+			return resp, nil
+		},
+	}
+	conn, cleanup := startTestServer(mockServer)
+	defer cleanup()
+	client := &Client{conn: conn, client: pb.NewGaiaClientClient(conn)}
+
+	seen := map[string]int{}
+	err := client.GetOwnSecretsPaged(context.Background(), "billing", 10, func(id, value string) error {
+		seen[id]++
+		if value != "value-"+id {
+			t.Errorf("Expected value %q for %s, got %q", "value-"+id, id, value)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 page requests, got %d", requests)
+	}
+	if len(seen) != len(ids) {
+		t.Errorf("Expected %d secrets, got %d", len(ids), len(seen))
+	}
+	for _, id := range ids {
+		if seen[id] != 1 {
+			t.Errorf("Expected %s exactly once, got %d times", id, seen[id])
+		}
+	}
+
+	// An error from the callback stops the iteration.
+	stop := fmt.Errorf("stop")
+	calls := 0
+	err = client.GetOwnSecretsPaged(context.Background(), "billing", 10, func(id, value string) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("Expected the callback error after one call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestResolveCertPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"ca.crt", "billing.crt", "billing.key"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("pem"), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	ca, cert, key, err := ResolveCertPaths(dir, "billing")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ca != filepath.Join(dir, "ca.crt") || cert != filepath.Join(dir, "billing.crt") || key != filepath.Join(dir, "billing.key") {
+		t.Errorf("Unexpected paths: %s, %s, %s", ca, cert, key)
+	}
+
+	if _, _, _, err := ResolveCertPaths(dir, "orders"); err == nil {
+		t.Error("Expected an error for a client without certificates, got nil")
+	}
+	if _, _, _, err := ResolveCertPaths(dir, ""); err == nil {
+		t.Error("Expected an error without a client name, got nil")
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	// The file as written by 'gaia clients register --emit-config', with the CA
+	// given relative to the file.
+	path := filepath.Join(dir, "billing.json")
+	data := `{
+  "address": "gaia.internal:50051",
+  "server_name": "gaia.internal",
+  "ca_cert_file": "ca.crt",
+  "client_cert_file": "/etc/gaia/certs/billing.crt",
+  "client_key_file": "/etc/gaia/certs/billing.key"
+}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := Config{
+		Address:        "gaia.internal:50051",
+		ServerName:     "gaia.internal",
+		CACertFile:     filepath.Join(dir, "ca.crt"),
+		ClientCertFile: "/etc/gaia/certs/billing.crt",
+		ClientKeyFile:  "/etc/gaia/certs/billing.key",
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"address": "gaia.internal:50051"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if _, err := LoadConfigFile(path); err == nil {
+		t.Error("Expected an error for a config file without certificate paths, got nil")
+	}
+}
+
+func TestGetCommonSecrets_Fallback(t *testing.T) {
+	mockServer := &mockGaiaClientServer{
+		GetCommonSecretsFunc: func(ctx context.Context, in *pb.GetCommonSecretsRequest) (*pb.GetCommonSecretsResponse, error) {
+			return &pb.GetCommonSecretsResponse{Namespaces: []*pb.Namespace{
+				{Name: "shared", Secrets: []*pb.Secret{{Id: "smtp_host", Value: "mail.internal"}}},
+			}}, nil
+		},
+	}
+	conn, cleanup := startTestServer(mockServer)
+	defer cleanup()
+
+	path := filepath.Join(t.TempDir(), "fallback.cache")
+	key := make([]byte, FallbackKeySize)
+	cache, err := newFallbackCache(path, key)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	online := &Client{conn: conn, client: pb.NewGaiaClientClient(conn), fallback: cache}
+	if _, err := online.GetCommonSecrets(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the cache file to be written, got %v", err)
+	}
+	if bytes.Contains(raw, []byte("mail.internal")) {
+		t.Error("Cache file contains a plaintext secret")
+	}
+
+	// A client whose daemon is down is served from the cache.
+	down, err := grpc.NewClient("passthrough:///127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer down.Close()
+	offline := &Client{conn: down, client: pb.NewGaiaClientClient(down), fallback: cache}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	secrets, err := offline.GetCommonSecrets(ctx, "shared")
+	if !errors.Is(err, ErrStale) {
+		t.Fatalf("Expected ErrStale, got %v", err)
+	}
+	if secrets["shared"]["smtp_host"] != "mail.internal" {
+		t.Errorf("Expected the cached secret, got %v", secrets)
+	}
+	if _, err := offline.GetCommonSecrets(ctx, "unknown"); err == nil || errors.Is(err, ErrStale) {
+		t.Errorf("Expected the daemon error for an uncached namespace, got %v", err)
+	}
+
+	// A cache read with the wrong key is ignored.
+	wrongKey := make([]byte, FallbackKeySize)
+	wrongKey[0] = 1
+	other, err := newFallbackCache(path, wrongKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	offline.fallback = other
+	if _, err := offline.GetCommonSecrets(ctx); err == nil || errors.Is(err, ErrStale) {
+		t.Errorf("Expected the daemon error with the wrong cache key, got %v", err)
+	}
+}
+
+func TestListIds(t *testing.T) {
+	mockServer := &mockGaiaClientServer{
+		ListOwnSecretIdsFunc: func(ctx context.Context, in *pb.ListOwnSecretIdsRequest) (*pb.ListOwnSecretIdsResponse, error) {
+			if in.Namespace != "billing" {
+				return nil, status.Errorf(codes.PermissionDenied, "not authorized for namespace %q", in.Namespace)
+			}
+			if in.PageToken == "" {
+				return &pb.ListOwnSecretIdsResponse{Ids: []string{"api_key", "db_password"}, NextPageToken: "2"}, nil
+			}
+			return &pb.ListOwnSecretIdsResponse{Ids: []string{"webhook_secret"}}, nil
+		},
+	}
+	conn, cleanup := startTestServer(mockServer)
+	defer cleanup()
+	client := &Client{conn: conn, client: pb.NewGaiaClientClient(conn)}
+
+	ids, err := client.ListIds(context.Background(), "billing")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := []string{"api_key", "db_password", "webhook_secret"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expected ids %v, got %v", want, ids)
+	}
+
+	if _, err := client.ListIds(context.Background(), "payments"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for another namespace, got %v", err)
+	}
+}
+
+// writeTestPKI writes a CA, a daemon certificate for dnsName only and a client
+// certificate to dir, and returns the daemon's key pair and the CA pool.
+func writeTestPKI(t *testing.T, dir, dnsName string) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		return key
+	}
+	writePEM := func(name, blockType string, der []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	caKey := newKey()
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Gaia Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+	writePEM("ca.crt", "CERTIFICATE", caDER)
+
+	issue := func(serial int64, cn string, usage x509.ExtKeyUsage, dnsNames []string) ([]byte, *ecdsa.PrivateKey) {
+		key := newKey()
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: cn},
+			DNSNames:     dnsNames,
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("Failed to create certificate for %s: %v", cn, err)
+		}
+		return der, key
+	}
+
+	clientDER, clientKey := issue(2, "billing", x509.ExtKeyUsageClientAuth, nil)
+	writePEM("billing.crt", "CERTIFICATE", clientDER)
+	clientKeyDER, _ := x509.MarshalECPrivateKey(clientKey)
+	writePEM("billing.key", "EC PRIVATE KEY", clientKeyDER)
+
+	serverDER, serverKey := issue(3, dnsName, x509.ExtKeyUsageServerAuth, []string{dnsName})
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey}, pool
+}
+
+func TestNewClient_ServerNameAndMinTLSVersion(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, pool := writeTestPKI(t, dir, "gaia.internal")
+
+	// The daemon listens on an IP address its certificate does not list and
+	// speaks TLS 1.2 at most.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MaxVersion:   tls.VersionTLS12,
+	})))
+	pb.RegisterGaiaClientServer(s, &mockGaiaClientServer{
+		GetStatusFunc: func(ctx context.Context, in *emptypb.Empty) (*pb.StatusResponse, error) {
+			return &pb.StatusResponse{Status: "running"}, nil
+		},
+	})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	newClient := func(serverName string, minVersion uint16) (*Client, error) {
+		return NewClient(Config{
+			Address:       lis.Addr().String(),
+			CertsDir:      dir,
+			ClientName:    "billing",
+			ServerName:    serverName,
+			MinTLSVersion: minVersion,
+			Timeout:       time.Second,
+		})
+	}
+
+	c, err := newClient("gaia.internal", tls.VersionTLS12)
+	if err != nil {
+		t.Fatalf("Expected no error connecting by IP with ServerName, got %v", err)
+	}
+	defer c.Close()
+	if st, err := c.GetStatus(context.Background()); err != nil || st != "running" {
+		t.Errorf("Expected status running, got %q, %v", st, err)
+	}
+
+	if c, err := newClient("", 0); err == nil {
+		c.Close()
+		t.Error("Expected an error connecting by an IP the certificate does not list, got nil")
+	}
+	if c, err := newClient("gaia.internal", tls.VersionTLS13); err == nil {
+		c.Close()
+		t.Error("Expected an error requiring TLS 1.3 from a TLS 1.2 daemon, got nil")
+	}
+	if _, err := newClient("gaia.internal", tls.VersionTLS11); err == nil || !strings.Contains(err.Error(), "TLS version") {
+		t.Errorf("Expected an unsupported TLS version error, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FallbackKeySize is the size of the key that encrypts the fallback cache file.
+const FallbackKeySize = 32
+
+// ErrStale is returned, wrapped in a *StaleError, together with secrets served
+// from the fallback cache because the daemon could not be reached.
+var ErrStale = errors.New("gaia: daemon unreachable, serving cached secrets")
+
+// StaleError reports that secrets came from the fallback cache. FetchedAt is
+// when they were last fetched from the daemon and Cause is the error of the
+// failed call.
+type StaleError struct {
+	FetchedAt time.Time
+	Cause     error
+}
+
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("%v (fetched %s): %v", ErrStale, e.FetchedAt.Format(time.RFC3339), e.Cause)
+}
+
+// Is makes errors.Is(err, ErrStale) match.
+func (e *StaleError) Is(target error) bool { return target == ErrStale }
+
+func (e *StaleError) Unwrap() error { return e.Cause }
+
+// fallbackCache persists the last common secrets fetched from the daemon to an
+// AES-256-GCM encrypted file.
+type fallbackCache struct {
+	mu   sync.Mutex
+	path string
+	aead cipher.AEAD
+}
+
+// fallbackData is the plaintext of the cache file.
+type fallbackData struct {
+	FetchedAt  time.Time                    `json:"fetched_at"`
+	Namespaces map[string]map[string]string `json:"namespaces"`
+}
+
+func newFallbackCache(path string, key []byte) (*fallbackCache, error) {
+	if len(key) != FallbackKeySize {
+		return nil, fmt.Errorf("fallback cache key must be %d bytes, got %d", FallbackKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fallbackCache{path: path, aead: aead}, nil
+}
+
+// store merges secrets into the cache. A fetch of every common namespace
+// replaces the cache; a fetch of a single namespace only replaces that one.
+func (f *fallbackCache) store(secrets map[string]map[string]string, all bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data := fallbackData{Namespaces: map[string]map[string]string{}}
+	if !all {
+		if cached, err := f.readLocked(); err == nil {
+			data = *cached
+		}
+	}
+	for ns, kv := range secrets {
+		data.Namespaces[ns] = kv
+	}
+	data.FetchedAt = time.Now().UTC()
+
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, f.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := f.aead.Seal(nonce, nonce, plaintext, nil)
+
+	// Write to a temporary file first so a crash never leaves a truncated cache.
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(sealed); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// load returns the cached secrets.
+func (f *fallbackCache) load() (*fallbackData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.readLocked()
+}
+
+func (f *fallbackCache) readLocked() (*fallbackData, error) {
+	sealed, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	n := f.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, errors.New("fallback cache is truncated")
+	}
+	plaintext, err := f.aead.Open(nil, sealed[:n], sealed[n:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt fallback cache: %w", err)
+	}
+	var data fallbackData
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse fallback cache: %w", err)
+	}
+	return &data, nil
+}
+
+// isUnreachable reports whether err means the daemon could not be reached, as
+// opposed to the daemon refusing the request.
+func isUnreachable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
@@ -359,6 +359,356 @@ func (x *GetCommonSecretsResponse) GetNamespaces() []*Namespace {
 	return nil
 }
 
+// CreateSecretIfAbsentRequest stores a secret in the caller's own namespace
+// unless it already exists.
+type CreateSecretIfAbsentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSecretIfAbsentRequest) Reset() {
+	*x = CreateSecretIfAbsentRequest{}
+	mi := &file_gaia_client_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSecretIfAbsentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSecretIfAbsentRequest) ProtoMessage() {}
+
+func (x *CreateSecretIfAbsentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_client_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSecretIfAbsentRequest.ProtoReflect.Descriptor instead.
+func (*CreateSecretIfAbsentRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_client_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateSecretIfAbsentRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *CreateSecretIfAbsentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateSecretIfAbsentRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// CreateSecretIfAbsentResponse carries the stored value, which is the existing
+// one when another caller created the secret first.
+type CreateSecretIfAbsentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Created       bool                   `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSecretIfAbsentResponse) Reset() {
+	*x = CreateSecretIfAbsentResponse{}
+	mi := &file_gaia_client_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSecretIfAbsentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSecretIfAbsentResponse) ProtoMessage() {}
+
+func (x *CreateSecretIfAbsentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_client_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSecretIfAbsentResponse.ProtoReflect.Descriptor instead.
+func (*CreateSecretIfAbsentResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_client_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateSecretIfAbsentResponse) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *CreateSecretIfAbsentResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+// ListOwnSecretsRequest pages through the secrets of one of the caller's own
+// namespaces in id order.
+type ListOwnSecretsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// page_size defaults to 100 and is capped at 1000.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous response.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOwnSecretsRequest) Reset() {
+	*x = ListOwnSecretsRequest{}
+	mi := &file_gaia_client_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOwnSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOwnSecretsRequest) ProtoMessage() {}
+
+func (x *ListOwnSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_client_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOwnSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ListOwnSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_client_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListOwnSecretsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ListOwnSecretsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOwnSecretsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListOwnSecretsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Secrets []*Secret              `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	// next_page_token is empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOwnSecretsResponse) Reset() {
+	*x = ListOwnSecretsResponse{}
+	mi := &file_gaia_client_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOwnSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOwnSecretsResponse) ProtoMessage() {}
+
+func (x *ListOwnSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_client_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOwnSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ListOwnSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_client_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListOwnSecretsResponse) GetSecrets() []*Secret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *ListOwnSecretsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// ListOwnSecretIdsRequest pages through the ids of the secrets in one of the
+// caller's own namespaces, without their values.
+type ListOwnSecretIdsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// page_size defaults to 100 and is capped at 1000.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous response.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOwnSecretIdsRequest) Reset() {
+	*x = ListOwnSecretIdsRequest{}
+	mi := &file_gaia_client_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOwnSecretIdsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOwnSecretIdsRequest) ProtoMessage() {}
+
+func (x *ListOwnSecretIdsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_client_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOwnSecretIdsRequest.ProtoReflect.Descriptor instead.
+func (*ListOwnSecretIdsRequest) Descriptor() ([]byte, []int) {
+	return file_gaia_client_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListOwnSecretIdsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ListOwnSecretIdsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOwnSecretIdsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListOwnSecretIdsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ids   []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// next_page_token is empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOwnSecretIdsResponse) Reset() {
+	*x = ListOwnSecretIdsResponse{}
+	mi := &file_gaia_client_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOwnSecretIdsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOwnSecretIdsResponse) ProtoMessage() {}
+
+func (x *ListOwnSecretIdsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gaia_client_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOwnSecretIdsResponse.ProtoReflect.Descriptor instead.
+func (*ListOwnSecretIdsResponse) Descriptor() ([]byte, []int) {
+	return file_gaia_client_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListOwnSecretIdsResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *ListOwnSecretIdsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 var File_gaia_client_proto protoreflect.FileDescriptor
 
 const file_gaia_client_proto_rawDesc = "" +
@@ -386,13 +736,39 @@ const file_gaia_client_proto_rawDesc = "" +
 	"\x18GetCommonSecretsResponse\x12/\n" +
 	"\n" +
 	"namespaces\x18\x01 \x03(\v2\x0f.gaia.NamespaceR\n" +
-	"namespaces2\x8f\x02\n" +
+	"namespaces\"a\n" +
+	"\x1bCreateSecretIfAbsentRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"N\n" +
+	"\x1cCreateSecretIfAbsentResponse\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\"q\n" +
+	"\x15ListOwnSecretsRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"h\n" +
+	"\x16ListOwnSecretsResponse\x12&\n" +
+	"\asecrets\x18\x01 \x03(\v2\f.gaia.SecretR\asecrets\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"s\n" +
+	"\x17ListOwnSecretIdsRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"T\n" +
+	"\x18ListOwnSecretIdsResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken2\x8e\x04\n" +
 	"\n" +
 	"GaiaClient\x121\n" +
 	"\tGetSecret\x12\x16.gaia.GetSecretRequest\x1a\f.gaia.Secret\x129\n" +
 	"\tGetStatus\x12\x16.google.protobuf.Empty\x1a\x14.gaia.StatusResponse\x12@\n" +
 	"\rGetNamespaces\x12\x16.google.protobuf.Empty\x1a\x17.gaia.NamespaceResponse\x12Q\n" +
-	"\x10GetCommonSecrets\x12\x1d.gaia.GetCommonSecretsRequest\x1a\x1e.gaia.GetCommonSecretsResponseB)Z'github.com/stain-win/gaia/libs/go/protob\x06proto3"
+	"\x10GetCommonSecrets\x12\x1d.gaia.GetCommonSecretsRequest\x1a\x1e.gaia.GetCommonSecretsResponse\x12]\n" +
+	"\x14CreateSecretIfAbsent\x12!.gaia.CreateSecretIfAbsentRequest\x1a\".gaia.CreateSecretIfAbsentResponse\x12K\n" +
+	"\x0eListOwnSecrets\x12\x1b.gaia.ListOwnSecretsRequest\x1a\x1c.gaia.ListOwnSecretsResponse\x12Q\n" +
+	"\x10ListOwnSecretIds\x12\x1d.gaia.ListOwnSecretIdsRequest\x1a\x1e.gaia.ListOwnSecretIdsResponseB)Z'github.com/stain-win/gaia/libs/go/protob\x06proto3"
 
 var (
 	file_gaia_client_proto_rawDescOnce sync.Once
@@ -406,33 +782,46 @@ func file_gaia_client_proto_rawDescGZIP() []byte {
 	return file_gaia_client_proto_rawDescData
 }
 
-var file_gaia_client_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_gaia_client_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_gaia_client_proto_goTypes = []any{
-	(*Secret)(nil),                   // 0: gaia.Secret
-	(*Namespace)(nil),                // 1: gaia.Namespace
-	(*GetSecretRequest)(nil),         // 2: gaia.GetSecretRequest
-	(*StatusResponse)(nil),           // 3: gaia.StatusResponse
-	(*NamespaceResponse)(nil),        // 4: gaia.NamespaceResponse
-	(*GetCommonSecretsRequest)(nil),  // 5: gaia.GetCommonSecretsRequest
-	(*GetCommonSecretsResponse)(nil), // 6: gaia.GetCommonSecretsResponse
-	(*emptypb.Empty)(nil),            // 7: google.protobuf.Empty
+	(*Secret)(nil),                       // 0: gaia.Secret
+	(*Namespace)(nil),                    // 1: gaia.Namespace
+	(*GetSecretRequest)(nil),             // 2: gaia.GetSecretRequest
+	(*StatusResponse)(nil),               // 3: gaia.StatusResponse
+	(*NamespaceResponse)(nil),            // 4: gaia.NamespaceResponse
+	(*GetCommonSecretsRequest)(nil),      // 5: gaia.GetCommonSecretsRequest
+	(*GetCommonSecretsResponse)(nil),     // 6: gaia.GetCommonSecretsResponse
+	(*CreateSecretIfAbsentRequest)(nil),  // 7: gaia.CreateSecretIfAbsentRequest
+	(*CreateSecretIfAbsentResponse)(nil), // 8: gaia.CreateSecretIfAbsentResponse
+	(*ListOwnSecretsRequest)(nil),        // 9: gaia.ListOwnSecretsRequest
+	(*ListOwnSecretsResponse)(nil),       // 10: gaia.ListOwnSecretsResponse
+	(*ListOwnSecretIdsRequest)(nil),      // 11: gaia.ListOwnSecretIdsRequest
+	(*ListOwnSecretIdsResponse)(nil),     // 12: gaia.ListOwnSecretIdsResponse
+	(*emptypb.Empty)(nil),                // 13: google.protobuf.Empty
 }
 var file_gaia_client_proto_depIdxs = []int32{
-	0, // 0: gaia.Namespace.secrets:type_name -> gaia.Secret
-	1, // 1: gaia.GetCommonSecretsResponse.namespaces:type_name -> gaia.Namespace
-	2, // 2: gaia.GaiaClient.GetSecret:input_type -> gaia.GetSecretRequest
-	7, // 3: gaia.GaiaClient.GetStatus:input_type -> google.protobuf.Empty
-	7, // 4: gaia.GaiaClient.GetNamespaces:input_type -> google.protobuf.Empty
-	5, // 5: gaia.GaiaClient.GetCommonSecrets:input_type -> gaia.GetCommonSecretsRequest
-	0, // 6: gaia.GaiaClient.GetSecret:output_type -> gaia.Secret
-	3, // 7: gaia.GaiaClient.GetStatus:output_type -> gaia.StatusResponse
-	4, // 8: gaia.GaiaClient.GetNamespaces:output_type -> gaia.NamespaceResponse
-	6, // 9: gaia.GaiaClient.GetCommonSecrets:output_type -> gaia.GetCommonSecretsResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: gaia.Namespace.secrets:type_name -> gaia.Secret
+	1,  // 1: gaia.GetCommonSecretsResponse.namespaces:type_name -> gaia.Namespace
+	0,  // 2: gaia.ListOwnSecretsResponse.secrets:type_name -> gaia.Secret
+	2,  // 3: gaia.GaiaClient.GetSecret:input_type -> gaia.GetSecretRequest
+	13, // 4: gaia.GaiaClient.GetStatus:input_type -> google.protobuf.Empty
+	13, // 5: gaia.GaiaClient.GetNamespaces:input_type -> google.protobuf.Empty
+	5,  // 6: gaia.GaiaClient.GetCommonSecrets:input_type -> gaia.GetCommonSecretsRequest
+	7,  // 7: gaia.GaiaClient.CreateSecretIfAbsent:input_type -> gaia.CreateSecretIfAbsentRequest
+	9,  // 8: gaia.GaiaClient.ListOwnSecrets:input_type -> gaia.ListOwnSecretsRequest
+	11, // 9: gaia.GaiaClient.ListOwnSecretIds:input_type -> gaia.ListOwnSecretIdsRequest
+	0,  // 10: gaia.GaiaClient.GetSecret:output_type -> gaia.Secret
+	3,  // 11: gaia.GaiaClient.GetStatus:output_type -> gaia.StatusResponse
+	4,  // 12: gaia.GaiaClient.GetNamespaces:output_type -> gaia.NamespaceResponse
+	6,  // 13: gaia.GaiaClient.GetCommonSecrets:output_type -> gaia.GetCommonSecretsResponse
+	8,  // 14: gaia.GaiaClient.CreateSecretIfAbsent:output_type -> gaia.CreateSecretIfAbsentResponse
+	10, // 15: gaia.GaiaClient.ListOwnSecrets:output_type -> gaia.ListOwnSecretsResponse
+	12, // 16: gaia.GaiaClient.ListOwnSecretIds:output_type -> gaia.ListOwnSecretIdsResponse
+	10, // [10:17] is the sub-list for method output_type
+	3,  // [3:10] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_gaia_client_proto_init() }
@@ -447,7 +836,7 @@ func file_gaia_client_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gaia_client_proto_rawDesc), len(file_gaia_client_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -20,10 +20,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	GaiaClient_GetSecret_FullMethodName        = "/gaia.GaiaClient/GetSecret"
-	GaiaClient_GetStatus_FullMethodName        = "/gaia.GaiaClient/GetStatus"
-	GaiaClient_GetNamespaces_FullMethodName    = "/gaia.GaiaClient/GetNamespaces"
-	GaiaClient_GetCommonSecrets_FullMethodName = "/gaia.GaiaClient/GetCommonSecrets"
+	GaiaClient_GetSecret_FullMethodName            = "/gaia.GaiaClient/GetSecret"
+	GaiaClient_GetStatus_FullMethodName            = "/gaia.GaiaClient/GetStatus"
+	GaiaClient_GetNamespaces_FullMethodName        = "/gaia.GaiaClient/GetNamespaces"
+	GaiaClient_GetCommonSecrets_FullMethodName     = "/gaia.GaiaClient/GetCommonSecrets"
+	GaiaClient_CreateSecretIfAbsent_FullMethodName = "/gaia.GaiaClient/CreateSecretIfAbsent"
+	GaiaClient_ListOwnSecrets_FullMethodName       = "/gaia.GaiaClient/ListOwnSecrets"
+	GaiaClient_ListOwnSecretIds_FullMethodName     = "/gaia.GaiaClient/ListOwnSecretIds"
 )
 
 // GaiaClientClient is the client API for GaiaClient service.
@@ -34,6 +37,9 @@ type GaiaClientClient interface {
 	GetStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatusResponse, error)
 	GetNamespaces(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*NamespaceResponse, error)
 	GetCommonSecrets(ctx context.Context, in *GetCommonSecretsRequest, opts ...grpc.CallOption) (*GetCommonSecretsResponse, error)
+	CreateSecretIfAbsent(ctx context.Context, in *CreateSecretIfAbsentRequest, opts ...grpc.CallOption) (*CreateSecretIfAbsentResponse, error)
+	ListOwnSecrets(ctx context.Context, in *ListOwnSecretsRequest, opts ...grpc.CallOption) (*ListOwnSecretsResponse, error)
+	ListOwnSecretIds(ctx context.Context, in *ListOwnSecretIdsRequest, opts ...grpc.CallOption) (*ListOwnSecretIdsResponse, error)
 }
 
 type gaiaClientClient struct {
@@ -84,6 +90,36 @@ func (c *gaiaClientClient) GetCommonSecrets(ctx context.Context, in *GetCommonSe
 	return out, nil
 }
 
+func (c *gaiaClientClient) CreateSecretIfAbsent(ctx context.Context, in *CreateSecretIfAbsentRequest, opts ...grpc.CallOption) (*CreateSecretIfAbsentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSecretIfAbsentResponse)
+	err := c.cc.Invoke(ctx, GaiaClient_CreateSecretIfAbsent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaClientClient) ListOwnSecrets(ctx context.Context, in *ListOwnSecretsRequest, opts ...grpc.CallOption) (*ListOwnSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOwnSecretsResponse)
+	err := c.cc.Invoke(ctx, GaiaClient_ListOwnSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gaiaClientClient) ListOwnSecretIds(ctx context.Context, in *ListOwnSecretIdsRequest, opts ...grpc.CallOption) (*ListOwnSecretIdsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOwnSecretIdsResponse)
+	err := c.cc.Invoke(ctx, GaiaClient_ListOwnSecretIds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GaiaClientServer is the server API for GaiaClient service.
 // All implementations must embed UnimplementedGaiaClientServer
 // for forward compatibility.
@@ -92,6 +128,9 @@ type GaiaClientServer interface {
 	GetStatus(context.Context, *emptypb.Empty) (*StatusResponse, error)
 	GetNamespaces(context.Context, *emptypb.Empty) (*NamespaceResponse, error)
 	GetCommonSecrets(context.Context, *GetCommonSecretsRequest) (*GetCommonSecretsResponse, error)
+	CreateSecretIfAbsent(context.Context, *CreateSecretIfAbsentRequest) (*CreateSecretIfAbsentResponse, error)
+	ListOwnSecrets(context.Context, *ListOwnSecretsRequest) (*ListOwnSecretsResponse, error)
+	ListOwnSecretIds(context.Context, *ListOwnSecretIdsRequest) (*ListOwnSecretIdsResponse, error)
 	mustEmbedUnimplementedGaiaClientServer()
 }
 
@@ -114,6 +153,15 @@ func (UnimplementedGaiaClientServer) GetNamespaces(context.Context, *emptypb.Emp
 func (UnimplementedGaiaClientServer) GetCommonSecrets(context.Context, *GetCommonSecretsRequest) (*GetCommonSecretsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCommonSecrets not implemented")
 }
+func (UnimplementedGaiaClientServer) CreateSecretIfAbsent(context.Context, *CreateSecretIfAbsentRequest) (*CreateSecretIfAbsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSecretIfAbsent not implemented")
+}
+func (UnimplementedGaiaClientServer) ListOwnSecrets(context.Context, *ListOwnSecretsRequest) (*ListOwnSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOwnSecrets not implemented")
+}
+func (UnimplementedGaiaClientServer) ListOwnSecretIds(context.Context, *ListOwnSecretIdsRequest) (*ListOwnSecretIdsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOwnSecretIds not implemented")
+}
 func (UnimplementedGaiaClientServer) mustEmbedUnimplementedGaiaClientServer() {}
 func (UnimplementedGaiaClientServer) testEmbeddedByValue()                    {}
 
@@ -207,6 +255,60 @@ func _GaiaClient_GetCommonSecrets_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GaiaClient_CreateSecretIfAbsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSecretIfAbsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaClientServer).CreateSecretIfAbsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaClient_CreateSecretIfAbsent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaClientServer).CreateSecretIfAbsent(ctx, req.(*CreateSecretIfAbsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaClient_ListOwnSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOwnSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaClientServer).ListOwnSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaClient_ListOwnSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaClientServer).ListOwnSecrets(ctx, req.(*ListOwnSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GaiaClient_ListOwnSecretIds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOwnSecretIdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GaiaClientServer).ListOwnSecretIds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GaiaClient_ListOwnSecretIds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GaiaClientServer).ListOwnSecretIds(ctx, req.(*ListOwnSecretIdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GaiaClient_ServiceDesc is the grpc.ServiceDesc for GaiaClient service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -230,6 +332,18 @@ var GaiaClient_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCommonSecrets",
 			Handler:    _GaiaClient_GetCommonSecrets_Handler,
 		},
+		{
+			MethodName: "CreateSecretIfAbsent",
+			Handler:    _GaiaClient_CreateSecretIfAbsent_Handler,
+		},
+		{
+			MethodName: "ListOwnSecrets",
+			Handler:    _GaiaClient_ListOwnSecrets_Handler,
+		},
+		{
+			MethodName: "ListOwnSecretIds",
+			Handler:    _GaiaClient_ListOwnSecretIds_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "gaia-client.proto",